@@ -8,7 +8,8 @@ package addons
 // 说明：
 // 本目录下的 addons（hotgo/addons）是给“插件工程(plugins)”使用的轻量入口。
 // 目前主服务插件体系实际实现位于 hotgo/internal/library/addons。
-// 为了保证历史插件代码（如 addons/exchange_*）可编译且不影响现有业务功能，这里提供最小兼容层。
+// 为了保证历史插件代码可编译且不影响现有业务功能，这里提供最小兼容层。
+// 交易所插件（addons/exchange_*）通过 hotgo/addons/exchange 注册交易平台驱动，见该包说明。
 
 import "context"
 
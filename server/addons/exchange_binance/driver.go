@@ -0,0 +1,30 @@
+// Package exchange_binance
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+
+package exchange_binance
+
+import (
+	"hotgo/addons/exchange"
+	library "hotgo/internal/library/exchange"
+)
+
+func init() {
+	exchange.Register(&exchange.Driver{
+		Platform:       "binance",
+		Title:          "Binance（币安）",
+		BaseUrl:        "https://fapi.binance.com",
+		NeedPassphrase: false,
+		NewExchange: func(config *exchange.Config) (exchange.Exchange, error) {
+			return library.NewBinance(config), nil
+		},
+		NewPrivateStream: func(config *exchange.Config) (exchange.PrivateStream, error) {
+			return library.NewBinancePrivateStream(config), nil
+		},
+		PublicStream: func() exchange.PublicStream {
+			return library.GetBinanceWebSocket()
+		},
+	})
+}
@@ -0,0 +1,32 @@
+// Package exchange_gate
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+
+package exchange_gate
+
+import (
+	"hotgo/addons/exchange"
+	library "hotgo/internal/library/exchange"
+)
+
+func init() {
+	exchange.Register(&exchange.Driver{
+		Platform:       "gate",
+		Title:          "Gate.io",
+		BaseUrl:        "https://api.gateio.ws",
+		NeedPassphrase: false,
+		NewExchange: func(config *exchange.Config) (exchange.Exchange, error) {
+			return library.NewGate(config), nil
+		},
+		NewPrivateStream: func(config *exchange.Config) (exchange.PrivateStream, error) {
+			return library.NewGatePrivateStream(config), nil
+		},
+		PublicStream: func() exchange.PublicStream {
+			return library.GetGateWebSocket()
+		},
+		// Gate 公共WS连接较慢，订阅会在连接建立后重放
+		PublicStreamReplay: true,
+	})
+}
@@ -0,0 +1,30 @@
+// Package exchange_okx
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+
+package exchange_okx
+
+import (
+	"hotgo/addons/exchange"
+	library "hotgo/internal/library/exchange"
+)
+
+func init() {
+	exchange.Register(&exchange.Driver{
+		Platform:       "okx",
+		Title:          "OKX（欧易）",
+		BaseUrl:        "https://www.okx.com",
+		NeedPassphrase: true,
+		NewExchange: func(config *exchange.Config) (exchange.Exchange, error) {
+			return library.NewOKX(config), nil
+		},
+		NewPrivateStream: func(config *exchange.Config) (exchange.PrivateStream, error) {
+			return library.NewOKXPrivateStream(config), nil
+		},
+		PublicStream: func() exchange.PublicStream {
+			return library.GetOKXWebSocket()
+		},
+	})
+}
@@ -0,0 +1,13 @@
+// Package modules
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package modules
+
+// 交易所插件：注册交易平台驱动，新增交易平台时在此引入
+import (
+	_ "hotgo/addons/exchange_binance"
+	_ "hotgo/addons/exchange_gate"
+	_ "hotgo/addons/exchange_okx"
+)
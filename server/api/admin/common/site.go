@@ -8,6 +8,8 @@ package common
 import (
 	"hotgo/internal/model"
 	"hotgo/internal/model/input/adminin"
+	"hotgo/internal/model/input/sysin"
+	"hotgo/internal/model/input/toogoin"
 
 	"github.com/gogf/gf/v2/frame/g"
 )
@@ -85,16 +87,37 @@ type MobileLoginRes struct {
 	*adminin.LoginModel
 }
 
+// TwoFactorLoginSetupReq 登录时绑定验证器
+type TwoFactorLoginSetupReq struct {
+	g.Meta `path:"/site/twoFactorLoginSetup" method:"post" tags:"后台基础" summary:"登录时绑定验证器" dc:"角色强制双因素认证且尚未绑定时，凭登录票据获取待绑定的密钥"`
+	adminin.TwoFactorLoginSetupInp
+}
+
+type TwoFactorLoginSetupRes struct {
+	*sysin.TwoFactorSetupModel
+}
+
+// TwoFactorLoginReq 提交双因素认证口令完成登录
+type TwoFactorLoginReq struct {
+	g.Meta `path:"/site/twoFactorLogin" method:"post" tags:"后台基础" summary:"双因素认证登录"`
+	adminin.TwoFactorLoginInp
+}
+
+type TwoFactorLoginRes struct {
+	*adminin.LoginModel
+}
+
 // SiteConfigReq 获取配置
 type SiteConfigReq struct {
 	g.Meta `path:"/site/config" method:"get" tags:"后台基础" summary:"获取配置"`
 }
 
 type SiteConfigRes struct {
-	Version string `json:"version"        dc:"系统版本"`
-	WsAddr  string `json:"wsAddr"         dc:"客户端websocket地址"`
-	Domain  string `json:"domain"         dc:"对外域名"`
-	Mode    string `json:"mode"           dc:"运行模式"`
+	Version  string                       `json:"version"        dc:"系统版本"`
+	WsAddr   string                       `json:"wsAddr"         dc:"客户端websocket地址"`
+	Domain   string                       `json:"domain"         dc:"对外域名"`
+	Mode     string                       `json:"mode"           dc:"运行模式"`
+	Branding *toogoin.TenantBrandingModel `json:"branding"       dc:"站点品牌（白标租户）"`
 }
 
 // SiteLoginConfigReq 获取登录配置
@@ -0,0 +1,59 @@
+// Package queuedeadletter
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package queuedeadletter
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"hotgo/internal/model/input/form"
+	"hotgo/internal/model/input/sysin"
+)
+
+// ListReq 查询列表
+type ListReq struct {
+	g.Meta `path:"/queueDeadLetter/list" method:"get" tags:"消息队列" summary:"获取死信列表"`
+	sysin.QueueDeadLetterListInp
+}
+
+type ListRes struct {
+	List []*sysin.QueueDeadLetterListModel `json:"list"   dc:"数据列表"`
+	form.PageRes
+}
+
+// ViewReq 获取指定信息
+type ViewReq struct {
+	g.Meta `path:"/queueDeadLetter/view" method:"get" tags:"消息队列" summary:"获取指定死信信息"`
+	sysin.QueueDeadLetterViewInp
+}
+
+type ViewRes struct {
+	*sysin.QueueDeadLetterViewModel
+}
+
+// ReplayReq 重放
+type ReplayReq struct {
+	g.Meta `path:"/queueDeadLetter/replay" method:"post" tags:"消息队列" summary:"重放死信到原主题"`
+	sysin.QueueDeadLetterReplayInp
+}
+
+type ReplayRes struct {
+	*sysin.QueueDeadLetterReplayModel
+}
+
+// IgnoreReq 忽略
+type IgnoreReq struct {
+	g.Meta `path:"/queueDeadLetter/ignore" method:"post" tags:"消息队列" summary:"忽略死信"`
+	sysin.QueueDeadLetterIgnoreInp
+}
+
+type IgnoreRes struct{}
+
+// DeleteReq 删除
+type DeleteReq struct {
+	g.Meta `path:"/queueDeadLetter/delete" method:"post" tags:"消息队列" summary:"删除死信"`
+	sysin.QueueDeadLetterDeleteInp
+}
+
+type DeleteRes struct{}
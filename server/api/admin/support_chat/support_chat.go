@@ -63,6 +63,24 @@ type SendRes struct {
 	*entity.SupportMessage `json:"message" dc:"消息"`
 }
 
+// SendAttachmentReq 发送附件（客服）
+type SendAttachmentReq struct {
+	g.Meta `path:"/supportChat/sendAttachment" method:"post" mime:"multipart/form-data" tags:"客服" summary:"发送附件(客服)"`
+	adminin.SupportSendAttachmentInp
+}
+
+type SendAttachmentRes struct {
+	*entity.SupportMessage `json:"message" dc:"消息"`
+}
+
+// ReadReq 标记已读
+type ReadReq struct {
+	g.Meta `path:"/supportChat/read" method:"post" tags:"客服" summary:"标记已读(客服)"`
+	adminin.SupportReadInp
+}
+
+type ReadRes struct{}
+
 // MessageListReq 消息列表
 type MessageListReq struct {
 	g.Meta `path:"/supportChat/messageList" method:"get" tags:"客服" summary:"消息列表"`
@@ -84,6 +102,53 @@ type TransferRes struct {
 	*entity.SupportSession `json:"session" dc:"会话"`
 }
 
+// EscalateReq 升级为工单
+type EscalateReq struct {
+	g.Meta `path:"/supportChat/escalate" method:"post" tags:"客服" summary:"升级为工单(关联机器人/订单)"`
+	adminin.SupportEscalateInp
+}
+
+type EscalateRes struct {
+	*entity.SupportSession `json:"session" dc:"会话"`
+}
+
+// ExecutionLogListReq 工单关联的执行日志
+type ExecutionLogListReq struct {
+	g.Meta `path:"/supportChat/executionLogList" method:"get" tags:"客服" summary:"工单关联的执行日志"`
+	adminin.SupportExecutionLogListInp
+}
+
+type ExecutionLogListRes struct {
+	List []*entity.TradingExecutionLog `json:"list" dc:"数据列表"`
+	form.PageRes
+}
+
+// ContextReq 会话用户上下文
+type ContextReq struct {
+	g.Meta `path:"/supportChat/context" method:"get" tags:"客服" summary:"会话用户上下文(机器人/失败记录/API Key/钱包/订阅)"`
+	adminin.SupportContextInp
+}
+
+type ContextRes struct {
+	*adminin.SupportContextModel
+}
+
+// RobotRestartReq 重建用户机器人引擎
+type RobotRestartReq struct {
+	g.Meta `path:"/supportChat/robot/restart" method:"post" tags:"客服" summary:"重建会话用户的机器人引擎"`
+	adminin.SupportRobotActionInp
+}
+
+type RobotRestartRes struct{}
+
+// RobotSyncOrdersReq 重新同步用户机器人订单
+type RobotSyncOrdersReq struct {
+	g.Meta `path:"/supportChat/robot/syncOrders" method:"post" tags:"客服" summary:"重新同步会话用户的机器人订单/持仓"`
+	adminin.SupportRobotActionInp
+}
+
+type RobotSyncOrdersRes struct{}
+
 // CannedListReq 常用语列表
 type CannedListReq struct {
 	g.Meta `path:"/supportChat/canned/list" method:"get" tags:"客服" summary:"常用语列表"`
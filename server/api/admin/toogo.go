@@ -179,6 +179,103 @@ type ToogoMySubscriptionRes struct {
 	*toogoin.MySubscriptionModel
 }
 
+// ToogoPlanChangeQuoteReq 升级/降级套餐试算请求
+type ToogoPlanChangeQuoteReq struct {
+	g.Meta `path:"/toogo/subscription/changeQuote" method:"get" tags:"Toogo订阅" summary:"升级/降级套餐试算"`
+	toogoin.ChangePlanInp
+}
+
+type ToogoPlanChangeQuoteRes struct {
+	*toogoin.ChangePlanModel
+}
+
+// ToogoChangePlanReq 升级/降级套餐请求
+type ToogoChangePlanReq struct {
+	g.Meta `path:"/toogo/subscription/change" method:"post" tags:"Toogo订阅" summary:"升级/降级套餐"`
+	toogoin.ChangePlanInp
+}
+
+type ToogoChangePlanRes struct {
+	*toogoin.ChangePlanModel
+}
+
+// ToogoSubscriptionAutoRenewReq 设置自动续费请求
+type ToogoSubscriptionAutoRenewReq struct {
+	g.Meta `path:"/toogo/subscription/autoRenew" method:"post" tags:"Toogo订阅" summary:"设置自动续费"`
+	toogoin.SubscriptionAutoRenewInp
+}
+
+type ToogoSubscriptionAutoRenewRes struct{}
+
+// ToogoSubscriptionRefundReq 订阅退款请求（管理员）
+type ToogoSubscriptionRefundReq struct {
+	g.Meta `path:"/toogo/subscription/refund" method:"post" tags:"Toogo订阅" summary:"订阅退款"`
+	toogoin.SubscriptionRefundInp
+}
+
+type ToogoSubscriptionRefundRes struct {
+	*toogoin.SubscriptionRefundModel
+}
+
+// ToogoSubscriptionLogListReq 订阅变更记录请求
+type ToogoSubscriptionLogListReq struct {
+	g.Meta `path:"/toogo/subscription/log" method:"get" tags:"Toogo订阅" summary:"订阅变更记录"`
+	toogoin.SubscriptionLogListInp
+}
+
+type ToogoSubscriptionLogListRes struct {
+	List       []*toogoin.SubscriptionLogListModel `json:"list"`
+	TotalCount int                                 `json:"totalCount"`
+}
+
+// ToogoCouponListReq 优惠券列表请求
+type ToogoCouponListReq struct {
+	g.Meta `path:"/toogo/coupon/list" method:"get" tags:"Toogo订阅" summary:"优惠券列表"`
+	toogoin.CouponListInp
+}
+
+type ToogoCouponListRes struct {
+	List       []*toogoin.CouponListModel `json:"list"`
+	TotalCount int                        `json:"totalCount"`
+}
+
+// ToogoCouponEditReq 编辑优惠券请求
+type ToogoCouponEditReq struct {
+	g.Meta `path:"/toogo/coupon/edit" method:"post" tags:"Toogo订阅" summary:"编辑优惠券/推广码"`
+	toogoin.CouponEditInp
+}
+
+type ToogoCouponEditRes struct{}
+
+// ToogoCouponDeleteReq 删除优惠券请求
+type ToogoCouponDeleteReq struct {
+	g.Meta `path:"/toogo/coupon/delete" method:"post" tags:"Toogo订阅" summary:"删除优惠券"`
+	toogoin.CouponDeleteInp
+}
+
+type ToogoCouponDeleteRes struct{}
+
+// ToogoCouponUsageListReq 优惠券使用记录请求
+type ToogoCouponUsageListReq struct {
+	g.Meta `path:"/toogo/coupon/usage" method:"get" tags:"Toogo订阅" summary:"优惠券使用记录"`
+	toogoin.CouponUsageListInp
+}
+
+type ToogoCouponUsageListRes struct {
+	List       []*toogoin.CouponUsageListModel `json:"list"`
+	TotalCount int                             `json:"totalCount"`
+}
+
+// ToogoCouponPreviewReq 优惠码试算请求
+type ToogoCouponPreviewReq struct {
+	g.Meta `path:"/toogo/coupon/preview" method:"get" tags:"Toogo订阅" summary:"优惠码试算"`
+	toogoin.CouponPreviewInp
+}
+
+type ToogoCouponPreviewRes struct {
+	*toogoin.CouponPreviewModel
+}
+
 // ========== 用户管理 ==========
 
 // ToogoUserInfoReq 用户信息请求
@@ -202,6 +299,16 @@ type ToogoUserListRes struct {
 	TotalCount int                      `json:"totalCount"`
 }
 
+// ToogoChangeInviterReq 调整邀请人请求（管理员）
+type ToogoChangeInviterReq struct {
+	g.Meta `path:"/toogo/user/change-inviter" method:"post" tags:"Toogo用户" summary:"调整邀请人（团队随迁并调整上级团队统计）"`
+	toogoin.ChangeInviterInp
+}
+
+type ToogoChangeInviterRes struct {
+	*toogoin.ChangeInviterModel
+}
+
 // ToogoRefreshInviteCodeReq 刷新邀请码请求
 type ToogoRefreshInviteCodeReq struct {
 	g.Meta `path:"/toogo/user/refresh-invite-code" method:"post" tags:"Toogo用户" summary:"刷新邀请码"`
@@ -259,6 +366,27 @@ type ToogoCheckVipUpgradeRes struct {
 	*toogoin.CheckVipUpgradeModel
 }
 
+// ToogoVipEvaluateReq 重新评估VIP等级请求（管理员）
+type ToogoVipEvaluateReq struct {
+	g.Meta `path:"/toogo/vip-level/evaluate" method:"post" tags:"Toogo用户" summary:"重新评估VIP等级"`
+	toogoin.VipEvaluateInp
+}
+
+type ToogoVipEvaluateRes struct {
+	*toogoin.VipEvaluateModel
+}
+
+// ToogoVipLevelLogListReq VIP等级变更记录请求
+type ToogoVipLevelLogListReq struct {
+	g.Meta `path:"/toogo/vip-level/log" method:"get" tags:"Toogo用户" summary:"VIP等级变更记录"`
+	toogoin.VipLevelLogListInp
+}
+
+type ToogoVipLevelLogListRes struct {
+	List       []*toogoin.VipLevelLogListModel `json:"list"`
+	TotalCount int                             `json:"totalCount"`
+}
+
 // ========== 佣金管理 ==========
 
 // ToogoCommissionLogListReq 佣金记录列表请求
@@ -281,6 +409,34 @@ type ToogoCommissionStatRes struct {
 	*toogoin.CommissionStatModel
 }
 
+// ToogoAgentDashboardReq 代理看板请求
+type ToogoAgentDashboardReq struct {
+	g.Meta `path:"/toogo/agent/dashboard" method:"get" tags:"Toogo佣金" summary:"代理看板"`
+	toogoin.AgentDashboardInp
+}
+
+type ToogoAgentDashboardRes struct {
+	*toogoin.AgentDashboardModel
+}
+
+// ToogoAgentStatementReq 代理月度对账单请求
+type ToogoAgentStatementReq struct {
+	g.Meta `path:"/toogo/agent/statement" method:"get" tags:"Toogo佣金" summary:"代理月度对账单"`
+	toogoin.AgentStatementInp
+}
+
+type ToogoAgentStatementRes struct {
+	*toogoin.AgentStatementModel
+}
+
+// ToogoAgentStatementExportReq 下载代理月度对账单请求
+type ToogoAgentStatementExportReq struct {
+	g.Meta `path:"/toogo/agent/statement/export" method:"get" tags:"Toogo佣金" summary:"下载代理月度对账单(CSV/PDF)"`
+	toogoin.AgentStatementExportInp
+}
+
+type ToogoAgentStatementExportRes struct{}
+
 // ToogoAgentLevelListReq 代理商等级列表请求
 type ToogoAgentLevelListReq struct {
 	g.Meta `path:"/toogo/agent-level/list" method:"get" tags:"Toogo代理商" summary:"代理商等级列表"`
@@ -416,6 +572,26 @@ type ToogoPowerConsumeStatRes struct {
 	*toogoin.PowerConsumeStatModel
 }
 
+// ToogoPerformanceFeePreviewReq 业绩费试算报告请求
+type ToogoPerformanceFeePreviewReq struct {
+	g.Meta `path:"/toogo/performance-fee/preview" method:"get" tags:"Toogo管理" summary:"业绩费试算报告"`
+	toogoin.PerformanceFeeInp
+}
+
+type ToogoPerformanceFeePreviewRes struct {
+	*toogoin.PerformanceFeeReport
+}
+
+// ToogoPerformanceFeeSettleReq 业绩费结算请求
+type ToogoPerformanceFeeSettleReq struct {
+	g.Meta `path:"/toogo/performance-fee/settle" method:"post" tags:"Toogo管理" summary:"业绩费结算"`
+	toogoin.PerformanceFeeInp
+}
+
+type ToogoPerformanceFeeSettleRes struct {
+	*toogoin.PerformanceFeeReport
+}
+
 // ========== 管理员操作 ==========
 
 // ToogoAdminRechargePowerReq 管理员手动充值算力请求
@@ -0,0 +1,75 @@
+// Package admin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+package admin
+
+import (
+	"hotgo/internal/model/input/toogoin"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// ToogoTenantListReq 白标租户列表请求
+type ToogoTenantListReq struct {
+	g.Meta `path:"/toogo/tenant/list" method:"get" tags:"Toogo白标租户" summary:"租户列表（跨租户统计）"`
+	toogoin.TenantListInp
+}
+
+type ToogoTenantListRes struct {
+	List       []*toogoin.TenantListModel `json:"list"`
+	TotalCount int                        `json:"totalCount"`
+}
+
+// ToogoTenantViewReq 白标租户详情请求
+type ToogoTenantViewReq struct {
+	g.Meta `path:"/toogo/tenant/view" method:"get" tags:"Toogo白标租户" summary:"租户详情"`
+	toogoin.TenantViewInp
+}
+
+type ToogoTenantViewRes struct {
+	*toogoin.TenantModel
+}
+
+// ToogoTenantEditReq 编辑白标租户请求
+type ToogoTenantEditReq struct {
+	g.Meta `path:"/toogo/tenant/edit" method:"post" tags:"Toogo白标租户" summary:"新增/编辑租户"`
+	toogoin.TenantEditInp
+}
+
+type ToogoTenantEditRes struct{}
+
+// ToogoTenantStatusReq 更新白标租户状态请求
+type ToogoTenantStatusReq struct {
+	g.Meta `path:"/toogo/tenant/status" method:"post" tags:"Toogo白标租户" summary:"更新租户状态"`
+	toogoin.TenantStatusInp
+}
+
+type ToogoTenantStatusRes struct{}
+
+// ToogoTenantCopyCatalogReq 复制平台套餐/官方策略模板到租户请求
+type ToogoTenantCopyCatalogReq struct {
+	g.Meta `path:"/toogo/tenant/copyCatalog" method:"post" tags:"Toogo白标租户" summary:"复制平台套餐/官方策略模板"`
+	toogoin.TenantCopyCatalogInp
+}
+
+type ToogoTenantCopyCatalogRes struct {
+	*toogoin.TenantCopyCatalogModel
+}
+
+// ToogoTenantBrandingReq 当前租户品牌配置请求
+type ToogoTenantBrandingReq struct {
+	g.Meta `path:"/toogo/tenant/branding" method:"get" tags:"Toogo白标租户" summary:"品牌配置"`
+}
+
+type ToogoTenantBrandingRes struct {
+	*toogoin.TenantModel
+}
+
+// ToogoTenantBrandingEditReq 修改当前租户品牌配置请求
+type ToogoTenantBrandingEditReq struct {
+	g.Meta `path:"/toogo/tenant/branding/edit" method:"post" tags:"Toogo白标租户" summary:"修改品牌配置与收款凭证"`
+	toogoin.TenantBrandingEditInp
+}
+
+type ToogoTenantBrandingEditRes struct{}
@@ -0,0 +1,29 @@
+// Package trading
+package trading
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+
+	"hotgo/internal/model/input/toogoin"
+)
+
+// ==================== 信号权重自适应学习 API ====================
+
+// AiLearningReportReq 学习报告（当前权重 vs 学习权重）
+type AiLearningReportReq struct {
+	g.Meta `path:"/ai-learning/report" method:"get" tags:"量化管理" summary:"信号权重学习报告"`
+	toogoin.AiLearningReportInp
+}
+
+type AiLearningReportRes struct {
+	*toogoin.AiLearningReportModel
+}
+
+// AiLearningRunReq 立即执行一轮学习
+type AiLearningRunReq struct {
+	g.Meta `path:"/ai-learning/run" method:"post" tags:"量化管理" summary:"执行信号权重学习"`
+}
+
+type AiLearningRunRes struct {
+	*toogoin.AiLearningRunModel
+}
@@ -54,3 +54,41 @@ type ProxyConfigToggleReq struct {
 // ProxyConfigToggleRes 切换启用状态响应
 type ProxyConfigToggleRes struct{}
 
+// ProxyPoolListReq 代理池列表请求
+type ProxyPoolListReq struct {
+	g.Meta `path:"/trading/proxyPool/list" method:"get" tags:"交易管理" summary:"代理池列表" dc:"获取代理池成员及健康状态"`
+	input.TradingProxyPoolListInp
+}
+
+// ProxyPoolListRes 代理池列表响应
+type ProxyPoolListRes struct {
+	List []*input.TradingProxyPoolModel `json:"list" dc:"代理列表"`
+}
+
+// ProxyPoolEditReq 编辑代理池成员请求
+type ProxyPoolEditReq struct {
+	g.Meta `path:"/trading/proxyPool/edit" method:"post" tags:"交易管理" summary:"编辑代理池成员" dc:"新增或修改全局/用户专属代理"`
+	input.TradingProxyPoolEditInp
+}
+
+// ProxyPoolEditRes 编辑代理池成员响应
+type ProxyPoolEditRes struct{}
+
+// ProxyPoolDeleteReq 删除代理池成员请求
+type ProxyPoolDeleteReq struct {
+	g.Meta `path:"/trading/proxyPool/delete" method:"post" tags:"交易管理" summary:"删除代理池成员"`
+	input.TradingProxyPoolDeleteInp
+}
+
+// ProxyPoolDeleteRes 删除代理池成员响应
+type ProxyPoolDeleteRes struct{}
+
+// ProxyPoolCheckReq 代理池健康检查请求
+type ProxyPoolCheckReq struct {
+	g.Meta `path:"/trading/proxyPool/check" method:"post" tags:"交易管理" summary:"代理池健康检查" dc:"立即检查所有代理到各交易所的连通性与延迟"`
+}
+
+// ProxyPoolCheckRes 代理池健康检查响应
+type ProxyPoolCheckRes struct {
+	List []*input.TradingProxyPoolModel `json:"list" dc:"代理列表"`
+}
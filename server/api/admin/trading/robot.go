@@ -103,6 +103,28 @@ type RobotStopReq struct {
 // RobotStopRes 停止机器人响应
 type RobotStopRes struct{}
 
+// RobotStopAllReq 停止全部机器人请求
+type RobotStopAllReq struct {
+	g.Meta `path:"/trading/robot/stopAll" method:"post" tags:"交易管理" summary:"停止全部机器人" dc:"停止当前用户全部未停用的机器人，有持仓的机器人会停止失败并返回原因"`
+	input.TradingRobotStopAllInp
+}
+
+// RobotStopAllRes 停止全部机器人响应
+type RobotStopAllRes struct {
+	*input.TradingRobotStopAllModel
+}
+
+// RobotGridStatusReq 网格运行状态请求
+type RobotGridStatusReq struct {
+	g.Meta `path:"/trading/robot/grid/status" method:"get" tags:"交易管理" summary:"网格运行状态" dc:"查看网格机器人各格子挂单/持仓与网格利润"`
+	input.TradingRobotGridStatusInp
+}
+
+// RobotGridStatusRes 网格运行状态响应
+type RobotGridStatusRes struct {
+	*input.TradingRobotGridStatusModel
+}
+
 // RobotStatsReq 获取运行统计请求
 type RobotStatsReq struct {
 	g.Meta `path:"/trading/robot/stats" method:"get" tags:"交易管理" summary:"获取运行统计" dc:"获取机器人运行统计数据"`
@@ -0,0 +1,85 @@
+// Package trading
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+
+package trading
+
+import (
+	"hotgo/internal/model/input"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// RobotBasketListReq 组合机器人列表请求
+type RobotBasketListReq struct {
+	g.Meta `path:"/trading/robot/basket/list" method:"get" tags:"交易管理" summary:"组合机器人列表" dc:"获取组合机器人列表"`
+	input.TradingRobotBasketListInp
+}
+
+// RobotBasketListRes 组合机器人列表响应
+type RobotBasketListRes struct {
+	List       []*input.TradingRobotBasketListModel `json:"list" dc:"列表数据"`
+	TotalCount int                                  `json:"totalCount" dc:"总数"`
+	Page       int                                  `json:"page" dc:"当前页"`
+	PageSize   int                                  `json:"pageSize" dc:"每页数量"`
+}
+
+// RobotBasketCreateReq 创建组合机器人请求
+type RobotBasketCreateReq struct {
+	g.Meta `path:"/trading/robot/basket/create" method:"post" tags:"交易管理" summary:"创建组合机器人" dc:"使用一个API账户同时交易多个交易对"`
+	input.TradingRobotBasketCreateInp
+}
+
+// RobotBasketCreateRes 创建组合机器人响应
+type RobotBasketCreateRes struct {
+	Id int64 `json:"id" dc:"组合ID"`
+}
+
+// RobotBasketViewReq 组合机器人详情请求
+type RobotBasketViewReq struct {
+	g.Meta `path:"/trading/robot/basket/view" method:"get" tags:"交易管理" summary:"组合机器人详情" dc:"查看组合及各交易对盈亏"`
+	input.TradingRobotBasketIdInp
+}
+
+// RobotBasketViewRes 组合机器人详情响应
+type RobotBasketViewRes struct {
+	*input.TradingRobotBasketViewModel
+}
+
+// RobotBasketStartReq 启动组合机器人请求
+type RobotBasketStartReq struct {
+	g.Meta `path:"/trading/robot/basket/start" method:"post" tags:"交易管理" summary:"启动组合机器人" dc:"启动组合下全部交易对"`
+	input.TradingRobotBasketIdInp
+}
+
+// RobotBasketStartRes 启动组合机器人响应
+type RobotBasketStartRes struct{}
+
+// RobotBasketPauseReq 暂停组合机器人请求
+type RobotBasketPauseReq struct {
+	g.Meta `path:"/trading/robot/basket/pause" method:"post" tags:"交易管理" summary:"暂停组合机器人" dc:"暂停组合下全部交易对"`
+	input.TradingRobotBasketIdInp
+}
+
+// RobotBasketPauseRes 暂停组合机器人响应
+type RobotBasketPauseRes struct{}
+
+// RobotBasketStopReq 停用组合机器人请求
+type RobotBasketStopReq struct {
+	g.Meta `path:"/trading/robot/basket/stop" method:"post" tags:"交易管理" summary:"停用组合机器人" dc:"停用组合下全部交易对"`
+	input.TradingRobotBasketIdInp
+}
+
+// RobotBasketStopRes 停用组合机器人响应
+type RobotBasketStopRes struct{}
+
+// RobotBasketDeleteReq 删除组合机器人请求
+type RobotBasketDeleteReq struct {
+	g.Meta `path:"/trading/robot/basket/delete" method:"post" tags:"交易管理" summary:"删除组合机器人" dc:"删除组合及其全部交易对"`
+	input.TradingRobotBasketIdInp
+}
+
+// RobotBasketDeleteRes 删除组合机器人响应
+type RobotBasketDeleteRes struct{}
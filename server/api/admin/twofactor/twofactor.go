@@ -0,0 +1,67 @@
+// Package twofactor
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package twofactor
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"hotgo/internal/model/input/sysin"
+)
+
+// StatusReq 获取双因素认证状态
+type StatusReq struct {
+	g.Meta `path:"/twoFactor/status" method:"get" tags:"双因素认证" summary:"获取双因素认证状态"`
+}
+
+type StatusRes struct {
+	*sysin.TwoFactorStatusModel
+}
+
+// SetupReq 生成待绑定的验证器密钥
+type SetupReq struct {
+	g.Meta `path:"/twoFactor/setup" method:"post" tags:"双因素认证" summary:"生成待绑定的验证器密钥"`
+}
+
+type SetupRes struct {
+	*sysin.TwoFactorSetupModel
+}
+
+// EnableReq 启用双因素认证
+type EnableReq struct {
+	g.Meta `path:"/twoFactor/enable" method:"post" tags:"双因素认证" summary:"提交验证器口令启用双因素认证"`
+	sysin.TwoFactorCodeInp
+}
+
+type EnableRes struct {
+	*sysin.TwoFactorRecoveryModel
+}
+
+// DisableReq 停用双因素认证
+type DisableReq struct {
+	g.Meta `path:"/twoFactor/disable" method:"post" tags:"双因素认证" summary:"停用双因素认证"`
+	sysin.TwoFactorCodeInp
+}
+
+type DisableRes struct{}
+
+// RecoveryCodesReq 重新生成恢复码
+type RecoveryCodesReq struct {
+	g.Meta `path:"/twoFactor/recoveryCodes" method:"post" tags:"双因素认证" summary:"重新生成恢复码"`
+	sysin.TwoFactorCodeInp
+}
+
+type RecoveryCodesRes struct {
+	*sysin.TwoFactorRecoveryModel
+}
+
+// VerifyReq 敏感操作二次验证
+type VerifyReq struct {
+	g.Meta `path:"/twoFactor/verify" method:"post" tags:"双因素认证" summary:"敏感操作二次验证" dc:"验证通过后一段时间内执行敏感操作无需再次输入口令"`
+	sysin.TwoFactorCodeInp
+}
+
+type VerifyRes struct {
+	*sysin.TwoFactorVerifyModel
+}
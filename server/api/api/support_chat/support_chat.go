@@ -14,6 +14,9 @@ type ISupportChatV1 interface {
 	Start(ctx context.Context, req *v1.StartReq) (res *v1.StartRes, err error)
 	Send(ctx context.Context, req *v1.SendReq) (res *v1.SendRes, err error)
 	MessageList(ctx context.Context, req *v1.MessageListReq) (res *v1.MessageListRes, err error)
+	SendAttachment(ctx context.Context, req *v1.SendAttachmentReq) (res *v1.SendAttachmentRes, err error)
+	Read(ctx context.Context, req *v1.ReadReq) (res *v1.ReadRes, err error)
+	Rate(ctx context.Context, req *v1.RateReq) (res *v1.RateRes, err error)
 }
 
 
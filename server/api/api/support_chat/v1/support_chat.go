@@ -39,3 +39,31 @@ type MessageListRes struct {
 }
 
 
+
+// SendAttachmentReq 发送附件（用户）
+type SendAttachmentReq struct {
+	g.Meta `path:"/supportChat/sendAttachment" method:"post" mime:"multipart/form-data" tags:"客服" summary:"发送附件(用户)"`
+	apiin.SupportSendAttachmentInp
+}
+
+type SendAttachmentRes struct {
+	*entity.SupportMessage `json:"message" dc:"消息"`
+}
+
+// ReadReq 标记已读（用户）
+type ReadReq struct {
+	g.Meta `path:"/supportChat/read" method:"post" tags:"客服" summary:"标记已读(用户)"`
+	apiin.SupportReadInp
+}
+
+type ReadRes struct{}
+
+// RateReq 会话评价（用户）
+type RateReq struct {
+	g.Meta `path:"/supportChat/rate" method:"post" tags:"客服" summary:"会话评价(用户)"`
+	apiin.SupportRateInp
+}
+
+type RateRes struct {
+	*entity.SupportSession `json:"session" dc:"会话"`
+}
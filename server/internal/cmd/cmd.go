@@ -40,6 +40,10 @@ var (
 		>> 释放casbin权限，用于清理无效的权限设置  [go run main.go tools -m=casbin -a1=refresh]
 		>> 打印所有打包的资源文件列表  [go run main.go tools -m=gres -a1=dump]
 		>> 打印指定打包的资源文件内容  [go run main.go tools -m=gres -a1=content -a2=resource/template/home/index.html]
+		>> 市场状态分类器离线评估  [go run main.go tools -m=marketEval -symbol=BTCUSDT -a=current -b=storage/eval_b.json -group=1]
+		>> 初始化API密钥保险箱的本地密钥环（首次部署）  [go run main.go tools -m=vault -a1=init]
+		>> 轮换API密钥主密钥并重新包装所有API配置  [go run main.go tools -m=vault -a1=rotate]
+		>> 迁移旧版加密的API配置到当前主密钥版本  [go run main.go tools -m=vault -a1=rewrap]
 		---------------------------------------------------------------------------------
 		升级更新
 		>> 修复菜单关系树  [go run main.go up -m=fix -a1=menuTree]
@@ -7,6 +7,9 @@ import (
 	"time"
 
 	_ "github.com/gogf/gf/contrib/drivers/mysql/v2"
+	_ "hotgo/addons/exchange_binance"
+	_ "hotgo/addons/exchange_gate"
+	_ "hotgo/addons/exchange_okx"
 	_ "hotgo/internal/packed"
 
 	"github.com/gogf/gf/v2/os/gctx"
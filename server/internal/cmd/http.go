@@ -10,6 +10,7 @@ import (
 	"hotgo/internal/library/addons"
 	"hotgo/internal/library/casbin"
 	"hotgo/internal/library/hggen"
+	"hotgo/internal/library/metrics"
 	"hotgo/internal/logic/toogo"
 	"hotgo/internal/router"
 	"hotgo/internal/service"
@@ -60,6 +61,11 @@ var (
 				router.Home(ctx, group)
 			})
 
+			// 注册Prometheus指标接口
+			if g.Cfg().MustGet(ctx, "metrics.switch").Bool() {
+				s.BindHandler(g.Cfg().MustGet(ctx, "metrics.path", "/metrics").String(), metrics.Handler)
+			}
+
 			// 启动插件
 			if err = addons.StartModules(ctx, &addons.Option{Server: s}); err != nil {
 				return err
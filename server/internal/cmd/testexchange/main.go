@@ -4,6 +4,9 @@ package main
 import (
 	"context"
 	"fmt"
+	_ "hotgo/addons/exchange_binance"
+	_ "hotgo/addons/exchange_gate"
+	_ "hotgo/addons/exchange_okx"
 	"hotgo/internal/library/exchange"
 	"net/url"
 	"os"
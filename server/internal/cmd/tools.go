@@ -39,6 +39,10 @@ var (
 				err = handleCasbin(ctx, args)
 			case "gres":
 				err = handleGRes(ctx, args)
+			case "marketEval":
+				err = handleMarketEval(ctx, args)
+			case "vault":
+				err = handleVault(ctx, args)
 			default:
 				err = gerror.Newf("tools method[%v] does not exist", method)
 			}
@@ -0,0 +1,170 @@
+// Package cmd
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 市场状态分类器离线评估工具
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/util/gconv"
+
+	"hotgo/internal/dao"
+	configlib "hotgo/internal/library/config"
+	"hotgo/internal/library/exchange"
+	"hotgo/internal/library/market"
+	"hotgo/internal/model/entity"
+)
+
+// handleMarketEval 市场状态分类器离线评估
+// 参数：
+//
+//	-platform  K线来源交易所，默认 binance
+//	-symbol    交易对，默认 BTCUSDT
+//	-limit     拉取1m K线数量，默认 1500
+//	-file      历史1m K线JSON文件（[]exchange.Kline），指定后不再请求交易所
+//	-a         配置A：current=数据库当前配置，default=内置默认配置，或 StateEvalConfig JSON 文件路径，默认 current
+//	-b         配置B（可选），取值同 -a，指定后输出对比
+//	-group     策略组ID（可选），按市场状态加载策略模板做模拟交易
+//	-risk      风险偏好，默认 balanced
+//	-horizon   前瞻K线数，默认 15
+func handleMarketEval(ctx context.Context, args map[string]string) (err error) {
+	platform := market.NormalizePlatform(argOrDefault(args, "platform", "binance"))
+	symbol := market.NormalizeSymbol(argOrDefault(args, "symbol", "BTCUSDT"))
+
+	klines, err := loadEvalKlines(ctx, platform, symbol, args)
+	if err != nil {
+		return
+	}
+
+	opts := &market.StateEvalOptions{Horizon: gconv.Int(args["horizon"])}
+	if groupId := gconv.Int64(args["group"]); groupId > 0 {
+		if opts.Templates, err = loadEvalTemplates(ctx, groupId, argOrDefault(args, "risk", "balanced")); err != nil {
+			return
+		}
+	}
+
+	cfgA, err := loadEvalConfig(ctx, symbol, argOrDefault(args, "a", "current"))
+	if err != nil {
+		return
+	}
+	reportA, err := market.EvaluateMarketStates(klines, cfgA, opts)
+	if err != nil {
+		return
+	}
+	fmt.Printf("市场状态离线评估: platform=%s symbol=%s klines=%d\n", platform, symbol, len(klines))
+	fmt.Print(market.FormatStateEvalReport(reportA))
+
+	source, ok := args["b"]
+	if !ok || source == "" {
+		return
+	}
+	cfgB, err := loadEvalConfig(ctx, symbol, source)
+	if err != nil {
+		return
+	}
+	reportB, err := market.EvaluateMarketStates(klines, cfgB, opts)
+	if err != nil {
+		return
+	}
+	fmt.Print(market.FormatStateEvalReport(reportB))
+	fmt.Print(market.FormatStateEvalDiff(reportA, reportB))
+	return
+}
+
+func argOrDefault(args map[string]string, key, def string) string {
+	if v, ok := args[key]; ok && strings.TrimSpace(v) != "" {
+		return strings.TrimSpace(v)
+	}
+	return def
+}
+
+// loadEvalKlines 加载评估用1m K线：优先读文件，否则通过公共行情接口拉取
+func loadEvalKlines(ctx context.Context, platform, symbol string, args map[string]string) (klines []*exchange.Kline, err error) {
+	if path, ok := args["file"]; ok && path != "" {
+		if !gfile.Exists(path) {
+			return nil, gerror.Newf("K线文件不存在: %v", path)
+		}
+		if err = gjson.DecodeTo(gfile.GetBytes(path), &klines); err != nil {
+			return nil, gerror.Wrapf(err, "解析K线文件失败: %v", path)
+		}
+		return
+	}
+
+	ex, err := exchange.NewExchange(&exchange.Config{Platform: platform})
+	if err != nil {
+		return
+	}
+	return ex.GetKlines(ctx, symbol, "1m", gconv.Int(argOrDefault(args, "limit", "1500")))
+}
+
+// loadEvalConfig 加载评估配置：current/default/JSON文件
+func loadEvalConfig(ctx context.Context, symbol, source string) (cfg *market.StateEvalConfig, err error) {
+	switch source {
+	case "current":
+		manager := configlib.GetVolatilityConfigManager()
+		if err = manager.LoadAllConfigs(ctx); err != nil {
+			return
+		}
+		vc := *manager.GetConfig(symbol)
+		return market.DefaultStateEvalConfig("current", &vc), nil
+	case "default":
+		vc := *configlib.GetVolatilityConfigManager().GetConfig("")
+		return market.DefaultStateEvalConfig("default", &vc), nil
+	}
+
+	if !gfile.Exists(source) {
+		return nil, gerror.Newf("评估配置文件不存在: %v", source)
+	}
+	cfg = market.DefaultStateEvalConfig(gfile.Name(source), nil)
+	if err = gjson.DecodeTo(gfile.GetBytes(source), cfg); err != nil {
+		return nil, gerror.Wrapf(err, "解析评估配置失败: %v", source)
+	}
+	if cfg.Volatility == nil {
+		return nil, gerror.Newf("评估配置缺少 volatility: %v", source)
+	}
+	return
+}
+
+// loadEvalTemplates 加载策略组下各市场状态对应的策略模板
+func loadEvalTemplates(ctx context.Context, groupId int64, riskPreference string) (map[string]*market.StateEvalTemplate, error) {
+	var list []*entity.TradingStrategyTemplate
+	if err := dao.TradingStrategyTemplate.Ctx(ctx).
+		Where("group_id", groupId).
+		Where(dao.TradingStrategyTemplate.Columns().RiskPreference, riskPreference).
+		Scan(&list); err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, gerror.Newf("策略组[%v]在风险偏好[%v]下没有策略模板", groupId, riskPreference)
+	}
+
+	templates := make(map[string]*market.StateEvalTemplate, len(list))
+	for _, t := range list {
+		state := t.MarketState
+		switch state {
+		case "range":
+			state = "volatile"
+		case "high-volatility":
+			state = "high_vol"
+		case "low-volatility":
+			state = "low_vol"
+		}
+		templates[state] = &market.StateEvalTemplate{
+			StrategyName:            t.StrategyName,
+			MonitorWindow:           t.MonitorWindow,
+			VolatilityThreshold:     t.VolatilityThreshold,
+			Leverage:                t.Leverage,
+			StopLossPercent:         t.StopLossPercent,
+			AutoStartRetreatPercent: t.AutoStartRetreatPercent,
+			ProfitRetreatPercent:    t.ProfitRetreatPercent,
+		}
+	}
+	return templates, nil
+}
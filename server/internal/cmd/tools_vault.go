@@ -0,0 +1,81 @@
+// Package cmd
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description API密钥保险箱维护工具
+package cmd
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"hotgo/internal/library/vault"
+	"hotgo/internal/logic/toogo"
+	"hotgo/internal/logic/trading"
+)
+
+// handleVault API密钥保险箱维护
+// 参数：
+//
+//	-a1=init    初始化本地密钥环（首次部署执行一次，密钥环已存在时报错）
+//	-a1=rotate  生成新版本主密钥，并将所有API配置、租户收款凭证重新包装到新版本
+//	-a1=rewrap  将旧版静态密钥加密的数据迁移为信封加密，并把旧版本包装的数据迁移到当前版本
+//
+// 两个操作均可在服务运行期间执行，旧版本主密钥保留在密钥环中，迁移完成前不影响读取
+func handleVault(ctx context.Context, args map[string]string) (err error) {
+	a1, ok := args["a1"]
+	if !ok {
+		err = gerror.New("vault args cannot be empty.")
+		return
+	}
+
+	if a1 == "init" {
+		path, err := vault.InitKeyring(ctx)
+		if err != nil {
+			return err
+		}
+		g.Log().Infof(ctx, "vault keyring initialised, path:%v, version:1", path)
+		return nil
+	}
+
+	v, err := vault.Instance(ctx)
+	if err != nil {
+		return
+	}
+
+	switch a1 {
+	case "rotate":
+		version, err := v.Rotate(ctx)
+		if err != nil {
+			return err
+		}
+		g.Log().Infof(ctx, "vault master key rotated, kms:%v, version:%v", v.KMS().Name(), version)
+	case "rewrap":
+	default:
+		return gerror.Newf("vault a1 is invalid, a1:%v", a1)
+	}
+
+	res, err := trading.ApiConfig.RewrapAll(ctx)
+	if res != nil {
+		g.Log().Infof(ctx, "vault rewrap finished, version:%v, total:%v, rewrapped:%v, skipped:%v, conflicts:%v, failed:%v",
+			res.KeyVersion, res.Total, res.Rewrapped, res.Skipped, res.Conflicts, res.Failed)
+	}
+	if err != nil {
+		return
+	}
+
+	tenantRes, err := toogo.RewrapTenantCredentials(ctx)
+	if tenantRes != nil {
+		g.Log().Infof(ctx, "vault tenant rewrap finished, total:%v, rewrapped:%v, skipped:%v, conflicts:%v, failed:%v",
+			tenantRes.Total, tenantRes.Rewrapped, tenantRes.Skipped, tenantRes.Conflicts, tenantRes.Failed)
+	}
+	if err != nil {
+		return
+	}
+	if failed := len(res.Failed) + len(tenantRes.Failed); failed > 0 {
+		err = gerror.Newf("vault rewrap failed for %d records", failed)
+	}
+	return
+}
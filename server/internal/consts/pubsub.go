@@ -6,7 +6,10 @@
 package consts
 
 const (
-	ClusterSyncSysconfig     = "cluster.sync.sysConfig"    // 系统配置
-	ClusterSyncSysBlacklist  = "cluster.sync.sysBlacklist" // 系统黑名单
-	ClusterSyncSysSuperAdmin = "cluster.sync.superAdmin"   // 超管
+	ClusterSyncSysconfig     = "cluster.sync.sysConfig"     // 系统配置
+	ClusterSyncSysBlacklist  = "cluster.sync.sysBlacklist"  // 系统黑名单
+	ClusterSyncSysSuperAdmin = "cluster.sync.superAdmin"    // 超管
+	ClusterSyncWebsocket     = "cluster.sync.websocket"     // websocket跨节点推送
+	ClusterSyncWebsocketHub  = "cluster.sync.websocketHub"  // toogo websocket hub跨节点推送
+	ClusterSyncRobotSnapshot = "cluster.sync.robotSnapshot" // 机器人实时快照跨节点转发
 )
@@ -60,6 +60,36 @@ const (
 	SensitiveOpApiKey     = "api_key"    // API密钥操作
 	SensitiveOpPassword   = "password"   // 密码修改
 	SensitiveOpRobotStart = "robot_start" // 机器人启动
+
+	SensitiveOpWithdrawAddress = "withdraw_address" // 修改提现账户
+	SensitiveOpRobotStopAll    = "robot_stop_all"   // 停止全部机器人
+	SensitiveOpTwoFactor       = "two_factor"       // 双因素认证设置
+	SensitiveOpSupportAction   = "support_action"   // 客服代用户执行运维操作
+)
+
+// 双因素认证相关常量
+const (
+	// TwoFactorCodeHeader 敏感操作提交动态口令的请求头，也可通过请求参数 TwoFactorCodeParam 提交
+	TwoFactorCodeHeader = "X-Two-Factor-Code"
+	TwoFactorCodeParam  = "twoFactorCode"
+
+	// TwoFactorStepUpTTL 二次验证通过后，敏感操作免验证时长（秒）
+	TwoFactorStepUpTTL = 300
+
+	// TwoFactorLoginTicketTTL 登录二次验证票据有效期（秒）
+	TwoFactorLoginTicketTTL = 300
+
+	// TwoFactorMaxAttempts 动态口令最多尝试次数（单个登录票据，以及同一用户在锁定时长内各场景累计）
+	TwoFactorMaxAttempts = 5
+
+	// TwoFactorLockDuration 动态口令错误次数达到上限后的锁定时间（秒）
+	TwoFactorLockDuration = 300
+
+	// TwoFactorRecoveryCount 恢复码数量
+	TwoFactorRecoveryCount = 10
+
+	// CodeTwoFactorRequired 敏感操作需要二次验证的错误码，前端据此弹出口令输入框后重试
+	CodeTwoFactorRequired = 1010
 )
 
 // IP白名单相关
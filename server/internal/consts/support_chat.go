@@ -24,7 +24,16 @@ const (
 )
 
 const (
-	// SupportWsTagAgents 客服在线标签（WS join 后用于广播排队/新会话）
+	// SupportMsgTypeText 文本
+	SupportMsgTypeText = 1
+	// SupportMsgTypeImage 图片（content 为图片地址）
+	SupportMsgTypeImage = 2
+	// SupportMsgTypeFile 文件（content 为文件地址）
+	SupportMsgTypeFile = 3
+)
+
+const (
+	// SupportWsTagAgents 客服在线频道（WS join 后用于广播排队/新会话，断线重连可按序号补发）
 	SupportWsTagAgents = "support_agents"
 )
 
@@ -33,6 +42,16 @@ const (
 	SupportWsEventSessionUpdated = "support/session/updated"
 	// SupportWsEventMessage 新消息
 	SupportWsEventMessage = "support/message"
+	// SupportWsEventRead 已读回执（推送给消息发送方）
+	SupportWsEventRead = "support/read"
+	// SupportWsEventTyping 对方正在输入
+	SupportWsEventTyping = "support/typing"
 )
 
-
+// 客服工作台对会话用户可执行的操作，值为对应接口路由，用于按角色权限下发可用操作
+const (
+	// SupportActionRobotRestart 重建机器人引擎
+	SupportActionRobotRestart = "/supportChat/robot/restart"
+	// SupportActionRobotSyncOrders 重新同步机器人订单/持仓
+	SupportActionRobotSyncOrders = "/supportChat/robot/syncOrders"
+)
@@ -65,6 +65,51 @@ const (
 	FreePowerGift = 30.0
 )
 
+// 订阅状态
+const (
+	SubscriptionStatusPending   = 1 // 待支付
+	SubscriptionStatusActive    = 2 // 生效中
+	SubscriptionStatusExpired   = 3 // 已过期
+	SubscriptionStatusCancelled = 4 // 已取消
+	SubscriptionStatusGrace     = 5 // 宽限期：机器人只管理持仓，不再开新仓
+	SubscriptionStatusReplaced  = 6 // 已变更：升降级时被新订阅替换
+)
+
+// 订阅周期：免费试用（套餐配置 trial_days，每个用户仅限一次）
+const SubscriptionPeriodTrial = "trial"
+
+// 优惠券类型
+const (
+	CouponTypePercent = "percent" // 按比例折扣：discount_value 为折扣百分比
+	CouponTypeFixed   = "fixed"   // 固定金额减免：discount_value 为减免USDT
+)
+
+// 佣金记录状态
+const (
+	CommissionStatusFrozen   = 1 // 冻结中：结算期内计入冻结佣金，到期由定时任务解冻入账
+	CommissionStatusSettled  = 2 // 已结算
+	CommissionStatusReversed = 3 // 已冲正：来源订单退款，冲正记录见 reversal_of
+)
+
+// 佣金账户变动类型
+const (
+	CommissionChangeRelease  = "commission_release"  // 冻结佣金解冻入账
+	CommissionChangeReversal = "commission_reversal" // 来源订单退款冲正（允许佣金余额为负）
+)
+
+// VIP等级变更类型
+const (
+	VipChangeUpgrade   = "upgrade"
+	VipChangeDowngrade = "downgrade"
+)
+
+// VIP等级评估来源
+const (
+	VipEvaluateSourceCron  = "cron"  // 定时任务
+	VipEvaluateSourceCheck = "check" // 用户主动检查升级
+	VipEvaluateSourceAdmin = "admin" // 管理员手动触发
+)
+
 // API配置
 const (
 	// API请求超时(秒)
@@ -77,6 +122,14 @@ const (
 	ApiRateLimit = 1200
 )
 
+// API Key权限检测状态
+const (
+	ApiPermStatusUnchecked = 0 // 未检测
+	ApiPermStatusNormal    = 1 // 正常
+	ApiPermStatusWarning   = 2 // 风险提示：未绑定IP、权限无法确认、即将过期
+	ApiPermStatusDanger    = 3 // 不可用：开启提现、无合约交易权限、已过期，机器人禁止启动
+)
+
 // 策略限制
 const (
 	// 每组最大策略数
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	api "hotgo/api/admin/support_chat"
+	"hotgo/internal/library/contexts"
 	"hotgo/internal/service"
 )
 
@@ -64,6 +65,21 @@ func (c *cSupportChat) Send(ctx context.Context, req *api.SendReq) (res *api.Sen
 	return
 }
 
+func (c *cSupportChat) SendAttachment(ctx context.Context, req *api.SendAttachmentReq) (res *api.SendAttachmentRes, err error) {
+	msg, err := service.SupportChat().SendAgentAttachment(ctx, &req.SupportSendAttachmentInp)
+	if err != nil {
+		return nil, err
+	}
+	res = new(api.SendAttachmentRes)
+	res.SupportMessage = msg
+	return
+}
+
+func (c *cSupportChat) Read(ctx context.Context, req *api.ReadReq) (res *api.ReadRes, err error) {
+	err = service.SupportChat().MarkRead(ctx, contexts.GetUserId(ctx), req.SessionId)
+	return
+}
+
 func (c *cSupportChat) MessageList(ctx context.Context, req *api.MessageListReq) (res *api.MessageListRes, err error) {
 	list, total, err := service.SupportChat().MessageList(ctx, &req.SupportMessageListInp)
 	if err != nil {
@@ -85,6 +101,47 @@ func (c *cSupportChat) Transfer(ctx context.Context, req *api.TransferReq) (res
 	return
 }
 
+func (c *cSupportChat) Escalate(ctx context.Context, req *api.EscalateReq) (res *api.EscalateRes, err error) {
+	session, err := service.SupportChat().Escalate(ctx, &req.SupportEscalateInp)
+	if err != nil {
+		return nil, err
+	}
+	res = new(api.EscalateRes)
+	res.SupportSession = session
+	return
+}
+
+func (c *cSupportChat) ExecutionLogList(ctx context.Context, req *api.ExecutionLogListReq) (res *api.ExecutionLogListRes, err error) {
+	list, total, err := service.SupportChat().ExecutionLogList(ctx, &req.SupportExecutionLogListInp)
+	if err != nil {
+		return nil, err
+	}
+	res = new(api.ExecutionLogListRes)
+	res.List = list
+	res.PageRes.Pack(req, total)
+	return
+}
+
+func (c *cSupportChat) Context(ctx context.Context, req *api.ContextReq) (res *api.ContextRes, err error) {
+	data, err := service.SupportChat().SessionContext(ctx, &req.SupportContextInp)
+	if err != nil {
+		return nil, err
+	}
+	res = new(api.ContextRes)
+	res.SupportContextModel = data
+	return
+}
+
+func (c *cSupportChat) RobotRestart(ctx context.Context, req *api.RobotRestartReq) (res *api.RobotRestartRes, err error) {
+	err = service.SupportChat().RestartRobot(ctx, &req.SupportRobotActionInp)
+	return
+}
+
+func (c *cSupportChat) RobotSyncOrders(ctx context.Context, req *api.RobotSyncOrdersReq) (res *api.RobotSyncOrdersRes, err error) {
+	err = service.SupportChat().SyncRobotOrders(ctx, &req.SupportRobotActionInp)
+	return
+}
+
 func (c *cSupportChat) CannedList(ctx context.Context, req *api.CannedListReq) (res *api.CannedListRes, err error) {
 	list, total, err := service.SupportChat().CannedList(ctx, &req.SupportCannedListInp)
 	if err != nil {
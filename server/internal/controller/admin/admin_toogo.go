@@ -7,6 +7,7 @@ package admin
 import (
 	"context"
 	"hotgo/api/admin"
+	"hotgo/internal/consts"
 	"hotgo/internal/library/contexts"
 	"hotgo/internal/model/input/toogoin"
 	"hotgo/internal/service"
@@ -211,6 +212,101 @@ func (c *cToogo) MySubscription(ctx context.Context, req *admin.ToogoMySubscript
 	return
 }
 
+// PlanChangeQuote 升级/降级套餐试算
+func (c *cToogo) PlanChangeQuote(ctx context.Context, req *admin.ToogoPlanChangeQuoteReq) (res *admin.ToogoPlanChangeQuoteRes, err error) {
+	req.UserId = contexts.GetUserId(ctx)
+	data, err := service.ToogoSubscription().PlanChangeQuote(ctx, &req.ChangePlanInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoPlanChangeQuoteRes{ChangePlanModel: data}
+	return
+}
+
+// ChangePlan 升级/降级套餐
+func (c *cToogo) ChangePlan(ctx context.Context, req *admin.ToogoChangePlanReq) (res *admin.ToogoChangePlanRes, err error) {
+	req.UserId = contexts.GetUserId(ctx)
+	data, err := service.ToogoSubscription().ChangePlan(ctx, &req.ChangePlanInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoChangePlanRes{ChangePlanModel: data}
+	return
+}
+
+// SubscriptionAutoRenew 设置自动续费
+func (c *cToogo) SubscriptionAutoRenew(ctx context.Context, req *admin.ToogoSubscriptionAutoRenewReq) (res *admin.ToogoSubscriptionAutoRenewRes, err error) {
+	req.UserId = contexts.GetUserId(ctx)
+	err = service.ToogoSubscription().SetAutoRenew(ctx, &req.SubscriptionAutoRenewInp)
+	return
+}
+
+// SubscriptionRefund 订阅退款（管理员）
+func (c *cToogo) SubscriptionRefund(ctx context.Context, req *admin.ToogoSubscriptionRefundReq) (res *admin.ToogoSubscriptionRefundRes, err error) {
+	data, err := service.ToogoSubscription().RefundSubscription(ctx, &req.SubscriptionRefundInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoSubscriptionRefundRes{SubscriptionRefundModel: data}
+	return
+}
+
+// SubscriptionLogList 订阅变更记录
+func (c *cToogo) SubscriptionLogList(ctx context.Context, req *admin.ToogoSubscriptionLogListReq) (res *admin.ToogoSubscriptionLogListRes, err error) {
+	if req.UserId == 0 {
+		req.UserId = contexts.GetUserId(ctx)
+	}
+	list, totalCount, err := service.ToogoSubscription().SubscriptionLogList(ctx, &req.SubscriptionLogListInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoSubscriptionLogListRes{List: list, TotalCount: totalCount}
+	return
+}
+
+// CouponList 优惠券列表
+func (c *cToogo) CouponList(ctx context.Context, req *admin.ToogoCouponListReq) (res *admin.ToogoCouponListRes, err error) {
+	list, totalCount, err := service.ToogoSubscription().CouponList(ctx, &req.CouponListInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoCouponListRes{List: list, TotalCount: totalCount}
+	return
+}
+
+// CouponEdit 编辑优惠券/推广码
+func (c *cToogo) CouponEdit(ctx context.Context, req *admin.ToogoCouponEditReq) (res *admin.ToogoCouponEditRes, err error) {
+	err = service.ToogoSubscription().CouponEdit(ctx, &req.CouponEditInp)
+	return
+}
+
+// CouponDelete 删除优惠券
+func (c *cToogo) CouponDelete(ctx context.Context, req *admin.ToogoCouponDeleteReq) (res *admin.ToogoCouponDeleteRes, err error) {
+	err = service.ToogoSubscription().CouponDelete(ctx, &req.CouponDeleteInp)
+	return
+}
+
+// CouponUsageList 优惠券使用记录
+func (c *cToogo) CouponUsageList(ctx context.Context, req *admin.ToogoCouponUsageListReq) (res *admin.ToogoCouponUsageListRes, err error) {
+	list, totalCount, err := service.ToogoSubscription().CouponUsageList(ctx, &req.CouponUsageListInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoCouponUsageListRes{List: list, TotalCount: totalCount}
+	return
+}
+
+// CouponPreview 优惠码试算
+func (c *cToogo) CouponPreview(ctx context.Context, req *admin.ToogoCouponPreviewReq) (res *admin.ToogoCouponPreviewRes, err error) {
+	req.UserId = contexts.GetUserId(ctx)
+	data, err := service.ToogoSubscription().CouponPreview(ctx, &req.CouponPreviewInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoCouponPreviewRes{CouponPreviewModel: data}
+	return
+}
+
 // ========== 用户管理 ==========
 
 // UserInfo 用户信息
@@ -238,6 +334,16 @@ func (c *cToogo) UserList(ctx context.Context, req *admin.ToogoUserListReq) (res
 	return
 }
 
+// ChangeInviter 调整邀请人
+func (c *cToogo) ChangeInviter(ctx context.Context, req *admin.ToogoChangeInviterReq) (res *admin.ToogoChangeInviterRes, err error) {
+	data, err := service.ToogoUser().ChangeInviter(ctx, &req.ChangeInviterInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoChangeInviterRes{ChangeInviterModel: data}
+	return
+}
+
 // RefreshInviteCode 刷新邀请码
 func (c *cToogo) RefreshInviteCode(ctx context.Context, req *admin.ToogoRefreshInviteCodeReq) (res *admin.ToogoRefreshInviteCodeRes, err error) {
 	memberId := contexts.GetUserId(ctx)
@@ -301,6 +407,27 @@ func (c *cToogo) CheckVipUpgrade(ctx context.Context, req *admin.ToogoCheckVipUp
 	return
 }
 
+// VipEvaluate 重新评估VIP等级
+func (c *cToogo) VipEvaluate(ctx context.Context, req *admin.ToogoVipEvaluateReq) (res *admin.ToogoVipEvaluateRes, err error) {
+	req.Source = consts.VipEvaluateSourceAdmin
+	data, err := service.ToogoUser().EvaluateVipLevels(ctx, &req.VipEvaluateInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoVipEvaluateRes{VipEvaluateModel: data}
+	return
+}
+
+// VipLevelLogList VIP等级变更记录
+func (c *cToogo) VipLevelLogList(ctx context.Context, req *admin.ToogoVipLevelLogListReq) (res *admin.ToogoVipLevelLogListRes, err error) {
+	list, totalCount, err := service.ToogoUser().VipLevelLogList(ctx, &req.VipLevelLogListInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoVipLevelLogListRes{List: list, TotalCount: totalCount}
+	return
+}
+
 // ========== 佣金管理 ==========
 
 // CommissionLogList 佣金记录列表
@@ -328,6 +455,35 @@ func (c *cToogo) CommissionStat(ctx context.Context, req *admin.ToogoCommissionS
 	return
 }
 
+// AgentDashboard 代理看板
+func (c *cToogo) AgentDashboard(ctx context.Context, req *admin.ToogoAgentDashboardReq) (res *admin.ToogoAgentDashboardRes, err error) {
+	req.MemberId = contexts.GetUserId(ctx)
+	data, err := service.ToogoCommission().AgentDashboard(ctx, &req.AgentDashboardInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoAgentDashboardRes{AgentDashboardModel: data}
+	return
+}
+
+// AgentStatement 代理月度对账单
+func (c *cToogo) AgentStatement(ctx context.Context, req *admin.ToogoAgentStatementReq) (res *admin.ToogoAgentStatementRes, err error) {
+	req.MemberId = contexts.GetUserId(ctx)
+	data, err := service.ToogoCommission().AgentStatement(ctx, &req.AgentStatementInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoAgentStatementRes{AgentStatementModel: data}
+	return
+}
+
+// AgentStatementExport 下载代理月度对账单
+func (c *cToogo) AgentStatementExport(ctx context.Context, req *admin.ToogoAgentStatementExportReq) (res *admin.ToogoAgentStatementExportRes, err error) {
+	req.MemberId = contexts.GetUserId(ctx)
+	err = service.ToogoCommission().AgentStatementExport(ctx, &req.AgentStatementExportInp)
+	return
+}
+
 // AgentLevelList 代理商等级列表
 func (c *cToogo) AgentLevelList(ctx context.Context, req *admin.ToogoAgentLevelListReq) (res *admin.ToogoAgentLevelListRes, err error) {
 	list, totalCount, err := service.ToogoCommission().AgentLevelList(ctx, &req.AgentLevelListInp)
@@ -469,6 +625,26 @@ func (c *cToogo) PowerConsumeStat(ctx context.Context, req *admin.ToogoPowerCons
 	return
 }
 
+// PerformanceFeePreview 业绩费试算报告（管理员）
+func (c *cToogo) PerformanceFeePreview(ctx context.Context, req *admin.ToogoPerformanceFeePreviewReq) (res *admin.ToogoPerformanceFeePreviewRes, err error) {
+	data, err := service.ToogoWallet().PerformanceFeeReport(ctx, &req.PerformanceFeeInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoPerformanceFeePreviewRes{PerformanceFeeReport: data}
+	return
+}
+
+// PerformanceFeeSettle 业绩费结算（管理员）
+func (c *cToogo) PerformanceFeeSettle(ctx context.Context, req *admin.ToogoPerformanceFeeSettleReq) (res *admin.ToogoPerformanceFeeSettleRes, err error) {
+	data, err := service.ToogoWallet().SettlePerformanceFee(ctx, &req.PerformanceFeeInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoPerformanceFeeSettleRes{PerformanceFeeReport: data}
+	return
+}
+
 // ========== 管理员操作 ==========
 
 // AdminRechargePower 管理员手动充值算力
@@ -0,0 +1,74 @@
+// Package admin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 白标租户控制器
+package admin
+
+import (
+	"context"
+	"hotgo/api/admin"
+	"hotgo/internal/service"
+)
+
+var ToogoTenant = cToogoTenant{}
+
+type cToogoTenant struct{}
+
+// List 租户列表
+func (c *cToogoTenant) List(ctx context.Context, req *admin.ToogoTenantListReq) (res *admin.ToogoTenantListRes, err error) {
+	list, totalCount, err := service.ToogoTenant().List(ctx, &req.TenantListInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoTenantListRes{List: list, TotalCount: totalCount}
+	return
+}
+
+// View 租户详情
+func (c *cToogoTenant) View(ctx context.Context, req *admin.ToogoTenantViewReq) (res *admin.ToogoTenantViewRes, err error) {
+	data, err := service.ToogoTenant().View(ctx, &req.TenantViewInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoTenantViewRes{TenantModel: data}
+	return
+}
+
+// Edit 新增/编辑租户
+func (c *cToogoTenant) Edit(ctx context.Context, req *admin.ToogoTenantEditReq) (res *admin.ToogoTenantEditRes, err error) {
+	err = service.ToogoTenant().Edit(ctx, &req.TenantEditInp)
+	return
+}
+
+// Status 更新租户状态
+func (c *cToogoTenant) Status(ctx context.Context, req *admin.ToogoTenantStatusReq) (res *admin.ToogoTenantStatusRes, err error) {
+	err = service.ToogoTenant().Status(ctx, &req.TenantStatusInp)
+	return
+}
+
+// CopyCatalog 复制平台套餐/官方策略模板到租户
+func (c *cToogoTenant) CopyCatalog(ctx context.Context, req *admin.ToogoTenantCopyCatalogReq) (res *admin.ToogoTenantCopyCatalogRes, err error) {
+	data, err := service.ToogoTenant().CopyCatalog(ctx, &req.TenantCopyCatalogInp)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoTenantCopyCatalogRes{TenantCopyCatalogModel: data}
+	return
+}
+
+// Branding 当前租户品牌配置
+func (c *cToogoTenant) Branding(ctx context.Context, req *admin.ToogoTenantBrandingReq) (res *admin.ToogoTenantBrandingRes, err error) {
+	data, err := service.ToogoTenant().Branding(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res = &admin.ToogoTenantBrandingRes{TenantModel: data}
+	return
+}
+
+// BrandingEdit 修改当前租户品牌配置与收款凭证
+func (c *cToogoTenant) BrandingEdit(ctx context.Context, req *admin.ToogoTenantBrandingEditReq) (res *admin.ToogoTenantBrandingEditRes, err error) {
+	err = service.ToogoTenant().BrandingEdit(ctx, &req.TenantBrandingEditInp)
+	return
+}
@@ -50,6 +50,7 @@ func (c *cSite) Config(ctx context.Context, _ *common.SiteConfigReq) (res *commo
 		Domain:  c.getDomain(ctx, request),
 		Mode:    gmode.Mode(),
 	}
+	res.Branding, err = service.ToogoTenant().SiteBranding(ctx)
 	return
 }
 
@@ -255,6 +256,28 @@ func (c *cSite) MobileLogin(ctx context.Context, req *common.MobileLoginReq) (re
 	return
 }
 
+// TwoFactorLoginSetup 登录时绑定验证器
+func (c *cSite) TwoFactorLoginSetup(ctx context.Context, req *common.TwoFactorLoginSetupReq) (res *common.TwoFactorLoginSetupRes, err error) {
+	data, err := service.AdminSite().TwoFactorLoginSetup(ctx, &req.TwoFactorLoginSetupInp)
+	if err != nil {
+		return
+	}
+
+	res = &common.TwoFactorLoginSetupRes{TwoFactorSetupModel: data}
+	return
+}
+
+// TwoFactorLogin 双因素认证登录
+func (c *cSite) TwoFactorLogin(ctx context.Context, req *common.TwoFactorLoginReq) (res *common.TwoFactorLoginRes, err error) {
+	model, err := service.AdminSite().TwoFactorLogin(ctx, &req.TwoFactorLoginInp)
+	if err != nil {
+		return
+	}
+
+	err = gconv.Scan(model, &res)
+	return
+}
+
 // Logout 注销登录
 func (c *cSite) Logout(ctx context.Context, _ *common.LoginLogoutReq) (res *common.LoginLogoutRes, err error) {
 	err = token.Logout(ghttp.RequestFromCtx(ctx))
@@ -0,0 +1,67 @@
+// Package sys
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package sys
+
+import (
+	"context"
+	"hotgo/api/admin/queuedeadletter"
+	"hotgo/internal/service"
+)
+
+var (
+	QueueDeadLetter = cQueueDeadLetter{}
+)
+
+type cQueueDeadLetter struct{}
+
+// List 查看列表
+func (c *cQueueDeadLetter) List(ctx context.Context, req *queuedeadletter.ListReq) (res *queuedeadletter.ListRes, err error) {
+	list, totalCount, err := service.SysQueueDeadLetter().List(ctx, &req.QueueDeadLetterListInp)
+	if err != nil {
+		return
+	}
+
+	res = new(queuedeadletter.ListRes)
+	res.List = list
+	res.PageRes.Pack(req, totalCount)
+	return
+}
+
+// View 获取指定信息
+func (c *cQueueDeadLetter) View(ctx context.Context, req *queuedeadletter.ViewReq) (res *queuedeadletter.ViewRes, err error) {
+	data, err := service.SysQueueDeadLetter().View(ctx, &req.QueueDeadLetterViewInp)
+	if err != nil {
+		return
+	}
+
+	res = new(queuedeadletter.ViewRes)
+	res.QueueDeadLetterViewModel = data
+	return
+}
+
+// Replay 重放
+func (c *cQueueDeadLetter) Replay(ctx context.Context, req *queuedeadletter.ReplayReq) (res *queuedeadletter.ReplayRes, err error) {
+	data, err := service.SysQueueDeadLetter().Replay(ctx, &req.QueueDeadLetterReplayInp)
+	if err != nil {
+		return
+	}
+
+	res = new(queuedeadletter.ReplayRes)
+	res.QueueDeadLetterReplayModel = data
+	return
+}
+
+// Ignore 忽略
+func (c *cQueueDeadLetter) Ignore(ctx context.Context, req *queuedeadletter.IgnoreReq) (res *queuedeadletter.IgnoreRes, err error) {
+	err = service.SysQueueDeadLetter().Ignore(ctx, &req.QueueDeadLetterIgnoreInp)
+	return
+}
+
+// Delete 删除
+func (c *cQueueDeadLetter) Delete(ctx context.Context, req *queuedeadletter.DeleteReq) (res *queuedeadletter.DeleteRes, err error) {
+	err = service.SysQueueDeadLetter().Delete(ctx, &req.QueueDeadLetterDeleteInp)
+	return
+}
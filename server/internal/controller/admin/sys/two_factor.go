@@ -0,0 +1,80 @@
+// Package sys
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package sys
+
+import (
+	"context"
+	"hotgo/api/admin/twofactor"
+	"hotgo/internal/library/contexts"
+	"hotgo/internal/service"
+)
+
+var (
+	TwoFactor = cTwoFactor{}
+)
+
+type cTwoFactor struct{}
+
+// Status 获取双因素认证状态
+func (c *cTwoFactor) Status(ctx context.Context, _ *twofactor.StatusReq) (res *twofactor.StatusRes, err error) {
+	data, err := service.SysTwoFactor().Status(ctx, contexts.GetUserId(ctx))
+	if err != nil {
+		return
+	}
+
+	res = &twofactor.StatusRes{TwoFactorStatusModel: data}
+	return
+}
+
+// Setup 生成待绑定的验证器密钥
+func (c *cTwoFactor) Setup(ctx context.Context, _ *twofactor.SetupReq) (res *twofactor.SetupRes, err error) {
+	data, err := service.SysTwoFactor().Setup(ctx, contexts.GetUserId(ctx))
+	if err != nil {
+		return
+	}
+
+	res = &twofactor.SetupRes{TwoFactorSetupModel: data}
+	return
+}
+
+// Enable 启用双因素认证
+func (c *cTwoFactor) Enable(ctx context.Context, req *twofactor.EnableReq) (res *twofactor.EnableRes, err error) {
+	data, err := service.SysTwoFactor().Enable(ctx, contexts.GetUserId(ctx), req.Code)
+	if err != nil {
+		return
+	}
+
+	res = &twofactor.EnableRes{TwoFactorRecoveryModel: data}
+	return
+}
+
+// Disable 停用双因素认证
+func (c *cTwoFactor) Disable(ctx context.Context, req *twofactor.DisableReq) (res *twofactor.DisableRes, err error) {
+	err = service.SysTwoFactor().Disable(ctx, contexts.GetUserId(ctx), req.Code)
+	return
+}
+
+// RecoveryCodes 重新生成恢复码
+func (c *cTwoFactor) RecoveryCodes(ctx context.Context, req *twofactor.RecoveryCodesReq) (res *twofactor.RecoveryCodesRes, err error) {
+	data, err := service.SysTwoFactor().RegenerateRecovery(ctx, contexts.GetUserId(ctx), req.Code)
+	if err != nil {
+		return
+	}
+
+	res = &twofactor.RecoveryCodesRes{TwoFactorRecoveryModel: data}
+	return
+}
+
+// Verify 敏感操作二次验证
+func (c *cTwoFactor) Verify(ctx context.Context, req *twofactor.VerifyReq) (res *twofactor.VerifyRes, err error) {
+	data, err := service.SysTwoFactor().StepUp(ctx, contexts.GetUserId(ctx), req.Code)
+	if err != nil {
+		return
+	}
+
+	res = &twofactor.VerifyRes{TwoFactorVerifyModel: data}
+	return
+}
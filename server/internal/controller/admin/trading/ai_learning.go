@@ -0,0 +1,31 @@
+package trading
+
+import (
+	"context"
+
+	"hotgo/api/admin/trading"
+	"hotgo/internal/service"
+)
+
+// AiLearning 信号权重自适应学习控制器
+var AiLearning = cAiLearning{}
+
+type cAiLearning struct{}
+
+// Report 学习报告（当前权重 vs 学习权重）
+func (c *cAiLearning) Report(ctx context.Context, req *trading.AiLearningReportReq) (res *trading.AiLearningReportRes, err error) {
+	data, err := service.ToogoAiLearning().Report(ctx, &req.AiLearningReportInp)
+	if err != nil {
+		return nil, err
+	}
+	return &trading.AiLearningReportRes{AiLearningReportModel: data}, nil
+}
+
+// Run 立即执行一轮学习
+func (c *cAiLearning) Run(ctx context.Context, req *trading.AiLearningRunReq) (res *trading.AiLearningRunRes, err error) {
+	data, err := service.ToogoAiLearning().Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &trading.AiLearningRunRes{AiLearningRunModel: data}, nil
+}
@@ -12,7 +12,6 @@ import (
 	"hotgo/internal/model/entity"
 
 	"github.com/gogf/gf/v2/errors/gerror"
-	"github.com/gogf/gf/v2/frame/g"
 )
 
 // AlertController 预警日志控制器
@@ -86,7 +85,7 @@ func (c *cAlertController) RiskPreferenceLogList(ctx context.Context, req *admin
 func (c *cAlertController) DirectionLogList(ctx context.Context, req *admin.DirectionLogListReq) (res *admin.DirectionLogListRes, err error) {
 	res = &admin.DirectionLogListRes{}
 
-	model := g.DB().Model("hg_trading_signal_log").Ctx(ctx)
+	model := dao.TradingSignalLog.Ctx(ctx)
 
 	// 只查询有价值的信号（long/short）
 	model = model.WhereIn("signal_type", []string{"long", "short", "LONG", "SHORT"})
@@ -67,3 +67,46 @@ func (c *cProxyConfig) Toggle(ctx context.Context, req *trading.ProxyConfigToggl
 	return
 }
 
+// PoolList 代理池列表
+func (c *cProxyConfig) PoolList(ctx context.Context, req *trading.ProxyPoolListReq) (res *trading.ProxyPoolListRes, err error) {
+	list, err := tradingLogic.ProxyConfig.PoolList(ctx, &req.TradingProxyPoolListInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &trading.ProxyPoolListRes{List: list}
+	return
+}
+
+// PoolEdit 编辑代理池成员
+func (c *cProxyConfig) PoolEdit(ctx context.Context, req *trading.ProxyPoolEditReq) (res *trading.ProxyPoolEditRes, err error) {
+	err = tradingLogic.ProxyConfig.PoolEdit(ctx, &req.TradingProxyPoolEditInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &trading.ProxyPoolEditRes{}
+	return
+}
+
+// PoolDelete 删除代理池成员
+func (c *cProxyConfig) PoolDelete(ctx context.Context, req *trading.ProxyPoolDeleteReq) (res *trading.ProxyPoolDeleteRes, err error) {
+	err = tradingLogic.ProxyConfig.PoolDelete(ctx, &req.TradingProxyPoolDeleteInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &trading.ProxyPoolDeleteRes{}
+	return
+}
+
+// PoolCheck 代理池健康检查
+func (c *cProxyConfig) PoolCheck(ctx context.Context, req *trading.ProxyPoolCheckReq) (res *trading.ProxyPoolCheckRes, err error) {
+	list, err := tradingLogic.ProxyConfig.PoolCheck(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &trading.ProxyPoolCheckRes{List: list}
+	return
+}
@@ -123,6 +123,17 @@ func (c *cRobot) Stop(ctx context.Context, req *trading.RobotStopReq) (res *trad
 	return
 }
 
+// StopAll 停止全部机器人
+func (c *cRobot) StopAll(ctx context.Context, req *trading.RobotStopAllReq) (res *trading.RobotStopAllRes, err error) {
+	out, err := tradingLogic.Robot.StopAll(ctx, &req.TradingRobotStopAllInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &trading.RobotStopAllRes{TradingRobotStopAllModel: out}
+	return
+}
+
 // Stats 获取运行统计
 func (c *cRobot) Stats(ctx context.Context, req *trading.RobotStatsReq) (res *trading.RobotStatsRes, err error) {
 	out, err := tradingLogic.Robot.GetStats(ctx, &req.TradingRobotStatsInp)
@@ -294,3 +305,12 @@ func (c *cRobot) ReloadStrategy(ctx context.Context, req *trading.RobotReloadStr
 	}
 	return &trading.RobotReloadStrategyRes{}, nil
 }
+
+// GridStatus 网格运行状态
+func (c *cRobot) GridStatus(ctx context.Context, req *trading.RobotGridStatusReq) (res *trading.RobotGridStatusRes, err error) {
+	data, err := tradingLogic.Robot.GridStatus(ctx, &req.TradingRobotGridStatusInp)
+	if err != nil {
+		return nil, err
+	}
+	return &trading.RobotGridStatusRes{TradingRobotGridStatusModel: data}, nil
+}
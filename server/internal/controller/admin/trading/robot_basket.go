@@ -0,0 +1,79 @@
+// Package trading
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+
+package trading
+
+import (
+	"context"
+	"hotgo/api/admin/trading"
+	tradingLogic "hotgo/internal/logic/trading"
+)
+
+var RobotBasket = cRobotBasket{}
+
+type cRobotBasket struct{}
+
+// List 组合机器人列表
+func (c *cRobotBasket) List(ctx context.Context, req *trading.RobotBasketListReq) (res *trading.RobotBasketListRes, err error) {
+	list, totalCount, err := tradingLogic.RobotBasket.List(ctx, &req.TradingRobotBasketListInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &trading.RobotBasketListRes{
+		List:       list,
+		TotalCount: totalCount,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+	}
+	return
+}
+
+// Create 创建组合机器人
+func (c *cRobotBasket) Create(ctx context.Context, req *trading.RobotBasketCreateReq) (res *trading.RobotBasketCreateRes, err error) {
+	id, err := tradingLogic.RobotBasket.Create(ctx, &req.TradingRobotBasketCreateInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &trading.RobotBasketCreateRes{Id: id}
+	return
+}
+
+// View 组合机器人详情
+func (c *cRobotBasket) View(ctx context.Context, req *trading.RobotBasketViewReq) (res *trading.RobotBasketViewRes, err error) {
+	data, err := tradingLogic.RobotBasket.View(ctx, &req.TradingRobotBasketIdInp)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &trading.RobotBasketViewRes{TradingRobotBasketViewModel: data}
+	return
+}
+
+// Start 启动组合机器人
+func (c *cRobotBasket) Start(ctx context.Context, req *trading.RobotBasketStartReq) (res *trading.RobotBasketStartRes, err error) {
+	err = tradingLogic.RobotBasket.Start(ctx, &req.TradingRobotBasketIdInp)
+	return
+}
+
+// Pause 暂停组合机器人
+func (c *cRobotBasket) Pause(ctx context.Context, req *trading.RobotBasketPauseReq) (res *trading.RobotBasketPauseRes, err error) {
+	err = tradingLogic.RobotBasket.Pause(ctx, &req.TradingRobotBasketIdInp)
+	return
+}
+
+// Stop 停用组合机器人
+func (c *cRobotBasket) Stop(ctx context.Context, req *trading.RobotBasketStopReq) (res *trading.RobotBasketStopRes, err error) {
+	err = tradingLogic.RobotBasket.Stop(ctx, &req.TradingRobotBasketIdInp)
+	return
+}
+
+// Delete 删除组合机器人
+func (c *cRobotBasket) Delete(ctx context.Context, req *trading.RobotBasketDeleteReq) (res *trading.RobotBasketDeleteRes, err error) {
+	err = tradingLogic.RobotBasket.Delete(ctx, &req.TradingRobotBasketIdInp)
+	return
+}
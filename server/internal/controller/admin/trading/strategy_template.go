@@ -24,7 +24,7 @@ type cStrategyTemplate struct{}
 
 // List 策略模板列表
 func (c *cStrategyTemplate) List(ctx context.Context, req *trading.StrategyTemplateListReq) (res *trading.StrategyTemplateListRes, err error) {
-	m := g.DB().Model("hg_trading_strategy_template").Safe()
+	m := dao.TradingStrategyTemplate.Ctx(ctx)
 
 	if req.GroupId > 0 {
 		// 组被禁用时：用户侧不展示（与“官方标识”无关，只看 is_active）
@@ -32,7 +32,7 @@ func (c *cStrategyTemplate) List(ctx context.Context, req *trading.StrategyTempl
 		roleKey := contexts.GetRoleKey(ctx)
 		if roleKey != consts.SuperRoleKey {
 			var group *entity.TradingStrategyGroup
-			_ = g.DB().Model("hg_trading_strategy_group").Ctx(ctx).
+			_ = dao.TradingStrategyGroup.Ctx(ctx).
 				Where("id", req.GroupId).
 				Scan(&group)
 			if group != nil && group.Id > 0 && group.IsActive == 0 {
@@ -136,7 +136,20 @@ func (c *cStrategyTemplate) Create(ctx context.Context, req *trading.StrategyTem
 		"updated_at":                 gtime.Now(),
 	}
 
-	_, err = g.DB().Model("hg_trading_strategy_template").Insert(data)
+	// 只能在本租户可见的策略组下创建策略，不归属策略组的策略仅平台可创建
+	if req.GroupId > 0 {
+		groupCount, err := dao.TradingStrategyGroup.Ctx(ctx).Where("id", req.GroupId).Count()
+		if err != nil {
+			return nil, err
+		}
+		if groupCount == 0 {
+			return nil, gerror.New("策略组不存在")
+		}
+	} else if err = toogo.RequirePlatform(ctx); err != nil {
+		return nil, err
+	}
+
+	_, err = dao.TradingStrategyTemplate.Ctx(ctx).Insert(data)
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +174,7 @@ func (c *cStrategyTemplate) Update(ctx context.Context, req *trading.StrategyTem
 		
 		if template.GroupId > 0 {
 			// 检查该策略组是否有机器人绑定
-			robotCount, err := g.DB().Model("hg_trading_robot").
+			robotCount, err := dao.TradingRobot.Ctx(ctx).
 				Where("strategy_group_id", template.GroupId).
 				WhereNull("deleted_at").
 				Count()
@@ -176,7 +189,7 @@ func (c *cStrategyTemplate) Update(ctx context.Context, req *trading.StrategyTem
 					Status   int    `json:"status"`
 				}
 				var robots []RobotInfo
-				err = g.DB().Model("hg_trading_robot").
+				err = dao.TradingRobot.Ctx(ctx).
 					Fields("id", "robot_name", "status").
 					Where("strategy_group_id", template.GroupId).
 					WhereNull("deleted_at").
@@ -275,7 +288,7 @@ func (c *cStrategyTemplate) Update(ctx context.Context, req *trading.StrategyTem
 		"updated_at":                 gtime.Now(),
 	}
 
-	_, err = g.DB().Model("hg_trading_strategy_template").Where("id", req.Id).Update(data)
+	_, err = dao.TradingStrategyTemplate.Ctx(ctx).Where("id", req.Id).Update(data)
 	if err != nil {
 		return nil, err
 	}
@@ -294,7 +307,7 @@ func (c *cStrategyTemplate) Update(ctx context.Context, req *trading.StrategyTem
 
 // Delete 删除策略模板
 func (c *cStrategyTemplate) Delete(ctx context.Context, req *trading.StrategyTemplateDeleteReq) (res *trading.StrategyTemplateDeleteRes, err error) {
-	_, err = g.DB().Model("hg_trading_strategy_template").Where("id", req.Id).Delete()
+	_, err = dao.TradingStrategyTemplate.Ctx(ctx).Where("id", req.Id).Delete()
 	if err != nil {
 		return nil, err
 	}
@@ -317,7 +330,7 @@ func (c *cStrategyTemplate) Apply(ctx context.Context, req *trading.StrategyTemp
 	// 获取策略组信息（用于获取交易平台、交易对、订单类型、保证金模式）
 	var group *entity.TradingStrategyGroup
 	if strategy.GroupId > 0 {
-		_ = g.DB().Model("hg_trading_strategy_group").Where("id", strategy.GroupId).Scan(&group)
+		_ = dao.TradingStrategyGroup.Ctx(ctx).Where("id", strategy.GroupId).Scan(&group)
 	}
 
 	// 解析 config_json 获取完整手动配置
@@ -431,7 +444,7 @@ func (c *cStrategyTemplate) Apply(ctx context.Context, req *trading.StrategyTemp
 	data["current_strategy"] = string(strategyBytes)
 
 	// 执行更新
-	_, err = g.DB().Model("hg_trading_robot").Where("id", req.RobotId).Update(data)
+	_, err = dao.TradingRobot.Ctx(ctx).Where("id", req.RobotId).Update(data)
 	if err != nil {
 		return nil, err
 	}
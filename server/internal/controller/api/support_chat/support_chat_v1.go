@@ -4,6 +4,7 @@ import (
 	"context"
 
 	v1 "hotgo/api/api/support_chat/v1"
+	"hotgo/internal/library/contexts"
 	"hotgo/internal/service"
 )
 
@@ -39,3 +40,28 @@ func (c *ControllerV1) MessageList(ctx context.Context, req *v1.MessageListReq)
 }
 
 
+
+func (c *ControllerV1) SendAttachment(ctx context.Context, req *v1.SendAttachmentReq) (res *v1.SendAttachmentRes, err error) {
+	msg, err := service.SupportChat().SendUserAttachment(ctx, req.SessionId, req.File)
+	if err != nil {
+		return nil, err
+	}
+	res = new(v1.SendAttachmentRes)
+	res.SupportMessage = msg
+	return
+}
+
+func (c *ControllerV1) Read(ctx context.Context, req *v1.ReadReq) (res *v1.ReadRes, err error) {
+	err = service.SupportChat().MarkRead(ctx, contexts.GetUserId(ctx), req.SessionId)
+	return
+}
+
+func (c *ControllerV1) Rate(ctx context.Context, req *v1.RateReq) (res *v1.RateRes, err error) {
+	session, err := service.SupportChat().RateSession(ctx, &req.SupportRateInp)
+	if err != nil {
+		return nil, err
+	}
+	res = new(v1.RateRes)
+	res.SupportSession = session
+	return
+}
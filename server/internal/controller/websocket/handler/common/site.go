@@ -6,6 +6,7 @@
 package common
 
 import (
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/util/gconv"
 	"hotgo/internal/websocket"
 )
@@ -24,6 +25,22 @@ func (c *cSite) Join(client *websocket.Client, req *websocket.WRequest) {
 	}
 
 	websocket.SendSuccess(client, req.Event, client.Tags.Slice())
+
+	// 断线重连时携带最后收到的频道序号，补发期间缺失的频道消息
+	if lastSeq, ok := req.Data["lastSeq"]; ok {
+		websocket.Resume(client, name, gconv.Int64(lastSeq))
+	}
+}
+
+// Resume 按最后收到的频道序号补发缺失的消息
+// 客户端检测到频道序号不连续时发送，只能补发已加入的频道
+func (c *cSite) Resume(client *websocket.Client, req *websocket.WRequest) {
+	name := gconv.String(req.Data["id"])
+	if !client.Tags.Contains(name) {
+		websocket.SendError(client, req.Event, gerror.Newf("未加入频道：%v", name))
+		return
+	}
+	websocket.Resume(client, name, gconv.Int64(req.Data["lastSeq"]))
 }
 
 func (c *cSite) Quit(client *websocket.Client, req *websocket.WRequest) {
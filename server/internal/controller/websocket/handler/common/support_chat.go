@@ -0,0 +1,59 @@
+package common
+
+import (
+	"errors"
+
+	"github.com/gogf/gf/v2/util/gconv"
+	"hotgo/internal/service"
+	"hotgo/internal/websocket"
+)
+
+var (
+	SupportChat = cSupportChat{}
+)
+
+// cSupportChat 客服聊天的实时信令：正在输入、已读回执
+// 推送给对方的事件与 HTTP 发送消息共用 support/* 事件名，见 consts.SupportWsEvent*
+type cSupportChat struct{}
+
+// Typing 正在输入
+// req.Data:
+// - sessionId: 会话ID
+// - typing: true开始输入 false停止输入
+func (c *cSupportChat) Typing(client *websocket.Client, req *websocket.WRequest) {
+	userId, sessionId, err := supportChatTarget(client, req)
+	if err != nil {
+		websocket.SendError(client, req.Event, err)
+		return
+	}
+	if err = service.SupportChat().Typing(client.Context(), userId, sessionId, gconv.Bool(req.Data["typing"])); err != nil {
+		websocket.SendError(client, req.Event, err)
+	}
+}
+
+// Read 标记已读，回执推送给对方
+// req.Data:
+// - sessionId: 会话ID
+func (c *cSupportChat) Read(client *websocket.Client, req *websocket.WRequest) {
+	userId, sessionId, err := supportChatTarget(client, req)
+	if err != nil {
+		websocket.SendError(client, req.Event, err)
+		return
+	}
+	if err = service.SupportChat().MarkRead(client.Context(), userId, sessionId); err != nil {
+		websocket.SendError(client, req.Event, err)
+		return
+	}
+	websocket.SendSuccess(client, req.Event)
+}
+
+func supportChatTarget(client *websocket.Client, req *websocket.WRequest) (userId, sessionId int64, err error) {
+	if client.User == nil || client.User.Id <= 0 {
+		return 0, 0, errors.New("请先登录")
+	}
+	sessionId = gconv.Int64(req.Data["sessionId"])
+	if sessionId <= 0 {
+		return 0, 0, errors.New("sessionId 不能为空")
+	}
+	return client.User.Id, sessionId, nil
+}
@@ -0,0 +1,272 @@
+// Package toogo WebSocket handlers for toogo realtime data.
+package toogo
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gredis"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gctx"
+
+	"hotgo/api/admin/trading"
+	"hotgo/internal/consts"
+	"hotgo/internal/library/hgrds/pubsub"
+	toogoLogic "hotgo/internal/logic/toogo"
+	tradingLogic "hotgo/internal/logic/trading"
+	"hotgo/internal/service"
+	"hotgo/utility/simple"
+)
+
+// 说明：
+// - 集群部署时机器人引擎不一定运行在客户端连接的节点上，本节点查询只能拿到交易所/数据库的降级数据
+// - 没有引擎的节点在推送时登记关注（redis key，定期续期），引擎所在节点每秒为被关注的机器人生成快照并广播
+// - 关注节点收到快照后直接用于推送；快照过期或从未收到时，仍回退到本节点查询
+
+const (
+	relayKindRealtime  = "realtime"  // 批量实时分析
+	relayKindPositions = "positions" // 持仓快照
+	relayKindOrders    = "orders"    // 挂单快照
+
+	relayWatchKeyPrefix = "toogo:ws:relay:watch:"
+	relayWatchTTL       = 5 // 关注有效期(秒)，推送循环会持续续期
+	relayFreshness      = 3 * time.Second
+	relayInterval       = time.Second
+)
+
+var relayKinds = []string{relayKindRealtime, relayKindPositions, relayKindOrders}
+
+// relaySnapshot 跨节点转发的机器人快照
+type relaySnapshot struct {
+	Node    string          `json:"node"`
+	Kind    string          `json:"kind"`
+	RobotId int64           `json:"robotId"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error,omitempty"`
+	At      int64           `json:"at"` // 生成时间(毫秒)
+}
+
+var (
+	relayOnce    sync.Once
+	relayCache   sync.Map // key: kind:robotId, value: *relaySnapshot
+	relayWatched sync.Map // key: kind:robotId, value: time.Time 上次续期时间
+)
+
+func relayKey(kind string, robotId int64) string {
+	return kind + ":" + strconv.FormatInt(robotId, 10)
+}
+
+// StartClusterRelay 启动机器人快照跨节点转发，未开启集群部署时不做任何处理
+func StartClusterRelay(ctx context.Context) {
+	if !simple.IsCluster(ctx) {
+		return
+	}
+	relayOnce.Do(func() {
+		if err := pubsub.Subscribe(consts.ClusterSyncRobotSnapshot, relaySync); err != nil {
+			g.Log().Warningf(ctx, "[WS][Relay] subscribe failed, cluster relay disabled: %v", err)
+			return
+		}
+		go relayProduceLoop()
+	})
+}
+
+// relaySync 接收其他节点广播的快照
+func relaySync(ctx context.Context, message *gredis.Message) {
+	var snap *relaySnapshot
+	if err := json.Unmarshal([]byte(message.Payload), &snap); err != nil || snap == nil {
+		g.Log().Warningf(ctx, "[WS][Relay] unmarshal snapshot failed: %v", err)
+		return
+	}
+	if snap.Node == pubsub.NodeId() {
+		return
+	}
+	key := relayKey(snap.Kind, snap.RobotId)
+	if v, ok := relayCache.Load(key); ok && v.(*relaySnapshot).At > snap.At {
+		return
+	}
+	relayCache.Store(key, snap)
+}
+
+// relayFetch 获取其他节点转发的快照
+// 本节点有该机器人的引擎、未开启集群或快照不可用时 ok=false，由调用方在本节点查询
+func relayFetch(ctx context.Context, kind string, robotId int64) (data json.RawMessage, ok bool, err error) {
+	if !simple.IsCluster(ctx) || toogoLogic.GetRobotTaskManager().GetEngine(robotId) != nil {
+		return
+	}
+	relayWatch(ctx, kind, robotId)
+
+	v, found := relayCache.Load(relayKey(kind, robotId))
+	if !found {
+		return
+	}
+	snap := v.(*relaySnapshot)
+	if time.Since(time.UnixMilli(snap.At)) > relayFreshness {
+		return
+	}
+	if snap.Error != "" {
+		err = gerror.New(snap.Error)
+	}
+	if len(snap.Data) > 0 && string(snap.Data) != "null" {
+		data = snap.Data
+	}
+	return data, true, err
+}
+
+// relayWatch 登记关注，半个有效期内只续期一次
+func relayWatch(ctx context.Context, kind string, robotId int64) {
+	key := relayKey(kind, robotId)
+	if v, ok := relayWatched.Load(key); ok && time.Since(v.(time.Time)) < relayWatchTTL*time.Second/2 {
+		return
+	}
+	relayWatched.Store(key, time.Now())
+	if err := g.Redis().SetEX(ctx, relayWatchKeyPrefix+key, 1, relayWatchTTL); err != nil {
+		g.Log().Debugf(ctx, "[WS][Relay] watch %s failed: %v", key, err)
+	}
+}
+
+// relayProduceLoop 为本节点引擎中被其他节点关注的机器人生成快照
+func relayProduceLoop() {
+	ctx := gctx.New()
+	tk := time.NewTicker(relayInterval)
+	defer tk.Stop()
+
+	for range tk.C {
+		robotIds := toogoLogic.GetRobotTaskManager().GetEngineRobotIds()
+		if len(robotIds) == 0 {
+			continue
+		}
+
+		keys := make([]string, 0, len(robotIds)*len(relayKinds))
+		for _, robotId := range robotIds {
+			for _, kind := range relayKinds {
+				keys = append(keys, relayWatchKeyPrefix+relayKey(kind, robotId))
+			}
+		}
+		watched, err := g.Redis().MGet(ctx, keys...)
+		if err != nil {
+			g.Log().Debugf(ctx, "[WS][Relay] load watch keys failed: %v", err)
+			continue
+		}
+
+		for _, robotId := range robotIds {
+			for _, kind := range relayKinds {
+				if v, ok := watched[relayWatchKeyPrefix+relayKey(kind, robotId)]; !ok || v.IsEmpty() {
+					continue
+				}
+				relayPublish(ctx, kind, robotId)
+			}
+		}
+	}
+}
+
+// relayPublish 生成并广播快照
+func relayPublish(ctx context.Context, kind string, robotId int64) {
+	var (
+		data any
+		err  error
+	)
+	switch kind {
+	case relayKindRealtime:
+		data, err = tradingLogic.Monitor.GetRobotAnalysis(ctx, robotId)
+	case relayKindPositions:
+		data, err = service.ToogoRobot().GetRobotPositions(ctx, robotId)
+	case relayKindOrders:
+		data, err = service.ToogoRobot().GetRobotOpenOrders(ctx, robotId)
+	default:
+		return
+	}
+
+	snap := &relaySnapshot{
+		Node:    pubsub.NodeId(),
+		Kind:    kind,
+		RobotId: robotId,
+		At:      time.Now().UnixMilli(),
+	}
+	if err != nil {
+		snap.Error = err.Error()
+	} else if snap.Data, err = json.Marshal(data); err != nil {
+		g.Log().Warningf(ctx, "[WS][Relay] marshal snapshot failed: kind=%s robotId=%d err=%v", kind, robotId, err)
+		return
+	}
+
+	payload, err := json.Marshal(snap)
+	if err != nil {
+		return
+	}
+	if _, err = pubsub.Publish(ctx, consts.ClusterSyncRobotSnapshot, string(payload)); err != nil {
+		g.Log().Debugf(ctx, "[WS][Relay] publish snapshot failed: kind=%s robotId=%d err=%v", kind, robotId, err)
+	}
+}
+
+// robotPositions 获取机器人持仓，集群部署时优先使用引擎所在节点转发的快照
+func robotPositions(ctx context.Context, robotId int64) (any, error) {
+	if data, ok, err := relayFetch(ctx, relayKindPositions, robotId); ok {
+		if data == nil {
+			return nil, err
+		}
+		return data, err
+	}
+	list, err := service.ToogoRobot().GetRobotPositions(ctx, robotId)
+	if list == nil {
+		return nil, err
+	}
+	return list, err
+}
+
+// robotOpenOrders 获取机器人挂单，集群部署时优先使用引擎所在节点转发的快照
+func robotOpenOrders(ctx context.Context, robotId int64) (any, error) {
+	if data, ok, err := relayFetch(ctx, relayKindOrders, robotId); ok {
+		if data == nil {
+			return nil, err
+		}
+		return data, err
+	}
+	list, err := service.ToogoRobot().GetRobotOpenOrders(ctx, robotId)
+	if list == nil {
+		return nil, err
+	}
+	return list, err
+}
+
+// robotBatchAnalysis 批量获取机器人实时分析，集群部署时优先使用引擎所在节点转发的快照
+func robotBatchAnalysis(ctx context.Context, robotIds string) (*trading.MonitorBatchRobotAnalysisRes, error) {
+	if !simple.IsCluster(ctx) {
+		return tradingLogic.Monitor.GetBatchRobotAnalysis(ctx, robotIds)
+	}
+
+	result := &trading.MonitorBatchRobotAnalysisRes{
+		List: make([]*trading.MonitorRobotAnalysisRes, 0),
+	}
+	for _, idStr := range strings.Split(robotIds, ",") {
+		robotId, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		var analysis *trading.MonitorRobotAnalysisRes
+		if data, ok, relayErr := relayFetch(ctx, relayKindRealtime, robotId); ok && relayErr == nil && data != nil {
+			err = json.Unmarshal(data, &analysis)
+		} else if ok && relayErr != nil {
+			err = relayErr
+		} else {
+			analysis, err = tradingLogic.Monitor.GetRobotAnalysis(ctx, robotId)
+		}
+
+		if err != nil || analysis == nil {
+			// 与 GetBatchRobotAnalysis 一致：失败的机器人返回连接错误状态
+			item := &trading.MonitorRobotAnalysisRes{RobotId: robotId, Connected: false}
+			if err != nil {
+				item.ConnectionError = err.Error()
+			}
+			result.List = append(result.List, item)
+			continue
+		}
+		result.List = append(result.List, analysis)
+	}
+	return result, nil
+}
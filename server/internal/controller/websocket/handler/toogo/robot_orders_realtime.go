@@ -12,7 +12,6 @@ import (
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/util/gconv"
 
-	"hotgo/internal/service"
 	"hotgo/internal/websocket"
 )
 
@@ -106,7 +105,7 @@ func (c *cRobotOrdersRealtime) Subscribe(client *websocket.Client, req *websocke
 					continue
 				}
 
-				list, err := service.ToogoRobot().GetRobotOpenOrders(ctx, robotId)
+				list, err := robotOpenOrders(ctx, robotId)
 				item := g.Map{
 					"robotId": robotId,
 					"list":    list,
@@ -12,7 +12,6 @@ import (
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/util/gconv"
 
-	"hotgo/internal/service"
 	"hotgo/internal/websocket"
 )
 
@@ -108,7 +107,7 @@ func (c *cRobotPositionsRealtime) Subscribe(client *websocket.Client, req *webso
 				if ctx == nil {
 					ctx = context.Background()
 				}
-				list, err := service.ToogoRobot().GetRobotPositions(ctx, robotId)
+				list, err := robotPositions(ctx, robotId)
 				item := g.Map{
 					"robotId": robotId,
 					"list":    list,
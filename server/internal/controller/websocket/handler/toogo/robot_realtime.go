@@ -10,7 +10,6 @@ import (
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/util/gconv"
 
-	tradingLogic "hotgo/internal/logic/trading"
 	"hotgo/internal/websocket"
 )
 
@@ -80,7 +79,7 @@ func (c *cRobotRealtime) Subscribe(client *websocket.Client, req *websocket.WReq
 				return
 			}
 
-			out, err := tradingLogic.Monitor.GetBatchRobotAnalysis(client.Context(), sub.robotIds)
+			out, err := robotBatchAnalysis(client.Context(), sub.robotIds)
 			if err != nil {
 				// 不中断，继续下一轮
 				g.Log().Warningf(client.Context(), "[WS][RobotRealtime] push failed: client=%s robotIds=%s err=%v", client.ID, sub.robotIds, err)
@@ -0,0 +1,37 @@
+// Package crons 客服聊天定时任务
+package crons
+
+import (
+	"context"
+
+	"hotgo/internal/library/cron"
+	"hotgo/internal/service"
+)
+
+func init() {
+	cron.Register(SupportOfflineNotifyTask)
+}
+
+// SupportOfflineNotifyTask 离线用户未读客服回复邮件送达
+var SupportOfflineNotifyTask = &cSupportOfflineNotify{name: "SupportOfflineNotify"}
+
+type cSupportOfflineNotify struct {
+	name string
+}
+
+func (c *cSupportOfflineNotify) GetName() string {
+	return c.name
+}
+
+// Execute 执行任务
+func (c *cSupportOfflineNotify) Execute(ctx context.Context, parser *cron.Parser) (err error) {
+	notified, err := service.SupportChat().NotifyOffline(ctx)
+	if err != nil {
+		parser.Logger.Warning(ctx, "[Cron] SupportOfflineNotify err:", err)
+		return
+	}
+	if notified > 0 {
+		parser.Logger.Infof(ctx, "[Cron] SupportOfflineNotify: sessions=%d", notified)
+	}
+	return
+}
@@ -8,12 +8,20 @@ import (
 	"github.com/gogf/gf/v2/frame/g"
 	"hotgo/internal/dao"
 	"hotgo/internal/library/cron"
+	"hotgo/internal/logic/toogo"
+	"hotgo/internal/logic/trading"
+	"hotgo/internal/model/input/toogoin"
+	"hotgo/internal/service"
 )
 
 func init() {
 	cron.Register(ToogoPowerSettlementTask)
 	cron.Register(ToogoInviteCodeCleanupTask)
 	cron.Register(ToogoVipLevelCheckTask)
+	cron.Register(ToogoAiLearningTask)
+	cron.Register(ToogoApiKeyPermissionCheckTask)
+	cron.Register(ToogoProxyHealthCheckTask)
+	cron.Register(ToogoCommissionReleaseTask)
 }
 
 // ToogoPowerSettlementTask 算力结算定时任务
@@ -27,11 +35,20 @@ func (c *cToogoPowerSettlement) GetName() string {
 	return c.name
 }
 
-// Execute 执行任务
+// Execute 业绩费（高水位）结算；未开启 toogo.performanceFee.enabled 时只输出试算报告
 func (c *cToogoPowerSettlement) Execute(ctx context.Context, parser *cron.Parser) (err error) {
-	parser.Logger.Debug(ctx, "[Cron] ToogoPowerSettlement: 执行算力结算")
-	// 算力结算主要在平仓时实时完成
-	// 这里可以做一些补偿性结算或统计
+	var report *toogoin.PerformanceFeeReport
+	if g.Cfg().MustGet(ctx, "toogo.performanceFee.enabled").Bool() {
+		report, err = service.ToogoWallet().SettlePerformanceFee(ctx, &toogoin.PerformanceFeeInp{})
+	} else {
+		report, err = service.ToogoWallet().PerformanceFeeReport(ctx, &toogoin.PerformanceFeeInp{})
+	}
+	if err != nil {
+		parser.Logger.Warning(ctx, "[Cron] ToogoPowerSettlement: 业绩费结算失败:", err)
+		return err
+	}
+	parser.Logger.Infof(ctx, "[Cron] ToogoPowerSettlement: dryRun=%v, items=%d, charged=%d, chargeable=%.4f, consumePower=%.4f, arrears=%.4f, commission=%.4f",
+		report.DryRun, len(report.Items), report.ChargedCount, report.TotalChargeable, report.TotalConsumePower, report.TotalArrears, report.TotalAgentCommission)
 	return
 }
 
@@ -84,65 +101,108 @@ func (c *cToogoVipLevelCheck) GetName() string {
 	return c.name
 }
 
-// Execute 检查用户VIP等级是否可升级
+// Execute 按实时指标重新评估所有用户VIP等级：达标立即升级，不达标经过宽限期后降级
 func (c *cToogoVipLevelCheck) Execute(ctx context.Context, parser *cron.Parser) (err error) {
-	parser.Logger.Debug(ctx, "[Cron] ToogoVipLevelCheck: 检查VIP等级")
-	
-	// 获取所有VIP等级配置
-	var levels []*struct {
-		Level                int     `json:"level"`
-		InviteCount          int     `json:"inviteCount"`
-		PersonalPowerConsume float64 `json:"personalPowerConsume"`
-		TeamPowerConsume     float64 `json:"teamPowerConsume"`
-	}
-	
-	err = dao.ToogoVipLevel.Ctx(ctx).OrderAsc("level").Scan(&levels)
-	if err != nil || len(levels) == 0 {
-		return
+	res, err := service.ToogoUser().EvaluateVipLevels(ctx, &toogoin.VipEvaluateInp{})
+	if err != nil {
+		parser.Logger.Warning(ctx, "[Cron] ToogoVipLevelCheck: 评估VIP等级失败:", err)
+		return err
 	}
+	parser.Logger.Debugf(ctx, "[Cron] ToogoVipLevelCheck: evaluated=%d, upgraded=%d, downgraded=%d, pending=%d",
+		res.Evaluated, res.Upgraded, res.Downgraded, res.Pending)
+	return
+}
+
+// ToogoAiLearningTask 信号权重自适应学习
+var ToogoAiLearningTask = &cToogoAiLearning{name: "ToogoAiLearning"}
+
+type cToogoAiLearning struct {
+	name string
+}
 
-	// 查询所有用户的统计数据
-	type UserStat struct {
-		MemberId             int64   `json:"memberId"`
-		VipLevel             int     `json:"vipLevel"`
-		InviteCount          int     `json:"inviteCount"`
-		TotalPowerConsume    float64 `json:"totalPowerConsume"`
-		TeamTotalPowerConsume float64 `json:"teamTotalPowerConsume"`
+func (c *cToogoAiLearning) GetName() string {
+	return c.name
+}
+
+// Execute 统计新平仓订单并重新计算学习权重
+func (c *cToogoAiLearning) Execute(ctx context.Context, parser *cron.Parser) (err error) {
+	res, err := service.ToogoAiLearning().Run(ctx)
+	if err != nil {
+		parser.Logger.Warning(ctx, "[Cron] ToogoAiLearning: 学习失败:", err)
+		return err
 	}
-	
-	var users []*UserStat
-	err = dao.ToogoUser.Ctx(ctx).
-		Fields("member_id, vip_level, invite_count, total_power_consume, team_total_power_consume").
-		Scan(&users)
+	parser.Logger.Debugf(ctx, "[Cron] ToogoAiLearning: mode=%s, learnedOrders=%d, symbols=%v", res.Mode, res.LearnedOrders, res.Symbols)
+	return
+}
+
+// ToogoApiKeyPermissionCheckTask API Key权限复检
+var ToogoApiKeyPermissionCheckTask = &cToogoApiKeyPermissionCheck{name: "ToogoApiKeyPermissionCheck"}
+
+type cToogoApiKeyPermissionCheck struct {
+	name string
+}
+
+func (c *cToogoApiKeyPermissionCheck) GetName() string {
+	return c.name
+}
+
+// Execute 复检启用中的API配置权限，权限变化时通知用户
+func (c *cToogoApiKeyPermissionCheck) Execute(ctx context.Context, parser *cron.Parser) (err error) {
+	checked, failed, err := trading.ApiConfig.RecheckAllPermissions(ctx)
 	if err != nil {
-		return
+		parser.Logger.Warning(ctx, "[Cron] ToogoApiKeyPermissionCheck: 复检失败:", err)
+		return err
 	}
+	parser.Logger.Debugf(ctx, "[Cron] ToogoApiKeyPermissionCheck: checked=%d, failed=%d", checked, failed)
+	return
+}
 
-	now := time.Now()
-	for _, user := range users {
-		// 找到用户应该达到的等级
-		targetLevel := 0
-		for _, lvl := range levels {
-			if user.InviteCount >= lvl.InviteCount &&
-				user.TotalPowerConsume >= lvl.PersonalPowerConsume &&
-				user.TeamTotalPowerConsume >= lvl.TeamPowerConsume {
-				targetLevel = lvl.Level
-			}
-		}
+// ToogoProxyHealthCheckTask 代理池健康检查
+var ToogoProxyHealthCheckTask = &cToogoProxyHealthCheck{name: "ToogoProxyHealthCheck"}
+
+type cToogoProxyHealthCheck struct {
+	name string
+}
 
-		// 如果需要升级
-		if targetLevel > user.VipLevel {
-			_, _ = dao.ToogoUser.Ctx(ctx).
-				Where("member_id", user.MemberId).
-				Data(g.Map{
-					"vip_level":  targetLevel,
-					"updated_at": now,
-				}).
-				Update()
-			
-			parser.Logger.Infof(ctx, "[VIP] 用户升级: memberId=%d, %d -> %d", 
-				user.MemberId, user.VipLevel, targetLevel)
+func (c *cToogoProxyHealthCheck) GetName() string {
+	return c.name
+}
+
+// Execute 检查代理池中各代理到交易所的连通性与延迟，不可用的代理暂停分配
+func (c *cToogoProxyHealthCheck) Execute(ctx context.Context, parser *cron.Parser) (err error) {
+	results, err := toogo.CheckProxyPool(ctx)
+	if err != nil {
+		parser.Logger.Warning(ctx, "[Cron] ToogoProxyHealthCheck: 检查失败:", err)
+		return err
+	}
+	unhealthy := 0
+	for _, res := range results {
+		if !res.Healthy {
+			unhealthy++
 		}
 	}
+	parser.Logger.Debugf(ctx, "[Cron] ToogoProxyHealthCheck: total=%d, unhealthy=%d", len(results), unhealthy)
+	return
+}
+
+// ToogoCommissionReleaseTask 代理佣金解冻
+var ToogoCommissionReleaseTask = &cToogoCommissionRelease{name: "ToogoCommissionRelease"}
+
+type cToogoCommissionRelease struct {
+	name string
+}
+
+func (c *cToogoCommissionRelease) GetName() string {
+	return c.name
+}
+
+// Execute 结算期已到的冻结佣金解冻入账
+func (c *cToogoCommissionRelease) Execute(ctx context.Context, parser *cron.Parser) (err error) {
+	count, amount, err := service.ToogoCommission().ReleaseCommission(ctx)
+	if err != nil {
+		parser.Logger.Warning(ctx, "[Cron] ToogoCommissionRelease: 解冻佣金失败:", err)
+		return err
+	}
+	parser.Logger.Debugf(ctx, "[Cron] ToogoCommissionRelease: released=%d, amount=%.4f", count, amount)
 	return
 }
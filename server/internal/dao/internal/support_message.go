@@ -21,23 +21,33 @@ type SupportMessageDao struct {
 
 // SupportMessageColumns defines and stores column names for the table hg_support_message.
 type SupportMessageColumns struct {
-	Id         string
-	SessionId  string
-	SenderRole string
-	SenderId   string
-	MsgType    string
-	Content    string
-	CreatedAt  string
+	Id              string
+	SessionId       string
+	SenderRole      string
+	SenderId        string
+	MsgType         string
+	Content         string
+	AttachmentId    string
+	FileName        string
+	FileSize        string
+	ReadAt          string
+	OfflineNotified string
+	CreatedAt       string
 }
 
 var supportMessageColumns = SupportMessageColumns{
-	Id:         "id",
-	SessionId:  "session_id",
-	SenderRole: "sender_role",
-	SenderId:   "sender_id",
-	MsgType:    "msg_type",
-	Content:    "content",
-	CreatedAt:  "created_at",
+	Id:              "id",
+	SessionId:       "session_id",
+	SenderRole:      "sender_role",
+	SenderId:        "sender_id",
+	MsgType:         "msg_type",
+	Content:         "content",
+	AttachmentId:    "attachment_id",
+	FileName:        "file_name",
+	FileSize:        "file_size",
+	ReadAt:          "read_at",
+	OfflineNotified: "offline_notified",
+	CreatedAt:       "created_at",
 }
 
 func NewSupportMessageDao(handlers ...gdb.ModelHandler) *SupportMessageDao {
@@ -76,5 +86,3 @@ func (dao *SupportMessageDao) Ctx(ctx context.Context) *gdb.Model {
 func (dao *SupportMessageDao) Transaction(ctx context.Context, f func(ctx context.Context, tx gdb.TX) error) (err error) {
 	return dao.Ctx(ctx).Transaction(ctx, f)
 }
-
-
@@ -21,33 +21,49 @@ type SupportSessionDao struct {
 
 // SupportSessionColumns defines and stores column names for the table hg_support_session.
 type SupportSessionColumns struct {
-	Id         string
-	UserId     string
-	AgentId    string
-	Status     string
-	Subject    string
-	LastMsg    string
-	LastMsgAt  string
-	UnreadUser string
-	UnreadAgent string
-	CreatedAt  string
-	UpdatedAt  string
-	ClosedAt   string
+	Id             string
+	UserId         string
+	AgentId        string
+	Status         string
+	Subject        string
+	LastMsg        string
+	LastMsgAt      string
+	UnreadUser     string
+	UnreadAgent    string
+	Rating         string
+	RatingRemark   string
+	RatedAt        string
+	RobotId        string
+	OrderId        string
+	EscalateRemark string
+	EscalatedBy    string
+	EscalatedAt    string
+	CreatedAt      string
+	UpdatedAt      string
+	ClosedAt       string
 }
 
 var supportSessionColumns = SupportSessionColumns{
-	Id:          "id",
-	UserId:      "user_id",
-	AgentId:     "agent_id",
-	Status:      "status",
-	Subject:     "subject",
-	LastMsg:     "last_msg",
-	LastMsgAt:   "last_msg_at",
-	UnreadUser:  "unread_user",
-	UnreadAgent: "unread_agent",
-	CreatedAt:   "created_at",
-	UpdatedAt:   "updated_at",
-	ClosedAt:    "closed_at",
+	Id:             "id",
+	UserId:         "user_id",
+	AgentId:        "agent_id",
+	Status:         "status",
+	Subject:        "subject",
+	LastMsg:        "last_msg",
+	LastMsgAt:      "last_msg_at",
+	UnreadUser:     "unread_user",
+	UnreadAgent:    "unread_agent",
+	Rating:         "rating",
+	RatingRemark:   "rating_remark",
+	RatedAt:        "rated_at",
+	RobotId:        "robot_id",
+	OrderId:        "order_id",
+	EscalateRemark: "escalate_remark",
+	EscalatedBy:    "escalated_by",
+	EscalatedAt:    "escalated_at",
+	CreatedAt:      "created_at",
+	UpdatedAt:      "updated_at",
+	ClosedAt:       "closed_at",
 }
 
 // NewSupportSessionDao creates and returns a new DAO object for table data access.
@@ -87,5 +103,3 @@ func (dao *SupportSessionDao) Ctx(ctx context.Context) *gdb.Model {
 func (dao *SupportSessionDao) Transaction(ctx context.Context, f func(ctx context.Context, tx gdb.TX) error) (err error) {
 	return dao.Ctx(ctx).Transaction(ctx, f)
 }
-
-
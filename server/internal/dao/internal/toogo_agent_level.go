@@ -20,6 +20,7 @@ type ToogoAgentLevelDao struct {
 // ToogoAgentLevelColumns defines and stores column names for table hg_toogo_agent_level.
 type ToogoAgentLevelColumns struct {
 	Id                   string // 主键ID
+	TenantId             string // 租户ID
 	Level                string // 等级
 	LevelName            string // 等级名称
 	RequireTeamCount     string // 需要团队人数
@@ -40,6 +41,7 @@ type ToogoAgentLevelColumns struct {
 // toogoAgentLevelColumns holds the columns for table hg_toogo_agent_level.
 var toogoAgentLevelColumns = ToogoAgentLevelColumns{
 	Id:                   "id",
+	TenantId:             "tenant_id",
 	Level:                "level",
 	LevelName:            "level_name",
 	RequireTeamCount:     "require_team_count",
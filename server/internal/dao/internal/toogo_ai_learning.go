@@ -0,0 +1,100 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package internal
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// ToogoAiLearningDao is the data access object for table hg_toogo_ai_learning.
+type ToogoAiLearningDao struct {
+	table   string                 // table is the underlying table name of the DAO.
+	group   string                 // group is the database configuration group name of current DAO.
+	columns ToogoAiLearningColumns // columns contains all the column names of Table for convenient usage.
+}
+
+// ToogoAiLearningColumns defines and stores column names for table hg_toogo_ai_learning.
+type ToogoAiLearningColumns struct {
+	Id               string // 主键ID
+	Symbol           string // 交易对
+	TimeFrame        string // 信号来源: 1m/5m/15m/30m/1h 或 market/technical/volatility
+	MarketState      string // 市场状态
+	RiskPreference   string // 风险偏好
+	PriceWeight      string // 价格权重
+	VolumeWeight     string // 成交量权重
+	TrendWeight      string // 趋势权重
+	VolatilityWeight string // 波动率权重
+	TotalSignals     string // 总信号数
+	CorrectSignals   string // 正确信号数
+	AccuracyRate     string // 准确率
+	TotalProfit      string // 总收益
+	AvgProfit        string // 平均收益
+	LastUpdate       string // 最后更新时间
+	CreatedAt        string // 创建时间
+	UpdatedAt        string // 更新时间
+}
+
+// toogoAiLearningColumns holds the columns for table hg_toogo_ai_learning.
+var toogoAiLearningColumns = ToogoAiLearningColumns{
+	Id:               "id",
+	Symbol:           "symbol",
+	TimeFrame:        "time_frame",
+	MarketState:      "market_state",
+	RiskPreference:   "risk_preference",
+	PriceWeight:      "price_weight",
+	VolumeWeight:     "volume_weight",
+	TrendWeight:      "trend_weight",
+	VolatilityWeight: "volatility_weight",
+	TotalSignals:     "total_signals",
+	CorrectSignals:   "correct_signals",
+	AccuracyRate:     "accuracy_rate",
+	TotalProfit:      "total_profit",
+	AvgProfit:        "avg_profit",
+	LastUpdate:       "last_update",
+	CreatedAt:        "created_at",
+	UpdatedAt:        "updated_at",
+}
+
+// NewToogoAiLearningDao creates and returns a new DAO object for table data access.
+func NewToogoAiLearningDao() *ToogoAiLearningDao {
+	return &ToogoAiLearningDao{
+		group:   "default",
+		table:   "hg_toogo_ai_learning",
+		columns: toogoAiLearningColumns,
+	}
+}
+
+// DB retrieves and returns the underlying raw database management object of current DAO.
+func (dao *ToogoAiLearningDao) DB() gdb.DB {
+	return g.DB(dao.group)
+}
+
+// Table returns the table name of current dao.
+func (dao *ToogoAiLearningDao) Table() string {
+	return dao.table
+}
+
+// Columns returns all column names of current dao.
+func (dao *ToogoAiLearningDao) Columns() ToogoAiLearningColumns {
+	return dao.columns
+}
+
+// Group returns the configuration group name of database of current dao.
+func (dao *ToogoAiLearningDao) Group() string {
+	return dao.group
+}
+
+// Ctx creates and returns the Model for current DAO.
+func (dao *ToogoAiLearningDao) Ctx(ctx context.Context) *gdb.Model {
+	return dao.DB().Model(dao.table).Safe().Ctx(ctx)
+}
+
+// Transaction wraps the transaction logic using function f.
+func (dao *ToogoAiLearningDao) Transaction(ctx context.Context, f func(ctx context.Context, tx gdb.TX) error) (err error) {
+	return dao.Ctx(ctx).Transaction(ctx, f)
+}
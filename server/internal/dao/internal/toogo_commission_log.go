@@ -33,6 +33,11 @@ type ToogoCommissionLogColumns struct {
 	RelatedType      string // 关联类型
 	OrderSn          string // 关联订单号
 	Remark           string // 备注
+	ReleaseTime      string // 冻结到期时间
+	ReleasedAt       string // 解冻入账时间
+	ReleasedAmount   string // 实际解冻入账金额
+	ReversedAt       string // 冲正时间
+	ReversalOf       string // 冲正的原佣金记录ID
 	CreatedAt        string // 创建时间
 }
 
@@ -52,6 +57,11 @@ var toogoCommissionLogColumns = ToogoCommissionLogColumns{
 	RelatedType:      "related_type",
 	OrderSn:          "order_sn",
 	Remark:           "remark",
+	ReleaseTime:      "release_time",
+	ReleasedAt:       "released_at",
+	ReleasedAmount:   "released_amount",
+	ReversedAt:       "reversed_at",
+	ReversalOf:       "reversal_of",
 	CreatedAt:        "created_at",
 }
 
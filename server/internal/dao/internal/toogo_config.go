@@ -20,6 +20,7 @@ type ToogoConfigDao struct {
 // ToogoConfigColumns defines and stores column names for table hg_toogo_config.
 type ToogoConfigColumns struct {
 	Id          string // 主键ID
+	TenantId    string // 租户ID
 	Group       string // 配置分组
 	Key         string // 配置KEY
 	Value       string // 配置值
@@ -34,6 +35,7 @@ type ToogoConfigColumns struct {
 // toogoConfigColumns holds the columns for table hg_toogo_config.
 var toogoConfigColumns = ToogoConfigColumns{
 	Id:          "id",
+	TenantId:    "tenant_id",
 	Group:       "group",
 	Key:         "key",
 	Value:       "value",
@@ -0,0 +1,104 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package internal
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// ToogoCouponDao is the data access object for table hg_toogo_coupon.
+type ToogoCouponDao struct {
+	table   string             // table is the underlying table name of the DAO.
+	group   string             // group is the database configuration group name of current DAO.
+	columns ToogoCouponColumns // columns contains all the column names of Table for convenient usage.
+}
+
+// ToogoCouponColumns defines and stores column names for table hg_toogo_coupon.
+type ToogoCouponColumns struct {
+	Id            string // 主键ID
+	TenantId      string // 租户ID
+	Code          string // 优惠码
+	CouponName    string // 优惠券名称
+	CouponType    string // 类型
+	DiscountValue string // 折扣值
+	MaxDiscount   string // 最高抵扣金额
+	MinAmount     string // 最低订单金额
+	PlanIds       string // 限定套餐ID
+	PeriodTypes   string // 限定订阅周期
+	TotalLimit    string // 总使用次数上限
+	UsedCount     string // 已使用次数
+	AgentId       string // 归属代理ID
+	StartTime     string // 生效时间
+	ExpireTime    string // 过期时间
+	Status        string // 状态
+	Remark        string // 备注
+	CreatedAt     string // 创建时间
+	UpdatedAt     string // 更新时间
+}
+
+// toogoCouponColumns holds the columns for table hg_toogo_coupon.
+var toogoCouponColumns = ToogoCouponColumns{
+	Id:            "id",
+	TenantId:      "tenant_id",
+	Code:          "code",
+	CouponName:    "coupon_name",
+	CouponType:    "coupon_type",
+	DiscountValue: "discount_value",
+	MaxDiscount:   "max_discount",
+	MinAmount:     "min_amount",
+	PlanIds:       "plan_ids",
+	PeriodTypes:   "period_types",
+	TotalLimit:    "total_limit",
+	UsedCount:     "used_count",
+	AgentId:       "agent_id",
+	StartTime:     "start_time",
+	ExpireTime:    "expire_time",
+	Status:        "status",
+	Remark:        "remark",
+	CreatedAt:     "created_at",
+	UpdatedAt:     "updated_at",
+}
+
+// NewToogoCouponDao creates and returns a new DAO object for table data access.
+func NewToogoCouponDao() *ToogoCouponDao {
+	return &ToogoCouponDao{
+		group:   "default",
+		table:   "hg_toogo_coupon",
+		columns: toogoCouponColumns,
+	}
+}
+
+// DB retrieves and returns the underlying raw database management object of current DAO.
+func (dao *ToogoCouponDao) DB() gdb.DB {
+	return g.DB(dao.group)
+}
+
+// Table returns the table name of current dao.
+func (dao *ToogoCouponDao) Table() string {
+	return dao.table
+}
+
+// Columns returns all column names of current dao.
+func (dao *ToogoCouponDao) Columns() ToogoCouponColumns {
+	return dao.columns
+}
+
+// Group returns the configuration group name of database of current dao.
+func (dao *ToogoCouponDao) Group() string {
+	return dao.group
+}
+
+// Ctx creates and returns the Model for current DAO, It automatically sets the context for current operation.
+func (dao *ToogoCouponDao) Ctx(ctx context.Context) *gdb.Model {
+	return dao.DB().Model(dao.table).Safe().Ctx(ctx)
+}
+
+// Transaction wraps the transaction logic using function f.
+func (dao *ToogoCouponDao) Transaction(ctx context.Context, f func(ctx context.Context, tx gdb.TX) error) (err error) {
+	return dao.Ctx(ctx).Transaction(ctx, f)
+}
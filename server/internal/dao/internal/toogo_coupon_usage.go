@@ -0,0 +1,92 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package internal
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// ToogoCouponUsageDao is the data access object for table hg_toogo_coupon_usage.
+type ToogoCouponUsageDao struct {
+	table   string                  // table is the underlying table name of the DAO.
+	group   string                  // group is the database configuration group name of current DAO.
+	columns ToogoCouponUsageColumns // columns contains all the column names of Table for convenient usage.
+}
+
+// ToogoCouponUsageColumns defines and stores column names for table hg_toogo_coupon_usage.
+type ToogoCouponUsageColumns struct {
+	Id             string // 主键ID
+	CouponId       string // 优惠券ID
+	CouponCode     string // 优惠码
+	UserId         string // 用户ID
+	AgentId        string // 推广码归属代理ID
+	Attributed     string // 是否由推广码绑定邀请关系
+	SubscriptionId string // 订阅ID
+	OrderSn        string // 订单号
+	PlanId         string // 套餐ID
+	PeriodType     string // 订阅周期
+	OriginalAmount string // 原价
+	DiscountAmount string // 抵扣金额
+	CreatedAt      string // 使用时间
+}
+
+// toogoCouponUsageColumns holds the columns for table hg_toogo_coupon_usage.
+var toogoCouponUsageColumns = ToogoCouponUsageColumns{
+	Id:             "id",
+	CouponId:       "coupon_id",
+	CouponCode:     "coupon_code",
+	UserId:         "user_id",
+	AgentId:        "agent_id",
+	Attributed:     "attributed",
+	SubscriptionId: "subscription_id",
+	OrderSn:        "order_sn",
+	PlanId:         "plan_id",
+	PeriodType:     "period_type",
+	OriginalAmount: "original_amount",
+	DiscountAmount: "discount_amount",
+	CreatedAt:      "created_at",
+}
+
+// NewToogoCouponUsageDao creates and returns a new DAO object for table data access.
+func NewToogoCouponUsageDao() *ToogoCouponUsageDao {
+	return &ToogoCouponUsageDao{
+		group:   "default",
+		table:   "hg_toogo_coupon_usage",
+		columns: toogoCouponUsageColumns,
+	}
+}
+
+// DB retrieves and returns the underlying raw database management object of current DAO.
+func (dao *ToogoCouponUsageDao) DB() gdb.DB {
+	return g.DB(dao.group)
+}
+
+// Table returns the table name of current dao.
+func (dao *ToogoCouponUsageDao) Table() string {
+	return dao.table
+}
+
+// Columns returns all column names of current dao.
+func (dao *ToogoCouponUsageDao) Columns() ToogoCouponUsageColumns {
+	return dao.columns
+}
+
+// Group returns the configuration group name of database of current dao.
+func (dao *ToogoCouponUsageDao) Group() string {
+	return dao.group
+}
+
+// Ctx creates and returns the Model for current DAO, It automatically sets the context for current operation.
+func (dao *ToogoCouponUsageDao) Ctx(ctx context.Context) *gdb.Model {
+	return dao.DB().Model(dao.table).Safe().Ctx(ctx)
+}
+
+// Transaction wraps the transaction logic using function f.
+func (dao *ToogoCouponUsageDao) Transaction(ctx context.Context, f func(ctx context.Context, tx gdb.TX) error) (err error) {
+	return dao.Ctx(ctx).Transaction(ctx, f)
+}
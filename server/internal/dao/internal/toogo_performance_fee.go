@@ -0,0 +1,86 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package internal
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// ToogoPerformanceFeeDao is the data access object for table hg_toogo_performance_fee.
+type ToogoPerformanceFeeDao struct {
+	table   string                     // table is the underlying table name of the DAO.
+	group   string                     // group is the database configuration group name of current DAO.
+	columns ToogoPerformanceFeeColumns // columns contains all the column names of Table for convenient usage.
+}
+
+// ToogoPerformanceFeeColumns defines and stores column names for table hg_toogo_performance_fee.
+type ToogoPerformanceFeeColumns struct {
+	Id                string // 主键ID
+	UserId            string // 用户ID
+	RobotId           string // 机器人ID
+	CumPnl            string // 累计净已实现盈亏
+	HighWaterMark     string // 已计费高水位
+	SettledUntil      string // 已结算至
+	TotalConsumePower string // 累计收取算力
+	LastSettleAt      string // 最近结算时间
+	CreatedAt         string // 创建时间
+	UpdatedAt         string // 更新时间
+}
+
+// toogoPerformanceFeeColumns holds the columns for table hg_toogo_performance_fee.
+var toogoPerformanceFeeColumns = ToogoPerformanceFeeColumns{
+	Id:                "id",
+	UserId:            "user_id",
+	RobotId:           "robot_id",
+	CumPnl:            "cum_pnl",
+	HighWaterMark:     "high_water_mark",
+	SettledUntil:      "settled_until",
+	TotalConsumePower: "total_consume_power",
+	LastSettleAt:      "last_settle_at",
+	CreatedAt:         "created_at",
+	UpdatedAt:         "updated_at",
+}
+
+// NewToogoPerformanceFeeDao creates and returns a new DAO object for table data access.
+func NewToogoPerformanceFeeDao() *ToogoPerformanceFeeDao {
+	return &ToogoPerformanceFeeDao{
+		group:   "default",
+		table:   "hg_toogo_performance_fee",
+		columns: toogoPerformanceFeeColumns,
+	}
+}
+
+// DB retrieves and returns the underlying raw database management object of current DAO.
+func (dao *ToogoPerformanceFeeDao) DB() gdb.DB {
+	return g.DB(dao.group)
+}
+
+// Table returns the table name of current dao.
+func (dao *ToogoPerformanceFeeDao) Table() string {
+	return dao.table
+}
+
+// Columns returns all column names of current dao.
+func (dao *ToogoPerformanceFeeDao) Columns() ToogoPerformanceFeeColumns {
+	return dao.columns
+}
+
+// Group returns the configuration group name of database of current dao.
+func (dao *ToogoPerformanceFeeDao) Group() string {
+	return dao.group
+}
+
+// Ctx creates and returns the Model for current DAO, It automatically sets the context for current operation.
+func (dao *ToogoPerformanceFeeDao) Ctx(ctx context.Context) *gdb.Model {
+	return dao.DB().Model(dao.table).Safe().Ctx(ctx)
+}
+
+// Transaction wraps the transaction logic using function f.
+func (dao *ToogoPerformanceFeeDao) Transaction(ctx context.Context, f func(ctx context.Context, tx gdb.TX) error) (err error) {
+	return dao.Ctx(ctx).Transaction(ctx, f)
+}
@@ -20,6 +20,7 @@ type ToogoPlanDao struct {
 // ToogoPlanColumns defines and stores column names for table hg_toogo_plan.
 type ToogoPlanColumns struct {
 	Id                 string // 主键ID
+	TenantId           string // 租户ID
 	PlanName           string // 套餐名称
 	PlanCode           string // 套餐代码
 	RobotLimit         string // 支持机器人数量
@@ -39,6 +40,8 @@ type ToogoPlanColumns struct {
 	GiftPowerQuarterly string // 季订阅赠送算力
 	GiftPowerHalfYear  string // 半年订阅赠送算力
 	GiftPowerYearly    string // 年订阅赠送算力
+	TrialDays          string // 免费试用天数
+	TrialRobotLimit    string // 试用期机器人数量
 	Description        string // 套餐描述
 	Features           string // 套餐特性(JSON)
 	IsDefault          string // 是否默认套餐
@@ -51,6 +54,7 @@ type ToogoPlanColumns struct {
 // toogoPlanColumns holds the columns for table hg_toogo_plan.
 var toogoPlanColumns = ToogoPlanColumns{
 	Id:                 "id",
+	TenantId:           "tenant_id",
 	PlanName:           "plan_name",
 	PlanCode:           "plan_code",
 	RobotLimit:         "robot_limit",
@@ -70,6 +74,8 @@ var toogoPlanColumns = ToogoPlanColumns{
 	GiftPowerQuarterly: "gift_power_quarterly",
 	GiftPowerHalfYear:  "gift_power_half_year",
 	GiftPowerYearly:    "gift_power_yearly",
+	TrialDays:          "trial_days",
+	TrialRobotLimit:    "trial_robot_limit",
 	Description:        "description",
 	Features:           "features",
 	IsDefault:          "is_default",
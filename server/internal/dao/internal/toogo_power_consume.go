@@ -25,10 +25,18 @@ type ToogoPowerConsumeColumns struct {
 	RobotId       string // 机器人ID
 	OrderId       string // 交易订单ID
 	OrderSn       string // 订单号
+	SettleType    string // 结算方式
+	PeriodStart   string // 结算周期开始
+	PeriodEnd     string // 结算周期结束
+	NetPnl        string // 本期净已实现盈亏
+	HwmBefore     string // 结算前高水位
+	HwmAfter      string // 结算后高水位
 	ProfitAmount  string // 盈利金额
 	ConsumeRate   string // 消耗比例
 	ConsumePower  string // 消耗算力
 	FromPower     string // 从算力账户扣除
+	FromGiftPower string // 从积分账户扣除
+	PowerAfter    string // 扣除后算力余额
 	VipLevel      string // 用户VIP等级
 	DiscountRate  string // 折扣比例
 	OriginalPower string // 原始消耗算力
@@ -42,10 +50,18 @@ var toogoPowerConsumeColumns = ToogoPowerConsumeColumns{
 	RobotId:       "robot_id",
 	OrderId:       "order_id",
 	OrderSn:       "order_sn",
+	SettleType:    "settle_type",
+	PeriodStart:   "period_start",
+	PeriodEnd:     "period_end",
+	NetPnl:        "net_pnl",
+	HwmBefore:     "hwm_before",
+	HwmAfter:      "hwm_after",
 	ProfitAmount:  "profit_amount",
 	ConsumeRate:   "consume_rate",
 	ConsumePower:  "consume_power",
 	FromPower:     "from_power",
+	FromGiftPower: "from_gift_power",
+	PowerAfter:    "power_after",
 	VipLevel:      "vip_level",
 	DiscountRate:  "discount_rate",
 	OriginalPower: "original_power",
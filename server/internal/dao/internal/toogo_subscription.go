@@ -26,6 +26,10 @@ type ToogoSubscriptionColumns struct {
 	OrderSn           string // 订单号
 	PeriodType        string // 订阅周期
 	Amount            string // 订阅金额
+	OriginalAmount    string // 订阅原价
+	DiscountAmount    string // 优惠券抵扣金额
+	CouponId          string // 优惠券ID
+	CouponCode        string // 优惠码
 	GiftPower         string // 赠送算力
 	StartTime         string // 开始时间
 	ExpireTime        string // 到期时间
@@ -33,6 +37,11 @@ type ToogoSubscriptionColumns struct {
 	Status            string // 状态
 	PaidAt            string // 支付时间
 	PayType           string // 支付方式
+	AutoRenew         string // 自动续费
+	RemindStage       string // 到期提醒
+	GraceExpireTime   string // 宽限期截止时间
+	CreditAmount      string // 升降级折抵金额
+	ReplacedBy        string // 被替换的新订阅ID
 	InviterId         string // 邀请人ID
 	CommissionSettled string // 佣金是否已结算
 	CreatedAt         string // 创建时间
@@ -48,6 +57,10 @@ var toogoSubscriptionColumns = ToogoSubscriptionColumns{
 	OrderSn:           "order_sn",
 	PeriodType:        "period_type",
 	Amount:            "amount",
+	OriginalAmount:    "original_amount",
+	DiscountAmount:    "discount_amount",
+	CouponId:          "coupon_id",
+	CouponCode:        "coupon_code",
 	GiftPower:         "gift_power",
 	StartTime:         "start_time",
 	ExpireTime:        "expire_time",
@@ -55,6 +68,11 @@ var toogoSubscriptionColumns = ToogoSubscriptionColumns{
 	Status:            "status",
 	PaidAt:            "paid_at",
 	PayType:           "pay_type",
+	AutoRenew:         "auto_renew",
+	RemindStage:       "remind_stage",
+	GraceExpireTime:   "grace_expire_time",
+	CreditAmount:      "credit_amount",
+	ReplacedBy:        "replaced_by",
 	InviterId:         "inviter_id",
 	CommissionSettled: "commission_settled",
 	CreatedAt:         "created_at",
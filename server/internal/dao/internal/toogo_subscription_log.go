@@ -0,0 +1,86 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package internal
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// ToogoSubscriptionLogDao is the data access object for table hg_toogo_subscription_log.
+type ToogoSubscriptionLogDao struct {
+	table   string                      // table is the underlying table name of the DAO.
+	group   string                      // group is the database configuration group name of current DAO.
+	columns ToogoSubscriptionLogColumns // columns contains all the column names of Table for convenient usage.
+}
+
+// ToogoSubscriptionLogColumns defines and stores column names for table hg_toogo_subscription_log.
+type ToogoSubscriptionLogColumns struct {
+	Id             string // 主键ID
+	SubscriptionId string // 订阅ID
+	UserId         string // 用户ID
+	PlanId         string // 套餐ID
+	Event          string // 事件
+	FromStatus     string // 变更前状态
+	ToStatus       string // 变更后状态
+	Amount         string // 涉及金额
+	Remark         string // 备注
+	CreatedAt      string // 创建时间
+}
+
+// toogoSubscriptionLogColumns holds the columns for table hg_toogo_subscription_log.
+var toogoSubscriptionLogColumns = ToogoSubscriptionLogColumns{
+	Id:             "id",
+	SubscriptionId: "subscription_id",
+	UserId:         "user_id",
+	PlanId:         "plan_id",
+	Event:          "event",
+	FromStatus:     "from_status",
+	ToStatus:       "to_status",
+	Amount:         "amount",
+	Remark:         "remark",
+	CreatedAt:      "created_at",
+}
+
+// NewToogoSubscriptionLogDao creates and returns a new DAO object for table data access.
+func NewToogoSubscriptionLogDao() *ToogoSubscriptionLogDao {
+	return &ToogoSubscriptionLogDao{
+		group:   "default",
+		table:   "hg_toogo_subscription_log",
+		columns: toogoSubscriptionLogColumns,
+	}
+}
+
+// DB retrieves and returns the underlying raw database management object of current DAO.
+func (dao *ToogoSubscriptionLogDao) DB() gdb.DB {
+	return g.DB(dao.group)
+}
+
+// Table returns the table name of current dao.
+func (dao *ToogoSubscriptionLogDao) Table() string {
+	return dao.table
+}
+
+// Columns returns all column names of current dao.
+func (dao *ToogoSubscriptionLogDao) Columns() ToogoSubscriptionLogColumns {
+	return dao.columns
+}
+
+// Group returns the configuration group name of database of current dao.
+func (dao *ToogoSubscriptionLogDao) Group() string {
+	return dao.group
+}
+
+// Ctx creates and returns the Model for current DAO, It automatically sets the context for current operation.
+func (dao *ToogoSubscriptionLogDao) Ctx(ctx context.Context) *gdb.Model {
+	return dao.DB().Model(dao.table).Safe().Ctx(ctx)
+}
+
+// Transaction wraps the transaction logic using function f.
+func (dao *ToogoSubscriptionLogDao) Transaction(ctx context.Context, f func(ctx context.Context, tx gdb.TX) error) (err error) {
+	return dao.Ctx(ctx).Transaction(ctx, f)
+}
@@ -20,6 +20,7 @@ type ToogoUserDao struct {
 // ToogoUserColumns defines and stores column names for table hg_toogo_user.
 type ToogoUserColumns struct {
 	Id                string // 主键ID
+	TenantId          string // 租户ID
 	MemberId          string // 关联admin_member.id
 	VipLevel          string // 身份等级: V1-V10
 	IsAgent           string // 是否代理商: 0=否, 1=是
@@ -37,9 +38,11 @@ type ToogoUserColumns struct {
 	TeamConsumePower  string // 团队消耗算力
 	CurrentPlanId     string // 当前订阅套餐ID
 	PlanExpireTime    string // 套餐到期时间
+	PlanGraceExpire   string // 套餐宽限期截止时间
 	RobotLimit        string // 机器人数量限制
 	ActiveRobotCount  string // 运行中机器人数量
 	PowerDiscount     string // 算力消耗折扣(%)
+	VipDowngradeAt    string // 不满足当前VIP等级的起始时间
 	AgentApplyRemark  string // 代理商申请备注
 	AgentApplyAt      string // 代理商申请时间
 	AgentApprovedAt   string // 代理商审批时间
@@ -52,6 +55,7 @@ type ToogoUserColumns struct {
 // toogoUserColumns holds the columns for table hg_toogo_user.
 var toogoUserColumns = ToogoUserColumns{
 	Id:                "id",
+	TenantId:          "tenant_id",
 	MemberId:          "member_id",
 	VipLevel:          "vip_level",
 	IsAgent:           "is_agent",
@@ -69,9 +73,11 @@ var toogoUserColumns = ToogoUserColumns{
 	TeamConsumePower:  "team_consume_power",
 	CurrentPlanId:     "current_plan_id",
 	PlanExpireTime:    "plan_expire_time",
+	PlanGraceExpire:   "plan_grace_expire",
 	RobotLimit:        "robot_limit",
 	ActiveRobotCount:  "active_robot_count",
 	PowerDiscount:     "power_discount",
+	VipDowngradeAt:    "vip_downgrade_at",
 	AgentApplyRemark:  "agent_apply_remark",
 	AgentApplyAt:      "agent_apply_at",
 	AgentApprovedAt:   "agent_approved_at",
@@ -0,0 +1,74 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package internal
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// ToogoUserRelationDao is the data access object for table hg_toogo_user_relation.
+type ToogoUserRelationDao struct {
+	table   string                   // table is the underlying table name of the DAO.
+	group   string                   // group is the database configuration group name of current DAO.
+	columns ToogoUserRelationColumns // columns contains all the column names of Table for convenient usage.
+}
+
+// ToogoUserRelationColumns defines and stores column names for table hg_toogo_user_relation.
+type ToogoUserRelationColumns struct {
+	AncestorId   string // 上级ID
+	DescendantId string // 下级ID
+	Depth        string // 层级距离
+	CreatedAt    string // 创建时间
+}
+
+// toogoUserRelationColumns holds the columns for table hg_toogo_user_relation.
+var toogoUserRelationColumns = ToogoUserRelationColumns{
+	AncestorId:   "ancestor_id",
+	DescendantId: "descendant_id",
+	Depth:        "depth",
+	CreatedAt:    "created_at",
+}
+
+// NewToogoUserRelationDao creates and returns a new DAO object for table data access.
+func NewToogoUserRelationDao() *ToogoUserRelationDao {
+	return &ToogoUserRelationDao{
+		group:   "default",
+		table:   "hg_toogo_user_relation",
+		columns: toogoUserRelationColumns,
+	}
+}
+
+// DB retrieves and returns the underlying raw database management object of current DAO.
+func (dao *ToogoUserRelationDao) DB() gdb.DB {
+	return g.DB(dao.group)
+}
+
+// Table returns the table name of current dao.
+func (dao *ToogoUserRelationDao) Table() string {
+	return dao.table
+}
+
+// Columns returns all column names of current dao.
+func (dao *ToogoUserRelationDao) Columns() ToogoUserRelationColumns {
+	return dao.columns
+}
+
+// Group returns the configuration group name of database of current dao.
+func (dao *ToogoUserRelationDao) Group() string {
+	return dao.group
+}
+
+// Ctx creates and returns the Model for current DAO, It automatically sets the context for current operation.
+func (dao *ToogoUserRelationDao) Ctx(ctx context.Context) *gdb.Model {
+	return dao.DB().Model(dao.table).Safe().Ctx(ctx)
+}
+
+// Transaction wraps the transaction logic using function f.
+func (dao *ToogoUserRelationDao) Transaction(ctx context.Context, f func(ctx context.Context, tx gdb.TX) error) (err error) {
+	return dao.Ctx(ctx).Transaction(ctx, f)
+}
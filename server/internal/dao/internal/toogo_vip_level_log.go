@@ -0,0 +1,92 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package internal
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// ToogoVipLevelLogDao is the data access object for table hg_toogo_vip_level_log.
+type ToogoVipLevelLogDao struct {
+	table   string                  // table is the underlying table name of the DAO.
+	group   string                  // group is the database configuration group name of current DAO.
+	columns ToogoVipLevelLogColumns // columns contains all the column names of Table for convenient usage.
+}
+
+// ToogoVipLevelLogColumns defines and stores column names for table hg_toogo_vip_level_log.
+type ToogoVipLevelLogColumns struct {
+	Id                string // 主键ID
+	UserId            string // 用户ID
+	FromLevel         string // 变更前等级
+	ToLevel           string // 变更后等级
+	ChangeType        string // 变更类型
+	Source            string // 触发来源
+	InviteCount       string // 直推人数
+	ConsumePower      string // 个人消耗算力
+	TeamConsumePower  string // 团队消耗算力
+	FromPowerDiscount string // 变更前算力折扣
+	ToPowerDiscount   string // 变更后算力折扣
+	Remark            string // 备注
+	CreatedAt         string // 创建时间
+}
+
+// toogoVipLevelLogColumns holds the columns for table hg_toogo_vip_level_log.
+var toogoVipLevelLogColumns = ToogoVipLevelLogColumns{
+	Id:                "id",
+	UserId:            "user_id",
+	FromLevel:         "from_level",
+	ToLevel:           "to_level",
+	ChangeType:        "change_type",
+	Source:            "source",
+	InviteCount:       "invite_count",
+	ConsumePower:      "consume_power",
+	TeamConsumePower:  "team_consume_power",
+	FromPowerDiscount: "from_power_discount",
+	ToPowerDiscount:   "to_power_discount",
+	Remark:            "remark",
+	CreatedAt:         "created_at",
+}
+
+// NewToogoVipLevelLogDao creates and returns a new DAO object for table data access.
+func NewToogoVipLevelLogDao() *ToogoVipLevelLogDao {
+	return &ToogoVipLevelLogDao{
+		group:   "default",
+		table:   "hg_toogo_vip_level_log",
+		columns: toogoVipLevelLogColumns,
+	}
+}
+
+// DB retrieves and returns the underlying raw database management object of current DAO.
+func (dao *ToogoVipLevelLogDao) DB() gdb.DB {
+	return g.DB(dao.group)
+}
+
+// Table returns the table name of current dao.
+func (dao *ToogoVipLevelLogDao) Table() string {
+	return dao.table
+}
+
+// Columns returns all column names of current dao.
+func (dao *ToogoVipLevelLogDao) Columns() ToogoVipLevelLogColumns {
+	return dao.columns
+}
+
+// Group returns the configuration group name of database of current dao.
+func (dao *ToogoVipLevelLogDao) Group() string {
+	return dao.group
+}
+
+// Ctx creates and returns the Model for current DAO, It automatically sets the context for current operation.
+func (dao *ToogoVipLevelLogDao) Ctx(ctx context.Context) *gdb.Model {
+	return dao.DB().Model(dao.table).Safe().Ctx(ctx)
+}
+
+// Transaction wraps the transaction logic using function f.
+func (dao *ToogoVipLevelLogDao) Transaction(ctx context.Context, f func(ctx context.Context, tx gdb.TX) error) (err error) {
+	return dao.Ctx(ctx).Transaction(ctx, f)
+}
@@ -30,11 +30,21 @@ type TradingApiConfigColumns struct {
 	ApiKey         string // API Key（加密）
 	SecretKey      string // Secret Key（加密）
 	Passphrase     string // Passphrase（加密，可选）
+	DataKey        string // 数据密钥（主密钥包装）
+	KeyVersion     string // 主密钥版本：0=旧版静态密钥
 	IsDefault      string // 是否默认：0=否,1=是
 	Status         string // 状态：1=正常,2=禁用
 	LastVerifyTime string // 最后验证时间
 	VerifyStatus   string // 验证状态：0=未验证,1=成功,2=失败
 	VerifyMessage  string // 验证消息
+	PermStatus     string // 权限检测状态：0=未检测,1=正常,2=风险提示,3=不可用
+	PermFutures    string // 合约交易权限：0=未开启,1=已开启,2=无法确认
+	PermWithdraw   string // 提现权限：0=未开启,1=已开启,2=无法确认
+	IpRestricted   string // 是否绑定IP白名单：0=否,1=是
+	IpWhitelist    string // IP白名单（逗号分隔）
+	KeyExpireAt    string // API Key过期时间
+	PermMessage    string // 权限检测结果说明
+	PermCheckedAt  string // 最后权限检测时间
 	Remark         string // 备注
 	CreatedAt      string // 创建时间
 	UpdatedAt      string // 更新时间
@@ -52,11 +62,21 @@ var tradingApiConfigColumns = TradingApiConfigColumns{
 	ApiKey:         "api_key",
 	SecretKey:      "secret_key",
 	Passphrase:     "passphrase",
+	DataKey:        "data_key",
+	KeyVersion:     "key_version",
 	IsDefault:      "is_default",
 	Status:         "status",
 	LastVerifyTime: "last_verify_time",
 	VerifyStatus:   "verify_status",
 	VerifyMessage:  "verify_message",
+	PermStatus:     "perm_status",
+	PermFutures:    "perm_futures",
+	PermWithdraw:   "perm_withdraw",
+	IpRestricted:   "ip_restricted",
+	IpWhitelist:    "ip_whitelist",
+	KeyExpireAt:    "key_expire_at",
+	PermMessage:    "perm_message",
+	PermCheckedAt:  "perm_checked_at",
 	Remark:         "remark",
 	CreatedAt:      "created_at",
 	UpdatedAt:      "updated_at",
@@ -24,6 +24,8 @@ type TradingProxyConfigColumns struct {
 	Id           string // 主键ID
 	TenantId     string // 租户ID
 	UserId       string // 用户ID
+	Name         string // 代理名称
+	Weight       string // 选择权重
 	Enabled      string // 是否启用：0=禁用,1=启用
 	ProxyType    string // 代理类型：socks5/http
 	ProxyAddress string // 代理地址
@@ -33,6 +35,8 @@ type TradingProxyConfigColumns struct {
 	LastTestTime string // 最后测试时间
 	TestStatus   string // 测试状态：0=未测试,1=成功,2=失败
 	TestMessage  string // 测试消息
+	Latency      string // 平均延迟(ms)
+	VenueLatency string // 各交易所延迟(ms)，JSON
 	CreatedAt    string // 创建时间
 	UpdatedAt    string // 更新时间
 }
@@ -42,6 +46,8 @@ var tradingProxyConfigColumns = TradingProxyConfigColumns{
 	Id:           "id",
 	TenantId:     "tenant_id",
 	UserId:       "user_id",
+	Name:         "name",
+	Weight:       "weight",
 	Enabled:      "enabled",
 	ProxyType:    "proxy_type",
 	ProxyAddress: "proxy_address",
@@ -51,6 +57,8 @@ var tradingProxyConfigColumns = TradingProxyConfigColumns{
 	LastTestTime: "last_test_time",
 	TestStatus:   "test_status",
 	TestMessage:  "test_message",
+	Latency:      "latency",
+	VenueLatency: "venue_latency",
 	CreatedAt:    "created_at",
 	UpdatedAt:    "updated_at",
 }
@@ -56,6 +56,10 @@ type TradingRobotColumns struct {
 	AutoCloseEnabled        string // 全自动平仓：0=否,1=是
 	ProfitLockEnabled       string // 锁定盈利开关：0=关闭,1=开启（止盈启动后禁止自动开新仓）
 	DualSidePosition        string // 双向开单：0=单向,1=双向
+	BasketId                string // 组合机器人ID：0=独立机器人
+	AllocationWeight        string // 组合内分配权重
+	RobotMode               string // 运行模式：signal=方向信号,grid=网格
+	GridConfig              string // 网格配置(JSON)
 	Remark                  string // 备注
 	CreatedAt               string // 创建时间
 	UpdatedAt               string // 更新时间
@@ -99,6 +103,8 @@ var tradingRobotColumns = TradingRobotColumns{
 	AutoCloseEnabled:        "auto_close_enabled",
 	ProfitLockEnabled:       "profit_lock_enabled",
 	DualSidePosition:        "dual_side_position",
+	BasketId:                "basket_id",
+	AllocationWeight:        "allocation_weight",
 	Remark:                  "remark",
 	CreatedAt:               "created_at",
 	UpdatedAt:               "updated_at",
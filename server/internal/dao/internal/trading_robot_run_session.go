@@ -22,6 +22,7 @@ type TradingRobotRunSessionDao struct {
 type TradingRobotRunSessionColumns struct {
 	Id             string
 	RobotId        string
+	BasketId       string
 	UserId         string
 	Exchange       string
 	Symbol         string
@@ -40,6 +41,7 @@ type TradingRobotRunSessionColumns struct {
 var tradingRobotRunSessionColumns = TradingRobotRunSessionColumns{
 	Id:             "id",
 	RobotId:        "robot_id",
+	BasketId:       "basket_id",
 	UserId:         "user_id",
 	Exchange:       "exchange",
 	Symbol:         "symbol",
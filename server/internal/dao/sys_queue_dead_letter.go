@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"hotgo/internal/dao/internal"
+)
+
+// sysQueueDeadLetterDao is the data access object for the table hg_sys_queue_dead_letter.
+// You can define custom methods on it to extend its functionality as needed.
+type sysQueueDeadLetterDao struct {
+	*internal.SysQueueDeadLetterDao
+}
+
+var (
+	// SysQueueDeadLetter is a globally accessible object for table hg_sys_queue_dead_letter operations.
+	SysQueueDeadLetter = sysQueueDeadLetterDao{internal.NewSysQueueDeadLetterDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"hotgo/internal/dao/internal"
+)
+
+// sysSensitiveLogDao is the data access object for the table hg_sys_sensitive_log.
+// You can define custom methods on it to extend its functionality as needed.
+type sysSensitiveLogDao struct {
+	*internal.SysSensitiveLogDao
+}
+
+var (
+	// SysSensitiveLog is a globally accessible object for table hg_sys_sensitive_log operations.
+	SysSensitiveLog = sysSensitiveLogDao{internal.NewSysSensitiveLogDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"hotgo/internal/dao/internal"
+)
+
+// sysTwoFactorDao is the data access object for the table hg_sys_two_factor.
+// You can define custom methods on it to extend its functionality as needed.
+type sysTwoFactorDao struct {
+	*internal.SysTwoFactorDao
+}
+
+var (
+	// SysTwoFactor is a globally accessible object for table hg_sys_two_factor operations.
+	SysTwoFactor = sysTwoFactorDao{internal.NewSysTwoFactorDao()}
+)
+
+// Add your custom methods and functionality below.
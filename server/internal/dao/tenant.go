@@ -0,0 +1,77 @@
+// Package dao
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+package dao
+
+import (
+	"github.com/gogf/gf/v2/database/gdb"
+)
+
+// 多租户(白标)数据隔离
+// toogo/trading 表的 Ctx 统一经过租户处理器，处理器由 hgorm/handler 注册（handler 依赖 dao，这里只保留扩展点）
+var (
+	tenantScopeHandler  gdb.ModelHandler // 租户目录数据(带 tenant_id)：只能访问本租户
+	tenantSharedHandler gdb.ModelHandler // 平台可共享数据(带 tenant_id)：本租户 + 平台(tenant_id=0)
+	tenantMemberHandler gdb.ModelHandler // 用户业务数据(带 user_id)：只能访问本租户用户的数据
+	tenantRobotHandler  gdb.ModelHandler // 机器人数据(带 robot_id)：只能访问本租户机器人的数据
+	tenantOrderHandler  gdb.ModelHandler // 订单数据(带 order_id)：只能访问本租户订单的数据
+	tenantGroupHandler  gdb.ModelHandler // 策略模板(带 group_id)：只能访问本租户策略模板组的数据
+)
+
+// TenantHandlers 多租户数据隔离处理器
+type TenantHandlers struct {
+	Scope  gdb.ModelHandler
+	Shared gdb.ModelHandler
+	Member gdb.ModelHandler
+	Robot  gdb.ModelHandler
+	Order  gdb.ModelHandler
+	Group  gdb.ModelHandler
+}
+
+// RegisterTenantHandler 注册多租户数据隔离处理器
+func RegisterTenantHandler(h TenantHandlers) {
+	tenantScopeHandler = h.Scope
+	tenantSharedHandler = h.Shared
+	tenantMemberHandler = h.Member
+	tenantRobotHandler = h.Robot
+	tenantOrderHandler = h.Order
+	tenantGroupHandler = h.Group
+}
+
+// tenantScope 按 tenant_id 隔离
+func tenantScope(m *gdb.Model) *gdb.Model {
+	return withTenantHandler(m, tenantScopeHandler)
+}
+
+// tenantShared 按 tenant_id 隔离，平台数据对所有租户可见
+func tenantShared(m *gdb.Model) *gdb.Model {
+	return withTenantHandler(m, tenantSharedHandler)
+}
+
+// tenantMember 按 user_id 所属租户隔离，表中没有 user_id 时不处理
+func tenantMember(m *gdb.Model) *gdb.Model {
+	return withTenantHandler(m, tenantMemberHandler)
+}
+
+// tenantRobot 按 robot_id 所属机器人隔离
+func tenantRobot(m *gdb.Model) *gdb.Model {
+	return withTenantHandler(m, tenantRobotHandler)
+}
+
+// tenantOrder 按 order_id 所属订单隔离
+func tenantOrder(m *gdb.Model) *gdb.Model {
+	return withTenantHandler(m, tenantOrderHandler)
+}
+
+// tenantGroup 按 group_id 所属策略模板组隔离
+func tenantGroup(m *gdb.Model) *gdb.Model {
+	return withTenantHandler(m, tenantGroupHandler)
+}
+
+func withTenantHandler(m *gdb.Model, h gdb.ModelHandler) *gdb.Model {
+	if h == nil {
+		return m
+	}
+	return m.Handler(h)
+}
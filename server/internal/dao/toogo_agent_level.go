@@ -5,6 +5,9 @@
 package dao
 
 import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
 	"hotgo/internal/dao/internal"
 )
 
@@ -23,3 +26,7 @@ type toogoAgentLevelDaoImpl struct {
 	internalToogoAgentLevelDao
 }
 
+// Ctx 创建模型，按租户隔离
+func (d *toogoAgentLevelDaoImpl) Ctx(ctx context.Context) *gdb.Model {
+	return tenantScope(d.internalToogoAgentLevelDao.Ctx(ctx))
+}
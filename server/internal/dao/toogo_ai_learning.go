@@ -0,0 +1,32 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"hotgo/internal/dao/internal"
+)
+
+// internalToogoAiLearningDao is internal type for wrapping internal DAO implements.
+type internalToogoAiLearningDao = *internal.ToogoAiLearningDao
+
+// toogoAiLearningDao is the data access object for table hg_toogo_ai_learning.
+var toogoAiLearningDao = &toogoAiLearningDaoImpl{
+	internal.NewToogoAiLearningDao(),
+}
+
+// ToogoAiLearning is the manager for table hg_toogo_ai_learning.
+var ToogoAiLearning = toogoAiLearningDao
+
+type toogoAiLearningDaoImpl struct {
+	internalToogoAiLearningDao
+}
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d *toogoAiLearningDaoImpl) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.internalToogoAiLearningDao.Ctx(ctx))
+}
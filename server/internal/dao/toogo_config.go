@@ -5,6 +5,9 @@
 package dao
 
 import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
 	"hotgo/internal/dao/internal"
 )
 
@@ -23,3 +26,7 @@ type toogoConfigDaoImpl struct {
 	internalToogoConfigDao
 }
 
+// Ctx 创建模型，按租户隔离，平台数据共享
+func (d *toogoConfigDaoImpl) Ctx(ctx context.Context) *gdb.Model {
+	return tenantShared(d.internalToogoConfigDao.Ctx(ctx))
+}
@@ -0,0 +1,32 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"hotgo/internal/dao/internal"
+)
+
+// internalToogoCouponDao is internal type for wrapping internal DAO implements.
+type internalToogoCouponDao = *internal.ToogoCouponDao
+
+// toogoCouponDao is the data access object for table hg_toogo_coupon.
+var toogoCouponDao = &toogoCouponDaoImpl{
+	internal.NewToogoCouponDao(),
+}
+
+// ToogoCoupon is the manager for table hg_toogo_coupon.
+var ToogoCoupon = toogoCouponDao
+
+type toogoCouponDaoImpl struct {
+	internalToogoCouponDao
+}
+
+// Ctx 创建模型，按租户隔离
+func (d *toogoCouponDaoImpl) Ctx(ctx context.Context) *gdb.Model {
+	return tenantScope(d.internalToogoCouponDao.Ctx(ctx))
+}
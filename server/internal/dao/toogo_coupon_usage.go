@@ -0,0 +1,32 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"hotgo/internal/dao/internal"
+)
+
+// internalToogoCouponUsageDao is internal type for wrapping internal DAO implements.
+type internalToogoCouponUsageDao = *internal.ToogoCouponUsageDao
+
+// toogoCouponUsageDao is the data access object for table hg_toogo_coupon_usage.
+var toogoCouponUsageDao = &toogoCouponUsageDaoImpl{
+	internal.NewToogoCouponUsageDao(),
+}
+
+// ToogoCouponUsage is the manager for table hg_toogo_coupon_usage.
+var ToogoCouponUsage = toogoCouponUsageDao
+
+type toogoCouponUsageDaoImpl struct {
+	internalToogoCouponUsageDao
+}
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d *toogoCouponUsageDaoImpl) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.internalToogoCouponUsageDao.Ctx(ctx))
+}
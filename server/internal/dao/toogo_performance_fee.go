@@ -0,0 +1,32 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"hotgo/internal/dao/internal"
+)
+
+// internalToogoPerformanceFeeDao is internal type for wrapping internal DAO implements.
+type internalToogoPerformanceFeeDao = *internal.ToogoPerformanceFeeDao
+
+// toogoPerformanceFeeDao is the data access object for table hg_toogo_performance_fee.
+var toogoPerformanceFeeDao = &toogoPerformanceFeeDaoImpl{
+	internal.NewToogoPerformanceFeeDao(),
+}
+
+// ToogoPerformanceFee is the manager for table hg_toogo_performance_fee.
+var ToogoPerformanceFee = toogoPerformanceFeeDao
+
+type toogoPerformanceFeeDaoImpl struct {
+	internalToogoPerformanceFeeDao
+}
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d *toogoPerformanceFeeDaoImpl) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.internalToogoPerformanceFeeDao.Ctx(ctx))
+}
@@ -5,6 +5,9 @@
 package dao
 
 import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
 	"hotgo/internal/dao/internal"
 )
 
@@ -23,3 +26,7 @@ type toogoPlanDaoImpl struct {
 	internalToogoPlanDao
 }
 
+// Ctx 创建模型，按租户隔离
+func (d *toogoPlanDaoImpl) Ctx(ctx context.Context) *gdb.Model {
+	return tenantScope(d.internalToogoPlanDao.Ctx(ctx))
+}
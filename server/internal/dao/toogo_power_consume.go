@@ -5,6 +5,9 @@
 package dao
 
 import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
 	"hotgo/internal/dao/internal"
 )
 
@@ -23,3 +26,7 @@ type toogoPowerConsumeDaoImpl struct {
 	internalToogoPowerConsumeDao
 }
 
+// Ctx 创建模型，按用户所属租户隔离
+func (d *toogoPowerConsumeDaoImpl) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.internalToogoPowerConsumeDao.Ctx(ctx))
+}
@@ -0,0 +1,32 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"hotgo/internal/dao/internal"
+)
+
+// internalToogoSubscriptionLogDao is internal type for wrapping internal DAO implements.
+type internalToogoSubscriptionLogDao = *internal.ToogoSubscriptionLogDao
+
+// toogoSubscriptionLogDao is the data access object for table hg_toogo_subscription_log.
+var toogoSubscriptionLogDao = &toogoSubscriptionLogDaoImpl{
+	internal.NewToogoSubscriptionLogDao(),
+}
+
+// ToogoSubscriptionLog is the manager for table hg_toogo_subscription_log.
+var ToogoSubscriptionLog = toogoSubscriptionLogDao
+
+type toogoSubscriptionLogDaoImpl struct {
+	internalToogoSubscriptionLogDao
+}
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d *toogoSubscriptionLogDaoImpl) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.internalToogoSubscriptionLogDao.Ctx(ctx))
+}
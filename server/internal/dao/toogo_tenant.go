@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"hotgo/internal/dao/internal"
+)
+
+// toogoTenantDao is the data access object for the table hg_toogo_tenant.
+// You can define custom methods on it to extend its functionality as needed.
+type toogoTenantDao struct {
+	*internal.ToogoTenantDao
+}
+
+var (
+	// ToogoTenant is a globally accessible object for table hg_toogo_tenant operations.
+	ToogoTenant = toogoTenantDao{internal.NewToogoTenantDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,32 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"hotgo/internal/dao/internal"
+)
+
+// internalToogoUserRelationDao is internal type for wrapping internal DAO implements.
+type internalToogoUserRelationDao = *internal.ToogoUserRelationDao
+
+// toogoUserRelationDao is the data access object for table hg_toogo_user_relation.
+var toogoUserRelationDao = &toogoUserRelationDaoImpl{
+	internal.NewToogoUserRelationDao(),
+}
+
+// ToogoUserRelation is the manager for table hg_toogo_user_relation.
+var ToogoUserRelation = toogoUserRelationDao
+
+type toogoUserRelationDaoImpl struct {
+	internalToogoUserRelationDao
+}
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d *toogoUserRelationDaoImpl) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.internalToogoUserRelationDao.Ctx(ctx))
+}
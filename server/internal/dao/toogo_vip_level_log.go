@@ -0,0 +1,32 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"hotgo/internal/dao/internal"
+)
+
+// internalToogoVipLevelLogDao is internal type for wrapping internal DAO implements.
+type internalToogoVipLevelLogDao = *internal.ToogoVipLevelLogDao
+
+// toogoVipLevelLogDao is the data access object for table hg_toogo_vip_level_log.
+var toogoVipLevelLogDao = &toogoVipLevelLogDaoImpl{
+	internal.NewToogoVipLevelLogDao(),
+}
+
+// ToogoVipLevelLog is the manager for table hg_toogo_vip_level_log.
+var ToogoVipLevelLog = toogoVipLevelLogDao
+
+type toogoVipLevelLogDaoImpl struct {
+	internalToogoVipLevelLogDao
+}
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d *toogoVipLevelLogDaoImpl) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.internalToogoVipLevelLogDao.Ctx(ctx))
+}
@@ -2,6 +2,9 @@
 package dao
 
 import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
 	"hotgo/internal/dao/internal"
 )
 
@@ -40,3 +43,23 @@ var TradingRobotRealtime = &tradingRobotRealtimeDao{
 type tradingRobotRealtimeDao struct {
 	*internal.TradingRobotRealtimeDao
 }
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d *tradingMarketStateLogDao) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.TradingMarketStateLogDao.Ctx(ctx))
+}
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d *tradingRiskPreferenceLogDao) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.TradingRiskPreferenceLogDao.Ctx(ctx))
+}
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d *tradingDirectionLogDao) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.TradingDirectionLogDao.Ctx(ctx))
+}
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d *tradingRobotRealtimeDao) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.TradingRobotRealtimeDao.Ctx(ctx))
+}
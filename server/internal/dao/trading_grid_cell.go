@@ -0,0 +1,30 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"hotgo/internal/dao/internal"
+)
+
+// tradingGridCellDao is the data access object for the table hg_trading_grid_cell.
+// You can define custom methods on it to extend its functionality as needed.
+type tradingGridCellDao struct {
+	*internal.TradingGridCellDao
+}
+
+var (
+	// TradingGridCell is a globally accessible object for table hg_trading_grid_cell operations.
+	TradingGridCell = tradingGridCellDao{internal.NewTradingGridCellDao()}
+)
+
+// Add your custom methods and functionality below.
+
+// Ctx 创建模型，按机器人所属租户隔离
+func (d tradingGridCellDao) Ctx(ctx context.Context) *gdb.Model {
+	return tenantRobot(d.TradingGridCellDao.Ctx(ctx))
+}
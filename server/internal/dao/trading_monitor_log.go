@@ -5,6 +5,9 @@
 package dao
 
 import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
 	"hotgo/internal/dao/internal"
 )
 
@@ -21,3 +24,7 @@ var (
 
 // Add your custom methods and functionality below.
 
+// Ctx 创建模型，按用户所属租户隔离
+func (d tradingMonitorLogDao) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.TradingMonitorLogDao.Ctx(ctx))
+}
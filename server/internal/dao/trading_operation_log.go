@@ -0,0 +1,30 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"hotgo/internal/dao/internal"
+)
+
+// tradingOperationLogDao is the data access object for the table hg_trading_operation_log.
+// You can define custom methods on it to extend its functionality as needed.
+type tradingOperationLogDao struct {
+	*internal.TradingOperationLogDao
+}
+
+var (
+	// TradingOperationLog is a globally accessible object for table hg_trading_operation_log operations.
+	TradingOperationLog = tradingOperationLogDao{internal.NewTradingOperationLogDao()}
+)
+
+// Add your custom methods and functionality below.
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d tradingOperationLogDao) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.TradingOperationLogDao.Ctx(ctx))
+}
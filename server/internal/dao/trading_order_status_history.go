@@ -5,6 +5,9 @@
 package dao
 
 import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
 	"hotgo/internal/dao/internal"
 )
 
@@ -18,3 +21,7 @@ var (
 	TradingOrderStatusHistory = tradingOrderStatusHistoryDao{internal.NewTradingOrderStatusHistoryDao()}
 )
 
+// Ctx 创建模型，按订单所属租户隔离
+func (d tradingOrderStatusHistoryDao) Ctx(ctx context.Context) *gdb.Model {
+	return tenantOrder(d.TradingOrderStatusHistoryDao.Ctx(ctx))
+}
@@ -0,0 +1,30 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"hotgo/internal/dao/internal"
+)
+
+// tradingRobotBasketDao is the data access object for the table hg_trading_robot_basket.
+// You can define custom methods on it to extend its functionality as needed.
+type tradingRobotBasketDao struct {
+	*internal.TradingRobotBasketDao
+}
+
+var (
+	// TradingRobotBasket is a globally accessible object for table hg_trading_robot_basket operations.
+	TradingRobotBasket = tradingRobotBasketDao{internal.NewTradingRobotBasketDao()}
+)
+
+// Add your custom methods and functionality below.
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d tradingRobotBasketDao) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.TradingRobotBasketDao.Ctx(ctx))
+}
@@ -5,6 +5,9 @@
 package dao
 
 import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
 	"hotgo/internal/dao/internal"
 )
 
@@ -16,3 +19,8 @@ var TradingRobotRunSession = &tradingRobotRunSessionDao{
 type tradingRobotRunSessionDao struct {
 	*internal.TradingRobotRunSessionDao
 }
+
+// Ctx 创建模型，按用户所属租户隔离
+func (d tradingRobotRunSessionDao) Ctx(ctx context.Context) *gdb.Model {
+	return tenantMember(d.TradingRobotRunSessionDao.Ctx(ctx))
+}
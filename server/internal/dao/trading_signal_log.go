@@ -0,0 +1,30 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"hotgo/internal/dao/internal"
+)
+
+// tradingSignalLogDao is the data access object for the table hg_trading_signal_log.
+// You can define custom methods on it to extend its functionality as needed.
+type tradingSignalLogDao struct {
+	*internal.TradingSignalLogDao
+}
+
+var (
+	// TradingSignalLog is a globally accessible object for table hg_trading_signal_log operations.
+	TradingSignalLog = tradingSignalLogDao{internal.NewTradingSignalLogDao()}
+)
+
+// Add your custom methods and functionality below.
+
+// Ctx 创建模型，按机器人所属租户隔离
+func (d tradingSignalLogDao) Ctx(ctx context.Context) *gdb.Model {
+	return tenantRobot(d.TradingSignalLogDao.Ctx(ctx))
+}
@@ -0,0 +1,30 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"hotgo/internal/dao/internal"
+)
+
+// tradingStrategyGroupDao is the data access object for the table hg_trading_strategy_group.
+// You can define custom methods on it to extend its functionality as needed.
+type tradingStrategyGroupDao struct {
+	*internal.TradingStrategyGroupDao
+}
+
+var (
+	// TradingStrategyGroup is a globally accessible object for table hg_trading_strategy_group operations.
+	TradingStrategyGroup = tradingStrategyGroupDao{internal.NewTradingStrategyGroupDao()}
+)
+
+// Add your custom methods and functionality below.
+
+// Ctx 创建模型，按租户隔离
+func (d tradingStrategyGroupDao) Ctx(ctx context.Context) *gdb.Model {
+	return tenantScope(d.TradingStrategyGroupDao.Ctx(ctx))
+}
@@ -12,7 +12,9 @@ import (
 	"hotgo/internal/consts"
 	"hotgo/internal/library/hgrds/lock"
 	"hotgo/internal/library/hgrds/pubsub"
+	hubws "hotgo/internal/library/websocket"
 	"hotgo/internal/service"
+	"hotgo/internal/websocket"
 	"hotgo/utility/simple"
 )
 
@@ -26,6 +28,8 @@ func SubscribeClusterSync(ctx context.Context) {
 		consts.ClusterSyncSysconfig:     service.SysConfig().ClusterSync,             // 系统配置
 		consts.ClusterSyncSysBlacklist:  service.SysBlacklist().ClusterSync,          // 系统黑名单
 		consts.ClusterSyncSysSuperAdmin: service.AdminMember().ClusterSyncSuperAdmin, // 超管
+		consts.ClusterSyncWebsocket:     websocket.ClusterSync,                       // websocket跨节点推送
+		consts.ClusterSyncWebsocketHub:  hubws.ClusterSync,                           // toogo websocket hub跨节点推送
 	})
 
 	if err != nil {
@@ -106,11 +106,16 @@ func GetDeptType(ctx context.Context) string {
 }
 
 // GetTenantId 获取租户ID
+// 租户账号即租户本身，其他账号取登录时绑定的租户，0为平台
 func GetTenantId(ctx context.Context) int64 {
 	if IsTenantDept(ctx) {
 		return GetUserId(ctx)
 	}
-	return 0
+	user := GetUser(ctx)
+	if user == nil {
+		return 0
+	}
+	return user.TenantId
 }
 
 // IsCompanyDept 是否为公司部门
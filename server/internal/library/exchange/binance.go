@@ -508,6 +508,12 @@ func (b *Binance) getHttpClient() *gclient.Client {
 	client := gclient.New()
 	client.SetTimeout(15 * time.Second)
 
+	// 代理池：按API Key粘性选择出口，连接失败自动切换
+	if b.config.ProxyBinding != nil {
+		client.Transport = GetProxyPool().Transport(b.config.ProxyBinding)
+		return client
+	}
+
 	// 配置代理
 	if b.config.Proxy != nil && b.config.Proxy.Enabled {
 		proxyAddr := b.config.Proxy.Host + ":" + strconv.Itoa(b.config.Proxy.Port)
@@ -528,6 +534,11 @@ func (b *Binance) getHttpClient() *gclient.Client {
 
 // publicRequest 公开请求
 func (b *Binance) publicRequest(ctx context.Context, method, path string, params map[string]string) (string, error) {
+	ctx, call, err := acquireRequest(ctx, b.config, method, path, params)
+	if err != nil {
+		return "", err
+	}
+	defer call.End()
 	client := b.getHttpClient()
 
 	reqUrl := b.endpoint + path
@@ -536,18 +547,19 @@ func (b *Binance) publicRequest(ctx context.Context, method, path string, params
 	}
 
 	var resp *gclient.Response
-	var err error
 	if method == "GET" {
 		resp, err = client.Get(ctx, reqUrl)
 	} else {
 		resp, err = client.Post(ctx, reqUrl)
 	}
 	if err != nil {
+		call.Fail(err)
 		return "", gerror.Wrap(err, "Request failed")
 	}
 	defer resp.Close()
 
 	body := resp.ReadAllString()
+	call.Observe(resp.StatusCode, resp.Header, body)
 	if resp.StatusCode != 200 {
 		return "", WrapAsAPIError("binance", resp.StatusCode, body, nil)
 	}
@@ -556,9 +568,19 @@ func (b *Binance) publicRequest(ctx context.Context, method, path string, params
 
 // signedRequest 签名请求
 func (b *Binance) signedRequest(ctx context.Context, method, path string, params map[string]string) (string, error) {
+	return b.signedRequestTo(ctx, b.endpoint, method, path, params)
+}
+
+// signedRequestTo 向指定域名发起签名请求（账户类接口在现货域名 api.binance.com 下）
+func (b *Binance) signedRequestTo(ctx context.Context, endpoint, method, path string, params map[string]string) (string, error) {
 	if params == nil {
 		params = make(map[string]string)
 	}
+	ctx, call, err := acquireRequest(ctx, b.config, method, path, params)
+	if err != nil {
+		return "", err
+	}
+	defer call.End()
 	params["timestamp"] = strconv.FormatInt(time.Now().UnixMilli(), 10)
 	params["recvWindow"] = "5000"
 
@@ -569,10 +591,9 @@ func (b *Binance) signedRequest(ctx context.Context, method, path string, params
 	client := b.getHttpClient()
 	client.SetHeader("X-MBX-APIKEY", b.config.ApiKey)
 
-	reqUrl := b.endpoint + path + "?" + query
+	reqUrl := endpoint + path + "?" + query
 
 	var resp *gclient.Response
-	var err error
 	switch method {
 	case "GET":
 		resp, err = client.Get(ctx, reqUrl)
@@ -585,11 +606,13 @@ func (b *Binance) signedRequest(ctx context.Context, method, path string, params
 	}
 
 	if err != nil {
+		call.Fail(err)
 		return "", gerror.Wrap(err, "Request failed")
 	}
 	defer resp.Close()
 
 	body := resp.ReadAllString()
+	call.Observe(resp.StatusCode, resp.Header, body)
 	if resp.StatusCode != 200 {
 		return "", WrapAsAPIError("binance", resp.StatusCode, body, nil)
 	}
@@ -690,6 +713,36 @@ func (b *Binance) GetOrderHistory(ctx context.Context, symbol string, limit int)
 	return orders, nil
 }
 
+// GetOrder 按订单ID查询单个订单
+func (b *Binance) GetOrder(ctx context.Context, symbol, orderId string) (*Order, error) {
+	params := map[string]string{
+		"symbol":  b.formatSymbol(symbol),
+		"orderId": orderId,
+	}
+	resp, err := b.signedRequest(ctx, "GET", "/fapi/v1/order", params)
+	if err != nil {
+		return nil, err
+	}
+
+	j := gjson.New(resp)
+	return &Order{
+		OrderId:      j.Get("orderId").String(),
+		ClientId:     j.Get("clientOrderId").String(),
+		Symbol:       j.Get("symbol").String(),
+		Side:         j.Get("side").String(),
+		PositionSide: j.Get("positionSide").String(),
+		Type:         j.Get("type").String(),
+		ReduceOnly:   j.Get("reduceOnly").Bool(),
+		Price:        j.Get("price").Float64(),
+		Quantity:     j.Get("origQty").Float64(),
+		FilledQty:    j.Get("executedQty").Float64(),
+		AvgPrice:     j.Get("avgPrice").Float64(),
+		Status:       j.Get("status").String(),
+		CreateTime:   j.Get("time").Int64(),
+		UpdateTime:   j.Get("updateTime").Int64(),
+	}, nil
+}
+
 // GetTradeHistory 获取成交记录（用于财务对账/已实现盈亏/手续费汇总）
 // Binance USDT 永续：GET /fapi/v1/userTrades
 // 注意：该接口返回的是“成交(fill)”级别数据，包含 realizedPnl 与 commission。
@@ -133,6 +133,7 @@ func (s *BinancePrivateStream) Start(ctx context.Context) error {
 	cfg.URL = s.wsBase + "/ws/" + lk
 	cfg.PingInterval = 3 * time.Minute
 	cfg.ProxyDialer = s.proxyDialer
+	cfg.Platform, cfg.Stream = "binance", "private"
 
 	s.conn = NewWebSocketConnection(cfg)
 	s.conn.SetCallbacks(s.onMessage, s.onConnected, s.onDisconnected)
@@ -76,6 +76,7 @@ func (b *BinanceWebSocket) Start(ctx context.Context) error {
 	config.URL = BinanceWSFuturesURL
 	config.PingInterval = 3 * time.Minute // Binance的心跳间隔较长
 	config.ProxyDialer = proxyDialer      // 设置代理
+	config.Platform, config.Stream = "binance", "public"
 
 	b.conn = NewWebSocketConnection(config)
 	b.conn.SetCallbacks(b.onMessage, b.onConnected, b.onDisconnected)
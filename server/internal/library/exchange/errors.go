@@ -68,8 +68,8 @@ func (e *APIError) IsRateLimitError() bool {
 
 // IsIPBannedError 判断是否为IP封禁错误
 func (e *APIError) IsIPBannedError() bool {
-	// HTTP 403 + 特定错误码
-	if e.StatusCode == 403 {
+	// HTTP 403 + 特定错误码；Binance 以 418 表示IP已被自动封禁
+	if e.StatusCode == 403 || e.StatusCode == 418 {
 		return true
 	}
 
@@ -8,6 +8,7 @@ package exchange
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/gogf/gf/v2/errors/gerror"
 )
@@ -70,6 +71,41 @@ type ExchangeAdvanced interface {
 	GetTradeHistory(ctx context.Context, symbol string, limit int) ([]*Trade, error)
 }
 
+// OrderQuerier 可选接口：按订单ID查询单个订单（含已成交/已撤销）
+type OrderQuerier interface {
+	GetOrder(ctx context.Context, symbol, orderId string) (*Order, error)
+}
+
+// QueryOrder 按订单ID查询订单；交易所未实现 OrderQuerier 时退回到最近的历史订单中查找
+func QueryOrder(ctx context.Context, ex Exchange, symbol, orderId string) (*Order, error) {
+	if querier, ok := ex.(OrderQuerier); ok {
+		return querier.GetOrder(ctx, symbol, orderId)
+	}
+	list, err := ex.GetOrderHistory(ctx, symbol, 100)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range list {
+		if o != nil && o.OrderId == orderId {
+			return o, nil
+		}
+	}
+	return nil, gerror.Newf("order does not exist: orderId=%s", orderId)
+}
+
+// IsOrderNotFound 判断是否为“订单不存在”错误
+// Binance 会归档超过3天且无成交的撤单，查询时返回 -2013；OKX 为 51603；Gate 为 ORDER_NOT_FOUND
+func IsOrderNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "order does not exist") ||
+		strings.Contains(msg, "-2013") ||
+		strings.Contains(msg, "51603") ||
+		strings.Contains(msg, "order_not_found")
+}
+
 // Config 交易所配置
 type Config struct {
 	Platform   string       `json:"platform"`   // 平台: binance, okx, gate
@@ -78,6 +114,25 @@ type Config struct {
 	Passphrase string       `json:"passphrase"` // Passphrase (OKX需要)
 	IsTestnet  bool         `json:"isTestnet"`  // 是否测试网
 	Proxy      *ProxyConfig `json:"proxy"`      // 代理配置
+	// ProxyBinding 代理池绑定，设置后REST请求经代理池出口（优先于 Proxy）
+	ProxyBinding *ProxyBinding `json:"proxyBinding"`
+}
+
+// String 脱敏输出，避免打印配置时泄露密钥
+func (c Config) String() string {
+	masked := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return "****"
+	}
+	return fmt.Sprintf("Config{Platform:%s, ApiKey:%s, SecretKey:%s, Passphrase:%s, IsTestnet:%v, Proxy:%v}",
+		c.Platform, masked(c.ApiKey), masked(c.SecretKey), masked(c.Passphrase), c.IsTestnet, c.ProxyBinding != nil || (c.Proxy != nil && c.Proxy.Enabled))
+}
+
+// GoString 脱敏输出，覆盖 %#v
+func (c Config) GoString() string {
+	return c.String()
 }
 
 // ProxyConfig 代理配置
@@ -312,18 +367,13 @@ type Trade struct {
 	Time            int64   `json:"time"`            // 成交时间
 }
 
-// NewExchange 创建交易所实例
+// NewExchange 创建交易所实例（按已注册的交易所驱动）
 func NewExchange(config *Config) (Exchange, error) {
-	switch config.Platform {
-	case "binance":
-		return NewBinance(config), nil
-	case "okx":
-		return NewOKX(config), nil
-	case "gate":
-		return NewGate(config), nil
-	default:
-		return nil, gerror.Newf("不支持的交易所: %s", config.Platform)
+	d, err := mustDriver(config.Platform)
+	if err != nil {
+		return nil, err
 	}
+	return d.NewExchange(config)
 }
 
 // FormatSymbol 格式化交易对
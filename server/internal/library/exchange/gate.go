@@ -88,7 +88,9 @@ func (gt *Gate) GetContractMultiplier(ctx context.Context, symbol string) (float
 func (gt *Gate) getHttpClient() *gclient.Client {
 	client := gclient.New()
 	client.SetTimeout(20 * time.Second)
-	if gt.config.Proxy != nil && gt.config.Proxy.Enabled {
+	if gt.config.ProxyBinding != nil {
+		client.Transport = GetProxyPool().Transport(gt.config.ProxyBinding)
+	} else if gt.config.Proxy != nil && gt.config.Proxy.Enabled {
 		client.SetProxy(gt.config.Proxy.GetProxyURL())
 	}
 	return client
@@ -142,8 +144,12 @@ func (gt *Gate) sign(method, requestPath, queryString, body, timestamp string) s
 }
 
 func (gt *Gate) signedRequest(ctx context.Context, method, path string, query url.Values, body any) (string, error) {
-	// 限流：统一通过全局 RateLimiter 控制请求速率，避免 Gate 429/风控封禁
-	_ = WaitForRateLimit(ctx, "gate")
+	// 限流：统一通过请求调度器控制额度，避免 Gate 429/风控封禁
+	ctx, call, err := acquireRequest(ctx, gt.config, method, path, nil)
+	if err != nil {
+		return "", err
+	}
+	defer call.End()
 
 	requestPath := "/api/v4" + path
 	queryString := ""
@@ -176,7 +182,6 @@ func (gt *Gate) signedRequest(ctx context.Context, method, path string, query ur
 	}
 
 	var resp *gclient.Response
-	var err error
 	switch strings.ToUpper(method) {
 	case "GET":
 		resp, err = client.Get(ctx, reqURL)
@@ -188,11 +193,13 @@ func (gt *Gate) signedRequest(ctx context.Context, method, path string, query ur
 		resp, err = client.Get(ctx, reqURL)
 	}
 	if err != nil {
+		call.Fail(err)
 		return "", gerror.Wrap(err, "Gate request failed")
 	}
 	defer resp.Close()
 
 	raw := resp.ReadAllString()
+	call.Observe(resp.StatusCode, resp.Header, raw)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return "", gerror.Wrapf(WrapAsAPIError("gate", resp.StatusCode, raw, nil), "[gate] http status=%d path=%s", resp.StatusCode, requestPath)
 	}
@@ -220,7 +227,11 @@ func gateCheckBizError(raw string) error {
 
 func (gt *Gate) publicRequest(ctx context.Context, path string, query url.Values) (string, error) {
 	// 限流：公共接口也纳入限流（避免全局行情/多机器人同时拉取造成风控）
-	_ = WaitForRateLimit(ctx, "gate")
+	ctx, call, err := acquireRequest(ctx, gt.config, "GET", path, nil)
+	if err != nil {
+		return "", err
+	}
+	defer call.End()
 
 	requestPath := "/api/v4" + path
 	reqURL := gt.endpoint + requestPath
@@ -230,10 +241,12 @@ func (gt *Gate) publicRequest(ctx context.Context, path string, query url.Values
 	client := gt.getHttpClient()
 	resp, err := client.Get(ctx, reqURL)
 	if err != nil {
+		call.Fail(err)
 		return "", gerror.Wrap(err, "Gate request failed")
 	}
 	defer resp.Close()
 	raw := resp.ReadAllString()
+	call.Observe(resp.StatusCode, resp.Header, raw)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return "", gerror.Wrapf(WrapAsAPIError("gate", resp.StatusCode, raw, nil), "[gate] http status=%d path=%s", resp.StatusCode, requestPath)
 	}
@@ -951,6 +964,66 @@ func (gt *Gate) GetOrderHistory(ctx context.Context, symbol string, limit int) (
 	return out, nil
 }
 
+// GetOrder 按订单ID查询单个订单
+// Gate 已结束订单的 status 统一为 finished，这里按 finish_as 区分成交与撤单，撤单返回 cancelled
+func (gt *Gate) GetOrder(ctx context.Context, symbol, orderId string) (*Order, error) {
+	contract := gt.formatContract(symbol)
+	mul, _ := gt.getMultiplier(ctx, contract)
+	if mul <= 0 {
+		mul = 1
+	}
+	raw, err := gt.signedRequest(ctx, "GET", "/futures/usdt/orders/"+url.PathEscape(orderId), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if bizErr := gateCheckBizError(raw); bizErr != nil {
+		return nil, bizErr
+	}
+
+	j := gjson.New(raw)
+	sizeContracts := j.Get("size").Float64()
+	qtyContractsAbs := absFloat(sizeContracts)
+	filledContracts := qtyContractsAbs - absFloat(j.Get("left").Float64())
+	if filledContracts < 0 {
+		filledContracts = 0
+	}
+	side := "BUY"
+	if sizeContracts < 0 {
+		side = "SELL"
+	}
+	typ := "LIMIT"
+	if j.Get("price").String() == "0" {
+		typ = "MARKET"
+	}
+	posSide := strings.ToUpper(strings.TrimSpace(j.Get("pos_side").String()))
+	if posSide == "" {
+		posSide = strings.ToUpper(strings.TrimSpace(j.Get("position_side").String()))
+	}
+	if posSide != "LONG" && posSide != "SHORT" {
+		posSide = ""
+	}
+	status := j.Get("status").String()
+	if strings.EqualFold(status, "finished") && !strings.EqualFold(j.Get("finish_as").String(), "filled") {
+		status = "cancelled"
+	}
+	return &Order{
+		OrderId:      j.Get("id").String(),
+		ClientId:     j.Get("text").String(),
+		Symbol:       symbol,
+		Side:         side,
+		PositionSide: posSide,
+		Type:         typ,
+		ReduceOnly:   j.Get("is_reduce_only").Bool() || j.Get("reduce_only").Bool(),
+		Price:        j.Get("price").Float64(),
+		Quantity:     qtyContractsAbs * mul,
+		FilledQty:    filledContracts * mul,
+		AvgPrice:     j.Get("fill_price").Float64(),
+		Status:       status,
+		CreateTime:   j.Get("create_time").Int64() * 1000,
+		UpdateTime:   j.Get("finish_time").Int64() * 1000,
+	}, nil
+}
+
 // GetTradeHistory 获取成交记录（用于财务对账/已实现盈亏/手续费汇总）
 // Gate v4 futures: GET /futures/usdt/my_trades
 func (gt *Gate) GetTradeHistory(ctx context.Context, symbol string, limit int) ([]*Trade, error) {
@@ -108,6 +108,7 @@ func (s *GatePrivateStream) Start(ctx context.Context) error {
 	cfg.URL = GateWSFuturesUSDTURL
 	cfg.PingInterval = 20 * time.Second
 	cfg.ProxyDialer = proxyDialer
+	cfg.Platform, cfg.Stream = "gate", "private"
 
 	s.conn = NewWebSocketConnection(cfg)
 	s.conn.SetCallbacks(s.onMessage, s.onConnected, s.onDisconnected)
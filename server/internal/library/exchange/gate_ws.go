@@ -80,6 +80,7 @@ func (gt *GateWebSocket) Start(ctx context.Context) error {
 	cfg.URL = GateWSFuturesUSDTURL
 	cfg.PingInterval = 20 * time.Second
 	cfg.ProxyDialer = proxyDialer
+	cfg.Platform, cfg.Stream = "gate", "public"
 
 	gt.conn = NewWebSocketConnection(cfg)
 	gt.conn.SetCallbacks(gt.onMessage, gt.onConnected, gt.onDisconnected)
@@ -0,0 +1,145 @@
+// Package exchange
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description API Key 权限与IP限制检测
+package exchange
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+// PermState 权限状态，取值与 hg_trading_api_config.perm_* 字段一致
+type PermState int
+
+const (
+	PermDisabled PermState = 0 // 未开启
+	PermEnabled  PermState = 1 // 已开启
+	PermUnknown  PermState = 2 // 交易所未提供，无法确认
+)
+
+// KeyPermissions API Key 权限信息
+type KeyPermissions struct {
+	Read         PermState `json:"read"`         // 读取
+	Futures      PermState `json:"futures"`      // 合约交易
+	Withdraw     PermState `json:"withdraw"`     // 提现
+	IpRestricted bool      `json:"ipRestricted"` // 是否绑定IP白名单
+	IpWhitelist  []string  `json:"ipWhitelist"`  // IP白名单（交易所提供时）
+	ExpireAt     int64     `json:"expireAt"`     // 过期时间（毫秒），0=不过期或未知
+}
+
+// KeyInspector 可选接口：查询 API Key 自身的权限配置
+type KeyInspector interface {
+	GetKeyPermissions(ctx context.Context) (*KeyPermissions, error)
+}
+
+// InspectKey 查询交易所实例所用 API Key 的权限
+func InspectKey(ctx context.Context, ex Exchange) (*KeyPermissions, error) {
+	inspector, ok := ex.(KeyInspector)
+	if !ok {
+		return nil, gerror.Newf("交易所[%s]暂不支持API权限检测", ex.GetName())
+	}
+	return inspector.GetKeyPermissions(ctx)
+}
+
+func boolPerm(v bool) PermState {
+	if v {
+		return PermEnabled
+	}
+	return PermDisabled
+}
+
+func splitIpList(s string) []string {
+	var ips []string
+	for _, ip := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == ';' }) {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+// GetKeyPermissions Binance：/sapi/v1/account/apiRestrictions（现货域名，测试网不支持）
+func (b *Binance) GetKeyPermissions(ctx context.Context) (*KeyPermissions, error) {
+	if b.config.IsTestnet {
+		return nil, gerror.New("Binance测试网不支持API权限查询")
+	}
+	raw, err := b.signedRequestTo(ctx, "https://api.binance.com", "GET", "/sapi/v1/account/apiRestrictions", nil)
+	if err != nil {
+		return nil, err
+	}
+	j := gjson.New(raw)
+	return &KeyPermissions{
+		Read:         boolPerm(j.Get("enableReading").Bool()),
+		Futures:      boolPerm(j.Get("enableFutures").Bool()),
+		Withdraw:     boolPerm(j.Get("enableWithdrawals").Bool()),
+		IpRestricted: j.Get("ipRestrict").Bool(),
+		ExpireAt:     j.Get("tradingAuthorityExpirationTime").Int64(),
+	}, nil
+}
+
+// GetKeyPermissions OKX：/api/v5/account/config 的 perm（read_only,trade,withdraw）与 ip 字段
+// 账户模式为简单交易模式（acctLv=1）时不能交易永续合约
+func (o *OKX) GetKeyPermissions(ctx context.Context) (*KeyPermissions, error) {
+	raw, err := o.signedRequest(ctx, "GET", "/api/v5/account/config", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	data := gjson.New(raw).Get("data").Array()
+	if len(data) == 0 {
+		return nil, gerror.New("OKX账户配置为空")
+	}
+	j := gjson.New(data[0])
+
+	perms := make(map[string]bool)
+	for _, p := range strings.Split(j.Get("perm").String(), ",") {
+		perms[strings.TrimSpace(p)] = true
+	}
+	ips := splitIpList(j.Get("ip").String())
+	return &KeyPermissions{
+		Read:         boolPerm(perms["read_only"] || perms["trade"]),
+		Futures:      boolPerm(perms["trade"] && j.Get("acctLv").String() != "1"),
+		Withdraw:     boolPerm(perms["withdraw"]),
+		IpRestricted: len(ips) > 0,
+		IpWhitelist:  ips,
+	}, nil
+}
+
+// GetKeyPermissions Gate：/account/detail 提供IP白名单；Gate 不提供权限查询接口，
+// 合约权限以合约账户可访问为准，提现权限无法确认
+func (gt *Gate) GetKeyPermissions(ctx context.Context) (*KeyPermissions, error) {
+	raw, err := gt.signedRequest(ctx, "GET", "/account/detail", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if bizErr := gateCheckBizError(raw); bizErr != nil {
+		return nil, bizErr
+	}
+	var ips []string
+	for _, ip := range gjson.New(raw).Get("ip_whitelist").Strings() {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+
+	perm := &KeyPermissions{
+		Read:         PermEnabled,
+		Futures:      PermEnabled,
+		Withdraw:     PermUnknown,
+		IpRestricted: len(ips) > 0,
+		IpWhitelist:  ips,
+	}
+	// 合约账户未创建（USER_NOT_FOUND）不代表无权限；网络等临时错误直接返回，避免误判
+	if _, err = gt.GetBalance(ctx); err != nil && !strings.Contains(err.Error(), "USER_NOT_FOUND") {
+		var apiErr *APIError
+		if !gerror.As(err, &apiErr) || !(apiErr.IsAuthError() || apiErr.StatusCode == 403) {
+			return nil, err
+		}
+		perm.Futures = PermDisabled
+	}
+	return perm, nil
+}
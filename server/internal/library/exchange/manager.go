@@ -10,7 +10,9 @@ import (
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"hotgo/internal/dao"
+	"hotgo/internal/library/vault"
 	"hotgo/internal/model/entity"
+	"strings"
 	"sync"
 )
 
@@ -43,14 +45,13 @@ func (m *Manager) GetExchange(ctx context.Context, apiConfigId int64) (Exchange,
 		return nil, gerror.Newf("API配置不存在: %d", apiConfigId)
 	}
 
+	config, err := ConfigFromApiConfig(ctx, apiConfig, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	// 创建交易所实例
-	ex, err := NewExchange(&Config{
-		Platform:   apiConfig.Platform,
-		ApiKey:     apiConfig.ApiKey,
-		SecretKey:  apiConfig.SecretKey,
-		Passphrase: apiConfig.Passphrase,
-		IsTestnet:  false, // 默认为主网
-	})
+	ex, err := NewExchange(config)
 	if err != nil {
 		return nil, err
 	}
@@ -62,6 +63,22 @@ func (m *Manager) GetExchange(ctx context.Context, apiConfigId int64) (Exchange,
 	return ex, nil
 }
 
+// ConfigFromApiConfig 根据API配置构建交易所配置，凭证统一经 vault 解密
+func ConfigFromApiConfig(ctx context.Context, apiConfig *entity.TradingApiConfig, proxy *ProxyConfig) (*Config, error) {
+	creds, err := vault.ApiCredentials(ctx, apiConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{
+		Platform:   strings.ToLower(strings.TrimSpace(apiConfig.Platform)),
+		ApiKey:     creds.ApiKey,
+		SecretKey:  creds.SecretKey,
+		Passphrase: creds.Passphrase,
+		IsTestnet:  false, // 默认为主网
+		Proxy:      proxy,
+	}, nil
+}
+
 // RemoveExchange 移除交易所实例 (API配置更新时调用)
 func (m *Manager) RemoveExchange(apiConfigId int64) {
 	m.exchanges.Delete(apiConfigId)
@@ -52,7 +52,9 @@ func (o *OKX) GetName() string { return "okx" }
 func (o *OKX) getHttpClient() *gclient.Client {
 	client := gclient.New()
 	client.SetTimeout(20 * time.Second)
-	if o.config.Proxy != nil && o.config.Proxy.Enabled {
+	if o.config.ProxyBinding != nil {
+		client.Transport = GetProxyPool().Transport(o.config.ProxyBinding)
+	} else if o.config.Proxy != nil && o.config.Proxy.Enabled {
 		client.SetProxy(o.config.Proxy.GetProxyURL())
 	}
 	return client
@@ -123,6 +125,11 @@ func (o *OKX) signedRequest(ctx context.Context, method, path string, query url.
 
 	maxRetries := 1
 	for retry := 0; retry <= maxRetries; retry++ {
+		reqCtx, call, err := acquireRequest(ctx, o.config, method, path, nil)
+		if err != nil {
+			return "", err
+		}
+
 		// OKX 对 OK-ACCESS-TIMESTAMP 的格式较严格，使用毫秒级 RFC3339 更兼容：
 		// e.g. 2025-12-24T12:34:56.789Z
 		tsMs := nowMsWithOffset(o.config)
@@ -138,22 +145,23 @@ func (o *OKX) signedRequest(ctx context.Context, method, path string, query url.
 
 		reqURL := o.endpoint + requestPath
 		var resp *gclient.Response
-		var err error
 		switch strings.ToUpper(method) {
 		case "GET":
-			resp, err = client.Get(ctx, reqURL)
+			resp, err = client.Get(reqCtx, reqURL)
 		case "POST":
-			resp, err = client.Post(ctx, reqURL, bodyStr)
+			resp, err = client.Post(reqCtx, reqURL, bodyStr)
 		default:
-			resp, err = client.Get(ctx, reqURL)
+			resp, err = client.Get(reqCtx, reqURL)
 		}
 		if err != nil {
 			// 网络错误不重试时间同步
+			call.Fail(err)
 			return "", gerror.Wrap(err, "OKX request failed")
 		}
 
 		raw := resp.ReadAllString()
 		status := resp.StatusCode
+		call.Observe(status, resp.Header, raw)
 		resp.Close()
 
 		// http status != 200：尝试识别 timestamp expired（常见 401）
@@ -198,13 +206,19 @@ func (o *OKX) publicRequest(ctx context.Context, path string, query url.Values)
 	if len(query) > 0 {
 		reqURL += "?" + query.Encode()
 	}
+	ctx, call, err := acquireRequest(ctx, o.config, "GET", path, nil)
+	if err != nil {
+		return "", err
+	}
 	client := o.getHttpClient()
 	resp, err := client.Get(ctx, reqURL)
 	if err != nil {
+		call.Fail(err)
 		return "", gerror.Wrap(err, "OKX request failed")
 	}
 	defer resp.Close()
 	raw := resp.ReadAllString()
+	call.Observe(resp.StatusCode, resp.Header, raw)
 	if resp.StatusCode != 200 {
 		return "", gerror.Wrapf(WrapAsAPIError("okx", resp.StatusCode, raw, nil), "[okx] http status=%d path=%s", resp.StatusCode, path)
 	}
@@ -718,6 +732,43 @@ func (o *OKX) GetOrderHistory(ctx context.Context, symbol string, limit int) ([]
 	return out, nil
 }
 
+// GetOrder 按订单ID查询单个订单，数量按 ctVal 由合约张数折算为基础币
+func (o *OKX) GetOrder(ctx context.Context, symbol, orderId string) (*Order, error) {
+	instId := o.formatInstId(symbol)
+	q := url.Values{}
+	q.Set("instId", instId)
+	q.Set("ordId", orderId)
+	raw, err := o.signedRequest(ctx, "GET", "/api/v5/trade/order", q, nil)
+	if err != nil {
+		return nil, err
+	}
+	data := gjson.New(raw).Get("data").Array()
+	if len(data) == 0 {
+		return nil, gerror.Newf("OKX order does not exist: ordId=%s", orderId)
+	}
+	ctVal, err := o.getCtVal(ctx, instId)
+	if err != nil {
+		return nil, err
+	}
+	j := gjson.New(data[0])
+	return &Order{
+		OrderId:      j.Get("ordId").String(),
+		ClientId:     j.Get("clOrdId").String(),
+		Symbol:       symbol,
+		Side:         strings.ToUpper(j.Get("side").String()),
+		PositionSide: strings.ToUpper(j.Get("posSide").String()),
+		Type:         strings.ToUpper(j.Get("ordType").String()),
+		ReduceOnly:   j.Get("reduceOnly").Bool(),
+		Price:        j.Get("px").Float64(),
+		Quantity:     j.Get("sz").Float64() * ctVal,
+		FilledQty:    j.Get("accFillSz").Float64() * ctVal,
+		AvgPrice:     j.Get("avgPx").Float64(),
+		Status:       j.Get("state").String(),
+		CreateTime:   j.Get("cTime").Int64(),
+		UpdateTime:   j.Get("uTime").Int64(),
+	}, nil
+}
+
 // GetTradeHistory 获取成交记录（用于财务对账/已实现盈亏/手续费汇总）
 // OKX V5：GET /api/v5/trade/fills
 func (o *OKX) GetTradeHistory(ctx context.Context, symbol string, limit int) ([]*Trade, error) {
@@ -100,6 +100,7 @@ func (s *OKXPrivateStream) Start(ctx context.Context) error {
 	cfg.URL = OKXWSPrivateURL
 	cfg.PingInterval = 25 * time.Second
 	cfg.ProxyDialer = proxyDialer
+	cfg.Platform, cfg.Stream = "okx", "private"
 
 	s.conn = NewWebSocketConnection(cfg)
 	s.conn.SetCallbacks(s.onMessage, s.onConnected, s.onDisconnected)
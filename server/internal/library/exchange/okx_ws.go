@@ -81,6 +81,7 @@ func (o *OKXWebSocket) Start(ctx context.Context) error {
 	cfg.URL = OKXWSPublicURL
 	cfg.PingInterval = 25 * time.Second
 	cfg.ProxyDialer = proxyDialer
+	cfg.Platform, cfg.Stream = "okx", "public"
 
 	o.conn = NewWebSocketConnection(cfg)
 	o.conn.SetCallbacks(o.onMessage, o.onConnected, o.onDisconnected)
@@ -97,6 +98,7 @@ func (o *OKXWebSocket) Start(ctx context.Context) error {
 	bizCfg.URL = OKXWSBusinessURL
 	bizCfg.PingInterval = 25 * time.Second
 	bizCfg.ProxyDialer = proxyDialer
+	bizCfg.Platform, bizCfg.Stream = "okx", "business"
 	o.klineConn = NewWebSocketConnection(bizCfg)
 	o.klineConn.SetCallbacks(o.onMessage, o.onKlineConnected, o.onKlineDisconnected)
 	if err := o.klineConn.Connect(o.ctx); err != nil {
@@ -54,19 +54,17 @@ type PrivateStreamStatusProvider interface {
 	LastEventAt() time.Time
 }
 
-// NewPrivateStream 创建私有流实例（按交易所平台）
+// NewPrivateStream 创建私有流实例（按已注册的交易所驱动）
 func NewPrivateStream(cfg *Config) (PrivateStream, error) {
 	if cfg == nil {
 		return nil, gerror.New("exchange config is nil")
 	}
-	switch cfg.Platform {
-	case "binance":
-		return NewBinancePrivateStream(cfg), nil
-	case "okx":
-		return NewOKXPrivateStream(cfg), nil
-	case "gate":
-		return NewGatePrivateStream(cfg), nil
-	default:
-		return nil, gerror.Newf("unsupported exchange: %s", cfg.Platform)
+	d, err := mustDriver(cfg.Platform)
+	if err != nil {
+		return nil, err
 	}
+	if d.NewPrivateStream == nil {
+		return nil, gerror.Newf("exchange %s does not provide private stream", cfg.Platform)
+	}
+	return d.NewPrivateStream(cfg)
 }
@@ -0,0 +1,512 @@
+// Package exchange
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 出口代理池：健康检查、加权选择、按API Key粘性分配与连接失败自动切换
+package exchange
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	proxyDialTimeout   = 10 * time.Second // 经代理建立连接超时
+	proxyCheckTimeout  = 8 * time.Second  // 健康检查单个交易所超时
+	proxyFailThreshold = 2                // 连续连接失败次数达到后判为不可用，等待健康检查恢复
+)
+
+// ProxyBinding 代理池绑定：同一 Key 粘性使用同一代理（交易所对频繁切换IP敏感），仅在代理故障时切换
+type ProxyBinding struct {
+	UserId int64  `json:"userId"` // 优先使用该用户的专属代理，无可用时使用全局代理
+	Key    string `json:"key"`    // 粘性分配键，如 api:<apiConfigId>、public
+}
+
+func (b *ProxyBinding) cacheKey() string {
+	return strconv.FormatInt(b.UserId, 10) + "|" + b.Key
+}
+
+// PoolProxy 代理池成员
+type PoolProxy struct {
+	Id     int64        // 代理ID（hg_trading_proxy_config.id）
+	UserId int64        // 0=全局代理
+	Name   string       // 名称
+	Weight int          // 选择权重
+	Proxy  *ProxyConfig // 代理地址与认证
+}
+
+func (p *PoolProxy) address() string {
+	return p.Proxy.Type + "://" + net.JoinHostPort(p.Proxy.Host, strconv.Itoa(p.Proxy.Port))
+}
+
+// ProxyHealth 代理健康检查结果
+type ProxyHealth struct {
+	Id           int64            `json:"id"`
+	Healthy      bool             `json:"healthy"`
+	Latency      int64            `json:"latency"`      // 可达交易所的平均延迟(ms)
+	VenueLatency map[string]int64 `json:"venueLatency"` // 各交易所延迟(ms)，-1=不可达
+	Message      string           `json:"message"`
+	CheckedAt    time.Time        `json:"checkedAt"`
+}
+
+// ProxyMemberStatus 代理池成员运行状态
+type ProxyMemberStatus struct {
+	Id        int64        `json:"id"`
+	UserId    int64        `json:"userId"`
+	Name      string       `json:"name"`
+	Address   string       `json:"address"`
+	Weight    int          `json:"weight"`
+	Healthy   bool         `json:"healthy"`
+	Fails     int          `json:"fails"`     // 连续连接失败次数
+	LastError string       `json:"lastError"` // 最近一次连接失败原因
+	Assigned  int          `json:"assigned"`  // 粘性分配到该代理的绑定数
+	Health    *ProxyHealth `json:"health"`    // 最近一次健康检查
+}
+
+type poolMember struct {
+	*PoolProxy
+	healthy   bool
+	fails     int
+	lastError string
+	health    *ProxyHealth
+}
+
+// ProxyPool 出口代理池
+type ProxyPool struct {
+	mu         sync.Mutex
+	members    map[int64]*poolMember
+	sticky     map[string]int64 // 绑定 → 代理ID
+	transports sync.Map         // 绑定 → *http.Transport
+}
+
+var proxyPool = &ProxyPool{
+	members: make(map[int64]*poolMember),
+	sticky:  make(map[string]int64),
+}
+
+// GetProxyPool 获取出口代理池
+func GetProxyPool() *ProxyPool {
+	return proxyPool
+}
+
+// SetMembers 替换代理池成员，地址未变化的成员保留运行状态
+func (p *ProxyPool) SetMembers(list []*PoolProxy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	members := make(map[int64]*poolMember, len(list))
+	for _, item := range list {
+		if item == nil || item.Proxy == nil {
+			continue
+		}
+		if item.Weight <= 0 {
+			item.Weight = 1
+		}
+		m := &poolMember{PoolProxy: item, healthy: true}
+		if old, ok := p.members[item.Id]; ok && old.address() == item.address() {
+			m.healthy, m.fails, m.lastError, m.health = old.healthy, old.fails, old.lastError, old.health
+		}
+		members[item.Id] = m
+	}
+	p.members = members
+	for key, id := range p.sticky {
+		if _, ok := members[id]; !ok {
+			delete(p.sticky, key)
+		}
+	}
+}
+
+// Len 代理池成员数量
+func (p *ProxyPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.members)
+}
+
+// candidates 可选代理：用户专属代理优先，其次全局代理；均不可用时退化为尝试全部未失败过的代理
+func (p *ProxyPool) candidates(userId int64, exclude map[int64]bool) []*poolMember {
+	scopes := []int64{userId}
+	if userId != 0 {
+		scopes = append(scopes, 0)
+	}
+	var fallback []*poolMember
+	for _, scope := range scopes {
+		var healthy, all []*poolMember
+		for _, m := range p.members {
+			if m.UserId != scope || exclude[m.Id] {
+				continue
+			}
+			all = append(all, m)
+			if m.healthy {
+				healthy = append(healthy, m)
+			}
+		}
+		if len(healthy) > 0 {
+			return healthy
+		}
+		fallback = append(fallback, all...)
+	}
+	return fallback
+}
+
+// pick 选择代理：粘性代理可用时继续使用，否则按 权重/延迟 加权随机重新分配（调用方持有锁）
+func (p *ProxyPool) pick(b *ProxyBinding, exclude map[int64]bool) *poolMember {
+	key := b.cacheKey()
+	prevId, hasPrev := p.sticky[key]
+	if hasPrev && !exclude[prevId] {
+		if m, ok := p.members[prevId]; ok && m.healthy && (m.UserId == b.UserId || m.UserId == 0) {
+			return m
+		}
+	}
+
+	list := p.candidates(b.UserId, exclude)
+	if len(list) == 0 {
+		return nil
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+
+	scores := make([]float64, len(list))
+	total := 0.0
+	for i, m := range list {
+		score := float64(m.Weight)
+		if m.health != nil && m.health.Latency > 0 {
+			score /= 1 + float64(m.health.Latency)/500
+		}
+		scores[i] = score
+		total += score
+	}
+	chosen := list[len(list)-1]
+	r := rand.Float64() * total
+	for i, score := range scores {
+		if r < score {
+			chosen = list[i]
+			break
+		}
+		r -= score
+	}
+
+	p.sticky[key] = chosen.Id
+	if hasPrev && prevId != chosen.Id {
+		g.Log().Warningf(context.Background(), "[ProxyPool] 代理切换: binding=%s, %d -> %d(%s)", key, prevId, chosen.Id, chosen.Name)
+		// 切换出口后丢弃旧代理上的空闲连接，避免同一API Key同时从多个IP发出请求
+		if tr, ok := p.transports.Load(key); ok {
+			go tr.(*http.Transport).CloseIdleConnections()
+		}
+	}
+	return chosen
+}
+
+// DialContext 经代理池建立连接：连接失败时标记代理并切换到下一个可用代理；
+// 代理池为空时直连，代理均不可用时返回错误（不回退直连，避免暴露服务器IP）
+func (p *ProxyPool) DialContext(ctx context.Context, b *ProxyBinding, network, addr string) (net.Conn, error) {
+	tried := make(map[int64]bool)
+	var lastErr error
+	for {
+		p.mu.Lock()
+		m := p.pick(b, tried)
+		p.mu.Unlock()
+
+		if m == nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return (&net.Dialer{Timeout: proxyDialTimeout, KeepAlive: 30 * time.Second}).DialContext(ctx, network, addr)
+		}
+
+		conn, err := dialViaProxy(ctx, m.Proxy, network, addr)
+		if err == nil {
+			p.markSuccess(m)
+			return conn, nil
+		}
+		tried[m.Id] = true
+		lastErr = gerror.Wrapf(err, "代理[%s]连接失败", m.Name)
+		p.markFailure(m, err)
+		if ctx.Err() != nil {
+			return nil, lastErr
+		}
+	}
+}
+
+// Dialer 获取绑定的拨号函数（用于WebSocket）
+func (p *ProxyPool) Dialer(b *ProxyBinding) func(network, addr string) (net.Conn, error) {
+	return p.NetDialer(b).Dial
+}
+
+// NetDialer 获取绑定的拨号器，实现 proxy.Dialer / proxy.ContextDialer
+func (p *ProxyPool) NetDialer(b *ProxyBinding) *PoolDialer {
+	return &PoolDialer{pool: p, binding: b}
+}
+
+// Transport 获取绑定的HTTP传输层（按绑定复用连接）
+func (p *ProxyPool) Transport(b *ProxyBinding) *http.Transport {
+	key := b.cacheKey()
+	if tr, ok := p.transports.Load(key); ok {
+		return tr.(*http.Transport)
+	}
+	tr, _ := p.transports.LoadOrStore(key, &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return p.DialContext(ctx, b, network, addr)
+		},
+		ForceAttemptHTTP2:   true,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	})
+	return tr.(*http.Transport)
+}
+
+// Egress 绑定当前使用的出口（用于按出口IP统计请求额度），未使用代理时为 direct
+func (p *ProxyPool) Egress(b *ProxyBinding) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if m := p.pick(b, nil); m != nil {
+		return m.address()
+	}
+	return "direct"
+}
+
+func (p *ProxyPool) markSuccess(m *poolMember) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m.fails = 0
+	m.healthy = true
+}
+
+func (p *ProxyPool) markFailure(m *poolMember, err error) {
+	p.mu.Lock()
+	m.fails++
+	m.lastError = err.Error()
+	down := m.healthy && m.fails >= proxyFailThreshold
+	if down {
+		m.healthy = false
+	}
+	p.mu.Unlock()
+
+	if down {
+		g.Log().Warningf(context.Background(), "[ProxyPool] 代理不可用，已切换: id=%d, name=%s, err=%v", m.Id, m.Name, err)
+	}
+}
+
+// Check 健康检查：经每个代理连接各交易所REST地址并测量延迟，任一交易所可达即视为可用
+func (p *ProxyPool) Check(ctx context.Context) []*ProxyHealth {
+	p.mu.Lock()
+	members := make([]*poolMember, 0, len(p.members))
+	for _, m := range p.members {
+		members = append(members, m)
+	}
+	p.mu.Unlock()
+
+	venues := make(map[string]string)
+	for _, d := range Drivers() {
+		if u, err := url.Parse(d.BaseUrl); err == nil && u.Hostname() != "" {
+			port := u.Port()
+			if port == "" {
+				port = "443"
+			}
+			venues[d.Platform] = net.JoinHostPort(u.Hostname(), port)
+		}
+	}
+
+	results := make([]*ProxyHealth, len(members))
+	var wg sync.WaitGroup
+	for i, m := range members {
+		wg.Add(1)
+		go func(i int, m *poolMember) {
+			defer wg.Done()
+			results[i] = checkProxy(ctx, m.Proxy, venues)
+			results[i].Id = m.Id
+		}(i, m)
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	for i, m := range members {
+		res := results[i]
+		recovered := res.Healthy && !m.healthy
+		m.health = res
+		m.healthy = res.Healthy
+		if res.Healthy {
+			m.fails = 0
+		} else {
+			m.lastError = res.Message
+		}
+		if recovered {
+			g.Log().Infof(ctx, "[ProxyPool] 代理恢复可用: id=%d, name=%s, latency=%dms", m.Id, m.Name, res.Latency)
+		}
+	}
+	p.mu.Unlock()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Id < results[j].Id })
+	return results
+}
+
+// Status 代理池运行状态
+func (p *ProxyPool) Status() []*ProxyMemberStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	assigned := make(map[int64]int)
+	for _, id := range p.sticky {
+		assigned[id]++
+	}
+	list := make([]*ProxyMemberStatus, 0, len(p.members))
+	for _, m := range p.members {
+		list = append(list, &ProxyMemberStatus{
+			Id:        m.Id,
+			UserId:    m.UserId,
+			Name:      m.Name,
+			Address:   m.address(),
+			Weight:    m.Weight,
+			Healthy:   m.healthy,
+			Fails:     m.fails,
+			LastError: m.lastError,
+			Assigned:  assigned[m.Id],
+			Health:    m.health,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+	return list
+}
+
+// PoolDialer 代理池拨号器
+type PoolDialer struct {
+	pool    *ProxyPool
+	binding *ProxyBinding
+}
+
+// Dial 建立连接
+func (d *PoolDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.pool.DialContext(context.Background(), d.binding, network, addr)
+}
+
+// DialContext 建立连接
+func (d *PoolDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return d.pool.DialContext(ctx, d.binding, network, addr)
+}
+
+func checkProxy(ctx context.Context, pc *ProxyConfig, venues map[string]string) *ProxyHealth {
+	res := &ProxyHealth{VenueLatency: make(map[string]int64, len(venues)), CheckedAt: time.Now()}
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failed   []string
+		sum, cnt int64
+	)
+	for platform, target := range venues {
+		wg.Add(1)
+		go func(platform, target string) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, proxyCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			conn, err := dialViaProxy(checkCtx, pc, "tcp", target)
+			latency := time.Since(start).Milliseconds()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				res.VenueLatency[platform] = -1
+				failed = append(failed, platform+": "+err.Error())
+				return
+			}
+			_ = conn.Close()
+			res.VenueLatency[platform] = latency
+			sum += latency
+			cnt++
+		}(platform, target)
+	}
+	wg.Wait()
+
+	sort.Strings(failed)
+	res.Healthy = cnt > 0
+	if cnt > 0 {
+		res.Latency = sum / cnt
+	}
+	switch {
+	case len(venues) == 0:
+		res.Message = "未注册交易所"
+	case len(failed) == 0:
+		res.Message = "正常"
+	default:
+		res.Message = "不可达 " + strings.Join(failed, "; ")
+		if len(res.Message) > 480 {
+			res.Message = res.Message[:480]
+		}
+	}
+	return res
+}
+
+// dialViaProxy 经 SOCKS5 或 HTTP CONNECT 代理建立到目标地址的连接
+func dialViaProxy(ctx context.Context, pc *ProxyConfig, network, addr string) (net.Conn, error) {
+	proxyAddr := net.JoinHostPort(pc.Host, strconv.Itoa(pc.Port))
+	forward := &net.Dialer{Timeout: proxyDialTimeout, KeepAlive: 30 * time.Second}
+
+	switch strings.ToLower(pc.Type) {
+	case "socks5":
+		var auth *proxy.Auth
+		if pc.Username != "" {
+			auth = &proxy.Auth{User: pc.Username, Password: pc.Password}
+		}
+		dialer, err := proxy.SOCKS5("tcp", proxyAddr, auth, forward)
+		if err != nil {
+			return nil, err
+		}
+		if cd, ok := dialer.(proxy.ContextDialer); ok {
+			return cd.DialContext(ctx, network, addr)
+		}
+		return dialer.Dial(network, addr)
+
+	case "http", "https":
+		conn, err := forward.DialContext(ctx, "tcp", proxyAddr)
+		if err != nil {
+			return nil, err
+		}
+		deadline := time.Now().Add(proxyDialTimeout)
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+		_ = conn.SetDeadline(deadline)
+
+		req := &http.Request{
+			Method: http.MethodConnect,
+			URL:    &url.URL{Opaque: addr},
+			Host:   addr,
+			Header: make(http.Header),
+		}
+		if pc.Username != "" {
+			req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(pc.Username+":"+pc.Password)))
+		}
+		if err = req.Write(conn); err != nil {
+			_ = conn.Close()
+			return nil, gerror.Wrap(err, "发送CONNECT请求失败")
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+		if err != nil {
+			_ = conn.Close()
+			return nil, gerror.Wrap(err, "读取代理响应失败")
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			_ = conn.Close()
+			return nil, gerror.Newf("HTTP代理CONNECT失败: %s", resp.Status)
+		}
+		_ = conn.SetDeadline(time.Time{})
+		return conn, nil
+	}
+	return nil, gerror.Newf("不支持的代理类型: %s", pc.Type)
+}
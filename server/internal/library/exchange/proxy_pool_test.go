@@ -0,0 +1,136 @@
+// Package exchange
+// @Description 出口代理池测试
+package exchange
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// startConnectProxy 启动一个最简 HTTP CONNECT 代理
+func startConnectProxy(t *testing.T) *ProxyConfig {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				req, err := http.ReadRequest(bufio.NewReader(conn))
+				if err != nil || req.Method != http.MethodConnect {
+					return
+				}
+				upstream, err := net.Dial("tcp", req.Host)
+				if err != nil {
+					_, _ = io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+					return
+				}
+				defer upstream.Close()
+				_, _ = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+				go func() { _, _ = io.Copy(upstream, conn) }()
+				_, _ = io.Copy(conn, upstream)
+			}(conn)
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return &ProxyConfig{Enabled: true, Type: "http", Host: host, Port: p}
+}
+
+// startEcho 启动回显服务作为目标地址
+func startEcho(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func deadProxy(t *testing.T) *ProxyConfig {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	_ = ln.Close()
+	p, _ := strconv.Atoi(port)
+	return &ProxyConfig{Enabled: true, Type: "http", Host: host, Port: p}
+}
+
+func TestProxyPoolFailover(t *testing.T) {
+	target := startEcho(t)
+	pool := &ProxyPool{members: make(map[int64]*poolMember), sticky: make(map[string]int64)}
+	pool.SetMembers([]*PoolProxy{
+		{Id: 1, Name: "dead", Weight: 100, Proxy: deadProxy(t)},
+		{Id: 2, Name: "good", Weight: 1, Proxy: startConnectProxy(t)},
+	})
+	b := &ProxyBinding{UserId: 7, Key: "api:1"}
+
+	for i := 0; i < 3; i++ {
+		conn, err := pool.DialContext(context.Background(), b, "tcp", target)
+		if err != nil {
+			t.Fatalf("DialContext() error = %v", err)
+		}
+		if _, err = conn.Write([]byte("ping")); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 4)
+		if _, err = io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+			t.Fatalf("echo = %q, %v", buf, err)
+		}
+		_ = conn.Close()
+	}
+
+	if id := pool.sticky[b.cacheKey()]; id != 2 {
+		t.Fatalf("sticky = %d, want 2", id)
+	}
+	for _, st := range pool.Status() {
+		if st.Id == 2 && st.Assigned != 1 {
+			t.Fatalf("Status() assigned = %d, want 1", st.Assigned)
+		}
+	}
+}
+
+func TestProxyPoolUserScope(t *testing.T) {
+	pool := &ProxyPool{members: make(map[int64]*poolMember), sticky: make(map[string]int64)}
+	pool.SetMembers([]*PoolProxy{
+		{Id: 1, UserId: 0, Name: "global", Proxy: &ProxyConfig{Type: "socks5", Host: "10.0.0.1", Port: 1080}},
+		{Id: 2, UserId: 7, Name: "user", Proxy: &ProxyConfig{Type: "socks5", Host: "10.0.0.2", Port: 1080}},
+	})
+
+	if got := pool.Egress(&ProxyBinding{UserId: 7, Key: "api:1"}); got != "socks5://10.0.0.2:1080" {
+		t.Fatalf("Egress(user) = %s", got)
+	}
+	if got := pool.Egress(&ProxyBinding{UserId: 8, Key: "api:2"}); got != "socks5://10.0.0.1:1080" {
+		t.Fatalf("Egress(other user) = %s", got)
+	}
+
+	pool.SetMembers(nil)
+	if got := pool.Egress(&ProxyBinding{UserId: 7, Key: "api:1"}); got != "direct" {
+		t.Fatalf("Egress(empty) = %s", got)
+	}
+}
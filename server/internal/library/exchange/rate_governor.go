@@ -0,0 +1,512 @@
+// Package exchange
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 自适应限流：按（平台, API Key, 出口IP/代理）统一调度请求额度
+package exchange
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// Priority 请求优先级，额度紧张时低优先级先让路
+type Priority int
+
+const (
+	PriorityCritical   Priority = iota // 下单/撤单/平仓
+	PriorityNormal                     // 持仓/余额/订单查询、行情
+	PriorityBackground                 // 行情分析轮询
+	PriorityLow                        // 后台成交历史/账单同步（需调用方 WithPriority 显式指定）
+	priorityCount
+)
+
+var priorityNames = [priorityCount]string{"critical", "normal", "background", "low"}
+
+func (p Priority) String() string {
+	if p < 0 || p >= priorityCount {
+		return "unknown"
+	}
+	return priorityNames[p]
+}
+
+// priorityPolicies 各优先级可使用的窗口额度比例与最长排队时间
+// 低优先级不排队：额度不足直接放弃，由缓存/DB兜底
+var priorityPolicies = [priorityCount]struct {
+	ceiling float64
+	maxWait time.Duration
+}{
+	PriorityCritical:   {ceiling: 1.0, maxWait: 10 * time.Second},
+	PriorityNormal:     {ceiling: 0.85, maxWait: 5 * time.Second},
+	PriorityBackground: {ceiling: 0.6, maxWait: 2 * time.Second},
+	PriorityLow:        {ceiling: 0.5, maxWait: 0},
+}
+
+type priorityCtxKey struct{}
+
+// WithPriority 为后续交易所请求指定优先级（未指定时下单类接口为关键，其余为普通）
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityCtxKey{}, p)
+}
+
+func priorityFromContext(ctx context.Context) (Priority, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	p, ok := ctx.Value(priorityCtxKey{}).(Priority)
+	return p, ok
+}
+
+// VenueLimit 交易所请求额度窗口
+type VenueLimit struct {
+	Window time.Duration // 窗口长度
+	Limit  float64       // 窗口内可用权重
+}
+
+// defaultVenueLimits 各交易所默认额度，可通过 exchange.rateLimit.<platform>.window/limit 覆盖
+// Binance 为每IP每分钟权重；OKX/Gate 按接口限频，这里取保守的账户级总额度
+var defaultVenueLimits = map[string]VenueLimit{
+	"binance": {Window: time.Minute, Limit: 2400},
+	"okx":     {Window: 2 * time.Second, Limit: 40},
+	"gate":    {Window: 10 * time.Second, Limit: 200},
+}
+
+func venueLimit(platform string) VenueLimit {
+	limit, ok := defaultVenueLimits[platform]
+	if !ok {
+		limit = VenueLimit{Window: time.Second, Limit: 10}
+	}
+	ctx := context.Background()
+	if v := g.Cfg().MustGet(ctx, "exchange.rateLimit."+platform+".window"); !v.IsEmpty() {
+		if d, err := time.ParseDuration(v.String()); err == nil && d > 0 {
+			limit.Window = d
+		}
+	}
+	if v := g.Cfg().MustGet(ctx, "exchange.rateLimit."+platform+".limit"); !v.IsEmpty() && v.Float64() > 0 {
+		limit.Limit = v.Float64()
+	}
+	return limit
+}
+
+// egressState 出口（平台+出口IP/代理）级的封禁/限流退避状态，同一出口下所有账户共享
+type egressState struct {
+	mu             sync.Mutex
+	bannedUntil    time.Time
+	throttledUntil time.Time
+	banCount       int
+	reason         string
+}
+
+func (s *egressState) blockedUntil() (until time.Time, banned bool, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.bannedUntil.After(s.throttledUntil) {
+		return s.bannedUntil, true, s.reason
+	}
+	return s.throttledUntil, false, s.reason
+}
+
+type priorityStats struct {
+	requests int64
+	waited   int64
+	rejected int64
+}
+
+// Governor 单个（平台, API Key, 出口）的请求额度调度器
+type Governor struct {
+	platform string
+	account  string // API Key 指纹
+	egress   string
+	limit    VenueLimit
+	state    *egressState
+	low      *RateLimiter // 低优先级按账户额外限速
+
+	mu          sync.Mutex
+	windowStart time.Time
+	used        float64
+	headerAt    time.Time
+	bans        int64
+	throttles   int64
+	stats       [priorityCount]priorityStats
+}
+
+var (
+	governors    sync.Map // key: platform|account|egress
+	egressStates sync.Map // key: platform|egress
+)
+
+// GetGovernor 获取交易所配置对应的请求调度器
+func GetGovernor(cfg *Config) *Governor {
+	platform := strings.ToLower(cfg.Platform)
+	account := "public"
+	if cfg.ApiKey != "" {
+		sum := sha256.Sum256([]byte(cfg.ApiKey))
+		account = hex.EncodeToString(sum[:4])
+	}
+	egress := "direct"
+	if cfg.ProxyBinding != nil {
+		egress = GetProxyPool().Egress(cfg.ProxyBinding)
+	} else if cfg.Proxy != nil && cfg.Proxy.Enabled {
+		egress = cfg.Proxy.Type + "://" + cfg.Proxy.Host + ":" + strconv.Itoa(cfg.Proxy.Port)
+	}
+
+	key := platform + "|" + account + "|" + egress
+	if v, ok := governors.Load(key); ok {
+		return v.(*Governor)
+	}
+	state, _ := egressStates.LoadOrStore(platform+"|"+egress, &egressState{})
+	limit := venueLimit(platform)
+	v, _ := governors.LoadOrStore(key, &Governor{
+		platform:    platform,
+		account:     account,
+		egress:      egress,
+		limit:       limit,
+		state:       state.(*egressState),
+		low:         NewRateLimiter(1, 1),
+		windowStart: time.Now().Truncate(limit.Window),
+	})
+	return v.(*Governor)
+}
+
+// Acquire 请求前申请额度；额度不足时按优先级排队或直接拒绝，出口被封禁/限流期间不放行
+func (gv *Governor) Acquire(ctx context.Context, p Priority, weight float64) error {
+	if p < 0 || p >= priorityCount {
+		p = PriorityNormal
+	}
+	policy := priorityPolicies[p]
+	if p == PriorityLow && !gv.low.TryAcquire() {
+		return gv.reject(p, 429, "低优先级请求限速")
+	}
+
+	deadline := time.Now().Add(policy.maxWait)
+	waited := false
+	for {
+		now := time.Now()
+		if until, banned, reason := gv.state.blockedUntil(); now.Before(until) {
+			if until.After(deadline) {
+				if banned {
+					return gv.reject(p, 418, fmt.Sprintf("出口IP被封禁，暂停请求至 %s（%s）", until.Format("15:04:05"), reason))
+				}
+				return gv.reject(p, 429, fmt.Sprintf("触发限流，暂停请求至 %s（%s）", until.Format("15:04:05"), reason))
+			}
+			if err := sleepCtx(ctx, until.Sub(now)); err != nil {
+				return err
+			}
+			waited = true
+			continue
+		}
+
+		gv.mu.Lock()
+		gv.rollWindow(now)
+		if gv.used == 0 || gv.used+weight <= gv.limit.Limit*policy.ceiling {
+			gv.used += weight
+			gv.stats[p].requests++
+			if waited {
+				gv.stats[p].waited++
+			}
+			gv.mu.Unlock()
+			return nil
+		}
+		reset := gv.windowStart.Add(gv.limit.Window)
+		gv.mu.Unlock()
+
+		if reset.After(deadline) {
+			return gv.reject(p, 429, fmt.Sprintf("%s优先级请求额度不足", p))
+		}
+		if err := sleepCtx(ctx, reset.Sub(now)); err != nil {
+			return err
+		}
+		waited = true
+	}
+}
+
+// Observe 记录响应：按交易所返回的用量头校准已用额度，识别限流/封禁并全局退避
+func (gv *Governor) Observe(status int, header http.Header, body string) {
+	now := time.Now()
+	gv.mu.Lock()
+	gv.rollWindow(now)
+	switch gv.platform {
+	case "binance":
+		// 权重按IP统计，响应头即为当前窗口内该IP的真实用量
+		if v := header.Get("X-MBX-USED-WEIGHT-1m"); v != "" {
+			if used, err := strconv.ParseFloat(v, 64); err == nil {
+				gv.used = used
+				gv.headerAt = now
+			}
+		}
+	case "gate":
+		gv.observeRemain(now, header.Get("X-Gate-RateLimit-Limit"), header.Get("X-Gate-RateLimit-Requests-Remain"))
+	default:
+		gv.observeRemain(now, header.Get("X-RateLimit-Limit"), header.Get("X-RateLimit-Remaining"))
+	}
+	gv.mu.Unlock()
+
+	banned, throttled := gv.classify(status, body)
+	if !banned && !throttled {
+		if status >= 200 && status < 300 {
+			gv.state.mu.Lock()
+			if gv.state.banCount > 0 && now.After(gv.state.bannedUntil) {
+				gv.state.banCount = 0
+			}
+			gv.state.mu.Unlock()
+		}
+		return
+	}
+
+	retryAfter := time.Duration(0)
+	if v := header.Get("Retry-After"); v != "" {
+		if sec, err := strconv.Atoi(v); err == nil && sec > 0 {
+			retryAfter = time.Duration(sec) * time.Second
+		}
+	}
+	reason := fmt.Sprintf("http=%d", status)
+
+	gv.state.mu.Lock()
+	if banned {
+		gv.state.banCount++
+		backoff := retryAfter
+		if backoff <= 0 {
+			// 未给出解封时间时指数退避：2分钟起，最长30分钟
+			backoff = time.Duration(math.Min(float64(2*time.Minute)*math.Pow(2, float64(gv.state.banCount-1)), float64(30*time.Minute)))
+		}
+		if until := now.Add(backoff); until.After(gv.state.bannedUntil) {
+			gv.state.bannedUntil = until
+		}
+	} else {
+		backoff := retryAfter
+		if backoff <= 0 {
+			backoff = 10 * time.Second
+		}
+		if until := now.Add(backoff); until.After(gv.state.throttledUntil) {
+			gv.state.throttledUntil = until
+		}
+	}
+	gv.state.reason = reason
+	until := gv.state.bannedUntil
+	if !banned {
+		until = gv.state.throttledUntil
+	}
+	gv.state.mu.Unlock()
+
+	kind, hit := "限流", "throttle"
+	gv.mu.Lock()
+	if banned {
+		gv.bans++
+		kind, hit = "IP封禁", "ban"
+	} else {
+		gv.throttles++
+	}
+	gv.mu.Unlock()
+	rateLimitHits.Inc(gv.platform, hit)
+	g.Log().Warningf(context.Background(), "[RateGovernor] %s 出口=%s 触发%s，暂停请求至 %s（%s）",
+		gv.platform, gv.egress, kind, until.Format("15:04:05"), reason)
+}
+
+// observeRemain 按“剩余次数/总次数”头换算用量；这类头按接口统计，取较大值保守处理
+func (gv *Governor) observeRemain(now time.Time, limitStr, remainStr string) {
+	if limitStr == "" || remainStr == "" {
+		return
+	}
+	limit, err1 := strconv.ParseFloat(limitStr, 64)
+	remain, err2 := strconv.ParseFloat(remainStr, 64)
+	if err1 != nil || err2 != nil || limit <= 0 {
+		return
+	}
+	if used := (limit - remain) / limit * gv.limit.Limit; used > gv.used {
+		gv.used = used
+	}
+	gv.headerAt = now
+}
+
+// classify 识别封禁与限流响应
+func (gv *Governor) classify(status int, body string) (banned, throttled bool) {
+	if status == 418 {
+		return true, false
+	}
+	if status == 429 {
+		return false, true
+	}
+	if status >= 400 {
+		apiErr := ParseAPIError(gv.platform, status, body)
+		switch apiErr.Code {
+		case ErrCodeBinanceIPBanned:
+			return true, false
+		case ErrCodeRateLimit:
+			return false, true
+		}
+		return false, apiErr.IsRateLimitError()
+	}
+	// OKX 限流可能以 HTTP 200 + 业务码返回
+	if gv.platform == "okx" && body != "" {
+		switch gjson.New(body).Get("code").String() {
+		case "50011", "50061":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// rollWindow 窗口按整点对齐（与 Binance 按自然分钟统计权重一致）
+func (gv *Governor) rollWindow(now time.Time) {
+	if start := now.Truncate(gv.limit.Window); start.After(gv.windowStart) {
+		gv.windowStart = start
+		gv.used = 0
+	}
+}
+
+func (gv *Governor) reject(p Priority, status int, msg string) error {
+	gv.mu.Lock()
+	gv.stats[p].rejected++
+	gv.mu.Unlock()
+	rateLimitHits.Inc(gv.platform, "rejected")
+	apiErr := &APIError{StatusCode: status, Platform: gv.platform, Message: msg}
+	if status == 418 {
+		apiErr.Code = ErrCodeIPBanned
+	} else {
+		apiErr.Code = ErrCodeRateLimit
+	}
+	return gerror.Wrapf(apiErr, "[%s] %s", gv.platform, msg)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// GovernorStatus 请求调度器状态
+type GovernorStatus struct {
+	Platform       string           `json:"platform"`
+	Account        string           `json:"account"` // API Key 指纹
+	Egress         string           `json:"egress"`
+	Window         string           `json:"window"`
+	Limit          float64          `json:"limit"`
+	Used           float64          `json:"used"`
+	UsageRatio     float64          `json:"usageRatio"`
+	HeaderAt       int64            `json:"headerAt"` // 最近一次按响应头校准的时间（毫秒），0=交易所未返回用量头
+	BannedUntil    int64            `json:"bannedUntil"`
+	ThrottledUntil int64            `json:"throttledUntil"`
+	Bans           int64            `json:"bans"`
+	Throttles      int64            `json:"throttles"`
+	Requests       map[string]int64 `json:"requests"`
+	Waited         map[string]int64 `json:"waited"`
+	Rejected       map[string]int64 `json:"rejected"`
+}
+
+// GovernorStatuses 获取所有请求调度器状态
+func GovernorStatuses() []*GovernorStatus {
+	var list []*GovernorStatus
+	governors.Range(func(_, v any) bool {
+		list = append(list, v.(*Governor).Status())
+		return true
+	})
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Platform != list[j].Platform {
+			return list[i].Platform < list[j].Platform
+		}
+		return list[i].Account+list[i].Egress < list[j].Account+list[j].Egress
+	})
+	return list
+}
+
+// Status 获取调度器状态
+func (gv *Governor) Status() *GovernorStatus {
+	gv.state.mu.Lock()
+	bannedUntil, throttledUntil := gv.state.bannedUntil, gv.state.throttledUntil
+	gv.state.mu.Unlock()
+
+	gv.mu.Lock()
+	defer gv.mu.Unlock()
+	gv.rollWindow(time.Now())
+	st := &GovernorStatus{
+		Platform:   gv.platform,
+		Account:    gv.account,
+		Egress:     gv.egress,
+		Window:     gv.limit.Window.String(),
+		Limit:      gv.limit.Limit,
+		Used:       gv.used,
+		UsageRatio: gv.used / gv.limit.Limit,
+		Bans:       gv.bans,
+		Throttles:  gv.throttles,
+		Requests:   make(map[string]int64, priorityCount),
+		Waited:     make(map[string]int64, priorityCount),
+		Rejected:   make(map[string]int64, priorityCount),
+	}
+	if !gv.headerAt.IsZero() {
+		st.HeaderAt = gv.headerAt.UnixMilli()
+	}
+	if time.Now().Before(bannedUntil) {
+		st.BannedUntil = bannedUntil.UnixMilli()
+	}
+	if time.Now().Before(throttledUntil) {
+		st.ThrottledUntil = throttledUntil.UnixMilli()
+	}
+	for p := Priority(0); p < priorityCount; p++ {
+		st.Requests[p.String()] = gv.stats[p].requests
+		st.Waited[p.String()] = gv.stats[p].waited
+		st.Rejected[p.String()] = gv.stats[p].rejected
+	}
+	return st
+}
+
+// requestPriority 请求优先级：上下文指定优先，否则下单/撤单/平仓为关键，其余为普通
+// 低优先级不排队、额度紧张时直接拒绝，只有调用方通过 WithPriority 明确声明时才降级，
+// 避免按接口路径把用户主动发起的历史/成交查询误降为低优先级。
+func requestPriority(ctx context.Context, method, path string) Priority {
+	if p, ok := priorityFromContext(ctx); ok {
+		return p
+	}
+	lower := strings.ToLower(path)
+	if !strings.EqualFold(method, "GET") && (strings.Contains(lower, "order") || strings.Contains(lower, "close-position")) {
+		return PriorityCritical
+	}
+	return PriorityNormal
+}
+
+// requestWeight 接口权重（Binance 按官方权重，其余交易所按次计）
+func requestWeight(platform, method, path string, params map[string]string) float64 {
+	if platform != "binance" {
+		return 1
+	}
+	switch path {
+	case "/fapi/v1/klines":
+		limit, _ := strconv.Atoi(params["limit"])
+		switch {
+		case limit > 0 && limit < 100:
+			return 1
+		case limit > 0 && limit < 500:
+			return 2
+		case limit == 0 || limit <= 1000:
+			return 5
+		default:
+			return 10
+		}
+	case "/fapi/v2/account", "/fapi/v2/balance", "/fapi/v2/positionRisk", "/fapi/v1/userTrades", "/fapi/v1/allOrders":
+		return 5
+	case "/fapi/v1/income":
+		return 30
+	case "/fapi/v1/ticker/24hr", "/fapi/v1/openOrders":
+		if params["symbol"] == "" {
+			return 40
+		}
+	}
+	return 1
+}
@@ -0,0 +1,99 @@
+// Package exchange
+// @Description 请求调度器测试
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newTestGovernor(platform string, limit float64) *Governor {
+	window := time.Hour
+	return &Governor{
+		platform:    platform,
+		account:     "test",
+		egress:      "direct",
+		limit:       VenueLimit{Window: window, Limit: limit},
+		state:       &egressState{},
+		low:         NewRateLimiter(1, 1),
+		windowStart: time.Now().Truncate(window),
+	}
+}
+
+func TestGovernorPriorityCeiling(t *testing.T) {
+	ctx := context.Background()
+	gv := newTestGovernor("binance", 100)
+
+	if err := gv.Acquire(ctx, PriorityNormal, 80); err != nil {
+		t.Fatalf("Acquire(normal) error = %v", err)
+	}
+	// 后台请求上限为 60%，已用 80 时应直接拒绝
+	if err := gv.Acquire(ctx, PriorityBackground, 1); !IsRateLimitErr(err) {
+		t.Fatalf("Acquire(background) error = %v, want rate limit", err)
+	}
+	// 下单请求可使用全部额度
+	if err := gv.Acquire(ctx, PriorityCritical, 20); err != nil {
+		t.Fatalf("Acquire(critical) error = %v", err)
+	}
+	if st := gv.Status(); st.Rejected[PriorityBackground.String()] != 1 {
+		t.Fatalf("Status().Rejected = %v", st.Rejected)
+	}
+}
+
+func TestGovernorBinanceHeader(t *testing.T) {
+	ctx := context.Background()
+	gv := newTestGovernor("binance", 100)
+
+	header := http.Header{}
+	header.Set("X-MBX-USED-WEIGHT-1m", "90")
+	gv.Observe(http.StatusOK, header, "")
+
+	if err := gv.Acquire(ctx, PriorityNormal, 1); !IsRateLimitErr(err) {
+		t.Fatalf("Acquire(normal) error = %v, want rate limit", err)
+	}
+	if err := gv.Acquire(ctx, PriorityCritical, 1); err != nil {
+		t.Fatalf("Acquire(critical) error = %v", err)
+	}
+}
+
+func TestGovernorBanBackoff(t *testing.T) {
+	ctx := context.Background()
+	gv := newTestGovernor("binance", 100)
+
+	gv.Observe(418, http.Header{}, `{"code":-1003,"msg":"Way too many requests; IP banned"}`)
+
+	if err := gv.Acquire(ctx, PriorityCritical, 1); !IsIPBannedErr(err) {
+		t.Fatalf("Acquire() error = %v, want ip banned", err)
+	}
+	until, banned, _ := gv.state.blockedUntil()
+	if !banned || time.Until(until) < time.Minute {
+		t.Fatalf("blockedUntil() = %v, %v", until, banned)
+	}
+}
+
+func TestRequestPriority(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		method, path string
+		want         Priority
+	}{
+		{"POST", "/fapi/v1/order", PriorityCritical},
+		{"GET", "/fapi/v1/order", PriorityNormal},
+		{"GET", "/fapi/v1/userTrades", PriorityNormal},
+		{"GET", "/api/v5/trade/fills-history", PriorityNormal},
+		{"GET", "/fapi/v2/positionRisk", PriorityNormal},
+	}
+	for _, tt := range tests {
+		if got := requestPriority(ctx, tt.method, tt.path); got != tt.want {
+			t.Errorf("requestPriority(%s %s) = %s, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+	if got := requestPriority(WithPriority(ctx, PriorityBackground), "GET", "/fapi/v1/klines"); got != PriorityBackground {
+		t.Errorf("requestPriority(ctx) = %s, want background", got)
+	}
+	if got := requestPriority(WithPriority(ctx, PriorityLow), "GET", "/fapi/v1/userTrades"); got != PriorityLow {
+		t.Errorf("requestPriority(ctx) = %s, want low", got)
+	}
+}
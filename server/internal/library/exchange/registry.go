@@ -0,0 +1,127 @@
+// Package exchange
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 交易所驱动注册表：各交易平台以插件（addons/exchange_*）形式注册实现
+package exchange
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// Driver 交易所驱动，描述一个交易平台的全部实现
+type Driver struct {
+	Platform       string // 平台标识，与 hg_trading_api_config.platform 一致（小写）
+	Title          string // 展示名称
+	BaseUrl        string // 默认REST地址
+	NeedPassphrase bool   // 是否需要 Passphrase
+
+	// NewExchange 创建REST交易实例（必填）
+	NewExchange func(config *Config) (Exchange, error)
+	// NewPrivateStream 创建私有WS流（可选，未提供时上层仅使用轮询）
+	NewPrivateStream func(config *Config) (PrivateStream, error)
+	// PublicStream 获取公共行情WS单例（可选，未提供时行情走HTTP轮询）
+	PublicStream func() PublicStream
+	// PublicStreamReplay 公共WS会保存订阅并在连接建立后自动重放：
+	// 订阅时不要求已连接；WS-only 模式下K线长时间未就绪时用公共REST补齐一次
+	PublicStreamReplay bool
+}
+
+// PublicStream 公共行情WS（ticker/K线），每个平台一个单例
+type PublicStream interface {
+	Start(ctx context.Context) error
+	Stop()
+	IsRunning() bool
+	GetConnectionState() string
+	SetProxyDialer(dialer func(network, addr string) (net.Conn, error))
+
+	SubscribeTicker(symbol string, callback func(*Ticker)) error
+	UnsubscribeTicker(symbol string) error
+	SubscribeKline(symbol, interval string, callback func([]*Kline)) error
+
+	GetTicker(symbol string) *Ticker
+	GetKlines(symbol, interval string) []*Kline
+}
+
+// MarkPriceSubscriber 可选接口：单独订阅标记价格
+type MarkPriceSubscriber interface {
+	SubscribeMarkPrice(symbol string) error
+	UnsubscribeMarkPrice(symbol string) error
+}
+
+// KlineUnsubscriber 可选接口：退订K线
+type KlineUnsubscriber interface {
+	UnsubscribeKline(symbol, interval string) error
+}
+
+// PublicStreamStatusProvider 可选接口：输出公共WS运行状态（用于状态查询）
+type PublicStreamStatusProvider interface {
+	Status() interface{}
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]*Driver)
+)
+
+// RegisterDriver 注册交易所驱动，同名平台后注册的覆盖先注册的
+func RegisterDriver(d *Driver) {
+	if d == nil || d.NewExchange == nil {
+		panic("exchange: RegisterDriver driver or NewExchange is nil")
+	}
+	d.Platform = strings.ToLower(strings.TrimSpace(d.Platform))
+	if d.Platform == "" {
+		panic("exchange: RegisterDriver platform is empty")
+	}
+
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if _, ok := drivers[d.Platform]; ok {
+		g.Log().Warningf(context.Background(), "[Exchange] 交易所驱动被覆盖: platform=%s", d.Platform)
+	}
+	drivers[d.Platform] = d
+}
+
+// GetDriver 获取交易所驱动
+func GetDriver(platform string) (*Driver, bool) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	d, ok := drivers[strings.ToLower(strings.TrimSpace(platform))]
+	return d, ok
+}
+
+// Drivers 获取已注册的交易所驱动，按平台标识排序
+func Drivers() []*Driver {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	list := make([]*Driver, 0, len(drivers))
+	for _, d := range drivers {
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Platform < list[j].Platform })
+	return list
+}
+
+func mustDriver(platform string) (*Driver, error) {
+	d, ok := GetDriver(platform)
+	if !ok {
+		return nil, gerror.Newf("不支持的交易所: %s", platform)
+	}
+	return d, nil
+}
+
+// Status 公共WS状态
+func (b *BinanceWebSocket) Status() interface{} { return b.GetStatus() }
+
+// Status 公共WS状态
+func (o *OKXWebSocket) Status() interface{} { return o.GetStatus() }
+
+// Status 公共WS状态
+func (gt *GateWebSocket) Status() interface{} { return gt.GetStatus() }
@@ -0,0 +1,36 @@
+// Package exchange
+// @Description 交易所驱动注册表测试
+package exchange
+
+import (
+	"testing"
+)
+
+func TestRegisterDriver(t *testing.T) {
+	RegisterDriver(&Driver{
+		Platform: " TestVenue ",
+		Title:    "Test",
+		NewExchange: func(config *Config) (Exchange, error) {
+			return NewBinance(config), nil
+		},
+	})
+	defer func() {
+		driversMu.Lock()
+		delete(drivers, "testvenue")
+		driversMu.Unlock()
+	}()
+
+	d, ok := GetDriver("TESTVENUE")
+	if !ok || d.Platform != "testvenue" {
+		t.Fatalf("GetDriver() = %v, %v", d, ok)
+	}
+	if _, err := NewExchange(&Config{Platform: "testvenue"}); err != nil {
+		t.Fatalf("NewExchange() error = %v", err)
+	}
+	if _, err := NewPrivateStream(&Config{Platform: "testvenue"}); err == nil {
+		t.Fatal("NewPrivateStream() expected error for driver without private stream")
+	}
+	if _, err := NewExchange(&Config{Platform: "unknown"}); err == nil {
+		t.Fatal("NewExchange() expected error for unregistered platform")
+	}
+}
@@ -0,0 +1,99 @@
+// Package exchange
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 交易所请求指标与链路追踪
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"hotgo/internal/library/metrics"
+
+	"github.com/gogf/gf/v2/net/gtrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var (
+	restLatency = metrics.NewHistogram("toogo_exchange_rest_duration_seconds",
+		"Exchange REST request latency in seconds.", nil, "platform", "priority", "code")
+	rateLimitHits = metrics.NewCounter("toogo_exchange_rate_limit_hits_total",
+		"Exchange rate limit hits: throttle/ban responses from the venue and requests rejected locally by the governor.", "platform", "kind")
+	wsReconnects = metrics.NewCounter("toogo_exchange_ws_reconnects_total",
+		"Exchange WebSocket reconnect attempts.", "platform", "stream")
+)
+
+// venueCall 单次交易所REST请求：额度调度、耗时指标与链路追踪
+type venueCall struct {
+	gv       *Governor
+	span     *gtrace.Span
+	priority Priority
+	start    time.Time
+	done     bool
+}
+
+// acquireRequest 交易所请求统一入口：申请额度并开启 exchange.request 追踪，响应后调用 Observe，结束时调用 End
+func acquireRequest(ctx context.Context, cfg *Config, method, path string, params map[string]string) (context.Context, *venueCall, error) {
+	gv := GetGovernor(cfg)
+	p := requestPriority(ctx, method, path)
+	ctx, span := gtrace.NewSpan(ctx, "exchange.request")
+	span.SetAttributes(
+		attribute.String("exchange.platform", gv.platform),
+		attribute.String("http.method", method),
+		attribute.String("http.route", requestRoute(path)),
+		attribute.String("exchange.priority", p.String()),
+		attribute.String("exchange.egress", gv.egress),
+	)
+	if err := gv.Acquire(ctx, p, requestWeight(gv.platform, method, path, params)); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		return ctx, nil, err
+	}
+	return ctx, &venueCall{gv: gv, span: span, priority: p, start: time.Now()}, nil
+}
+
+// Observe 记录响应：交给调度器校准额度，并记录耗时与状态码
+func (c *venueCall) Observe(status int, header http.Header, body string) {
+	c.gv.Observe(status, header, body)
+	restLatency.Observe(time.Since(c.start).Seconds(), c.gv.platform, c.priority.String(), strconv.Itoa(status))
+	c.span.SetAttributes(attribute.Int("http.status_code", status))
+	if status < 200 || status >= 300 {
+		c.span.SetStatus(codes.Error, "http "+strconv.Itoa(status))
+	}
+	c.finish()
+}
+
+// Fail 记录网络层失败（未拿到响应）
+func (c *venueCall) Fail(err error) {
+	restLatency.Observe(time.Since(c.start).Seconds(), c.gv.platform, c.priority.String(), "error")
+	c.span.RecordError(err)
+	c.span.SetStatus(codes.Error, err.Error())
+	c.finish()
+}
+
+// End 结束追踪；未调用 Observe/Fail 的提前返回路径也能正确收尾
+func (c *venueCall) End() {
+	c.finish()
+}
+
+func (c *venueCall) finish() {
+	if c.done {
+		return
+	}
+	c.done = true
+	c.span.End()
+}
+
+// requestRoute 去掉路径中的查询参数，避免追踪属性携带订单参数
+func requestRoute(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
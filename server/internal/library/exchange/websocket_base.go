@@ -32,6 +32,8 @@ type WebSocketConfig struct {
 	MaxReconnects     int                                          // 最大重连次数
 	MessageBufferSize int                                          // 消息缓冲区大小
 	ProxyDialer       func(network, addr string) (net.Conn, error) // 代理拨号器（可选）
+	Platform          string                                       // 交易所（指标标签）
+	Stream            string                                       // 连接用途，如 public/private（指标标签）
 
 	// PingAsText:
 	// - false: 使用 WS 协议层 ping frame（websocket.PingMessage）
@@ -395,6 +397,7 @@ func (c *WebSocketConnection) reconnect(ctx context.Context) {
 		c.reconnectCount++
 		count := c.reconnectCount
 		c.mu.Unlock()
+		wsReconnects.Inc(c.config.Platform, c.config.Stream)
 
 		if count > c.config.MaxReconnects {
 			g.Log().Errorf(ctx, "[WebSocket] 重连次数超过上限 (%d), 停止重连", c.config.MaxReconnects)
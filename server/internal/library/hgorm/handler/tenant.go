@@ -6,12 +6,26 @@
 package handler
 
 import (
+	"context"
 	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/text/gstr"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
 	"hotgo/internal/library/contexts"
 	"hotgo/utility/convert"
 )
 
+func init() {
+	dao.RegisterTenantHandler(dao.TenantHandlers{
+		Scope:  FilterTenantScope,
+		Shared: FilterTenantShared,
+		Member: FilterTenantMember,
+		Robot:  FilterTenantRobot,
+		Order:  FilterTenantOrder,
+		Group:  FilterTenantStrategyGroup,
+	})
+}
+
 // FilterTenant 过滤多租户数据权限
 // 根据部门类型识别当前租户、商户、用户身份，过滤只属于自己的数据
 func FilterTenant(m *gdb.Model) *gdb.Model {
@@ -47,3 +61,75 @@ func FilterTenant(m *gdb.Model) *gdb.Model {
 	m = m.Where(filterField, contexts.GetUserId(ctx))
 	return m
 }
+
+// tenantFilterable 是否需要按租户隔离
+// 无用户上下文（定时任务、引擎协程、公开接口）和公司部门（超管跨租户视图）不做隔离
+func tenantFilterable(ctx context.Context) bool {
+	return contexts.GetUser(ctx) != nil && !contexts.IsCompanyDept(ctx)
+}
+
+// FilterTenantScope 过滤白标租户数据，只能访问本租户(tenant_id)的数据
+func FilterTenantScope(m *gdb.Model) *gdb.Model {
+	ctx := m.GetCtx()
+	if !tenantFilterable(ctx) {
+		return m
+	}
+	return m.Where(consts.TenantId, contexts.GetTenantId(ctx))
+}
+
+// FilterTenantShared 过滤白标租户数据，平台数据(tenant_id=0)对所有租户可见
+func FilterTenantShared(m *gdb.Model) *gdb.Model {
+	ctx := m.GetCtx()
+	if !tenantFilterable(ctx) {
+		return m
+	}
+	tenantId := contexts.GetTenantId(ctx)
+	if tenantId == 0 {
+		return m.Where(consts.TenantId, 0)
+	}
+	return m.WhereIn(consts.TenantId, []int64{0, tenantId})
+}
+
+// FilterTenantMember 过滤白标租户的用户数据，只能访问本租户用户(user_id)的数据
+// 普通用户只能访问自己的数据，由业务按 user_id 限定，这里不再追加条件
+func FilterTenantMember(m *gdb.Model) *gdb.Model {
+	ctx := m.GetCtx()
+	if !tenantFilterable(ctx) || contexts.IsUserDept(ctx) {
+		return m
+	}
+	if !gstr.InArray(convert.EscapeFieldsToSlice(m.GetFieldsStr()), consts.UserId) {
+		return m
+	}
+	members := dao.ToogoUser.Ctx(ctx).Fields(dao.ToogoUser.Columns().MemberId)
+	return m.Where(consts.UserId+" IN(?)", members)
+}
+
+// FilterTenantRobot 过滤白标租户的机器人数据，只能访问本租户机器人(robot_id)的数据
+func FilterTenantRobot(m *gdb.Model) *gdb.Model {
+	ctx := m.GetCtx()
+	if !tenantFilterable(ctx) || contexts.IsUserDept(ctx) {
+		return m
+	}
+	robots := dao.TradingRobot.Ctx(ctx).Fields(dao.TradingRobot.Columns().Id)
+	return m.Where("robot_id IN(?)", robots)
+}
+
+// FilterTenantOrder 过滤白标租户的订单数据，只能访问本租户订单(order_id)的数据
+func FilterTenantOrder(m *gdb.Model) *gdb.Model {
+	ctx := m.GetCtx()
+	if !tenantFilterable(ctx) || contexts.IsUserDept(ctx) {
+		return m
+	}
+	orders := dao.TradingOrder.Ctx(ctx).Fields(dao.TradingOrder.Columns().Id)
+	return m.Where("order_id IN(?)", orders)
+}
+
+// FilterTenantStrategyGroup 过滤白标租户的策略模板，只能访问本租户策略模板组(group_id)下的模板
+func FilterTenantStrategyGroup(m *gdb.Model) *gdb.Model {
+	ctx := m.GetCtx()
+	if !tenantFilterable(ctx) {
+		return m
+	}
+	groups := dao.TradingStrategyGroup.Ctx(ctx).Fields(dao.TradingStrategyGroup.Columns().Id)
+	return m.Where("group_id IN(?)", groups)
+}
@@ -0,0 +1,210 @@
+// Package pubsub
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package pubsub
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/util/guid"
+	"hotgo/utility/simple"
+)
+
+const (
+	seqKeyPrefix    = "pubsub:seq:"    // 频道序号
+	replayKeyPrefix = "pubsub:replay:" // 频道最近消息，用于断线重连补发
+
+	// ReplayWindow 每个频道保留的最近消息条数
+	ReplayWindow = 500
+	// ReplayTTL 频道无新消息后，补发缓存保留的秒数
+	ReplayTTL = 3600
+)
+
+// 分配序号并写入补发缓存，保证序号与缓存的原子性
+// 成员带上序号前缀，避免相同内容的消息在有序集合中被合并
+const appendScript = `
+	local seq = redis.call('incr', KEYS[1])
+	redis.call('zadd', KEYS[2], seq, seq .. ':' .. ARGV[1])
+	redis.call('zremrangebyrank', KEYS[2], 0, -(tonumber(ARGV[2]) + 1))
+	redis.call('expire', KEYS[2], ARGV[3])
+	return seq
+`
+
+// nodeId 当前进程的节点标识，用于在集群消息中过滤本节点发出的消息
+var nodeId = guid.S()
+
+// NodeId 当前节点标识
+func NodeId() string {
+	return nodeId
+}
+
+// SeqMessage 带序号的频道消息
+type SeqMessage struct {
+	Seq     int64
+	Payload string
+}
+
+// localChannel 未开启集群部署时，进程内的频道序号与补发缓存
+type localChannel struct {
+	seq  int64
+	list []*SeqMessage // 最近 ReplayWindow 条消息，按序号升序
+	at   time.Time     // 最后一次写入时间
+}
+
+// locals 单节点部署只有本进程推送频道消息，序号与补发缓存保存在内存中，不经过 redis
+var locals = struct {
+	sync.Mutex
+	channels map[string]*localChannel
+	sweepAt  time.Time
+}{channels: make(map[string]*localChannel)}
+
+// Append 为频道分配下一个序号，并把消息写入补发缓存
+func Append(ctx context.Context, channel string, payload string) (seq int64, err error) {
+	if !simple.IsCluster(ctx) {
+		return localAppend(channel, payload), nil
+	}
+
+	keys := []string{seqKeyPrefix + channel, replayKeyPrefix + channel}
+	args := []interface{}{payload, ReplayWindow, ReplayTTL}
+	eval, err := g.Redis().GroupScript().Eval(ctx, appendScript, int64(len(keys)), keys, args)
+	if err != nil {
+		return
+	}
+	return eval.Int64(), nil
+}
+
+// LastSeq 频道当前的最新序号
+func LastSeq(ctx context.Context, channel string) (seq int64, err error) {
+	if !simple.IsCluster(ctx) {
+		return localLastSeq(channel), nil
+	}
+
+	v, err := g.Redis().Get(ctx, seqKeyPrefix+channel)
+	if err != nil {
+		return
+	}
+	return v.Int64(), nil
+}
+
+// Since 获取序号大于 afterSeq 的缓存消息，按序号升序
+// complete=false 表示缓存已不包含 afterSeq 之后的全部消息（超出保留窗口或已过期），客户端需要全量刷新
+func Since(ctx context.Context, channel string, afterSeq int64) (list []*SeqMessage, complete bool, err error) {
+	if !simple.IsCluster(ctx) {
+		last, cached := localSince(channel, afterSeq)
+		list, complete = sinceComplete(cached, last, afterSeq)
+		return
+	}
+
+	last, err := LastSeq(ctx, channel)
+	if err != nil {
+		return
+	}
+	if last <= afterSeq {
+		list, complete = sinceComplete(nil, last, afterSeq)
+		return
+	}
+
+	v, err := g.Redis().Do(ctx, "ZRANGEBYSCORE", replayKeyPrefix+channel, "("+strconv.FormatInt(afterSeq, 10), "+inf")
+	if err != nil {
+		return
+	}
+
+	for _, member := range v.Strings() {
+		idx := strings.IndexByte(member, ':')
+		if idx <= 0 {
+			continue
+		}
+		seq, err := strconv.ParseInt(member[:idx], 10, 64)
+		if err != nil {
+			continue
+		}
+		list = append(list, &SeqMessage{Seq: seq, Payload: member[idx+1:]})
+	}
+
+	list, complete = sinceComplete(list, last, afterSeq)
+	return
+}
+
+// sinceComplete 判断缓存消息是否完整覆盖 (afterSeq, last]
+// 缓存中的第一条必须紧接 afterSeq，且覆盖到最新序号，中间才没有缺口
+func sinceComplete(list []*SeqMessage, last, afterSeq int64) ([]*SeqMessage, bool) {
+	if last <= afterSeq {
+		// 客户端序号比服务端还新，说明序号已被重置，同样需要全量刷新
+		return nil, last == afterSeq
+	}
+	return list, len(list) > 0 && list[0].Seq == afterSeq+1 && list[len(list)-1].Seq == last
+}
+
+// localAppend 在进程内分配序号并写入补发缓存
+func localAppend(channel string, payload string) int64 {
+	locals.Lock()
+	defer locals.Unlock()
+
+	now := time.Now()
+	sweepLocalsLocked(now)
+
+	c, ok := locals.channels[channel]
+	if !ok {
+		c = new(localChannel)
+		locals.channels[channel] = c
+	} else if now.Sub(c.at) > ReplayTTL*time.Second {
+		c.list = nil
+	}
+	c.seq++
+	c.at = now
+	c.list = append(c.list, &SeqMessage{Seq: c.seq, Payload: payload})
+	if over := len(c.list) - ReplayWindow; over > 0 {
+		c.list = append(c.list[:0], c.list[over:]...)
+	}
+	return c.seq
+}
+
+// localLastSeq 进程内频道的最新序号
+func localLastSeq(channel string) int64 {
+	locals.Lock()
+	defer locals.Unlock()
+	if c, ok := locals.channels[channel]; ok {
+		return c.seq
+	}
+	return 0
+}
+
+// localSince 进程内频道的最新序号，以及序号大于 afterSeq 的缓存消息
+func localSince(channel string, afterSeq int64) (last int64, list []*SeqMessage) {
+	locals.Lock()
+	defer locals.Unlock()
+
+	c, ok := locals.channels[channel]
+	if !ok {
+		return
+	}
+	if time.Since(c.at) > ReplayTTL*time.Second {
+		return c.seq, nil
+	}
+	for _, item := range c.list {
+		if item.Seq > afterSeq {
+			list = append(list, item)
+		}
+	}
+	return c.seq, list
+}
+
+// sweepLocalsLocked 每分钟清理一次超过 ReplayTTL 未写入的频道，避免频道数只增不减
+func sweepLocalsLocked(now time.Time) {
+	if now.Sub(locals.sweepAt) < time.Minute {
+		return
+	}
+	locals.sweepAt = now
+	for name, c := range locals.channels {
+		if now.Sub(c.at) > ReplayTTL*time.Second {
+			delete(locals.channels, name)
+		}
+	}
+}
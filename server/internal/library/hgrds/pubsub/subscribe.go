@@ -13,8 +13,12 @@ import (
 	"github.com/gogf/gf/v2/os/gctx"
 	"hotgo/utility/simple"
 	"sync"
+	"time"
 )
 
+// resubscribeInterval 订阅断开后的重连间隔
+const resubscribeInterval = time.Second * 3
+
 type SubHandler func(ctx context.Context, message *gredis.Message)
 
 type subscribeManager struct {
@@ -54,20 +58,31 @@ func Subscribe(channel string, hr SubHandler) (err error) {
 	return
 }
 
+// doSubscribe 持续订阅，连接断开后自动重连，避免redis闪断后集群消息永久丢失
 func doSubscribe(channel string, hr SubHandler) {
 	ctx := gctx.New()
+	for {
+		if err := receive(ctx, channel, hr); err != nil {
+			g.Log().Warningf(ctx, "subscribe %v interrupted, retry after %v, err:%v", channel, resubscribeInterval, err)
+		}
+		time.Sleep(resubscribeInterval)
+	}
+}
+
+func receive(ctx context.Context, channel string, hr SubHandler) (err error) {
 	conn, err := g.Redis().Conn(ctx)
 	if err != nil {
 		return
 	}
 	defer conn.Close(ctx)
 
-	_, err = conn.Subscribe(ctx, channel)
+	if _, err = conn.Subscribe(ctx, channel); err != nil {
+		return
+	}
 	for {
 		msg, err := conn.ReceiveMessage(ctx)
 		if err != nil {
-			g.Log().Warningf(ctx, "subscribe quit, err:%v", err)
-			return
+			return err
 		}
 		handleMessage(hr, msg)
 	}
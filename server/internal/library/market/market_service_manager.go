@@ -36,10 +36,9 @@ type MarketServiceManager struct {
 	wsEnabled bool
 	// wsOnly: 强制“只使用WebSocket数据源”（ticker/klines均不做REST兜底/轮询）。
 	// 适用场景：需要彻底隔离行情链路与HTTP/交易所REST限流，并允许“未就绪则为空”的严格语义。
-	wsOnly    bool
-	binanceWS *exchange.BinanceWebSocket
-	okxWS     *exchange.OKXWebSocket
-	gateWS    *exchange.GateWebSocket
+	wsOnly bool
+	// 已启动的公共行情WS key: platform，由交易所驱动提供
+	publicStreams map[string]exchange.PublicStream
 
 	// 代理配置
 	proxyDialer func(network, addr string) (net.Conn, error)
@@ -109,6 +108,7 @@ func GetMarketServiceManager() *MarketServiceManager {
 	marketServiceManagerOnce.Do(func() {
 		marketServiceManager = &MarketServiceManager{
 			services:       make(map[string]*ExchangeMarketService),
+			publicStreams:  make(map[string]exchange.PublicStream),
 			priceCallbacks: make(map[string][]func(*exchange.Ticker)),
 			callbackQueues: make(map[string]chan *exchange.Ticker),
 			stopCh:         make(chan struct{}),
@@ -170,47 +170,43 @@ func (m *MarketServiceManager) startWebSocketServices(ctx context.Context) {
 	proxyDialer := m.proxyDialer // 复制代理配置
 	m.mu.Unlock()
 
-	// 统一启动流程：减少重复代码
+	// 启动所有提供公共行情WS的交易所驱动
 	successCount := 0
-	totalCount := 3
-
-	// 启动各个交易所WebSocket
-	startWS := func(name string, getter func() interface{}, setter func(interface{})) {
-		ws := getter()
-
-		// 设置代理
-		type proxySettable interface {
-			SetProxyDialer(func(string, string) (net.Conn, error))
+	totalCount := 0
+	for _, d := range exchange.Drivers() {
+		if d.PublicStream == nil {
+			continue
 		}
+		totalCount++
+		ws := d.PublicStream()
 		if proxyDialer != nil {
-			if p, ok := ws.(proxySettable); ok {
-				p.SetProxyDialer(proxyDialer)
-			}
+			ws.SetProxyDialer(proxyDialer)
 		}
-
-		// 启动WebSocket
-		type startable interface {
-			Start(context.Context) error
-		}
-		if s, ok := ws.(startable); ok {
-			if err := s.Start(ctx); err != nil {
-				g.Log().Warningf(ctx, "[MarketServiceManager] %s WebSocket启动失败: %v", name, err)
-			} else {
-				g.Log().Warningf(ctx, "[MarketServiceManager] ✅ %s WebSocket已启动", name)
-				setter(ws)
-				successCount++
-			}
+		if err := ws.Start(ctx); err != nil {
+			g.Log().Warningf(ctx, "[MarketServiceManager] %s WebSocket启动失败: %v", d.Title, err)
+			continue
 		}
+		g.Log().Warningf(ctx, "[MarketServiceManager] ✅ %s WebSocket已启动", d.Title)
+		m.mu.Lock()
+		m.publicStreams[d.Platform] = ws
+		m.mu.Unlock()
+		successCount++
 	}
 
-	// 启动顺序：Gate -> OKX -> Binance
-	startWS("Gate", func() interface{} { return exchange.GetGateWebSocket() }, func(ws interface{}) { m.gateWS = ws.(*exchange.GateWebSocket) })
-	startWS("OKX", func() interface{} { return exchange.GetOKXWebSocket() }, func(ws interface{}) { m.okxWS = ws.(*exchange.OKXWebSocket) })
-	startWS("Binance", func() interface{} { return exchange.GetBinanceWebSocket() }, func(ws interface{}) { m.binanceWS = ws.(*exchange.BinanceWebSocket) })
-
 	g.Log().Warningf(ctx, "[MarketServiceManager] WebSocket服务启动完成: 成功=%d/%d", successCount, totalCount)
 }
 
+// publicStream 获取平台公共行情WS，requireRunning=true 时仅返回连接正常的
+func (m *MarketServiceManager) publicStream(platform string, requireRunning bool) exchange.PublicStream {
+	m.mu.RLock()
+	ws := m.publicStreams[platform]
+	m.mu.RUnlock()
+	if ws == nil || (requireRunning && !ws.IsRunning()) {
+		return nil
+	}
+	return ws
+}
+
 // Stop 停止行情服务管理器
 func (m *MarketServiceManager) Stop() {
 	m.mu.Lock()
@@ -223,25 +219,10 @@ func (m *MarketServiceManager) Stop() {
 	m.running = false
 	close(m.stopCh)
 
-	// 停止所有WebSocket服务（统一循环处理）
-	type stoppable interface {
-		Stop()
-	}
-
-	wsClients := []struct {
-		name   string
-		client stoppable
-	}{
-		{"Binance", m.binanceWS},
-		{"OKX", m.okxWS},
-		{"Gate", m.gateWS},
-	}
-
-	for _, ws := range wsClients {
-		if ws.client != nil {
-			ws.client.Stop()
-			g.Log().Infof(context.Background(), "[MarketServiceManager] %s WebSocket已停止", ws.name)
-		}
+	// 停止所有WebSocket服务
+	for platform, ws := range m.publicStreams {
+		ws.Stop()
+		g.Log().Infof(context.Background(), "[MarketServiceManager] %s WebSocket已停止", platform)
 	}
 
 	// 停止所有HTTP轮询服务
@@ -522,153 +503,124 @@ func (m *MarketServiceManager) subscribeWebSocket(ctx context.Context, platform,
 		svc.mu.Unlock()
 	}
 
-	switch platform {
-	case "binance":
-		if m.binanceWS != nil && m.binanceWS.IsRunning() {
-			m.binanceWS.SubscribeTicker(symbol, func(ticker *exchange.Ticker) {
-				if svc := m.GetService(platform); svc != nil {
-					svc.mu.Lock()
-					svc.Tickers[symbol] = &TickerCache{Data: ticker, UpdatedAt: time.Now()}
-					svc.mu.Unlock()
-				}
-				// 【新增】触发注册的回调函数
-				m.triggerPriceCallbacks(platform, symbol, ticker)
-			})
-			// 标记价格（风控/盈亏口径）
-			_ = m.binanceWS.SubscribeMarkPrice(symbol)
-			for _, interval := range []string{"1m", "5m", "15m", "30m", "1h"} {
-				_ = m.binanceWS.SubscribeKline(symbol, interval, func(klines []*exchange.Kline) {
-					updateSvcKlines(interval, klines)
-				})
-			}
+	d, ok := exchange.GetDriver(platform)
+	if !ok {
+		return
+	}
+	// 公共WS会保存订阅并在连接恢复后自动重放时（如 Gate 连接较慢或短暂断线重连），不要用 IsRunning() 做硬门槛，
+	// 否则“订阅请求发生在连接完成之前”会被跳过，导致永远没有K线数据。
+	ws := m.publicStream(platform, !d.PublicStreamReplay)
+	if ws == nil {
+		return
+	}
+	_ = ws.SubscribeTicker(symbol, m.wsTickerCallback(platform, symbol))
+	// 标记价格（风控/盈亏口径）
+	if mp, ok := ws.(exchange.MarkPriceSubscriber); ok {
+		_ = mp.SubscribeMarkPrice(symbol)
+	}
+	for _, interval := range []string{"1m", "5m", "15m", "30m", "1h"} {
+		_ = ws.SubscribeKline(symbol, interval, func(klines []*exchange.Kline) {
+			updateSvcKlines(interval, klines)
+		})
+	}
+
+	// 兜底：慢热的公共WS在 WS-only 模式下长时间收不到K线（或解析异常），会导致 MarketAnalyzer 永远没有数据。
+	// 这里做一次延迟检查：若仍无任何K线，则触发一次 REST 拉取补齐（仅一次，避免刷接口）。
+	m.mu.RLock()
+	wsOnly := m.wsOnly
+	m.mu.RUnlock()
+	if d.PublicStreamReplay && wsOnly {
+		go m.fillKlinesFromPublicRest(platform, symbol)
+	}
+}
+
+// wsTickerCallback 公共WS ticker 回调：写入行情缓存并触发价格回调
+func (m *MarketServiceManager) wsTickerCallback(platform, symbol string) func(*exchange.Ticker) {
+	return func(ticker *exchange.Ticker) {
+		if svc := m.GetService(platform); svc != nil {
+			svc.mu.Lock()
+			svc.Tickers[symbol] = &TickerCache{Data: ticker, UpdatedAt: time.Now()}
+			svc.mu.Unlock()
 		}
-	case "okx":
-		if m.okxWS != nil && m.okxWS.IsRunning() {
-			m.okxWS.SubscribeTicker(symbol, func(ticker *exchange.Ticker) {
-				if svc := m.GetService(platform); svc != nil {
-					svc.mu.Lock()
-					svc.Tickers[symbol] = &TickerCache{Data: ticker, UpdatedAt: time.Now()}
-					svc.mu.Unlock()
-				}
-				// 【新增】触发注册的回调函数
-				m.triggerPriceCallbacks(platform, symbol, ticker)
-			})
-			_ = m.okxWS.SubscribeMarkPrice(symbol)
-			for _, interval := range []string{"1m", "5m", "15m", "30m", "1h"} {
-				_ = m.okxWS.SubscribeKline(symbol, interval, func(klines []*exchange.Kline) {
-					updateSvcKlines(interval, klines)
-				})
-			}
+		m.triggerPriceCallbacks(platform, symbol, ticker)
+	}
+}
+
+// fillKlinesFromPublicRest 公共WS K线延迟未就绪时，用公共行情REST补齐一次
+func (m *MarketServiceManager) fillKlinesFromPublicRest(platform, symbol string) {
+	defer func() {
+		if r := recover(); r != nil {
+			g.Log().Warningf(context.Background(),
+				"[MarketServiceManager] REST K线兜底 goroutine panic: platform=%s, symbol=%s, err=%v", platform, symbol, r)
 		}
-	case "gate":
-		// Gate WS 连接可能比其它交易所更慢（或短暂断线重连）。
-		// 这里不要用 IsRunning() 做硬门槛，否则“订阅请求发生在连接完成之前”会被跳过，导致永远没有K线数据。
-		// SubscribeKline/SubscribeTicker 内部会保存 subscriptions，连接恢复后 onConnected 会自动重放。
-		if m.gateWS != nil {
-			m.gateWS.SubscribeTicker(symbol, func(ticker *exchange.Ticker) {
-				if svc := m.GetService(platform); svc != nil {
-					svc.mu.Lock()
-					svc.Tickers[symbol] = &TickerCache{Data: ticker, UpdatedAt: time.Now()}
-					svc.mu.Unlock()
-				}
-				// 【新增】触发注册的回调函数
-				m.triggerPriceCallbacks(platform, symbol, ticker)
-			})
-			for _, interval := range []string{"1m", "5m", "15m", "30m", "1h"} {
-				_ = m.gateWS.SubscribeKline(symbol, interval, func(klines []*exchange.Kline) {
-					updateSvcKlines(interval, klines)
-				})
-			}
+	}()
+	// candlesticks 可能“慢热”：新订阅后 10~30 秒才可能推第一根K线。
+	// 这里不要在 5~6 秒内就判定“未就绪”，否则会误触发兜底并产生噪声日志。
+	time.Sleep(35 * time.Second)
+	svc := m.GetService(platform)
+	if svc == nil {
+		return
+	}
+	if klineCacheHasAnyData(svc.GetMultiTimeframeKlines(symbol)) {
+		return
+	}
+	// 【Debug级别】Gate机器人通常使用OKX的市场状态和K线数据（analysisPlatform=okx），兜底日志只用于调试，不应该刷屏。
+	g.Log().Debugf(context.Background(),
+		"[MarketServiceManager] WS K线未就绪，触发一次REST兜底补齐: platform=%s, symbol=%s", platform, symbol)
 
-			// 兜底：如果 WS-only 模式下 Gate 长时间收不到 candlesticks（或解析异常），会导致 MarketAnalyzer 永远没有数据。
-			// 这里做一次延迟检查：若仍无任何K线，则触发一次 REST 拉取补齐（仅一次，避免刷接口）。
-			// 目的：让“市场状态/多周期播报”至少可用，即使 Gate WS 的 K线频道不稳定。
-			m.mu.RLock()
-			wsOnly := m.wsOnly
-			m.mu.RUnlock()
-			if wsOnly {
-				p := platform
-				sym := symbol
-				svcPlatform := platform // capture
-				go func() {
-					defer func() {
-						if r := recover(); r != nil {
-							g.Log().Warningf(context.Background(),
-								"[MarketServiceManager] Gate REST K线兜底 goroutine panic: platform=%s, symbol=%s, err=%v", p, sym, r)
-						}
-					}()
-					// Gate candlesticks 可能“慢热”：新订阅后 10~30 秒才可能推第一根K线。
-					// 这里不要在 5~6 秒内就判定“未就绪”，否则会误触发兜底并产生噪声日志。
-					time.Sleep(35 * time.Second)
-					svc := m.GetService(svcPlatform)
-					if svc == nil {
-						return
-					}
-				kc := svc.GetMultiTimeframeKlines(sym)
-				if !klineCacheHasAnyData(kc) {
-					// 【降级为Debug】Gate机器人通常使用OKX的市场状态和K线数据（analysisPlatform=okx），
-					// 所以Gate自己的K线兜底日志只用于调试，不应该刷屏。
-					g.Log().Debugf(context.Background(),
-						"[MarketServiceManager] Gate WS K线未就绪，触发一次REST兜底补齐: platform=%s, symbol=%s", p, sym)
-						// 注意：WS-only 模式下 ExchangeMarketService.fetchAllKlines 会被 WSOnly 短路，无法真正走 REST。
-						// 因此这里直接使用“公共行情服务”拉取 Gate 的 candlesticks（不依赖用户API），并写回 KlineCache，确保 MarketAnalyzer 可产出数据。
-						pms := exchange.GetPublicMarketService()
-						type it struct {
-							interval string
-							limit    int
-						}
-						items := []it{
-							{"1m", 100},
-							{"5m", 100},
-							{"15m", 100},
-							{"30m", 50},
-							{"1h", 50},
-						}
-						now := time.Now()
-						var n1, n5, n15, n30, n1h int
-						for _, item := range items {
-							kl, err := pms.GetKlines(context.Background(), exchange.PlatformGate, sym, item.interval, item.limit)
-							if err != nil || len(kl) == 0 {
-						if err != nil {
-								g.Log().Debugf(context.Background(),
-									"[MarketServiceManager] Gate REST K线兜底失败: platform=%s, symbol=%s, interval=%s, err=%v", p, sym, item.interval, err)
-							}
-								continue
-							}
-							svc.mu.Lock()
-							cache := svc.Klines[sym]
-							if cache == nil {
-								cache = &KlineCache{}
-								svc.Klines[sym] = cache
-							}
-							cache.UpdatedAt = now
-							switch item.interval {
-							case "1m":
-								cache.Klines1m = kl
-								n1 = len(kl)
-							case "5m":
-								cache.Klines5m = kl
-								n5 = len(kl)
-							case "15m":
-								cache.Klines15m = kl
-								n15 = len(kl)
-							case "30m":
-								cache.Klines30m = kl
-								n30 = len(kl)
-							case "1h":
-								cache.Klines1h = kl
-								n1h = len(kl)
-							}
-							svc.mu.Unlock()
-						}
-						g.Log().Debugf(context.Background(),
-							"[MarketServiceManager] Gate REST K线兜底完成: platform=%s, symbol=%s, 1m=%d, 5m=%d, 15m=%d, 30m=%d, 1h=%d",
-							p, sym, n1, n5, n15, n30, n1h)
-					}
-				}()
+	// 注意：WS-only 模式下 ExchangeMarketService.fetchAllKlines 会被 WSOnly 短路，无法真正走 REST。
+	// 因此这里直接使用“公共行情服务”拉取 candlesticks（不依赖用户API），并写回 KlineCache，确保 MarketAnalyzer 可产出数据。
+	pms := exchange.GetPublicMarketService()
+	items := []struct {
+		interval string
+		limit    int
+	}{
+		{"1m", 100},
+		{"5m", 100},
+		{"15m", 100},
+		{"30m", 50},
+		{"1h", 50},
+	}
+	now := time.Now()
+	var n1, n5, n15, n30, n1h int
+	for _, item := range items {
+		kl, err := pms.GetKlines(context.Background(), platform, symbol, item.interval, item.limit)
+		if err != nil || len(kl) == 0 {
+			if err != nil {
+				g.Log().Debugf(context.Background(),
+					"[MarketServiceManager] REST K线兜底失败: platform=%s, symbol=%s, interval=%s, err=%v", platform, symbol, item.interval, err)
 			}
+			continue
+		}
+		svc.mu.Lock()
+		cache := svc.Klines[symbol]
+		if cache == nil {
+			cache = &KlineCache{}
+			svc.Klines[symbol] = cache
 		}
+		cache.UpdatedAt = now
+		switch item.interval {
+		case "1m":
+			cache.Klines1m = kl
+			n1 = len(kl)
+		case "5m":
+			cache.Klines5m = kl
+			n5 = len(kl)
+		case "15m":
+			cache.Klines15m = kl
+			n15 = len(kl)
+		case "30m":
+			cache.Klines30m = kl
+			n30 = len(kl)
+		case "1h":
+			cache.Klines1h = kl
+			n1h = len(kl)
+		}
+		svc.mu.Unlock()
 	}
+	g.Log().Debugf(context.Background(),
+		"[MarketServiceManager] REST K线兜底完成: platform=%s, symbol=%s, 1m=%d, 5m=%d, 15m=%d, 30m=%d, 1h=%d",
+		platform, symbol, n1, n5, n15, n30, n1h)
 }
 
 // subscribeWebSocketQuoteOnly 仅订阅报价（ticker/mark price），不订阅K线/不触发K线兜底。
@@ -682,43 +634,17 @@ func (m *MarketServiceManager) subscribeWebSocketQuoteOnly(ctx context.Context,
 		return
 	}
 
-	switch platform {
-	case "binance":
-		if m.binanceWS != nil && m.binanceWS.IsRunning() {
-			m.binanceWS.SubscribeTicker(symbol, func(ticker *exchange.Ticker) {
-				if svc := m.GetService(platform); svc != nil {
-					svc.mu.Lock()
-					svc.Tickers[symbol] = &TickerCache{Data: ticker, UpdatedAt: time.Now()}
-					svc.mu.Unlock()
-				}
-				m.triggerPriceCallbacks(platform, symbol, ticker)
-			})
-			_ = m.binanceWS.SubscribeMarkPrice(symbol)
-		}
-	case "okx":
-		if m.okxWS != nil && m.okxWS.IsRunning() {
-			m.okxWS.SubscribeTicker(symbol, func(ticker *exchange.Ticker) {
-				if svc := m.GetService(platform); svc != nil {
-					svc.mu.Lock()
-					svc.Tickers[symbol] = &TickerCache{Data: ticker, UpdatedAt: time.Now()}
-					svc.mu.Unlock()
-				}
-				m.triggerPriceCallbacks(platform, symbol, ticker)
-			})
-			_ = m.okxWS.SubscribeMarkPrice(symbol)
-		}
-	case "gate":
-		// Gate quote-only: 只订阅 ticker（不订阅 candlesticks，因此不会打印 Gate K线兜底/未就绪日志）
-		if m.gateWS != nil {
-			m.gateWS.SubscribeTicker(symbol, func(ticker *exchange.Ticker) {
-				if svc := m.GetService(platform); svc != nil {
-					svc.mu.Lock()
-					svc.Tickers[symbol] = &TickerCache{Data: ticker, UpdatedAt: time.Now()}
-					svc.mu.Unlock()
-				}
-				m.triggerPriceCallbacks(platform, symbol, ticker)
-			})
-		}
+	d, ok := exchange.GetDriver(platform)
+	if !ok {
+		return
+	}
+	ws := m.publicStream(platform, !d.PublicStreamReplay)
+	if ws == nil {
+		return
+	}
+	_ = ws.SubscribeTicker(symbol, m.wsTickerCallback(platform, symbol))
+	if mp, ok := ws.(exchange.MarkPriceSubscriber); ok {
+		_ = mp.SubscribeMarkPrice(symbol)
 	}
 }
 
@@ -785,26 +711,17 @@ func (m *MarketServiceManager) unsubscribeWebSocket(platform, symbol string) {
 		return
 	}
 
-	switch platform {
-	case "binance":
-		if m.binanceWS != nil && m.binanceWS.IsRunning() {
-			_ = m.binanceWS.UnsubscribeTicker(symbol)
-			_ = m.binanceWS.UnsubscribeMarkPrice(symbol)
-		}
-	case "okx":
-		if m.okxWS != nil && m.okxWS.IsRunning() {
-			_ = m.okxWS.UnsubscribeTicker(symbol)
-			_ = m.okxWS.UnsubscribeMarkPrice(symbol)
-			for _, interval := range []string{"1m", "5m", "15m", "30m", "1h"} {
-				_ = m.okxWS.UnsubscribeKline(symbol, interval)
-			}
-		}
-	case "gate":
-		if m.gateWS != nil && m.gateWS.IsRunning() {
-			_ = m.gateWS.UnsubscribeTicker(symbol)
-			for _, interval := range []string{"1m", "5m", "15m", "30m", "1h"} {
-				_ = m.gateWS.UnsubscribeKline(symbol, interval)
-			}
+	ws := m.publicStream(platform, true)
+	if ws == nil {
+		return
+	}
+	_ = ws.UnsubscribeTicker(symbol)
+	if mp, ok := ws.(exchange.MarkPriceSubscriber); ok {
+		_ = mp.UnsubscribeMarkPrice(symbol)
+	}
+	if ku, ok := ws.(exchange.KlineUnsubscriber); ok {
+		for _, interval := range []string{"1m", "5m", "15m", "30m", "1h"} {
+			_ = ku.UnsubscribeKline(symbol, interval)
 		}
 	}
 }
@@ -842,19 +759,8 @@ func (m *MarketServiceManager) GetTicker(platform, symbol string) *exchange.Tick
 func (m *MarketServiceManager) getTickerFromWebSocket(platform, symbol string) *exchange.Ticker {
 	platform = normalizePlatform(platform)
 	symbol = normalizeSymbol(symbol)
-	switch platform {
-	case "binance":
-		if m.binanceWS != nil && m.binanceWS.IsRunning() {
-			return m.binanceWS.GetTicker(symbol)
-		}
-	case "okx":
-		if m.okxWS != nil && m.okxWS.IsRunning() {
-			return m.okxWS.GetTicker(symbol)
-		}
-	case "gate":
-		if m.gateWS != nil && m.gateWS.IsRunning() {
-			return m.gateWS.GetTicker(symbol)
-		}
+	if ws := m.publicStream(platform, true); ws != nil {
+		return ws.GetTicker(symbol)
 	}
 	return nil
 }
@@ -888,19 +794,8 @@ func (m *MarketServiceManager) GetKlines(platform, symbol, interval string) []*e
 func (m *MarketServiceManager) getKlinesFromWebSocket(platform, symbol, interval string) []*exchange.Kline {
 	platform = normalizePlatform(platform)
 	symbol = normalizeSymbol(symbol)
-	switch platform {
-	case "binance":
-		if m.binanceWS != nil && m.binanceWS.IsRunning() {
-			return m.binanceWS.GetKlines(symbol, interval)
-		}
-	case "okx":
-		if m.okxWS != nil && m.okxWS.IsRunning() {
-			return m.okxWS.GetKlines(symbol, interval)
-		}
-	case "gate":
-		if m.gateWS != nil && m.gateWS.IsRunning() {
-			return m.gateWS.GetKlines(symbol, interval)
-		}
+	if ws := m.publicStream(platform, true); ws != nil {
+		return ws.GetKlines(symbol, interval)
 	}
 	return nil
 }
@@ -1006,9 +901,10 @@ func (s *ExchangeMarketService) Start(ctx context.Context) {
 
 	// WS-only 模式：禁用所有REST轮询更新（WS回调会写入缓存）
 	if !s.WSOnly {
-		// 启动定时更新任务
-		go s.runTickerUpdater(ctx)
-		go s.runKlineUpdater(ctx)
+		// 启动定时更新任务：行情轮询让位于下单/平仓请求
+		pollCtx := exchange.WithPriority(ctx, exchange.PriorityBackground)
+		go s.runTickerUpdater(pollCtx)
+		go s.runKlineUpdater(pollCtx)
 	}
 
 	g.Log().Infof(ctx, "[ExchangeMarketService] %s 行情服务启动", s.Platform)
@@ -1036,7 +932,7 @@ func (s *ExchangeMarketService) Subscribe(ctx context.Context, symbol string) {
 	if s.Subscriptions[symbol] == 1 {
 		// 首次订阅：WS-only 模式不做REST初始拉取，等待WS回填缓存
 		if !s.WSOnly {
-			go s.fetchInitialData(ctx, symbol)
+			go s.fetchInitialData(exchange.WithPriority(ctx, exchange.PriorityBackground), symbol)
 		}
 	}
 
@@ -1362,10 +1258,8 @@ func (s *ExchangeMarketService) FetchTickerDirect(ctx context.Context, symbol st
 
 // WebSocketStatus WebSocket状态
 type WebSocketStatus struct {
-	Enabled       bool                      `json:"enabled"`
-	BinanceStatus *exchange.BinanceWSStatus `json:"binance"`
-	OKXStatus     *exchange.OKXWSStatus     `json:"okx"`
-	GateStatus    *exchange.GateWSStatus    `json:"gate"`
+	Enabled bool                   `json:"enabled"`
+	Streams map[string]interface{} `json:"streams"` // key: platform，value: 各交易所公共WS状态
 }
 
 // GetWebSocketStatus 获取WebSocket状态
@@ -1375,18 +1269,15 @@ func (m *MarketServiceManager) GetWebSocketStatus() *WebSocketStatus {
 
 	status := &WebSocketStatus{
 		Enabled: m.wsEnabled,
+		Streams: make(map[string]interface{}, len(m.publicStreams)),
 	}
-
-	if m.binanceWS != nil {
-		status.BinanceStatus = m.binanceWS.GetStatus()
-	}
-	if m.okxWS != nil {
-		status.OKXStatus = m.okxWS.GetStatus()
-	}
-	if m.gateWS != nil {
-		status.GateStatus = m.gateWS.GetStatus()
+	for platform, ws := range m.publicStreams {
+		if sp, ok := ws.(exchange.PublicStreamStatusProvider); ok {
+			status.Streams[platform] = sp.Status()
+		} else {
+			status.Streams[platform] = ws.GetConnectionState()
+		}
 	}
-
 	return status
 }
 
@@ -1421,8 +1312,9 @@ func (m *MarketServiceManager) DisableWebSocket() {
 	}
 
 	m.wsEnabled = false
-	if m.binanceWS != nil {
-		m.binanceWS.Stop()
+	for platform, ws := range m.publicStreams {
+		ws.Stop()
+		delete(m.publicStreams, platform)
 	}
 
 	g.Log().Info(context.Background(), "[MarketServiceManager] WebSocket已禁用")
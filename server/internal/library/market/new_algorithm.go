@@ -13,6 +13,13 @@ import (
 
 var marketAnalyzerInsufficientDataLogAt sync.Map // key: platform:symbol, value: time.Time
 
+// 平滑参数（默认值：不改数据库/页面即可生效，离线评估工具也以此为默认值）
+const (
+	StateSmoothTimeframeWindow = 5   // 单周期状态平滑窗口（1秒一轮，5表示约5秒）
+	StateSmoothFinalWindow     = 7   // 最终状态平滑窗口
+	StateSmoothMinRatio        = 0.6 // 多数占比阈值，>=60% 才切换到多数态
+)
+
 // MarketStateThresholds 定义每个周期的状态阈值
 type MarketStateThresholds struct {
 	LowV       float64 // V小于LowV -> 低波动
@@ -155,6 +162,9 @@ func (a *MarketAnalyzer) AnalyzeMarketWithNewAlgorithm(
 		}
 	}
 
+	// 学习循环已生效的周期权重（影子模式下不生效）
+	volatilityConfig = applyLearnedTimeframeWeights(symbol, volatilityConfig)
+
 	// 2. 多周期配置（使用配置中的权重）
 	timeframes := []struct {
 		interval  string
@@ -214,13 +224,10 @@ func (a *MarketAnalyzer) AnalyzeMarketWithNewAlgorithm(
 	key := platform + ":" + symbol
 	smoother := a.getOrCreateStateSmoother(key)
 
-	// 平滑参数（默认值：不改数据库/页面即可生效）
-	// - timeframeWindow: 单周期状态平滑窗口（1秒一轮，5表示约5秒）
-	// - finalWindow: 最终状态平滑窗口
-	// - minRatio: 多数占比阈值，>=60% 才切换到多数态
-	const timeframeWindow = 5
-	const finalWindow = 7
-	const minRatio = 0.6
+	// 平滑参数（默认值见 StateSmoothTimeframeWindow 等常量）
+	const timeframeWindow = StateSmoothTimeframeWindow
+	const finalWindow = StateSmoothFinalWindow
+	const minRatio = StateSmoothMinRatio
 
 	var O, H, L, P, delta []float64
 	var thresh []MarketStateThresholds
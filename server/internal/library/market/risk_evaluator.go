@@ -155,7 +155,7 @@ func (r *RiskEvaluator) EvaluateRisk(ctx context.Context, robotCtx *RobotContext
 	eval.VolatilityRisk = r.evaluateVolatilityRisk(analysis)
 
 	// 6. 计算综合胜算概率
-	eval.WinProbability = r.calculateWinProbability(eval)
+	eval.WinProbability = r.calculateWinProbability(eval, GetSignalWeightLearner().RiskWeights(robotCtx.Symbol, learningMarketState(analysis.MarketState)))
 
 	// 7. 判定风险偏好
 	eval.RiskPreference = r.determineRiskPreference(eval, robotCtx, analysis)
@@ -327,21 +327,13 @@ func (r *RiskEvaluator) evaluateVolatilityRisk(analysis *MarketAnalysis) float64
 }
 
 // calculateWinProbability 计算胜算概率
-func (r *RiskEvaluator) calculateWinProbability(eval *RiskEvaluation) float64 {
-	// 加权计算胜算概率
-	weights := map[string]float64{
-		"market":     0.25,
-		"technical":  0.25,
-		"account":    0.20,
-		"history":    0.20,
-		"volatility": 0.10, // 波动风险是负向影响
-	}
-
-	probability := eval.MarketScore*weights["market"] +
-		eval.TechnicalScore*weights["technical"] +
-		eval.AccountScore*weights["account"] +
-		eval.HistoryScore*weights["history"] -
-		eval.VolatilityRisk*weights["volatility"]
+// weights 为维度权重：默认 DefaultRiskWeights，学习循环生效后按交易对+市场状态使用学习权重
+func (r *RiskEvaluator) calculateWinProbability(eval *RiskEvaluation, weights map[string]float64) float64 {
+	probability := eval.MarketScore*weights[RiskDimMarket] +
+		eval.TechnicalScore*weights[RiskDimTechnical] +
+		eval.AccountScore*weights[RiskDimAccount] +
+		eval.HistoryScore*weights[RiskDimHistory] -
+		eval.VolatilityRisk*weights[RiskDimVolatility] // 波动风险是负向影响
 
 	return math.Min(100, math.Max(0, probability))
 }
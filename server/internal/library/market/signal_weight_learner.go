@@ -0,0 +1,253 @@
+// Package market 信号权重自适应学习
+// 学习循环（logic/toogo/ai_learning.go）按已平仓订单的实际结果统计各周期方向的准确率，
+// 在管理员设定的边界内给出新的周期投票权重/风险评估权重；影子模式下只生成报告，
+// 正式模式下通过本文件的全局注册表生效于 AnalyzeMarketWithNewAlgorithm 与 RiskEvaluator。
+package market
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	configlib "hotgo/internal/library/config"
+)
+
+// LearningTimeframes 参与学习的周期（与新算法投票周期一致）
+var LearningTimeframes = []string{"1m", "5m", "15m", "30m", "1h"}
+
+// 风险评估维度
+const (
+	RiskDimMarket     = "market"
+	RiskDimTechnical  = "technical"
+	RiskDimAccount    = "account"
+	RiskDimHistory    = "history"
+	RiskDimVolatility = "volatility"
+)
+
+// DefaultRiskWeights 风险评估默认维度权重（与 calculateWinProbability 原有口径一致）
+var DefaultRiskWeights = map[string]float64{
+	RiskDimMarket:     0.25,
+	RiskDimTechnical:  0.25,
+	RiskDimAccount:    0.20,
+	RiskDimHistory:    0.20,
+	RiskDimVolatility: 0.10, // 波动风险是负向影响
+}
+
+// SignalLearningStat 某个信号来源（周期/维度）的统计
+type SignalLearningStat struct {
+	TotalSignals   int     `json:"totalSignals"`
+	CorrectSignals int     `json:"correctSignals"`
+	TotalProfit    float64 `json:"totalProfit"`
+}
+
+// Accuracy 拉普拉斯平滑后的准确率，样本少时趋近0.5
+func (s SignalLearningStat) Accuracy() float64 {
+	return (float64(s.CorrectSignals) + 1) / (float64(s.TotalSignals) + 2)
+}
+
+// SignalLearningBounds 学习边界（管理员配置）
+type SignalLearningBounds struct {
+	MinSamples int     `json:"minSamples"` // 样本数未达到时不调整该来源
+	WeightMin  float64 `json:"weightMin"`  // 单个权重下限
+	WeightMax  float64 `json:"weightMax"`  // 单个权重上限
+	MaxStep    float64 `json:"maxStep"`    // 单次学习最大调整幅度
+}
+
+// ProposeLearnedWeights 根据统计在边界内给出新权重
+// 规则：目标权重 = 当前权重 × (准确率/0.5)，单次调整不超过 MaxStep，
+// 再按原权重总和归一化并限制在 [WeightMin, WeightMax]；样本不足的来源保持不变。
+func ProposeLearnedWeights(base map[string]float64, stats map[string]SignalLearningStat, bounds SignalLearningBounds) map[string]float64 {
+	out := make(map[string]float64, len(base))
+	var baseSum, outSum float64
+	for key, w := range base {
+		baseSum += w
+		st, ok := stats[key]
+		if !ok || st.TotalSignals < bounds.MinSamples {
+			out[key] = w
+			outSum += w
+			continue
+		}
+		target := w * st.Accuracy() / 0.5
+		if bounds.MaxStep > 0 {
+			target = w + math.Max(-bounds.MaxStep, math.Min(bounds.MaxStep, target-w))
+		}
+		out[key] = clampLearnedWeight(target, bounds)
+		outSum += out[key]
+	}
+	if outSum <= 0 || baseSum <= 0 {
+		return out
+	}
+	for key, w := range out {
+		out[key] = clampLearnedWeight(w*baseSum/outSum, bounds)
+	}
+	return out
+}
+
+func clampLearnedWeight(w float64, bounds SignalLearningBounds) float64 {
+	if bounds.WeightMin > 0 && w < bounds.WeightMin {
+		w = bounds.WeightMin
+	}
+	if bounds.WeightMax > 0 && w > bounds.WeightMax {
+		w = bounds.WeightMax
+	}
+	return math.Round(w*10000) / 10000
+}
+
+// SignalWeightLearner 已生效的学习权重注册表（单例，仅在非影子模式下写入）
+type SignalWeightLearner struct {
+	mu sync.RWMutex
+	// timeframeWeights key: symbol -> interval -> weight
+	timeframeWeights map[string]map[string]float64
+	// riskWeights key: symbol:marketState -> dimension -> weight
+	riskWeights map[string]map[string]float64
+}
+
+var (
+	signalWeightLearner     *SignalWeightLearner
+	signalWeightLearnerOnce sync.Once
+)
+
+// GetSignalWeightLearner 获取学习权重注册表单例
+func GetSignalWeightLearner() *SignalWeightLearner {
+	signalWeightLearnerOnce.Do(func() {
+		signalWeightLearner = &SignalWeightLearner{
+			timeframeWeights: make(map[string]map[string]float64),
+			riskWeights:      make(map[string]map[string]float64),
+		}
+	})
+	return signalWeightLearner
+}
+
+// SetTimeframeWeights 设置交易对的周期投票权重
+func (l *SignalWeightLearner) SetTimeframeWeights(symbol string, weights map[string]float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.timeframeWeights[NormalizeSymbol(symbol)] = copyWeights(weights)
+}
+
+// TimeframeWeights 获取交易对已生效的周期投票权重，未学习返回nil
+func (l *SignalWeightLearner) TimeframeWeights(symbol string) map[string]float64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return copyWeights(l.timeframeWeights[NormalizeSymbol(symbol)])
+}
+
+// SetRiskWeights 设置交易对在某市场状态下的风险评估维度权重
+func (l *SignalWeightLearner) SetRiskWeights(symbol, marketState string, weights map[string]float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.riskWeights[NormalizeSymbol(symbol)+":"+marketState] = copyWeights(weights)
+}
+
+// RiskWeights 获取风险评估维度权重，未学习时返回默认权重
+func (l *SignalWeightLearner) RiskWeights(symbol, marketState string) map[string]float64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if w, ok := l.riskWeights[NormalizeSymbol(symbol)+":"+marketState]; ok {
+		return copyWeights(w)
+	}
+	return copyWeights(DefaultRiskWeights)
+}
+
+// Reset 清空已生效的学习权重（切回影子模式/关闭学习时调用）
+func (l *SignalWeightLearner) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.timeframeWeights = make(map[string]map[string]float64)
+	l.riskWeights = make(map[string]map[string]float64)
+}
+
+// Symbols 已生效学习权重的交易对列表
+func (l *SignalWeightLearner) Symbols() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	out := make([]string, 0, len(l.timeframeWeights))
+	for symbol := range l.timeframeWeights {
+		out = append(out, symbol)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func copyWeights(src map[string]float64) map[string]float64 {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[string]float64, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// SignalSnapshot 下单时的信号快照（保存在订单 signal_snapshot 字段，供学习循环打分）
+type SignalSnapshot struct {
+	MarketState    string            `json:"marketState"`    // 最终市场状态
+	Directions     map[string]string `json:"directions"`     // 各周期最新K线方向 up/down
+	States         map[string]string `json:"states"`         // 各周期平滑后的状态
+	MarketScore    float64           `json:"marketScore"`    // 风险评估-市场得分
+	TechnicalScore float64           `json:"technicalScore"` // 风险评估-技术得分
+	VolatilityRisk float64           `json:"volatilityRisk"` // 风险评估-波动风险
+}
+
+// BuildSignalSnapshot 从当前市场分析结果生成信号快照，无分析数据返回nil
+func BuildSignalSnapshot(platform, symbol string) *SignalSnapshot {
+	analysis := GetMarketAnalyzer().GetAnalysis(platform, symbol)
+	if analysis == nil {
+		return nil
+	}
+	snap := &SignalSnapshot{
+		MarketState: learningMarketState(analysis.MarketState),
+		Directions:  make(map[string]string, len(analysis.TimeframeAnalysis)),
+		States:      make(map[string]string, len(analysis.TimeframeAnalysis)),
+	}
+	for interval, tf := range analysis.TimeframeAnalysis {
+		if tf == nil {
+			continue
+		}
+		switch {
+		case tf.Close > tf.Open:
+			snap.Directions[interval] = "up"
+		case tf.Close < tf.Open:
+			snap.Directions[interval] = "down"
+		}
+		snap.States[interval] = tf.SmoothedState
+	}
+	r := GetRiskEvaluator()
+	snap.MarketScore = r.evaluateMarketScore(analysis)
+	snap.TechnicalScore = r.evaluateTechnicalScore(analysis)
+	snap.VolatilityRisk = r.evaluateVolatilityRisk(analysis)
+	return snap
+}
+
+// applyLearnedTimeframeWeights 用已生效的学习权重覆盖波动率配置中的周期权重（返回副本，不修改全局配置）
+func applyLearnedTimeframeWeights(symbol string, cfg *configlib.VolatilityConfig) *configlib.VolatilityConfig {
+	learned := GetSignalWeightLearner().TimeframeWeights(symbol)
+	if cfg == nil || len(learned) == 0 {
+		return cfg
+	}
+	out := *cfg
+	for interval, w := range learned {
+		switch interval {
+		case "1m":
+			out.Weight1m = w
+		case "5m":
+			out.Weight5m = w
+		case "15m":
+			out.Weight15m = w
+		case "30m":
+			out.Weight30m = w
+		case "1h":
+			out.Weight1h = w
+		}
+	}
+	return &out
+}
+
+// learningMarketState 将 MarketState 转为学习表使用的统一口径（trend/volatile/high_vol/low_vol）
+func learningMarketState(state MarketState) string {
+	if state == MarketStateVolatile {
+		return "volatile"
+	}
+	return string(state)
+}
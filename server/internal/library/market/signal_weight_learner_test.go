@@ -0,0 +1,53 @@
+// Package market
+// @Description 信号权重学习测试
+package market
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProposeLearnedWeights(t *testing.T) {
+	base := map[string]float64{"1m": 0.2, "5m": 0.3, "15m": 0.5}
+	bounds := SignalLearningBounds{MinSamples: 30, WeightMin: 0.05, WeightMax: 0.6, MaxStep: 0.05}
+	stats := map[string]SignalLearningStat{
+		"1m":  {TotalSignals: 100, CorrectSignals: 80},
+		"5m":  {TotalSignals: 100, CorrectSignals: 30},
+		"15m": {TotalSignals: 10, CorrectSignals: 10}, // 样本不足
+	}
+
+	got := ProposeLearnedWeights(base, stats, bounds)
+
+	if got["1m"] <= base["1m"] {
+		t.Errorf("1m: got %v, want > %v", got["1m"], base["1m"])
+	}
+	if got["5m"] >= base["5m"] {
+		t.Errorf("5m: got %v, want < %v", got["5m"], base["5m"])
+	}
+	for key, w := range got {
+		if math.Abs(w-base[key]) > bounds.MaxStep+0.01 {
+			t.Errorf("%v: step too large, got %v from %v", key, w, base[key])
+		}
+	}
+	sum := got["1m"] + got["5m"] + got["15m"]
+	if math.Abs(sum-1) > 0.001 {
+		t.Errorf("sum: got %v, want 1", sum)
+	}
+}
+
+func TestSignalWeightLearnerFallback(t *testing.T) {
+	l := GetSignalWeightLearner()
+	l.Reset()
+	defer l.Reset()
+
+	if w := l.RiskWeights("BTCUSDT", "trend"); w[RiskDimMarket] != DefaultRiskWeights[RiskDimMarket] {
+		t.Errorf("default risk weights expected, got %v", w)
+	}
+	l.SetRiskWeights("BTC-USDT", "trend", map[string]float64{RiskDimMarket: 0.3})
+	if w := l.RiskWeights("BTCUSDT", "trend"); w[RiskDimMarket] != 0.3 {
+		t.Errorf("learned risk weights expected, got %v", w)
+	}
+	if w := l.TimeframeWeights("BTCUSDT"); w != nil {
+		t.Errorf("no timeframe weights expected, got %v", w)
+	}
+}
@@ -0,0 +1,476 @@
+// Package market 市场状态分类器离线评估
+// 用历史1m K线回放新算法（DetectMarketStateSingle + 多周期投票 + 平滑器），
+// 以“前瞻实际走势”作为标签，输出混淆矩阵、状态切换频率以及各状态映射策略模板的模拟表现，
+// 用于对比两套阈值/平滑参数，避免凭感觉调参。
+package market
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+
+	configlib "hotgo/internal/library/config"
+	"hotgo/internal/library/exchange"
+)
+
+// 前瞻走势标签
+const (
+	EvalLabelTrend      = "trend"       // 趋势延续：前瞻区间内价格单向推进
+	EvalLabelMeanRevert = "mean_revert" // 均值回归：振幅足够但来回拉锯
+	EvalLabelRange      = "range"       // 窄幅区间：振幅很小
+)
+
+// evalStates 分类器输出状态（固定顺序，保证报告与投票结果可复现）
+var evalStates = []string{"trend", "volatile", "high_vol", "low_vol"}
+
+// evalLabels 前瞻标签（固定顺序）
+var evalLabels = []string{EvalLabelTrend, EvalLabelMeanRevert, EvalLabelRange}
+
+// evalExpectedLabel 分类器状态 -> 期望的前瞻走势（用于计算命中率）
+var evalExpectedLabel = map[string]string{
+	"trend":    EvalLabelTrend,
+	"volatile": EvalLabelMeanRevert,
+	"high_vol": EvalLabelMeanRevert,
+	"low_vol":  EvalLabelRange,
+}
+
+// evalTimeframes 参与投票的周期（分钟数），与 AnalyzeMarketWithNewAlgorithm 保持一致
+var evalTimeframes = []struct {
+	interval string
+	minutes  int
+}{
+	{"1m", 1}, {"5m", 5}, {"15m", 15}, {"30m", 30}, {"1h", 60},
+}
+
+// StateEvalConfig 一组待评估的分类器配置
+type StateEvalConfig struct {
+	Name            string                      `json:"name"`            // 配置名称（报告展示用）
+	Volatility      *configlib.VolatilityConfig `json:"volatility"`      // 阈值/delta/权重
+	TimeframeWindow int                         `json:"timeframeWindow"` // 单周期平滑窗口（评估步数）
+	FinalWindow     int                         `json:"finalWindow"`     // 最终状态平滑窗口（评估步数）
+	MinRatio        float64                     `json:"minRatio"`        // 多数占比阈值
+}
+
+// StateEvalTemplate 映射到某个市场状态的策略模板参数（窗口突破模拟所需字段）
+type StateEvalTemplate struct {
+	StrategyName            string  `json:"strategyName"`
+	MonitorWindow           int     `json:"monitorWindow"`           // 监控时间窗口(秒)
+	VolatilityThreshold     float64 `json:"volatilityThreshold"`     // 波动阈值(USDT)
+	Leverage                int     `json:"leverage"`                // 杠杆倍数
+	StopLossPercent         float64 `json:"stopLossPercent"`         // 止损百分比(%)
+	AutoStartRetreatPercent float64 `json:"autoStartRetreatPercent"` // 启动止盈百分比(%)
+	ProfitRetreatPercent    float64 `json:"profitRetreatPercent"`    // 止盈回撤百分比(%)
+}
+
+// StateEvalOptions 评估参数
+type StateEvalOptions struct {
+	Horizon         int                           // 前瞻K线数(1m)，默认15
+	TrendEfficiency float64                       // 趋势效率阈值：|净位移|/路径长度，默认0.5
+	RangeMaxPercent float64                       // 窄幅区间振幅上限(%)，默认0.15
+	Warmup          int                           // 预热K线数（保证1h周期有数据），默认60
+	FeePercent      float64                       // 单边手续费(%)，默认0.05，传负数表示不计手续费
+	Templates       map[string]*StateEvalTemplate // key: 市场状态(trend/volatile/high_vol/low_vol)
+}
+
+// StateEvalTemplateStats 某状态下策略模板的模拟表现
+type StateEvalTemplateStats struct {
+	StrategyName    string  `json:"strategyName"`
+	Trades          int     `json:"trades"`
+	Wins            int     `json:"wins"`
+	WinRate         float64 `json:"winRate"`         // 胜率(%)
+	TotalPnlPercent float64 `json:"totalPnlPercent"` // 累计收益(占保证金%)
+	AvgPnlPercent   float64 `json:"avgPnlPercent"`   // 单笔平均收益(占保证金%)
+	MaxDrawdown     float64 `json:"maxDrawdown"`     // 累计收益曲线最大回撤(占保证金%)
+}
+
+// StateEvalReport 单个配置的评估报告
+type StateEvalReport struct {
+	Config          string                             `json:"config"`
+	Steps           int                                `json:"steps"`           // 有效评估步数
+	Confusion       map[string]map[string]int          `json:"confusion"`       // 预测状态 -> 前瞻标签 -> 次数
+	HitRate         float64                            `json:"hitRate"`         // 命中率(%)
+	StateShare      map[string]float64                 `json:"stateShare"`      // 各状态占比(%)
+	LabelShare      map[string]float64                 `json:"labelShare"`      // 各标签占比(%)
+	Flips           int                                `json:"flips"`           // 最终状态切换次数
+	FlipsPerHour    float64                            `json:"flipsPerHour"`    // 每小时切换次数
+	AvgDwellMinutes float64                            `json:"avgDwellMinutes"` // 平均状态持续时长(分钟)
+	Templates       map[string]*StateEvalTemplateStats `json:"templates"`       // 市场状态 -> 模板表现
+}
+
+// StateEvalDiff 两份报告的单项指标对比
+type StateEvalDiff struct {
+	Metric string  `json:"metric"`
+	A      float64 `json:"a"`
+	B      float64 `json:"b"`
+	Delta  float64 `json:"delta"`
+}
+
+// DefaultStateEvalConfig 使用与线上一致的默认平滑参数构建评估配置
+func DefaultStateEvalConfig(name string, vc *configlib.VolatilityConfig) *StateEvalConfig {
+	return &StateEvalConfig{
+		Name:            name,
+		Volatility:      vc,
+		TimeframeWindow: StateSmoothTimeframeWindow,
+		FinalWindow:     StateSmoothFinalWindow,
+		MinRatio:        StateSmoothMinRatio,
+	}
+}
+
+func (o *StateEvalOptions) withDefaults() StateEvalOptions {
+	out := StateEvalOptions{}
+	if o != nil {
+		out = *o
+	}
+	if out.Horizon <= 0 {
+		out.Horizon = 15
+	}
+	if out.TrendEfficiency <= 0 {
+		out.TrendEfficiency = 0.5
+	}
+	if out.RangeMaxPercent <= 0 {
+		out.RangeMaxPercent = 0.15
+	}
+	if out.Warmup <= 0 {
+		out.Warmup = 60
+	}
+	if out.FeePercent < 0 {
+		out.FeePercent = 0
+	} else if out.FeePercent == 0 {
+		out.FeePercent = 0.05
+	}
+	return out
+}
+
+// EvaluateMarketStates 用历史1m K线回放分类器并生成评估报告
+func EvaluateMarketStates(klines []*exchange.Kline, cfg *StateEvalConfig, opts *StateEvalOptions) (*StateEvalReport, error) {
+	if cfg == nil || cfg.Volatility == nil {
+		return nil, gerror.New("评估配置不能为空")
+	}
+	o := opts.withDefaults()
+
+	bars := make([]*exchange.Kline, 0, len(klines))
+	for _, k := range klines {
+		if k != nil && k.High >= k.Low && k.Close > 0 {
+			bars = append(bars, k)
+		}
+	}
+	sort.SliceStable(bars, func(i, j int) bool { return bars[i].OpenTime < bars[j].OpenTime })
+	if len(bars) <= o.Warmup+o.Horizon {
+		return nil, gerror.Newf("K线数量不足: 需要大于%d根，实际%d根", o.Warmup+o.Horizon, len(bars))
+	}
+
+	report := &StateEvalReport{
+		Config:     cfg.Name,
+		Confusion:  make(map[string]map[string]int, len(evalStates)),
+		StateShare: make(map[string]float64, len(evalStates)),
+		LabelShare: make(map[string]float64, len(evalLabels)),
+		Templates:  make(map[string]*StateEvalTemplateStats),
+	}
+	for _, st := range evalStates {
+		report.Confusion[st] = make(map[string]int, len(evalLabels))
+	}
+
+	smoother := newMarketStateSmoother()
+	states := make([]string, len(bars))
+	labelCount := make(map[string]int, len(evalLabels))
+	stateCount := make(map[string]int, len(evalStates))
+	hits := 0
+	prev := ""
+
+	for t := o.Warmup; t < len(bars)-o.Horizon; t++ {
+		state := classifyEvalStep(bars, t, cfg, smoother)
+		states[t] = state
+		label := labelForwardBehaviour(bars[t:t+o.Horizon+1], o.TrendEfficiency, o.RangeMaxPercent)
+
+		report.Confusion[state][label]++
+		stateCount[state]++
+		labelCount[label]++
+		if evalExpectedLabel[state] == label {
+			hits++
+		}
+		if prev != "" && state != prev {
+			report.Flips++
+		}
+		prev = state
+		report.Steps++
+	}
+
+	steps := float64(report.Steps)
+	report.HitRate = float64(hits) / steps * 100
+	for _, st := range evalStates {
+		report.StateShare[st] = float64(stateCount[st]) / steps * 100
+	}
+	for _, lb := range evalLabels {
+		report.LabelShare[lb] = float64(labelCount[lb]) / steps * 100
+	}
+	report.FlipsPerHour = float64(report.Flips) / (steps / 60)
+	report.AvgDwellMinutes = steps / float64(report.Flips+1)
+
+	if len(o.Templates) > 0 {
+		report.Templates = simulateStateTemplates(bars, states, o)
+	}
+	return report, nil
+}
+
+// classifyEvalStep 在第t根1m K线收盘时，按线上逻辑计算最终市场状态
+// 说明：线上每秒用各周期“当前未收盘K线”计算，这里以1m为步长，用截至t的部分聚合K线近似
+func classifyEvalStep(bars []*exchange.Kline, t int, cfg *StateEvalConfig, smoother *marketStateSmoother) string {
+	vc := cfg.Volatility
+	thresh := MarketStateThresholds{
+		LowV:       vc.LowVolatilityThreshold,
+		HighV:      vc.HighVolatilityThreshold,
+		TrendV:     vc.TrendStrengthThreshold,
+		DThreshold: vc.DThreshold,
+	}
+	params := map[string][2]float64{ // interval -> {delta, weight}
+		"1m":  {vc.Delta1m, vc.Weight1m},
+		"5m":  {vc.Delta5m, vc.Weight5m},
+		"15m": {vc.Delta15m, vc.Weight15m},
+		"30m": {vc.Delta30m, vc.Weight30m},
+		"1h":  {vc.Delta1h, vc.Weight1h},
+	}
+
+	votes := make(map[string]float64, len(evalStates))
+	for _, tf := range evalTimeframes {
+		o, h, l, c, ok := aggregatePartialBar(bars, t, tf.minutes)
+		if !ok {
+			continue
+		}
+		p := params[tf.interval]
+		raw := DetectMarketStateSingle(o, h, l, c, p[0], thresh)
+		smoothed, _ := smoother.pushAndSmoothTimeframe(tf.interval, raw, cfg.TimeframeWindow, cfg.MinRatio)
+		votes[smoothed] += p[1]
+	}
+
+	finalRaw := "volatile"
+	maxVote := 0.0
+	for _, st := range evalStates {
+		if votes[st] > maxVote {
+			maxVote = votes[st]
+			finalRaw = st
+		}
+	}
+	final, _ := smoother.pushAndSmoothFinal(finalRaw, cfg.FinalWindow, cfg.MinRatio)
+	return final
+}
+
+// aggregatePartialBar 把截至第t根的1m K线聚合成所属周期的“当前K线”
+func aggregatePartialBar(bars []*exchange.Kline, t, minutes int) (o, h, l, c float64, ok bool) {
+	span := int64(minutes) * 60 * 1000
+	bucketStart := bars[t].OpenTime - bars[t].OpenTime%span
+	start := t
+	for start > 0 && t-start < minutes-1 && bars[start-1].OpenTime >= bucketStart {
+		start--
+	}
+	o, h, l, c = bars[start].Open, bars[start].High, bars[start].Low, bars[t].Close
+	for i := start + 1; i <= t; i++ {
+		h = math.Max(h, bars[i].High)
+		l = math.Min(l, bars[i].Low)
+	}
+	return o, h, l, c, o > 0
+}
+
+// labelForwardBehaviour 根据前瞻K线（window[0] 为当前K线）标注实际走势
+func labelForwardBehaviour(window []*exchange.Kline, trendEfficiency, rangeMaxPercent float64) string {
+	base := window[0].Close
+	high, low := base, base
+	path := 0.0
+	for i := 1; i < len(window); i++ {
+		high = math.Max(high, window[i].High)
+		low = math.Min(low, window[i].Low)
+		path += math.Abs(window[i].Close - window[i-1].Close)
+	}
+	if base <= 0 || (high-low)/base*100 < rangeMaxPercent {
+		return EvalLabelRange
+	}
+	net := math.Abs(window[len(window)-1].Close - base)
+	if path > 0 && net/path >= trendEfficiency {
+		return EvalLabelTrend
+	}
+	return EvalLabelMeanRevert
+}
+
+// evalPosition 模拟持仓
+type evalPosition struct {
+	state      string
+	dir        float64 // 1=多 -1=空
+	entry      float64
+	tpl        *StateEvalTemplate
+	highestPnl float64 // 最高盈利(占保证金%)
+	trailing   bool    // 是否已启动止盈回撤
+}
+
+// simulateStateTemplates 按状态映射的策略模板做窗口突破模拟（与 EvaluateWindowSignal 同口径：
+// 实时价-窗口最低价>=阈值做多，窗口最高价-实时价>=阈值做空，双向同时触发不开仓），单仓位。
+func simulateStateTemplates(bars []*exchange.Kline, states []string, o StateEvalOptions) map[string]*StateEvalTemplateStats {
+	stats := make(map[string]*StateEvalTemplateStats, len(o.Templates))
+	equity := make(map[string]float64, len(o.Templates))
+	peak := make(map[string]float64, len(o.Templates))
+	for st, tpl := range o.Templates {
+		if tpl != nil {
+			stats[st] = &StateEvalTemplateStats{StrategyName: tpl.StrategyName}
+		}
+	}
+
+	closePos := func(pos *evalPosition, pnl float64) {
+		s := stats[pos.state]
+		pnl -= 2 * o.FeePercent * float64(pos.tpl.Leverage)
+		s.Trades++
+		if pnl > 0 {
+			s.Wins++
+		}
+		s.TotalPnlPercent += pnl
+		equity[pos.state] += pnl
+		if equity[pos.state] > peak[pos.state] {
+			peak[pos.state] = equity[pos.state]
+		}
+		if dd := peak[pos.state] - equity[pos.state]; dd > s.MaxDrawdown {
+			s.MaxDrawdown = dd
+		}
+	}
+
+	var pos *evalPosition
+	for t := o.Warmup; t < len(bars)-o.Horizon; t++ {
+		bar := bars[t]
+		if pos != nil {
+			lev := float64(pos.tpl.Leverage)
+			pnlAt := func(price float64) float64 { return (price - pos.entry) / pos.entry * 100 * lev * pos.dir }
+			worst, best := bar.Low, bar.High
+			if pos.dir < 0 {
+				worst, best = bar.High, bar.Low
+			}
+			// 保守口径：同一根K线内先判止损，再更新最高盈利
+			if pos.tpl.StopLossPercent > 0 && pnlAt(worst) <= -pos.tpl.StopLossPercent {
+				closePos(pos, -pos.tpl.StopLossPercent)
+				pos = nil
+				continue
+			}
+			pos.highestPnl = math.Max(pos.highestPnl, pnlAt(best))
+			if !pos.trailing && pos.tpl.AutoStartRetreatPercent > 0 && pos.highestPnl >= pos.tpl.AutoStartRetreatPercent {
+				pos.trailing = true
+			}
+			cur := pnlAt(bar.Close)
+			if pos.trailing && pos.tpl.ProfitRetreatPercent > 0 && pos.highestPnl > 0 &&
+				(pos.highestPnl-cur)/pos.highestPnl*100 >= pos.tpl.ProfitRetreatPercent {
+				closePos(pos, cur)
+				pos = nil
+			}
+			continue
+		}
+
+		tpl := o.Templates[states[t]]
+		if tpl == nil || tpl.VolatilityThreshold <= 0 || tpl.Leverage <= 0 {
+			continue
+		}
+		window := int(math.Ceil(float64(tpl.MonitorWindow) / 60))
+		if window < 1 {
+			window = 1
+		}
+		minP, maxP := bar.Low, bar.High
+		for i := t - 1; i >= 0 && i > t-window; i-- {
+			minP = math.Min(minP, bars[i].Low)
+			maxP = math.Max(maxP, bars[i].High)
+		}
+		longHit := bar.Close-minP >= tpl.VolatilityThreshold
+		shortHit := maxP-bar.Close >= tpl.VolatilityThreshold
+		switch {
+		case longHit && !shortHit:
+			pos = &evalPosition{state: states[t], dir: 1, entry: bar.Close, tpl: tpl}
+		case shortHit && !longHit:
+			pos = &evalPosition{state: states[t], dir: -1, entry: bar.Close, tpl: tpl}
+		}
+	}
+	// 评估区间结束仍持仓：按最后一根收盘价平仓
+	if pos != nil {
+		last := bars[len(bars)-o.Horizon-1].Close
+		closePos(pos, (last-pos.entry)/pos.entry*100*float64(pos.tpl.Leverage)*pos.dir)
+	}
+
+	for _, s := range stats {
+		if s.Trades > 0 {
+			s.WinRate = float64(s.Wins) / float64(s.Trades) * 100
+			s.AvgPnlPercent = s.TotalPnlPercent / float64(s.Trades)
+		}
+	}
+	return stats
+}
+
+// CompareStateEvalReports 对比两份报告的关键指标（B - A）
+func CompareStateEvalReports(a, b *StateEvalReport) []StateEvalDiff {
+	if a == nil || b == nil {
+		return nil
+	}
+	diff := func(metric string, va, vb float64) StateEvalDiff {
+		return StateEvalDiff{Metric: metric, A: va, B: vb, Delta: vb - va}
+	}
+	out := []StateEvalDiff{
+		diff("hitRate(%)", a.HitRate, b.HitRate),
+		diff("flipsPerHour", a.FlipsPerHour, b.FlipsPerHour),
+		diff("avgDwellMinutes", a.AvgDwellMinutes, b.AvgDwellMinutes),
+	}
+	for _, st := range evalStates {
+		out = append(out, diff("share."+st+"(%)", a.StateShare[st], b.StateShare[st]))
+	}
+	for _, st := range evalStates {
+		sa, sb := a.Templates[st], b.Templates[st]
+		if sa == nil && sb == nil {
+			continue
+		}
+		if sa == nil {
+			sa = &StateEvalTemplateStats{}
+		}
+		if sb == nil {
+			sb = &StateEvalTemplateStats{}
+		}
+		out = append(out,
+			diff("template."+st+".trades", float64(sa.Trades), float64(sb.Trades)),
+			diff("template."+st+".winRate(%)", sa.WinRate, sb.WinRate),
+			diff("template."+st+".totalPnl(%)", sa.TotalPnlPercent, sb.TotalPnlPercent),
+		)
+	}
+	return out
+}
+
+// FormatStateEvalReport 格式化报告为文本（命令行输出）
+func FormatStateEvalReport(r *StateEvalReport) string {
+	if r == nil {
+		return ""
+	}
+	s := fmt.Sprintf("== 配置[%s] 评估步数=%d 命中率=%.2f%% 状态切换=%d次(%.2f次/小时) 平均持续=%.1f分钟\n",
+		r.Config, r.Steps, r.HitRate, r.Flips, r.FlipsPerHour, r.AvgDwellMinutes)
+	s += fmt.Sprintf("%-10s", "预测\\实际")
+	for _, lb := range evalLabels {
+		s += fmt.Sprintf("%12s", lb)
+	}
+	s += fmt.Sprintf("%10s\n", "占比%")
+	for _, st := range evalStates {
+		s += fmt.Sprintf("%-10s", st)
+		for _, lb := range evalLabels {
+			s += fmt.Sprintf("%12d", r.Confusion[st][lb])
+		}
+		s += fmt.Sprintf("%10.2f\n", r.StateShare[st])
+	}
+	s += fmt.Sprintf("%-10s", "标签占比%")
+	for _, lb := range evalLabels {
+		s += fmt.Sprintf("%12.2f", r.LabelShare[lb])
+	}
+	s += "\n"
+	for _, st := range evalStates {
+		if ts := r.Templates[st]; ts != nil {
+			s += fmt.Sprintf("模板[%s] %s: 交易=%d 胜率=%.2f%% 累计=%.2f%% 单笔=%.2f%% 最大回撤=%.2f%%\n",
+				st, ts.StrategyName, ts.Trades, ts.WinRate, ts.TotalPnlPercent, ts.AvgPnlPercent, ts.MaxDrawdown)
+		}
+	}
+	return s
+}
+
+// FormatStateEvalDiff 格式化对比结果为文本（命令行输出）
+func FormatStateEvalDiff(a, b *StateEvalReport) string {
+	s := fmt.Sprintf("== 对比 A[%s] vs B[%s]\n%-30s%14s%14s%14s\n", a.Config, b.Config, "指标", "A", "B", "B-A")
+	for _, d := range CompareStateEvalReports(a, b) {
+		s += fmt.Sprintf("%-30s%14.3f%14.3f%+14.3f\n", d.Metric, d.A, d.B, d.Delta)
+	}
+	return s
+}
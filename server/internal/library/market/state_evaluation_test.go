@@ -0,0 +1,127 @@
+// Package market
+// @Description 市场状态离线评估测试
+package market
+
+import (
+	"testing"
+
+	configlib "hotgo/internal/library/config"
+	"hotgo/internal/library/exchange"
+)
+
+// buildEvalKlines 生成n根1m K线，每根收盘价按 step 变化，振幅为 spread
+func buildEvalKlines(start float64, n int, step, spread float64) []*exchange.Kline {
+	out := make([]*exchange.Kline, 0, n)
+	price := start
+	for i := 0; i < n; i++ {
+		open := price
+		price += step
+		if step == 0 && i%2 == 1 {
+			price = start + spread/2
+		} else if step == 0 {
+			price = start - spread/2
+		}
+		high, low := open, price
+		if price > open {
+			high, low = price, open
+		}
+		out = append(out, &exchange.Kline{
+			OpenTime: int64(i) * 60 * 1000,
+			Open:     open,
+			High:     high + spread/4,
+			Low:      low - spread/4,
+			Close:    price,
+		})
+	}
+	return out
+}
+
+func TestLabelForwardBehaviour(t *testing.T) {
+	testCases := []struct {
+		name     string
+		klines   []*exchange.Kline
+		expected string
+	}{
+		{"steady uptrend", buildEvalKlines(100, 16, 1, 0.2), EvalLabelTrend},
+		{"choppy swings", buildEvalKlines(100, 16, 0, 4), EvalLabelMeanRevert},
+		{"quiet range", buildEvalKlines(100, 16, 0, 0.01), EvalLabelRange},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := labelForwardBehaviour(tc.klines, 0.5, 0.15); got != tc.expected {
+				t.Errorf("Got %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEvaluateMarketStates(t *testing.T) {
+	klines := append(buildEvalKlines(100, 200, 0.5, 0.2), buildEvalKlines(200, 200, 0, 3)...)
+	for i, k := range klines {
+		k.OpenTime = int64(i) * 60 * 1000
+	}
+
+	vc := *evalTestVolatilityConfig()
+	a := DefaultStateEvalConfig("a", &vc)
+	tight := vc
+	tight.TrendStrengthThreshold = 0.1
+	b := DefaultStateEvalConfig("b", &tight)
+
+	opts := &StateEvalOptions{Templates: map[string]*StateEvalTemplate{
+		"trend": {StrategyName: "trend", MonitorWindow: 180, VolatilityThreshold: 1, Leverage: 10, StopLossPercent: 10, AutoStartRetreatPercent: 5, ProfitRetreatPercent: 30},
+	}}
+
+	ra, err := EvaluateMarketStates(klines, a, opts)
+	if err != nil {
+		t.Fatalf("evaluate a: %v", err)
+	}
+	rb, err := EvaluateMarketStates(klines, b, opts)
+	if err != nil {
+		t.Fatalf("evaluate b: %v", err)
+	}
+
+	if ra.Steps != len(klines)-60-15 {
+		t.Errorf("steps: got %v, want %v", ra.Steps, len(klines)-60-15)
+	}
+	total := 0
+	for _, row := range ra.Confusion {
+		for _, n := range row {
+			total += n
+		}
+	}
+	if total != ra.Steps {
+		t.Errorf("confusion total: got %v, want %v", total, ra.Steps)
+	}
+	if ra.Templates["trend"] == nil {
+		t.Fatalf("missing trend template stats")
+	}
+	if diffs := CompareStateEvalReports(ra, rb); len(diffs) == 0 {
+		t.Errorf("expected comparison rows")
+	}
+
+	if _, err = EvaluateMarketStates(klines[:50], a, opts); err == nil {
+		t.Errorf("expected error for insufficient klines")
+	}
+}
+
+// evalTestVolatilityConfig 测试用波动率配置（同 BTCUSDT 默认值）
+func evalTestVolatilityConfig() *configlib.VolatilityConfig {
+	return &configlib.VolatilityConfig{
+		HighVolatilityThreshold: 2.0,
+		LowVolatilityThreshold:  0.9,
+		TrendStrengthThreshold:  1.2,
+		DThreshold:              0.7,
+		Delta1m:                 2.0,
+		Delta5m:                 2.0,
+		Delta15m:                3.0,
+		Delta30m:                3.0,
+		Delta1h:                 5.0,
+		Weight1m:                0.18,
+		Weight5m:                0.25,
+		Weight15m:               0.27,
+		Weight30m:               0.20,
+		Weight1h:                0.10,
+		IsActive:                1,
+	}
+}
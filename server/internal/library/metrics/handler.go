@@ -0,0 +1,33 @@
+// Package metrics
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 指标导出接口
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+// Handler 输出 Prometheus 文本格式指标
+// 配置了 metrics.token 时需携带 Authorization: Bearer <token> 或 ?token=<token>
+func Handler(r *ghttp.Request) {
+	if token := g.Cfg().MustGet(r.Context(), "metrics.token").String(); token != "" {
+		got := strings.TrimPrefix(r.GetHeader("Authorization"), "Bearer ")
+		if got == "" {
+			got = r.Get("token").String()
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			r.Response.WriteStatusExit(http.StatusUnauthorized, "unauthorized")
+		}
+	}
+	r.Response.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := defaultRegistry.WriteText(r.Response.Writer); err != nil {
+		g.Log().Warningf(r.Context(), "[Metrics] 输出指标失败: %v", err)
+	}
+}
@@ -0,0 +1,379 @@
+// Package metrics
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 运行指标采集，按 Prometheus 文本格式导出
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets 默认耗时分桶（秒）
+var DefBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// collector 指标采集接口
+type collector interface {
+	name() string
+	write(w *bytes.Buffer)
+}
+
+// Registry 指标注册表
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []collector
+	names      map[string]collector
+}
+
+// NewRegistry 创建指标注册表
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]collector)}
+}
+
+var defaultRegistry = NewRegistry()
+
+// Default 全局注册表
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// register 同名指标只注册一次，重复注册返回已有实例
+func (r *Registry) register(c collector) collector {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if exist, ok := r.names[c.name()]; ok {
+		return exist
+	}
+	r.names[c.name()] = c
+	r.collectors = append(r.collectors, c)
+	return c
+}
+
+// WriteText 按 Prometheus 文本格式输出全部指标
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.RLock()
+	list := make([]collector, len(r.collectors))
+	copy(list, r.collectors)
+	r.mu.RUnlock()
+
+	var buf bytes.Buffer
+	for _, c := range list {
+		c.write(&buf)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// desc 指标描述
+type desc struct {
+	metricName string
+	help       string
+	labels     []string
+}
+
+func (d *desc) name() string {
+	return d.metricName
+}
+
+func (d *desc) header(w *bytes.Buffer, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.metricName, escapeHelp(d.help), d.metricName, typ)
+}
+
+// labelKey 标签值拼接为序列键；标签数量不一致时截断或补空，避免调用方传错导致 panic
+func (d *desc) labelKey(values []string) (string, []string) {
+	fixed := make([]string, len(d.labels))
+	copy(fixed, values)
+	return strings.Join(fixed, "\xff"), fixed
+}
+
+// series 单条时间序列
+type series struct {
+	labels []string
+	value  float64
+}
+
+// vec 带标签的数值指标（Counter/Gauge 共用）
+type vec struct {
+	desc
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func (v *vec) add(delta float64, values []string) {
+	key, labels := v.labelKey(values)
+	v.mu.Lock()
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{labels: labels}
+		v.series[key] = s
+	}
+	s.value += delta
+	v.mu.Unlock()
+}
+
+func (v *vec) set(value float64, values []string) {
+	key, labels := v.labelKey(values)
+	v.mu.Lock()
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{labels: labels}
+		v.series[key] = s
+	}
+	s.value = value
+	v.mu.Unlock()
+}
+
+func (v *vec) writeSeries(w *bytes.Buffer) {
+	v.mu.Lock()
+	keys := make([]string, 0, len(v.series))
+	for k := range v.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := v.series[k]
+		writeSample(w, v.metricName, v.labels, s.labels, "", "", s.value)
+	}
+	v.mu.Unlock()
+}
+
+// Counter 只增计数器
+type Counter struct {
+	vec
+}
+
+// Counter 注册计数器
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	c := &Counter{vec{desc: desc{metricName: name, help: help, labels: labels}, series: make(map[string]*series)}}
+	return r.register(c).(*Counter)
+}
+
+// Inc 计数加一
+func (c *Counter) Inc(labelValues ...string) {
+	c.add(1, labelValues)
+}
+
+// Add 计数增加，负数忽略
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.add(delta, labelValues)
+}
+
+func (c *Counter) write(w *bytes.Buffer) {
+	c.header(w, "counter")
+	c.writeSeries(w)
+}
+
+// Gauge 可增可减的瞬时值
+type Gauge struct {
+	vec
+}
+
+// Gauge 注册瞬时值指标
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	gg := &Gauge{vec{desc: desc{metricName: name, help: help, labels: labels}, series: make(map[string]*series)}}
+	return r.register(gg).(*Gauge)
+}
+
+// Set 设置当前值
+func (gg *Gauge) Set(value float64, labelValues ...string) {
+	gg.set(value, labelValues)
+}
+
+// Add 增减当前值
+func (gg *Gauge) Add(delta float64, labelValues ...string) {
+	gg.add(delta, labelValues)
+}
+
+func (gg *Gauge) write(w *bytes.Buffer) {
+	gg.header(w, "gauge")
+	gg.writeSeries(w)
+}
+
+// GaugeFunc 采集时回调取值的瞬时值指标，适合引擎数、队列深度等已有状态
+type GaugeFunc struct {
+	desc
+	fn func(emit func(value float64, labelValues ...string))
+}
+
+// GaugeFunc 注册回调型瞬时值指标
+func (r *Registry) GaugeFunc(name, help string, labels []string, fn func(emit func(value float64, labelValues ...string))) *GaugeFunc {
+	gf := &GaugeFunc{desc: desc{metricName: name, help: help, labels: labels}, fn: fn}
+	return r.register(gf).(*GaugeFunc)
+}
+
+func (gf *GaugeFunc) write(w *bytes.Buffer) {
+	gf.header(w, "gauge")
+	gf.fn(func(value float64, labelValues ...string) {
+		_, labels := gf.labelKey(labelValues)
+		writeSample(w, gf.metricName, gf.labels, labels, "", "", value)
+	})
+}
+
+// histogramSeries 单条直方图序列
+type histogramSeries struct {
+	labels []string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Histogram 分布统计（耗时等）
+type Histogram struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+// Histogram 注册直方图，buckets 为空时使用 DefBuckets
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+	h := &Histogram{
+		desc:    desc{metricName: name, help: help, labels: labels},
+		buckets: sorted,
+		series:  make(map[string]*histogramSeries),
+	}
+	return r.register(h).(*Histogram)
+}
+
+// Observe 记录一次观测值
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	key, labels := h.labelKey(labelValues)
+	idx := sort.SearchFloat64s(h.buckets, value)
+	h.mu.Lock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{labels: labels, counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if idx < len(h.buckets) {
+		s.counts[idx]++
+	}
+	s.count++
+	s.sum += value
+	h.mu.Unlock()
+}
+
+func (h *Histogram) write(w *bytes.Buffer) {
+	h.header(w, "histogram")
+	h.mu.Lock()
+	keys := make([]string, 0, len(h.series))
+	for k := range h.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := h.series[k]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			writeSample(w, h.metricName+"_bucket", h.labels, s.labels, "le", formatFloat(upper), float64(cumulative))
+		}
+		writeSample(w, h.metricName+"_bucket", h.labels, s.labels, "le", "+Inf", float64(s.count))
+		writeSample(w, h.metricName+"_sum", h.labels, s.labels, "", "", s.sum)
+		writeSample(w, h.metricName+"_count", h.labels, s.labels, "", "", float64(s.count))
+	}
+	h.mu.Unlock()
+}
+
+// writeSample 输出一行样本：name{k="v",...} value
+func writeSample(w *bytes.Buffer, name string, names, values []string, extraName, extraValue string, value float64) {
+	w.WriteString(name)
+	if len(names) > 0 || extraName != "" {
+		w.WriteByte('{')
+		for i, n := range names {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(n)
+			w.WriteString(`="`)
+			w.WriteString(escapeLabel(values[i]))
+			w.WriteByte('"')
+		}
+		if extraName != "" {
+			if len(names) > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(extraName)
+			w.WriteString(`="`)
+			w.WriteString(extraValue)
+			w.WriteByte('"')
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string {
+	return labelReplacer.Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+// NewCounter 在全局注册表注册计数器
+func NewCounter(name, help string, labels ...string) *Counter {
+	return defaultRegistry.Counter(name, help, labels...)
+}
+
+// NewGauge 在全局注册表注册瞬时值指标
+func NewGauge(name, help string, labels ...string) *Gauge {
+	return defaultRegistry.Gauge(name, help, labels...)
+}
+
+// NewGaugeFunc 在全局注册表注册回调型瞬时值指标
+func NewGaugeFunc(name, help string, labels []string, fn func(emit func(value float64, labelValues ...string))) *GaugeFunc {
+	return defaultRegistry.GaugeFunc(name, help, labels, fn)
+}
+
+// NewHistogram 在全局注册表注册直方图
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return defaultRegistry.Histogram(name, help, buckets, labels...)
+}
+
+func init() {
+	NewGaugeFunc("go_goroutines", "Number of goroutines that currently exist.", nil, func(emit func(float64, ...string)) {
+		emit(float64(runtime.NumGoroutine()))
+	})
+	NewGaugeFunc("go_memstats_heap_alloc_bytes", "Number of heap bytes allocated and still in use.", nil, func(emit func(float64, ...string)) {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+		emit(float64(ms.HeapAlloc))
+	})
+}
@@ -0,0 +1,59 @@
+// Package metrics
+// @Description 指标导出测试
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	c := r.Counter("orders_total", "Orders.", "platform", "result")
+	c.Inc("okx", "success")
+	c.Add(2, "binance", "failed")
+	c.Add(-1, "binance", "failed")
+
+	h := r.Histogram("latency_seconds", "Latency.", []float64{0.1, 1}, "platform")
+	h.Observe(0.05, "okx")
+	h.Observe(0.5, "okx")
+	h.Observe(3, "okx")
+
+	r.GaugeFunc("queue_depth", "Queue.", []string{"queue"}, func(emit func(float64, ...string)) {
+		emit(7, `a"b`)
+	})
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# TYPE orders_total counter\n",
+		`orders_total{platform="binance",result="failed"} 2` + "\n",
+		`orders_total{platform="okx",result="success"} 1` + "\n",
+		`latency_seconds_bucket{platform="okx",le="0.1"} 1` + "\n",
+		`latency_seconds_bucket{platform="okx",le="1"} 2` + "\n",
+		`latency_seconds_bucket{platform="okx",le="+Inf"} 3` + "\n",
+		`latency_seconds_sum{platform="okx"} 3.55` + "\n",
+		`latency_seconds_count{platform="okx"} 3` + "\n",
+		`queue_depth{queue="a\"b"} 7` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteText() missing %q\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "orders_total{platform=\"binance\"") > strings.Index(out, "orders_total{platform=\"okx\"") {
+		t.Errorf("WriteText() series not sorted\n%s", out)
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	r := NewRegistry()
+	a := r.Counter("dup_total", "Dup.")
+	b := r.Counter("dup_total", "Dup.")
+	if a != b {
+		t.Fatal("Counter() registered twice")
+	}
+}
@@ -277,10 +277,10 @@ func (n *NOWPayments) CreatePayout(ctx context.Context, req *CreatePayoutReq) (*
 	return &result, nil
 }
 
-// VerifyIPN 验证IPN回调签名
+// VerifyIPN 验证IPN回调签名，未设置IPN密钥时一律拒绝
 func (n *NOWPayments) VerifyIPN(ipnSecretFromHeader string, body []byte) bool {
-	if n.IpnSecret == "" {
-		return true // 未设置IPN密钥则跳过验证
+	if n.IpnSecret == "" || ipnSecretFromHeader == "" {
+		return false
 	}
 
 	// 对请求体进行排序后计算HMAC-SHA512
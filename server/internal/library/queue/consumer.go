@@ -68,6 +68,19 @@ func consumerListen(ctx context.Context, job Consumer) {
 		Logger().Debugf(ctx, "queue consumer shutdown signal received, topic=%s", topic)
 	})
 
+	// 可靠投递：按主题策略重试，重试用尽进入死信，处理完毕后才确认消息
+	if config.Reliable.Switch {
+		if ac, ok := c.(MqAckConsumer); ok {
+			if listenErr := ac.ListenReceiveMsgAck(topic, func(mqMsg MqMsg) error {
+				return deliver(ctx, job, mqMsg)
+			}); listenErr != nil {
+				Logger().Errorf(ctx, "消费队列：%s 监听失败, err:%+v", topic, listenErr)
+			}
+			return
+		}
+		Logger().Warningf(ctx, "消费队列：%s 当前驱动不支持消息确认，回退为至多一次投递", topic)
+	}
+
 	if listenErr := c.ListenReceiveMsgDo(topic, func(mqMsg MqMsg) {
 		err = job.Handle(ctx, mqMsg)
 
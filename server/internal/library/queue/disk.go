@@ -27,6 +27,12 @@ type DiskConsumerMq struct {
 	config *disk.Config
 }
 
+// 延迟消息索引按目录共享，同进程内的生产者和消费者使用同一个实例
+var (
+	diskDelays   = make(map[string]*disk.Delay)
+	diskDelaysMu sync.Mutex
+)
+
 func RegisterDiskMqConsumer(config *disk.Config) (client MqConsumer, err error) {
 	return &DiskConsumerMq{
 		config: config,
@@ -35,8 +41,17 @@ func RegisterDiskMqConsumer(config *disk.Config) (client MqConsumer, err error)
 
 // ListenReceiveMsgDo 消费数据
 func (q *DiskConsumerMq) ListenReceiveMsgDo(topic string, receiveDo func(mqMsg MqMsg)) (err error) {
+	return q.ListenReceiveMsgAck(topic, func(mqMsg MqMsg) error {
+		receiveDo(mqMsg)
+		return nil
+	})
+}
+
+// ListenReceiveMsgAck 消费数据，处理成功后才提交读取索引
+// 处理失败时不提交并重复投递同一条消息，进程重启后也会从最后一次提交的位置继续读取。
+func (q *DiskConsumerMq) ListenReceiveMsgAck(topic string, receiveDo func(mqMsg MqMsg) error) (err error) {
 	if topic == "" {
-		return gerror.New("disk.ListenReceiveMsgDo topic is empty")
+		return gerror.New("disk.ListenReceiveMsgAck topic is empty")
 	}
 
 	var (
@@ -44,16 +59,24 @@ func (q *DiskConsumerMq) ListenReceiveMsgDo(topic string, receiveDo func(mqMsg M
 		sleep = time.Second
 	)
 
+	delay, err := getDiskDelay(topic, q.config)
+	if err != nil {
+		return gerror.Newf("disk.ListenReceiveMsgAck open delay index err:%+v", err)
+	}
+	go diskDelayLoop(topic, delay, receiveDo)
+
 	go func() {
 		for {
 			if index, offset, data, err := queue.Read(); err == nil {
 				var mqMsg MqMsg
 				if err = json.Unmarshal(data, &mqMsg); err != nil {
-					Logger().Warningf(ctx, "disk.ListenReceiveMsgDo Unmarshal err:%+v, topic：%v, data:%+v .", err, topic, string(data))
+					Logger().Warningf(ctx, "disk.ListenReceiveMsgAck Unmarshal err:%+v, topic：%v, data:%+v .", err, topic, string(data))
 					continue
 				}
 				if mqMsg.MsgId != "" {
-					receiveDo(mqMsg)
+					for receiveDo(mqMsg) != nil {
+						time.Sleep(time.Second)
+					}
 					queue.Commit(index, offset)
 					sleep = time.Millisecond * 10
 				}
@@ -106,11 +129,46 @@ func (d *DiskProducerMq) SendByteMsg(topic string, body []byte) (mqMsg MqMsg, er
 	return
 }
 
+// SendDelayMsg 生产延迟数据，delaySecond 延迟秒数
+// 消息先持久化到主题目录下的延迟索引，到期后由消费者按到期时间顺序投递，进程重启不丢失。
 func (d *DiskProducerMq) SendDelayMsg(topic string, body string, delaySecond int64) (mqMsg MqMsg, err error) {
-	err = gerror.New("implement me")
+	if topic == "" {
+		return mqMsg, gerror.New("DiskMq topic is empty")
+	}
+
+	mqMsg = MqMsg{
+		RunType:   SendMsg,
+		Topic:     topic,
+		MsgId:     getRandMsgId(),
+		Body:      []byte(body),
+		Timestamp: time.Now(),
+	}
+
+	mqMsgJson, err := json.Marshal(mqMsg)
+	if err != nil {
+		return mqMsg, gerror.New(fmt.Sprint("queue disk 生产者解析json消息失败:", err))
+	}
+
+	delay, err := getDiskDelay(topic, d.config)
+	if err != nil {
+		return mqMsg, gerror.New(fmt.Sprint("queue disk 打开延迟索引失败:", err))
+	}
+
+	if _, err = delay.Add(time.Now().Add(time.Duration(delaySecond)*time.Second), mqMsgJson); err != nil {
+		return mqMsg, gerror.New(fmt.Sprint("queue disk 生产者添加延迟消息失败:", err))
+	}
 	return
 }
 
+// DelayPending 主题待投递的延迟消息数
+func (d *DiskProducerMq) DelayPending(topic string) (count int, err error) {
+	delay, err := getDiskDelay(topic, d.config)
+	if err != nil {
+		return
+	}
+	return delay.Len(), nil
+}
+
 func (d *DiskProducerMq) getProducer(topic string) *disk.Queue {
 	queue, ok := d.producers[topic]
 	if ok {
@@ -147,3 +205,60 @@ func NewDiskQueue(topic string, config *disk.Config) *disk.Queue {
 	}
 	return queue
 }
+
+// getDiskDelay 获取主题的延迟消息索引，存放在主题数据目录的 delay 子目录下
+// 每个进程按目录缓存一个实例；http 与 queue 分进程部署时各自打开同一目录，由索引内的文件锁保证多进程一致
+func getDiskDelay(topic string, config *disk.Config) (*disk.Delay, error) {
+	dir := config.Path + "/" + config.GroupName + "/" + topic + "/delay"
+
+	diskDelaysMu.Lock()
+	defer diskDelaysMu.Unlock()
+	if delay, ok := diskDelays[dir]; ok {
+		return delay, nil
+	}
+
+	delay, err := disk.NewDelay(dir)
+	if err != nil {
+		return nil, err
+	}
+	diskDelays[dir] = delay
+	return delay, nil
+}
+
+// diskDelayLoop 按到期时间顺序投递延迟消息，处理成功后才确认
+// 处理失败时放回索引，1秒后从最早到期的消息重新投递，保持到期顺序。
+func diskDelayLoop(topic string, delay *disk.Delay, receiveDo func(mqMsg MqMsg) error) {
+	for {
+		items := delay.Due(time.Now(), 100)
+		failed := false
+		for i, item := range items {
+			var mqMsg MqMsg
+			if err := json.Unmarshal(item.Data, &mqMsg); err != nil {
+				Logger().Warningf(ctx, "disk.diskDelayLoop Unmarshal err:%+v, topic：%v, data:%+v .", err, topic, string(item.Data))
+				_ = delay.Ack(item.Id)
+				continue
+			}
+			if err := receiveDo(mqMsg); err != nil {
+				for _, rest := range items[i:] {
+					delay.Nack(rest.Id)
+				}
+				failed = true
+				break
+			}
+			if err := delay.Ack(item.Id); err != nil {
+				Logger().Warningf(ctx, "disk.diskDelayLoop Ack err:%+v, topic：%v", err, topic)
+			}
+		}
+
+		sleep := time.Second
+		if !failed && len(items) > 0 {
+			sleep = time.Millisecond * 10
+		} else if next := delay.NextDue(); !failed && !next.IsZero() && time.Until(next) < sleep {
+			sleep = time.Until(next)
+		}
+		if sleep < time.Millisecond*10 {
+			sleep = time.Millisecond * 10
+		}
+		time.Sleep(sleep)
+	}
+}
@@ -0,0 +1,447 @@
+// Package disk
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package disk
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	delayLogFile  = "delay.log"  // 延迟消息日志（追加写）
+	delayAckFile  = "delay.ack"  // 已投递的延迟消息ID（追加写）
+	delayTmpFile  = "delay.tmp"  // 压缩时的临时文件
+	delayLockFile = "delay.lock" // 多进程共享目录时的文件锁
+	compactMinAck = 1000         // 已确认条数达到该值且超过待投递条数两倍时压缩日志
+)
+
+// DelayItem 延迟消息
+type DelayItem struct {
+	Id   int64  `json:"id"`
+	Due  int64  `json:"due"` // 到期时间(毫秒)
+	Data []byte `json:"data"`
+}
+
+// Delay 持久化的延迟消息索引
+// 写入时追加到 delay.log 并落盘，投递确认后把ID追加到 delay.ack；重启时以 log - ack 重建最小堆，
+// 因此进程在任意时刻崩溃都不会丢失未确认的延迟消息（确认前崩溃会重复投递）。
+//
+// 同一目录可以被多个进程同时打开（如 http 与 queue 分进程部署时，http 写入、queue 投递）：
+// 每次操作都持有 delay.lock 文件锁，并先增量读取其他进程追加的消息和确认；
+// 日志被其他进程压缩替换后重新加载，压缩时也以所有进程写入的数据为准，不会丢弃其他进程的消息。
+// 投递（Due/Ack）应只由一个进程负责，多个进程同时投递同一目录会重复投递。
+type Delay struct {
+	sync.Mutex
+	dir      string
+	lockFile *os.File
+	logFile  *os.File
+	ackFile  *os.File
+	logOff   int64                // 已读取到的 delay.log 偏移
+	ackOff   int64                // 已读取到的 delay.ack 偏移
+	logTail  bool                 // delay.log 末尾有崩溃残留的半行
+	ackTail  bool                 // delay.ack 末尾有崩溃残留的半行
+	items    delayHeap            // 待投递的最小堆，已被确认或已取出的条目在取出时跳过
+	live     map[int64]*DelayItem // 未确认的消息（含已取出未确认）
+	inflight map[int64]*DelayItem
+	acked    int
+	lastId   int64
+	closed   bool
+}
+
+// NewDelay 打开（或恢复）目录下的延迟消息索引
+func NewDelay(dir string) (d *Delay, err error) {
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return
+	}
+	d = &Delay{dir: dir, inflight: make(map[int64]*DelayItem)}
+	if d.lockFile, err = os.OpenFile(path.Join(dir, delayLockFile), os.O_CREATE|os.O_RDWR, filePerm); err != nil {
+		return nil, err
+	}
+	if err = d.withFileLock(d.reload); err != nil {
+		d.closeFiles()
+		return nil, err
+	}
+	return d, nil
+}
+
+// Add 写入一条延迟消息，返回后即已落盘
+func (d *Delay) Add(due time.Time, data []byte) (id int64, err error) {
+	d.Lock()
+	defer d.Unlock()
+	if d.closed {
+		return 0, errors.New("closed")
+	}
+
+	err = d.withFileLock(func() error {
+		if err := d.refresh(); err != nil {
+			return err
+		}
+
+		// ID 取单调递增的纳秒时间戳，保证日志压缩/重启后也不会与 ack 文件中的旧ID冲突；
+		// 持有文件锁且已读取全部日志，lastId 是所有进程中的最大值
+		id = time.Now().UnixNano()
+		if id <= d.lastId {
+			id = d.lastId + 1
+		}
+		line, err := json.Marshal(&DelayItem{Id: id, Due: due.UnixMilli(), Data: data})
+		if err != nil {
+			return err
+		}
+		if err = appendLine(d.logFile, line, d.logTail); err != nil {
+			return err
+		}
+		// 自己写入的行也按偏移读回，保持内存索引与文件一致
+		return d.readAppended()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Due 按到期时间顺序取出最多 limit 条已到期消息，取出的消息需 Ack 或 Nack
+func (d *Delay) Due(now time.Time, limit int) (list []*DelayItem) {
+	d.Lock()
+	defer d.Unlock()
+	if d.closed {
+		return
+	}
+	_ = d.withFileLock(d.refresh)
+
+	nowMs := now.UnixMilli()
+	for d.dropStaleLocked() && len(list) < limit && d.items[0].Due <= nowMs {
+		item := heap.Pop(&d.items).(*DelayItem)
+		d.inflight[item.Id] = item
+		list = append(list, item)
+	}
+	return
+}
+
+// Ack 确认投递完成
+func (d *Delay) Ack(id int64) (err error) {
+	d.Lock()
+	defer d.Unlock()
+	if d.closed {
+		return errors.New("closed")
+	}
+	if _, ok := d.inflight[id]; !ok {
+		return nil
+	}
+
+	return d.withFileLock(func() error {
+		if err := d.refresh(); err != nil {
+			return err
+		}
+		if _, ok := d.inflight[id]; !ok {
+			return nil
+		}
+		if err := appendLine(d.ackFile, []byte(strconv.FormatInt(id, 10)), d.ackTail); err != nil {
+			return err
+		}
+		if err := d.readAppended(); err != nil {
+			return err
+		}
+		if d.acked >= compactMinAck && d.acked > 2*len(d.live) {
+			return d.compact()
+		}
+		return nil
+	})
+}
+
+// Nack 投递失败，放回索引等待下次投递
+func (d *Delay) Nack(id int64) {
+	d.Lock()
+	defer d.Unlock()
+	if item, ok := d.inflight[id]; ok {
+		delete(d.inflight, id)
+		if _, ok = d.live[id]; ok {
+			heap.Push(&d.items, item)
+		}
+	}
+}
+
+// Len 待投递的延迟消息数（含已取出未确认）
+func (d *Delay) Len() int {
+	d.Lock()
+	defer d.Unlock()
+	if !d.closed {
+		_ = d.withFileLock(d.refresh)
+	}
+	return len(d.live)
+}
+
+// NextDue 最近一条待投递消息的到期时间，没有时返回零值
+func (d *Delay) NextDue() time.Time {
+	d.Lock()
+	defer d.Unlock()
+	if !d.closed {
+		_ = d.withFileLock(d.refresh)
+	}
+	if !d.dropStaleLocked() {
+		return time.Time{}
+	}
+	return time.UnixMilli(d.items[0].Due)
+}
+
+// Close 关闭索引
+func (d *Delay) Close() {
+	d.Lock()
+	defer d.Unlock()
+	if d.closed {
+		return
+	}
+	d.closed = true
+	d.closeFiles()
+}
+
+func (d *Delay) closeFiles() {
+	for _, f := range []*os.File{d.logFile, d.ackFile, d.lockFile} {
+		if f != nil {
+			_ = f.Close()
+		}
+	}
+}
+
+// dropStaleLocked 弹出堆顶已被确认或已取出的条目，返回堆中是否还有可投递的消息
+func (d *Delay) dropStaleLocked() bool {
+	for len(d.items) > 0 {
+		top := d.items[0]
+		_, live := d.live[top.Id]
+		_, inflight := d.inflight[top.Id]
+		if live && !inflight {
+			return true
+		}
+		heap.Pop(&d.items)
+	}
+	return false
+}
+
+// withFileLock 持有目录文件锁执行，与打开同一目录的其他进程互斥
+func (d *Delay) withFileLock(fn func() error) error {
+	if err := lockFile(d.lockFile); err != nil {
+		return err
+	}
+	defer func() { _ = unlockFile(d.lockFile) }()
+	return fn()
+}
+
+// refresh 读取其他进程追加的消息和确认；日志已被其他进程压缩替换时重新加载（需持有文件锁）
+func (d *Delay) refresh() error {
+	if d.logFile == nil || d.ackFile == nil || replaced(d.logFile, path.Join(d.dir, delayLogFile)) {
+		return d.reload()
+	}
+	if info, err := d.ackFile.Stat(); err != nil || info.Size() < d.ackOff {
+		return d.reload()
+	}
+	return d.readAppended()
+}
+
+// reload 重新打开日志文件，以 log - ack 重建待投递索引（需持有文件锁）
+// 已取出未确认的消息仍保留在 inflight 中，已被确认的从 inflight 移除
+func (d *Delay) reload() (err error) {
+	for _, f := range []*os.File{d.logFile, d.ackFile} {
+		if f != nil {
+			_ = f.Close()
+		}
+	}
+	d.logFile, d.ackFile = nil, nil
+	if d.logFile, err = os.OpenFile(path.Join(d.dir, delayLogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerm); err != nil {
+		return
+	}
+	if d.ackFile, err = os.OpenFile(path.Join(d.dir, delayAckFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerm); err != nil {
+		return
+	}
+
+	d.logOff, d.ackOff = 0, 0
+	d.logTail, d.ackTail = false, false
+	d.items = d.items[:0]
+	d.live = make(map[int64]*DelayItem)
+	d.acked = 0
+	if err = d.readAppended(); err != nil {
+		return
+	}
+	for id := range d.inflight {
+		if _, ok := d.live[id]; !ok {
+			delete(d.inflight, id)
+		}
+	}
+	return
+}
+
+// readAppended 从上次读取的位置继续读取日志和确认；崩溃时写了一半的末行暂不读取
+func (d *Delay) readAppended() (err error) {
+	d.logOff, d.logTail, err = readLinesFrom(path.Join(d.dir, delayLogFile), d.logOff, func(line []byte) {
+		var item DelayItem
+		if err := json.Unmarshal(line, &item); err != nil || item.Id == 0 {
+			return
+		}
+		if item.Id > d.lastId {
+			d.lastId = item.Id
+		}
+		if _, ok := d.live[item.Id]; ok {
+			return
+		}
+		d.live[item.Id] = &item
+		heap.Push(&d.items, &item)
+	})
+	if err != nil {
+		return
+	}
+
+	d.ackOff, d.ackTail, err = readLinesFrom(path.Join(d.dir, delayAckFile), d.ackOff, func(line []byte) {
+		id, err := strconv.ParseInt(string(line), 10, 64)
+		if err != nil {
+			return
+		}
+		d.acked++
+		delete(d.live, id)
+		delete(d.inflight, id)
+	})
+	return
+}
+
+// compact 只保留未确认的消息重写日志，并清空 ack 文件（需持有文件锁且已 refresh）
+// 先写临时文件再 rename，任一步骤崩溃都能由 reload 得到一致结果。
+// rename 成功后才切换写入句柄；打开新文件失败时保留旧句柄，下次操作的 refresh 会发现日志已替换并重新打开。
+func (d *Delay) compact() (err error) {
+	tmp := path.Join(d.dir, delayTmpFile)
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, filePerm)
+	if err != nil {
+		return
+	}
+	w := bufio.NewWriter(f)
+	for _, item := range d.live {
+		var line []byte
+		if line, err = json.Marshal(item); err != nil {
+			break
+		}
+		if _, err = w.Write(append(line, '\n')); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	_ = f.Close()
+	if err != nil {
+		_ = os.Remove(tmp)
+		return
+	}
+
+	logPath := path.Join(d.dir, delayLogFile)
+	if err = os.Rename(tmp, logPath); err != nil {
+		_ = os.Remove(tmp)
+		return
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerm)
+	if err != nil {
+		return
+	}
+	info, err := logFile.Stat()
+	if err != nil {
+		_ = logFile.Close()
+		return
+	}
+	_ = d.logFile.Close()
+	d.logFile = logFile
+	d.logOff, d.logTail = info.Size(), false
+
+	if err = d.ackFile.Truncate(0); err != nil {
+		return
+	}
+	d.ackOff, d.ackTail = 0, false
+	d.acked = 0
+	return
+}
+
+// replaced 打开的文件是否已被替换（其他进程压缩日志后 rename）
+func replaced(f *os.File, name string) bool {
+	opened, err := f.Stat()
+	if err != nil {
+		return true
+	}
+	current, err := os.Stat(name)
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(opened, current)
+}
+
+// appendLine 追加一行并落盘，末尾有崩溃残留的半行时先换行，避免与新行拼接
+func appendLine(f *os.File, line []byte, tail bool) error {
+	buf := make([]byte, 0, len(line)+2)
+	if tail {
+		buf = append(buf, '\n')
+	}
+	buf = append(buf, line...)
+	buf = append(buf, '\n')
+	if _, err := f.Write(buf); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// readLinesFrom 从 off 开始读取完整的行，返回新的偏移以及末尾是否有未写完的半行
+func readLinesFrom(name string, off int64, fn func(line []byte)) (int64, bool, error) {
+	f, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return off, false, nil
+	}
+	if err != nil {
+		return off, false, err
+	}
+	defer f.Close()
+
+	if _, err = f.Seek(off, io.SeekStart); err != nil {
+		return off, false, err
+	}
+	r := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return off, len(line) > 0, nil
+		}
+		if err != nil {
+			return off, false, err
+		}
+		off += int64(len(line))
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			fn(line)
+		}
+	}
+}
+
+// delayHeap 按 (到期时间, ID) 排序的最小堆
+type delayHeap []*DelayItem
+
+func (h delayHeap) Len() int { return len(h) }
+func (h delayHeap) Less(i, j int) bool {
+	if h[i].Due != h[j].Due {
+		return h[i].Due < h[j].Due
+	}
+	return h[i].Id < h[j].Id
+}
+func (h delayHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *delayHeap) Push(x interface{}) { *h = append(*h, x.(*DelayItem)) }
+func (h *delayHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,235 @@
+package disk
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+	"time"
+)
+
+const crashEnv = "HOTGO_DISK_DELAY_CRASH_DIR"
+
+// TestMain 子进程模式：写入延迟消息并确认一条后直接退出，不执行 Close，模拟进程崩溃
+func TestMain(m *testing.M) {
+	if dir := os.Getenv(crashEnv); dir != "" {
+		d, err := NewDelay(dir)
+		if err != nil {
+			os.Exit(2)
+		}
+		now := time.Now()
+		_, _ = d.Add(now.Add(-time.Second), []byte("due"))
+		_, _ = d.Add(now.Add(time.Hour), []byte("late"))
+		_, _ = d.Add(now.Add(time.Minute), []byte("soon"))
+		for _, item := range d.Due(now, 1) {
+			_ = d.Ack(item.Id)
+		}
+		os.Exit(3)
+	}
+	os.Exit(m.Run())
+}
+
+func TestDelayDueOrder(t *testing.T) {
+	d, err := NewDelay(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	now := time.Now()
+	_, _ = d.Add(now.Add(3*time.Second), []byte("c"))
+	_, _ = d.Add(now.Add(1*time.Second), []byte("a"))
+	_, _ = d.Add(now.Add(2*time.Second), []byte("b"))
+	_, _ = d.Add(now.Add(time.Hour), []byte("z"))
+
+	if items := d.Due(now, 10); len(items) != 0 {
+		t.Fatalf("nothing should be due yet, got %d", len(items))
+	}
+
+	items := d.Due(now.Add(5*time.Second), 10)
+	if got := dataOf(items); got != "abc" {
+		t.Fatalf("due order = %q, want %q", got, "abc")
+	}
+	if n := d.Len(); n != 4 {
+		t.Fatalf("pending with inflight = %d, want 4", n)
+	}
+
+	d.Nack(items[0].Id)
+	_ = d.Ack(items[1].Id)
+	_ = d.Ack(items[2].Id)
+	if n := d.Len(); n != 2 {
+		t.Fatalf("pending = %d, want 2", n)
+	}
+	if got := dataOf(d.Due(now.Add(5*time.Second), 10)); got != "a" {
+		t.Fatalf("nacked item redelivery = %q, want %q", got, "a")
+	}
+}
+
+// TestDelayRestartMidDelay 未 Close 直接重新打开，未确认的消息（含已取出未确认）全部恢复
+func TestDelayRestartMidDelay(t *testing.T) {
+	dir := t.TempDir()
+	d, err := NewDelay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	_, _ = d.Add(now.Add(2*time.Second), []byte("b"))
+	_, _ = d.Add(now.Add(1*time.Second), []byte("a"))
+	_, _ = d.Add(now.Add(3*time.Second), []byte("c"))
+	items := d.Due(now.Add(2*time.Second), 10)
+	_ = d.Ack(items[0].Id) // a 已投递
+	// b 已取出但未确认即崩溃
+
+	// 末尾追加半行，模拟写入过程中崩溃
+	f, _ := os.OpenFile(path.Join(dir, delayLogFile), os.O_WRONLY|os.O_APPEND, filePerm)
+	_, _ = f.WriteString(`{"id":99,"due":`)
+	_ = f.Close()
+
+	r, err := NewDelay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if n := r.Len(); n != 2 {
+		t.Fatalf("pending after restart = %d, want 2", n)
+	}
+	if got := dataOf(r.Due(now.Add(5*time.Second), 10)); got != "bc" {
+		t.Fatalf("due order after restart = %q, want %q", got, "bc")
+	}
+
+	// 重启后新写入的ID不能与旧ID冲突
+	id, err := r.Add(now, []byte("d"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id <= items[0].Id {
+		t.Fatalf("new id %d should be greater than restored id %d", id, items[0].Id)
+	}
+}
+
+// TestDelayProcessCrash 子进程写入后异常退出，父进程从同一目录恢复
+func TestDelayProcessCrash(t *testing.T) {
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), crashEnv+"="+dir)
+	if err := cmd.Run(); err == nil {
+		t.Fatal("child process should exit abnormally")
+	} else if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 3 {
+		t.Fatalf("child process err: %v", err)
+	}
+
+	d, err := NewDelay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	if n := d.Len(); n != 2 {
+		t.Fatalf("pending after crash = %d, want 2", n)
+	}
+	if items := d.Due(time.Now(), 10); len(items) != 0 {
+		t.Fatalf("acked message redelivered: %q", dataOf(items))
+	}
+	if got := dataOf(d.Due(time.Now().Add(2*time.Hour), 10)); got != "soonlate" {
+		t.Fatalf("due order after crash = %q, want %q", got, "soonlate")
+	}
+}
+
+func TestDelayCompact(t *testing.T) {
+	dir := t.TempDir()
+	d, err := NewDelay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	_, _ = d.Add(now.Add(time.Hour), []byte("keep"))
+	for i := 0; i < compactMinAck; i++ {
+		_, _ = d.Add(now, []byte("x"))
+	}
+	for _, item := range d.Due(now, compactMinAck) {
+		if err = d.Ack(item.Id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if info, _ := os.Stat(path.Join(dir, delayAckFile)); info.Size() != 0 {
+		t.Fatalf("ack file should be truncated after compact, size %d", info.Size())
+	}
+	d.Close()
+
+	r, err := NewDelay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if n := r.Len(); n != 1 {
+		t.Fatalf("pending after compact = %d, want 1", n)
+	}
+	if got := dataOf(r.Due(now.Add(2*time.Hour), 10)); got != "keep" {
+		t.Fatalf("item after compact = %q, want %q", got, "keep")
+	}
+}
+
+// TestDelaySharedDir http 与 queue 分进程部署时两个实例打开同一目录：一个写入，一个投递并压缩
+func TestDelaySharedDir(t *testing.T) {
+	dir := t.TempDir()
+	producer, err := NewDelay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer producer.Close()
+	consumer, err := NewDelay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer consumer.Close()
+
+	now := time.Now()
+	_, _ = producer.Add(now.Add(time.Hour), []byte("keep"))
+	for i := 0; i < compactMinAck; i++ {
+		_, _ = producer.Add(now, []byte("x"))
+	}
+	if n := consumer.Len(); n != compactMinAck+1 {
+		t.Fatalf("consumer pending = %d, want %d", n, compactMinAck+1)
+	}
+
+	// 投递确认触发压缩，压缩后的日志必须保留生产者写入的未到期消息
+	for _, item := range consumer.Due(now, compactMinAck) {
+		if err = consumer.Ack(item.Id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if info, _ := os.Stat(path.Join(dir, delayAckFile)); info.Size() != 0 {
+		t.Fatalf("ack file should be truncated after compact, size %d", info.Size())
+	}
+
+	// 生产者在压缩后继续写入，不能写到被替换的旧日志
+	_, _ = producer.Add(now.Add(-time.Second), []byte("after"))
+	if n := producer.Len(); n != 2 {
+		t.Fatalf("producer pending after compact = %d, want 2", n)
+	}
+	if got := dataOf(consumer.Due(now, 10)); got != "after" {
+		t.Fatalf("due after compact = %q, want %q", got, "after")
+	}
+	if got := dataOf(consumer.Due(now.Add(2*time.Hour), 10)); got != "keep" {
+		t.Fatalf("late item after compact = %q, want %q", got, "keep")
+	}
+
+	r, err := NewDelay(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if n := r.Len(); n != 2 {
+		t.Fatalf("pending after reopen = %d, want 2", n)
+	}
+}
+
+func dataOf(items []*DelayItem) (s string) {
+	for _, item := range items {
+		s += string(item.Data)
+	}
+	return
+}
@@ -81,6 +81,14 @@ func (r *KafkaMq) SendDelayMsg(topic string, body string, delaySecond int64) (mq
 
 // ListenReceiveMsgDo 消费数据
 func (r *KafkaMq) ListenReceiveMsgDo(topic string, receiveDo func(mqMsg MqMsg)) (err error) {
+	return r.ListenReceiveMsgAck(topic, func(mqMsg MqMsg) error {
+		receiveDo(mqMsg)
+		return nil
+	})
+}
+
+// ListenReceiveMsgAck 消费数据，处理成功后才标记位点
+func (r *KafkaMq) ListenReceiveMsgAck(topic string, receiveDo func(mqMsg MqMsg) error) (err error) {
 	if r.consumerIns == nil {
 		return gerror.New("queue kafka consumer not register")
 	}
@@ -224,7 +232,7 @@ func validateVersion(version sarama.KafkaVersion) bool {
 
 type KaConsumer struct {
 	ready        chan bool
-	receiveDoFun func(mqMsg MqMsg)
+	receiveDoFun func(mqMsg MqMsg) error
 }
 
 // Setup is run at the beginning of a new session, before ConsumeClaim
@@ -247,14 +255,22 @@ func (consumer *KaConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, cl
 	// https://github.com/Shopify/sarama/blob/master/consumer_group.go#L27-L29
 	// `ConsumeClaim` 方法已经是 goroutine 调用 不要在该方法内进行 goroutine
 	for message := range claim.Messages() {
-		consumer.receiveDoFun(MqMsg{
+		mqMsg := MqMsg{
 			RunType:   ReceiveMsg,
 			Topic:     message.Topic,
 			Body:      message.Value,
 			Offset:    message.Offset,
 			Timestamp: message.Timestamp,
 			Partition: message.Partition,
-		})
+		}
+		// 位点按分区顺序提交，处理失败时阻塞在当前消息重试，避免越过未确认的消息提交位点
+		for consumer.receiveDoFun(mqMsg) != nil {
+			select {
+			case <-session.Context().Done():
+				return nil
+			case <-time.After(time.Second):
+			}
+		}
 		session.MarkMessage(message, "")
 	}
 	return nil
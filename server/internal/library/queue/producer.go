@@ -6,6 +6,7 @@
 package queue
 
 import (
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/util/gconv"
 )
 
@@ -21,7 +22,7 @@ func Push(topic string, data interface{}) (err error) {
 }
 
 // DelayPush 推送延迟队列
-// redis delay 传入 秒。如：10代表延迟10秒
+// redis、disk delay 传入 秒。如：10代表延迟10秒
 // rocketmq delay 传入 延迟级别。如：2代表延迟5秒
 // rocketmq reference delay level definition: 1s 5s 10s 30s 1m 2m 3m 4m 5m 6m 7m 8m 9m 10m 20m 30m 1h 2h
 // rocketmq delay level starts from 1. for example, if we set param level=1, then the delay time is 1s.
@@ -34,3 +35,17 @@ func DelayPush(topic string, data interface{}, delay int64) (err error) {
 	ProducerLog(ctx, topic, mqMsg, err)
 	return
 }
+
+// DelayPending 获取主题待投递的延迟消息数，仅支持可统计的驱动（disk）
+func DelayPending(topic string) (count int, err error) {
+	q, err := InstanceProducer()
+	if err != nil {
+		return
+	}
+	counter, ok := q.(MqDelayCounter)
+	if !ok {
+		err = gerror.Newf("queue driver %v does not support delay pending count", config.Driver)
+		return
+	}
+	return counter.DelayPending(topic)
+}
@@ -21,6 +21,11 @@ type MqProducer interface {
 	SendDelayMsg(topic string, body string, delay int64) (mqMsg MqMsg, err error)
 }
 
+// MqDelayCounter 支持统计待投递延迟消息数的生产者
+type MqDelayCounter interface {
+	DelayPending(topic string) (count int, err error)
+}
+
 type MqConsumer interface {
 	ListenReceiveMsgDo(topic string, receiveDo func(mqMsg MqMsg)) (err error)
 }
@@ -39,6 +44,7 @@ type Config struct {
 	Rocketmq  RocketmqConf
 	Kafka     KafkaConf
 	Disk      *disk.Config
+	Reliable  ReliableConf
 }
 
 type RedisConf struct {
@@ -12,9 +12,11 @@ import (
 	"github.com/gogf/gf/v2/database/gredis"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/util/gconv"
 	"hotgo/utility/encrypt"
 	"math/rand"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -56,6 +58,12 @@ func (r *RedisMq) SendByteMsg(topic string, body []byte) (mqMsg MqMsg, err error
 		return
 	}
 
+	// 可靠投递模式写入 stream，由消费组确认
+	if config.Reliable.Switch {
+		err = r.xadd(r.genStreamKey(topic), data)
+		return
+	}
+
 	key := r.genKey(r.groupName, topic)
 	if _, err = g.Redis().Do(ctx, "LPUSH", key, data); err != nil {
 		return
@@ -164,6 +172,11 @@ func (r *RedisMq) genKey(groupName string, topic string) string {
 	return fmt.Sprintf("queue:%s_%s", groupName, topic)
 }
 
+// 生成可靠投递模式的stream key
+func (r *RedisMq) genStreamKey(topic string) string {
+	return r.genKey(r.groupName, "stream:"+topic)
+}
+
 func (r *RedisMq) loopReadQueue(key string) (mqMsgList []MqMsg) {
 	conn := g.Redis()
 	for {
@@ -275,3 +288,186 @@ func (r *RedisMq) loopReadDelayQueue(key string) (resCh chan MqMsg, errCh chan e
 	}()
 	return resCh, errCh
 }
+
+// streamEntry stream 中的一条消息
+type streamEntry struct {
+	id    string
+	mqMsg MqMsg
+	valid bool
+}
+
+// xadd 写入stream
+// 配置了超时时间时按 MINID 近似裁剪早于超时时间的消息，不对整个 stream 设置过期，避免空闲期间连同消费组一起被删除
+func (r *RedisMq) xadd(key string, data []byte) (err error) {
+	args := []interface{}{key}
+	if r.timeout > 0 {
+		minId := time.Now().Add(-time.Duration(r.timeout) * time.Second).UnixMilli()
+		args = append(args, "MINID", "~", minId)
+	}
+	args = append(args, "*", "msg", data)
+	_, err = g.Redis().Do(ctx, "XADD", args...)
+	return
+}
+
+// createGroup 创建消费组，stream 不存在时一并创建，消费组已存在视为成功
+func (r *RedisMq) createGroup(key string) (err error) {
+	if _, err = g.Redis().Do(ctx, "XGROUP", "CREATE", key, r.groupName, "0", "MKSTREAM"); err != nil {
+		if !strings.Contains(err.Error(), "BUSYGROUP") {
+			return
+		}
+		err = nil
+	}
+	return
+}
+
+// ListenReceiveMsgAck 基于 Redis Streams 消费组消费数据
+// 处理成功后 XACK；处理失败或消费者宕机的消息留在 PEL 中，空闲超过 claimIdle 后由任一消费者 XAUTOCLAIM 重新认领。
+func (r *RedisMq) ListenReceiveMsgAck(topic string, receiveDo func(mqMsg MqMsg) error) (err error) {
+	if r.poolName == "" {
+		return gerror.New("RedisMq consumer not register")
+	}
+	if topic == "" {
+		return gerror.New("RedisMq topic is empty")
+	}
+
+	var (
+		key       = r.genStreamKey(topic)
+		claimIdle = time.Duration(config.Reliable.ClaimIdle) * time.Second
+	)
+	if claimIdle <= 0 {
+		claimIdle = time.Minute
+	}
+
+	if err = r.createGroup(key); err != nil {
+		return
+	}
+
+	// 开启可靠投递前遗留在 list 中的消息迁入 stream
+	for _, mqMsg := range r.loopReadQueue(r.genKey(r.groupName, topic)) {
+		if data, jsonErr := json.Marshal(mqMsg); jsonErr == nil {
+			if addErr := r.xadd(key, data); addErr != nil {
+				Logger().Warningf(ctx, "RedisMq migrate list message failed, topic:%v, msgId:%v, err:%+v", topic, mqMsg.MsgId, addErr)
+			}
+		}
+	}
+
+	// 到期的延迟消息转入 stream，与普通消息走同一套确认流程
+	go func() {
+		mqMsgCh, errCh := r.loopReadDelayQueue(r.genKey(r.groupName, "delay:"+topic))
+		for mqMsg := range mqMsgCh {
+			data, _ := json.Marshal(mqMsg)
+			if addErr := r.xadd(key, data); addErr != nil {
+				Logger().Warningf(ctx, "RedisMq move delay message failed, topic:%v, msgId:%v, err:%+v", topic, mqMsg.MsgId, addErr)
+			}
+		}
+		for err = range errCh {
+			if err != nil && err != context.Canceled && err != context.DeadlineExceeded {
+				Logger().Infof(ctx, "ListenReceiveMsgAck Delay topic:%v, err:%+v", topic, err)
+			}
+		}
+	}()
+
+	go func() {
+		lastClaim := time.Now()
+		for {
+			if time.Since(lastClaim) >= claimIdle/2 {
+				lastClaim = time.Now()
+				for _, entry := range r.autoClaim(key, claimIdle) {
+					r.handleEntry(key, entry, receiveDo)
+				}
+			}
+
+			entries, readErr := r.readGroup(key)
+			if readErr != nil {
+				Logger().Warningf(ctx, "RedisMq XREADGROUP topic:%v, err:%+v", topic, readErr)
+				time.Sleep(time.Second)
+				continue
+			}
+			for _, entry := range entries {
+				r.handleEntry(key, entry, receiveDo)
+			}
+		}
+	}()
+
+	select {}
+}
+
+// handleEntry 处理一条stream消息，成功后确认并删除
+func (r *RedisMq) handleEntry(key string, entry streamEntry, receiveDo func(mqMsg MqMsg) error) {
+	// 无法解析的消息直接确认，避免反复认领
+	if entry.valid && receiveDo(entry.mqMsg) != nil {
+		return
+	}
+	conn := g.Redis()
+	if _, err := conn.Do(ctx, "XACK", key, r.groupName, entry.id); err != nil {
+		Logger().Warningf(ctx, "RedisMq XACK key:%v, id:%v, err:%+v", key, entry.id, err)
+		return
+	}
+	_, _ = conn.Do(ctx, "XDEL", key, entry.id)
+}
+
+// readGroup 读取新消息
+// stream 或消费组被删除（如 FLUSHDB、手动 DEL）时重建消费组，下一轮继续读取
+func (r *RedisMq) readGroup(key string) (entries []streamEntry, err error) {
+	reply, err := g.Redis().Do(ctx, "XREADGROUP", "GROUP", r.groupName, r.poolName, "COUNT", 10, "BLOCK", 2000, "STREAMS", key, ">")
+	if err != nil {
+		if strings.Contains(err.Error(), "NOGROUP") {
+			Logger().Warningf(ctx, "RedisMq consumer group missing, recreate it, key:%v, group:%v", key, r.groupName)
+			err = r.createGroup(key)
+		}
+		return
+	}
+	if reply.IsNil() {
+		return
+	}
+	for _, stream := range gconv.Interfaces(reply.Val()) {
+		if item := gconv.Interfaces(stream); len(item) == 2 {
+			entries = append(entries, parseStreamEntries(item[1])...)
+		}
+	}
+	return
+}
+
+// autoClaim 认领空闲超时未确认的消息（包括本消费者处理失败的消息和已宕机消费者遗留的消息）
+func (r *RedisMq) autoClaim(key string, idle time.Duration) (entries []streamEntry) {
+	start := "0-0"
+	for i := 0; i < 10; i++ {
+		reply, err := g.Redis().Do(ctx, "XAUTOCLAIM", key, r.groupName, r.poolName, idle.Milliseconds(), start, "COUNT", 50)
+		if err != nil {
+			Logger().Warningf(ctx, "RedisMq XAUTOCLAIM key:%v, err:%+v", key, err)
+			return
+		}
+		parts := gconv.Interfaces(reply.Val())
+		if len(parts) < 2 {
+			return
+		}
+		entries = append(entries, parseStreamEntries(parts[1])...)
+		start = gconv.String(parts[0])
+		if start == "0-0" {
+			return
+		}
+	}
+	return
+}
+
+// parseStreamEntries 解析 [[id, [field, value, ...]], ...]
+func parseStreamEntries(raw interface{}) (entries []streamEntry) {
+	for _, item := range gconv.Interfaces(raw) {
+		pair := gconv.Interfaces(item)
+		if len(pair) != 2 {
+			continue
+		}
+		entry := streamEntry{id: gconv.String(pair[0])}
+		fields := gconv.Interfaces(pair[1])
+		for i := 0; i+1 < len(fields); i += 2 {
+			if gconv.String(fields[i]) != "msg" {
+				continue
+			}
+			if err := json.Unmarshal(gconv.Bytes(fields[i+1]), &entry.mqMsg); err == nil && entry.mqMsg.MsgId != "" {
+				entry.valid = true
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return
+}
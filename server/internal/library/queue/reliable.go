@@ -0,0 +1,148 @@
+// Package queue
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package queue
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/dao"
+)
+
+// 死信状态
+const (
+	DeadLetterStatusPending  = 1 // 待处理
+	DeadLetterStatusReplayed = 2 // 已重放
+	DeadLetterStatusIgnored  = 3 // 已忽略
+)
+
+// MqAckConsumer 支持消费确认的消费者
+// receiveDo 返回 nil 时驱动才确认消息（redis=XACK，kafka=提交位点，rocketmq=ConsumeSuccess，disk=提交索引），
+// 返回错误时消息保留在驱动内等待重新投递，从而实现 at-least-once。
+type MqAckConsumer interface {
+	ListenReceiveMsgAck(topic string, receiveDo func(mqMsg MqMsg) error) (err error)
+}
+
+// ReliableConf 可靠投递配置
+type ReliableConf struct {
+	Switch    bool                   `json:"switch"`    // 是否开启可靠投递（at-least-once）
+	ClaimIdle int64                  `json:"claimIdle"` // redis：消费者宕机后，超过N秒未确认的消息由其他消费者重新认领
+	Retry     RetryPolicy            `json:"retry"`     // 默认重试策略
+	Topics    map[string]RetryPolicy `json:"topics"`    // 按主题覆盖重试策略
+}
+
+// RetryPolicy 重试策略，第 n 次失败后等待 InitialBackoff × Multiplier^(n-1) 秒（不超过 MaxBackoff）再重试
+type RetryPolicy struct {
+	MaxAttempts    int     `json:"maxAttempts"`    // 最大消费次数（含首次），用尽后进入死信
+	InitialBackoff int64   `json:"initialBackoff"` // 首次重试等待(秒)
+	MaxBackoff     int64   `json:"maxBackoff"`     // 最大等待(秒)
+	Multiplier     float64 `json:"multiplier"`     // 退避倍数
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 1,
+	MaxBackoff:     60,
+	Multiplier:     2,
+}
+
+// PolicyFor 获取主题的重试策略，未配置的字段回退到默认策略
+func PolicyFor(topic string) RetryPolicy {
+	policy := config.Reliable.Retry
+	if p, ok := config.Reliable.Topics[topic]; ok {
+		policy = p.merge(policy)
+	}
+	return policy.merge(defaultRetryPolicy)
+}
+
+func (p RetryPolicy) merge(fallback RetryPolicy) RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = fallback.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = fallback.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = fallback.MaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = fallback.Multiplier
+	}
+	return p
+}
+
+// Backoff 第 attempt 次失败后的等待时长
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	sec := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	if max := float64(p.MaxBackoff); max > 0 && sec > max {
+		sec = max
+	}
+	return time.Duration(sec * float64(time.Second))
+}
+
+// deliver 按主题重试策略消费消息，重试用尽后写入死信
+// 返回 nil 表示消息已处理完毕（成功或已进入死信），驱动可以确认；返回错误则不确认，由驱动重新投递。
+func deliver(ctx context.Context, job Consumer, mqMsg MqMsg) (err error) {
+	var (
+		topic  = job.GetTopic()
+		policy = PolicyFor(topic)
+	)
+
+	for attempt := 1; ; attempt++ {
+		if err = safeHandle(ctx, job, mqMsg); err == nil {
+			return nil
+		}
+		ConsumerLog(ctx, topic, mqMsg, err)
+		if attempt >= policy.MaxAttempts {
+			break
+		}
+		time.Sleep(policy.Backoff(attempt))
+	}
+
+	if dlErr := SaveDeadLetter(ctx, topic, mqMsg, policy.MaxAttempts, err); dlErr != nil {
+		Logger().Errorf(ctx, "queue dead letter save failed, topic:%v, msgId:%v, err:%+v", topic, mqMsg.MsgId, dlErr)
+		return err
+	}
+	Logger().Warningf(ctx, "queue message moved to dead letter, topic:%v, msgId:%v, attempts:%v, err:%+v", topic, mqMsg.MsgId, policy.MaxAttempts, err)
+	return nil
+}
+
+// safeHandle 调用消费者处理消息，panic 视为一次消费失败
+func safeHandle(ctx context.Context, job Consumer, mqMsg MqMsg) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = gerror.Newf("consumer panic: %v", r)
+		}
+	}()
+	return job.Handle(ctx, mqMsg)
+}
+
+// SaveDeadLetter 写入死信
+func SaveDeadLetter(ctx context.Context, topic string, mqMsg MqMsg, attempts int, cause error) (err error) {
+	errText := ""
+	if cause != nil {
+		errText = fmt.Sprintf("%+v", cause)
+	}
+	cols := dao.SysQueueDeadLetter.Columns()
+	_, err = dao.SysQueueDeadLetter.Ctx(ctx).Data(map[string]interface{}{
+		cols.Topic:     topic,
+		cols.MsgId:     mqMsg.MsgId,
+		cols.Body:      string(mqMsg.Body),
+		cols.Error:     errText,
+		cols.Attempts:  attempts,
+		cols.Status:    DeadLetterStatusPending,
+		cols.CreatedAt: gtime.Now(),
+		cols.UpdatedAt: gtime.Now(),
+	}).Insert()
+	return
+}
@@ -179,18 +179,7 @@ func (r *RocketMq) SendDelayMsg(topic string, body string, delayTimeLevel int64)
 
 // ListenReceiveMsgDo 消费数据
 func (r *RocketMq) ListenReceiveMsgDo(topic string, receiveDo func(mqMsg MqMsg)) (err error) {
-	if r.consumerIns == nil {
-		return gerror.New("rocketMq consumer not register")
-	}
-
-	rocketManager.cMutex.Lock()
-	defer rocketManager.cMutex.Unlock()
-
-	if err = r.createTopicIfNotExists(topic); err != nil {
-		return err
-	}
-
-	err = r.consumerIns.Subscribe(topic, consumer.MessageSelector{}, func(ctx context.Context, msgs ...*primitive.MessageExt) (consumer.ConsumeResult, error) {
+	return r.subscribe(topic, func(ctx context.Context, msgs ...*primitive.MessageExt) (consumer.ConsumeResult, error) {
 		for _, item := range msgs {
 			_ = rocketManager.goPool.Add(ctx, func(ctx context.Context) {
 				receiveDo(MqMsg{
@@ -203,8 +192,39 @@ func (r *RocketMq) ListenReceiveMsgDo(topic string, receiveDo func(mqMsg MqMsg))
 		}
 		return consumer.ConsumeSuccess, nil
 	})
+}
 
-	if err != nil {
+// ListenReceiveMsgAck 消费数据，同步处理，失败时返回 ConsumeRetryLater 由 broker 重新投递
+func (r *RocketMq) ListenReceiveMsgAck(topic string, receiveDo func(mqMsg MqMsg) error) (err error) {
+	return r.subscribe(topic, func(ctx context.Context, msgs ...*primitive.MessageExt) (consumer.ConsumeResult, error) {
+		for _, item := range msgs {
+			if err := receiveDo(MqMsg{
+				RunType: ReceiveMsg,
+				Topic:   item.Topic,
+				MsgId:   item.MsgId,
+				Body:    item.Body,
+			}); err != nil {
+				return consumer.ConsumeRetryLater, err
+			}
+		}
+		return consumer.ConsumeSuccess, nil
+	})
+}
+
+// subscribe 订阅主题并启动消费者
+func (r *RocketMq) subscribe(topic string, f func(ctx context.Context, msgs ...*primitive.MessageExt) (consumer.ConsumeResult, error)) (err error) {
+	if r.consumerIns == nil {
+		return gerror.New("rocketMq consumer not register")
+	}
+
+	rocketManager.cMutex.Lock()
+	defer rocketManager.cMutex.Unlock()
+
+	if err = r.createTopicIfNotExists(topic); err != nil {
+		return err
+	}
+
+	if err = r.consumerIns.Subscribe(topic, consumer.MessageSelector{}, f); err != nil {
 		return
 	}
 
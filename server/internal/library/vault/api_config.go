@@ -0,0 +1,39 @@
+// Package vault
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 交易所API配置凭证
+package vault
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"hotgo/internal/model/entity"
+)
+
+// SealedFromApiConfig 取出API配置中的密文凭证
+func SealedFromApiConfig(apiConfig *entity.TradingApiConfig) *Sealed {
+	return &Sealed{
+		ApiKey:     apiConfig.ApiKey,
+		SecretKey:  apiConfig.SecretKey,
+		Passphrase: apiConfig.Passphrase,
+		DataKey:    apiConfig.DataKey,
+		KeyVersion: apiConfig.KeyVersion,
+	}
+}
+
+// ApiCredentials 解密API配置中的凭证，所有交易所客户端构造都应通过此方法取得明文
+func ApiCredentials(ctx context.Context, apiConfig *entity.TradingApiConfig) (*Credentials, error) {
+	if apiConfig == nil {
+		return nil, gerror.New("API配置不能为空")
+	}
+	c, err := Open(ctx, SealedFromApiConfig(apiConfig))
+	if err != nil {
+		return nil, gerror.Wrapf(err, "解密API凭证失败: apiConfigId=%d", apiConfig.Id)
+	}
+	if c.ApiKey == "" || c.SecretKey == "" {
+		return nil, gerror.Newf("API凭证不完整: apiConfigId=%d", apiConfig.Id)
+	}
+	return c, nil
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+
+// Package vault
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 密钥环文件锁
+package vault
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile 对整个文件加排他锁，阻塞直到获得锁
+func lockFile(f *os.File) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// unlockFile 释放文件锁
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
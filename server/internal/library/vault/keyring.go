@@ -0,0 +1,331 @@
+// Package vault
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 本地文件密钥环
+package vault
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// KMSLocal 本地文件密钥环驱动
+const KMSLocal = "local"
+
+const defaultKeyringPath = "storage/vault/keyring.json"
+
+// keyringFile 密钥环文件内容
+type keyringFile struct {
+	Current int               `json:"current"` // 当前版本
+	Keys    map[string]string `json:"keys"`    // 版本 => base64主密钥
+}
+
+// LocalKMS 本地文件密钥环
+// 说明：
+// - 首次部署需执行 `tools -m=vault -a1=init` 生成版本1，文件权限0600，应与数据库分开备份
+// - 文件缺失时直接报错而不是自动生成，避免挂载丢失后用新密钥加密，导致已有数据无法解密
+// - 集群部署时各节点需挂载同一份密钥环；文件修改后各节点在下次使用时自动重新加载
+// - 初始化与轮换持有同目录下的 .lock 文件锁读改写，多个进程同时操作时不会生成重复版本而丢失主密钥
+type LocalKMS struct {
+	path    string
+	mu      sync.RWMutex
+	current int
+	keys    map[int][]byte
+	modTime time.Time
+}
+
+func newLocalKMS(ctx context.Context) (KMS, error) {
+	return NewLocalKMS(keyringPath(ctx))
+}
+
+func keyringPath(ctx context.Context) string {
+	return g.Cfg().MustGet(ctx, "vault.keyringPath", defaultKeyringPath).String()
+}
+
+// NewLocalKMS 打开本地密钥环，文件不存在时返回错误
+func NewLocalKMS(path string) (*LocalKMS, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, gerror.Newf("vault keyring %s not found, run `tools -m=vault -a1=init` to initialise it", path)
+	}
+	k := &LocalKMS{path: path}
+	if err := k.load(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// InitLocalKMS 初始化本地密钥环并生成版本1主密钥，文件已存在时返回错误
+func InitLocalKMS(path string) (*LocalKMS, error) {
+	k := &LocalKMS{path: path}
+	err := k.withFileLock(func() error {
+		if _, err := os.Stat(path); err == nil {
+			return gerror.Newf("vault keyring %s already exists", path)
+		} else if !os.IsNotExist(err) {
+			return gerror.Wrap(err, "vault keyring unavailable")
+		}
+		key, err := newMasterKey()
+		if err != nil {
+			return err
+		}
+		return k.save(&keyringFile{Current: 1, Keys: map[string]string{"1": key}})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = k.load(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// InitKeyring 按配置的路径初始化本地密钥环，返回密钥环路径
+func InitKeyring(ctx context.Context) (string, error) {
+	path := keyringPath(ctx)
+	_, err := InitLocalKMS(path)
+	return path, err
+}
+
+// Name 驱动名称
+func (k *LocalKMS) Name() string {
+	return KMSLocal
+}
+
+// CurrentVersion 当前用于包装的主密钥版本
+func (k *LocalKMS) CurrentVersion(ctx context.Context) (int, error) {
+	if err := k.reloadIfChanged(); err != nil {
+		return 0, err
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.current, nil
+}
+
+// Wrap 使用指定版本的主密钥包装数据密钥
+func (k *LocalKMS) Wrap(ctx context.Context, version int, dataKey []byte) ([]byte, error) {
+	master, err := k.master(version)
+	if err != nil {
+		return nil, err
+	}
+	return sealBytes(master, dataKey, versionAAD(version))
+}
+
+// Unwrap 使用指定版本的主密钥解包数据密钥
+func (k *LocalKMS) Unwrap(ctx context.Context, version int, wrapped []byte) ([]byte, error) {
+	master, err := k.master(version)
+	if err != nil {
+		return nil, err
+	}
+	return openBytes(master, wrapped, versionAAD(version))
+}
+
+// Rotate 生成新版本主密钥并设为当前版本，旧版本保留用于解包
+func (k *LocalKMS) Rotate(ctx context.Context) (next int, err error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	err = k.withFileLock(func() error {
+		file, err := k.read()
+		if err != nil {
+			return err
+		}
+		key, err := newMasterKey()
+		if err != nil {
+			return err
+		}
+
+		next = file.Current + 1
+		for v := range file.Keys {
+			if n, _ := strconv.Atoi(v); n >= next {
+				next = n + 1
+			}
+		}
+		file.Keys[strconv.Itoa(next)] = key
+		file.Current = next
+
+		if err = k.save(file); err != nil {
+			return err
+		}
+		return k.apply(file)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// withFileLock 持有密钥环文件锁执行读改写；密钥环通过替换文件写入，因此锁加在单独的 .lock 文件上
+func (k *LocalKMS) withFileLock(fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(k.path), 0700); err != nil {
+		return gerror.Wrap(err, "create vault keyring dir failed")
+	}
+	f, err := os.OpenFile(k.path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return gerror.Wrap(err, "open vault keyring lock failed")
+	}
+	defer f.Close()
+	if err = lockFile(f); err != nil {
+		return gerror.Wrap(err, "lock vault keyring failed")
+	}
+	defer func() { _ = unlockFile(f) }()
+	return fn()
+}
+
+func (k *LocalKMS) master(version int) ([]byte, error) {
+	if err := k.reloadIfChanged(); err != nil {
+		return nil, err
+	}
+	k.mu.RLock()
+	key, ok := k.keys[version]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, gerror.Newf("vault master key version %d not found in keyring", version)
+	}
+	return key, nil
+}
+
+// reloadIfChanged 其他进程轮换后文件发生变化，重新加载
+func (k *LocalKMS) reloadIfChanged() error {
+	st, err := os.Stat(k.path)
+	if err != nil {
+		return gerror.Wrap(err, "vault keyring unavailable")
+	}
+	k.mu.RLock()
+	changed := !st.ModTime().Equal(k.modTime)
+	k.mu.RUnlock()
+	if !changed {
+		return nil
+	}
+	return k.load()
+}
+
+func (k *LocalKMS) load() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	file, err := k.read()
+	if err != nil {
+		return err
+	}
+	return k.apply(file)
+}
+
+func (k *LocalKMS) read() (*keyringFile, error) {
+	b, err := os.ReadFile(k.path)
+	if err != nil {
+		return nil, gerror.Wrap(err, "read vault keyring failed")
+	}
+	var file *keyringFile
+	if err = json.Unmarshal(b, &file); err != nil || file == nil {
+		return nil, gerror.New("vault keyring is corrupted")
+	}
+	if file.Keys == nil {
+		file.Keys = make(map[string]string)
+	}
+	return file, nil
+}
+
+// apply 调用方需持有写锁
+func (k *LocalKMS) apply(file *keyringFile) error {
+	keys := make(map[int][]byte, len(file.Keys))
+	for v, encoded := range file.Keys {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return gerror.Newf("vault keyring has invalid version: %s", v)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return gerror.Newf("vault keyring has invalid key for version %d", n)
+		}
+		keys[n] = key
+	}
+	if _, ok := keys[file.Current]; !ok {
+		return gerror.Newf("vault keyring current version %d has no key", file.Current)
+	}
+
+	k.current = file.Current
+	k.keys = keys
+	if st, err := os.Stat(k.path); err == nil {
+		k.modTime = st.ModTime()
+	}
+	return nil
+}
+
+// save 先写临时文件再替换，避免写入中断导致密钥环损坏
+func (k *LocalKMS) save(file *keyringFile) error {
+	if err := os.MkdirAll(filepath.Dir(k.path), 0700); err != nil {
+		return gerror.Wrap(err, "create vault keyring dir failed")
+	}
+	b, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := k.path + ".tmp"
+	if err = os.WriteFile(tmp, b, 0600); err != nil {
+		return gerror.Wrap(err, "write vault keyring failed")
+	}
+	if err = os.Rename(tmp, k.path); err != nil {
+		return gerror.Wrap(err, "replace vault keyring failed")
+	}
+	return nil
+}
+
+func newMasterKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func versionAAD(version int) []byte {
+	return []byte("vault:kek:" + strconv.Itoa(version))
+}
+
+// sealBytes AES-256-GCM 加密，输出 nonce||ciphertext
+func sealBytes(key, plaintext, aad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// openBytes 解密 sealBytes 的输出
+func openBytes(key, data, aad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, gerror.New("vault ciphertext too short")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], aad)
+	if err != nil {
+		return nil, gerror.New("vault decryption failed")
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,55 @@
+// Package vault 敏感凭证保险箱
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 信封加密：每条记录使用独立的数据密钥加密字段，数据密钥由KMS中的主密钥包装后随记录保存
+package vault
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+// KMS 主密钥服务，主密钥只在KMS内部使用，对外只提供数据密钥的包装与解包
+// 主密钥按版本管理：新数据使用当前版本包装，旧版本保留用于解包，轮换后通过重新包装迁移到新版本
+type KMS interface {
+	// Name 驱动名称
+	Name() string
+	// CurrentVersion 当前用于包装的主密钥版本
+	CurrentVersion(ctx context.Context) (version int, err error)
+	// Wrap 使用指定版本的主密钥包装数据密钥
+	Wrap(ctx context.Context, version int, dataKey []byte) (wrapped []byte, err error)
+	// Unwrap 使用指定版本的主密钥解包数据密钥
+	Unwrap(ctx context.Context, version int, wrapped []byte) (dataKey []byte, err error)
+	// Rotate 生成新版本主密钥并设为当前版本，返回新版本号
+	Rotate(ctx context.Context) (version int, err error)
+}
+
+// KMSFactory 创建KMS驱动
+type KMSFactory func(ctx context.Context) (KMS, error)
+
+var (
+	kmsMu        sync.RWMutex
+	kmsFactories = map[string]KMSFactory{
+		KMSLocal: newLocalKMS,
+	}
+)
+
+// RegisterKMS 注册KMS驱动，对接云厂商KMS或HSM时在 init 中注册，并配置 vault.kms 为驱动名称
+func RegisterKMS(name string, factory KMSFactory) {
+	kmsMu.Lock()
+	defer kmsMu.Unlock()
+	kmsFactories[name] = factory
+}
+
+func newKMS(ctx context.Context, name string) (KMS, error) {
+	kmsMu.RLock()
+	factory, ok := kmsFactories[name]
+	kmsMu.RUnlock()
+	if !ok {
+		return nil, gerror.Newf("vault kms driver not registered: %s", name)
+	}
+	return factory(ctx)
+}
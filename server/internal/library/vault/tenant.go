@@ -0,0 +1,38 @@
+// Package vault
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 白标租户NOWPayments收款凭证
+package vault
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"hotgo/internal/model/entity"
+)
+
+// SealedFromTenant 取出租户的收款密文凭证，ApiKey 对应 API Key，SecretKey 对应 IPN 密钥
+func SealedFromTenant(tenant *entity.ToogoTenant) *Sealed {
+	return &Sealed{
+		ApiKey:     tenant.NowpaymentsApiKey,
+		SecretKey:  tenant.NowpaymentsIpnSecret,
+		DataKey:    tenant.NowpaymentsDataKey,
+		KeyVersion: tenant.NowpaymentsKeyVersion,
+	}
+}
+
+// TenantCredentials 解密租户的收款凭证，未配置时返回空凭证
+func TenantCredentials(ctx context.Context, tenant *entity.ToogoTenant) (*Credentials, error) {
+	if tenant == nil {
+		return nil, gerror.New("租户不能为空")
+	}
+	if tenant.NowpaymentsApiKey == "" && tenant.NowpaymentsIpnSecret == "" {
+		return new(Credentials), nil
+	}
+	c, err := Open(ctx, SealedFromTenant(tenant))
+	if err != nil {
+		return nil, gerror.Wrapf(err, "解密租户收款凭证失败: tenantId=%d", tenant.MemberId)
+	}
+	return c, nil
+}
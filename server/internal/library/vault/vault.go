@@ -0,0 +1,319 @@
+// Package vault
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 交易所凭证的加密、解密与密钥轮换，业务代码只通过本包访问明文凭证
+package vault
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcache"
+	"hotgo/utility/encrypt"
+)
+
+// 字段密文前缀，区别于旧版 encrypt.EncryptApiKey 的 ENC: 前缀
+const sealedPrefix = "VLT:"
+
+const (
+	fieldApiKey     = "apiKey"
+	fieldSecretKey  = "secretKey"
+	fieldPassphrase = "passphrase"
+)
+
+const defaultCacheTTL = 5 * time.Minute
+
+// Credentials 明文凭证
+// 格式化输出时只显示脱敏内容，避免误打日志泄露密钥
+type Credentials struct {
+	ApiKey     string
+	SecretKey  string
+	Passphrase string
+}
+
+// String 脱敏输出
+func (c Credentials) String() string {
+	return "Credentials{ApiKey:" + encrypt.MaskApiKey(c.ApiKey) + ", SecretKey:" + maskSecret(c.SecretKey) + ", Passphrase:" + maskSecret(c.Passphrase) + "}"
+}
+
+// GoString 脱敏输出，覆盖 %#v
+func (c Credentials) GoString() string {
+	return c.String()
+}
+
+// Sealed 密文凭证，字段与 hg_trading_api_config 一一对应
+// DataKey 为空表示旧版数据（应用静态密钥加密），读取时兼容，重新包装时迁移
+type Sealed struct {
+	ApiKey     string
+	SecretKey  string
+	Passphrase string
+	DataKey    string // 被主密钥包装后的数据密钥（base64）
+	KeyVersion int    // 包装数据密钥的主密钥版本
+}
+
+// IsLegacy 是否为旧版加密数据
+func (s *Sealed) IsLegacy() bool {
+	return s.DataKey == ""
+}
+
+// Vault 凭证保险箱
+type Vault struct {
+	kms      KMS
+	cache    *gcache.Cache
+	cacheTTL time.Duration
+}
+
+var (
+	defaultVault *Vault
+	defaultMu    sync.Mutex
+)
+
+// New 创建保险箱，cacheTTL<=0 时不缓存明文
+func New(kms KMS, cacheTTL time.Duration) *Vault {
+	return &Vault{
+		kms:      kms,
+		cache:    gcache.New(),
+		cacheTTL: cacheTTL,
+	}
+}
+
+// Instance 按配置初始化的默认保险箱
+func Instance(ctx context.Context) (*Vault, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultVault != nil {
+		return defaultVault, nil
+	}
+
+	driver := g.Cfg().MustGet(ctx, "vault.kms", KMSLocal).String()
+	kms, err := newKMS(ctx, driver)
+	if err != nil {
+		return nil, err
+	}
+	ttl := g.Cfg().MustGet(ctx, "vault.cacheTTL", defaultCacheTTL).Duration()
+	defaultVault = New(kms, ttl)
+	return defaultVault, nil
+}
+
+// Seal 使用新的数据密钥加密凭证
+func Seal(ctx context.Context, c *Credentials) (*Sealed, error) {
+	v, err := Instance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return v.Seal(ctx, c)
+}
+
+// Open 解密凭证
+func Open(ctx context.Context, s *Sealed) (*Credentials, error) {
+	v, err := Instance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return v.Open(ctx, s)
+}
+
+// Seal 使用新的数据密钥加密凭证
+func (v *Vault) Seal(ctx context.Context, c *Credentials) (*Sealed, error) {
+	if c == nil {
+		return nil, gerror.New("vault credentials is nil")
+	}
+	version, err := v.kms.CurrentVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dataKey := make([]byte, 32)
+	if _, err = rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	wrapped, err := v.kms.Wrap(ctx, version, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Sealed{
+		DataKey:    base64.StdEncoding.EncodeToString(wrapped),
+		KeyVersion: version,
+	}
+	if s.ApiKey, err = sealField(dataKey, fieldApiKey, c.ApiKey); err != nil {
+		return nil, err
+	}
+	if s.SecretKey, err = sealField(dataKey, fieldSecretKey, c.SecretKey); err != nil {
+		return nil, err
+	}
+	if s.Passphrase, err = sealField(dataKey, fieldPassphrase, c.Passphrase); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Open 解密凭证，结果按密文指纹在进程内缓存 cacheTTL
+func (v *Vault) Open(ctx context.Context, s *Sealed) (*Credentials, error) {
+	if s == nil {
+		return nil, gerror.New("vault sealed credentials is nil")
+	}
+
+	key := fingerprint(s)
+	if v.cacheTTL > 0 {
+		if val, _ := v.cache.Get(ctx, key); val != nil {
+			if c, ok := val.Val().(*Credentials); ok {
+				copied := *c
+				return &copied, nil
+			}
+		}
+	}
+
+	c, err := v.open(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.cacheTTL > 0 {
+		copied := *c
+		_ = v.cache.Set(ctx, key, &copied, v.cacheTTL)
+	}
+	return c, nil
+}
+
+func (v *Vault) open(ctx context.Context, s *Sealed) (c *Credentials, err error) {
+	c = new(Credentials)
+	if s.IsLegacy() {
+		if c.ApiKey, err = encrypt.DecryptApiKey(s.ApiKey); err != nil {
+			return nil, gerror.Wrap(err, "decrypt legacy apiKey failed")
+		}
+		if c.SecretKey, err = encrypt.DecryptApiKey(s.SecretKey); err != nil {
+			return nil, gerror.Wrap(err, "decrypt legacy secretKey failed")
+		}
+		if c.Passphrase, err = encrypt.DecryptApiKey(s.Passphrase); err != nil {
+			return nil, gerror.Wrap(err, "decrypt legacy passphrase failed")
+		}
+		return c, nil
+	}
+
+	dataKey, err := v.unwrap(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	if c.ApiKey, err = openField(dataKey, fieldApiKey, s.ApiKey); err != nil {
+		return nil, err
+	}
+	if c.SecretKey, err = openField(dataKey, fieldSecretKey, s.SecretKey); err != nil {
+		return nil, err
+	}
+	if c.Passphrase, err = openField(dataKey, fieldPassphrase, s.Passphrase); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Rewrap 将凭证迁移到当前主密钥版本
+// 新版数据只重新包装数据密钥，字段密文不变；旧版数据解密后使用新数据密钥重新加密
+// 已是当前版本时返回 changed=false
+func (v *Vault) Rewrap(ctx context.Context, s *Sealed) (out *Sealed, changed bool, err error) {
+	version, err := v.kms.CurrentVersion(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if s.IsLegacy() {
+		c, err := v.open(ctx, s)
+		if err != nil {
+			return nil, false, err
+		}
+		out, err = v.Seal(ctx, c)
+		return out, err == nil, err
+	}
+
+	if s.KeyVersion == version {
+		return s, false, nil
+	}
+	dataKey, err := v.unwrap(ctx, s)
+	if err != nil {
+		return nil, false, err
+	}
+	wrapped, err := v.kms.Wrap(ctx, version, dataKey)
+	if err != nil {
+		return nil, false, err
+	}
+	out = &Sealed{
+		ApiKey:     s.ApiKey,
+		SecretKey:  s.SecretKey,
+		Passphrase: s.Passphrase,
+		DataKey:    base64.StdEncoding.EncodeToString(wrapped),
+		KeyVersion: version,
+	}
+	return out, true, nil
+}
+
+// Rotate 轮换主密钥，返回新版本号。已有数据仍可用旧版本解包，需再执行重新包装完成迁移
+func (v *Vault) Rotate(ctx context.Context) (int, error) {
+	return v.kms.Rotate(ctx)
+}
+
+// KMS 当前使用的主密钥服务
+func (v *Vault) KMS() KMS {
+	return v.kms
+}
+
+func (v *Vault) unwrap(ctx context.Context, s *Sealed) ([]byte, error) {
+	wrapped, err := base64.StdEncoding.DecodeString(s.DataKey)
+	if err != nil {
+		return nil, gerror.New("vault data key is corrupted")
+	}
+	return v.kms.Unwrap(ctx, s.KeyVersion, wrapped)
+}
+
+func sealField(dataKey []byte, field, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	ct, err := sealBytes(dataKey, []byte(plaintext), []byte(field))
+	if err != nil {
+		return "", err
+	}
+	return sealedPrefix + base64.StdEncoding.EncodeToString(ct), nil
+}
+
+func openField(dataKey []byte, field, sealed string) (string, error) {
+	if sealed == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(sealed, sealedPrefix) {
+		return "", gerror.Newf("vault %s is not sealed", field)
+	}
+	ct, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(sealed, sealedPrefix))
+	if err != nil {
+		return "", gerror.Newf("vault %s is corrupted", field)
+	}
+	plaintext, err := openBytes(dataKey, ct, []byte(field))
+	if err != nil {
+		return "", gerror.Newf("vault %s decryption failed", field)
+	}
+	return string(plaintext), nil
+}
+
+// fingerprint 缓存键只使用密文摘要，缓存中不出现可还原的密文或明文索引
+func fingerprint(s *Sealed) string {
+	h := sha256.New()
+	for _, part := range []string{s.ApiKey, s.SecretKey, s.Passphrase, s.DataKey} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return "vault:" + hex.EncodeToString(h.Sum(nil))
+}
+
+func maskSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "****"
+}
@@ -0,0 +1,214 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hotgo/utility/encrypt"
+)
+
+func newTestVault(t *testing.T) *Vault {
+	t.Helper()
+	kms, err := InitLocalKMS(filepath.Join(t.TempDir(), "keyring.json"))
+	if err != nil {
+		t.Fatalf("InitLocalKMS: %v", err)
+	}
+	return New(kms, 0)
+}
+
+func TestSealOpen(t *testing.T) {
+	ctx := context.Background()
+	v := newTestVault(t)
+	in := &Credentials{ApiKey: "ak-123456789", SecretKey: "sk-secret", Passphrase: ""}
+
+	s, err := v.Seal(ctx, in)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if s.KeyVersion != 1 || s.DataKey == "" {
+		t.Fatalf("unexpected key meta: version=%d dataKey=%q", s.KeyVersion, s.DataKey)
+	}
+	if !strings.HasPrefix(s.ApiKey, sealedPrefix) || strings.Contains(s.SecretKey, "sk-secret") {
+		t.Fatalf("fields not sealed: %+v", s)
+	}
+	if s.Passphrase != "" {
+		t.Fatalf("empty passphrase should stay empty, got %q", s.Passphrase)
+	}
+
+	out, err := v.Open(ctx, s)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if *out != *in {
+		t.Fatalf("Open mismatch: got %+v", *out)
+	}
+
+	// 字段互换后 AAD 不匹配，必须解密失败
+	swapped := *s
+	swapped.ApiKey, swapped.SecretKey = s.SecretKey, s.ApiKey
+	if _, err = v.Open(ctx, &swapped); err == nil {
+		t.Fatal("swapped fields should fail to open")
+	}
+}
+
+func TestRotateRewrap(t *testing.T) {
+	ctx := context.Background()
+	v := newTestVault(t)
+	in := &Credentials{ApiKey: "ak", SecretKey: "sk", Passphrase: "pp"}
+
+	s, err := v.Seal(ctx, in)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	version, err := v.Rotate(ctx)
+	if err != nil || version != 2 {
+		t.Fatalf("Rotate: version=%d err=%v", version, err)
+	}
+
+	// 旧版本包装的数据轮换后仍可读取
+	if out, err := v.Open(ctx, s); err != nil || *out != *in {
+		t.Fatalf("Open after rotate: %v", err)
+	}
+
+	out, changed, err := v.Rewrap(ctx, s)
+	if err != nil || !changed {
+		t.Fatalf("Rewrap: changed=%v err=%v", changed, err)
+	}
+	if out.KeyVersion != 2 || out.ApiKey != s.ApiKey {
+		t.Fatalf("Rewrap should only re-wrap data key: %+v", out)
+	}
+	if opened, err := v.Open(ctx, out); err != nil || *opened != *in {
+		t.Fatalf("Open after rewrap: %v", err)
+	}
+	if _, changed, _ = v.Rewrap(ctx, out); changed {
+		t.Fatal("Rewrap at current version should be a no-op")
+	}
+}
+
+func TestRewrapLegacy(t *testing.T) {
+	ctx := context.Background()
+	v := newTestVault(t)
+
+	ak, _ := encrypt.EncryptApiKey("legacy-ak")
+	sk, _ := encrypt.EncryptApiKey("legacy-sk")
+	legacy := &Sealed{ApiKey: ak, SecretKey: sk}
+
+	out, err := v.Open(ctx, legacy)
+	if err != nil || out.ApiKey != "legacy-ak" || out.SecretKey != "legacy-sk" {
+		t.Fatalf("Open legacy: %+v %v", out, err)
+	}
+
+	migrated, changed, err := v.Rewrap(ctx, legacy)
+	if err != nil || !changed || migrated.IsLegacy() {
+		t.Fatalf("Rewrap legacy: changed=%v err=%v", changed, err)
+	}
+	if opened, err := v.Open(ctx, migrated); err != nil || opened.SecretKey != "legacy-sk" {
+		t.Fatalf("Open migrated: %v", err)
+	}
+}
+
+func TestKeyringInit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault", "keyring.json")
+	if _, err := NewLocalKMS(path); err == nil {
+		t.Fatalf("NewLocalKMS must fail when keyring is missing")
+	}
+
+	kms, err := InitLocalKMS(path)
+	if err != nil {
+		t.Fatalf("InitLocalKMS: %v", err)
+	}
+	version, err := kms.CurrentVersion(context.Background())
+	if err != nil || version != 1 {
+		t.Fatalf("CurrentVersion: version=%v err=%v", version, err)
+	}
+	if _, err = InitLocalKMS(path); err == nil {
+		t.Fatalf("InitLocalKMS must not overwrite an existing keyring")
+	}
+	if _, err = NewLocalKMS(path); err != nil {
+		t.Fatalf("NewLocalKMS existing keyring: %v", err)
+	}
+}
+
+func TestCacheAndMask(t *testing.T) {
+	ctx := context.Background()
+	kms, err := InitLocalKMS(filepath.Join(t.TempDir(), "keyring.json"))
+	if err != nil {
+		t.Fatalf("InitLocalKMS: %v", err)
+	}
+	v := New(kms, time.Minute)
+
+	in := &Credentials{ApiKey: "abcd12345678wxyz", SecretKey: "super-secret"}
+	s, _ := v.Seal(ctx, in)
+	first, _ := v.Open(ctx, s)
+	first.SecretKey = "tampered"
+	second, err := v.Open(ctx, s)
+	if err != nil || second.SecretKey != "super-secret" {
+		t.Fatalf("cached credentials must not be shared: %+v %v", second, err)
+	}
+
+	for _, out := range []string{fmt.Sprint(*in), fmt.Sprintf("%v", in), fmt.Sprintf("%#v", *in)} {
+		if strings.Contains(out, "super-secret") || strings.Contains(out, "abcd12345678wxyz") {
+			t.Fatalf("credentials leaked in format output: %s", out)
+		}
+	}
+}
+
+// TestRotateConcurrent 多个进程同时轮换同一份密钥环，每次轮换得到不同版本且主密钥不丢失
+func TestRotateConcurrent(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	if _, err := InitLocalKMS(path); err != nil {
+		t.Fatalf("InitLocalKMS: %v", err)
+	}
+
+	const workers, rounds = 4, 5
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		versions = make(map[int]bool)
+	)
+	for i := 0; i < workers; i++ {
+		// 每个实例单独打开密钥环，模拟不同进程
+		kms, err := NewLocalKMS(path)
+		if err != nil {
+			t.Fatalf("NewLocalKMS: %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < rounds; j++ {
+				v, err := kms.Rotate(ctx)
+				if err != nil {
+					t.Errorf("Rotate: %v", err)
+					return
+				}
+				mu.Lock()
+				if versions[v] {
+					t.Errorf("version %d rotated twice", v)
+				}
+				versions[v] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	kms, err := NewLocalKMS(path)
+	if err != nil {
+		t.Fatalf("NewLocalKMS: %v", err)
+	}
+	want := 1 + workers*rounds
+	if cur, _ := kms.CurrentVersion(ctx); cur != want {
+		t.Fatalf("current version = %d, want %d", cur, want)
+	}
+	for v := 1; v <= want; v++ {
+		if _, err = kms.master(v); err != nil {
+			t.Fatalf("master key version %d lost: %v", v, err)
+		}
+	}
+}
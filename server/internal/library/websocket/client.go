@@ -23,8 +23,9 @@ const (
 
 // ClientMessage 客户端发送的消息
 type ClientMessage struct {
-	Action  string `json:"action"`  // subscribe/unsubscribe/ping
-	Channel string `json:"channel"` // 频道名称
+	Action  string `json:"action"`            // subscribe/unsubscribe/resume/ping
+	Channel string `json:"channel"`           // 频道名称
+	LastSeq *int64 `json:"lastSeq,omitempty"` // 重新订阅时最后收到的频道序号
 }
 
 // NewClient 创建新客户端
@@ -124,6 +125,16 @@ func (c *Client) handleMessage(data []byte) {
 			c.Hub.Subscribe(c, msg.Channel)
 			// 发送订阅确认
 			c.sendResponse("subscribed", msg.Channel)
+			// 断线重连补发缺失的频道消息
+			if msg.LastSeq != nil {
+				c.Hub.Resume(c, msg.Channel, *msg.LastSeq)
+			}
+		}
+		
+	case "resume":
+		// 连接未断开但检测到序号缺口时，按最后收到的序号补发；只能补发已订阅的频道
+		if msg.Channel != "" && msg.LastSeq != nil && c.isSubscribed(msg.Channel) {
+			c.Hub.Resume(c, msg.Channel, *msg.LastSeq)
 		}
 		
 	case "unsubscribe":
@@ -140,6 +151,13 @@ func (c *Client) handleMessage(data []byte) {
 	}
 }
 
+// isSubscribed 客户端是否已订阅频道
+func (c *Client) isSubscribed(channel string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Subscribed[channel]
+}
+
 // sendResponse 发送响应
 func (c *Client) sendResponse(action, channel string) {
 	response := map[string]interface{}{
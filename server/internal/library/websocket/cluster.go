@@ -0,0 +1,150 @@
+// Package websocket Hub跨节点推送
+// 集群部署时，SendToUser/SendToChannel/Broadcast 先投递给本节点连接，再通过redis广播给其他节点；
+// 频道消息额外分配集群内递增的序号，客户端重新订阅时携带最后收到的序号即可补发缺失的消息。
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gredis"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gctx"
+
+	"hotgo/internal/consts"
+	"hotgo/internal/library/hgrds/pubsub"
+	"hotgo/utility/simple"
+)
+
+// 跨节点推送类型
+const (
+	hubClusterAll     = "all"
+	hubClusterUser    = "user"
+	hubClusterChannel = "channel"
+)
+
+// hubSeqPrefix 频道序号命名空间，避免与 internal/websocket 的频道冲突
+const hubSeqPrefix = "hub:"
+
+// hubClusterMessage 跨节点推送消息
+type hubClusterMessage struct {
+	Node    string   `json:"node"`
+	Kind    string   `json:"kind"`
+	UserID  int64    `json:"userId,omitempty"`
+	Message *Message `json:"message"`
+}
+
+var hubCtx = gctx.GetInitCtx()
+
+// SendToUser 发送消息给指定用户（集群内所有节点）
+func (h *Hub) SendToUser(userID int64, message *Message) {
+	h.sendToUserLocal(userID, message)
+	h.publishCluster(&hubClusterMessage{Kind: hubClusterUser, UserID: userID, Message: message})
+}
+
+// SendToChannel 发送消息到频道（集群内所有节点），并分配频道序号
+func (h *Hub) SendToChannel(channel string, message *Message) {
+	message.Channel = channel
+	if message.Timestamp == 0 {
+		message.Timestamp = time.Now().UnixMilli()
+	}
+
+	if payload, err := json.Marshal(message); err != nil {
+		g.Log().Warningf(hubCtx, "[WebSocket] SendToChannel marshal error: %v", err)
+	} else if message.Seq, err = pubsub.Append(hubCtx, hubSeqPrefix+channel, string(payload)); err != nil {
+		// 序号分配失败不影响实时推送，只是该消息无法补发
+		g.Log().Warningf(hubCtx, "[WebSocket] SendToChannel append error: channel=%s, err=%v", channel, err)
+	}
+
+	h.broadcast <- message
+	h.publishCluster(&hubClusterMessage{Kind: hubClusterChannel, Message: message})
+}
+
+// Broadcast 广播消息给所有客户端（集群内所有节点）
+func (h *Hub) Broadcast(message *Message) {
+	h.broadcastLocal(message)
+	h.publishCluster(&hubClusterMessage{Kind: hubClusterAll, Message: message})
+}
+
+// Resume 按客户端最后收到的序号补发频道消息
+// 缺失的消息已超出保留窗口时发送 resync 响应，客户端应全量刷新该频道的数据
+func (h *Hub) Resume(client *Client, channel string, lastSeq int64) {
+	list, complete, err := pubsub.Since(hubCtx, hubSeqPrefix+channel, lastSeq)
+	if err != nil {
+		g.Log().Warningf(hubCtx, "[WebSocket] Resume error: channel=%s, lastSeq=%d, err=%v", channel, lastSeq, err)
+	}
+
+	if !complete {
+		client.sendResponse("resync", channel)
+		return
+	}
+
+	for _, item := range list {
+		var message *Message
+		if err = json.Unmarshal([]byte(item.Payload), &message); err != nil || message == nil {
+			continue
+		}
+		message.Seq = item.Seq
+		data, err := json.Marshal(message)
+		if err != nil {
+			continue
+		}
+		select {
+		case client.Send <- data:
+		default:
+			// 发送缓冲已满，剩余消息无法补发，改为通知客户端全量刷新
+			client.sendResponse("resync", channel)
+			return
+		}
+	}
+}
+
+// PeerPushedWithin 其他节点是否在 d 时间内推送过该频道
+// 各节点都能生成的频道数据（如行情）可据此跳过本轮推送，避免订阅者收到多个节点的重复推送
+func (h *Hub) PeerPushedWithin(channel string, d time.Duration) bool {
+	v, ok := h.peerChannelAt.Load(channel)
+	return ok && time.Since(v.(time.Time)) < d
+}
+
+// publishCluster 广播给其他节点，未开启集群部署时不做任何处理
+func (h *Hub) publishCluster(msg *hubClusterMessage) {
+	if !simple.IsCluster(hubCtx) {
+		return
+	}
+
+	msg.Node = pubsub.NodeId()
+	data, err := json.Marshal(msg)
+	if err != nil {
+		g.Log().Warningf(hubCtx, "[WebSocket] publishCluster marshal error: %v", err)
+		return
+	}
+	if _, err = pubsub.Publish(hubCtx, consts.ClusterSyncWebsocketHub, string(data)); err != nil {
+		g.Log().Warningf(hubCtx, "[WebSocket] publishCluster error: kind=%s, err=%v", msg.Kind, err)
+	}
+}
+
+// ClusterSync 集群同步，投递其他节点发出的推送
+func ClusterSync(ctx context.Context, message *gredis.Message) {
+	var msg *hubClusterMessage
+	if err := json.Unmarshal([]byte(message.Payload), &msg); err != nil {
+		g.Log().Warningf(ctx, "[WebSocket] ClusterSync unmarshal error: %v", err)
+		return
+	}
+	if msg == nil || msg.Message == nil || msg.Node == pubsub.NodeId() {
+		return
+	}
+
+	h := GetHub()
+	switch msg.Kind {
+	case hubClusterUser:
+		h.sendToUserLocal(msg.UserID, msg.Message)
+	case hubClusterChannel:
+		h.peerChannelAt.Store(msg.Message.Channel, time.Now())
+		h.broadcastLocal(msg.Message)
+	case hubClusterAll:
+		h.broadcastLocal(msg.Message)
+	default:
+		g.Log().Warningf(ctx, "[WebSocket] ClusterSync unknown kind: %s", msg.Kind)
+	}
+}
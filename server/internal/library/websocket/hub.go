@@ -60,6 +60,7 @@ type Message struct {
 	Channel   string      `json:"channel"`    // 频道/交易对
 	Data      interface{} `json:"data"`       // 消息数据
 	Timestamp int64       `json:"timestamp"`  // 时间戳
+	Seq       int64       `json:"seq,omitempty"` // 频道内序号，重新订阅时携带最后收到的序号补发
 }
 
 // Client WebSocket客户端
@@ -132,6 +133,9 @@ type Hub struct {
 	// 是否运行中
 	running bool
 	stopCh  chan struct{}
+
+	// 其他节点最近一次推送频道消息的时间（channel -> time.Time），用于各节点行情推送去重
+	peerChannelAt sync.Map
 }
 
 // HubStats Hub统计信息
@@ -395,8 +399,8 @@ func (h *Hub) Unsubscribe(client *Client, channel string) {
 	}
 }
 
-// SendToUser 发送消息给指定用户
-func (h *Hub) SendToUser(userID int64, message *Message) {
+// sendToUserLocal 发送消息给本节点的指定用户
+func (h *Hub) sendToUserLocal(userID int64, message *Message) {
 	data, err := json.Marshal(message)
 	if err != nil {
 		g.Log().Errorf(context.Background(), "[WebSocket] Marshal message error: %v", err)
@@ -415,14 +419,8 @@ func (h *Hub) SendToUser(userID int64, message *Message) {
 	}
 }
 
-// SendToChannel 发送消息到频道
-func (h *Hub) SendToChannel(channel string, message *Message) {
-	message.Channel = channel
-	h.broadcast <- message
-}
-
-// Broadcast 广播消息给所有客户端
-func (h *Hub) Broadcast(message *Message) {
+// broadcastLocal 广播消息给本节点所有客户端
+func (h *Hub) broadcastLocal(message *Message) {
 	h.broadcast <- message
 }
 
@@ -546,7 +544,7 @@ func (h *Hub) KickUser(userID int64, reason string) {
 	}
 	
 	for _, client := range clients {
-		h.SendToUser(userID, kickMsg)
+		h.sendToUserLocal(userID, kickMsg)
 		h.Unregister(client)
 	}
 	
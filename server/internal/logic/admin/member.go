@@ -164,6 +164,12 @@ func (s *sAdminMember) UpdateCash(ctx context.Context, in *adminin.MemberUpdateC
 		return
 	}
 
+	// 敏感操作二次验证
+	if err = service.SysTwoFactor().RequireVerified(ctx, memberId, consts.SensitiveOpWithdrawAddress,
+		fmt.Sprintf("修改提现账户: name=%s, account=%s", in.Name, service.SysSecurity().MaskSensitiveString(in.Account, 3, 4))); err != nil {
+		return
+	}
+
 	_, err = dao.AdminMember.Ctx(ctx).Where(dao.AdminMember.Columns().Id, memberId).
 		Data(g.Map{
 			dao.AdminMember.Columns().Cash: adminin.MemberCash{
@@ -106,6 +106,28 @@ func (s *sAdminSite) Register(ctx context.Context, in *adminin.RegisterInp) (err
 		data.Pid = inviterId
 	}
 
+	// 白标租户：按访问域名确定用户所属租户，租户站点只接受本租户的邀请码
+	var tenantId int64
+	siteTenant, err := service.ToogoTenant().RequestTenant(ctx)
+	if err != nil {
+		return
+	}
+	if siteTenant != nil {
+		if siteTenant.Status != consts.StatusEnabled {
+			err = gerror.New("该站点已停止注册")
+			return
+		}
+		tenantId = siteTenant.MemberId
+	}
+	if in.InviteCode != "" {
+		inviterTenantId := service.ToogoTenant().MemberTenantId(ctx, data.Pid)
+		if siteTenant != nil && inviterTenantId != tenantId {
+			err = gerror.New("邀请码不属于当前站点")
+			return
+		}
+		tenantId = inviterTenantId
+	}
+
 	if config.RegisterSwitch != 1 {
 		err = gerror.New("管理员未开放注册")
 		return
@@ -183,6 +205,11 @@ func (s *sAdminSite) Register(ctx context.Context, in *adminin.RegisterInp) (err
 		return err
 	}
 
+	// 绑定所属租户（不影响基础注册成功）
+	if bindErr := service.ToogoTenant().BindMember(ctx, newMemberId, tenantId); bindErr != nil {
+		g.Log().Warningf(ctx, "绑定用户租户失败: memberId=%d, tenantId=%d, err=%v", newMemberId, tenantId, bindErr)
+	}
+
 	// 绑定 Toogo 邀请关系（不影响基础注册成功）
 	if in.InviteCode != "" && newMemberId > 0 {
 		if bindErr := service.ToogoUser().RegisterWithInvite(ctx, &toogoin.RegisterWithInviteInp{
@@ -229,6 +256,15 @@ func (s *sAdminSite) AccountLogin(ctx context.Context, in *adminin.AccountLoginI
 		return
 	}
 
+	var challenge *adminin.LoginModel
+	if challenge, err = s.twoFactorChallenge(ctx, mb, in.TwoFactorCode); err != nil {
+		return
+	}
+	if challenge != nil {
+		res = challenge
+		return
+	}
+
 	res, err = s.handleLogin(ctx, mb)
 	return
 }
@@ -269,10 +305,106 @@ func (s *sAdminSite) MobileLogin(ctx context.Context, in *adminin.MobileLoginInp
 		return
 	}
 
+	var challenge *adminin.LoginModel
+	if challenge, err = s.twoFactorChallenge(ctx, mb, in.TwoFactorCode); err != nil {
+		return
+	}
+	if challenge != nil {
+		res = challenge
+		return
+	}
+
 	res, err = s.handleLogin(ctx, mb)
 	return
 }
 
+// twoFactorChallenge 双因素认证校验
+// 无需双因素认证，或随登录提交的口令校验通过时返回nil，继续签发token；否则返回登录票据，由前端提交口令后完成登录
+func (s *sAdminSite) twoFactorChallenge(ctx context.Context, mb *entity.AdminMember, code string) (res *adminin.LoginModel, err error) {
+	enabled, err := service.SysTwoFactor().IsEnabled(ctx, mb.Id)
+	if err != nil {
+		return
+	}
+
+	if !enabled {
+		required, err := service.SysTwoFactor().RequiredForRole(ctx, mb.RoleId)
+		if err != nil || !required {
+			return nil, err
+		}
+	} else if code != "" {
+		err = service.SysTwoFactor().Verify(ctx, mb.Id, code)
+		return
+	}
+
+	ticket, err := service.SysTwoFactor().IssueLoginTicket(ctx, mb.Id)
+	if err != nil {
+		return
+	}
+
+	res = &adminin.LoginModel{
+		Id:                mb.Id,
+		Username:          mb.Username,
+		TwoFactorRequired: true,
+		TwoFactorSetup:    !enabled,
+		TwoFactorTicket:   ticket,
+	}
+	return
+}
+
+// TwoFactorLoginSetup 登录时绑定验证器，获取待绑定的密钥
+func (s *sAdminSite) TwoFactorLoginSetup(ctx context.Context, in *adminin.TwoFactorLoginSetupInp) (res *sysin.TwoFactorSetupModel, err error) {
+	memberId, err := service.SysTwoFactor().LoginTicketMember(ctx, in.Ticket)
+	if err != nil {
+		return
+	}
+
+	enabled, err := service.SysTwoFactor().IsEnabled(ctx, memberId)
+	if err != nil {
+		return
+	}
+	if enabled {
+		err = gerror.New("已绑定验证器，请直接输入动态口令")
+		return
+	}
+	return service.SysTwoFactor().Setup(ctx, memberId)
+}
+
+// TwoFactorLogin 提交动态口令完成登录
+func (s *sAdminSite) TwoFactorLogin(ctx context.Context, in *adminin.TwoFactorLoginInp) (res *adminin.LoginModel, err error) {
+	defer func() {
+		service.SysLoginLog().Push(ctx, &sysin.LoginLogPushInp{Response: res, Err: err})
+	}()
+
+	memberId, recovery, err := service.SysTwoFactor().VerifyLoginTicket(ctx, in.Ticket, in.Code)
+	if err != nil {
+		return
+	}
+
+	var mb *entity.AdminMember
+	if err = dao.AdminMember.Ctx(ctx).Where(dao.AdminMember.Columns().Id, memberId).Scan(&mb); err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+		return
+	}
+
+	if mb == nil {
+		err = gerror.New("账号不存在")
+		return
+	}
+
+	if mb.Status != consts.StatusEnabled {
+		err = gerror.New("账号已被禁用")
+		return
+	}
+
+	if res, err = s.handleLogin(ctx, mb); err != nil {
+		return
+	}
+	if recovery != nil {
+		res.RecoveryCodes = recovery.RecoveryCodes
+	}
+	return
+}
+
 // handleLogin .
 func (s *sAdminSite) handleLogin(ctx context.Context, mb *entity.AdminMember) (res *adminin.LoginModel, err error) {
 	role, dept, err := s.getLoginRoleAndDept(ctx, mb.RoleId, mb.DeptId)
@@ -285,6 +417,7 @@ func (s *sAdminSite) handleLogin(ctx context.Context, mb *entity.AdminMember) (r
 		Pid:      mb.Pid,
 		DeptId:   dept.Id,
 		DeptType: dept.Type,
+		TenantId: service.ToogoTenant().MemberTenantId(ctx, mb.Id),
 		RoleId:   role.Id,
 		RoleKey:  role.Key,
 		Username: mb.Username,
@@ -376,6 +509,7 @@ func (s *sAdminSite) BindUserContext(ctx context.Context, claims *model.Identity
 		Pid:      mb.Pid,
 		DeptId:   dept.Id,
 		DeptType: dept.Type,
+		TenantId: claims.TenantId,
 		RoleId:   mb.RoleId,
 		RoleKey:  role.Key,
 		Username: mb.Username,
@@ -43,9 +43,11 @@ func NewMiddleware() *sMiddleware {
 	return &sMiddleware{
 		LoginUrl: "/common",
 		DemoWhiteList: g.Map{
-			"/admin/site/accountLogin": struct{}{}, // 璐﹀彿鐧诲綍
-			"/admin/site/mobileLogin":  struct{}{}, // 鎵嬫満鍙风櫥褰?
-			"/admin/genCodes/preview":  struct{}{}, // 棰勮浠ｇ爜
+			"/admin/site/accountLogin":        struct{}{}, // 璐﹀彿鐧诲綍
+			"/admin/site/mobileLogin":         struct{}{}, // 鎵嬫満鍙风櫥褰?
+			"/admin/site/twoFactorLoginSetup": struct{}{}, // 登录时绑定验证器
+			"/admin/site/twoFactorLogin":      struct{}{}, // 双因素认证登录
+			"/admin/genCodes/preview":         struct{}{}, // 棰勮浠ｇ爜
 		},
 		NotRecordRequest: g.Map{
 			"/admin/upload/file":       struct{}{}, // 涓婁紶鏂囦欢
@@ -18,6 +18,7 @@ import (
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/form"
 	"hotgo/internal/model/input/payin"
+	"hotgo/internal/model/input/toogoin"
 	"hotgo/internal/service"
 	"hotgo/utility/convert"
 	"hotgo/utility/excel"
@@ -71,6 +72,11 @@ func (s *sPayRefund) Refund(ctx context.Context, in *payin.PayRefundInp) (res *p
 		return
 	}
 
+	if in.RefundMoney <= 0 || in.RefundMoney > models.PayAmount {
+		err = gerror.Newf("退款金额需大于0且不超过支付金额%v", models.PayAmount)
+		return
+	}
+
 	var traceIds []string
 	if err = models.TraceIds.Scan(&traceIds); err != nil {
 		return
@@ -99,47 +105,72 @@ func (s *sPayRefund) Refund(ctx context.Context, in *payin.PayRefundInp) (res *p
 	models.IsRefund = consts.RefundStatusAgree
 	models.TraceIds = gjson.New(traceIds)
 
-	result, err := s.Model(ctx).
-		Fields(
-			dao.PayLog.Columns().RefundSn,
-			dao.PayLog.Columns().IsRefund,
-			dao.PayLog.Columns().TraceIds,
-		).
-		Where(dao.PayLog.Columns().Id, models.Id).
-		OmitEmpty().
-		Data(models).Update()
-	if err != nil {
-		return
-	}
-
-	ret, err := result.RowsAffected()
+	var order *toogoin.OrderRefundModel
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		result, err := s.Model(ctx).
+			Fields(
+				dao.PayLog.Columns().RefundSn,
+				dao.PayLog.Columns().IsRefund,
+				dao.PayLog.Columns().TraceIds,
+			).
+			Where(dao.PayLog.Columns().Id, models.Id).
+			OmitEmpty().
+			Data(models).Update()
+		if err != nil {
+			return err
+		}
+
+		ret, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if ret == 0 {
+			g.Log().Warningf(ctx, "Refund 没有被更新的数据行")
+		}
+
+		data := &entity.PayRefund{
+			Id:            0,
+			MemberId:      models.MemberId,
+			AppId:         models.AppId,
+			OrderSn:       models.OrderSn,
+			RefundTradeNo: "",
+			RefundMoney:   in.RefundMoney,
+			RefundWay:     1,
+			Ip:            location.GetClientIp(ghttp.RequestFromCtx(ctx)),
+			Reason:        in.Reason,
+			Remark:        in.Remark,
+			Status:        consts.RefundStatusAgree,
+		}
+
+		// 创建退款记录
+		if _, err = s.Model(ctx).Data(data).OmitEmptyData().Insert(); err != nil {
+			return err
+		}
+
+		// 按退款比例冲正该订单产生的代理佣金，并取消或缩短订单对应的订阅
+		order, err = service.ToogoSubscription().RefundOrder(ctx, &toogoin.OrderRefundInp{
+			OrderSn: models.OrderSn,
+			Ratio:   refundRatio(in.RefundMoney, models.PayAmount),
+			Reason:  in.Reason,
+		})
+		return err
+	})
 	if err != nil {
+		g.Log().Errorf(ctx, "Refund 第三方退款已成功但本地退款处理失败，需人工核对, orderSn:%v, refundSn:%v, err:%+v", models.OrderSn, refundSn, err)
 		return
 	}
 
-	if ret == 0 {
-		g.Log().Warningf(ctx, "Refund 没有被更新的数据行")
-	}
-
-	data := &entity.PayRefund{
-		Id:            0,
-		MemberId:      models.MemberId,
-		AppId:         models.AppId,
-		OrderSn:       models.OrderSn,
-		RefundTradeNo: "",
-		RefundMoney:   in.RefundMoney,
-		RefundWay:     1,
-		Ip:            location.GetClientIp(ghttp.RequestFromCtx(ctx)),
-		Reason:        in.Reason,
-		Remark:        in.Remark,
-		Status:        consts.RefundStatusAgree,
-	}
+	service.ToogoSubscription().AfterRefundOrder(ctx, order)
+	return
+}
 
-	// 创建退款记录
-	if _, err = s.Model(ctx).Data(data).OmitEmptyData().Insert(); err != nil {
-		return
+// refundRatio 退款金额占支付金额的比例
+func refundRatio(refundMoney, payAmount float64) float64 {
+	if payAmount <= 0 || refundMoney >= payAmount {
+		return 1
 	}
-	return
+	return refundMoney / payAmount
 }
 
 // List 获取交易退款列表
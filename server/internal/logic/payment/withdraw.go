@@ -9,9 +9,11 @@ package payment
 import (
 	"context"
 	"fmt"
+	"hotgo/internal/consts"
 	"hotgo/internal/dao"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input"
+	"hotgo/internal/service"
 	"hotgo/utility/simple"
 
 	"github.com/gogf/gf/v2/database/gdb"
@@ -32,6 +34,12 @@ func (s *sWithdraw) Apply(ctx context.Context, in *input.WithdrawApplyInp) (orde
 		return "", gerror.New("用户未登录")
 	}
 
+	// 敏感操作二次验证
+	if err = service.SysTwoFactor().RequireVerified(ctx, user.Id, consts.SensitiveOpWithdraw,
+		fmt.Sprintf("申请USDT提现: amount=%.4f, network=%s, to=%s", in.Amount, in.Network, in.ToAddress)); err != nil {
+		return "", err
+	}
+
 	// 检查余额是否足够
 	var balance *entity.UsdtBalance
 	err = dao.UsdtBalance.Ctx(ctx).Where("user_id", user.Id).Scan(&balance)
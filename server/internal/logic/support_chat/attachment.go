@@ -0,0 +1,97 @@
+package support_chat
+
+import (
+	"context"
+	"strings"
+
+	"hotgo/internal/consts"
+	"hotgo/internal/library/storager"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/adminin"
+	"hotgo/utility/validate"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+const (
+	defaultAttachmentMaxSize  = 10 // MB
+	defaultAttachmentAllowExt = "jpg,jpeg,png,gif,webp,bmp,pdf,txt,log,csv,zip"
+)
+
+// SendAgentAttachment 客服发送附件
+func (s *sSupportChat) SendAgentAttachment(ctx context.Context, in *adminin.SupportSendAttachmentInp) (msg *entity.SupportMessage, err error) {
+	u, err := s.mustUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.sendAttachment(ctx, in.SessionId, consts.SupportSenderRoleAgent, u.Id, in.File)
+}
+
+// SendUserAttachment 用户发送附件（如交易所报错截图）
+func (s *sSupportChat) SendUserAttachment(ctx context.Context, sessionId int64, file *ghttp.UploadFile) (msg *entity.SupportMessage, err error) {
+	u, err := s.mustUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.sendAttachment(ctx, sessionId, consts.SupportSenderRoleUser, u.Id, file)
+}
+
+// sendAttachment 先校验会话与文件，再上传到存储驱动，最后作为图片/文件消息投递
+func (s *sSupportChat) sendAttachment(ctx context.Context, sessionId int64, senderRole int, senderId int64, file *ghttp.UploadFile) (msg *entity.SupportMessage, err error) {
+	if file == nil {
+		return nil, gerror.New("没有找到上传的文件")
+	}
+
+	session, err := s.writableSession(ctx, sessionId, senderRole, senderId)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := storager.GetFileMeta(file)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateAttachment(ctx, meta); err != nil {
+		return nil, err
+	}
+
+	attachment, err := storager.DoUpload(ctx, meta.Kind, file)
+	if err != nil {
+		return nil, err
+	}
+
+	msgType := consts.SupportMsgTypeFile
+	if meta.Kind == storager.KindImg {
+		msgType = consts.SupportMsgTypeImage
+	}
+	msg = &entity.SupportMessage{
+		SessionId:    sessionId,
+		SenderRole:   senderRole,
+		SenderId:     senderId,
+		MsgType:      msgType,
+		Content:      storager.LastUrl(ctx, attachment.FileUrl, attachment.Drive),
+		AttachmentId: attachment.Id,
+		FileName:     meta.Filename,
+		FileSize:     meta.Size,
+	}
+	if err = s.deliverMessage(ctx, session, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// validateAttachment 客服聊天附件的类型/大小限制，在全局上传配置之外单独收紧
+func validateAttachment(ctx context.Context, meta *storager.FileMeta) error {
+	maxSize := g.Cfg().MustGet(ctx, "supportChat.attachment.maxSize", defaultAttachmentMaxSize).Int64()
+	if maxSize > 0 && meta.Size > maxSize*1024*1024 {
+		return gerror.Newf("附件大小不能超过%vMB", maxSize)
+	}
+
+	allowExt := g.Cfg().MustGet(ctx, "supportChat.attachment.allowExt", defaultAttachmentAllowExt).String()
+	if allowExt != "" && !validate.InSlice(strings.Split(allowExt, ","), meta.Ext) {
+		return gerror.Newf("不支持的附件类型:%v", meta.Ext)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package support_chat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/adminin"
+	"hotgo/internal/service"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+// supportActions 工作台操作，按顺序下发
+var supportActions = []string{
+	consts.SupportActionRobotRestart,
+	consts.SupportActionRobotSyncOrders,
+}
+
+// agentSession 当前客服接线中的会话
+func (s *sSupportChat) agentSession(ctx context.Context, sessionId int64) (session *entity.SupportSession, agentId int64, err error) {
+	u, err := s.mustUser(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	session = new(entity.SupportSession)
+	if err = dao.SupportSession.Ctx(ctx).Where(dao.SupportSession.Columns().Id, sessionId).Scan(session); err != nil {
+		return nil, 0, err
+	}
+	if session.Id == 0 || session.AgentId != u.Id {
+		return nil, 0, gerror.New("会话不存在或无权限")
+	}
+	return session, u.Id, nil
+}
+
+// SessionContext 会话用户上下文，仅当前接线客服可查看
+func (s *sSupportChat) SessionContext(ctx context.Context, in *adminin.SupportContextInp) (res *adminin.SupportContextModel, err error) {
+	session, _, err := s.agentSession(ctx, in.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := service.ToogoRobot().SupportContext(ctx, session.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &adminin.SupportContextModel{
+		SessionId:           session.Id,
+		SupportContextModel: data,
+		Actions:             make([]string, 0, len(supportActions)),
+	}
+	for _, action := range supportActions {
+		if service.AdminRole().Verify(ctx, action, http.MethodPost) {
+			res.Actions = append(res.Actions, action)
+		}
+	}
+	return
+}
+
+// RestartRobot 重建会话用户的机器人引擎
+func (s *sSupportChat) RestartRobot(ctx context.Context, in *adminin.SupportRobotActionInp) (err error) {
+	return s.robotAction(ctx, in, "重建机器人引擎", service.ToogoRobot().RestartRobotEngine)
+}
+
+// SyncRobotOrders 重新同步会话用户的机器人订单/持仓
+func (s *sSupportChat) SyncRobotOrders(ctx context.Context, in *adminin.SupportRobotActionInp) (err error) {
+	return s.robotAction(ctx, in, "重新同步订单", service.ToogoRobot().TriggerOrderSync)
+}
+
+// robotAction 校验会话归属与机器人归属后执行操作，无论成败都写入敏感操作审计
+// 接口权限由后台路由鉴权控制，见 consts.SupportAction*
+func (s *sSupportChat) robotAction(ctx context.Context, in *adminin.SupportRobotActionInp, name string, action func(ctx context.Context, robotId int64) error) (err error) {
+	session, agentId, err := s.agentSession(ctx, in.SessionId)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := dao.TradingRobot.Ctx(ctx).
+		Where(dao.TradingRobot.Columns().Id, in.RobotId).
+		Where(dao.TradingRobot.Columns().UserId, session.UserId).
+		Count()
+	if err != nil {
+		return err
+	}
+	if cnt == 0 {
+		return gerror.New("机器人不存在或不属于该会话用户")
+	}
+
+	err = action(ctx, in.RobotId)
+
+	result := "success"
+	if err != nil {
+		result = "failed: " + err.Error()
+	}
+	details := fmt.Sprintf("%s session=%d user=%d robot=%d result=%s", name, session.Id, session.UserId, in.RobotId, result)
+	if remark := strings.TrimSpace(in.Remark); remark != "" {
+		details += " remark=" + remark
+	}
+	_ = service.SysSecurity().LogSensitiveOperation(ctx, agentId, consts.SensitiveOpSupportAction, details)
+	return
+}
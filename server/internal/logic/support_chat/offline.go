@@ -0,0 +1,127 @@
+package support_chat
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/sysin"
+	"hotgo/internal/service"
+	"hotgo/internal/websocket"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+const (
+	defaultOfflineNotifyDelay = 300 // 秒
+	offlineNotifyBatch        = 500
+	offlineNotifyMaxAge       = 24 * time.Hour
+)
+
+// NotifyOffline 离线送达：客服回复超过延迟仍未读、且用户没有在线连接时，汇总后按会话发送邮件。
+// 站内提醒(admin_notice)在发送消息时已写入，用户重新上线即可看到，这里只补发邮件。
+func (s *sSupportChat) NotifyOffline(ctx context.Context) (notified int, err error) {
+	if !g.Cfg().MustGet(ctx, "supportChat.offlineNotify.enabled", true).Bool() {
+		return 0, nil
+	}
+	delay := g.Cfg().MustGet(ctx, "supportChat.offlineNotify.delay", defaultOfflineNotifyDelay).Int64()
+
+	var (
+		cols   = dao.SupportMessage.Columns()
+		now    = gtime.Now()
+		lastId int64
+	)
+	// 按消息ID游标分页：在线用户的消息不标记，游标越过它们继续扫描，避免其占满批次而饿死离线用户
+	for {
+		var msgs []*entity.SupportMessage
+		if err = dao.SupportMessage.Ctx(ctx).
+			Where(cols.SenderRole, consts.SupportSenderRoleAgent).
+			Where(cols.OfflineNotified, 0).
+			WhereNull(cols.ReadAt).
+			WhereGT(cols.Id, lastId).
+			WhereLTE(cols.CreatedAt, now.Add(-time.Duration(delay)*time.Second)).
+			WhereGTE(cols.CreatedAt, now.Add(-offlineNotifyMaxAge)).
+			Order(cols.Id + " asc").
+			Limit(offlineNotifyBatch).
+			Scan(&msgs); err != nil || len(msgs) == 0 {
+			return
+		}
+		lastId = msgs[len(msgs)-1].Id
+
+		n, err := s.notifyOfflineBatch(ctx, msgs)
+		notified += n
+		if err != nil || len(msgs) < offlineNotifyBatch {
+			return notified, err
+		}
+	}
+}
+
+// notifyOfflineBatch 按会话汇总一批未读回复，跳过仍在线的用户，返回已通知的会话数
+func (s *sSupportChat) notifyOfflineBatch(ctx context.Context, msgs []*entity.SupportMessage) (notified int, err error) {
+	var (
+		cols       = dao.SupportMessage.Columns()
+		sessionIds []int64
+		bySession  = make(map[int64][]*entity.SupportMessage)
+	)
+	for _, msg := range msgs {
+		if _, ok := bySession[msg.SessionId]; !ok {
+			sessionIds = append(sessionIds, msg.SessionId)
+		}
+		bySession[msg.SessionId] = append(bySession[msg.SessionId], msg)
+	}
+
+	var sessions []*entity.SupportSession
+	if err = dao.SupportSession.Ctx(ctx).WhereIn(dao.SupportSession.Columns().Id, sessionIds).Scan(&sessions); err != nil {
+		return
+	}
+
+	for _, session := range sessions {
+		// 用户仍有连接（含集群其他节点）时消息已实时推送，等待其阅读，不打扰
+		if websocket.IsUserOnline(ctx, session.UserId) {
+			continue
+		}
+
+		list := bySession[session.Id]
+		if err := s.emailOfflineMessages(ctx, session, list); err != nil {
+			g.Log().Warningf(ctx, "support NotifyOffline sessionId:%v userId:%v err:%+v", session.Id, session.UserId, err)
+		}
+
+		// 无论邮件是否成功都标记，避免邮件配置异常时每轮重复尝试
+		ids := make([]int64, 0, len(list))
+		for _, msg := range list {
+			ids = append(ids, msg.Id)
+		}
+		if _, err = dao.SupportMessage.Ctx(ctx).WhereIn(cols.Id, ids).Data(cols.OfflineNotified, 1).Update(); err != nil {
+			return
+		}
+		notified++
+	}
+	return
+}
+
+// emailOfflineMessages 将未读回复汇总为一封邮件，用户未绑定邮箱时跳过
+func (s *sSupportChat) emailOfflineMessages(ctx context.Context, session *entity.SupportSession, list []*entity.SupportMessage) error {
+	email, err := dao.AdminMember.Ctx(ctx).Where(dao.AdminMember.Columns().Id, session.UserId).Value(dao.AdminMember.Columns().Email)
+	if err != nil || email.IsEmpty() {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("<p>您的客服会话 #%d 有 %d 条未读回复：</p>", session.Id, len(list)))
+	for _, msg := range list {
+		b.WriteString(fmt.Sprintf("<p>[%s] %s</p>", msg.CreatedAt.Format("Y-m-d H:i"), html.EscapeString(messagePreview(msg))))
+	}
+	b.WriteString("<p>请登录后在客服中心查看完整内容。</p>")
+
+	return service.SysEmsLog().Send(ctx, &sysin.SendEmsInp{
+		Event:   consts.EmsTemplateText,
+		Email:   email.String(),
+		Content: b.String(),
+	})
+}
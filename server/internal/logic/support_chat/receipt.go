@@ -0,0 +1,135 @@
+package support_chat
+
+import (
+	"context"
+
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/websocket"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// participantRole 会话参与方角色，非参与方返回错误
+func (s *sSupportChat) participantRole(ctx context.Context, memberId, sessionId int64) (session *entity.SupportSession, role int, err error) {
+	session = new(entity.SupportSession)
+	if err = dao.SupportSession.Ctx(ctx).Where(dao.SupportSession.Columns().Id, sessionId).Scan(session); err != nil {
+		return nil, 0, err
+	}
+	switch {
+	case session.Id == 0 || memberId <= 0:
+		err = gerror.New("会话不存在或无权限")
+	case session.UserId == memberId:
+		role = consts.SupportSenderRoleUser
+	case session.AgentId == memberId:
+		role = consts.SupportSenderRoleAgent
+	default:
+		err = gerror.New("会话不存在或无权限")
+	}
+	return
+}
+
+// counterpartId 会话中另一方的ID，未接线时客服方为0
+func counterpartId(session *entity.SupportSession, role int) int64 {
+	if role == consts.SupportSenderRoleUser {
+		return session.AgentId
+	}
+	return session.UserId
+}
+
+// MarkRead 标记会话内对方发来的消息为已读，并向对方推送已读回执
+func (s *sSupportChat) MarkRead(ctx context.Context, memberId, sessionId int64) (err error) {
+	session, role, err := s.participantRole(ctx, memberId, sessionId)
+	if err != nil {
+		return err
+	}
+	s.markRead(ctx, session, role)
+	return nil
+}
+
+// markRead 已读处理：写入 read_at、清空己方未读数；有新读消息时回执给对方
+func (s *sSupportChat) markRead(ctx context.Context, session *entity.SupportSession, readerRole int) {
+	var (
+		cols = dao.SupportMessage.Columns()
+		now  = gtime.Now()
+	)
+
+	var lastReadId int64
+	if v, err := dao.SupportMessage.Ctx(ctx).
+		Where(cols.SessionId, session.Id).
+		WhereNot(cols.SenderRole, readerRole).
+		WhereNull(cols.ReadAt).
+		Max(cols.Id); err == nil {
+		lastReadId = int64(v)
+	}
+	if lastReadId > 0 {
+		if _, err := dao.SupportMessage.Ctx(ctx).
+			Where(cols.SessionId, session.Id).
+			WhereNot(cols.SenderRole, readerRole).
+			WhereNull(cols.ReadAt).
+			WhereLTE(cols.Id, lastReadId).
+			Data(g.Map{cols.ReadAt: now}).
+			Update(); err != nil {
+			g.Log().Warningf(ctx, "support markRead sessionId:%v err:%+v", session.Id, err)
+			return
+		}
+	}
+
+	unreadCol, unread := dao.SupportSession.Columns().UnreadUser, session.UnreadUser
+	if readerRole == consts.SupportSenderRoleAgent {
+		unreadCol, unread = dao.SupportSession.Columns().UnreadAgent, session.UnreadAgent
+	}
+	if unread > 0 {
+		_, _ = dao.SupportSession.Ctx(ctx).
+			Where(dao.SupportSession.Columns().Id, session.Id).
+			Data(g.Map{
+				unreadCol:                              0,
+				dao.SupportSession.Columns().UpdatedAt: now,
+			}).
+			Update()
+		if readerRole == consts.SupportSenderRoleAgent {
+			session.UnreadAgent = 0
+		} else {
+			session.UnreadUser = 0
+		}
+		session.UpdatedAt = now
+		s.pushSessionUpdated(ctx, session)
+	}
+
+	if receiverId := counterpartId(session, readerRole); lastReadId > 0 && receiverId > 0 {
+		websocket.SendToUser(receiverId, &websocket.WResponse{
+			Event: consts.SupportWsEventRead,
+			Data: g.Map{
+				"sessionId":  session.Id,
+				"readerRole": readerRole,
+				"lastReadId": lastReadId,
+				"readAt":     now,
+			},
+		})
+	}
+}
+
+// Typing 向对方推送“正在输入”状态，仅转发不落库
+func (s *sSupportChat) Typing(ctx context.Context, memberId, sessionId int64, typing bool) (err error) {
+	session, role, err := s.participantRole(ctx, memberId, sessionId)
+	if err != nil {
+		return err
+	}
+	if session.Status == consts.SupportSessionStatusClosed {
+		return gerror.New("会话已关闭")
+	}
+	if receiverId := counterpartId(session, role); receiverId > 0 {
+		websocket.SendToUser(receiverId, &websocket.WResponse{
+			Event: consts.SupportWsEventTyping,
+			Data: g.Map{
+				"sessionId":  session.Id,
+				"senderRole": role,
+				"typing":     typing,
+			},
+		})
+	}
+	return nil
+}
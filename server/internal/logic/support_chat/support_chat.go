@@ -207,20 +207,9 @@ func (s *sSupportChat) MessageList(ctx context.Context, in *adminin.SupportMessa
 		return nil, 0, gerror.New("会话未接线")
 	}
 
-	// 已读：客服查看消息列表时清空 unread_agent
-	if session.AgentId == u.Id && session.UnreadAgent > 0 {
-		now := gtime.Now()
-		_, _ = dao.SupportSession.Ctx(ctx).
-			Where(dao.SupportSession.Columns().Id, session.Id).
-			Where(dao.SupportSession.Columns().AgentId, u.Id).
-			Data(g.Map{
-				dao.SupportSession.Columns().UnreadAgent: 0,
-				dao.SupportSession.Columns().UpdatedAt:   now,
-			}).
-			Update()
-		session.UnreadAgent = 0
-		session.UpdatedAt = now
-		s.pushSessionUpdated(ctx, &session)
+	// 已读：客服查看消息列表时清空 unread_agent，并回执给用户
+	if session.AgentId == u.Id {
+		s.markRead(ctx, &session, consts.SupportSenderRoleAgent)
 	}
 
 	mod := dao.SupportMessage.Ctx(ctx).Where(dao.SupportMessage.Columns().SessionId, in.SessionId)
@@ -400,7 +389,7 @@ func (s *sSupportChat) StartUserSession(ctx context.Context) (session *entity.Su
 	session.UpdatedAt = now
 
 	// 通知在线客服：有新排队会话（不包含消息内容，避免泄露给未接线客服）
-	websocket.SendToTag(consts.SupportWsTagAgents, &websocket.WResponse{
+	websocket.SendToChannel(consts.SupportWsTagAgents, &websocket.WResponse{
 		Event: consts.SupportWsEventSessionUpdated,
 		Data: g.Map{
 			"id":     session.Id,
@@ -433,21 +422,8 @@ func (s *sSupportChat) UserMessageList(ctx context.Context, sessionId int64, pag
 		return nil, 0, gerror.New("会话不存在或无权限")
 	}
 
-	// 已读：用户查看消息列表时清空 unread_user
-	if session.UnreadUser > 0 {
-		now := gtime.Now()
-		_, _ = dao.SupportSession.Ctx(ctx).
-			Where(dao.SupportSession.Columns().Id, session.Id).
-			Where(dao.SupportSession.Columns().UserId, u.Id).
-			Data(g.Map{
-				dao.SupportSession.Columns().UnreadUser: 0,
-				dao.SupportSession.Columns().UpdatedAt:  now,
-			}).
-			Update()
-		session.UnreadUser = 0
-		session.UpdatedAt = now
-		s.pushSessionUpdated(ctx, &session)
-	}
+	// 已读：用户查看消息列表时清空 unread_user，并回执给客服
+	s.markRead(ctx, &session, consts.SupportSenderRoleUser)
 
 	if perPage <= 0 {
 		perPage = 20
@@ -470,8 +446,27 @@ func (s *sSupportChat) sendMessage(ctx context.Context, sessionId int64, senderR
 		return nil, gerror.New("消息内容过长")
 	}
 
-	var session entity.SupportSession
-	if err = dao.SupportSession.Ctx(ctx).Where(dao.SupportSession.Columns().Id, sessionId).Scan(&session); err != nil {
+	session, err := s.writableSession(ctx, sessionId, senderRole, senderId)
+	if err != nil {
+		return nil, err
+	}
+	msg = &entity.SupportMessage{
+		SessionId:  sessionId,
+		SenderRole: senderRole,
+		SenderId:   senderId,
+		MsgType:    consts.SupportMsgTypeText,
+		Content:    content,
+	}
+	if err = s.deliverMessage(ctx, session, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// writableSession 获取允许发送方发消息的会话
+func (s *sSupportChat) writableSession(ctx context.Context, sessionId int64, senderRole int, senderId int64) (session *entity.SupportSession, err error) {
+	session = new(entity.SupportSession)
+	if err = dao.SupportSession.Ctx(ctx).Where(dao.SupportSession.Columns().Id, sessionId).Scan(session); err != nil {
 		return nil, err
 	}
 	if session.Id == 0 {
@@ -490,22 +485,26 @@ func (s *sSupportChat) sendMessage(ctx context.Context, sessionId int64, senderR
 			return nil, gerror.New("会话未分配给当前客服")
 		}
 	}
+	return
+}
 
+// deliverMessage 写入消息，更新会话摘要/未读数，并推送给双方
+func (s *sSupportChat) deliverMessage(ctx context.Context, session *entity.SupportSession, msg *entity.SupportMessage) (err error) {
 	now := gtime.Now()
-	msg = &entity.SupportMessage{
-		SessionId:  sessionId,
-		SenderRole: senderRole,
-		SenderId:   senderId,
-		MsgType:    1,
-		Content:    content,
-		CreatedAt:  now,
-	}
+	msg.CreatedAt = now
 	id, err := dao.SupportMessage.Ctx(ctx).Data(msg).OmitEmptyData().InsertAndGetId()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	msg.Id = id
 
+	var (
+		sessionId  = session.Id
+		senderRole = msg.SenderRole
+		senderId   = msg.SenderId
+		content    = messagePreview(msg)
+	)
+
 	// 写入“消息提醒中心”(admin_notice)：客服聊天
 	// - 用户发给客服：提醒客服
 	// - 客服发给用户：提醒用户
@@ -567,7 +566,7 @@ func (s *sSupportChat) sendMessage(ctx context.Context, sessionId int64, senderR
 		})
 	} else if senderRole == consts.SupportSenderRoleUser {
 		// 未接线：只通知“有待接会话更新”，不广播消息内容/预览
-		websocket.SendToTag(consts.SupportWsTagAgents, &websocket.WResponse{
+		websocket.SendToChannel(consts.SupportWsTagAgents, &websocket.WResponse{
 			Event: consts.SupportWsEventSessionUpdated,
 			Data: g.Map{
 				"id":     session.Id,
@@ -577,7 +576,19 @@ func (s *sSupportChat) sendMessage(ctx context.Context, sessionId int64, senderR
 			},
 		})
 	}
-	return msg, nil
+	return nil
+}
+
+// messagePreview 消息预览（会话摘要/提醒）
+func messagePreview(msg *entity.SupportMessage) string {
+	switch msg.MsgType {
+	case consts.SupportMsgTypeImage:
+		return "[图片]"
+	case consts.SupportMsgTypeFile:
+		return "[文件] " + msg.FileName
+	default:
+		return msg.Content
+	}
 }
 
 func (s *sSupportChat) pushSessionUpdated(ctx context.Context, session *entity.SupportSession) {
@@ -595,7 +606,7 @@ func (s *sSupportChat) pushSessionUpdated(ctx context.Context, session *entity.S
 		})
 	}
 	// 对在线客服仅推“会话状态/分配”信息，避免把客户消息预览泄露给未接线客服
-	websocket.SendToTag(consts.SupportWsTagAgents, &websocket.WResponse{
+	websocket.SendToChannel(consts.SupportWsTagAgents, &websocket.WResponse{
 		Event: consts.SupportWsEventSessionUpdated,
 		Data: g.Map{
 			"id":     session.Id,
@@ -0,0 +1,176 @@
+package support_chat
+
+import (
+	"context"
+	"strings"
+
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/adminin"
+	"hotgo/internal/model/input/apiin"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// RateSession 用户评价会话，每个会话仅允许评价一次
+func (s *sSupportChat) RateSession(ctx context.Context, in *apiin.SupportRateInp) (session *entity.SupportSession, err error) {
+	u, err := s.mustUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.Rating < 1 || in.Rating > 5 {
+		return nil, gerror.New("评分范围为1-5")
+	}
+
+	session = new(entity.SupportSession)
+	if err = dao.SupportSession.Ctx(ctx).Where(dao.SupportSession.Columns().Id, in.SessionId).Scan(session); err != nil {
+		return nil, err
+	}
+	if session.Id == 0 || session.UserId != u.Id {
+		return nil, gerror.New("会话不存在或无权限")
+	}
+	if session.AgentId == 0 {
+		return nil, gerror.New("会话未接线，暂不能评价")
+	}
+
+	now := gtime.Now()
+	r, err := dao.SupportSession.Ctx(ctx).
+		Where(dao.SupportSession.Columns().Id, session.Id).
+		Where(dao.SupportSession.Columns().UserId, u.Id).
+		Where(dao.SupportSession.Columns().Rating, 0).
+		Data(g.Map{
+			dao.SupportSession.Columns().Rating:       in.Rating,
+			dao.SupportSession.Columns().RatingRemark: strings.TrimSpace(in.Remark),
+			dao.SupportSession.Columns().RatedAt:      now,
+			dao.SupportSession.Columns().UpdatedAt:    now,
+		}).
+		Update()
+	if err != nil {
+		return nil, err
+	}
+	affected, _ := r.RowsAffected()
+	if affected == 0 {
+		return nil, gerror.New("该会话已评价")
+	}
+
+	session.Rating = in.Rating
+	session.RatingRemark = strings.TrimSpace(in.Remark)
+	session.RatedAt = now
+	session.UpdatedAt = now
+	s.pushSessionUpdated(ctx, session)
+	return
+}
+
+// Escalate 客服将会话升级为工单，关联用户的机器人/订单，便于查看相关执行日志
+func (s *sSupportChat) Escalate(ctx context.Context, in *adminin.SupportEscalateInp) (session *entity.SupportSession, err error) {
+	u, err := s.mustUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.RobotId <= 0 && in.OrderId <= 0 {
+		return nil, gerror.New("请至少关联一个机器人或订单")
+	}
+
+	session = new(entity.SupportSession)
+	if err = dao.SupportSession.Ctx(ctx).Where(dao.SupportSession.Columns().Id, in.SessionId).Scan(session); err != nil {
+		return nil, err
+	}
+	if session.Id == 0 || session.AgentId != u.Id {
+		return nil, gerror.New("会话不存在或无权限")
+	}
+
+	robotId := in.RobotId
+	if in.OrderId > 0 {
+		var order *entity.TradingOrder
+		if err = dao.TradingOrder.Ctx(ctx).
+			Fields(dao.TradingOrder.Columns().Id, dao.TradingOrder.Columns().UserId, dao.TradingOrder.Columns().RobotId).
+			Where(dao.TradingOrder.Columns().Id, in.OrderId).
+			Scan(&order); err != nil {
+			return nil, err
+		}
+		if order == nil || order.UserId != session.UserId {
+			return nil, gerror.New("订单不存在或不属于该会话用户")
+		}
+		if robotId == 0 {
+			robotId = order.RobotId
+		} else if order.RobotId != robotId {
+			return nil, gerror.New("订单不属于所选机器人")
+		}
+	}
+	if robotId > 0 {
+		cnt, err := dao.TradingRobot.Ctx(ctx).
+			Where(dao.TradingRobot.Columns().Id, robotId).
+			Where(dao.TradingRobot.Columns().UserId, session.UserId).
+			Count()
+		if err != nil {
+			return nil, err
+		}
+		if cnt == 0 {
+			return nil, gerror.New("机器人不存在或不属于该会话用户")
+		}
+	}
+
+	now := gtime.Now()
+	if _, err = dao.SupportSession.Ctx(ctx).
+		Where(dao.SupportSession.Columns().Id, session.Id).
+		Where(dao.SupportSession.Columns().AgentId, u.Id).
+		Data(g.Map{
+			dao.SupportSession.Columns().RobotId:        robotId,
+			dao.SupportSession.Columns().OrderId:        in.OrderId,
+			dao.SupportSession.Columns().EscalateRemark: strings.TrimSpace(in.Remark),
+			dao.SupportSession.Columns().EscalatedBy:    u.Id,
+			dao.SupportSession.Columns().EscalatedAt:    now,
+			dao.SupportSession.Columns().UpdatedAt:      now,
+		}).
+		Update(); err != nil {
+		return nil, err
+	}
+
+	session.RobotId = robotId
+	session.OrderId = in.OrderId
+	session.EscalateRemark = strings.TrimSpace(in.Remark)
+	session.EscalatedBy = u.Id
+	session.EscalatedAt = now
+	session.UpdatedAt = now
+	s.pushSessionUpdated(ctx, session)
+	return
+}
+
+// ExecutionLogList 工单关联的机器人/订单执行日志
+func (s *sSupportChat) ExecutionLogList(ctx context.Context, in *adminin.SupportExecutionLogListInp) (list []*entity.TradingExecutionLog, totalCount int, err error) {
+	u, err := s.mustUser(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var session entity.SupportSession
+	if err = dao.SupportSession.Ctx(ctx).Where(dao.SupportSession.Columns().Id, in.SessionId).Scan(&session); err != nil {
+		return
+	}
+	if session.Id == 0 || session.AgentId != u.Id {
+		return nil, 0, gerror.New("会话不存在或无权限")
+	}
+	if session.EscalatedAt == nil {
+		return nil, 0, gerror.New("会话尚未升级为工单")
+	}
+
+	cols := dao.TradingExecutionLog.Columns()
+	mod := dao.TradingExecutionLog.Ctx(ctx)
+	if session.OrderId > 0 {
+		mod = mod.Where(cols.OrderId, session.OrderId)
+	} else {
+		mod = mod.Where(cols.RobotId, session.RobotId)
+	}
+	if in.Status != "" {
+		mod = mod.Where(cols.Status, in.Status)
+	}
+
+	totalCount, err = mod.Count()
+	if err != nil || totalCount == 0 {
+		return
+	}
+	err = mod.Page(in.Page, in.PerPage).Order(cols.Id + " desc").Scan(&list)
+	return
+}
@@ -0,0 +1,132 @@
+// Package sys
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package sys
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/queue"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/sysin"
+	"hotgo/internal/service"
+)
+
+type sSysQueueDeadLetter struct{}
+
+func NewSysQueueDeadLetter() *sSysQueueDeadLetter {
+	return &sSysQueueDeadLetter{}
+}
+
+func init() {
+	service.RegisterSysQueueDeadLetter(NewSysQueueDeadLetter())
+}
+
+// List 获取死信列表
+func (s *sSysQueueDeadLetter) List(ctx context.Context, in *sysin.QueueDeadLetterListInp) (list []*sysin.QueueDeadLetterListModel, totalCount int, err error) {
+	mod := dao.SysQueueDeadLetter.Ctx(ctx)
+	cols := dao.SysQueueDeadLetter.Columns()
+
+	if in.Topic != "" {
+		mod = mod.Where(cols.Topic, in.Topic)
+	}
+
+	if in.Status > 0 {
+		mod = mod.Where(cols.Status, in.Status)
+	}
+
+	if len(in.CreatedAt) == 2 {
+		mod = mod.WhereBetween(cols.CreatedAt, in.CreatedAt[0], in.CreatedAt[1])
+	}
+
+	totalCount, err = mod.Count()
+	if err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+		return
+	}
+
+	if totalCount == 0 {
+		return
+	}
+
+	if err = mod.Page(in.Page, in.PerPage).OrderDesc(cols.Id).Scan(&list); err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+		return
+	}
+	return
+}
+
+// View 获取死信信息
+func (s *sSysQueueDeadLetter) View(ctx context.Context, in *sysin.QueueDeadLetterViewInp) (res *sysin.QueueDeadLetterViewModel, err error) {
+	if err = dao.SysQueueDeadLetter.Ctx(ctx).Where(dao.SysQueueDeadLetter.Columns().Id, in.Id).Scan(&res); err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+		return
+	}
+	if res == nil {
+		err = gerror.New("死信不存在")
+	}
+	return
+}
+
+// Replay 将死信重新投递到原主题
+// 重放后消息按正常流程消费，再次失败会生成新的死信记录。
+func (s *sSysQueueDeadLetter) Replay(ctx context.Context, in *sysin.QueueDeadLetterReplayInp) (res *sysin.QueueDeadLetterReplayModel, err error) {
+	var list []*entity.SysQueueDeadLetter
+	cols := dao.SysQueueDeadLetter.Columns()
+	if err = dao.SysQueueDeadLetter.Ctx(ctx).WhereIn(cols.Id, in.Id).Scan(&list); err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+		return
+	}
+	if len(list) == 0 {
+		err = gerror.New("死信不存在")
+		return
+	}
+
+	res = new(sysin.QueueDeadLetterReplayModel)
+	for _, item := range list {
+		if err = queue.Push(item.Topic, item.Body); err != nil {
+			err = gerror.Wrapf(err, "重放死信失败，ID:%v，已成功重放%v条", item.Id, res.Replayed)
+			return
+		}
+
+		if _, err = dao.SysQueueDeadLetter.Ctx(ctx).Where(cols.Id, item.Id).Data(g.Map{
+			cols.Status:      queue.DeadLetterStatusReplayed,
+			cols.ReplayCount: gdb.Raw(cols.ReplayCount + "+1"),
+			cols.ReplayedAt:  gtime.Now(),
+			cols.UpdatedAt:   gtime.Now(),
+		}).Update(); err != nil {
+			err = gerror.Wrap(err, consts.ErrorORM)
+			return
+		}
+		res.Replayed++
+	}
+	return
+}
+
+// Ignore 忽略死信
+func (s *sSysQueueDeadLetter) Ignore(ctx context.Context, in *sysin.QueueDeadLetterIgnoreInp) (err error) {
+	cols := dao.SysQueueDeadLetter.Columns()
+	if _, err = dao.SysQueueDeadLetter.Ctx(ctx).
+		WhereIn(cols.Id, in.Id).
+		Where(cols.Status, queue.DeadLetterStatusPending).
+		Data(g.Map{
+			cols.Status:    queue.DeadLetterStatusIgnored,
+			cols.UpdatedAt: gtime.Now(),
+		}).Update(); err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+	}
+	return
+}
+
+// Delete 删除死信
+func (s *sSysQueueDeadLetter) Delete(ctx context.Context, in *sysin.QueueDeadLetterDeleteInp) (err error) {
+	_, err = dao.SysQueueDeadLetter.Ctx(ctx).Where(dao.SysQueueDeadLetter.Columns().Id, in.Id).Delete()
+	return
+}
@@ -12,8 +12,12 @@ import (
 
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/gcache"
+	"github.com/gogf/gf/v2/os/gtime"
 	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/location"
 	"hotgo/internal/service"
 	"hotgo/utility/encrypt"
 )
@@ -97,7 +101,24 @@ func (s *sSysSecurity) ValidateIPWhitelist(ctx context.Context, ip string, white
 // LogSensitiveOperation 记录敏感操作
 func (s *sSysSecurity) LogSensitiveOperation(ctx context.Context, userId int64, operation string, details string) error {
 	g.Log().Infof(ctx, "Sensitive operation: user=%d, op=%s, details=%s", userId, operation, details)
-	// TODO: 写入审计日志表
+
+	var ip string
+	if r := ghttp.RequestFromCtx(ctx); r != nil {
+		ip = location.GetClientIp(r)
+	}
+
+	cols := dao.SysSensitiveLog.Columns()
+	_, err := dao.SysSensitiveLog.Ctx(ctx).Data(g.Map{
+		cols.MemberId:  userId,
+		cols.Operation: operation,
+		cols.Details:   details,
+		cols.Ip:        ip,
+		cols.CreatedAt: gtime.Now(),
+	}).Insert()
+	if err != nil {
+		g.Log().Warningf(ctx, "Sensitive operation audit write failed: user=%d, op=%s, err=%v", userId, operation, err)
+		return gerror.Wrap(err, consts.ErrorORM)
+	}
 	return nil
 }
 
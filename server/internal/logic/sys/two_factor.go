@@ -0,0 +1,577 @@
+// Package sys
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 双因素认证（TOTP）
+package sys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/grand"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/cache"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/sysin"
+	"hotgo/internal/service"
+	"hotgo/utility/encrypt"
+)
+
+// 说明：
+// - 用户先 Setup 生成待绑定密钥，验证器扫码后提交口令 Enable 启用，同时生成一次性恢复码
+// - 登录时已启用或角色被强制的用户需额外完成口令校验，见 AdminSite.AccountLogin
+// - 敏感操作调用 RequireVerified，口令通过请求头/参数随请求提交，或先调用 StepUp 开启短时免验证窗口
+
+const (
+	twoFactorStatusPending = 0 // 待绑定
+	twoFactorStatusEnabled = 1 // 已启用
+
+	twoFactorSkew        = 1 // 允许前后1个步长的时钟偏差
+	twoFactorTicketKey   = "two_factor:ticket:"
+	twoFactorStepUpKey   = "two_factor:step_up:"
+	twoFactorFailKey     = "two_factor:fail:"
+	twoFactorDefaultName = "Toogo"
+)
+
+type sSysTwoFactor struct{}
+
+func NewSysTwoFactor() *sSysTwoFactor {
+	return &sSysTwoFactor{}
+}
+
+func init() {
+	service.RegisterSysTwoFactor(NewSysTwoFactor())
+}
+
+// Status 获取双因素认证状态
+func (s *sSysTwoFactor) Status(ctx context.Context, memberId int64) (res *sysin.TwoFactorStatusModel, err error) {
+	res = new(sysin.TwoFactorStatusModel)
+	if res.Required, err = s.RequiredForMember(ctx, memberId); err != nil {
+		return
+	}
+
+	tf, err := s.get(ctx, memberId)
+	if err != nil || tf == nil || tf.Status != twoFactorStatusEnabled {
+		return
+	}
+
+	res.Enabled = true
+	res.EnabledAt = tf.EnabledAt
+	res.RecoveryRemaining = len(decodeRecoveryCodes(tf.RecoveryCodes))
+	return
+}
+
+// IsEnabled 是否已启用双因素认证
+func (s *sSysTwoFactor) IsEnabled(ctx context.Context, memberId int64) (bool, error) {
+	tf, err := s.get(ctx, memberId)
+	if err != nil {
+		return false, err
+	}
+	return tf != nil && tf.Status == twoFactorStatusEnabled, nil
+}
+
+// RequiredForRole 角色是否被登录配置强制启用双因素认证
+func (s *sSysTwoFactor) RequiredForRole(ctx context.Context, roleId int64) (bool, error) {
+	conf, err := service.SysConfig().GetLogin(ctx)
+	if err != nil {
+		return false, err
+	}
+	if conf == nil {
+		return false, nil
+	}
+	for _, id := range conf.TwoFactorRoleIds {
+		if id == roleId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RequiredForMember 用户所属角色是否被强制启用双因素认证
+func (s *sSysTwoFactor) RequiredForMember(ctx context.Context, memberId int64) (bool, error) {
+	roleId, err := dao.AdminMember.Ctx(ctx).Where(dao.AdminMember.Columns().Id, memberId).Value(dao.AdminMember.Columns().RoleId)
+	if err != nil {
+		return false, gerror.Wrap(err, consts.ErrorORM)
+	}
+	return s.RequiredForRole(ctx, roleId.Int64())
+}
+
+// Setup 生成待绑定的验证器密钥，未启用前重复调用会替换密钥
+func (s *sSysTwoFactor) Setup(ctx context.Context, memberId int64) (res *sysin.TwoFactorSetupModel, err error) {
+	tf, err := s.get(ctx, memberId)
+	if err != nil {
+		return
+	}
+	if tf != nil && tf.Status == twoFactorStatusEnabled {
+		err = gerror.New("已启用双因素认证，如需更换验证器请先停用")
+		return
+	}
+
+	username, err := dao.AdminMember.Ctx(ctx).Where(dao.AdminMember.Columns().Id, memberId).Value(dao.AdminMember.Columns().Username)
+	if err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+		return
+	}
+	if username.IsEmpty() {
+		err = gerror.New("用户不存在")
+		return
+	}
+
+	secret, err := encrypt.GenerateTotpSecret()
+	if err != nil {
+		return
+	}
+	cipher, err := encrypt.EncryptApiKey(secret)
+	if err != nil {
+		return
+	}
+
+	cols := dao.SysTwoFactor.Columns()
+	if tf == nil {
+		_, err = dao.SysTwoFactor.Ctx(ctx).Data(g.Map{
+			cols.MemberId: memberId,
+			cols.Secret:   cipher,
+			cols.Status:   twoFactorStatusPending,
+		}).Insert()
+	} else {
+		_, err = dao.SysTwoFactor.Ctx(ctx).Where(cols.Id, tf.Id).Data(g.Map{
+			cols.Secret:        cipher,
+			cols.Status:        twoFactorStatusPending,
+			cols.RecoveryCodes: "",
+			cols.LastUsedStep:  0,
+		}).Update()
+	}
+	if err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+		return
+	}
+
+	res = &sysin.TwoFactorSetupModel{
+		Secret:     secret,
+		OtpauthUrl: encrypt.TotpURI(s.issuer(ctx), username.String(), secret),
+	}
+	return
+}
+
+// Enable 校验验证器口令并启用，返回恢复码
+func (s *sSysTwoFactor) Enable(ctx context.Context, memberId int64, code string) (res *sysin.TwoFactorRecoveryModel, err error) {
+	tf, err := s.get(ctx, memberId)
+	if err != nil {
+		return
+	}
+	if tf == nil {
+		err = gerror.New("请先生成验证器密钥")
+		return
+	}
+	if tf.Status == twoFactorStatusEnabled {
+		err = gerror.New("已启用双因素认证")
+		return
+	}
+
+	// 启用时只接受验证器口令，确认用户已正确绑定
+	if err = s.verifyTotp(ctx, tf, code); err != nil {
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return
+	}
+
+	cols := dao.SysTwoFactor.Columns()
+	if _, err = dao.SysTwoFactor.Ctx(ctx).Where(cols.Id, tf.Id).Data(g.Map{
+		cols.Status:        twoFactorStatusEnabled,
+		cols.RecoveryCodes: hashes,
+		cols.EnabledAt:     gtime.Now(),
+	}).Update(); err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+		return
+	}
+
+	_ = service.SysSecurity().LogSensitiveOperation(ctx, memberId, consts.SensitiveOpTwoFactor, "启用双因素认证")
+	res = &sysin.TwoFactorRecoveryModel{RecoveryCodes: codes}
+	return
+}
+
+// Disable 校验口令或恢复码后停用，被角色强制的用户不能停用
+func (s *sSysTwoFactor) Disable(ctx context.Context, memberId int64, code string) (err error) {
+	required, err := s.RequiredForMember(ctx, memberId)
+	if err != nil {
+		return
+	}
+	if required {
+		return gerror.New("当前角色必须启用双因素认证，无法停用")
+	}
+
+	if err = s.Verify(ctx, memberId, code); err != nil {
+		return
+	}
+
+	if _, err = dao.SysTwoFactor.Ctx(ctx).Where(dao.SysTwoFactor.Columns().MemberId, memberId).Delete(); err != nil {
+		return gerror.Wrap(err, consts.ErrorORM)
+	}
+
+	_, _ = cache.Instance().Remove(ctx, s.stepUpKey(memberId))
+	_ = service.SysSecurity().LogSensitiveOperation(ctx, memberId, consts.SensitiveOpTwoFactor, "停用双因素认证")
+	return
+}
+
+// RegenerateRecovery 校验验证器口令后重新生成恢复码，旧恢复码全部失效
+func (s *sSysTwoFactor) RegenerateRecovery(ctx context.Context, memberId int64, code string) (res *sysin.TwoFactorRecoveryModel, err error) {
+	var tf *entity.SysTwoFactor
+	err = s.attempt(ctx, memberId, func(enabled *entity.SysTwoFactor) error {
+		tf = enabled
+		return s.verifyTotp(ctx, tf, code)
+	})
+	if err != nil {
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return
+	}
+
+	cols := dao.SysTwoFactor.Columns()
+	if _, err = dao.SysTwoFactor.Ctx(ctx).Where(cols.Id, tf.Id).Data(cols.RecoveryCodes, hashes).Update(); err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+		return
+	}
+
+	_ = service.SysSecurity().LogSensitiveOperation(ctx, memberId, consts.SensitiveOpTwoFactor, "重新生成恢复码")
+	res = &sysin.TwoFactorRecoveryModel{RecoveryCodes: codes}
+	return
+}
+
+// Verify 校验验证器口令或恢复码，恢复码使用后立即作废
+// 登录、StepUp、敏感操作等所有场景共用同一用户级失败计数，锁定时长内累计错误达到上限后拒绝校验
+func (s *sSysTwoFactor) Verify(ctx context.Context, memberId int64, code string) (err error) {
+	return s.attempt(ctx, memberId, func(tf *entity.SysTwoFactor) error {
+		code = strings.TrimSpace(code)
+		if len(code) == encrypt.TotpDigits {
+			return s.verifyTotp(ctx, tf, code)
+		}
+		return s.useRecoveryCode(ctx, tf, code)
+	})
+}
+
+// attempt 在用户级失败计数下执行口令校验，锁定期间直接拒绝，校验通过后清除计数
+func (s *sSysTwoFactor) attempt(ctx context.Context, memberId int64, verify func(tf *entity.SysTwoFactor) error) (err error) {
+	if s.locked(ctx, memberId) {
+		return gerror.Newf("动态口令错误次数过多，请%d分钟后再试", consts.TwoFactorLockDuration/60)
+	}
+
+	tf, err := s.getEnabled(ctx, memberId)
+	if err != nil {
+		return
+	}
+
+	key := s.failKey(memberId)
+	if err = verify(tf); err == nil {
+		_, _ = cache.Instance().Remove(ctx, key)
+		return
+	}
+
+	v, _ := cache.Instance().Get(ctx, key)
+	attempts := v.Int() + 1
+	_ = cache.Instance().Set(ctx, key, attempts, consts.TwoFactorLockDuration*time.Second)
+	_ = service.SysSecurity().LogSensitiveOperation(ctx, memberId, consts.SensitiveOpTwoFactor, fmt.Sprintf("动态口令错误（%d/%d）", attempts, consts.TwoFactorMaxAttempts))
+	if attempts >= consts.TwoFactorMaxAttempts {
+		return gerror.Newf("动态口令错误次数过多，请%d分钟后再试", consts.TwoFactorLockDuration/60)
+	}
+	return
+}
+
+// locked 动态口令是否因错误次数过多被锁定
+func (s *sSysTwoFactor) locked(ctx context.Context, memberId int64) bool {
+	v, _ := cache.Instance().Get(ctx, s.failKey(memberId))
+	return v.Int() >= consts.TwoFactorMaxAttempts
+}
+
+// StepUp 校验口令，通过后一段时间内执行敏感操作无需再次输入
+func (s *sSysTwoFactor) StepUp(ctx context.Context, memberId int64, code string) (res *sysin.TwoFactorVerifyModel, err error) {
+	if err = s.Verify(ctx, memberId, code); err != nil {
+		return
+	}
+
+	if err = cache.Instance().Set(ctx, s.stepUpKey(memberId), 1, consts.TwoFactorStepUpTTL*time.Second); err != nil {
+		return
+	}
+	res = &sysin.TwoFactorVerifyModel{ExpiresIn: consts.TwoFactorStepUpTTL}
+	return
+}
+
+// RequireVerified 敏感操作二次验证，并写入审计记录
+// 未启用双因素认证的用户直接放行（角色被强制的除外）；已启用的用户需在请求中携带口令，或处于 StepUp 免验证窗口内
+func (s *sSysTwoFactor) RequireVerified(ctx context.Context, memberId int64, operation, details string) (err error) {
+	audit := func(result string) {
+		_ = service.SysSecurity().LogSensitiveOperation(ctx, memberId, operation, fmt.Sprintf("%s [2fa:%s]", details, result))
+	}
+
+	if memberId <= 0 {
+		audit("rejected:anonymous")
+		return gerror.New("用户未登录")
+	}
+
+	enabled, err := s.IsEnabled(ctx, memberId)
+	if err != nil {
+		return
+	}
+	if !enabled {
+		required, err := s.RequiredForMember(ctx, memberId)
+		if err != nil {
+			return err
+		}
+		if required {
+			audit("rejected:not_enrolled")
+			return gerror.NewCode(gcode.New(consts.CodeTwoFactorRequired, "", nil), "该操作需要双因素认证，请先绑定验证器")
+		}
+		audit("skipped:not_enrolled")
+		return nil
+	}
+
+	if code := s.requestCode(ctx); code != "" {
+		if err = s.Verify(ctx, memberId, code); err != nil {
+			audit("rejected:invalid_code")
+			return
+		}
+		audit("passed:code")
+		return nil
+	}
+
+	if v, _ := cache.Instance().Get(ctx, s.stepUpKey(memberId)); v != nil && !v.IsEmpty() {
+		audit("passed:step_up")
+		return nil
+	}
+
+	audit("rejected:missing_code")
+	return gerror.NewCode(gcode.New(consts.CodeTwoFactorRequired, "", nil), "请输入双因素认证动态口令")
+}
+
+// IssueLoginTicket 签发登录二次验证票据
+func (s *sSysTwoFactor) IssueLoginTicket(ctx context.Context, memberId int64) (ticket string, err error) {
+	ticket = grand.S(32)
+	err = cache.Instance().Set(ctx, twoFactorTicketKey+ticket, &sysin.TwoFactorLoginTicket{MemberId: memberId}, consts.TwoFactorLoginTicketTTL*time.Second)
+	return
+}
+
+// LoginTicketMember 获取登录票据对应的用户，票据无效或已过期时返回错误
+func (s *sSysTwoFactor) LoginTicketMember(ctx context.Context, ticket string) (memberId int64, err error) {
+	t, err := s.getTicket(ctx, ticket)
+	if err != nil {
+		return
+	}
+	return t.MemberId, nil
+}
+
+// VerifyLoginTicket 校验登录票据及口令，已启用的用户校验口令或恢复码，未启用的用户校验待绑定密钥并启用
+// 口令错误次数超过限制后票据作废，需重新登录
+func (s *sSysTwoFactor) VerifyLoginTicket(ctx context.Context, ticket, code string) (memberId int64, recovery *sysin.TwoFactorRecoveryModel, err error) {
+	t, err := s.getTicket(ctx, ticket)
+	if err != nil {
+		return
+	}
+	memberId = t.MemberId
+
+	enabled, err := s.IsEnabled(ctx, memberId)
+	if err != nil {
+		return
+	}
+	if enabled {
+		if err = s.Verify(ctx, memberId, code); s.locked(ctx, memberId) {
+			_, _ = cache.Instance().Remove(ctx, twoFactorTicketKey+ticket)
+			return
+		}
+	} else {
+		recovery, err = s.Enable(ctx, memberId, code)
+	}
+
+	key := twoFactorTicketKey + ticket
+	if err != nil {
+		t.Attempts++
+		if t.Attempts >= consts.TwoFactorMaxAttempts {
+			_, _ = cache.Instance().Remove(ctx, key)
+			err = gerror.New("动态口令错误次数过多，请重新登录")
+			return
+		}
+		_, _, _ = cache.Instance().Update(ctx, key, t)
+		return
+	}
+
+	_, _ = cache.Instance().Remove(ctx, key)
+	return
+}
+
+func (s *sSysTwoFactor) getTicket(ctx context.Context, ticket string) (t *sysin.TwoFactorLoginTicket, err error) {
+	if ticket == "" {
+		return nil, gerror.New("登录票据不能为空")
+	}
+	v, err := cache.Instance().Get(ctx, twoFactorTicketKey+ticket)
+	if err != nil {
+		return
+	}
+	if v == nil || v.IsEmpty() {
+		return nil, gerror.New("登录票据已过期，请重新登录")
+	}
+	if err = v.Scan(&t); err != nil || t == nil || t.MemberId <= 0 {
+		return nil, gerror.New("登录票据无效，请重新登录")
+	}
+	return
+}
+
+func (s *sSysTwoFactor) get(ctx context.Context, memberId int64) (tf *entity.SysTwoFactor, err error) {
+	if err = dao.SysTwoFactor.Ctx(ctx).Where(dao.SysTwoFactor.Columns().MemberId, memberId).Scan(&tf); err != nil {
+		err = gerror.Wrap(err, consts.ErrorORM)
+	}
+	return
+}
+
+func (s *sSysTwoFactor) getEnabled(ctx context.Context, memberId int64) (tf *entity.SysTwoFactor, err error) {
+	if tf, err = s.get(ctx, memberId); err != nil {
+		return
+	}
+	if tf == nil || tf.Status != twoFactorStatusEnabled {
+		return nil, gerror.New("未启用双因素认证")
+	}
+	return
+}
+
+// verifyTotp 校验验证器口令，同一步长的口令只能使用一次
+func (s *sSysTwoFactor) verifyTotp(ctx context.Context, tf *entity.SysTwoFactor, code string) error {
+	secret, err := encrypt.DecryptApiKey(tf.Secret)
+	if err != nil {
+		return gerror.Wrap(err, "读取验证器密钥失败")
+	}
+
+	step, ok := encrypt.VerifyTotp(secret, code, time.Now(), twoFactorSkew)
+	if !ok || step <= tf.LastUsedStep {
+		return gerror.New("动态口令错误或已使用")
+	}
+
+	// 条件更新防止并发请求重复使用同一口令
+	cols := dao.SysTwoFactor.Columns()
+	r, err := dao.SysTwoFactor.Ctx(ctx).
+		Where(cols.Id, tf.Id).
+		WhereLT(cols.LastUsedStep, step).
+		Data(cols.LastUsedStep, step).
+		Update()
+	if err != nil {
+		return gerror.Wrap(err, consts.ErrorORM)
+	}
+	if n, _ := r.RowsAffected(); n == 0 {
+		return gerror.New("动态口令错误或已使用")
+	}
+	tf.LastUsedStep = step
+	return nil
+}
+
+// useRecoveryCode 校验并作废恢复码
+func (s *sSysTwoFactor) useRecoveryCode(ctx context.Context, tf *entity.SysTwoFactor, code string) error {
+	hashes := decodeRecoveryCodes(tf.RecoveryCodes)
+	hash := hashRecoveryCode(code)
+
+	remain := make([]string, 0, len(hashes))
+	found := false
+	for _, h := range hashes {
+		if !found && h == hash {
+			found = true
+			continue
+		}
+		remain = append(remain, h)
+	}
+	if !found {
+		return gerror.New("动态口令或恢复码错误")
+	}
+
+	b, _ := json.Marshal(remain)
+	cols := dao.SysTwoFactor.Columns()
+	r, err := dao.SysTwoFactor.Ctx(ctx).
+		Where(cols.Id, tf.Id).
+		Where(cols.RecoveryCodes, tf.RecoveryCodes).
+		Data(cols.RecoveryCodes, string(b)).
+		Update()
+	if err != nil {
+		return gerror.Wrap(err, consts.ErrorORM)
+	}
+	if n, _ := r.RowsAffected(); n == 0 {
+		return gerror.New("恢复码已被使用，请重试")
+	}
+
+	tf.RecoveryCodes = string(b)
+	_ = service.SysSecurity().LogSensitiveOperation(ctx, tf.MemberId, consts.SensitiveOpTwoFactor, fmt.Sprintf("使用恢复码，剩余%d个", len(remain)))
+	return nil
+}
+
+func (s *sSysTwoFactor) requestCode(ctx context.Context) string {
+	r := ghttp.RequestFromCtx(ctx)
+	if r == nil {
+		return ""
+	}
+	if code := r.GetHeader(consts.TwoFactorCodeHeader); code != "" {
+		return code
+	}
+	return r.Get(consts.TwoFactorCodeParam).String()
+}
+
+func (s *sSysTwoFactor) stepUpKey(memberId int64) string {
+	return fmt.Sprintf("%s%d", twoFactorStepUpKey, memberId)
+}
+
+func (s *sSysTwoFactor) failKey(memberId int64) string {
+	return fmt.Sprintf("%s%d", twoFactorFailKey, memberId)
+}
+
+func (s *sSysTwoFactor) issuer(ctx context.Context) string {
+	basic, err := service.SysConfig().GetBasic(ctx)
+	if err != nil || basic == nil || basic.Name == "" {
+		return twoFactorDefaultName
+	}
+	return basic.Name
+}
+
+// generateRecoveryCodes 生成恢复码，返回明文与哈希(JSON)
+func generateRecoveryCodes() (codes []string, hashes string, err error) {
+	codes = make([]string, consts.TwoFactorRecoveryCount)
+	list := make([]string, consts.TwoFactorRecoveryCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err = rand.Read(b); err != nil {
+			return
+		}
+		raw := hex.EncodeToString(b)
+		codes[i] = raw[:5] + "-" + raw[5:]
+		list[i] = hashRecoveryCode(codes[i])
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		return
+	}
+	return codes, string(b), nil
+}
+
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func decodeRecoveryCodes(s string) (list []string) {
+	if s == "" {
+		return
+	}
+	_ = json.Unmarshal([]byte(s), &list)
+	return
+}
@@ -0,0 +1,613 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 代理报表：团队看板（增长、分层业绩、贡献排行、邀请转化）与月度佣金对账单
+package toogo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/toogoin"
+	"hotgo/utility/excel"
+	"hotgo/utility/pdf"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+const (
+	agentReportDefaultDays = 30
+	agentReportDefaultTop  = 10
+	// reconcileEpsilon 对账金额误差
+	reconcileEpsilon = 1e-6
+)
+
+// paidSubscriptionStatuses 已付费订阅状态（不含待支付、已取消/已退款）
+var paidSubscriptionStatuses = []int{
+	consts.SubscriptionStatusActive,
+	consts.SubscriptionStatusExpired,
+	consts.SubscriptionStatusGrace,
+	consts.SubscriptionStatusReplaced,
+}
+
+// commissionLedgerTypes 佣金入账/冲正在账户流水中的变动类型（佣金/冻结佣金账户）
+var commissionLedgerTypes = []string{"subscribe", "power", consts.CommissionChangeReversal}
+
+// AgentDashboard 代理看板
+// 团队范围同团队列表：已解锁层级为无限级，否则仅直推
+func (s *sToogoCommission) AgentDashboard(ctx context.Context, in *toogoin.AgentDashboardInp) (res *toogoin.AgentDashboardModel, err error) {
+	agent, err := reportAgent(ctx, in.MemberId)
+	if err != nil {
+		return nil, err
+	}
+	if agent.IsAgent != 1 || agent.AgentStatus != 2 {
+		return nil, gerror.New("您还不是代理商")
+	}
+
+	start, end := gtime.Now().AddDate(0, 0, -agentReportDefaultDays).StartOfDay(), gtime.Now()
+	if len(in.CreatedAt) == 2 {
+		start, end = gtime.New(in.CreatedAt[0]), gtime.New(in.CreatedAt[1])
+		if start == nil || end == nil || !start.Before(end) {
+			return nil, gerror.New("统计区间不正确")
+		}
+	}
+	topLimit := in.TopLimit
+	if topLimit <= 0 {
+		topLimit = agentReportDefaultTop
+	}
+
+	maxDepth := 1
+	if agent.AgentUnlockLevel == 1 {
+		maxDepth = 0
+	}
+	team, err := loadTeam(ctx, agent.MemberId, maxDepth)
+	if err != nil {
+		return nil, gerror.Wrap(err, "加载团队成员失败")
+	}
+	depthOf := make(map[int64]int, len(team))
+	ids := make([]int64, 0, len(team))
+	for _, m := range team {
+		depthOf[m.MemberId] = m.Depth
+		ids = append(ids, m.MemberId)
+	}
+
+	res = &toogoin.AgentDashboardModel{
+		StartTime:  start.String(),
+		EndTime:    end.String(),
+		Overview:   &toogoin.AgentOverview{TeamCount: len(team)},
+		Conversion: &toogoin.AgentInviteConversion{},
+	}
+	levels := make(map[int]*toogoin.AgentLevelReport)
+	levelOf := func(depth int) *toogoin.AgentLevelReport {
+		if levels[depth] == nil {
+			levels[depth] = &toogoin.AgentLevelReport{Level: depth, RateDiffs: []float64{}}
+		}
+		return levels[depth]
+	}
+	for _, m := range team {
+		levelOf(m.Depth).MemberCount++
+		if m.Depth == 1 {
+			res.Overview.DirectCount++
+		}
+	}
+
+	// 团队增长与邀请转化（区间内注册的成员）
+	joined, err := teamJoinTimes(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	res.Growth = teamGrowth(joined, start, end, in.Interval)
+	var cohort []int64
+	for id, at := range joined {
+		if !at.Before(start) && !at.After(end) {
+			cohort = append(cohort, id)
+		}
+	}
+	res.Overview.NewMembers = len(cohort)
+	if res.Conversion, err = inviteConversion(ctx, cohort); err != nil {
+		return nil, err
+	}
+
+	// 运行中机器人
+	robots, err := activeRobotCounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for id, count := range robots {
+		levelOf(depthOf[id]).ActiveRobots += count
+		res.Overview.ActiveRobots += count
+	}
+
+	// 区间内订阅金额
+	revenue := make(map[int64]float64)
+	for batch := range slices.Chunk(ids, teamIdBatch) {
+		var subs []*entity.ToogoSubscription
+		err = dao.ToogoSubscription.Ctx(ctx).
+			Fields("user_id, amount, credit_amount").
+			WhereIn(dao.ToogoSubscription.Columns().UserId, batch).
+			WhereIn(dao.ToogoSubscription.Columns().Status, paidSubscriptionStatuses).
+			WhereNot(dao.ToogoSubscription.Columns().PeriodType, consts.SubscriptionPeriodTrial).
+			WhereBetween(dao.ToogoSubscription.Columns().PaidAt, start, end).
+			Scan(&subs)
+		if err != nil {
+			return nil, gerror.Wrap(err, "统计团队订阅失败")
+		}
+		for _, sub := range subs {
+			amount := math.Max(sub.Amount-sub.CreditAmount, 0)
+			revenue[sub.UserId] += amount
+			level := levelOf(depthOf[sub.UserId])
+			level.SubscriptionCount++
+			level.SubscriptionRevenue += amount
+			res.Overview.SubscriptionRevenue += amount
+		}
+	}
+
+	// 区间内佣金（含冲正记录），层级即来源用户相对代理的层级
+	cols := dao.ToogoCommissionLog.Columns()
+	var logs []*entity.ToogoCommissionLog
+	err = dao.ToogoCommissionLog.Ctx(ctx).
+		Fields("from_user_id, level, base_amount, commission_rate, commission_amount, reversal_of").
+		Where(cols.UserId, agent.MemberId).
+		Where(cols.SettleType, "usdt").
+		WhereBetween(cols.CreatedAt, start, end).
+		Scan(&logs)
+	if err != nil {
+		return nil, gerror.Wrap(err, "统计佣金失败")
+	}
+	contribution := make(map[int64]float64)
+	for _, log := range logs {
+		level := levelOf(log.Level)
+		level.BaseAmount += log.BaseAmount
+		level.Commission += log.CommissionAmount
+		if log.ReversalOf == 0 && !slices.Contains(level.RateDiffs, log.CommissionRate) {
+			level.RateDiffs = append(level.RateDiffs, log.CommissionRate)
+		}
+		contribution[log.FromUserId] += log.CommissionAmount
+		res.Overview.Commission += log.CommissionAmount
+	}
+
+	for _, level := range levels {
+		sort.Float64s(level.RateDiffs)
+		if level.BaseAmount > 0 {
+			level.EffectiveRate = level.Commission / level.BaseAmount * 100
+		}
+		res.Levels = append(res.Levels, level)
+	}
+	sort.Slice(res.Levels, func(i, j int) bool { return res.Levels[i].Level < res.Levels[j].Level })
+
+	if res.TopMembers, err = topContributors(ctx, contribution, revenue, depthOf, topLimit); err != nil {
+		return nil, err
+	}
+
+	frozen, _ := dao.ToogoCommissionLog.Ctx(ctx).
+		Where(cols.UserId, agent.MemberId).
+		Where(cols.Status, consts.CommissionStatusFrozen).
+		Sum(cols.CommissionAmount)
+	res.Overview.FrozenCommission = frozen
+	balance, _ := dao.ToogoWallet.Ctx(ctx).
+		Where(dao.ToogoWallet.Columns().UserId, agent.MemberId).
+		Value(dao.ToogoWallet.Columns().Commission)
+	res.Overview.CommissionBalance = balance.Float64()
+	return
+}
+
+// AgentStatement 代理月度对账单
+// 佣金记录与佣金/冻结佣金账户流水逐月核对：本月净佣金 = 流水记入佣金，本月解冻 = 流水解冻入账
+func (s *sToogoCommission) AgentStatement(ctx context.Context, in *toogoin.AgentStatementInp) (res *toogoin.AgentStatementModel, err error) {
+	agent, err := reportAgent(ctx, in.MemberId)
+	if err != nil {
+		return nil, err
+	}
+	start, err := gtime.StrToTimeFormat(in.Month+"-01", "Y-m-d")
+	if err != nil {
+		return nil, gerror.New("账单月份格式为 Y-m")
+	}
+	end := start.AddDate(0, 1, 0)
+
+	res = &toogoin.AgentStatementModel{
+		MemberId: agent.MemberId,
+		Month:    start.Format("Y-m"),
+	}
+	usernames, err := memberUsernames(ctx, []int64{agent.MemberId})
+	if err != nil {
+		return nil, err
+	}
+	res.Username = usernames[agent.MemberId]
+
+	cols := dao.ToogoCommissionLog.Columns()
+	var logs []*entity.ToogoCommissionLog
+	err = dao.ToogoCommissionLog.Ctx(ctx).
+		Where(cols.UserId, agent.MemberId).
+		Where(cols.SettleType, "usdt").
+		WhereGTE(cols.CreatedAt, start).
+		WhereLT(cols.CreatedAt, end).
+		OrderAsc(cols.Id).
+		Scan(&logs)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询佣金记录失败")
+	}
+	fromIds := make([]int64, 0, len(logs))
+	for _, log := range logs {
+		fromIds = append(fromIds, log.FromUserId)
+	}
+	fromNames, err := memberUsernames(ctx, fromIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, log := range logs {
+		if log.ReversalOf > 0 {
+			res.Reversed -= log.CommissionAmount
+		} else {
+			res.Earned += log.CommissionAmount
+		}
+		res.Commissions = append(res.Commissions, &toogoin.AgentStatementCommission{
+			Id:             log.Id,
+			CreatedAt:      log.CreatedAt,
+			CommissionType: log.CommissionType,
+			FromUserId:     log.FromUserId,
+			FromUsername:   fromNames[log.FromUserId],
+			Level:          log.Level,
+			BaseAmount:     log.BaseAmount,
+			RateDiff:       log.CommissionRate,
+			Amount:         log.CommissionAmount,
+			Status:         log.Status,
+			ReversalOf:     log.ReversalOf,
+			OrderSn:        log.OrderSn,
+		})
+	}
+	res.NetCommission = res.Earned - res.Reversed
+
+	res.Released, err = dao.ToogoCommissionLog.Ctx(ctx).
+		Where(cols.UserId, agent.MemberId).
+		WhereGTE(cols.ReleasedAt, start).
+		WhereLT(cols.ReleasedAt, end).
+		Sum(cols.ReleasedAmount)
+	if err != nil {
+		return nil, gerror.Wrap(err, "统计解冻佣金失败")
+	}
+
+	// 账户流水核对
+	walletCols := dao.ToogoWalletLog.Columns()
+	res.LedgerCredited, err = dao.ToogoWalletLog.Ctx(ctx).
+		Where(walletCols.UserId, agent.MemberId).
+		WhereIn(walletCols.AccountType, []string{"commission", "frozen_commission"}).
+		WhereIn(walletCols.ChangeType, commissionLedgerTypes).
+		WhereGTE(walletCols.CreatedAt, start).
+		WhereLT(walletCols.CreatedAt, end).
+		Sum(walletCols.ChangeAmount)
+	if err != nil {
+		return nil, gerror.Wrap(err, "统计佣金流水失败")
+	}
+	res.LedgerReleased, err = dao.ToogoWalletLog.Ctx(ctx).
+		Where(walletCols.UserId, agent.MemberId).
+		Where(walletCols.AccountType, "commission").
+		Where(walletCols.ChangeType, consts.CommissionChangeRelease).
+		WhereGTE(walletCols.CreatedAt, start).
+		WhereLT(walletCols.CreatedAt, end).
+		Sum(walletCols.ChangeAmount)
+	if err != nil {
+		return nil, gerror.Wrap(err, "统计解冻流水失败")
+	}
+	res.Reconciled = math.Abs(res.NetCommission-res.LedgerCredited) < reconcileEpsilon &&
+		math.Abs(res.Released-res.LedgerReleased) < reconcileEpsilon
+
+	// 佣金提现
+	withdrawCols := dao.ToogoWithdraw.Columns()
+	var withdraws []*entity.ToogoWithdraw
+	err = dao.ToogoWithdraw.Ctx(ctx).
+		Where(withdrawCols.UserId, agent.MemberId).
+		Where(withdrawCols.AccountType, "commission").
+		Where(fmt.Sprintf("((%s >= ? AND %s < ?) OR (%s >= ? AND %s < ?))",
+			withdrawCols.CreatedAt, withdrawCols.CreatedAt, withdrawCols.CompletedAt, withdrawCols.CompletedAt), start, end, start, end).
+		OrderAsc(withdrawCols.Id).
+		Scan(&withdraws)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询佣金提现失败")
+	}
+	for _, w := range withdraws {
+		if w.Status == 4 && w.CompletedAt != nil && !w.CompletedAt.Before(start) && w.CompletedAt.Before(end) {
+			res.Withdrawn += w.Amount
+		}
+		res.Withdraws = append(res.Withdraws, &toogoin.AgentStatementWithdraw{
+			OrderSn:     w.OrderSn,
+			Amount:      w.Amount,
+			Fee:         w.Fee,
+			RealAmount:  w.RealAmount,
+			Status:      w.Status,
+			CreatedAt:   w.CreatedAt,
+			CompletedAt: w.CompletedAt,
+		})
+	}
+
+	var wallet *entity.ToogoWallet
+	if err = dao.ToogoWallet.Ctx(ctx).Where(dao.ToogoWallet.Columns().UserId, agent.MemberId).Scan(&wallet); err != nil {
+		return nil, gerror.Wrap(err, "查询钱包失败")
+	}
+	if wallet != nil {
+		res.CommissionBalance = wallet.Commission
+	}
+	res.FrozenCommission, _ = dao.ToogoCommissionLog.Ctx(ctx).
+		Where(cols.UserId, agent.MemberId).
+		Where(cols.Status, consts.CommissionStatusFrozen).
+		Sum(cols.CommissionAmount)
+	return
+}
+
+// AgentStatementExport 下载代理月度对账单（CSV/PDF）
+func (s *sToogoCommission) AgentStatementExport(ctx context.Context, in *toogoin.AgentStatementExportInp) (err error) {
+	statement, err := s.AgentStatement(ctx, &in.AgentStatementInp)
+	if err != nil {
+		return err
+	}
+	fileName := fmt.Sprintf("commission-statement-%d-%s", statement.MemberId, statement.Month)
+	if in.Format == "pdf" {
+		return pdf.Export(ctx, statementPdf(statement), fileName)
+	}
+	return excel.ExportCSV(ctx, statementCsv(statement), fileName)
+}
+
+// statementCsv 对账单CSV：汇总 + 佣金明细 + 提现明细
+func statementCsv(st *toogoin.AgentStatementModel) [][]string {
+	money := func(v float64) string { return fmt.Sprintf("%.4f", v) }
+	rows := [][]string{
+		{"代理佣金对账单", st.Month},
+		{"用户ID", fmt.Sprint(st.MemberId), "用户名", st.Username},
+		{},
+		{"本月新增佣金", money(st.Earned)},
+		{"本月冲正佣金", money(st.Reversed)},
+		{"本月净佣金", money(st.NetCommission)},
+		{"本月解冻入账", money(st.Released)},
+		{"本月完成提现", money(st.Withdrawn)},
+		{"当前冻结佣金", money(st.FrozenCommission)},
+		{"当前佣金余额", money(st.CommissionBalance)},
+		{"账户流水记入佣金", money(st.LedgerCredited)},
+		{"账户流水解冻入账", money(st.LedgerReleased)},
+		{"对账结果", map[bool]string{true: "一致", false: "不一致"}[st.Reconciled]},
+		{},
+		{"佣金记录ID", "时间", "佣金类型", "来源用户ID", "来源用户名", "层级", "计佣基数", "级差比例(%)", "佣金金额", "状态", "冲正原记录ID", "来源订单号"},
+	}
+	for _, c := range st.Commissions {
+		rows = append(rows, []string{
+			fmt.Sprint(c.Id), c.CreatedAt.String(), c.CommissionType, fmt.Sprint(c.FromUserId), c.FromUsername, fmt.Sprint(c.Level),
+			money(c.BaseAmount), fmt.Sprintf("%.2f", c.RateDiff), money(c.Amount), commissionStatusText(c.Status), fmt.Sprint(c.ReversalOf), c.OrderSn,
+		})
+	}
+	rows = append(rows, []string{}, []string{"提现订单号", "申请时间", "完成时间", "提现金额", "手续费", "实际到账", "状态"})
+	for _, w := range st.Withdraws {
+		rows = append(rows, []string{
+			w.OrderSn, w.CreatedAt.String(), w.CompletedAt.String(), money(w.Amount), money(w.Fee), money(w.RealAmount), fmt.Sprint(w.Status),
+		})
+	}
+	return rows
+}
+
+// statementPdf 对账单PDF（内置字体不支持中文，使用英文标签）
+func statementPdf(st *toogoin.AgentStatementModel) *pdf.Document {
+	doc := pdf.New()
+	doc.Line("Commission Statement  %s", st.Month)
+	doc.Line("Member: %d  %s", st.MemberId, st.Username)
+	doc.Line("")
+	doc.Line("Earned:             %14.4f USDT", st.Earned)
+	doc.Line("Reversed:           %14.4f USDT", st.Reversed)
+	doc.Line("Net commission:     %14.4f USDT", st.NetCommission)
+	doc.Line("Released:           %14.4f USDT", st.Released)
+	doc.Line("Withdrawn:          %14.4f USDT", st.Withdrawn)
+	doc.Line("Frozen (current):   %14.4f USDT", st.FrozenCommission)
+	doc.Line("Balance (current):  %14.4f USDT", st.CommissionBalance)
+	doc.Line("Ledger credited:    %14.4f USDT", st.LedgerCredited)
+	doc.Line("Ledger released:    %14.4f USDT", st.LedgerReleased)
+	doc.Line("Reconciled:         %v", st.Reconciled)
+	doc.Line("")
+	doc.Line("%-8s %-19s %-10s %-10s %-3s %12s %7s %12s %-9s %s", "ID", "Time", "Type", "From", "Lv", "Base", "Rate%", "Amount", "Status", "Order")
+	for _, c := range st.Commissions {
+		doc.Line("%-8d %-19s %-10s %-10d %-3d %12.4f %7.2f %12.4f %-9s %s",
+			c.Id, c.CreatedAt.String(), c.CommissionType, c.FromUserId, c.Level, c.BaseAmount, c.RateDiff, c.Amount, commissionStatusCode(c.Status), c.OrderSn)
+	}
+	doc.Line("")
+	doc.Line("%-24s %-19s %-19s %12s %10s %12s %s", "Withdraw", "Created", "Completed", "Amount", "Fee", "Received", "Status")
+	for _, w := range st.Withdraws {
+		doc.Line("%-24s %-19s %-19s %12.4f %10.4f %12.4f %d",
+			w.OrderSn, w.CreatedAt.String(), w.CompletedAt.String(), w.Amount, w.Fee, w.RealAmount, w.Status)
+	}
+	return doc
+}
+
+func commissionStatusText(status int) string {
+	switch status {
+	case consts.CommissionStatusFrozen:
+		return "冻结中"
+	case consts.CommissionStatusReversed:
+		return "已冲正"
+	default:
+		return "已结算"
+	}
+}
+
+func commissionStatusCode(status int) string {
+	switch status {
+	case consts.CommissionStatusFrozen:
+		return "frozen"
+	case consts.CommissionStatusReversed:
+		return "reversed"
+	default:
+		return "settled"
+	}
+}
+
+// reportAgent 报表所属用户
+func reportAgent(ctx context.Context, memberId int64) (*entity.ToogoUser, error) {
+	var agent *entity.ToogoUser
+	if err := dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, memberId).Scan(&agent); err != nil {
+		return nil, gerror.Wrap(err, "获取用户信息失败")
+	}
+	if agent == nil {
+		return nil, gerror.New("用户不存在")
+	}
+	return agent, nil
+}
+
+// teamJoinTimes 团队成员加入时间
+func teamJoinTimes(ctx context.Context, ids []int64) (map[int64]*gtime.Time, error) {
+	joined := make(map[int64]*gtime.Time, len(ids))
+	for batch := range slices.Chunk(ids, teamIdBatch) {
+		var users []*entity.ToogoUser
+		err := dao.ToogoUser.Ctx(ctx).
+			Fields("member_id, created_at").
+			WhereIn(dao.ToogoUser.Columns().MemberId, batch).
+			Scan(&users)
+		if err != nil {
+			return nil, gerror.Wrap(err, "查询团队成员失败")
+		}
+		for _, u := range users {
+			if u.CreatedAt != nil {
+				joined[u.MemberId] = u.CreatedAt
+			}
+		}
+	}
+	return joined, nil
+}
+
+// teamGrowth 按天/按月统计新增与累计成员，区间内无新增的日期也输出
+func teamGrowth(joined map[int64]*gtime.Time, start, end *gtime.Time, interval string) []*toogoin.AgentTeamGrowth {
+	layout, step := "Y-m-d", func(t *gtime.Time) *gtime.Time { return t.AddDate(0, 0, 1) }
+	bucketStart := start.StartOfDay()
+	if interval == "month" {
+		layout, step = "Y-m", func(t *gtime.Time) *gtime.Time { return t.AddDate(0, 1, 0) }
+		bucketStart = start.StartOfMonth()
+	}
+
+	before := 0
+	counts := make(map[string]int)
+	for _, at := range joined {
+		if at.Before(start) {
+			before++
+		} else if !at.After(end) {
+			counts[at.Format(layout)]++
+		}
+	}
+
+	growth := make([]*toogoin.AgentTeamGrowth, 0)
+	total := before
+	for t := bucketStart; !t.After(end); t = step(t) {
+		date := t.Format(layout)
+		total += counts[date]
+		growth = append(growth, &toogoin.AgentTeamGrowth{Date: date, NewMembers: counts[date], TotalMembers: total})
+	}
+	return growth
+}
+
+// inviteConversion 邀请转化：成员中领取过试用、购买过付费套餐的人数
+func inviteConversion(ctx context.Context, cohort []int64) (*toogoin.AgentInviteConversion, error) {
+	res := &toogoin.AgentInviteConversion{Invited: len(cohort)}
+	trial, paid := make(map[int64]struct{}), make(map[int64]struct{})
+	for batch := range slices.Chunk(cohort, teamIdBatch) {
+		var subs []*entity.ToogoSubscription
+		err := dao.ToogoSubscription.Ctx(ctx).
+			Fields("user_id, period_type").
+			WhereIn(dao.ToogoSubscription.Columns().UserId, batch).
+			WhereIn(dao.ToogoSubscription.Columns().Status, paidSubscriptionStatuses).
+			Scan(&subs)
+		if err != nil {
+			return nil, gerror.Wrap(err, "统计邀请转化失败")
+		}
+		for _, sub := range subs {
+			if sub.PeriodType == consts.SubscriptionPeriodTrial {
+				trial[sub.UserId] = struct{}{}
+			} else {
+				paid[sub.UserId] = struct{}{}
+			}
+		}
+	}
+	res.TrialMembers, res.PaidMembers = len(trial), len(paid)
+	if res.Invited > 0 {
+		res.ConversionRate = float64(res.PaidMembers) / float64(res.Invited) * 100
+	}
+	return res, nil
+}
+
+// activeRobotCounts 成员运行中机器人数量
+func activeRobotCounts(ctx context.Context, ids []int64) (map[int64]int, error) {
+	counts := make(map[int64]int)
+	cols := dao.TradingRobot.Columns()
+	for batch := range slices.Chunk(ids, teamIdBatch) {
+		var rows []struct {
+			UserId int64 `json:"userId"`
+			Count  int   `json:"count"`
+		}
+		err := dao.TradingRobot.Ctx(ctx).
+			Fields("user_id, COUNT(1) AS count").
+			WhereIn(cols.UserId, batch).
+			Where(cols.Status, 2). // 运行中
+			WhereNull(cols.DeletedAt).
+			Group(cols.UserId).
+			Scan(&rows)
+		if err != nil {
+			return nil, gerror.Wrap(err, "统计团队机器人失败")
+		}
+		for _, row := range rows {
+			counts[row.UserId] = row.Count
+		}
+	}
+	return counts, nil
+}
+
+// topContributors 贡献佣金最多的成员
+func topContributors(ctx context.Context, contribution, revenue map[int64]float64, depthOf map[int64]int, limit int) ([]*toogoin.AgentTopMember, error) {
+	top := make([]*toogoin.AgentTopMember, 0, len(contribution))
+	for id, amount := range contribution {
+		if amount <= 0 {
+			continue
+		}
+		top = append(top, &toogoin.AgentTopMember{
+			MemberId:            id,
+			Level:               depthOf[id],
+			SubscriptionRevenue: revenue[id],
+			Commission:          amount,
+		})
+	}
+	sort.Slice(top, func(i, j int) bool { return top[i].Commission > top[j].Commission })
+	if len(top) > limit {
+		top = top[:limit]
+	}
+
+	ids := make([]int64, 0, len(top))
+	for _, m := range top {
+		ids = append(ids, m.MemberId)
+	}
+	names, err := memberUsernames(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range top {
+		m.Username = names[m.MemberId]
+	}
+	return top, nil
+}
+
+// memberUsernames 批量获取用户名
+func memberUsernames(ctx context.Context, ids []int64) (map[int64]string, error) {
+	names := make(map[int64]string, len(ids))
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+	for batch := range slices.Chunk(ids, teamIdBatch) {
+		var members []*entity.AdminMember
+		err := dao.AdminMember.Ctx(ctx).
+			Fields("id, username").
+			WhereIn(dao.AdminMember.Columns().Id, batch).
+			Scan(&members)
+		if err != nil {
+			return nil, gerror.Wrap(err, "查询用户名失败")
+		}
+		for _, m := range members {
+			names[m.Id] = m.Username
+		}
+	}
+	return names, nil
+}
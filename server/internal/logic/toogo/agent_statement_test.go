@@ -0,0 +1,106 @@
+//go:build integration
+// +build integration
+
+// Package toogo
+// @Description 代理月度对账单核对测试（需要数据库）
+package toogo
+
+import (
+	_ "github.com/gogf/gf/contrib/drivers/mysql/v2"
+
+	"math"
+	"testing"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/grand"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/toogoin"
+	"hotgo/internal/service"
+)
+
+// TestAgentStatementPartialReversal 佣金部分冲正后再解冻，对账单本月解冻与解冻流水一致
+func TestAgentStatementPartialReversal(t *testing.T) {
+	var (
+		ctx      = gctx.New()
+		agentId  = int64(grand.N(900000000, 949999999))
+		orderSn  = "TEST" + grand.Digits(16)
+		logCols  = dao.ToogoCommissionLog.Columns()
+		userCols = dao.ToogoUser.Columns()
+		walCols  = dao.ToogoWallet.Columns()
+	)
+
+	if _, err := dao.ToogoUser.Ctx(ctx).Data(g.Map{userCols.MemberId: agentId}).Insert(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dao.ToogoWallet.Ctx(ctx).Data(g.Map{walCols.UserId: agentId}).Insert(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_, _ = dao.ToogoCommissionLog.Ctx(ctx).Where(logCols.UserId, agentId).Delete()
+		_, _ = dao.ToogoWalletLog.Ctx(ctx).Where(dao.ToogoWalletLog.Columns().UserId, agentId).Delete()
+		_, _ = dao.ToogoWallet.Ctx(ctx).Where(walCols.UserId, agentId).Delete()
+		_, _ = dao.ToogoUser.Ctx(ctx).Where(userCols.MemberId, agentId).Delete()
+	}()
+
+	// 冻结佣金 10，来源订单退款 40% 冲正 4，解冻剩余 6
+	id, err := dao.ToogoCommissionLog.Ctx(ctx).Data(&entity.ToogoCommissionLog{
+		UserId:           agentId,
+		CommissionType:   "subscribe",
+		Level:            1,
+		CommissionAmount: 10,
+		SettleType:       "usdt",
+		Status:           consts.CommissionStatusFrozen,
+		OrderSn:          orderSn,
+		ReleaseTime:      gtime.Now(),
+		CreatedAt:        gtime.Now(),
+	}).InsertAndGetId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = service.ToogoWallet().ChangeBalance(ctx, &toogoin.ChangeBalanceInp{
+		UserId:      agentId,
+		AccountType: "frozen_commission",
+		ChangeType:  "subscribe",
+		Amount:      10,
+		RelatedId:   id,
+		RelatedType: "commission_log",
+		OrderSn:     orderSn,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewToogoCommission()
+	if _, err = s.ReverseOrderCommission(ctx, orderSn, "test", 0.4); err != nil {
+		t.Fatal(err)
+	}
+	var log *entity.ToogoCommissionLog
+	if err = dao.ToogoCommissionLog.Ctx(ctx).Where(logCols.Id, id).Scan(&log); err != nil {
+		t.Fatal(err)
+	}
+	released, err := s.releaseCommission(ctx, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(released-6) > reconcileEpsilon {
+		t.Fatalf("解冻金额 = %v, 期望 6", released)
+	}
+
+	res, err := s.AgentStatement(ctx, &toogoin.AgentStatementInp{MemberId: agentId, Month: gtime.Now().Format("Y-m")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(res.Released-6) > reconcileEpsilon || math.Abs(res.LedgerReleased-6) > reconcileEpsilon {
+		t.Errorf("本月解冻 = %v, 解冻流水 = %v, 期望均为 6", res.Released, res.LedgerReleased)
+	}
+	if math.Abs(res.NetCommission-6) > reconcileEpsilon {
+		t.Errorf("本月净佣金 = %v, 期望 6", res.NetCommission)
+	}
+	if !res.Reconciled {
+		t.Errorf("部分冲正后解冻的佣金应对账一致: %+v", res)
+	}
+}
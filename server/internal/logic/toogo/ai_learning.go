@@ -0,0 +1,453 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 信号权重自适应学习：按平仓订单实际结果统计各信号来源准确率，在管理员设定的边界内调整权重
+package toogo
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"hotgo/internal/dao"
+	configlib "hotgo/internal/library/config"
+	"hotgo/internal/library/market"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/toogoin"
+	"hotgo/internal/service"
+)
+
+// aiLearningBatchSize 每轮最多计入的订单数
+const aiLearningBatchSize = 500
+
+// aiLearningRiskSources 参与学习的风险维度（账户/历史得分依赖实时账户数据，快照中没有，保持默认权重）
+var aiLearningRiskSources = []string{market.RiskDimMarket, market.RiskDimTechnical, market.RiskDimVolatility}
+
+type sToogoAiLearning struct{}
+
+func NewToogoAiLearning() *sToogoAiLearning {
+	return &sToogoAiLearning{}
+}
+
+func init() {
+	service.RegisterToogoAiLearning(NewToogoAiLearning())
+}
+
+// aiLearningOrder 学习用的已平仓订单
+type aiLearningOrder struct {
+	Id             int64   `json:"id"`
+	Symbol         string  `json:"symbol"`
+	Direction      string  `json:"direction"`
+	RealizedProfit float64 `json:"realizedProfit"`
+	MarketState    string  `json:"marketState"`
+	RiskLevel      string  `json:"riskLevel"`
+	SignalSnapshot string  `json:"signalSnapshot"`
+}
+
+// aiLearningKey 学习表唯一键
+type aiLearningKey struct {
+	Symbol         string
+	Source         string
+	MarketState    string
+	RiskPreference string
+}
+
+// Run 统计新平仓订单并重新计算学习权重（影子模式只生成报告，未启用时清空已生效权重后直接返回）
+func (s *sToogoAiLearning) Run(ctx context.Context) (res *toogoin.AiLearningRunModel, err error) {
+	mode, err := GetConfig().GetAiLearningMode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res = &toogoin.AiLearningRunModel{Mode: mode}
+	if mode != toogoin.AiLearningModeShadow && mode != toogoin.AiLearningModeLive {
+		market.GetSignalWeightLearner().Reset()
+		return
+	}
+
+	if res.LearnedOrders, err = s.collect(ctx); err != nil {
+		return nil, err
+	}
+
+	report, err := s.Report(ctx, &toogoin.AiLearningReportInp{})
+	if err != nil {
+		return nil, err
+	}
+	if err = s.saveLearnedWeights(ctx, report); err != nil {
+		return nil, err
+	}
+	res.Symbols = applyLearnedWeights(report, mode)
+	return
+}
+
+// LoadWeights 从学习表恢复已生效的学习权重（服务启动及定时刷新时调用）
+// 学习统计持久化在学习表，这里按与 Run 相同的口径重新计算，进程重启或学习任务运行在其他进程时权重保持一致
+func (s *sToogoAiLearning) LoadWeights(ctx context.Context) error {
+	mode, err := GetConfig().GetAiLearningMode(ctx)
+	if err != nil {
+		return err
+	}
+	if mode != toogoin.AiLearningModeLive {
+		market.GetSignalWeightLearner().Reset()
+		return nil
+	}
+	report, err := s.Report(ctx, &toogoin.AiLearningReportInp{})
+	if err != nil {
+		return err
+	}
+	applyLearnedWeights(report, mode)
+	return nil
+}
+
+// applyLearnedWeights 将学习报告写入权重注册表（仅正式模式生效），返回报告中的交易对
+func applyLearnedWeights(report *toogoin.AiLearningReportModel, mode string) (symbols []string) {
+	learner := market.GetSignalWeightLearner()
+	learner.Reset()
+	for _, item := range report.Symbols {
+		symbols = append(symbols, item.Symbol)
+		if mode != toogoin.AiLearningModeLive {
+			continue
+		}
+		learner.SetTimeframeWeights(item.Symbol, learnedWeights(item.Timeframes))
+		for state, sources := range item.RiskStates {
+			weights := learnedWeights(sources)
+			for dim, w := range market.DefaultRiskWeights {
+				if _, ok := weights[dim]; !ok {
+					weights[dim] = w
+				}
+			}
+			learner.SetRiskWeights(item.Symbol, state, weights)
+		}
+	}
+	return
+}
+
+// collect 将未学习的已平仓订单计入统计，并标记为已学习
+func (s *sToogoAiLearning) collect(ctx context.Context) (int, error) {
+	var orders []*aiLearningOrder
+	err := dao.TradingOrder.Ctx(ctx).
+		Fields("id, symbol, direction, realized_profit, market_state, risk_level, signal_snapshot").
+		Where("status", OrderStatusClosed).
+		Where("ai_learned", 0).
+		WhereNotNull("signal_snapshot").
+		WhereNot("signal_snapshot", "").
+		OrderAsc("id").
+		Limit(aiLearningBatchSize).
+		Scan(&orders)
+	if err != nil {
+		return 0, gerror.Wrap(err, "获取待学习订单失败")
+	}
+	if len(orders) == 0 {
+		return 0, nil
+	}
+
+	deltas := make(map[aiLearningKey]*market.SignalLearningStat)
+	ids := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.Id)
+		scoreLearningOrder(order, deltas)
+	}
+
+	err = dao.ToogoAiLearning.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		for key, delta := range deltas {
+			if err := upsertLearningStat(ctx, tx, key, delta); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Model(dao.TradingOrder.Table()).Ctx(ctx).
+			WhereIn("id", ids).
+			Data(g.Map{"ai_learned": 1}).
+			Update()
+		return err
+	})
+	if err != nil {
+		return 0, gerror.Wrap(err, "保存学习统计失败")
+	}
+	return len(orders), nil
+}
+
+// scoreLearningOrder 按订单实际结果给快照中的各信号来源打分
+// 周期：K线方向与订单实际有利方向一致为正确；风险维度：得分对胜负的判断（>=50看好）与结果一致为正确
+func scoreLearningOrder(order *aiLearningOrder, deltas map[aiLearningKey]*market.SignalLearningStat) {
+	if order.RealizedProfit == 0 {
+		return
+	}
+	var snap market.SignalSnapshot
+	if err := gjson.DecodeTo(order.SignalSnapshot, &snap); err != nil {
+		return
+	}
+
+	win := order.RealizedProfit > 0
+	favorable := "up"
+	if (order.Direction == "long") != win {
+		favorable = "down"
+	}
+
+	state := snap.MarketState
+	if state == "" {
+		state = normalizeMarketState(order.MarketState)
+	}
+	symbol := market.NormalizeSymbol(order.Symbol)
+	add := func(source string, correct bool) {
+		key := aiLearningKey{Symbol: symbol, Source: source, MarketState: state, RiskPreference: order.RiskLevel}
+		st, ok := deltas[key]
+		if !ok {
+			st = &market.SignalLearningStat{}
+			deltas[key] = st
+		}
+		st.TotalSignals++
+		if correct {
+			st.CorrectSignals++
+		}
+		st.TotalProfit += order.RealizedProfit
+	}
+
+	for _, interval := range market.LearningTimeframes {
+		if dir := snap.Directions[interval]; dir != "" {
+			add(interval, dir == favorable)
+		}
+	}
+	add(market.RiskDimMarket, (snap.MarketScore >= 50) == win)
+	add(market.RiskDimTechnical, (snap.TechnicalScore >= 50) == win)
+	add(market.RiskDimVolatility, (snap.VolatilityRisk < 50) == win)
+}
+
+// upsertLearningStat 累加一条学习统计
+func upsertLearningStat(ctx context.Context, tx gdb.TX, key aiLearningKey, delta *market.SignalLearningStat) error {
+	cols := dao.ToogoAiLearning.Columns()
+	var row *entity.ToogoAiLearning
+	err := tx.Model(dao.ToogoAiLearning.Table()).Ctx(ctx).
+		Where(cols.Symbol, key.Symbol).
+		Where(cols.TimeFrame, key.Source).
+		Where(cols.MarketState, key.MarketState).
+		Where(cols.RiskPreference, key.RiskPreference).
+		LockUpdate().
+		Scan(&row)
+	if err != nil {
+		return err
+	}
+
+	stat := *delta
+	if row != nil {
+		stat.TotalSignals += row.TotalSignals
+		stat.CorrectSignals += row.CorrectSignals
+		stat.TotalProfit += row.TotalProfit
+	}
+	data := g.Map{
+		cols.TotalSignals:   stat.TotalSignals,
+		cols.CorrectSignals: stat.CorrectSignals,
+		cols.AccuracyRate:   float64(stat.CorrectSignals) / float64(stat.TotalSignals),
+		cols.TotalProfit:    stat.TotalProfit,
+		cols.AvgProfit:      stat.TotalProfit / float64(stat.TotalSignals),
+		cols.LastUpdate:     gtime.Now(),
+	}
+
+	if row != nil {
+		_, err = tx.Model(dao.ToogoAiLearning.Table()).Ctx(ctx).Where(cols.Id, row.Id).Data(data).Update()
+		return err
+	}
+	data[cols.Symbol] = key.Symbol
+	data[cols.TimeFrame] = key.Source
+	data[cols.MarketState] = key.MarketState
+	data[cols.RiskPreference] = key.RiskPreference
+	data[cols.CreatedAt] = gtime.Now()
+	_, err = tx.Model(dao.ToogoAiLearning.Table()).Ctx(ctx).Data(data).Insert()
+	return err
+}
+
+// Report 学习报告：当前权重 vs 学习权重
+// 周期权重按交易对汇总全部市场状态/风险偏好的统计；风险维度权重按交易对+市场状态汇总。
+// 学习权重以当前配置为基准计算，不会在多轮学习之间累积漂移。
+func (s *sToogoAiLearning) Report(ctx context.Context, in *toogoin.AiLearningReportInp) (res *toogoin.AiLearningReportModel, err error) {
+	mode, err := GetConfig().GetAiLearningMode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bounds, riskBounds, err := s.bounds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mod := dao.ToogoAiLearning.Ctx(ctx)
+	if in.Symbol != "" {
+		mod = mod.Where(dao.ToogoAiLearning.Columns().Symbol, market.NormalizeSymbol(in.Symbol))
+	}
+	var rows []*entity.ToogoAiLearning
+	if err = mod.Scan(&rows); err != nil {
+		return nil, gerror.Wrap(err, "获取学习统计失败")
+	}
+
+	// symbol -> source -> stat；symbol -> state -> dim -> stat
+	tfStats := make(map[string]map[string]market.SignalLearningStat)
+	riskStats := make(map[string]map[string]map[string]market.SignalLearningStat)
+	for _, row := range rows {
+		if isLearningTimeframe(row.TimeFrame) {
+			if tfStats[row.Symbol] == nil {
+				tfStats[row.Symbol] = make(map[string]market.SignalLearningStat)
+			}
+			tfStats[row.Symbol][row.TimeFrame] = mergeLearningStat(tfStats[row.Symbol][row.TimeFrame], row)
+			continue
+		}
+		if riskStats[row.Symbol] == nil {
+			riskStats[row.Symbol] = make(map[string]map[string]market.SignalLearningStat)
+		}
+		if riskStats[row.Symbol][row.MarketState] == nil {
+			riskStats[row.Symbol][row.MarketState] = make(map[string]market.SignalLearningStat)
+		}
+		riskStats[row.Symbol][row.MarketState][row.TimeFrame] = mergeLearningStat(riskStats[row.Symbol][row.MarketState][row.TimeFrame], row)
+	}
+
+	symbols := make([]string, 0, len(tfStats))
+	for symbol := range tfStats {
+		symbols = append(symbols, symbol)
+	}
+	for symbol := range riskStats {
+		if _, ok := tfStats[symbol]; !ok {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+
+	res = &toogoin.AiLearningReportModel{Mode: mode}
+	for _, symbol := range symbols {
+		item := &toogoin.AiLearningSymbolModel{
+			Symbol:     symbol,
+			RiskStates: make(map[string][]*toogoin.AiLearningSourceModel),
+		}
+		base := timeframeBaseWeights(configlib.GetVolatilityConfigManager().GetConfig(symbol))
+		item.Timeframes = buildLearningSources(market.LearningTimeframes, base, tfStats[symbol], bounds)
+
+		riskBase := make(map[string]float64, len(aiLearningRiskSources))
+		for _, dim := range aiLearningRiskSources {
+			riskBase[dim] = market.DefaultRiskWeights[dim]
+		}
+		for state, stats := range riskStats[symbol] {
+			item.RiskStates[state] = buildLearningSources(aiLearningRiskSources, riskBase, stats, riskBounds)
+		}
+		res.Symbols = append(res.Symbols, item)
+	}
+	return
+}
+
+// saveLearnedWeights 将学习权重回写到学习表 trend_weight 字段
+func (s *sToogoAiLearning) saveLearnedWeights(ctx context.Context, report *toogoin.AiLearningReportModel) error {
+	cols := dao.ToogoAiLearning.Columns()
+	return dao.ToogoAiLearning.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		for _, item := range report.Symbols {
+			for _, src := range item.Timeframes {
+				if _, err := tx.Model(dao.ToogoAiLearning.Table()).Ctx(ctx).
+					Where(cols.Symbol, item.Symbol).
+					Where(cols.TimeFrame, src.Source).
+					Data(g.Map{cols.TrendWeight: src.LearnedWeight}).
+					Update(); err != nil {
+					return err
+				}
+			}
+			for state, sources := range item.RiskStates {
+				for _, src := range sources {
+					if _, err := tx.Model(dao.ToogoAiLearning.Table()).Ctx(ctx).
+						Where(cols.Symbol, item.Symbol).
+						Where(cols.TimeFrame, src.Source).
+						Where(cols.MarketState, state).
+						Data(g.Map{cols.TrendWeight: src.LearnedWeight}).
+						Update(); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// bounds 读取学习边界配置（未配置时使用默认值）
+func (s *sToogoAiLearning) bounds(ctx context.Context) (tf, risk market.SignalLearningBounds, err error) {
+	c := GetConfig()
+	values := make(map[string]float64)
+	for _, key := range []string{"min_samples", "max_step", "weight_min", "weight_max", "risk_weight_min", "risk_weight_max"} {
+		if values[key], err = c.GetFloat(ctx, "ai_learning", key); err != nil {
+			return
+		}
+	}
+	orDefault := func(key string, def float64) float64 {
+		if values[key] > 0 {
+			return values[key]
+		}
+		return def
+	}
+
+	tf = market.SignalLearningBounds{
+		MinSamples: int(orDefault("min_samples", 30)),
+		MaxStep:    orDefault("max_step", 0.05),
+		WeightMin:  orDefault("weight_min", 0.05),
+		WeightMax:  orDefault("weight_max", 0.5),
+	}
+	risk = tf
+	risk.WeightMin = orDefault("risk_weight_min", 0.05)
+	risk.WeightMax = orDefault("risk_weight_max", 0.4)
+	return
+}
+
+func buildLearningSources(sources []string, base map[string]float64, stats map[string]market.SignalLearningStat, bounds market.SignalLearningBounds) []*toogoin.AiLearningSourceModel {
+	learned := market.ProposeLearnedWeights(base, stats, bounds)
+	out := make([]*toogoin.AiLearningSourceModel, 0, len(sources))
+	for _, source := range sources {
+		st := stats[source]
+		item := &toogoin.AiLearningSourceModel{
+			Source:         source,
+			TotalSignals:   st.TotalSignals,
+			CorrectSignals: st.CorrectSignals,
+			TotalProfit:    st.TotalProfit,
+			CurrentWeight:  base[source],
+			LearnedWeight:  learned[source],
+		}
+		if st.TotalSignals > 0 {
+			item.AccuracyRate = float64(st.CorrectSignals) / float64(st.TotalSignals)
+			item.AvgProfit = st.TotalProfit / float64(st.TotalSignals)
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+func learnedWeights(sources []*toogoin.AiLearningSourceModel) map[string]float64 {
+	out := make(map[string]float64, len(sources))
+	for _, src := range sources {
+		out[src.Source] = src.LearnedWeight
+	}
+	return out
+}
+
+func mergeLearningStat(st market.SignalLearningStat, row *entity.ToogoAiLearning) market.SignalLearningStat {
+	st.TotalSignals += row.TotalSignals
+	st.CorrectSignals += row.CorrectSignals
+	st.TotalProfit += row.TotalProfit
+	return st
+}
+
+func isLearningTimeframe(source string) bool {
+	for _, interval := range market.LearningTimeframes {
+		if interval == source {
+			return true
+		}
+	}
+	return false
+}
+
+func timeframeBaseWeights(cfg *configlib.VolatilityConfig) map[string]float64 {
+	if cfg == nil {
+		return map[string]float64{}
+	}
+	return map[string]float64{
+		"1m":  cfg.Weight1m,
+		"5m":  cfg.Weight5m,
+		"15m": cfg.Weight15m,
+		"30m": cfg.Weight30m,
+		"1h":  cfg.Weight1h,
+	}
+}
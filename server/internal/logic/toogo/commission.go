@@ -7,7 +7,9 @@ package toogo
 import (
 	"context"
 	"fmt"
+	"hotgo/internal/consts"
 	"hotgo/internal/dao"
+	"hotgo/internal/library/contexts"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/toogoin"
 	"hotgo/internal/service"
@@ -15,6 +17,7 @@ import (
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
 )
 
 type sToogoCommission struct{}
@@ -41,6 +44,9 @@ func (s *sToogoCommission) CommissionLogList(ctx context.Context, in *toogoin.Co
 	if in.Level > 0 {
 		mod = mod.Where(cols.Level, in.Level)
 	}
+	if in.Status > 0 {
+		mod = mod.Where(cols.Status, in.Status)
+	}
 	if len(in.CreatedAt) == 2 {
 		mod = mod.WhereBetween(cols.CreatedAt, in.CreatedAt[0], in.CreatedAt[1])
 	}
@@ -121,6 +127,20 @@ func (s *sToogoCommission) CommissionStat(ctx context.Context, in *toogoin.Commi
 		Sum(cols.CommissionAmount)
 	res.SubscribeCommission = subscribeTotal
 
+	// 结算期内冻结中的佣金
+	frozenTotal, _ := dao.ToogoCommissionLog.Ctx(ctx).
+		Where(cols.UserId, in.UserId).
+		Where(cols.Status, consts.CommissionStatusFrozen).
+		Sum(cols.CommissionAmount)
+	res.FrozenCommission = frozenTotal
+
+	// 已冲正佣金（冲正记录为负数）
+	reversedTotal, _ := dao.ToogoCommissionLog.Ctx(ctx).
+		Where(cols.UserId, in.UserId).
+		WhereGT(cols.ReversalOf, 0).
+		Sum(cols.CommissionAmount)
+	res.ReversedCommission = -reversedTotal
+
 	return
 }
 
@@ -128,45 +148,55 @@ func (s *sToogoCommission) CommissionStat(ctx context.Context, in *toogoin.Commi
 type AgentWithRate struct {
 	UserId           int64
 	SubscribeRate    float64
+	PowerRate        float64
 	IsAgent          int
 	AgentUnlockLevel int // 层级解锁: 0=仅一级佣金, 1=无限级佣金
 }
 
-// GetAgentChainWithRates 获取完整的代理链及佣金比例（从消费者往上）
+// GetAgentChainWithRates 获取完整的代理链及佣金比例（从消费者往上，基于邀请关系闭包表）
 func (s *sToogoCommission) GetAgentChainWithRates(ctx context.Context, userId int64) []*AgentWithRate {
-	var result []*AgentWithRate
-	currentId := userId
-	visited := make(map[int64]bool) // 防止循环引用
+	ancestorIds, err := loadAncestors(ctx, userId)
+	if err != nil {
+		g.Log().Warningf(ctx, "[GetAgentChainWithRates] 查询上级链失败 userId=%d: %v", userId, err)
+		return nil
+	}
+	if len(ancestorIds) == 0 {
+		return nil
+	}
 
-	for {
-		if visited[currentId] {
-			break
-		}
-		visited[currentId] = true
+	var users []*entity.ToogoUser
+	err = dao.ToogoUser.Ctx(ctx).
+		WhereIn(dao.ToogoUser.Columns().MemberId, ancestorIds).
+		Scan(&users)
+	if err != nil {
+		g.Log().Warningf(ctx, "[GetAgentChainWithRates] 查询上级信息失败 userId=%d: %v", userId, err)
+		return nil
+	}
+	byId := make(map[int64]*entity.ToogoUser, len(users))
+	for _, u := range users {
+		byId[u.MemberId] = u
+	}
 
-		var user *entity.ToogoUser
-		err := dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, currentId).Scan(&user)
-		if err != nil || user == nil || user.InviterId == 0 {
+	tenantId := service.ToogoTenant().MemberTenantId(ctx, userId)
+	result := make([]*AgentWithRate, 0, len(ancestorIds))
+	for _, id := range ancestorIds {
+		inviter, ok := byId[id]
+		if !ok {
+			// 上级信息缺失时链路在此中断，与逐级查询时的行为一致
 			break
 		}
-
-		// 获取上级信息
-		var inviter *entity.ToogoUser
-		err = dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, user.InviterId).Scan(&inviter)
-		if err != nil || inviter == nil {
+		if inviter.TenantId != tenantId {
+			// 佣金不跨租户分配，链路在租户边界中断
 			break
 		}
-
 		result = append(result, &AgentWithRate{
 			UserId:           inviter.MemberId,
 			SubscribeRate:    inviter.SubscribeRate,
+			PowerRate:        inviter.PowerRate,
 			IsAgent:          inviter.IsAgent,
 			AgentUnlockLevel: inviter.AgentUnlockLevel,
 		})
-
-		currentId = inviter.MemberId
 	}
-
 	return result
 }
 
@@ -181,13 +211,53 @@ func (s *sToogoCommission) SettleSubscribeCommission(ctx context.Context, fromUs
 		return nil
 	}
 
+	for _, share := range levelDiffShares(ctx, agentChain, func(a *AgentWithRate) float64 { return a.SubscribeRate }) {
+		// 记录佣金
+		err := s.AddCommission(ctx, share.UserId, fromUserId, "subscribe", share.Level, amount, share.RateDiff, amount*(share.RateDiff/100), subscriptionId, "subscription", orderSn)
+		if err != nil {
+			g.Log().Warningf(ctx, "记录订阅佣金失败: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// SettlePowerCommission 结算算力消耗佣金（级差制，比例取 power_rate），返回佣金合计
+// amount 为用户实际消耗的付费算力（不含积分、不含欠费部分）
+func (s *sToogoCommission) SettlePowerCommission(ctx context.Context, fromUserId int64, amount float64, consumeId int64, orderSn string) (total float64, err error) {
+	if amount <= 0 {
+		return 0, nil
+	}
+	agentChain := s.GetAgentChainWithRates(ctx, fromUserId)
+	for _, share := range levelDiffShares(ctx, agentChain, powerRateOf) {
+		commissionAmount := amount * (share.RateDiff / 100)
+		if err = s.AddCommission(ctx, share.UserId, fromUserId, "power", share.Level, amount, share.RateDiff, commissionAmount, consumeId, "power_consume", orderSn); err != nil {
+			return total, err
+		}
+		total += commissionAmount
+	}
+	return total, nil
+}
+
+// commissionShare 级差佣金分配结果
+type commissionShare struct {
+	UserId   int64
+	Level    int     // 佣金层级，1=直推
+	RateDiff float64 // 级差比例(%)
+}
+
+func powerRateOf(a *AgentWithRate) float64 { return a.PowerRate }
+
+// levelDiffShares 按级差制计算代理链上每个代理应得的比例（层级解锁规则同 SettleSubscribeCommission）
+func levelDiffShares(ctx context.Context, agentChain []*AgentWithRate, rateOf func(*AgentWithRate) float64) (shares []*commissionShare) {
 	// 级差制计算：每个代理获得 (自己比例 - 下级比例) × 金额
 	// 下级比例指的是紧邻的下一个代理的比例（离消费者更近的）
 	prevRate := 0.0 // 下级比例，初始为0（消费者本身没有佣金比例）
 
 	for level, agent := range agentChain {
+		rate := rateOf(agent)
 		// 只有代理才能获得佣金
-		if agent.IsAgent != 1 || agent.SubscribeRate <= 0 {
+		if agent.IsAgent != 1 || rate <= 0 {
 			// 非代理或无比例，跳过但更新prevRate
 			continue
 		}
@@ -197,34 +267,24 @@ func (s *sToogoCommission) SettleSubscribeCommission(ctx context.Context, fromUs
 		// - level>0 表示二级及以上，需要解锁层级才能获得
 		if level > 0 && agent.AgentUnlockLevel == 0 {
 			// 未解锁层级，只能获得一级佣金，跳过更深层级
-			g.Log().Debugf(ctx, "[SettleSubscribeCommission] 代理 %d 未解锁层级，跳过第%d级佣金", agent.UserId, level+1)
+			g.Log().Debugf(ctx, "[levelDiffShares] 代理 %d 未解锁层级，跳过第%d级佣金", agent.UserId, level+1)
 			// 仍需更新prevRate以便上级正确计算级差
-			prevRate = agent.SubscribeRate
+			prevRate = rate
 			continue
 		}
 
 		// 计算级差
-		rateDiff := agent.SubscribeRate - prevRate
+		rateDiff := rate - prevRate
+		// 更新下级比例为当前代理的比例
+		prevRate = rate
 		if rateDiff <= 0 {
 			// 没有级差，跳过
-			prevRate = agent.SubscribeRate // 更新为当前比例供上级计算
 			continue
 		}
 
-		// 计算佣金
-		commissionAmount := amount * (rateDiff / 100)
-
-		// 记录佣金
-		err := s.AddCommission(ctx, agent.UserId, fromUserId, "subscribe", level+1, amount, rateDiff, commissionAmount, subscriptionId, "subscription", orderSn)
-		if err != nil {
-			g.Log().Warningf(ctx, "记录订阅佣金失败: %v", err)
-		}
-
-		// 更新下级比例为当前代理的比例
-		prevRate = agent.SubscribeRate
+		shares = append(shares, &commissionShare{UserId: agent.UserId, Level: level + 1, RateDiff: rateDiff})
 	}
-
-	return nil
+	return
 }
 
 // SettleInviteReward 发放邀请奖励（积分）
@@ -236,6 +296,10 @@ func (s *sToogoCommission) SettleInviteReward(ctx context.Context, inviterId int
 	var inviter *entity.ToogoUser
 	dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, inviterId).Scan(&inviter)
 	if inviter != nil {
+		// 邀请人所在租户的注册奖励配置
+		if v, err := GetConfig().GetTenantValue(ctx, inviter.TenantId, "invite", "register_reward"); err == nil && gconv.Float64(v) > 0 {
+			rewardPower = gconv.Float64(v)
+		}
 		var vipLevel *entity.ToogoVipLevel
 		dao.ToogoVipLevel.Ctx(ctx).Where(dao.ToogoVipLevel.Columns().Level, inviter.VipLevel).Scan(&vipLevel)
 		if vipLevel != nil && vipLevel.InviteRewardPower > 0 {
@@ -288,7 +352,7 @@ func (s *sToogoCommission) AddCommission(ctx context.Context, userId, fromUserId
 		CommissionRate:   rate,
 		CommissionAmount: amount,
 		SettleType:       "usdt",
-		Status:           2, // 已结算
+		Status:           consts.CommissionStatusSettled,
 		RelatedId:        relatedId,
 		RelatedType:      relatedType,
 		OrderSn:          orderSn,
@@ -300,22 +364,32 @@ func (s *sToogoCommission) AddCommission(ctx context.Context, userId, fromUserId
 		log.SettleType = "power"
 	}
 
+	// 订阅佣金进入结算期：先计入冻结佣金，到期由定时任务解冻入账，期间来源订单退款直接冲正
+	accountType := "commission"
+	remark := fmt.Sprintf("级差佣金(级差%.2f%%)", rate)
+	if holdDays := commissionHoldDays(ctx, userId); holdDays > 0 && commissionHeld(commissionType, log.SettleType) {
+		log.Status = consts.CommissionStatusFrozen
+		log.ReleaseTime = gtime.Now().AddDate(0, 0, holdDays)
+		accountType = "frozen_commission"
+		remark += fmt.Sprintf("，%s解冻", log.ReleaseTime.Format("Y-m-d H:i"))
+	}
+
 	_, err := dao.ToogoCommissionLog.Ctx(ctx).Data(log).Insert()
 	if err != nil {
 		return gerror.Wrap(err, "添加佣金记录失败")
 	}
 
-	// 如果是USDT佣金，增加佣金账户余额
+	// 如果是USDT佣金，增加佣金账户（或冻结佣金）余额
 	if log.SettleType == "usdt" && amount > 0 {
 		err = service.ToogoWallet().ChangeBalance(ctx, &toogoin.ChangeBalanceInp{
 			UserId:      userId,
-			AccountType: "commission",
+			AccountType: accountType,
 			ChangeType:  commissionType,
 			Amount:      amount,
 			RelatedId:   relatedId,
 			RelatedType: relatedType,
 			OrderSn:     orderSn,
-			Remark:      fmt.Sprintf("级差佣金(级差%.2f%%)", rate),
+			Remark:      remark,
 		})
 		if err != nil {
 			g.Log().Warningf(ctx, "增加佣金余额失败: %v", err)
@@ -708,6 +782,7 @@ func (s *sToogoCommission) AgentLevelEdit(ctx context.Context, in *toogoin.Agent
 	if in.Id > 0 {
 		_, err = dao.ToogoAgentLevel.Ctx(ctx).Where(dao.ToogoAgentLevel.Columns().Id, in.Id).Data(data).Update()
 	} else {
+		data[cols.TenantId] = contexts.GetTenantId(ctx)
 		_, err = dao.ToogoAgentLevel.Ctx(ctx).Data(data).Insert()
 	}
 
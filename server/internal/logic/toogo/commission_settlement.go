@@ -0,0 +1,278 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 代理佣金结算期与冲正：订阅佣金先冻结，结算期满解冻入账；来源订单退款时冲正
+package toogo
+
+import (
+	"context"
+	"fmt"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/toogoin"
+	"hotgo/internal/service"
+	"math"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// commissionReleaseBatch 每轮最多解冻的佣金记录数
+const commissionReleaseBatch = 500
+
+// commissionHoldDays 佣金结算期(天)，0=立即入账
+// 按佣金所属用户的租户配置 commission.hold_days，未配置时使用 toogo.commission.holdDays
+func commissionHoldDays(ctx context.Context, userId int64) int {
+	days := g.Cfg().MustGet(ctx, "toogo.commission.holdDays", 7).Int()
+	tenantId := service.ToogoTenant().MemberTenantId(ctx, userId)
+	if v, err := GetConfig().GetTenantValue(ctx, tenantId, "commission", "hold_days"); err == nil && v != "" {
+		days = gconv.Int(v)
+	}
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// commissionHeld 是否进入结算期冻结：仅订阅佣金（来源订单可退款），算力消耗佣金与邀请奖励立即入账
+func commissionHeld(commissionType, settleType string) bool {
+	return commissionType == "subscribe" && settleType == "usdt"
+}
+
+// ReleaseCommission 解冻结算期已满的佣金（定时任务调用），返回解冻笔数与金额
+func (s *sToogoCommission) ReleaseCommission(ctx context.Context) (count int, amount float64, err error) {
+	cols := dao.ToogoCommissionLog.Columns()
+	var logs []*entity.ToogoCommissionLog
+	err = dao.ToogoCommissionLog.Ctx(ctx).
+		Where(cols.Status, consts.CommissionStatusFrozen).
+		WhereLTE(cols.ReleaseTime, gtime.Now()).
+		OrderAsc(cols.Id).
+		Limit(commissionReleaseBatch).
+		Scan(&logs)
+	if err != nil {
+		return 0, 0, gerror.Wrap(err, "查询待解冻佣金失败")
+	}
+
+	for _, log := range logs {
+		released, err := s.releaseCommission(ctx, log)
+		if err != nil {
+			g.Log().Warningf(ctx, "[Commission] 解冻佣金失败 logId=%d, userId=%d: %v", log.Id, log.UserId, err)
+			continue
+		}
+		if released > 0 {
+			count++
+			amount += released
+		}
+	}
+	return
+}
+
+// releaseCommission 单笔佣金解冻：冻结佣金扣除已部分冲正的金额后转入佣金余额（已被全额冲正的跳过），返回解冻金额
+func (s *sToogoCommission) releaseCommission(ctx context.Context, log *entity.ToogoCommissionLog) (released float64, err error) {
+	cols := dao.ToogoCommissionLog.Columns()
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		result, err := dao.ToogoCommissionLog.Ctx(ctx).
+			Where(cols.Id, log.Id).
+			Where(cols.Status, consts.CommissionStatusFrozen).
+			Data(g.Map{
+				cols.Status:     consts.CommissionStatusSettled,
+				cols.ReleasedAt: gtime.Now(),
+			}).
+			Update()
+		if err != nil {
+			return gerror.Wrap(err, "更新佣金状态失败")
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return nil
+		}
+
+		// 状态更新持有行锁，部分冲正已提交或等待本事务结束，此时统计的剩余金额不会再变化
+		reversed, err := reversedCommission(ctx, log.Id)
+		if err != nil {
+			return err
+		}
+		amount := log.CommissionAmount - reversed
+		if amount <= 0 {
+			return nil
+		}
+		if _, err = dao.ToogoCommissionLog.Ctx(ctx).Where(cols.Id, log.Id).Data(cols.ReleasedAmount, amount).Update(); err != nil {
+			return gerror.Wrap(err, "更新解冻金额失败")
+		}
+
+		in := &toogoin.ChangeBalanceInp{
+			UserId:      log.UserId,
+			AccountType: "frozen_commission",
+			ChangeType:  consts.CommissionChangeRelease,
+			Amount:      -amount,
+			RelatedId:   log.Id,
+			RelatedType: "commission_log",
+			OrderSn:     log.OrderSn,
+			Remark:      "佣金结算期满解冻",
+		}
+		if err = service.ToogoWallet().ChangeBalance(ctx, in); err != nil {
+			return err
+		}
+		in.AccountType = "commission"
+		in.Amount = amount
+		if err = service.ToogoWallet().ChangeBalance(ctx, in); err != nil {
+			return err
+		}
+		released = amount
+		return nil
+	})
+	return
+}
+
+// reversedCommission 佣金记录已被冲正的金额合计（部分退款可能产生多笔冲正记录）
+func reversedCommission(ctx context.Context, logId int64) (float64, error) {
+	cols := dao.ToogoCommissionLog.Columns()
+	sum, err := dao.ToogoCommissionLog.Ctx(ctx).Where(cols.ReversalOf, logId).Sum(cols.CommissionAmount)
+	if err != nil {
+		return 0, gerror.Wrap(err, "查询佣金冲正记录失败")
+	}
+	return -sum, nil
+}
+
+// ReverseOrderCommission 按退款比例冲正来源订单产生的USDT佣金（订单退款时调用），返回冲正金额合计
+// ratio 为退款金额占订单金额的比例，>=1 时冲正全部剩余佣金；多次部分退款按原佣金金额累计冲正，不超过原佣金。
+// 冻结中的佣金从冻结佣金扣回；已解冻入账的从佣金余额扣回，余额不足时允许为负，由后续佣金抵扣
+func (s *sToogoCommission) ReverseOrderCommission(ctx context.Context, orderSn, reason string, ratio float64) (total float64, err error) {
+	if orderSn == "" || ratio <= 0 {
+		return 0, nil
+	}
+	cols := dao.ToogoCommissionLog.Columns()
+	var logs []*entity.ToogoCommissionLog
+	err = dao.ToogoCommissionLog.Ctx(ctx).
+		Where(cols.OrderSn, orderSn).
+		Where(cols.SettleType, "usdt").
+		Where(cols.ReversalOf, 0).
+		WhereIn(cols.Status, []int{consts.CommissionStatusFrozen, consts.CommissionStatusSettled}).
+		WhereGT(cols.CommissionAmount, 0).
+		Scan(&logs)
+	if err != nil {
+		return 0, gerror.Wrap(err, "查询订单佣金失败")
+	}
+	if len(logs) == 0 {
+		return 0, nil
+	}
+
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		for _, log := range logs {
+			reversed, err := s.reverseCommission(ctx, log, reason, ratio)
+			if err != nil {
+				return err
+			}
+			total += reversed
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	g.Log().Infof(ctx, "[Commission] 订单退款冲正佣金: orderSn=%s, records=%d, ratio=%.4f, total=%.4f, reason=%s", orderSn, len(logs), ratio, total, reason)
+	return total, nil
+}
+
+// reverseCommission 单笔佣金冲正：扣回佣金并写入负金额冲正记录，返回冲正金额
+// 累计冲正达到原佣金时原记录标记已冲正，否则保持原状态，解冻时只转入剩余部分
+func (s *sToogoCommission) reverseCommission(ctx context.Context, log *entity.ToogoCommissionLog, reason string, ratio float64) (float64, error) {
+	cols := dao.ToogoCommissionLog.Columns()
+	now := gtime.Now()
+
+	// 锁定原记录并确认状态未变，避免与解冻或并发退款交错
+	var current *entity.ToogoCommissionLog
+	err := dao.ToogoCommissionLog.Ctx(ctx).
+		Where(cols.Id, log.Id).
+		Where(cols.Status, log.Status).
+		LockUpdate().
+		Scan(&current)
+	if err != nil {
+		return 0, gerror.Wrap(err, "查询佣金记录失败")
+	}
+	if current == nil {
+		return 0, nil
+	}
+
+	reversed, err := reversedCommission(ctx, log.Id)
+	if err != nil {
+		return 0, err
+	}
+	remaining := math.Round((log.CommissionAmount-reversed)*1e4) / 1e4
+	if remaining <= 0 {
+		return 0, nil
+	}
+	amount := remaining
+	if ratio < 1 {
+		amount = math.Min(math.Round(log.CommissionAmount*ratio*1e4)/1e4, remaining)
+	}
+	if amount <= 0 {
+		return 0, nil
+	}
+
+	if amount >= remaining {
+		_, err = dao.ToogoCommissionLog.Ctx(ctx).
+			Where(cols.Id, log.Id).
+			Data(g.Map{
+				cols.Status:     consts.CommissionStatusReversed,
+				cols.ReversedAt: now,
+			}).
+			Update()
+		if err != nil {
+			return 0, gerror.Wrap(err, "更新佣金状态失败")
+		}
+	}
+
+	accountType := "commission"
+	if log.Status == consts.CommissionStatusFrozen {
+		accountType = "frozen_commission"
+	}
+	err = service.ToogoWallet().ChangeBalance(ctx, &toogoin.ChangeBalanceInp{
+		UserId:      log.UserId,
+		AccountType: accountType,
+		ChangeType:  consts.CommissionChangeReversal,
+		Amount:      -amount,
+		RelatedId:   log.Id,
+		RelatedType: "commission_log",
+		OrderSn:     log.OrderSn,
+		Remark:      fmt.Sprintf("来源订单退款冲正佣金：%s", reason),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// 扣减累计佣金
+	_, err = dao.ToogoWallet.Ctx(ctx).
+		Where(dao.ToogoWallet.Columns().UserId, log.UserId).
+		Decrement(dao.ToogoWallet.Columns().TotalCommission, amount)
+	if err != nil {
+		return 0, gerror.Wrap(err, "更新累计佣金失败")
+	}
+
+	_, err = dao.ToogoCommissionLog.Ctx(ctx).Data(&entity.ToogoCommissionLog{
+		UserId:           log.UserId,
+		FromUserId:       log.FromUserId,
+		CommissionType:   log.CommissionType,
+		Level:            log.Level,
+		BaseAmount:       -math.Round(log.BaseAmount*amount/log.CommissionAmount*1e4) / 1e4,
+		CommissionRate:   log.CommissionRate,
+		CommissionAmount: -amount,
+		SettleType:       log.SettleType,
+		Status:           consts.CommissionStatusSettled,
+		RelatedId:        log.RelatedId,
+		RelatedType:      log.RelatedType,
+		OrderSn:          log.OrderSn,
+		Remark:           reason,
+		ReversalOf:       log.Id,
+		CreatedAt:        now,
+	}).Insert()
+	if err != nil {
+		return 0, gerror.Wrap(err, "记录佣金冲正失败")
+	}
+	return amount, nil
+}
@@ -5,8 +5,13 @@ import (
 	"context"
 
 	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/text/gstr"
 	"hotgo/internal/dao"
+	"hotgo/internal/library/contexts"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/toogoin"
 )
 
 // ToogoConfig 系统配置服务
@@ -45,35 +50,99 @@ var ConfigGroups = []ConfigGroup{
 	{Key: "withdraw", Label: "提现配置"},
 	{Key: "invite", Label: "邀请配置"},
 	{Key: "robot", Label: "机器人配置"},
+	{Key: "ai_learning", Label: "AI学习配置"},
 }
 
-// GetGroups 获取配置分组
+// TenantConfigGroups 允许租户覆盖的配置分组（佣金规则、邀请奖励、提现规则），其余分组由平台统一管理
+var TenantConfigGroups = []string{"commission", "invite", "withdraw"}
+
+// GetGroups 获取配置分组，租户只能看到可覆盖的分组
 func (c *ToogoConfig) GetGroups(ctx context.Context) []ConfigGroup {
-	return ConfigGroups
+	if contexts.GetTenantId(ctx) == 0 {
+		return ConfigGroups
+	}
+	groups := make([]ConfigGroup, 0, len(TenantConfigGroups))
+	for _, group := range ConfigGroups {
+		if gstr.InArray(TenantConfigGroups, group.Key) {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// tenantModel 平台配置 + 租户覆盖配置
+func (c *ToogoConfig) tenantModel(ctx context.Context, tenantId int64) *gdb.Model {
+	cols := dao.ToogoConfig.Columns()
+	if tenantId == 0 {
+		return dao.ToogoConfig.Ctx(ctx).Where(cols.TenantId, 0)
+	}
+	return dao.ToogoConfig.Ctx(ctx).WhereIn(cols.TenantId, []int64{0, tenantId})
 }
 
-// GetList 获取配置列表
+// GetList 获取配置列表，租户覆盖的配置项替换平台配置项
 func (c *ToogoConfig) GetList(ctx context.Context, group string) ([]*ConfigItem, error) {
-	model := dao.ToogoConfig.Ctx(ctx)
+	tenantId := contexts.GetTenantId(ctx)
+	if tenantId > 0 && group != "" && !gstr.InArray(TenantConfigGroups, group) {
+		return []*ConfigItem{}, nil
+	}
+
+	model := c.tenantModel(ctx, tenantId)
 	if group != "" {
 		model = model.Where("group", group)
+	} else if tenantId > 0 {
+		model = model.WhereIn("group", TenantConfigGroups)
 	}
 
-	var list []*ConfigItem
-	err := model.OrderAsc("sort").OrderAsc("id").Scan(&list)
+	var rows []*entity.ToogoConfig
+	err := model.OrderAsc("sort").OrderAsc("id").Scan(&rows)
 	if err != nil {
 		return nil, err
 	}
 
+	overrides := make(map[string]*entity.ToogoConfig)
+	for _, row := range rows {
+		if row.TenantId > 0 {
+			overrides[row.Group+"."+row.Key] = row
+		}
+	}
+
+	list := make([]*ConfigItem, 0, len(rows))
+	for _, row := range rows {
+		if row.TenantId > 0 {
+			continue
+		}
+		item := &ConfigItem{
+			Id:          row.Id,
+			Group:       row.Group,
+			Key:         row.Key,
+			Value:       row.Value,
+			Type:        row.Type,
+			Name:        row.Name,
+			Description: row.Description,
+			Sort:        row.Sort,
+		}
+		if v, ok := overrides[row.Group+"."+row.Key]; ok {
+			item.Id = v.Id
+			item.Value = v.Value
+		}
+		list = append(list, item)
+	}
 	return list, nil
 }
 
-// Get 获取单个配置
+// Get 获取单个配置（当前租户）
 func (c *ToogoConfig) Get(ctx context.Context, group, key string) (*ConfigItem, error) {
+	return c.GetTenant(ctx, contexts.GetTenantId(ctx), group, key)
+}
+
+// GetTenant 获取指定租户的单个配置，租户未覆盖时使用平台配置
+func (c *ToogoConfig) GetTenant(ctx context.Context, tenantId int64, group, key string) (*ConfigItem, error) {
 	var item *ConfigItem
-	err := dao.ToogoConfig.Ctx(ctx).
+	err := c.tenantModel(ctx, tenantId).
 		Where("group", group).
 		Where("key", key).
+		OrderDesc(dao.ToogoConfig.Columns().TenantId).
+		Limit(1).
 		Scan(&item)
 	if err != nil {
 		return nil, err
@@ -81,6 +150,18 @@ func (c *ToogoConfig) Get(ctx context.Context, group, key string) (*ConfigItem,
 	return item, nil
 }
 
+// GetTenantValue 获取指定租户的配置值
+func (c *ToogoConfig) GetTenantValue(ctx context.Context, tenantId int64, group, key string) (string, error) {
+	item, err := c.GetTenant(ctx, tenantId, group, key)
+	if err != nil {
+		return "", err
+	}
+	if item == nil {
+		return "", nil
+	}
+	return item.Value, nil
+}
+
 // GetValue 获取配置值
 func (c *ToogoConfig) GetValue(ctx context.Context, group, key string) (string, error) {
 	item, err := c.Get(ctx, group, key)
@@ -121,15 +202,62 @@ func (c *ToogoConfig) GetBool(ctx context.Context, group, key string) (bool, err
 }
 
 // Update 更新配置
+// 平台直接更新平台配置；租户只能覆盖允许的分组，首次覆盖时按平台配置项生成租户配置
 func (c *ToogoConfig) Update(ctx context.Context, items []ConfigUpdateItem) error {
+	tenantId := contexts.GetTenantId(ctx)
+	cols := dao.ToogoConfig.Columns()
 	return g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
 		for _, item := range items {
-			_, err := dao.ToogoConfig.Ctx(ctx).
+			if tenantId > 0 && !gstr.InArray(TenantConfigGroups, item.Group) {
+				return gerror.Newf("配置分组[%s]由平台统一管理，租户不能修改", item.Group)
+			}
+
+			exist := tenantId == 0
+			if !exist {
+				count, err := dao.ToogoConfig.Ctx(ctx).
+					Where(cols.TenantId, tenantId).
+					Where("group", item.Group).
+					Where("key", item.Key).
+					Count()
+				if err != nil {
+					return err
+				}
+				exist = count > 0
+			}
+			if exist {
+				_, err := dao.ToogoConfig.Ctx(ctx).
+					Where(cols.TenantId, tenantId).
+					Where("group", item.Group).
+					Where("key", item.Key).
+					Data(g.Map{"value": item.Value}).
+					Update()
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			var base *entity.ToogoConfig
+			if err := dao.ToogoConfig.Ctx(ctx).
+				Where(cols.TenantId, 0).
 				Where("group", item.Group).
 				Where("key", item.Key).
-				Data(g.Map{"value": item.Value}).
-				Update()
-			if err != nil {
+				Scan(&base); err != nil {
+				return err
+			}
+			if base == nil {
+				return gerror.Newf("配置项[%s.%s]不存在", item.Group, item.Key)
+			}
+			if _, err := dao.ToogoConfig.Ctx(ctx).Data(g.Map{
+				cols.TenantId:    tenantId,
+				cols.Group:       base.Group,
+				cols.Key:         base.Key,
+				cols.Value:       item.Value,
+				cols.Type:        base.Type,
+				cols.Name:        base.Name,
+				cols.Description: base.Description,
+				cols.Sort:        base.Sort,
+			}).Insert(); err != nil {
 				return err
 			}
 		}
@@ -186,3 +314,19 @@ func (c *ToogoConfig) NeedInviteCode(ctx context.Context) (bool, error) {
 	return c.GetBool(ctx, "invite", "need_invite_code")
 }
 
+// GetAiLearningMode 获取信号权重学习模式：off/shadow/live
+func (c *ToogoConfig) GetAiLearningMode(ctx context.Context) (string, error) {
+	enabled, err := c.GetBool(ctx, "ai_learning", "enabled")
+	if err != nil || !enabled {
+		return toogoin.AiLearningModeOff, err
+	}
+	// 未配置时默认影子模式
+	shadow, err := c.GetValue(ctx, "ai_learning", "shadow_mode")
+	if err != nil {
+		return toogoin.AiLearningModeOff, err
+	}
+	if shadow == "" || g.NewVar(shadow).Bool() {
+		return toogoin.AiLearningModeShadow, nil
+	}
+	return toogoin.AiLearningModeLive, nil
+}
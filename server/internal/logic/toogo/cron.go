@@ -10,6 +10,8 @@ package toogo
 import (
 	"context"
 
+	"hotgo/internal/service"
+
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gcron"
 )
@@ -22,6 +24,23 @@ func RegisterOrderSyncCron(ctx context.Context) error {
 	return nil
 }
 
+// aiLearningWeightsCron 学习权重刷新任务名
+const aiLearningWeightsCron = "AiLearningWeightsReload"
+
+// RegisterAiLearningWeightsCron 启动时恢复学习权重，之后每5分钟刷新
+// 学习任务运行在定时任务进程，机器人引擎所在进程只能从学习表读取结果
+func RegisterAiLearningWeightsCron(ctx context.Context) error {
+	if err := service.ToogoAiLearning().LoadWeights(ctx); err != nil {
+		g.Log().Warningf(ctx, "[AiLearning] 恢复学习权重失败: %v", err)
+	}
+	_, err := gcron.AddSingleton(ctx, "0 */5 * * * *", func(ctx context.Context) {
+		if err := service.ToogoAiLearning().LoadWeights(ctx); err != nil {
+			g.Log().Warningf(ctx, "[AiLearning] 刷新学习权重失败: %v", err)
+		}
+	}, aiLearningWeightsCron)
+	return err
+}
+
 // RegisterAllCronTasks 注册所有定时任务
 func RegisterAllCronTasks(ctx context.Context) error {
 	// 1. 注册订单同步任务
@@ -29,7 +48,12 @@ func RegisterAllCronTasks(ctx context.Context) error {
 		return err
 	}
 
-	// 2. 其他定时任务可以在这里添加
+	// 2. 恢复信号权重学习结果，并定时同步其他进程（定时任务进程）的学习结果
+	if err := RegisterAiLearningWeightsCron(ctx); err != nil {
+		return err
+	}
+
+	// 3. 其他定时任务可以在这里添加
 	// ...
 
 	g.Log().Info(ctx, "[Cron] 所有定时任务注册完成")
@@ -39,5 +63,6 @@ func RegisterAllCronTasks(ctx context.Context) error {
 // StopAllCronTasks 停止所有定时任务
 func StopAllCronTasks(ctx context.Context) {
 	gcron.Stop("OrderSyncTask")
+	gcron.Stop(aiLearningWeightsCron)
 	g.Log().Info(ctx, "[Cron] 所有定时任务已停止")
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"hotgo/internal/library/exchange"
+	"hotgo/internal/library/market"
 )
 
 // RobotAnalyzer 机器人市场分析器
@@ -279,6 +280,15 @@ func (a *RobotAnalyzer) getTimeframeWeights(config *VolatilityConfig) map[string
 		}
 	}
 	
+	// 学习循环已生效的周期权重优先（影子模式下不生效）
+	if a.engine.Robot != nil {
+		for tf, w := range market.GetSignalWeightLearner().TimeframeWeights(a.engine.Robot.Symbol) {
+			if w > 0 {
+				weights[tf] = w
+			}
+		}
+	}
+
 	// 如果配置为空或权重都为0，使用全局默认权重
 	if len(weights) == 0 {
 		weights = TimeframeWeights
@@ -380,6 +380,57 @@ func SyncExchangeOpenOrdersToDB(ctx context.Context, robotId int64, platform str
 	return nil
 }
 
+// UpsertExchangeOrder 将主动下单/按ID查询得到的单个订单写入事实表（按robot维度）
+// 网格等直接调用交易所下单的模式通过此方法落库，订单展示与对账口径与WS事件一致。
+func UpsertExchangeOrder(ctx context.Context, robotId int64, o *exchange.Order) error {
+	if o == nil || strings.TrimSpace(o.OrderId) == "" {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	meta, err := getRobotMeta(ctx, robotId)
+	if err != nil {
+		return err
+	}
+	status := normalizeOrderStatus(meta.Platform, o.Status)
+	if status == "" {
+		status = "NEW"
+	}
+	updateTime := o.UpdateTime
+	if updateTime <= 0 {
+		updateTime = time.Now().UnixMilli()
+	}
+	data := g.Map{
+		"tenant_id":         meta.TenantId,
+		"user_id":           meta.UserId,
+		"robot_id":          meta.RobotId,
+		"api_config_id":     meta.ApiConfigId,
+		"platform":          meta.Platform,
+		"symbol":            meta.Symbol,
+		"exchange_order_id": strings.TrimSpace(o.OrderId),
+		"client_order_id":   strings.TrimSpace(o.ClientId),
+		"side":              strings.ToUpper(strings.TrimSpace(o.Side)),
+		"position_side":     strings.ToUpper(strings.TrimSpace(o.PositionSide)),
+		"order_type":        strings.ToUpper(strings.TrimSpace(o.Type)),
+		"reduce_only":       boolToTiny(o.ReduceOnly),
+		"price":             o.Price,
+		"quantity":          o.Quantity,
+		"filled_qty":        o.FilledQty,
+		"avg_price":         o.AvgPrice,
+		"status":            status,
+		"raw_status":        strings.TrimSpace(o.Status),
+		"is_open":           boolToTiny(status == "NEW" || status == "PARTIALLY_FILLED"),
+		"update_time":       updateTime,
+		"last_event_time":   time.Now().UnixMilli(),
+		"raw":               "",
+	}
+	if o.CreateTime > 0 {
+		data["create_time"] = o.CreateTime
+	}
+	return upsertExchangeOrder(ctx, meta.Platform, meta.ApiConfigId, o.OrderId, data)
+}
+
 // ---- parsing helpers ----
 
 type parsedOrder struct {
@@ -11,9 +11,12 @@ import (
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/util/grand"
+	"hotgo/internal/consts"
 	"hotgo/internal/dao"
+	"hotgo/internal/library/payment"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/toogoin"
+	"hotgo/internal/service"
 )
 
 // ToogoFinance 财务服务
@@ -51,12 +54,63 @@ func (f *ToogoFinance) CreateDeposit(ctx context.Context, in *toogoin.CreateDepo
 		return nil, gerror.Wrap(err, "创建充值订单失败")
 	}
 
-	return &toogoin.CreateDepositModel{
+	res := &toogoin.CreateDepositModel{
 		OrderSn:  orderSn,
 		Amount:   in.Amount,
 		Currency: in.Currency,
 		Network:  in.Network,
-	}, nil
+	}
+
+	// 使用用户所属租户的收款网关生成支付页面
+	gateway, err := service.ToogoTenant().NOWPayments(ctx, service.ToogoTenant().MemberTenantId(ctx, in.UserId))
+	if err != nil {
+		return nil, err
+	}
+	if gateway == nil {
+		return res, nil
+	}
+
+	invoice, err := gateway.CreateInvoice(ctx, &payment.CreateInvoiceReq{
+		PriceAmount:      in.Amount,
+		PriceCurrency:    "usd",
+		PayCurrency:      payment.GetCurrencyCode(in.Currency, in.Network),
+		IpnCallbackUrl:   g.Cfg().MustGet(ctx, "nowpayments.callbackUrl").String() + "/admin/payment/nowpayments/callback",
+		OrderId:          orderSn,
+		OrderDescription: fmt.Sprintf("充值 %.2f %s", in.Amount, in.Currency),
+	})
+	if err != nil {
+		return nil, gerror.Wrap(err, "创建支付订单失败")
+	}
+
+	_, err = dao.ToogoDeposit.Ctx(ctx).Where(dao.ToogoDeposit.Columns().OrderSn, orderSn).Data(g.Map{
+		dao.ToogoDeposit.Columns().PaymentChannel: "nowpayments",
+		dao.ToogoDeposit.Columns().PaymentId:      invoice.Id,
+		dao.ToogoDeposit.Columns().UpdatedAt:      gtime.Now(),
+	}).Update()
+	if err != nil {
+		return nil, gerror.Wrap(err, "更新充值订单失败")
+	}
+
+	res.PaymentUrl = invoice.InvoiceUrl
+	return res, nil
+}
+
+// verifyNOWPaymentsIPN 使用订单用户所属租户的收款凭证校验IPN签名
+func verifyNOWPaymentsIPN(ctx context.Context, userId int64, body []byte) error {
+	gateway, err := service.ToogoTenant().NOWPayments(ctx, service.ToogoTenant().MemberTenantId(ctx, userId))
+	if err != nil {
+		return err
+	}
+	if gateway == nil {
+		return gerror.New("收款网关未配置")
+	}
+	if gateway.IpnSecret == "" {
+		return gerror.New("收款网关未设置IPN密钥，拒绝回调")
+	}
+	if !gateway.VerifyIPN(g.RequestFromCtx(ctx).GetHeader("x-nowpayments-sig"), body) {
+		return gerror.New("IPN签名校验失败")
+	}
+	return nil
 }
 
 // DepositCallback 充值回调
@@ -113,6 +167,12 @@ func (f *ToogoFinance) DepositCallback(ctx context.Context, in *toogoin.DepositC
 
 // CreateWithdraw 创建提现申请
 func (f *ToogoFinance) CreateWithdraw(ctx context.Context, in *toogoin.CreateWithdrawInp) (*toogoin.CreateWithdrawModel, error) {
+	// 敏感操作二次验证
+	if err := service.SysTwoFactor().RequireVerified(ctx, in.UserId, consts.SensitiveOpWithdraw,
+		fmt.Sprintf("申请提现: account=%s, amount=%.4f %s, network=%s, to=%s", in.AccountType, in.Amount, in.Currency, in.Network, in.ToAddress)); err != nil {
+		return nil, err
+	}
+
 	// 获取提现配置
 	minAmount, _ := GetConfig().GetWithdrawMinAmount(ctx)
 	if in.Amount < minAmount {
@@ -295,6 +355,10 @@ func (f *ToogoFinance) HandleNOWPaymentsIPNCallback(ctx context.Context) error {
 	if err != nil || deposit == nil {
 		return gerror.Newf("订单不存在: %s", orderSn)
 	}
+	if err = verifyNOWPaymentsIPN(ctx, deposit.UserId, request.GetBody()); err != nil {
+		g.Log().Warningf(ctx, "[NOWPayments] IPN回调校验失败: orderSn=%s, err=%v", orderSn, err)
+		return err
+	}
 
 	// 根据状态处理
 	switch paymentStatus {
@@ -400,6 +464,10 @@ func (f *ToogoFinance) HandleNOWPaymentsPayoutIPNCallback(ctx context.Context) e
 	if err != nil || withdraw == nil {
 		return gerror.Newf("提现记录不存在: %s", batchOrderSn)
 	}
+	if err = verifyNOWPaymentsIPN(ctx, withdraw.UserId, request.GetBody()); err != nil {
+		g.Log().Warningf(ctx, "[NOWPayments] Payout IPN回调校验失败: batchId=%s, err=%v", batchOrderSn, err)
+		return err
+	}
 
 	// 根据状态处理
 	switch status {
@@ -0,0 +1,472 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 业绩费（高水位）结算：按成交流水净已实现盈亏周期结算算力消耗
+package toogo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/toogoin"
+	"hotgo/internal/service"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// 业绩费结算结果
+const (
+	PerformanceFeeCharged  = "charged"   // 已扣费，高水位上移
+	PerformanceFeeDeferred = "deferred"  // 应扣算力低于最小扣费，顺延到下期（高水位不动）
+	PerformanceFeeBelowHwm = "below_hwm" // 累计净盈亏未超过高水位（含亏损回本中）
+)
+
+// performanceFeeConfig 业绩费配置（toogo.performanceFee）
+type performanceFeeConfig struct {
+	Enabled     bool
+	ConsumeRate float64 // 消耗比例(%)，取 toogo.powerConsumePercent
+	StartAt     int64   // 计费起点(毫秒)，早于该时间的成交不计费
+	PeriodEnd   int64   // 本次结算截止(毫秒)，留出成交同步延迟
+}
+
+// firstPeriodStart 首次结算的机器人从计费起点开始；未配置起点时只回看一个周期，不追溯历史成交
+func (c *performanceFeeConfig) firstPeriodStart() int64 {
+	if c.StartAt > 0 {
+		return c.StartAt
+	}
+	return c.PeriodEnd - int64(24*time.Hour/time.Millisecond)
+}
+
+func getPerformanceFeeConfig(ctx context.Context) *performanceFeeConfig {
+	cfg := &performanceFeeConfig{
+		Enabled:     g.Cfg().MustGet(ctx, "toogo.performanceFee.enabled").Bool(),
+		ConsumeRate: g.Cfg().MustGet(ctx, "toogo.powerConsumePercent", consts.PowerConsumePercent).Float64(),
+	}
+	if cfg.ConsumeRate <= 0 {
+		cfg.ConsumeRate = consts.PowerConsumePercent
+	}
+	lag := g.Cfg().MustGet(ctx, "toogo.performanceFee.settleLagMinutes", 60).Int()
+	if lag < 0 {
+		lag = 0
+	}
+	cfg.PeriodEnd = time.Now().Add(-time.Duration(lag) * time.Minute).UnixMilli()
+
+	if startAt := g.Cfg().MustGet(ctx, "toogo.performanceFee.startAt").String(); startAt != "" {
+		if t, err := gtime.StrToTime(startAt); err == nil {
+			cfg.StartAt = t.TimestampMilli()
+		} else {
+			g.Log().Warningf(ctx, "[PerformanceFee] toogo.performanceFee.startAt 格式错误: %s", startAt)
+		}
+	}
+	return cfg
+}
+
+// PerformanceFeeReport 业绩费试算报告（不扣费、不写库），用于开启前核对
+func (s *sToogoWallet) PerformanceFeeReport(ctx context.Context, in *toogoin.PerformanceFeeInp) (*toogoin.PerformanceFeeReport, error) {
+	return s.settlePerformanceFee(ctx, in, getPerformanceFeeConfig(ctx), true)
+}
+
+// SettlePerformanceFee 业绩费结算
+// 规则：
+// - 每个用户+机器人累计净已实现盈亏（成交流水 realized_pnl - fee）超过历史高水位的部分才计费
+// - 亏损需先回本，手动平仓、盈亏对冲都无法规避
+// - 扣费 = 计费盈利 × 消耗比例 × (1 - VIP折扣)，优先扣积分，不足部分扣算力（允许为负，欠费后机器人无法启动/交易）
+// - 代理按 power_rate 级差获得实际扣除的付费算力佣金
+func (s *sToogoWallet) SettlePerformanceFee(ctx context.Context, in *toogoin.PerformanceFeeInp) (*toogoin.PerformanceFeeReport, error) {
+	cfg := getPerformanceFeeConfig(ctx)
+	if !cfg.Enabled {
+		return nil, gerror.New("业绩费结算未开启，请先通过试算报告核对后开启 toogo.performanceFee.enabled")
+	}
+	return s.settlePerformanceFee(ctx, in, cfg, false)
+}
+
+// performanceFeePair 待结算的用户+机器人
+type performanceFeePair struct {
+	UserId  int64 `orm:"user_id"`
+	RobotId int64 `orm:"robot_id"`
+}
+
+func (s *sToogoWallet) settlePerformanceFee(ctx context.Context, in *toogoin.PerformanceFeeInp, cfg *performanceFeeConfig, dryRun bool) (*toogoin.PerformanceFeeReport, error) {
+	report := &toogoin.PerformanceFeeReport{
+		DryRun:      dryRun,
+		PeriodEnd:   cfg.PeriodEnd,
+		ConsumeRate: cfg.ConsumeRate,
+		Items:       make([]*toogoin.PerformanceFeeItem, 0),
+	}
+
+	pairs, err := s.performanceFeePairs(ctx, in, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pair := range pairs {
+		item, err := s.settlePerformanceFeePair(ctx, pair, cfg, dryRun)
+		if err != nil {
+			// 单个机器人失败不影响其他机器人，下次结算从原游标重试
+			g.Log().Warningf(ctx, "[PerformanceFee] 结算失败 userId=%d, robotId=%d: %v", pair.UserId, pair.RobotId, err)
+			continue
+		}
+		if item == nil {
+			continue
+		}
+		report.Items = append(report.Items, item)
+		report.TotalChargeable += item.Chargeable
+		report.TotalAgentCommission += item.AgentCommission
+		if item.Status == PerformanceFeeCharged {
+			report.ChargedCount++
+			report.TotalConsumePower += item.ConsumePower
+			if item.PowerAfter < 0 {
+				report.TotalArrears += -item.PowerAfter
+			}
+		}
+	}
+	return report, nil
+}
+
+// performanceFeePairs 待结算列表：已有高水位状态的机器人 + 计费起点后有成交的新机器人
+func (s *sToogoWallet) performanceFeePairs(ctx context.Context, in *toogoin.PerformanceFeeInp, cfg *performanceFeeConfig) ([]*performanceFeePair, error) {
+	var (
+		pairs []*performanceFeePair
+		seen  = make(map[performanceFeePair]bool)
+		cols  = dao.ToogoPerformanceFee.Columns()
+	)
+
+	stateMod := dao.ToogoPerformanceFee.Ctx(ctx).Fields(cols.UserId, cols.RobotId).WhereLT(cols.SettledUntil, cfg.PeriodEnd)
+	if in.UserId > 0 {
+		stateMod = stateMod.Where(cols.UserId, in.UserId)
+	}
+	if in.RobotId > 0 {
+		stateMod = stateMod.Where(cols.RobotId, in.RobotId)
+	}
+	var states []*performanceFeePair
+	if err := stateMod.Scan(&states); err != nil {
+		return nil, gerror.Wrap(err, "查询业绩费状态失败")
+	}
+
+	fillMod := dao.TradingTradeFill.Ctx(ctx).
+		Fields("DISTINCT user_id, robot_id").
+		WhereGT("robot_id", 0).
+		Where(tradeFillTsRange, tradeFillTsRangeArgs(cfg.firstPeriodStart(), cfg.PeriodEnd)...)
+	if in.UserId > 0 {
+		fillMod = fillMod.Where("user_id", in.UserId)
+	}
+	if in.RobotId > 0 {
+		fillMod = fillMod.Where("robot_id", in.RobotId)
+	}
+	var fills []*performanceFeePair
+	if err := fillMod.Scan(&fills); err != nil {
+		return nil, gerror.Wrap(err, "查询待结算成交失败")
+	}
+
+	for _, p := range append(states, fills...) {
+		if seen[*p] {
+			continue
+		}
+		seen[*p] = true
+		pairs = append(pairs, p)
+	}
+	return pairs, nil
+}
+
+// tradeFillTsRange 成交时间区间 (startMs, endMs]，兼容历史秒级 ts
+const tradeFillTsRange = "((ts > ? AND ts <= ?) OR (ts > ? AND ts <= ? AND ts < ?))"
+
+func tradeFillTsRangeArgs(startMs, endMs int64) []interface{} {
+	const tsMsThreshold int64 = 1000000000000 // 1e12
+	return []interface{}{startMs, endMs, startMs / 1000, endMs / 1000, tsMsThreshold}
+}
+
+// settlePerformanceFeePair 结算单个用户+机器人；无新成交时返回 nil
+func (s *sToogoWallet) settlePerformanceFeePair(ctx context.Context, pair *performanceFeePair, cfg *performanceFeeConfig, dryRun bool) (item *toogoin.PerformanceFeeItem, err error) {
+	cols := dao.ToogoPerformanceFee.Columns()
+
+	var state *entity.ToogoPerformanceFee
+	err = dao.ToogoPerformanceFee.Ctx(ctx).
+		Where(cols.UserId, pair.UserId).
+		Where(cols.RobotId, pair.RobotId).
+		Scan(&state)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询业绩费状态失败")
+	}
+	if state == nil {
+		state = &entity.ToogoPerformanceFee{UserId: pair.UserId, RobotId: pair.RobotId, SettledUntil: cfg.firstPeriodStart()}
+	}
+	periodStart := state.SettledUntil
+	if periodStart < cfg.StartAt {
+		periodStart = cfg.StartAt
+	}
+	if periodStart >= cfg.PeriodEnd {
+		return nil, nil
+	}
+
+	// 本期净已实现盈亏（fee 以正数存储）
+	var agg struct {
+		TotalPnl   float64 `orm:"total_pnl"`
+		TotalFee   float64 `orm:"total_fee"`
+		TradeCount int     `orm:"trade_count"`
+	}
+	err = dao.TradingTradeFill.Ctx(ctx).
+		Fields("COALESCE(SUM(realized_pnl), 0) AS total_pnl, COALESCE(SUM(fee), 0) AS total_fee, COUNT(*) AS trade_count").
+		Where("user_id", pair.UserId).
+		Where("robot_id", pair.RobotId).
+		Where(tradeFillTsRange, tradeFillTsRangeArgs(periodStart, cfg.PeriodEnd)...).
+		Scan(&agg)
+	if err != nil {
+		return nil, gerror.Wrap(err, "汇总成交流水失败")
+	}
+	if agg.TradeCount == 0 {
+		return nil, nil
+	}
+
+	item = &toogoin.PerformanceFeeItem{
+		UserId:      pair.UserId,
+		RobotId:     pair.RobotId,
+		PeriodStart: periodStart,
+		PeriodEnd:   cfg.PeriodEnd,
+		TradeCount:  agg.TradeCount,
+		NetPnl:      roundPower(agg.TotalPnl - agg.TotalFee),
+		HwmBefore:   state.HighWaterMark,
+		HwmAfter:    state.HighWaterMark,
+		ConsumeRate: cfg.ConsumeRate,
+		Status:      PerformanceFeeBelowHwm,
+	}
+	item.CumPnl = roundPower(state.CumPnl + item.NetPnl)
+
+	var robot *entity.TradingRobot
+	_ = dao.TradingRobot.Ctx(ctx).Fields("robot_name").Where("id", pair.RobotId).Scan(&robot)
+	if robot != nil {
+		item.RobotName = robot.RobotName
+	}
+
+	if item.CumPnl > state.HighWaterMark {
+		item.Chargeable = roundPower(item.CumPnl - state.HighWaterMark)
+		item.VipLevel, item.DiscountRate = s.performanceFeeDiscount(ctx, pair.UserId)
+		item.OriginalPower = roundPower(item.Chargeable * cfg.ConsumeRate / 100)
+		item.ConsumePower = roundPower(item.OriginalPower * (1 - item.DiscountRate/100))
+		if item.ConsumePower >= consts.MinPowerConsume {
+			item.Status = PerformanceFeeCharged
+			item.HwmAfter = item.CumPnl
+		} else {
+			item.Status = PerformanceFeeDeferred
+		}
+	}
+
+	wallet, err := s.GetOrCreate(ctx, pair.UserId)
+	if err != nil {
+		return nil, err
+	}
+	splitPerformanceFee(item, wallet)
+
+	if dryRun {
+		if item.Status == PerformanceFeeCharged {
+			item.AgentCommission = s.previewPowerCommission(ctx, pair.UserId, paidPowerPortion(item.FromPower, wallet.Power))
+		}
+		return item, nil
+	}
+
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		return s.applyPerformanceFee(ctx, state, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// applyPerformanceFee 在事务内推进高水位状态并扣费
+func (s *sToogoWallet) applyPerformanceFee(ctx context.Context, state *entity.ToogoPerformanceFee, item *toogoin.PerformanceFeeItem) error {
+	cols := dao.ToogoPerformanceFee.Columns()
+	now := gtime.Now()
+	charged := 0.0
+	if item.Status == PerformanceFeeCharged {
+		charged = item.ConsumePower
+	}
+
+	// 先推进状态（乐观锁：游标未被其他结算推进），并发重复结算时整体回滚
+	data := g.Map{
+		cols.CumPnl:            item.CumPnl,
+		cols.HighWaterMark:     item.HwmAfter,
+		cols.SettledUntil:      item.PeriodEnd,
+		cols.TotalConsumePower: roundPower(state.TotalConsumePower + charged),
+		cols.LastSettleAt:      now,
+		cols.UpdatedAt:         now,
+	}
+	if state.Id == 0 {
+		data[cols.UserId] = state.UserId
+		data[cols.RobotId] = state.RobotId
+		data[cols.CreatedAt] = now
+		if _, err := dao.ToogoPerformanceFee.Ctx(ctx).Data(data).Insert(); err != nil {
+			return gerror.Wrap(err, "创建业绩费状态失败")
+		}
+	} else {
+		result, err := dao.ToogoPerformanceFee.Ctx(ctx).
+			Where(cols.Id, state.Id).
+			Where(cols.SettledUntil, state.SettledUntil).
+			Data(data).
+			Update()
+		if err != nil {
+			return gerror.Wrap(err, "更新业绩费状态失败")
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return gerror.New("业绩费状态已被其他结算更新")
+		}
+	}
+
+	if item.Status != PerformanceFeeCharged {
+		return nil
+	}
+
+	orderSn := fmt.Sprintf("PF%s%d", gtime.Now().Format("YmdHis"), item.RobotId)
+	remark := fmt.Sprintf("业绩费结算(机器人%d,计费盈利%.4f)", item.RobotId, item.Chargeable)
+
+	// 事务内按最新余额重新拆分积分/算力
+	wallet, err := s.GetOrCreate(ctx, item.UserId)
+	if err != nil {
+		return err
+	}
+	splitPerformanceFee(item, wallet)
+	if item.FromGiftPower > 0 {
+		err = s.ChangeBalance(ctx, &toogoin.ChangeBalanceInp{
+			UserId:      item.UserId,
+			AccountType: "gift_power",
+			ChangeType:  "performance_fee",
+			Amount:      -item.FromGiftPower,
+			RelatedId:   item.RobotId,
+			RelatedType: "robot",
+			OrderSn:     orderSn,
+			Remark:      remark,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if item.FromPower > 0 {
+		err = s.ChangeBalance(ctx, &toogoin.ChangeBalanceInp{
+			UserId:      item.UserId,
+			AccountType: "power",
+			ChangeType:  "performance_fee",
+			Amount:      -item.FromPower,
+			RelatedId:   item.RobotId,
+			RelatedType: "robot",
+			OrderSn:     orderSn,
+			Remark:      remark,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	consumeId, err := dao.ToogoPowerConsume.Ctx(ctx).Data(&entity.ToogoPowerConsume{
+		UserId:        item.UserId,
+		RobotId:       item.RobotId,
+		OrderSn:       orderSn,
+		SettleType:    "hwm",
+		PeriodStart:   item.PeriodStart,
+		PeriodEnd:     item.PeriodEnd,
+		NetPnl:        item.NetPnl,
+		HwmBefore:     item.HwmBefore,
+		HwmAfter:      item.HwmAfter,
+		ProfitAmount:  item.Chargeable,
+		ConsumeRate:   item.ConsumeRate,
+		ConsumePower:  item.ConsumePower,
+		FromPower:     item.FromPower,
+		FromGiftPower: item.FromGiftPower,
+		PowerAfter:    item.PowerAfter,
+		VipLevel:      item.VipLevel,
+		DiscountRate:  item.DiscountRate,
+		OriginalPower: item.OriginalPower,
+		CreatedAt:     gtime.Now(),
+	}).InsertAndGetId()
+	if err != nil {
+		return gerror.Wrap(err, "记录算力消耗失败")
+	}
+	item.ConsumeId = consumeId
+
+	// 累计消耗：钱包、用户本人及上级团队（用于VIP升级）
+	_, err = dao.ToogoWallet.Ctx(ctx).
+		Where(dao.ToogoWallet.Columns().UserId, item.UserId).
+		Increment(dao.ToogoWallet.Columns().TotalPowerConsume, item.ConsumePower)
+	if err != nil {
+		return gerror.Wrap(err, "更新累计消耗算力失败")
+	}
+	_, err = dao.ToogoUser.Ctx(ctx).
+		Where(dao.ToogoUser.Columns().MemberId, item.UserId).
+		Increment(dao.ToogoUser.Columns().TotalConsumePower, item.ConsumePower)
+	if err != nil {
+		return gerror.Wrap(err, "更新用户消耗算力失败")
+	}
+	ancestorIds, err := loadAncestors(ctx, item.UserId)
+	if err != nil {
+		return gerror.Wrap(err, "查询上级链失败")
+	}
+	if len(ancestorIds) > 0 {
+		_, err = dao.ToogoUser.Ctx(ctx).
+			WhereIn(dao.ToogoUser.Columns().MemberId, ancestorIds).
+			Increment(dao.ToogoUser.Columns().TeamConsumePower, item.ConsumePower)
+		if err != nil {
+			return gerror.Wrap(err, "更新团队消耗算力失败")
+		}
+	}
+
+	// 代理佣金只按付费算力计算：积分与欠费部分不返佣
+	item.AgentCommission, err = service.ToogoCommission().SettlePowerCommission(ctx, item.UserId, paidPowerPortion(item.FromPower, wallet.Power), consumeId, orderSn)
+	return err
+}
+
+// splitPerformanceFee 优先扣积分，不足部分扣算力（算力允许扣成负数）
+func splitPerformanceFee(item *toogoin.PerformanceFeeItem, wallet *entity.ToogoWallet) {
+	item.FromGiftPower, item.FromPower = 0, 0
+	if item.Status == PerformanceFeeCharged {
+		item.FromGiftPower = math.Min(item.ConsumePower, math.Max(wallet.GiftPower, 0))
+		item.FromPower = roundPower(item.ConsumePower - item.FromGiftPower)
+	}
+	item.PowerAfter = roundPower(wallet.Power - item.FromPower)
+}
+
+// performanceFeeDiscount 用户折扣：VIP等级折扣与用户单独折扣取大
+func (s *sToogoWallet) performanceFeeDiscount(ctx context.Context, userId int64) (vipLevel int, discount float64) {
+	var user *entity.ToogoUser
+	_ = dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, userId).Scan(&user)
+	if user == nil {
+		return 0, 0
+	}
+	vipLevel, discount = user.VipLevel, user.PowerDiscount
+
+	var level *entity.ToogoVipLevel
+	_ = dao.ToogoVipLevel.Ctx(ctx).Where(dao.ToogoVipLevel.Columns().Level, user.VipLevel).Scan(&level)
+	if level != nil && level.PowerDiscount > discount {
+		discount = level.PowerDiscount
+	}
+	return vipLevel, math.Min(math.Max(discount, 0), 100)
+}
+
+// previewPowerCommission 试算代理算力佣金
+func (s *sToogoWallet) previewPowerCommission(ctx context.Context, userId int64, amount float64) (total float64) {
+	if amount <= 0 {
+		return 0
+	}
+	agentChain := NewToogoCommission().GetAgentChainWithRates(ctx, userId)
+	for _, share := range levelDiffShares(ctx, agentChain, powerRateOf) {
+		total += amount * (share.RateDiff / 100)
+	}
+	return total
+}
+
+// paidPowerPortion 扣除的算力中有余额支撑的部分（欠费部分不计佣金）
+func paidPowerPortion(fromPower, powerBefore float64) float64 {
+	return math.Min(fromPower, math.Max(powerBefore, 0))
+}
+
+// roundPower 算力保留8位小数，与库字段 decimal(20,8) 一致
+func roundPower(v float64) float64 {
+	return math.Round(v*1e8) / 1e8
+}
@@ -9,12 +9,10 @@ import (
 	"sync"
 	"time"
 
-	"hotgo/internal/dao"
 	"hotgo/internal/library/exchange"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/service"
 	"hotgo/internal/websocket"
-	"hotgo/utility/encrypt"
 
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -113,10 +111,8 @@ func (m *PrivateStreamManager) Acquire(ctx context.Context, apiConfig *entity.Tr
 		return err
 	}
 
-	// proxy dialer（复用 RobotTaskManager 的全局代理配置）
-	if dialer, err := getWebSocketDialer(ctx); err == nil && dialer != nil {
-		ps.SetProxyDialer(dialer)
-	}
+	// proxy dialer（与REST共用该API Key在代理池中的粘性出口）
+	ps.SetProxyDialer(getWebSocketDialer(ctx, cfg.ProxyBinding))
 	ps.SetOnEvent(func(ev *exchange.PrivateEvent) {
 		if ev != nil {
 			ev.ApiConfigId = apiConfig.Id
@@ -337,6 +333,12 @@ func (m *PrivateStreamManager) onEvent(ev *exchange.PrivateEvent) {
 
 	now := time.Now()
 	for _, robotId := range targets {
+		// 网格挂单成交必须逐条处理（不参与去抖），由 GridTrader 按订单ID匹配格子
+		if ev.Type == exchange.PrivateEventOrder {
+			if engine := GetRobotTaskManager().GetEngine(robotId); engine != nil && engine.Grid != nil {
+				go engine.Grid.OnOrderEvent(context.Background(), ev)
+			}
+		}
 		// debounce 200ms
 		m.mu.Lock()
 		last := m.robotDebounce[robotId]
@@ -901,66 +903,16 @@ func (m *PrivateStreamManager) tryUpsertBinanceTradeFillFromOrderEvent(ctx conte
 	_, _, _ = upsertTradeFillsFromTrades(ctx, ev.ApiConfigId, "binance", symbol, []*exchange.Trade{trade}, nil)
 }
 
-// buildExchangeConfigFromAPIConfig 构建 exchange.Config（解密字段，补代理）
+// buildExchangeConfigFromAPIConfig 构建 exchange.Config（凭证经 vault 解密，出口走代理池）
 func buildExchangeConfigFromAPIConfig(ctx context.Context, apiConfig *entity.TradingApiConfig) (*exchange.Config, error) {
 	if apiConfig == nil {
 		return nil, gerror.New("apiConfig is nil")
 	}
 
-	apiKey, err := encrypt.AesDecrypt(apiConfig.ApiKey)
+	cfg, err := exchange.ConfigFromApiConfig(ctx, apiConfig, nil)
 	if err != nil {
-		apiKey = apiConfig.ApiKey
+		return nil, err
 	}
-	secretKey, err := encrypt.AesDecrypt(apiConfig.SecretKey)
-	if err != nil {
-		secretKey = apiConfig.SecretKey
-	}
-	passphrase := ""
-	if apiConfig.Passphrase != "" {
-		p, err := encrypt.AesDecrypt(apiConfig.Passphrase)
-		if err != nil {
-			passphrase = apiConfig.Passphrase
-		} else {
-			passphrase = p
-		}
-	}
-
-	// 代理配置（全局）
-	var proxyCfg *exchange.ProxyConfig
-	var proxyEnt *entity.TradingProxyConfig
-	_ = dao.TradingProxyConfig.Ctx(ctx).
-		Where(dao.TradingProxyConfig.Columns().UserId, 0).
-		Where(dao.TradingProxyConfig.Columns().TenantId, 0).
-		Where(dao.TradingProxyConfig.Columns().Enabled, 1).
-		Scan(&proxyEnt)
-	if proxyEnt != nil {
-		host := proxyEnt.ProxyAddress
-		port := 0
-		if idx := strings.Index(proxyEnt.ProxyAddress, ":"); idx > 0 {
-			host = proxyEnt.ProxyAddress[:idx]
-			port = g.NewVar(proxyEnt.ProxyAddress[idx+1:]).Int()
-		}
-		proxyCfg = &exchange.ProxyConfig{
-			Enabled:  true,
-			Type:     proxyEnt.ProxyType,
-			Host:     host,
-			Port:     port,
-			Username: proxyEnt.Username,
-			Password: "",
-		}
-		if proxyEnt.AuthEnabled == 1 && proxyEnt.Password != "" {
-			if pwd, err := encrypt.AesDecrypt(proxyEnt.Password); err == nil {
-				proxyCfg.Password = pwd
-			}
-		}
-	}
-
-	return &exchange.Config{
-		Platform:   strings.ToLower(strings.TrimSpace(apiConfig.Platform)),
-		ApiKey:     apiKey,
-		SecretKey:  secretKey,
-		Passphrase: passphrase,
-		IsTestnet:  false,
-		Proxy:      proxyCfg,
-	}, nil
+	cfg.ProxyBinding = apiProxyBinding(ctx, apiConfig)
+	return cfg, nil
 }
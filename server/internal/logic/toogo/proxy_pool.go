@@ -0,0 +1,140 @@
+// Package toogo 出口代理池
+package toogo
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+
+	"hotgo/internal/dao"
+	"hotgo/internal/library/exchange"
+	"hotgo/internal/model/entity"
+	"hotgo/utility/encrypt"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// publicProxyBinding 公共行情WS的代理池绑定（只使用全局代理）
+var publicProxyBinding = &exchange.ProxyBinding{Key: "public"}
+
+var proxyPoolLoaded atomic.Bool
+
+// ReloadProxyPool 从 hg_trading_proxy_config 加载启用中的代理到代理池（user_id=0 为全局代理）
+func ReloadProxyPool(ctx context.Context) error {
+	var list []*entity.TradingProxyConfig
+	cols := dao.TradingProxyConfig.Columns()
+	if err := dao.TradingProxyConfig.Ctx(ctx).
+		Where(cols.Enabled, 1).
+		OrderAsc(cols.Id).
+		Scan(&list); err != nil {
+		return gerror.Wrap(err, "加载代理池失败")
+	}
+
+	members := make([]*exchange.PoolProxy, 0, len(list))
+	for _, row := range list {
+		pc, err := ProxyConfigFromEntity(row)
+		if err != nil {
+			g.Log().Warningf(ctx, "[ProxyPool] 跳过无效代理: id=%d, err=%v", row.Id, err)
+			continue
+		}
+		name := row.Name
+		if name == "" {
+			name = row.ProxyAddress
+		}
+		members = append(members, &exchange.PoolProxy{
+			Id:     row.Id,
+			UserId: row.UserId,
+			Name:   name,
+			Weight: row.Weight,
+			Proxy:  pc,
+		})
+	}
+	exchange.GetProxyPool().SetMembers(members)
+	proxyPoolLoaded.Store(true)
+	return nil
+}
+
+// ensureProxyPool 首次使用时加载代理池
+func ensureProxyPool(ctx context.Context) {
+	if proxyPoolLoaded.Load() {
+		return
+	}
+	if err := ReloadProxyPool(ctx); err != nil {
+		g.Log().Warningf(ctx, "[ProxyPool] %v", err)
+	}
+}
+
+// ProxyConfigFromEntity 代理记录转换为交易所代理配置（解密密码）
+func ProxyConfigFromEntity(row *entity.TradingProxyConfig) (*exchange.ProxyConfig, error) {
+	host, portStr, err := net.SplitHostPort(strings.TrimSpace(row.ProxyAddress))
+	if err != nil {
+		return nil, gerror.Wrapf(err, "代理地址格式错误: %s", row.ProxyAddress)
+	}
+	pc := &exchange.ProxyConfig{
+		Enabled: true,
+		Type:    strings.ToLower(row.ProxyType),
+		Host:    host,
+		Port:    g.NewVar(portStr).Int(),
+	}
+	if row.AuthEnabled == 1 && row.Username != "" {
+		pc.Username = row.Username
+		if row.Password != "" {
+			if pc.Password, err = encrypt.AesDecrypt(row.Password); err != nil {
+				return nil, gerror.Wrap(err, "代理密码解密失败")
+			}
+		}
+	}
+	return pc, nil
+}
+
+// apiProxyBinding API配置的代理池绑定：同一API Key粘性使用同一出口
+func apiProxyBinding(ctx context.Context, apiConfig *entity.TradingApiConfig) *exchange.ProxyBinding {
+	ensureProxyPool(ctx)
+	return &exchange.ProxyBinding{UserId: apiConfig.UserId, Key: fmt.Sprintf("api:%d", apiConfig.Id)}
+}
+
+// GlobalProxyBinding 全局代理池绑定（与公共行情WS共用出口）
+func GlobalProxyBinding(ctx context.Context) *exchange.ProxyBinding {
+	ensureProxyPool(ctx)
+	return publicProxyBinding
+}
+
+// getWebSocketDialer 获取WS拨号器：经代理池建立连接，代理故障时自动切换
+func getWebSocketDialer(ctx context.Context, binding *exchange.ProxyBinding) func(network, addr string) (net.Conn, error) {
+	ensureProxyPool(ctx)
+	return exchange.GetProxyPool().Dialer(binding)
+}
+
+// CheckProxyPool 代理池健康检查，结果回写到代理记录
+func CheckProxyPool(ctx context.Context) ([]*exchange.ProxyHealth, error) {
+	if err := ReloadProxyPool(ctx); err != nil {
+		return nil, err
+	}
+
+	results := exchange.GetProxyPool().Check(ctx)
+	cols := dao.TradingProxyConfig.Columns()
+	for _, res := range results {
+		testStatus := 2
+		if res.Healthy {
+			testStatus = 1
+		}
+		if _, err := dao.TradingProxyConfig.Ctx(ctx).
+			Where(cols.Id, res.Id).
+			Data(g.Map{
+				cols.LastTestTime: gtime.New(res.CheckedAt),
+				cols.TestStatus:   testStatus,
+				cols.TestMessage:  res.Message,
+				cols.Latency:      res.Latency,
+				cols.VenueLatency: gjson.MustEncodeString(res.VenueLatency),
+			}).
+			Update(); err != nil {
+			g.Log().Warningf(ctx, "[ProxyPool] 保存健康检查结果失败: id=%d, err=%v", res.Id, err)
+		}
+	}
+	return results, nil
+}
@@ -73,7 +73,7 @@ func (p *Pusher) PushTicker(ctx context.Context, symbols []string) {
 				continue
 			}
 
-			// 推送到对应频道
+			// 推送到对应频道：分配频道序号供断线补发，并转发给其他节点的订阅者
 			websocket.GetHub().SendToChannel(channel, &websocket.Message{
 				Type:      websocket.MsgTypeTicker,
 				Channel:   channel,
@@ -143,6 +143,8 @@ func (p *Pusher) pushSubscribedTickers(ctx context.Context) int {
 	if len(subs) == 0 {
 		return 0
 	}
+	hub := websocket.GetHub()
+	interval := p.calcTickerPushInterval(len(subs))
 
 	// 去重：同一个 platform:symbol 只拉取/读取一次，然后推送到多个频道
 	type key struct {
@@ -171,7 +173,11 @@ func (p *Pusher) pushSubscribedTickers(ctx context.Context) int {
 		}
 
 		for _, ch := range channels {
-			websocket.GetHub().SendToChannel(ch, &websocket.Message{
+			// 其他节点本轮已推送过该频道（集群内转发已送达本节点订阅者），不再重复推送
+			if hub.PeerPushedWithin(ch, interval) {
+				continue
+			}
+			hub.SendToChannel(ch, &websocket.Message{
 				Type:      websocket.MsgTypeTicker,
 				Channel:   ch,
 				Data:      ticker,
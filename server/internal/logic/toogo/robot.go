@@ -121,6 +121,7 @@ func (s *sToogoRobot) StartRobot(ctx context.Context, in *toogoin.StartRobotInp)
 	// 创建运行区间记录
 	_, err = dao.TradingRobotRunSession.Ctx(ctx).Data(g.Map{
 		"robot_id":   robot.Id,
+		"basket_id":  robot.BasketId,
 		"user_id":    robot.UserId,
 		"exchange":   robot.Exchange,
 		"symbol":     robot.Symbol,
@@ -1738,8 +1739,8 @@ func (s *sToogoRobot) CancelRobotOrder(ctx context.Context, robotId int64, order
 // robotId: 机器人ID，0表示清空所有机器人的预警记录
 // keepExecuted: 是否保留已执行的记录（true=只删除未执行的，false=删除所有）
 func (s *sToogoRobot) ClearSignalLogs(ctx context.Context, robotId int64, keepExecuted bool) error {
-	// 如果清空所有记录，使用 TRUNCATE（更快且不需要WHERE条件）
-	if robotId == 0 && !keepExecuted {
+	// 如果清空所有记录，使用 TRUNCATE（更快且不需要WHERE条件），租户只能按条件删除本租户的记录
+	if robotId == 0 && !keepExecuted && RequirePlatform(ctx) == nil {
 		_, err := g.DB().Exec(ctx, "TRUNCATE TABLE hg_trading_signal_log")
 		if err != nil {
 			return gerror.Wrap(err, "清空预警记录失败")
@@ -1749,7 +1750,7 @@ func (s *sToogoRobot) ClearSignalLogs(ctx context.Context, robotId int64, keepEx
 	}
 
 	// 有条件的删除，使用WHERE条件
-	query := g.DB().Model("hg_trading_signal_log").Ctx(ctx)
+	query := dao.TradingSignalLog.Ctx(ctx)
 
 	// 如果指定了机器人ID，只删除该机器人的记录
 	if robotId > 0 {
@@ -0,0 +1,281 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 组合机器人运行时协调：同一组合的各交易对（腿）共享账户快照、保证金预算与持仓数上限
+package toogo
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"hotgo/internal/dao"
+	"hotgo/internal/library/exchange"
+	"hotgo/internal/model/entity"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+const (
+	// basketBalanceMinAge 组合内共享余额的最短复用时间：多条腿同时下单时只请求一次交易所
+	basketBalanceMinAge = 2 * time.Second
+	// basketConfigTTL 组合配置缓存时间
+	basketConfigTTL = 10 * time.Second
+	// basketReserveTTL 保证金预占有效期：下单到持仓同步之间，避免并发开仓突破预算
+	basketReserveTTL = 30 * time.Second
+)
+
+// basketReservation 单腿保证金预占
+type basketReservation struct {
+	margin float64
+	at     time.Time
+}
+
+// BasketCoordinator 组合机器人协调器（按 basketId 单例）
+// 私有WS按 api_config_id 复用，组合内各腿天然共享同一条连接；这里补齐账户快照与保证金/持仓数约束。
+type BasketCoordinator struct {
+	basketId int64
+
+	mu         sync.Mutex
+	basket     *entity.TradingRobotBasket
+	basketAt   time.Time
+	balance    *exchange.Balance
+	balanceAt  time.Time
+	balanceErr error
+	fetchMu    sync.Mutex // 同一时刻只允许一条腿请求余额
+	allocateMu sync.Mutex // 串行化各腿的保证金分配，避免并发开仓突破预算
+
+	reserved map[int64]*basketReservation // robotId -> 预占（仅 allocateMu 下访问）
+}
+
+var (
+	basketCoordinators   = make(map[int64]*BasketCoordinator)
+	basketCoordinatorsMu sync.Mutex
+)
+
+// GetBasketCoordinator 获取组合协调器
+func GetBasketCoordinator(basketId int64) *BasketCoordinator {
+	basketCoordinatorsMu.Lock()
+	defer basketCoordinatorsMu.Unlock()
+	c, ok := basketCoordinators[basketId]
+	if !ok {
+		c = &BasketCoordinator{basketId: basketId, reserved: make(map[int64]*basketReservation)}
+		basketCoordinators[basketId] = c
+	}
+	return c
+}
+
+// ReleaseBasketLeg 组合内一条腿停止：清理其保证金预占，组合内已没有运行中的腿时移除协调器
+func ReleaseBasketLeg(basketId, robotId int64) {
+	basketCoordinatorsMu.Lock()
+	c := basketCoordinators[basketId]
+	basketCoordinatorsMu.Unlock()
+	if c == nil {
+		return
+	}
+
+	c.allocateMu.Lock()
+	delete(c.reserved, robotId)
+	c.allocateMu.Unlock()
+
+	for _, eng := range GetRobotTaskManager().GetAllEngines() {
+		if eng != nil && eng.Robot != nil && eng.Robot.BasketId == basketId && eng.Robot.Id != robotId && eng.IsRunning() {
+			return
+		}
+	}
+
+	basketCoordinatorsMu.Lock()
+	if basketCoordinators[basketId] == c {
+		delete(basketCoordinators, basketId)
+	}
+	basketCoordinatorsMu.Unlock()
+}
+
+// InvalidateBasket 组合配置变更后清理缓存
+func InvalidateBasket(basketId int64) {
+	basketCoordinatorsMu.Lock()
+	c := basketCoordinators[basketId]
+	basketCoordinatorsMu.Unlock()
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.basket = nil
+	c.mu.Unlock()
+}
+
+// Balance 组合共享账户快照：maxCacheAge 内复用，最少复用 basketBalanceMinAge
+func (c *BasketCoordinator) Balance(ctx context.Context, e *RobotEngine, maxCacheAge time.Duration) (*exchange.Balance, error) {
+	if maxCacheAge < basketBalanceMinAge {
+		maxCacheAge = basketBalanceMinAge
+	}
+	if bal, ok := c.cachedBalance(maxCacheAge); ok {
+		return bal, nil
+	}
+
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+	// 等锁期间其他腿可能已刷新
+	if bal, ok := c.cachedBalance(maxCacheAge); ok {
+		return bal, nil
+	}
+
+	bal, err := e.fetchBalance(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		g.Log().Debugf(ctx, "[Basket] basketId=%d 共享余额刷新失败，使用旧快照: %v", c.basketId, err)
+		return c.balance, nil
+	}
+	c.balance = bal
+	c.balanceAt = time.Now()
+	return bal, nil
+}
+
+func (c *BasketCoordinator) cachedBalance(maxAge time.Duration) (*exchange.Balance, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.balance != nil && time.Since(c.balanceAt) < maxAge {
+		return c.balance, true
+	}
+	return nil, false
+}
+
+func (c *BasketCoordinator) loadBasket(ctx context.Context) (*entity.TradingRobotBasket, error) {
+	c.mu.Lock()
+	if c.basket != nil && time.Since(c.basketAt) < basketConfigTTL {
+		b := c.basket
+		c.mu.Unlock()
+		return b, nil
+	}
+	c.mu.Unlock()
+
+	var basket *entity.TradingRobotBasket
+	err := dao.TradingRobotBasket.Ctx(ctx).
+		Where(dao.TradingRobotBasket.Columns().Id, c.basketId).
+		WhereNull(dao.TradingRobotBasket.Columns().DeletedAt).
+		Scan(&basket)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询组合机器人失败")
+	}
+	if basket == nil {
+		return nil, gerror.Newf("组合机器人不存在: basketId=%d", c.basketId)
+	}
+
+	c.mu.Lock()
+	c.basket = basket
+	c.basketAt = time.Now()
+	c.mu.Unlock()
+	return basket, nil
+}
+
+// basketLegUsage 组合内某条腿的持仓占用
+type basketLegUsage struct {
+	hasPosition bool
+	margin      float64
+}
+
+// legUsages 从运行中的引擎读取组合内各腿的持仓快照（不请求交易所）
+// 尚未同步到持仓的新下单按预占计入。
+func (c *BasketCoordinator) legUsages() map[int64]*basketLegUsage {
+	out := make(map[int64]*basketLegUsage)
+	for _, eng := range GetRobotTaskManager().GetAllEngines() {
+		if eng == nil || eng.Robot == nil || eng.Robot.BasketId != c.basketId {
+			continue
+		}
+		eng.mu.RLock()
+		positions := eng.CurrentPositions
+		eng.mu.RUnlock()
+
+		usage := &basketLegUsage{}
+		for _, pos := range positions {
+			if pos == nil || math.Abs(pos.PositionAmt) <= 1e-9 {
+				continue
+			}
+			usage.hasPosition = true
+			margin := pos.Margin
+			if margin <= 0 {
+				margin = pos.IsolatedMargin
+			}
+			if margin <= 0 && pos.Leverage > 0 {
+				margin = math.Abs(pos.PositionAmt) * pos.EntryPrice / float64(pos.Leverage)
+			}
+			usage.margin += margin
+		}
+		if r := c.reserved[eng.Robot.Id]; r != nil {
+			if usage.hasPosition || time.Since(r.at) > basketReserveTTL {
+				delete(c.reserved, eng.Robot.Id)
+			} else {
+				usage.hasPosition = true
+				usage.margin += r.margin
+			}
+		}
+		out[eng.Robot.Id] = usage
+	}
+	return out
+}
+
+// AllocateMargin 按组合约束裁剪本次开仓保证金
+// 规则：
+// ① 持仓交易对数达到 max_positions 时，无持仓的腿不允许开新仓
+// ② 预算 = margin_budget（为0时取 可用余额 + 组合已占用保证金）
+// ③ 单腿上限 = 预算 × 分配权重 - 该腿已占用；组合剩余 = 预算 - 组合已占用；取三者最小值
+// 返回的 release 用于下单失败时释放本次预占，避免失败的下单在 basketReserveTTL 内继续占用预算
+func (c *BasketCoordinator) AllocateMargin(ctx context.Context, e *RobotEngine, margin, availableBalance float64) (float64, func(), error) {
+	basket, err := c.loadBasket(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	c.allocateMu.Lock()
+	defer c.allocateMu.Unlock()
+
+	usages := c.legUsages()
+	self := usages[e.Robot.Id]
+	if self == nil {
+		self = &basketLegUsage{}
+	}
+
+	var openLegs int
+	var usedMargin float64
+	for _, u := range usages {
+		if u.hasPosition {
+			openLegs++
+		}
+		usedMargin += u.margin
+	}
+	if basket.MaxPositions > 0 && !self.hasPosition && openLegs >= basket.MaxPositions {
+		return 0, nil, gerror.Newf("组合持仓交易对数已达上限(%d/%d)", openLegs, basket.MaxPositions)
+	}
+
+	budget := basket.MarginBudget
+	if budget <= 0 {
+		budget = availableBalance + usedMargin
+	}
+	legCap := budget*e.Robot.AllocationWeight - self.margin
+	remaining := budget - usedMargin
+
+	allowed := math.Min(margin, math.Min(legCap, remaining))
+	allowed = math.Min(allowed, availableBalance)
+	if allowed <= 0 {
+		return 0, nil, gerror.Newf("组合保证金预算不足（预算=%.2f, 已占用=%.2f, 本腿权重=%.2f, 本腿已占用=%.2f）",
+			budget, usedMargin, e.Robot.AllocationWeight, self.margin)
+	}
+	if allowed < margin {
+		g.Log().Infof(ctx, "[Basket] basketId=%d robotId=%d 保证金按组合约束裁剪: %.2f -> %.2f（预算=%.2f, 已占用=%.2f, 权重=%.2f）",
+			c.basketId, e.Robot.Id, margin, allowed, budget, usedMargin, e.Robot.AllocationWeight)
+	}
+	reservation := &basketReservation{margin: allowed, at: time.Now()}
+	c.reserved[e.Robot.Id] = reservation
+	release := func() {
+		c.allocateMu.Lock()
+		defer c.allocateMu.Unlock()
+		if c.reserved[e.Robot.Id] == reservation {
+			delete(c.reserved, e.Robot.Id)
+		}
+	}
+	return allowed, release, nil
+}
@@ -23,8 +23,10 @@ import (
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/gtrace"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/util/grand"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // balanceFetchErrLogAt throttles "no balance cache" warnings to avoid log spam.
@@ -61,6 +63,7 @@ type RobotEngine struct {
 	Analyzer  *RobotAnalyzer  // 市场分析模块
 	SignalGen *RobotSignalGen // 信号生成模块
 	Trader    *RobotTrader    // 交易执行模块
+	Grid      *GridTrader     // 网格执行模块（仅 robot_mode=grid，此时不走方向信号/止盈止损链路）
 
 	// ============ 状态缓存（统一数据中心，所有请求都使用这里的缓存） ============
 	LastTicker       *exchange.Ticker     // 最新行情
@@ -126,6 +129,7 @@ type RobotEngine struct {
 	// ============ 并发控制 ============
 	processingPriceUpdate int32 // 是否正在处理“数据库订单更新”任务（原子操作，防止goroutine堆积）
 	processingWSUpdate    int32 // 是否正在处理“WS价格回调的平仓检查”任务（原子操作，避免风暴但不阻断报价）
+	scalingInFlight       int32 // 是否正在处理分批加仓检查（原子操作，下单期间不阻塞平仓检查）
 
 	// ============ 行情/交易解耦（保证报价不被订单/DB阻塞） ============
 	// signalEvalPending: 在 WS 报价回调中触发信号评估时，确保同一时刻只跑一个评估任务（丢弃多余触发）。
@@ -373,6 +377,7 @@ func (e *RobotEngine) analysisPlatform(ctx context.Context) string {
 // 任何交易/DB 问题都只影响 worker，不会反向阻塞行情推送。
 type windowSignalEvent struct {
 	Signal *RobotSignal
+	Trace  *signalTrace
 }
 
 // GetAccountSnapshot 获取账户缓存快照（线程安全）
@@ -476,6 +481,14 @@ type PositionTracker struct {
 	MarginPercent           float64 // 保证金比例(%)
 	MarketState             string  // 开仓时市场状态
 	RiskPreference          string  // 开仓时风险偏好
+
+	// ===== 分批加仓（开仓时冻结；启用后 EntryMargin 为各批保证金之和，止损/止盈均按加权均价计量） =====
+	Scaling        *ScaleConfig // 冻结的加仓配置（nil=未启用）
+	EntryCount     int          // 已成交批次（含首单）
+	LastEntryPrice float64      // 最近一批成交价（下一次加仓的触发基准）
+	ScaleInRetryAt time.Time    // 加仓下单失败后的重试时间（触发基准保持最近一批成交价不变）
+	AvgEntryPrice  float64      // 加权开仓均价
+	TotalQuantity  float64      // 各批数量之和
 }
 
 // calcRiskQtyAndMargin 计算用于风控(止损/止盈)的有效持仓数量与保证金
@@ -722,8 +735,10 @@ func (e *RobotEngine) Start(ctx context.Context) error {
 		market.GetMarketServiceManager().Subscribe(ctx, analysisPlatform, e.Robot.Symbol, nil)
 	}
 
-	// 启动“信号写库/下单”worker：与行情推送链路彻底隔离
-	go e.runWindowSignalWorker()
+	// 启动“信号写库/下单”worker：与行情推送链路彻底隔离（网格模式不使用方向信号）
+	if e.Grid == nil {
+		go e.runWindowSignalWorker()
+	}
 
 	// 私有WS（订单/持仓/账户变更）按 apiConfigId 复用：事件驱动触发同步，减少轮询
 	// 失败不阻断引擎启动（仍有定期兜底对账）
@@ -784,6 +799,13 @@ func (e *RobotEngine) Start(ctx context.Context) error {
 			e.Robot.Id, ap, e.Platform, e.Robot.Symbol)
 	}()
 
+	// 网格模式：挂单/成交翻转由 GridTrader 驱动，主循环只负责行情与账户同步
+	if e.Grid != nil {
+		go e.Grid.Run(context.Background(), e.stopCh)
+		go e.runMainLoop(ctx)
+		return nil
+	}
+
 	// 【启动期修复】等待全局市场分析器产出 marketState（避免启动阶段 CurrentStrategyParams 为空，导致无法获取窗口/阈值）
 	// - MarketAnalyzer 1s 一轮，启动期最多等待 5s
 	// - 不阻塞主流程太久：放到 goroutine 内执行
@@ -881,11 +903,6 @@ func (e *RobotEngine) trySyncTradeFillsLowPriority(ctx context.Context, reason s
 		return
 	}
 
-	// low priority limiter (shared with wallet补齐逻辑)
-	if !getTradeHistoryLimiter(apiId).Allow() {
-		return
-	}
-
 	callCtx := ctx
 	cancel := func() {}
 	if callCtx == nil {
@@ -899,6 +916,8 @@ func (e *RobotEngine) trySyncTradeFillsLowPriority(ctx context.Context, reason s
 		callCtx, cancel = context.WithTimeout(callCtx, 6*time.Second)
 	}
 	defer cancel()
+	// 低优先级：额度紧张时由请求调度器直接拒绝，下次再同步
+	callCtx = exchange.WithPriority(callCtx, exchange.PriorityLow)
 
 	saved, matched, err := fetchAndStoreTradeHistory(callCtx, e.Exchange, apiId, e.Exchange.GetName(), symbol, limit)
 	if err == nil {
@@ -943,6 +962,16 @@ func (e *RobotEngine) Stop() {
 		GetPrivateStreamManager().Release(e.Platform, e.APIConfig.Id, e.Robot.Symbol, e.Robot.Id)
 	}
 
+	// 网格模式：撤掉全部网格挂单（持仓保留，重启后按格子状态续挂）
+	if e.Grid != nil {
+		go e.Grid.CancelAll(context.Background())
+	}
+
+	// 组合机器人：释放保证金预占，最后一条腿停止时移除组合协调器（异步执行，调用方可能持有任务管理器锁）
+	if e.Robot.BasketId > 0 {
+		go ReleaseBasketLeg(e.Robot.BasketId, e.Robot.Id)
+	}
+
 	g.Log().Infof(context.Background(), "[RobotEngine] 机器人引擎停止: robotId=%d", e.Robot.Id)
 }
 
@@ -1278,6 +1307,11 @@ func (e *RobotEngine) initTrackerFromDB(ctx context.Context, positionSide string
 		RiskPreference          string  `json:"risk_preference"`
 		RiskLevel               string  `json:"risk_level"` // 兼容旧字段
 		StrategyGroupId         int64   `json:"strategy_group_id"`
+
+		ScaleConfig string  `json:"scale_config"`
+		EntryCount  int     `json:"entry_count"`
+		OpenPrice   float64 `json:"open_price"`
+		Quantity    float64 `json:"quantity"`
 	}
 	err := dao.TradingOrder.Ctx(ctx).
 		Where("robot_id", robot.Id).
@@ -1297,6 +1331,10 @@ func (e *RobotEngine) initTrackerFromDB(ctx context.Context, positionSide string
 			"risk_preference",
 			"risk_level",
 			"strategy_group_id",
+			"scale_config",
+			"entry_count",
+			"open_price",
+			"quantity",
 		).
 		OrderDesc("id").
 		Scan(&row)
@@ -1330,6 +1368,9 @@ func (e *RobotEngine) initTrackerFromDB(ctx context.Context, positionSide string
 	tracker.RiskPreference = rp
 	tracker.ParamsLoaded = true
 	tracker.OrderId = row.Id
+	if tracker.Scaling == nil {
+		restoreScaleFromOrder(ctx, tracker, row.Id, row.ScaleConfig, row.EntryCount, row.OpenPrice, row.Quantity)
+	}
 
 	// ===== 兼容：历史订单未落“冻结参数”时，允许回退到当前策略参数 =====
 	// 场景：
@@ -1601,6 +1642,11 @@ func (e *RobotEngine) OnPriceUpdate(ctx context.Context, ticker *exchange.Ticker
 		riskPrice = pricePoint
 	}
 
+	// 网格模式：成交由交易所挂单驱动，不需要价格窗口/止盈止损检查
+	if e.Grid != nil {
+		return
+	}
+
 	// 更新价格窗口（用于信号生成）
 	e.priceLock.Lock()
 	now := time.Now()
@@ -1645,11 +1691,13 @@ func (e *RobotEngine) OnPriceUpdate(ctx context.Context, ticker *exchange.Ticker
 				e.checkAndPushProgressUpdate(checkCtx, riskPrice)
 			}()
 		}
+		// 分批加仓：独立协程下单，避免占用平仓检查通道
+		e.scheduleScaleIn(riskPrice)
 	}
 
 	// 信号评估/写库/下单属于“交易链路”，必须与“行情链路”解耦，避免任何异常影响报价。
 	// 这里仅做非阻塞触发：后台评估信号并投递到 worker。
-	e.scheduleWindowSignalEval()
+	e.scheduleWindowSignalEval(ctx, now)
 }
 
 // selectWindowPricePoint selects the price used for window-based signal generation.
@@ -1693,12 +1741,22 @@ func windowSignalToInt(direction string) int32 {
 // - 在行情回调里只做 CAS + spawn，确保不被 DB/下单/异常阻塞
 // - 评估结果更新 LastSignal（供前端展示）
 // - 需要下单的信号事件投递到 windowSignalCh（失败则丢弃，不影响报价）
-func (e *RobotEngine) scheduleWindowSignalEval() {
+// - 追踪：toogo.OnPriceUpdate → toogo.EvaluateWindowSignal，信号事件携带追踪上下文交给订单链路
+func (e *RobotEngine) scheduleWindowSignalEval(ctx context.Context, tickAt time.Time) {
 	if !atomic.CompareAndSwapInt32(&e.signalEvalPending, 0, 1) {
 		return
 	}
+	e.mu.RLock()
+	var robotId int64
+	var symbol string
+	if e.Robot != nil {
+		robotId, symbol = e.Robot.Id, e.Robot.Symbol
+	}
+	e.mu.RUnlock()
+	ctx, tickSpan := startTickSpan(ctx, robotId, e.Platform, symbol)
 	go func() {
 		defer atomic.StoreInt32(&e.signalEvalPending, 0)
+		defer tickSpan.End()
 		defer func() {
 			if r := recover(); r != nil {
 				g.Log().Errorf(context.Background(), "[RobotEngine] scheduleWindowSignalEval panic recovered: robotId=%d, err=%v",
@@ -1719,7 +1777,12 @@ func (e *RobotEngine) scheduleWindowSignalEval() {
 		default:
 		}
 
+		_, evalSpan := gtrace.NewSpan(ctx, "toogo.EvaluateWindowSignal")
 		signal := e.EvaluateWindowSignal()
+		if signal != nil {
+			evalSpan.SetAttributes(attribute.String("signal.direction", signal.Direction), attribute.String("signal.action", signal.Action))
+		}
+		evalSpan.End()
 		if signal == nil {
 			return
 		}
@@ -1746,12 +1809,16 @@ func (e *RobotEngine) scheduleWindowSignalEval() {
 
 		// 投递给交易链路（非阻塞；满了就丢，不能影响行情）
 		signalCopy := *signal
-		ev := &windowSignalEvent{Signal: &signalCopy}
+		ev := &windowSignalEvent{Signal: &signalCopy, Trace: &signalTrace{
+			SpanContext: evalSpan.SpanContext(),
+			SignalAt:    time.Now(),
+		}}
 		select {
 		case <-e.stopCh:
 			return
 		case e.windowSignalCh <- ev:
 			atomic.StoreInt32(&e.lastDispatchedWindowSignal, d)
+			tickToSignal.Observe(ev.Trace.SignalAt.Sub(tickAt).Seconds(), e.Platform)
 		default:
 			// drop
 		}
@@ -1791,6 +1858,7 @@ func (e *RobotEngine) runWindowSignalWorker() {
 			if logId <= 0 {
 				continue
 			}
+			rememberSignalTrace(logId, ev.Trace)
 		}
 	}
 }
@@ -1811,6 +1879,14 @@ func (e *RobotEngine) doAnalysis(ctx context.Context) {
 	if ticker == nil || ticker.EffectiveMarkPrice() <= 0 {
 		return
 	}
+	// 网格模式：只维护最新行情
+	if e.Grid != nil {
+		e.mu.Lock()
+		e.LastTicker = ticker
+		e.LastTickerUpdate = time.Now()
+		e.mu.Unlock()
+		return
+	}
 	// pricePoint 用于信号窗口（与 OnPriceUpdate 同口径，避免 Gate 单边预警问题）
 	pricePoint := e.selectWindowPricePoint(ticker)
 	// riskPrice 用于盈亏/止盈止损/风控（MarkPrice优先，LastPrice兜底）
@@ -2030,15 +2106,16 @@ var defaultStrategyParams = map[string]map[string]struct {
 
 // StrategyParams 策略参数（从策略模板加载的完整参数）
 type StrategyParams struct {
-	Window                  int     // 时间窗口(秒)
-	Threshold               float64 // 波动阈值(USDT)
-	LeverageMin             int     // 杠杆最小值
-	LeverageMax             int     // 杠杆最大值
-	MarginPercentMin        float64 // 保证金比例最小值
-	MarginPercentMax        float64 // 保证金比例最大值
-	StopLossPercent         float64 // 止损百分比
-	ProfitRetreatPercent    float64 // 止盈回撤百分比
-	AutoStartRetreatPercent float64 // 启动止盈百分比
+	Window                  int          // 时间窗口(秒)
+	Threshold               float64      // 波动阈值(USDT)
+	LeverageMin             int          // 杠杆最小值
+	LeverageMax             int          // 杠杆最大值
+	MarginPercentMin        float64      // 保证金比例最小值
+	MarginPercentMax        float64      // 保证金比例最大值
+	StopLossPercent         float64      // 止损百分比
+	ProfitRetreatPercent    float64      // 止盈回撤百分比
+	AutoStartRetreatPercent float64      // 启动止盈百分比
+	Scaling                 *ScaleConfig // 分批加仓配置（nil=未启用，见 robot_scaling.go）
 }
 
 // VolatilityConfig 波动率配置（市场状态阈值 + 5个时间周期权重）
@@ -2189,6 +2266,7 @@ func (e *RobotEngine) loadFullStrategyParams(ctx context.Context, marketState, r
 			params.StopLossPercent = strategy.StopLossPercent
 			params.ProfitRetreatPercent = strategy.ProfitRetreatPercent
 			params.AutoStartRetreatPercent = strategy.AutoStartRetreatPercent
+			params.Scaling = ParseScaleConfig(strategy.ConfigJson)
 
 			g.Log().Infof(ctx, "[RobotEngine] robotId=%d 从策略模板加载参数: market=%s(规范化=%s,查询=%s), risk=%s, 窗口=%d, 波动=%.1f, 杠杆=%d, 保证金=%.1f%%, 止损=%.1f%%, 启动止盈=%.1f%%, 止盈回撤=%.1f%%",
 				e.Robot.Id, marketState, normalizedMarketState, ms, riskPreference,
@@ -2378,6 +2456,18 @@ func (e *RobotEngine) GetPositionsSmart(ctx context.Context, maxCacheAge time.Du
 
 // GetBalanceSmart 智能获取余额（使用缓存 + singleflight 模式）
 func (e *RobotEngine) GetBalanceSmart(ctx context.Context, maxCacheAge time.Duration) (*exchange.Balance, error) {
+	// 组合机器人：同一组合的各腿共享一份账户快照（同一API账户，避免每条腿各自请求余额）
+	if e.Robot != nil && e.Robot.BasketId > 0 {
+		balance, err := GetBasketCoordinator(e.Robot.BasketId).Balance(ctx, e, maxCacheAge)
+		if balance != nil {
+			e.mu.Lock()
+			e.AccountBalance = balance
+			e.LastBalanceUpdate = time.Now()
+			e.mu.Unlock()
+		}
+		return balance, err
+	}
+
 	// 1. 检查缓存是否有效
 	e.mu.RLock()
 	cachedBalance := e.AccountBalance
@@ -2411,8 +2501,39 @@ func (e *RobotEngine) GetBalanceSmart(ctx context.Context, maxCacheAge time.Dura
 	// 3. 执行实际的 API 调用
 	defer atomic.StoreInt32(&e.balanceFetching, 0)
 
+	balance, err := e.fetchBalance(ctx)
+	if err != nil {
+		if e.Exchange == nil {
+			return cachedBalance, err
+		}
+		g.Log().Debugf(ctx, "[RobotEngine] robotId=%d GetBalance失败，使用旧缓存: %v", e.Robot.Id, err)
+		// 如果缓存为空，则前端 batchRobotAnalysis 会返回 account=null（显示为 "--"）。
+		// 这里做低频告警，方便现场定位：是交易所余额接口不通/超时/限流，还是权限/解析问题。
+		if cachedBalance == nil && shouldLogBalanceFetchErr(e.Robot.Id, 10*time.Second) {
+			sym := ""
+			plat := strings.TrimSpace(e.Platform)
+			if e.Robot != nil {
+				sym = strings.TrimSpace(e.Robot.Symbol)
+			}
+			g.Log().Warningf(ctx, "[RobotEngine] robotId=%d GetBalance失败且余额缓存为空（页面可用余额将显示'--'）: platform=%s symbol=%s err=%v",
+				e.Robot.Id, plat, sym, err)
+		}
+		return cachedBalance, nil
+	}
+
+	// 4. 更新缓存
+	e.mu.Lock()
+	e.AccountBalance = balance
+	e.LastBalanceUpdate = time.Now()
+	e.mu.Unlock()
+
+	return balance, nil
+}
+
+// fetchBalance 直接请求交易所余额（带超时；全0余额视为解析异常）
+func (e *RobotEngine) fetchBalance(ctx context.Context) (*exchange.Balance, error) {
 	if e.Exchange == nil {
-		return cachedBalance, gerror.New("交易所实例不存在")
+		return nil, gerror.New("交易所实例不存在")
 	}
 
 	// 【关键】为 GetBalance 增加超时，避免交易所接口卡住导致 balanceFetching 长时间不释放，
@@ -2433,32 +2554,13 @@ func (e *RobotEngine) GetBalanceSmart(ctx context.Context, maxCacheAge time.Dura
 
 	balance, err := e.Exchange.GetBalance(callCtx)
 	if err != nil {
-		g.Log().Debugf(ctx, "[RobotEngine] robotId=%d GetBalance失败，使用旧缓存: %v", e.Robot.Id, err)
-		// 如果缓存为空，则前端 batchRobotAnalysis 会返回 account=null（显示为 "--"）。
-		// 这里做低频告警，方便现场定位：是交易所余额接口不通/超时/限流，还是权限/解析问题。
-		if cachedBalance == nil && shouldLogBalanceFetchErr(e.Robot.Id, 10*time.Second) {
-			sym := ""
-			plat := strings.TrimSpace(e.Platform)
-			if e.Robot != nil {
-				sym = strings.TrimSpace(e.Robot.Symbol)
-			}
-			g.Log().Warningf(ctx, "[RobotEngine] robotId=%d GetBalance失败且余额缓存为空（页面可用余额将显示'--'）: platform=%s symbol=%s err=%v",
-				e.Robot.Id, plat, sym, err)
-		}
-		return cachedBalance, nil
+		return nil, err
 	}
 	// 防止“解析异常但无 error”的全 0 余额污染缓存，导致页面长期显示 0.00 且不再重试。
-	if balance != nil && balance.TotalBalance == 0 && balance.AvailableBalance == 0 && balance.UnrealizedPnl == 0 {
+	if balance == nil || (balance.TotalBalance == 0 && balance.AvailableBalance == 0 && balance.UnrealizedPnl == 0) {
 		g.Log().Warningf(ctx, "[RobotEngine] robotId=%d GetBalance返回全0，忽略并保留旧缓存（platform=%s）", e.Robot.Id, e.Platform)
-		return cachedBalance, nil
+		return nil, gerror.New("交易所返回全0余额")
 	}
-
-	// 4. 更新缓存
-	e.mu.Lock()
-	e.AccountBalance = balance
-	e.LastBalanceUpdate = time.Now()
-	e.mu.Unlock()
-
 	return balance, nil
 }
 
@@ -3120,6 +3222,9 @@ func (e *RobotEngine) syncAccountDataIfNeeded(ctx context.Context, syncType stri
 // 【简化】信号生成和下单触发已在 EvaluateWindowSignal 中完成
 // 此函数只负责更新引擎状态，不再重复触发下单检查
 func (e *RobotEngine) doSignalGeneration(ctx context.Context) {
+	if e.Grid != nil {
+		return
+	}
 	signal := e.SignalGen.Generate(ctx)
 	if signal == nil {
 		return
@@ -3674,7 +3779,7 @@ func (e *RobotEngine) checkTakeProfitAndClose(ctx context.Context, currentPrice
 				if !wasEnabled {
 					// 立即推送，确保前端能及时看到开关状态变化
 					e.notifyPositionsDeltaAsync("take_profit_auto_enabled")
-					g.Log().Debugf(ctx, "[RobotEngine] robotId=%d 【关键节点】血条达到100%，已推送开关状态更新", e.Robot.Id)
+					g.Log().Debugf(ctx, "[RobotEngine] robotId=%d 【关键节点】血条达到100%%，已推送开关状态更新", e.Robot.Id)
 				}
 			}
 		}
@@ -5804,6 +5909,7 @@ func (t *RobotTrader) updateOrderOpenFreeze(ctx context.Context, orderId int64,
 // 2. 参数计算 → 获取市场状态和策略参数（与机器人详情页面相同的方法）
 // 3. 创建订单到平台（完成）
 func (t *RobotTrader) TryAutoTradeAndUpdate(ctx context.Context, signal *RobotSignal, logId int64) {
+	ctx = resumeSignalTrace(ctx, logId)
 	robot := t.engine.Robot
 	if robot == nil {
 		if logId > 0 {
@@ -6144,6 +6250,7 @@ func (t *RobotTrader) TryAutoTradeAndUpdate(ctx context.Context, signal *RobotSi
 	// 开仓限制规则（按你的最新定义）：
 	// - DualSidePosition=1（双向开单开启）：允许同时持有多+空，但【同方向只能一单】（不允许加仓）
 	// - DualSidePosition=0（关闭）：【持仓内只能有一单】（不区分多空）
+	// - 策略模板启用分批加仓时，加仓由价格触发（robot_scaling.go），不经过信号开仓链路，这里的限制不变
 	//
 	// 这里基于“交易所实时持仓”做判断，避免依赖DB/内存不同步。
 	hasAnyPosition := false
@@ -6338,7 +6445,8 @@ func (t *RobotTrader) saveExecutionLog(ctx context.Context, signalLogId int64, o
 	}
 
 	// 【新增】分析失败原因，提取分类和详情
-	failureCategory, failureReason := t.analyzeFailureReason(eventType, message, eventData)
+	failureCategory, failureReason := analyzeFailureReason(eventType, message, eventData)
+	observeOrderResult(ctx, t.engine.Platform, eventType, failureCategory)
 
 	// 写入交易日志
 	insertData := g.Map{
@@ -6405,7 +6513,7 @@ func (t *RobotTrader) saveExecutionLog(ctx context.Context, signalLogId int64, o
 
 // analyzeFailureReason 分析失败原因，提取分类和详情
 // 【新增】自动分析失败原因，生成结构化说明和解决建议
-func (t *RobotTrader) analyzeFailureReason(eventType string, message string, eventData map[string]interface{}) (category string, reason string) {
+func analyzeFailureReason(eventType string, message string, eventData map[string]interface{}) (category string, reason string) {
 	// 只处理失败事件
 	if eventType != "order_failed" {
 		return "", ""
@@ -7114,6 +7222,11 @@ func (t *RobotTrader) preCreateOrder(ctx context.Context, signal *RobotSignal, s
 		"updated_at": gtime.Now(),
 	}
 
+	// 信号快照（供AI学习循环按实际结果打分）
+	if snapshot := market.BuildSignalSnapshot(t.engine.Platform, robot.Symbol); snapshot != nil {
+		orderData["signal_snapshot"] = gjson.MustEncodeString(snapshot)
+	}
+
 	// 保存策略参数
 	if strategyParams != nil {
 		if strategyParams.StopLossPercent > 0 {
@@ -7459,8 +7572,25 @@ func (t *RobotTrader) getStrategyParamsForTrade(ctx context.Context) (marketStat
 	return marketState, riskPreference, strategyParams, nil
 }
 
-func (t *RobotTrader) executeOpen(ctx context.Context, signal *RobotSignal, signalLogId int64) error {
+func (t *RobotTrader) executeOpen(ctx context.Context, signal *RobotSignal, signalLogId int64) (err error) {
 	robot := t.engine.Robot
+	ctx, span := gtrace.NewSpan(ctx, "toogo.executeOpen")
+	if robot != nil {
+		span.SetAttributes(robotSpanAttrs(robot.Id, t.engine.Platform, robot.Symbol)...)
+	}
+	span.SetAttributes(attribute.String("signal.direction", signal.Direction), attribute.Int64("signal.log_id", signalLogId))
+	defer func() { endSpan(span, err) }()
+
+	// 订阅宽限期：只管理已有持仓，不再开新仓
+	if robot != nil && SubscriptionOpenBlocked(ctx, robot.UserId) {
+		errMsg := "订阅已到期（宽限期内），暂停开新仓，请续费后恢复"
+		if signalLogId > 0 {
+			t.saveExecutionLog(ctx, signalLogId, 0, "order_failed", "failed", errMsg, map[string]interface{}{
+				"step": "subscription_check",
+			})
+		}
+		return gerror.New(errMsg)
+	}
 
 	// 【重要】下单时必须从交易所API获取最新余额，不允许使用本地缓存余额
 	// 获取缓存余额仅用于对比和日志记录
@@ -7590,6 +7720,27 @@ func (t *RobotTrader) executeOpen(ctx context.Context, signal *RobotSignal, sign
 	// ③ 下单数量 = 订单金额 / 当前价格
 	// 【修复】直接使用 AvailableBalance，避免重复计算（AvailableBalance 已经是扣除已用保证金后的可用余额）
 	margin := balance.AvailableBalance * marginPercent / 100
+	// 组合机器人：按组合保证金预算、分配权重与持仓数上限裁剪
+	var releaseMargin func()
+	if robot.BasketId > 0 {
+		margin, releaseMargin, err = GetBasketCoordinator(robot.BasketId).AllocateMargin(ctx, t.engine, margin, balance.AvailableBalance)
+		if err != nil {
+			g.Log().Warningf(ctx, "[RobotTrader] robotId=%d basketId=%d 组合约束拒绝开仓: %v", robot.Id, robot.BasketId, err)
+			if signalLogId > 0 {
+				t.saveExecutionLog(ctx, signalLogId, 0, "order_failed", "failed", err.Error(), map[string]interface{}{
+					"step":     "basket_limit",
+					"basketId": robot.BasketId,
+				})
+			}
+			return err
+		}
+		// 交易所下单成功前的任何失败都释放本次预占
+		defer func() {
+			if err != nil && releaseMargin != nil {
+				releaseMargin()
+			}
+		}()
+	}
 	orderValue := margin * float64(leverage)
 
 	// 【详细日志】输出订单金额计算过程
@@ -7766,6 +7917,9 @@ func (t *RobotTrader) executeOpen(ctx context.Context, signal *RobotSignal, sign
 		return gerror.Wrap(err, "交易所下单失败")
 	}
 
+	// 已成交的下单由持仓同步接管占用，预占保留到持仓同步或过期
+	releaseMargin = nil
+
 	g.Log().Infof(ctx, "[RobotTrader] robotId=%d 【步骤3.3】交易所API下单成功: exchangeOrderId=%s, avgPrice=%.2f, filledQty=%.4f",
 		robot.Id, order.OrderId, order.AvgPrice, order.FilledQty)
 
@@ -7940,6 +8094,12 @@ func (t *RobotTrader) executeOpen(ctx context.Context, signal *RobotSignal, sign
 
 	t.engine.mu.Unlock()
 
+	// 分批加仓：冻结加仓配置（含总保证金上限）并记录首批明细
+	if strategyParams.Scaling != nil {
+		t.freezeScaleOnOpen(ctx, t.engine.GetPositionTracker(positionSide), strategyParams.Scaling, localOrderId, order.OrderId,
+			entryPrice, quantity, margin, leverage, balance.AvailableBalance)
+	}
+
 	// 【订单日志】订单成功 - 只保留一条成功日志（详版），同时携带 step=done 以兼容前端“最终态”展示
 	t.saveExecutionLog(ctx, signalLogId, localOrderId, "order_success", "success", fmt.Sprintf("订单成功: 交易所订单ID=%s, %s方向, 数量%.4f, 成交价%.2f, 杠杆%dx", order.OrderId, positionSide, quantity, entryPrice, leverage), map[string]interface{}{
 		"step":                   "done",
@@ -8144,6 +8304,11 @@ func (t *RobotTrader) recordOrder(ctx context.Context, order *exchange.Order, si
 		"unrealized_profit":      0, // 初始未实现盈亏为0
 	}
 
+	// 信号快照（供AI学习循环按实际结果打分）
+	if snapshot := market.BuildSignalSnapshot(t.engine.Platform, robot.Symbol); snapshot != nil {
+		orderData["signal_snapshot"] = gjson.MustEncodeString(snapshot)
+	}
+
 	// 保存策略参数
 	if strategyParams != nil {
 		if strategyParams.StopLossPercent > 0 {
@@ -0,0 +1,912 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 网格交易模式：在上下限之间按等差/等比划分网格，每格始终挂一张限价单，成交后翻转挂单
+package toogo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"hotgo/internal/dao"
+	"hotgo/internal/library/exchange"
+	"hotgo/internal/library/market"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/service"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// 机器人运行模式
+const (
+	RobotModeSignal = "signal" // 方向信号（窗口突破）
+	RobotModeGrid   = "grid"   // 网格
+)
+
+// 网格方向偏好
+const (
+	GridBiasLong    = "long"    // 做多网格：低买高卖
+	GridBiasShort   = "short"   // 做空网格：高卖低买
+	GridBiasNeutral = "neutral" // 中性网格：现价以下做多格、以上做空格
+)
+
+// 网格间距
+const (
+	GridSpacingArithmetic = "arithmetic" // 等差
+	GridSpacingGeometric  = "geometric"  // 等比
+)
+
+const (
+	gridMaxCount           = 200
+	gridPlaceGracePeriod   = 5 * time.Second  // 刚挂出的单可能尚未出现在 openOrders 中，对账时跳过
+	gridReconcileInterval  = 15 * time.Second // REST 对账兜底周期（私有WS事件为主）
+	gridOrderCallTimeout   = 10 * time.Second
+	gridStartTickerTimeout = 10 * time.Second
+	gridStartRetryMin      = 5 * time.Second // 启动失败后的首次重试间隔，之后按倍数退避
+	gridStartRetryMax      = 5 * time.Minute
+	gridFillEpsilon        = 1e-6 // 成交数量的相对误差，剩余不足该比例视为整格成交
+)
+
+// GridConfig 网格配置（存储于 trading_robot.grid_config）
+type GridConfig struct {
+	LowerPrice      float64 `json:"lowerPrice"`      // 下限价格
+	UpperPrice      float64 `json:"upperPrice"`      // 上限价格
+	GridCount       int     `json:"gridCount"`       // 网格数（格子数 = 价格档位数 - 1）
+	Spacing         string  `json:"spacing"`         // 间距：arithmetic/geometric
+	Bias            string  `json:"bias"`            // 方向：long/short/neutral
+	QuantityPerGrid float64 `json:"quantityPerGrid"` // 每格下单数量（基础币）
+	Leverage        int     `json:"leverage"`        // 杠杆倍数
+}
+
+// ParseGridConfig 解析并校验网格配置
+func ParseGridConfig(raw string) (*GridConfig, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, gerror.New("网格配置为空")
+	}
+	var cfg *GridConfig
+	if err := gjson.DecodeTo(raw, &cfg); err != nil || cfg == nil {
+		return nil, gerror.New("网格配置格式错误")
+	}
+	if err := cfg.Normalize(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Normalize 填充默认值并校验
+func (c *GridConfig) Normalize() error {
+	c.Spacing = strings.ToLower(strings.TrimSpace(c.Spacing))
+	if c.Spacing == "" {
+		c.Spacing = GridSpacingArithmetic
+	}
+	c.Bias = strings.ToLower(strings.TrimSpace(c.Bias))
+	if c.Bias == "" {
+		c.Bias = GridBiasNeutral
+	}
+	if c.Leverage <= 0 {
+		c.Leverage = 5
+	}
+
+	if c.LowerPrice <= 0 || c.UpperPrice <= c.LowerPrice {
+		return gerror.New("网格价格区间无效：需满足 0 < 下限 < 上限")
+	}
+	if c.GridCount < 2 || c.GridCount > gridMaxCount {
+		return gerror.Newf("网格数需在 2~%d 之间", gridMaxCount)
+	}
+	if c.QuantityPerGrid <= 0 {
+		return gerror.New("每格数量必须大于0")
+	}
+	if c.Spacing != GridSpacingArithmetic && c.Spacing != GridSpacingGeometric {
+		return gerror.Newf("不支持的网格间距: %s", c.Spacing)
+	}
+	if c.Bias != GridBiasLong && c.Bias != GridBiasShort && c.Bias != GridBiasNeutral {
+		return gerror.Newf("不支持的网格方向: %s", c.Bias)
+	}
+	return nil
+}
+
+// Levels 计算价格档位（共 GridCount+1 档，自下而上）
+func (c *GridConfig) Levels() []float64 {
+	levels := make([]float64, c.GridCount+1)
+	if c.Spacing == GridSpacingGeometric {
+		ratio := math.Pow(c.UpperPrice/c.LowerPrice, 1/float64(c.GridCount))
+		for i := range levels {
+			levels[i] = c.LowerPrice * math.Pow(ratio, float64(i))
+		}
+	} else {
+		step := (c.UpperPrice - c.LowerPrice) / float64(c.GridCount)
+		for i := range levels {
+			levels[i] = c.LowerPrice + step*float64(i)
+		}
+	}
+	levels[c.GridCount] = c.UpperPrice
+	return levels
+}
+
+// GridTrader 网格交易执行器（每个网格模式机器人一个）
+// 状态机（每格始终只有一张挂单）：
+//   - long 格：空仓挂 BUY@下沿（开多）→ 成交后挂 SELL@上沿（平多）→ 成交后记一次利润并回到空仓
+//   - short 格：空仓挂 SELL@上沿（开空）→ 成交后挂 BUY@下沿（平空）→ 成交后记一次利润并回到空仓
+//
+// 部分成交：挂单部分成交期间只记录 order_filled_qty；挂单部分成交后失效时，成交部分计入本轮 filled_qty，
+// 按剩余数量补挂同方向的单，本轮累计成交满一格后才翻转。
+// 交易所请求期间不持有 t.mu，正在挂单的格子记录在 placing 中，避免 WS 事件与对账重复挂单。
+type GridTrader struct {
+	engine *RobotEngine
+	cfg    *GridConfig
+
+	mu       sync.Mutex
+	cells    []*entity.TradingGridCell
+	byOrder  map[string]*entity.TradingGridCell
+	placedAt map[int]time.Time // cellIndex -> 最近一次挂单时间
+	placing  map[int]bool      // cellIndex -> 正在向交易所挂单
+	ready    bool
+}
+
+// gridOrderUpdate 网格挂单的状态（来自私有WS事件或按订单ID查询）
+type gridOrderUpdate struct {
+	OrderId   string
+	Status    string  // 归一化状态：NEW/PARTIALLY_FILLED/FILLED/CANCELED/EXPIRED/REJECTED
+	Quantity  float64 // 订单数量（单位可能是合约张数，只用于计算成交比例）
+	FilledQty float64 // 订单累计成交数量（与 Quantity 同单位）
+	AvgPrice  float64
+}
+
+// gridRoundTrip 一次完成的网格套利，解锁后落库
+type gridRoundTrip struct {
+	CellIndex    int
+	Direction    string
+	OpenPrice    float64
+	ClosePrice   float64
+	Quantity     float64
+	Profit       float64
+	CloseOrderId string
+}
+
+// NewGridTrader 创建网格执行器
+func NewGridTrader(engine *RobotEngine) (*GridTrader, error) {
+	cfg, err := ParseGridConfig(engine.Robot.GridConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &GridTrader{
+		engine:   engine,
+		cfg:      cfg,
+		byOrder:  make(map[string]*entity.TradingGridCell),
+		placedAt: make(map[int]time.Time),
+		placing:  make(map[int]bool),
+	}, nil
+}
+
+// Run 网格主循环：初始化格子后按周期做 REST 对账（成交以私有WS事件驱动为主）
+// 启动失败（行情未就绪、交易所/数据库暂时不可用等）按指数退避重试，直到启动成功或机器人停止。
+func (t *GridTrader) Run(ctx context.Context, stopCh <-chan struct{}) {
+	robot := t.engine.Robot
+	backoff := gridStartRetryMin
+	for {
+		err := t.start(ctx, stopCh)
+		if err == nil {
+			break
+		}
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+		g.Log().Errorf(ctx, "[Grid] robotId=%d 网格启动失败，%v 后重试: %v", robot.Id, backoff, err)
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > gridStartRetryMax {
+			backoff = gridStartRetryMax
+		}
+	}
+
+	ticker := time.NewTicker(gridReconcileInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			t.Reconcile(ctx)
+		}
+	}
+}
+
+// start 设置杠杆、加载或初始化格子并完成首轮挂单
+func (t *GridTrader) start(ctx context.Context, stopCh <-chan struct{}) error {
+	robot := t.engine.Robot
+
+	levCtx, cancel := context.WithTimeout(ctx, gridOrderCallTimeout)
+	if err := t.engine.Exchange.SetLeverage(levCtx, robot.Symbol, t.cfg.Leverage); err != nil {
+		g.Log().Warningf(ctx, "[Grid] robotId=%d 设置杠杆失败(继续): %v", robot.Id, err)
+	}
+	cancel()
+
+	var cells []*entity.TradingGridCell
+	err := dao.TradingGridCell.Ctx(ctx).
+		Where(dao.TradingGridCell.Columns().RobotId, robot.Id).
+		OrderAsc(dao.TradingGridCell.Columns().CellIndex).
+		Scan(&cells)
+	if err != nil {
+		return gerror.Wrap(err, "加载网格状态失败")
+	}
+
+	if len(cells) > 0 && !t.matchesConfig(cells) {
+		for _, c := range cells {
+			if c.Holding == 1 || c.FilledQty > 0 || c.OrderFilledQty > 0 {
+				return gerror.New("网格配置已变更，但旧网格仍有持仓，请先平仓后再启动")
+			}
+		}
+		t.cancelCellOrders(ctx, cells)
+		if _, err = dao.TradingGridCell.Ctx(ctx).Where(dao.TradingGridCell.Columns().RobotId, robot.Id).Delete(); err != nil {
+			return gerror.Wrap(err, "清理旧网格失败")
+		}
+		cells = nil
+	}
+
+	if len(cells) == 0 {
+		price, err := t.waitPrice(stopCh)
+		if err != nil {
+			return err
+		}
+		if cells, err = t.initCells(ctx, price); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	select {
+	case <-stopCh:
+		// 初始化期间机器人已停止：不再进入就绪状态（CancelAll 已执行过）
+		t.mu.Unlock()
+		return gerror.New("机器人已停止")
+	default:
+	}
+	t.cells = cells
+	for _, c := range cells {
+		if c.OrderId != "" {
+			t.byOrder[c.OrderId] = c
+		}
+	}
+	t.ready = true
+	t.mu.Unlock()
+
+	g.Log().Infof(ctx, "[Grid] robotId=%d 网格已就绪: symbol=%s, 区间=[%.4f, %.4f], 格数=%d, 方向=%s, 间距=%s, 每格数量=%.6f",
+		robot.Id, robot.Symbol, t.cfg.LowerPrice, t.cfg.UpperPrice, t.cfg.GridCount, t.cfg.Bias, t.cfg.Spacing, t.cfg.QuantityPerGrid)
+
+	// 首轮对账：补挂缺失的单、识别停机期间已成交的单
+	t.Reconcile(ctx)
+	return nil
+}
+
+// matchesConfig 已持久化的格子是否与当前配置一致
+func (t *GridTrader) matchesConfig(cells []*entity.TradingGridCell) bool {
+	levels := t.cfg.Levels()
+	if len(cells) != len(levels)-1 {
+		return false
+	}
+	for i, c := range cells {
+		if c.CellIndex != i ||
+			!gridPriceEqual(c.LowerPrice, levels[i]) ||
+			!gridPriceEqual(c.UpperPrice, levels[i+1]) ||
+			!gridPriceEqual(c.Quantity, t.cfg.QuantityPerGrid) {
+			return false
+		}
+	}
+	return true
+}
+
+func gridPriceEqual(a, b float64) bool {
+	return math.Abs(a-b) <= math.Max(math.Abs(b)*1e-8, 1e-8)
+}
+
+// waitPrice 等待行情就绪
+func (t *GridTrader) waitPrice(stopCh <-chan struct{}) (float64, error) {
+	deadline := time.Now().Add(gridStartTickerTimeout)
+	for time.Now().Before(deadline) {
+		tk := market.GetMarketServiceManager().GetTicker(t.engine.Platform, t.engine.Robot.Symbol)
+		if tk == nil {
+			t.engine.mu.RLock()
+			tk = t.engine.LastTicker
+			t.engine.mu.RUnlock()
+		}
+		if tk != nil && tk.LastPrice > 0 {
+			return tk.LastPrice, nil
+		}
+		select {
+		case <-stopCh:
+			return 0, gerror.New("机器人已停止")
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+	return 0, gerror.New("等待行情超时，无法初始化网格")
+}
+
+// initCells 根据现价划分格子方向，并为需要“持仓起步”的格子市价建立底仓
+// - long：现价以上的格子需持有多仓（挂卖单平多）；其余挂买单开多
+// - short：现价以下的格子需持有空仓（挂买单平空）；其余挂卖单开空
+// - neutral：现价以下为 long 格、以上为 short 格，均空仓起步（不建底仓）
+// 格子先以空仓落库再建底仓：建底仓失败时启动重试会加载已有格子，不会重复建仓。
+func (t *GridTrader) initCells(ctx context.Context, price float64) ([]*entity.TradingGridCell, error) {
+	robot := t.engine.Robot
+	levels := t.cfg.Levels()
+	cells := make([]*entity.TradingGridCell, 0, len(levels)-1)
+	seeded := make(map[int]bool)
+
+	var longSeed, shortSeed int
+	for i := 0; i < len(levels)-1; i++ {
+		c := &entity.TradingGridCell{
+			RobotId:    robot.Id,
+			CellIndex:  i,
+			LowerPrice: levels[i],
+			UpperPrice: levels[i+1],
+			Quantity:   t.cfg.QuantityPerGrid,
+			Direction:  GridBiasLong,
+		}
+		switch t.cfg.Bias {
+		case GridBiasLong:
+			if levels[i] >= price {
+				seeded[i] = true
+				longSeed++
+			}
+		case GridBiasShort:
+			c.Direction = GridBiasShort
+			if levels[i+1] <= price {
+				seeded[i] = true
+				shortSeed++
+			}
+		default:
+			if levels[i] >= price {
+				c.Direction = GridBiasShort
+			}
+		}
+		cells = append(cells, c)
+	}
+
+	err := dao.TradingGridCell.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		for _, c := range cells {
+			_, err := dao.TradingGridCell.Ctx(ctx).Data(g.Map{
+				"robot_id":    c.RobotId,
+				"cell_index":  c.CellIndex,
+				"lower_price": c.LowerPrice,
+				"upper_price": c.UpperPrice,
+				"direction":   c.Direction,
+				"quantity":    c.Quantity,
+			}).Insert()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, gerror.Wrap(err, "保存网格状态失败")
+	}
+
+	// 建立底仓（一笔市价单覆盖全部持仓起步的格子）
+	seed := func(count int, side, positionSide string) (float64, error) {
+		if count == 0 {
+			return 0, nil
+		}
+		req := &exchange.OrderRequest{
+			Symbol:       robot.Symbol,
+			Side:         side,
+			PositionSide: positionSide,
+			Type:         "MARKET",
+			Quantity:     t.cfg.QuantityPerGrid * float64(count),
+		}
+		callCtx, cancel := context.WithTimeout(ctx, gridOrderCallTimeout)
+		defer cancel()
+		order, err := t.engine.Exchange.CreateOrder(callCtx, req)
+		if err != nil {
+			return 0, gerror.Wrapf(err, "网格建立底仓失败(%s %d格)", positionSide, count)
+		}
+		t.persistOrder(ctx, order, req)
+		entry := price
+		if order != nil && order.AvgPrice > 0 {
+			entry = order.AvgPrice
+		}
+		return entry, nil
+	}
+	longEntry, err := seed(longSeed, "BUY", "LONG")
+	if err != nil {
+		return nil, err
+	}
+	shortEntry, err := seed(shortSeed, "SELL", "SHORT")
+	if err != nil {
+		return nil, err
+	}
+
+	// 底仓已建立：此后以内存状态为准，落库失败只记录日志，后续挂单时 saveCell 会再次写入持仓状态
+	for _, c := range cells {
+		if !seeded[c.CellIndex] {
+			continue
+		}
+		c.Holding = 1
+		if c.Direction == GridBiasLong {
+			c.EntryPrice = longEntry
+		} else {
+			c.EntryPrice = shortEntry
+		}
+		t.saveCell(ctx, c)
+	}
+	g.Log().Infof(ctx, "[Grid] robotId=%d 网格初始化完成: 现价=%.4f, 多头底仓格=%d, 空头底仓格=%d", robot.Id, price, longSeed, shortSeed)
+	return cells, nil
+}
+
+// cellOrderSpec 计算格子当前应挂的单
+func cellOrderSpec(c *entity.TradingGridCell) (side, positionSide string, price float64, reduceOnly bool) {
+	if c.Direction == GridBiasShort {
+		if c.Holding == 1 {
+			return "BUY", "SHORT", c.LowerPrice, true
+		}
+		return "SELL", "SHORT", c.UpperPrice, false
+	}
+	if c.Holding == 1 {
+		return "SELL", "LONG", c.UpperPrice, true
+	}
+	return "BUY", "LONG", c.LowerPrice, false
+}
+
+// placeCellOrders 为空挂单的格子挂单（调用方不持有 t.mu）
+func (t *GridTrader) placeCellOrders(ctx context.Context, cells []*entity.TradingGridCell) {
+	for _, c := range cells {
+		t.placeCellOrder(ctx, c)
+	}
+}
+
+// placeCellOrder 为格子挂出本轮剩余数量的限价单（调用方不持有 t.mu）
+// 下单请求期间不持锁；挂单期间机器人被停止时，撤掉刚挂出的单，订单ID保留给重启后的对账。
+func (t *GridTrader) placeCellOrder(ctx context.Context, c *entity.TradingGridCell) {
+	robot := t.engine.Robot
+
+	t.mu.Lock()
+	if !t.ready || c.OrderId != "" || t.placing[c.CellIndex] {
+		t.mu.Unlock()
+		return
+	}
+	side, positionSide, price, reduceOnly := cellOrderSpec(c)
+	quantity := c.Quantity - c.FilledQty
+	t.placing[c.CellIndex] = true
+	t.placedAt[c.CellIndex] = time.Now()
+	t.mu.Unlock()
+
+	// 订阅宽限期只挂平仓单，开仓格子等续费后由对账补挂
+	if !reduceOnly && SubscriptionOpenBlocked(ctx, robot.UserId) {
+		t.mu.Lock()
+		delete(t.placing, c.CellIndex)
+		t.mu.Unlock()
+		return
+	}
+
+	req := &exchange.OrderRequest{
+		Symbol:       robot.Symbol,
+		Side:         side,
+		PositionSide: positionSide,
+		Type:         "LIMIT",
+		Quantity:     quantity,
+		Price:        price,
+		ReduceOnly:   reduceOnly,
+	}
+	callCtx, cancel := context.WithTimeout(ctx, gridOrderCallTimeout)
+	order, err := t.engine.Exchange.CreateOrder(callCtx, req)
+	cancel()
+
+	t.mu.Lock()
+	delete(t.placing, c.CellIndex)
+	t.placedAt[c.CellIndex] = time.Now()
+	if err != nil || order == nil || order.OrderId == "" {
+		t.mu.Unlock()
+		g.Log().Warningf(ctx, "[Grid] robotId=%d cell=%d 挂单失败(稍后对账重试): %s %s %.6f@%.4f err=%v",
+			robot.Id, c.CellIndex, side, positionSide, quantity, price, err)
+		return
+	}
+	t.setCellOrderLocked(ctx, c, order.OrderId, side, price)
+	stopped := !t.ready
+	t.mu.Unlock()
+
+	t.persistOrder(ctx, order, req)
+	if stopped {
+		callCtx, cancel = context.WithTimeout(ctx, gridOrderCallTimeout)
+		if _, err = t.engine.Exchange.CancelOrder(callCtx, robot.Symbol, order.OrderId); err != nil {
+			g.Log().Debugf(ctx, "[Grid] robotId=%d cell=%d 停止后撤单失败(可能已成交): %v", robot.Id, c.CellIndex, err)
+		}
+		cancel()
+	}
+}
+
+// persistOrder 补全下单返回中缺失的字段后写入订单事实表
+func (t *GridTrader) persistOrder(ctx context.Context, order *exchange.Order, req *exchange.OrderRequest) {
+	if order == nil || order.OrderId == "" {
+		return
+	}
+	rec := *order
+	if rec.Side == "" {
+		rec.Side = req.Side
+	}
+	if rec.PositionSide == "" {
+		rec.PositionSide = req.PositionSide
+	}
+	if rec.Type == "" {
+		rec.Type = req.Type
+	}
+	if rec.Price <= 0 {
+		rec.Price = req.Price
+	}
+	if rec.Quantity <= 0 {
+		rec.Quantity = req.Quantity
+	}
+	rec.ReduceOnly = rec.ReduceOnly || req.ReduceOnly
+	switch normalizeOrderStatus(t.engine.Platform, rec.Status) {
+	case "NEW", "PARTIALLY_FILLED", "FILLED", "CANCELED", "EXPIRED", "REJECTED":
+	default:
+		// 下单接口只返回受理结果（如 OKX sCode），以新订单落库，后续由WS事件/对账更新
+		rec.Status = "NEW"
+	}
+	if err := UpsertExchangeOrder(ctx, t.engine.Robot.Id, &rec); err != nil {
+		g.Log().Warningf(ctx, "[Grid] robotId=%d 网格订单落库失败: orderId=%s err=%v", t.engine.Robot.Id, rec.OrderId, err)
+	}
+}
+
+// setCellOrderLocked 更新格子挂单并持久化
+func (t *GridTrader) setCellOrderLocked(ctx context.Context, c *entity.TradingGridCell, orderId, side string, price float64) {
+	if c.OrderId != "" {
+		delete(t.byOrder, c.OrderId)
+	}
+	c.OrderId, c.OrderSide, c.OrderPrice = orderId, side, price
+	c.OrderFilledQty = 0
+	if orderId != "" {
+		t.byOrder[orderId] = c
+	}
+	t.saveCell(ctx, c)
+}
+
+func (t *GridTrader) saveCell(ctx context.Context, c *entity.TradingGridCell) {
+	_, err := dao.TradingGridCell.Ctx(ctx).
+		Where(dao.TradingGridCell.Columns().RobotId, c.RobotId).
+		Where(dao.TradingGridCell.Columns().CellIndex, c.CellIndex).
+		Data(g.Map{
+			"holding":          c.Holding,
+			"order_id":         c.OrderId,
+			"order_side":       c.OrderSide,
+			"order_price":      c.OrderPrice,
+			"filled_qty":       c.FilledQty,
+			"filled_price":     c.FilledPrice,
+			"order_filled_qty": c.OrderFilledQty,
+			"entry_price":      c.EntryPrice,
+			"round_trips":      c.RoundTrips,
+			"realized_profit":  c.RealizedProfit,
+			"updated_at":       gtime.Now(),
+		}).Update()
+	if err != nil {
+		g.Log().Warningf(ctx, "[Grid] robotId=%d cell=%d 保存状态失败: %v", c.RobotId, c.CellIndex, err)
+	}
+}
+
+// OnOrderEvent 私有WS订单事件：跟踪网格挂单的部分成交/完全成交/失效并翻转或补挂
+func (t *GridTrader) OnOrderEvent(ctx context.Context, ev *exchange.PrivateEvent) {
+	if ev == nil || ev.Type != exchange.PrivateEventOrder {
+		return
+	}
+	var (
+		place []*entity.TradingGridCell
+		trips []*gridRoundTrip
+	)
+	for _, o := range parsePrivateOrderEvent(strings.ToLower(ev.Platform), ev.Raw) {
+		if o.ExchangeOrderId == "" {
+			continue
+		}
+		// 部分交易所（如 Gate）撤单也推送终态 finished，成交数量不足时交给对账按订单ID确认
+		if o.Status == "FILLED" && o.Quantity > 0 && o.FilledQty < o.Quantity*(1-gridFillEpsilon) {
+			continue
+		}
+		u := &gridOrderUpdate{
+			OrderId:   o.ExchangeOrderId,
+			Status:    o.Status,
+			Quantity:  o.Quantity,
+			FilledQty: o.FilledQty,
+			AvgPrice:  o.AvgPrice,
+		}
+		if u.AvgPrice <= 0 {
+			u.AvgPrice = o.Price
+		}
+
+		t.mu.Lock()
+		if c := t.byOrder[u.OrderId]; c != nil && c.OrderId == u.OrderId {
+			if trip, done := t.applyOrderUpdateLocked(ctx, c, u); done {
+				place = append(place, c)
+				if trip != nil {
+					trips = append(trips, trip)
+				}
+			}
+		}
+		t.mu.Unlock()
+	}
+	t.finishUpdates(ctx, place, trips)
+}
+
+// finishUpdates 记录完成的套利并为已结束挂单的格子补挂（调用方不持有 t.mu）
+func (t *GridTrader) finishUpdates(ctx context.Context, place []*entity.TradingGridCell, trips []*gridRoundTrip) {
+	for _, trip := range trips {
+		t.recordRoundTrip(ctx, trip)
+	}
+	t.placeCellOrders(ctx, place)
+}
+
+// applyOrderUpdateLocked 应用格子当前挂单的状态（调用方持有 t.mu）
+// 返回 done=true 表示挂单已结束、格子需要补挂；本轮成交满一格并平仓时返回完成的套利记录。
+func (t *GridTrader) applyOrderUpdateLocked(ctx context.Context, c *entity.TradingGridCell, u *gridOrderUpdate) (trip *gridRoundTrip, done bool) {
+	// 订单数量可能是合约张数，按成交比例折算为本单（本轮剩余数量）的基础币成交数量
+	orderQty := c.Quantity - c.FilledQty
+	filled := u.FilledQty
+	if u.Quantity > 0 {
+		filled = orderQty * math.Min(u.FilledQty/u.Quantity, 1)
+	}
+
+	switch u.Status {
+	case "NEW":
+		return nil, false
+	case "PARTIALLY_FILLED":
+		if filled > c.OrderFilledQty {
+			c.OrderFilledQty = filled
+			t.saveCell(ctx, c)
+		}
+		return nil, false
+	case "FILLED":
+		filled = orderQty
+	default:
+		// 已撤销/过期/拒绝：成交部分计入本轮，剩余数量重新挂单
+		if filled > 0 {
+			g.Log().Warningf(ctx, "[Grid] robotId=%d cell=%d 挂单部分成交后失效(orderId=%s filled=%.6f/%.6f)，补挂剩余数量",
+				c.RobotId, c.CellIndex, u.OrderId, filled, orderQty)
+		}
+	}
+
+	if filled > 0 {
+		price := u.AvgPrice
+		if price <= 0 {
+			price = c.OrderPrice
+		}
+		total := c.FilledQty + filled
+		c.FilledPrice = (c.FilledPrice*c.FilledQty + price*filled) / total
+		c.FilledQty = total
+	}
+	if c.FilledQty < c.Quantity*(1-gridFillEpsilon) {
+		t.setCellOrderLocked(ctx, c, "", "", 0)
+		return nil, true
+	}
+	return t.completeLegLocked(ctx, c, u.OrderId), true
+}
+
+// completeLegLocked 本轮成交满一格：翻转格子状态（调用方持有 t.mu）
+func (t *GridTrader) completeLegLocked(ctx context.Context, c *entity.TradingGridCell, closeOrderId string) (trip *gridRoundTrip) {
+	fillPrice := c.FilledPrice
+	if fillPrice <= 0 {
+		fillPrice = c.OrderPrice
+	}
+	if c.Holding == 0 {
+		c.Holding = 1
+		c.EntryPrice = fillPrice
+	} else {
+		profit := (fillPrice - c.EntryPrice) * c.Quantity
+		if c.Direction == GridBiasShort {
+			profit = -profit
+		}
+		trip = &gridRoundTrip{
+			CellIndex:    c.CellIndex,
+			Direction:    c.Direction,
+			OpenPrice:    c.EntryPrice,
+			ClosePrice:   fillPrice,
+			Quantity:     c.Quantity,
+			Profit:       profit,
+			CloseOrderId: closeOrderId,
+		}
+		c.Holding = 0
+		c.RoundTrips++
+		c.RealizedProfit += profit
+		c.EntryPrice = 0
+	}
+	c.FilledQty, c.FilledPrice = 0, 0
+	t.setCellOrderLocked(ctx, c, "", "", 0)
+	return trip
+}
+
+// recordRoundTrip 记录一次网格利润：落库、累计机器人盈亏、按已实现利润消耗算力
+func (t *GridTrader) recordRoundTrip(ctx context.Context, trip *gridRoundTrip) {
+	robot := t.engine.Robot
+	var tradeId int64
+	err := dao.TradingGridTrade.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		_, err := dao.TradingGridTrade.Ctx(ctx).Data(g.Map{
+			"user_id":        robot.UserId,
+			"robot_id":       robot.Id,
+			"symbol":         robot.Symbol,
+			"cell_index":     trip.CellIndex,
+			"direction":      trip.Direction,
+			"open_price":     trip.OpenPrice,
+			"close_price":    trip.ClosePrice,
+			"quantity":       trip.Quantity,
+			"profit":         trip.Profit,
+			"close_order_id": trip.CloseOrderId,
+		}).Insert()
+		if err != nil {
+			return err
+		}
+		// 【PostgreSQL 兼容】InsertAndGetId() 不支持 PostgreSQL，改用 LASTVAL()
+		val, err := tx.GetValue("SELECT LASTVAL()")
+		if err != nil {
+			return err
+		}
+		tradeId = val.Int64()
+		return nil
+	})
+	if err != nil {
+		// 唯一键 (robot_id, close_order_id) 冲突说明已记录过（WS 与对账重复），不再重复累计
+		if isDuplicateKeyErr(err) {
+			return
+		}
+		g.Log().Warningf(ctx, "[Grid] robotId=%d cell=%d 记录网格利润失败: %v", robot.Id, trip.CellIndex, err)
+		return
+	}
+
+	_, _ = dao.TradingRobot.Ctx(ctx).
+		Where(dao.TradingRobot.Columns().Id, robot.Id).
+		Increment(dao.TradingRobot.Columns().TotalProfit, trip.Profit)
+
+	g.Log().Infof(ctx, "[Grid] robotId=%d cell=%d 完成一次网格套利: %s 开=%.4f 平=%.4f 数量=%.6f 利润=%.4f",
+		robot.Id, trip.CellIndex, trip.Direction, trip.OpenPrice, trip.ClosePrice, trip.Quantity, trip.Profit)
+
+	if trip.Profit > 0 {
+		orderSn := fmt.Sprintf("GRID%d-%d", robot.Id, tradeId)
+		if err := service.ToogoWallet().ConsumePower(ctx, robot.UserId, robot.Id, tradeId, orderSn, trip.Profit); err != nil {
+			g.Log().Warningf(ctx, "[Grid] robotId=%d 网格利润 %s 扣除算力失败: %v", robot.Id, orderSn, err)
+		}
+	}
+}
+
+// Reconcile REST 对账兜底：按订单ID查询不在 openOrders 中的格子挂单，识别成交（WS 丢事件/停机期间）、
+// 部分成交与失效，并为空挂单的格子补挂。交易所请求期间不持有 t.mu。
+func (t *GridTrader) Reconcile(ctx context.Context) {
+	t.mu.Lock()
+	ready := t.ready
+	t.mu.Unlock()
+	if !ready {
+		return
+	}
+	robot := t.engine.Robot
+
+	callCtx, cancel := context.WithTimeout(ctx, gridOrderCallTimeout)
+	openOrders, err := t.engine.Exchange.GetOpenOrders(callCtx, robot.Symbol)
+	cancel()
+	if err != nil {
+		g.Log().Debugf(ctx, "[Grid] robotId=%d 获取挂单失败，跳过本轮对账: %v", robot.Id, err)
+		return
+	}
+	open := make(map[string]*exchange.Order, len(openOrders))
+	for _, o := range openOrders {
+		if o != nil && o.OrderId != "" {
+			open[o.OrderId] = o
+		}
+	}
+
+	type cellOrder struct {
+		cell    *entity.TradingGridCell
+		orderId string
+	}
+	var (
+		place  []*entity.TradingGridCell
+		trips  []*gridRoundTrip
+		checks []cellOrder
+	)
+	t.mu.Lock()
+	if !t.ready {
+		t.mu.Unlock()
+		return
+	}
+	for _, c := range t.cells {
+		if t.placing[c.CellIndex] {
+			continue
+		}
+		if o := open[c.OrderId]; c.OrderId != "" && o != nil {
+			// 仍在挂单中：同步部分成交数量
+			t.applyOrderUpdateLocked(ctx, c, &gridOrderUpdate{
+				OrderId:   o.OrderId,
+				Status:    "PARTIALLY_FILLED",
+				Quantity:  o.Quantity,
+				FilledQty: o.FilledQty,
+			})
+			continue
+		}
+		if at, ok := t.placedAt[c.CellIndex]; ok && time.Since(at) < gridPlaceGracePeriod {
+			continue
+		}
+		if c.OrderId == "" {
+			place = append(place, c)
+			continue
+		}
+		checks = append(checks, cellOrder{cell: c, orderId: c.OrderId})
+	}
+	t.mu.Unlock()
+
+	// 挂单已不在 openOrders：按订单ID查询是成交、部分成交后失效还是被撤
+	for _, check := range checks {
+		u := &gridOrderUpdate{OrderId: check.orderId}
+		qCtx, qCancel := context.WithTimeout(ctx, gridOrderCallTimeout)
+		o, err := exchange.QueryOrder(qCtx, t.engine.Exchange, robot.Symbol, check.orderId)
+		qCancel()
+		switch {
+		case err == nil && o != nil:
+			if err := UpsertExchangeOrder(ctx, robot.Id, o); err != nil {
+				g.Log().Debugf(ctx, "[Grid] robotId=%d 网格订单落库失败: orderId=%s err=%v", robot.Id, check.orderId, err)
+			}
+			u.Status = normalizeOrderStatus(t.engine.Platform, o.Status)
+			u.Quantity, u.FilledQty, u.AvgPrice = o.Quantity, o.FilledQty, o.AvgPrice
+			if u.AvgPrice <= 0 {
+				u.AvgPrice = o.Price
+			}
+		case exchange.IsOrderNotFound(err):
+			// 交易所只会归档无成交的撤单，按无成交撤单处理
+			u.Status = "CANCELED"
+		default:
+			g.Log().Debugf(ctx, "[Grid] robotId=%d cell=%d 查询订单失败，下轮重试: orderId=%s err=%v",
+				robot.Id, check.cell.CellIndex, check.orderId, err)
+			continue
+		}
+
+		t.mu.Lock()
+		// 查询期间格子可能已被WS事件翻转
+		if check.cell.OrderId == check.orderId {
+			if trip, done := t.applyOrderUpdateLocked(ctx, check.cell, u); done {
+				place = append(place, check.cell)
+				if trip != nil {
+					trips = append(trips, trip)
+				}
+			}
+		}
+		t.mu.Unlock()
+	}
+	t.finishUpdates(ctx, place, trips)
+}
+
+// CancelAll 撤销全部网格挂单（停止/暂停时调用；持仓保留）
+// 格子上的订单ID不清空：撤单前可能刚好成交，重启后由 Reconcile 按订单ID查询区分“已成交/已撤”再续挂。
+func (t *GridTrader) CancelAll(ctx context.Context) {
+	t.mu.Lock()
+	t.ready = false
+	cells := make([]*entity.TradingGridCell, 0, len(t.cells))
+	for _, c := range t.cells {
+		if c.OrderId != "" {
+			snapshot := *c
+			cells = append(cells, &snapshot)
+		}
+	}
+	t.mu.Unlock()
+	t.cancelCellOrders(ctx, cells)
+}
+
+// cancelCellOrders 撤销格子挂单（调用方不持有 t.mu，cells 不能与运行中的格子共享）
+func (t *GridTrader) cancelCellOrders(ctx context.Context, cells []*entity.TradingGridCell) {
+	robot := t.engine.Robot
+	for _, c := range cells {
+		if c.OrderId == "" {
+			continue
+		}
+		callCtx, cancel := context.WithTimeout(ctx, gridOrderCallTimeout)
+		if _, err := t.engine.Exchange.CancelOrder(callCtx, robot.Symbol, c.OrderId); err != nil {
+			g.Log().Debugf(ctx, "[Grid] robotId=%d cell=%d 撤单失败(可能已成交/已撤): %v", robot.Id, c.CellIndex, err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,494 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 分批加仓（DCA）：持仓逆向波动达到步长时按倍数追加开仓，止损/止盈按加权均价与总保证金计算
+package toogo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"hotgo/internal/dao"
+	"hotgo/internal/library/exchange"
+	"hotgo/internal/model/entity"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// 加仓配置上限（防止模板误配导致无限加仓）
+const (
+	scaleMaxEntriesLimit    = 10
+	scaleMaxMultiplierLimit = 5.0
+)
+
+// scaleInRetryDelay 加仓下单失败后的重试间隔
+const scaleInRetryDelay = 30 * time.Second
+
+// ScaleConfig 分批加仓配置
+// 来源：策略模板 config_json.scaling；开仓时连同计算出的保证金上限一起冻结到订单 scale_config，之后不随模板变化。
+type ScaleConfig struct {
+	Enabled               bool    `json:"enabled"`               // 是否启用
+	MaxEntries            int     `json:"maxEntries"`            // 最大加仓次数（不含首单）
+	StepPercent           float64 `json:"stepPercent"`           // 触发步长：相对上一批成交价逆向波动的百分比(%)
+	SizeMultiplier        float64 `json:"sizeMultiplier"`        // 数量倍数：第 n 次加仓数量 = 首单数量 × 倍数^n
+	MaxTotalMarginPercent float64 `json:"maxTotalMarginPercent"` // 总保证金上限：占首单开仓前可用余额的百分比(%)
+
+	// 以下为开仓时冻结的字段
+	MarginCap    float64 `json:"marginCap,omitempty"`    // 总保证金硬上限(USDT)
+	BaseQuantity float64 `json:"baseQuantity,omitempty"` // 首单数量
+	Leverage     int     `json:"leverage,omitempty"`     // 开仓杠杆（加仓沿用）
+}
+
+// ParseScaleConfig 从策略模板 config_json 解析加仓配置，未启用或配置无效时返回 nil
+func ParseScaleConfig(configJson string) *ScaleConfig {
+	configJson = strings.TrimSpace(configJson)
+	if configJson == "" {
+		return nil
+	}
+	var wrapper struct {
+		Scaling *ScaleConfig `json:"scaling"`
+	}
+	if err := json.Unmarshal([]byte(configJson), &wrapper); err != nil || wrapper.Scaling == nil {
+		return nil
+	}
+	return wrapper.Scaling.Normalize()
+}
+
+// parseFrozenScaleConfig 解析订单上冻结的加仓配置
+func parseFrozenScaleConfig(raw string) *ScaleConfig {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	var c ScaleConfig
+	if err := json.Unmarshal([]byte(raw), &c); err != nil {
+		return nil
+	}
+	return c.Normalize()
+}
+
+// Normalize 校验并裁剪配置，无效时返回 nil
+func (c *ScaleConfig) Normalize() *ScaleConfig {
+	if c == nil || !c.Enabled || c.MaxEntries <= 0 || c.StepPercent <= 0 || c.MaxTotalMarginPercent <= 0 {
+		return nil
+	}
+	if c.MaxEntries > scaleMaxEntriesLimit {
+		c.MaxEntries = scaleMaxEntriesLimit
+	}
+	if c.SizeMultiplier <= 0 {
+		c.SizeMultiplier = 1
+	}
+	if c.SizeMultiplier > scaleMaxMultiplierLimit {
+		c.SizeMultiplier = scaleMaxMultiplierLimit
+	}
+	return c
+}
+
+// TriggerPrice 返回下一次加仓的触发价
+func (c *ScaleConfig) TriggerPrice(positionSide string, lastEntryPrice float64) float64 {
+	if strings.EqualFold(positionSide, "SHORT") {
+		return lastEntryPrice * (1 + c.StepPercent/100)
+	}
+	return lastEntryPrice * (1 - c.StepPercent/100)
+}
+
+// AddOnQuantity 返回第 n 次加仓（n 从 1 开始）的计划数量
+func (c *ScaleConfig) AddOnQuantity(n int) float64 {
+	return c.BaseQuantity * math.Pow(c.SizeMultiplier, float64(n))
+}
+
+// freezeScaleOnOpen 首单成交后冻结加仓配置并记录第0批明细
+// availableBalance 为首单开仓前的可用余额，用于换算总保证金硬上限。
+func (t *RobotTrader) freezeScaleOnOpen(ctx context.Context, tracker *PositionTracker, cfg *ScaleConfig, localOrderId int64, exchangeOrderId string,
+	entryPrice, quantity, margin float64, leverage int, availableBalance float64) {
+	if cfg == nil || tracker == nil || localOrderId <= 0 {
+		return
+	}
+	frozen := *cfg
+	frozen.MarginCap = availableBalance * cfg.MaxTotalMarginPercent / 100
+	frozen.BaseQuantity = quantity
+	frozen.Leverage = leverage
+
+	if err := g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		if _, err := tx.Model(dao.TradingOrder.Table()).Ctx(ctx).
+			Where(dao.TradingOrder.Columns().Id, localOrderId).
+			Data(g.Map{
+				"scale_config": gjson.MustEncodeString(frozen),
+				"entry_count":  1,
+			}).Update(); err != nil {
+			return err
+		}
+		_, err := tx.Model(dao.TradingOrderEntry.Table()).Ctx(ctx).Data(g.Map{
+			dao.TradingOrderEntry.Columns().OrderId:         localOrderId,
+			dao.TradingOrderEntry.Columns().RobotId:         t.engine.Robot.Id,
+			dao.TradingOrderEntry.Columns().EntryIndex:      0,
+			dao.TradingOrderEntry.Columns().ExchangeOrderId: exchangeOrderId,
+			dao.TradingOrderEntry.Columns().Price:           entryPrice,
+			dao.TradingOrderEntry.Columns().Quantity:        quantity,
+			dao.TradingOrderEntry.Columns().Margin:          margin,
+			dao.TradingOrderEntry.Columns().AvgPriceAfter:   entryPrice,
+			dao.TradingOrderEntry.Columns().CreatedAt:       gtime.Now(),
+		}).Insert()
+		return err
+	}); err != nil {
+		g.Log().Warningf(ctx, "[Scaling] robotId=%d 冻结加仓配置失败(本单不加仓): orderId=%d err=%v", t.engine.Robot.Id, localOrderId, err)
+		return
+	}
+
+	t.engine.mu.Lock()
+	tracker.Scaling = &frozen
+	tracker.EntryCount = 1
+	tracker.LastEntryPrice = entryPrice
+	tracker.AvgEntryPrice = entryPrice
+	tracker.TotalQuantity = quantity
+	t.engine.mu.Unlock()
+
+	g.Log().Infof(ctx, "[Scaling] robotId=%d orderId=%d 已启用分批加仓: 最多加仓%d次, 步长=%.2f%%, 倍数=%.2f, 总保证金上限=%.2f USDT",
+		t.engine.Robot.Id, localOrderId, frozen.MaxEntries, frozen.StepPercent, frozen.SizeMultiplier, frozen.MarginCap)
+}
+
+// restoreScaleFromOrder 服务重启/延迟加载时从订单恢复加仓状态（调用方持有 tracker 所有权，无需加锁）
+func restoreScaleFromOrder(ctx context.Context, tracker *PositionTracker, orderId int64, scaleConfig string, entryCount int, openPrice, quantity float64) {
+	cfg := parseFrozenScaleConfig(scaleConfig)
+	if cfg == nil || cfg.MarginCap <= 0 || cfg.BaseQuantity <= 0 {
+		return
+	}
+	if entryCount <= 0 {
+		entryCount = 1
+	}
+	tracker.Scaling = cfg
+	tracker.EntryCount = entryCount
+	tracker.AvgEntryPrice = openPrice
+	tracker.TotalQuantity = quantity
+	tracker.LastEntryPrice = openPrice
+
+	// 下一次加仓以“最近一批成交价”为基准，而非均价
+	var last *entity.TradingOrderEntry
+	_ = dao.TradingOrderEntry.Ctx(ctx).
+		Where(dao.TradingOrderEntry.Columns().OrderId, orderId).
+		OrderDesc(dao.TradingOrderEntry.Columns().EntryIndex).
+		Limit(1).
+		Scan(&last)
+	if last != nil && last.Price > 0 {
+		tracker.LastEntryPrice = last.Price
+	}
+}
+
+// scheduleScaleIn 行情回调中非阻塞触发加仓检查（同一时刻只跑一个）
+func (e *RobotEngine) scheduleScaleIn(riskPrice float64) {
+	if riskPrice <= 0 || !atomic.CompareAndSwapInt32(&e.scalingInFlight, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&e.scalingInFlight, 0)
+		e.checkScaleIn(context.Background(), riskPrice)
+	}()
+}
+
+// checkScaleIn 检查各方向持仓是否达到加仓触发价
+func (e *RobotEngine) checkScaleIn(ctx context.Context, currentPrice float64) {
+	defer func() {
+		if r := recover(); r != nil {
+			g.Log().Errorf(ctx, "[Scaling] checkScaleIn panic recovered: robotId=%d, err=%v", e.Robot.Id, r)
+		}
+	}()
+
+	e.mu.RLock()
+	robot := e.Robot
+	positions := e.CurrentPositions
+	e.mu.RUnlock()
+	// 加仓属于自动开仓，受全自动下单开关控制
+	if robot == nil || robot.AutoTradeEnabled != 1 || e.Trader == nil {
+		return
+	}
+
+	for _, pos := range positions {
+		if pos == nil || math.Abs(pos.PositionAmt) <= positionAmtEpsilon {
+			continue
+		}
+		tracker := e.GetPositionTracker(pos.PositionSide)
+		if tracker == nil {
+			continue
+		}
+
+		e.mu.RLock()
+		cfg := tracker.Scaling
+		entryCount := tracker.EntryCount
+		lastEntryPrice := tracker.LastEntryPrice
+		closing := tracker.CloseInFlightUntil.After(time.Now())
+		backoff := tracker.ScaleInRetryAt.After(time.Now())
+		e.mu.RUnlock()
+		if cfg == nil || closing || backoff || lastEntryPrice <= 0 || entryCount-1 >= cfg.MaxEntries {
+			continue
+		}
+
+		trigger := cfg.TriggerPrice(pos.PositionSide, lastEntryPrice)
+		isLong := !strings.EqualFold(pos.PositionSide, "SHORT")
+		if (isLong && currentPrice > trigger) || (!isLong && currentPrice < trigger) {
+			continue
+		}
+
+		if err := e.Trader.executeScaleIn(ctx, pos, tracker, currentPrice); err != nil {
+			g.Log().Warningf(ctx, "[Scaling] robotId=%d %s 加仓未执行: %v", robot.Id, pos.PositionSide, err)
+		}
+	}
+}
+
+// executeScaleIn 执行一次加仓：下市价单 → 按实际成交重算均价/总保证金 → 回写主订单与批次明细 → 刷新 tracker
+func (t *RobotTrader) executeScaleIn(ctx context.Context, pos *exchange.Position, tracker *PositionTracker, currentPrice float64) error {
+	e := t.engine
+	robot := e.Robot
+
+	// 与开仓/平仓互斥；拿不到锁说明有订单操作进行中，等下一个 tick
+	if !e.orderLock.TryLock() {
+		return nil
+	}
+	defer e.orderLock.Unlock()
+
+	e.mu.RLock()
+	cfg := tracker.Scaling
+	entryCount := tracker.EntryCount
+	orderId := tracker.OrderId
+	totalMargin := tracker.EntryMargin
+	totalQty := tracker.TotalQuantity
+	avgPrice := tracker.AvgEntryPrice
+	e.mu.RUnlock()
+	if cfg == nil || orderId <= 0 || entryCount-1 >= cfg.MaxEntries {
+		return nil
+	}
+	// 订阅宽限期不加仓
+	if SubscriptionOpenBlocked(ctx, robot.UserId) {
+		return nil
+	}
+	if totalQty <= 0 {
+		totalQty = math.Abs(pos.PositionAmt)
+	}
+	if avgPrice <= 0 {
+		avgPrice = pos.EntryPrice
+	}
+	leverage := cfg.Leverage
+	if leverage <= 0 {
+		leverage = pos.Leverage
+	}
+	if leverage <= 0 {
+		return gerror.New("无法确定加仓杠杆")
+	}
+
+	entryIndex := entryCount
+	quantity := cfg.AddOnQuantity(entryIndex)
+	margin := quantity * currentPrice / float64(leverage)
+
+	// 总保证金硬上限：超出部分裁剪，无剩余额度则不再加仓
+	remaining := cfg.MarginCap - totalMargin
+	if remaining <= 0 {
+		return gerror.Newf("总保证金已达上限（%.2f/%.2f USDT）", totalMargin, cfg.MarginCap)
+	}
+	if margin > remaining {
+		margin = remaining
+		quantity = margin * float64(leverage) / currentPrice
+	}
+
+	balance, err := e.GetBalanceSmart(ctx, 0)
+	if err != nil || balance == nil {
+		return gerror.Newf("获取余额失败: %v", err)
+	}
+	if margin > balance.AvailableBalance {
+		return gerror.Newf("可用余额不足（需要%.2f, 可用%.2f USDT）", margin, balance.AvailableBalance)
+	}
+	var releaseMargin func()
+	if robot.BasketId > 0 {
+		allowed, release, err := GetBasketCoordinator(robot.BasketId).AllocateMargin(ctx, e, margin, balance.AvailableBalance)
+		if err != nil {
+			return err
+		}
+		releaseMargin = release
+		if allowed < margin {
+			margin = allowed
+			quantity = margin * float64(leverage) / currentPrice
+		}
+	}
+
+	side := "BUY"
+	if strings.EqualFold(pos.PositionSide, "SHORT") {
+		side = "SELL"
+	}
+	orderCtx, cancel := context.WithTimeout(ctx, 12*time.Second)
+	defer cancel()
+	order, err := e.Exchange.CreateOrder(orderCtx, &exchange.OrderRequest{
+		Symbol:       robot.Symbol,
+		Side:         side,
+		PositionSide: pos.PositionSide,
+		Type:         "MARKET",
+		Quantity:     quantity,
+	})
+	if err != nil {
+		if releaseMargin != nil {
+			releaseMargin()
+		}
+		// 失败后暂停重试，避免每个 tick 都重复请求交易所；触发基准只在成交后更新
+		e.mu.Lock()
+		tracker.ScaleInRetryAt = time.Now().Add(scaleInRetryDelay)
+		e.mu.Unlock()
+		t.saveExecutionLog(ctx, 0, orderId, "scale_in_failed", "failed", fmt.Sprintf("第%d次加仓下单失败: %s", entryIndex, formatExchangeAPIError(err.Error())), map[string]interface{}{
+			"entry_index": entryIndex,
+			"quantity":    quantity,
+			"margin":      margin,
+			"price":       currentPrice,
+			"error":       err.Error(),
+		})
+		return gerror.Wrap(err, "加仓下单失败")
+	}
+
+	fillPrice := currentPrice
+	if order.AvgPrice > 0 {
+		fillPrice = order.AvgPrice
+	}
+	if order.FilledQty > 0 {
+		quantity = order.FilledQty
+	} else if order.Quantity > 0 {
+		quantity = order.Quantity
+	}
+	margin = quantity * fillPrice / float64(leverage)
+
+	newQty := totalQty + quantity
+	newAvg := (avgPrice*totalQty + fillPrice*quantity) / newQty
+	newMargin := totalMargin + margin
+	newCount := entryCount + 1
+
+	if err := g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		if _, err := tx.Model(dao.TradingOrderEntry.Table()).Ctx(ctx).Data(g.Map{
+			dao.TradingOrderEntry.Columns().OrderId:         orderId,
+			dao.TradingOrderEntry.Columns().RobotId:         robot.Id,
+			dao.TradingOrderEntry.Columns().EntryIndex:      entryIndex,
+			dao.TradingOrderEntry.Columns().ExchangeOrderId: order.OrderId,
+			dao.TradingOrderEntry.Columns().Price:           fillPrice,
+			dao.TradingOrderEntry.Columns().Quantity:        quantity,
+			dao.TradingOrderEntry.Columns().Margin:          margin,
+			dao.TradingOrderEntry.Columns().AvgPriceAfter:   newAvg,
+			dao.TradingOrderEntry.Columns().CreatedAt:       gtime.Now(),
+		}).Insert(); err != nil {
+			return err
+		}
+		// 主订单保存加权汇总：均价/总数量/总保证金；最高盈利随仓位变化重新计量
+		_, err := tx.Model(dao.TradingOrder.Table()).Ctx(ctx).
+			Where(dao.TradingOrder.Columns().Id, orderId).
+			Data(g.Map{
+				"open_price":     newAvg,
+				"avg_price":      newAvg,
+				"quantity":       newQty,
+				"filled_qty":     newQty,
+				"margin":         newMargin,
+				"open_margin":    newMargin,
+				"entry_count":    newCount,
+				"highest_profit": 0,
+				"updated_at":     gtime.Now(),
+			}).Update()
+		return err
+	}); err != nil {
+		// 交易所已成交，仅本地落库失败：内存仍按实际仓位更新，下次 syncPositionFromDB/对账时修正
+		g.Log().Errorf(ctx, "[Scaling] robotId=%d orderId=%d 加仓已成交但落库失败: exchangeOrderId=%s err=%v", robot.Id, orderId, order.OrderId, err)
+	}
+
+	e.mu.Lock()
+	tracker.EntryCount = newCount
+	tracker.LastEntryPrice = fillPrice
+	tracker.ScaleInRetryAt = time.Time{}
+	tracker.AvgEntryPrice = newAvg
+	tracker.TotalQuantity = newQty
+	tracker.EntryMargin = newMargin
+	tracker.HighestProfit = 0
+	tracker.LowestProfit = 0
+	for _, p := range e.CurrentPositions {
+		if p != nil && strings.EqualFold(p.PositionSide, pos.PositionSide) {
+			p.EntryPrice = newAvg
+			p.PositionAmt = newQty
+			if side == "SELL" {
+				p.PositionAmt = -newQty
+			}
+			p.IsolatedMargin = newMargin
+			break
+		}
+	}
+	e.mu.Unlock()
+
+	t.saveExecutionLog(ctx, 0, orderId, "scale_in", "success",
+		fmt.Sprintf("第%d次加仓成功: %s方向, 数量%.6f, 成交价%.4f, 均价%.4f, 总保证金%.2f USDT", entryIndex, pos.PositionSide, quantity, fillPrice, newAvg, newMargin),
+		map[string]interface{}{
+			"entry_index":       entryIndex,
+			"exchange_order_id": order.OrderId,
+			"quantity":          quantity,
+			"price":             fillPrice,
+			"avg_price":         newAvg,
+			"total_quantity":    newQty,
+			"total_margin":      newMargin,
+			"margin_cap":        cfg.MarginCap,
+		})
+	g.Log().Infof(ctx, "[Scaling] robotId=%d orderId=%d 第%d/%d次加仓: qty=%.6f price=%.4f → 均价=%.4f 总保证金=%.2f/%.2f",
+		robot.Id, orderId, entryIndex, cfg.MaxEntries, quantity, fillPrice, newAvg, newMargin, cfg.MarginCap)
+	return nil
+}
+
+// appendScaleEntryLinks 加仓单的交易所订单ID不在主订单上，成交落库时按批次明细映射回主订单
+func appendScaleEntryLinks(ctx context.Context, orderIDs []string, orderLinks map[string][]*tradeFillOrderLink) error {
+	var entries []*entity.TradingOrderEntry
+	if err := dao.TradingOrderEntry.Ctx(ctx).
+		WhereIn(dao.TradingOrderEntry.Columns().ExchangeOrderId, orderIDs).
+		WhereGT(dao.TradingOrderEntry.Columns().EntryIndex, 0).
+		Scan(&entries); err != nil || len(entries) == 0 {
+		return err
+	}
+	parentIds := make([]int64, 0, len(entries))
+	for _, en := range entries {
+		parentIds = append(parentIds, en.OrderId)
+	}
+	type row struct {
+		Id        int64       `orm:"id"`
+		UserId    int64       `orm:"user_id"`
+		RobotId   int64       `orm:"robot_id"`
+		OrderSn   string      `orm:"order_sn"`
+		Exchange  string      `orm:"exchange"`
+		Symbol    string      `orm:"symbol"`
+		Direction string      `orm:"direction"`
+		OpenTime  *gtime.Time `orm:"open_time"`
+	}
+	var rows []*row
+	if err := dao.TradingOrder.Ctx(ctx).
+		Fields("id", "user_id", "robot_id", "order_sn", "exchange", "symbol", "direction", "open_time").
+		WhereIn("id", parentIds).
+		Scan(&rows); err != nil {
+		return err
+	}
+	parents := make(map[int64]*row, len(rows))
+	for _, r := range rows {
+		parents[r.Id] = r
+	}
+	for _, en := range entries {
+		p := parents[en.OrderId]
+		oid := strings.TrimSpace(en.ExchangeOrderId)
+		if p == nil || oid == "" {
+			continue
+		}
+		orderLinks[oid] = append(orderLinks[oid], &tradeFillOrderLink{
+			OrderId:         p.Id,
+			UserId:          p.UserId,
+			RobotId:         p.RobotId,
+			OrderSn:         p.OrderSn,
+			Exchange:        p.Exchange,
+			Symbol:          p.Symbol,
+			Direction:       p.Direction,
+			ExchangeOrderId: oid,
+			OpenTime:        en.CreatedAt,
+		})
+	}
+	return nil
+}
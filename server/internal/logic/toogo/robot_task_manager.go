@@ -4,9 +4,7 @@ package toogo
 
 import (
 	"context"
-	"fmt"
 	"math"
-	"net"
 	"strings"
 	"sync"
 	"time"
@@ -16,12 +14,10 @@ import (
 	"hotgo/internal/library/exchange"
 	"hotgo/internal/library/market"
 	"hotgo/internal/model/entity"
-	"hotgo/utility/encrypt"
 
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gtime"
-	"golang.org/x/net/proxy"
 )
 
 // RobotTaskManager 鏈哄櫒浜轰换鍔＄鐞嗗櫒
@@ -77,13 +73,8 @@ func (m *RobotTaskManager) Start(ctx context.Context) error {
 		return err
 	}
 
-	// 璁剧疆WebSocket浠ｇ悊閰嶇疆锛堢洿鎺ヨ幏鍙栵紝閬垮厤寰幆渚濊禆锛?
-	wsDialer, err := getWebSocketDialer(ctx)
-	if err != nil {
-		g.Log().Warningf(ctx, "[RobotTaskManager] 鑾峰彇WebSocket浠ｇ悊閰嶇疆澶辫触: %v", err)
-	} else if wsDialer != nil {
-		market.GetMarketServiceManager().SetProxyDialer(wsDialer)
-	}
+	// 公共行情WS经代理池（全局代理）建立连接，代理故障时自动切换
+	market.GetMarketServiceManager().SetProxyDialer(getWebSocketDialer(ctx, publicProxyBinding))
 
 	// 鍚姩鍏ㄥ眬琛屾儏鏈嶅姟绠＄悊鍣?
 	if err := market.GetMarketServiceManager().Start(ctx); err != nil {
@@ -513,6 +504,12 @@ func (m *RobotTaskManager) initRobotEngine(ctx context.Context, robot *entity.Tr
 
 	// 鍒涘缓鏈哄櫒浜哄紩鎿?
 	engine := NewRobotEngine(ctx, robot, apiConfig, ex)
+	// 网格模式：配置无效时不启动引擎（避免退化为方向信号模式下单）
+	if robot.RobotMode == RobotModeGrid {
+		if engine.Grid, err = NewGridTrader(engine); err != nil {
+			return nil, gerror.Wrapf(err, "网格配置无效: robotId=%d", robot.Id)
+		}
+	}
 	return engine, nil
 }
 
@@ -535,6 +532,18 @@ func (m *RobotTaskManager) GetAllEngines() []*RobotEngine {
 	return result
 }
 
+// GetEngineRobotIds 获取本节点运行中引擎的机器人ID
+func (m *RobotTaskManager) GetEngineRobotIds() []int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]int64, 0, len(m.engines))
+	for robotId := range m.engines {
+		result = append(result, robotId)
+	}
+	return result
+}
+
 // GetActiveCount 鑾峰彇娲昏穬鏈哄櫒浜烘暟閲?
 func (m *RobotTaskManager) GetActiveCount() int {
 	m.mu.RLock()
@@ -819,6 +828,37 @@ func (m *RobotTaskManager) StopRobot(ctx context.Context, robotId int64, reason
 	return nil
 }
 
+// RestartEngine 重建运行中机器人的本地引擎（不改变机器人状态与运行区间），用于引擎卡死/连接异常时人工恢复
+func (m *RobotTaskManager) RestartEngine(ctx context.Context, robotId int64) error {
+	count, err := dao.TradingRobot.Ctx(ctx).
+		Where(dao.TradingRobot.Columns().Id, robotId).
+		Where(dao.TradingRobot.Columns().Status, 2).
+		Count()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return gerror.New("机器人未在运行中")
+	}
+
+	m.mu.Lock()
+	engine, ok := m.engines[robotId]
+	if ok {
+		delete(m.engines, robotId)
+	}
+	m.mu.Unlock()
+	if ok {
+		engine.Stop()
+	}
+
+	// 由同步流程按数据库状态重新初始化并启动引擎
+	m.syncRobots(ctx)
+	if m.GetEngine(robotId) == nil {
+		return gerror.New("引擎重建失败，请查看服务日志")
+	}
+	return nil
+}
+
 // handleMaxRuntimeRobots 处理 max_runtime 到期的机器人：自动暂停 + 全平（不依赖客户端）
 // 说明：
 // - max_runtime 单位为秒
@@ -989,7 +1029,8 @@ type GlobalEngineStatus struct {
 
 // GlobalServicesStatus 鍏ㄥ眬鏈嶅姟鐘舵€?
 type GlobalServicesStatus struct {
-	MarketService *MarketServiceStatus `json:"marketService"`
+	MarketService *MarketServiceStatus       `json:"marketService"`
+	RateLimit     []*exchange.GovernorStatus `json:"rateLimit"` // 各 平台/API Key/出口IP 的请求额度与封禁退避
 }
 
 // MarketServiceStatus 琛屾儏鏈嶅姟鐘舵€?
@@ -1034,6 +1075,7 @@ func (m *RobotTaskManager) getServicesStatus() *GlobalServicesStatus {
 			Running:   market.GetMarketServiceManager().IsRunning(),
 			Exchanges: make(map[string]*ExchangeServiceStatus),
 		},
+		RateLimit: exchange.GovernorStatuses(),
 	}
 
 	// 鑾峰彇鍚勪氦鏄撴墍鏈嶅姟鐘舵€?
@@ -1115,139 +1157,3 @@ func (m *RobotTaskManager) engineToManagedRobot(engine *RobotEngine) *ManagedRob
 
 	return managed
 }
-
-// getWebSocketDialer 鑾峰彇WebSocket浠ｇ悊鎷ㄥ彿鍣紙閬垮厤寰幆渚濊禆锛岀洿鎺ュ湪鏈寘瀹炵幇锛?
-func getWebSocketDialer(ctx context.Context) (func(network, addr string) (net.Conn, error), error) {
-	// 鑾峰彇鍏ㄥ眬浠ｇ悊閰嶇疆
-	var proxyConfig *entity.TradingProxyConfig
-	err := dao.TradingProxyConfig.Ctx(ctx).
-		Where(dao.TradingProxyConfig.Columns().UserId, 0).   // user_id=0 琛ㄧず鍏ㄥ眬閰嶇疆
-		Where(dao.TradingProxyConfig.Columns().TenantId, 0). // tenant_id=0 琛ㄧず鍏ㄥ眬閰嶇疆
-		Where(dao.TradingProxyConfig.Columns().Enabled, 1).
-		Scan(&proxyConfig)
-
-	if err != nil {
-		return nil, err
-	}
-
-	// 濡傛灉娌℃湁鍚敤浠ｇ悊锛岃繑鍥瀗il锛堜娇鐢ㄩ粯璁ゆ嫧鍙峰櫒锛?
-	if proxyConfig == nil {
-		return nil, nil
-	}
-
-	// 瑙ｅ瘑瀵嗙爜
-	password := ""
-	if proxyConfig.AuthEnabled == 1 && proxyConfig.Password != "" {
-		password, err = encrypt.AesDecrypt(proxyConfig.Password)
-		if err != nil {
-			return nil, gerror.Wrap(err, "瀵嗙爜瑙ｅ瘑澶辫触")
-		}
-	}
-
-	// 鏍规嵁浠ｇ悊绫诲瀷鍒涘缓鎷ㄥ彿鍣?
-	if proxyConfig.ProxyType == "http" || proxyConfig.ProxyType == "https" {
-		// HTTP浠ｇ悊锛氫娇鐢–ONNECT鏂规硶寤虹珛闅ч亾
-		return func(network, addr string) (net.Conn, error) {
-			// 杩炴帴鍒颁唬鐞嗘湇鍔″櫒
-			proxyConn, err := net.DialTimeout("tcp", proxyConfig.ProxyAddress, 30*time.Second)
-			if err != nil {
-				return nil, fmt.Errorf("杩炴帴HTTP浠ｇ悊澶辫触: %v", err)
-			}
-
-			// 鏋勫缓CONNECT璇锋眰
-			connectReq := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\n", addr, addr)
-
-			// 濡傛灉闇€瑕佽璇?
-			if proxyConfig.AuthEnabled == 1 && proxyConfig.Username != "" {
-				auth := proxyConfig.Username
-				if password != "" {
-					auth += ":" + password
-				}
-				encoded := base64EncodeSimple([]byte(auth))
-				connectReq += fmt.Sprintf("Proxy-Authorization: Basic %s\r\n", encoded)
-			}
-
-			connectReq += "\r\n"
-
-			// 鍙戦€丆ONNECT璇锋眰
-			_, err = proxyConn.Write([]byte(connectReq))
-			if err != nil {
-				proxyConn.Close()
-				return nil, fmt.Errorf("鍙戦€丆ONNECT璇锋眰澶辫触: %v", err)
-			}
-
-			// 璇诲彇鍝嶅簲
-			response := make([]byte, 1024)
-			n, err := proxyConn.Read(response)
-			if err != nil {
-				proxyConn.Close()
-				return nil, fmt.Errorf("璇诲彇浠ｇ悊鍝嶅簲澶辫触: %v", err)
-			}
-
-			// 妫€鏌ュ搷搴旂姸鎬?
-			respStr := string(response[:n])
-			if !strings.Contains(respStr, "200") {
-				proxyConn.Close()
-				return nil, fmt.Errorf("HTTP浠ｇ悊CONNECT澶辫触: %s", respStr)
-			}
-
-			return proxyConn, nil
-		}, nil
-	} else if proxyConfig.ProxyType == "socks5" {
-		// SOCKS5浠ｇ悊
-		var auth *proxy.Auth
-		if proxyConfig.AuthEnabled == 1 && proxyConfig.Username != "" {
-			auth = &proxy.Auth{
-				User:     proxyConfig.Username,
-				Password: password,
-			}
-		}
-
-		dialer, err := proxy.SOCKS5("tcp", proxyConfig.ProxyAddress, auth, proxy.Direct)
-		if err != nil {
-			return nil, err
-		}
-
-		return dialer.Dial, nil
-	}
-
-	return nil, gerror.Newf("涓嶆敮鎸佺殑浠ｇ悊绫诲瀷: %s", proxyConfig.ProxyType)
-}
-
-// base64EncodeSimple 绠€鍗曠殑base64缂栫爜
-func base64EncodeSimple(data []byte) string {
-	const base64Chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
-	var result strings.Builder
-
-	for i := 0; i < len(data); i += 3 {
-		var n uint32
-		var padding int
-
-		n = uint32(data[i]) << 16
-		if i+1 < len(data) {
-			n |= uint32(data[i+1]) << 8
-		} else {
-			padding++
-		}
-		if i+2 < len(data) {
-			n |= uint32(data[i+2])
-		} else {
-			padding++
-		}
-
-		result.WriteByte(base64Chars[(n>>18)&0x3F])
-		result.WriteByte(base64Chars[(n>>12)&0x3F])
-		if padding < 2 {
-			result.WriteByte(base64Chars[(n>>6)&0x3F])
-		} else {
-			result.WriteByte('=')
-		}
-		if padding < 1 {
-			result.WriteByte(base64Chars[n&0x3F])
-		} else {
-			result.WriteByte('=')
-		}
-	}
-
-	return result.String()
-}
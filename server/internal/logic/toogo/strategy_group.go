@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"hotgo/internal/consts"
+	"hotgo/internal/dao"
 	"hotgo/internal/library/contexts"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/toogoin"
@@ -48,9 +49,14 @@ func NewStrategyGroupService() *StrategyGroupService {
 	return &StrategyGroupService{}
 }
 
+// canManageOfficial 是否可维护官方策略：超级管理员，或白标租户管理员（只作用于本租户）
+func canManageOfficial(ctx context.Context) bool {
+	return contexts.GetRoleKey(ctx) == consts.SuperRoleKey || contexts.IsTenantDept(ctx)
+}
+
 // List 获取策略模板列表
 func (s *StrategyGroupService) List(ctx context.Context, in *toogoin.StrategyGroupListInp) (*toogoin.StrategyGroupListModel, error) {
-	m := g.DB().Model("hg_trading_strategy_group").Safe()
+	m := dao.TradingStrategyGroup.Ctx(ctx).Safe()
 
 	if in.Exchange != "" {
 		m = m.Where("exchange", in.Exchange)
@@ -70,8 +76,7 @@ func (s *StrategyGroupService) List(ctx context.Context, in *toogoin.StrategyGro
 	// - 官方策略（is_official=1）为公用资源，不限制 user_id
 	// - 我的策略（is_official=0）为用户私有资源，必须限制 user_id=当前登录用户（超级管理员不限制）
 	userId := contexts.GetUserId(ctx)
-	roleKey := contexts.GetRoleKey(ctx)
-	isSuper := roleKey == consts.SuperRoleKey
+	isSuper := canManageOfficial(ctx)
 	if in.IsOfficial != nil {
 		m = m.Where("is_official", *in.IsOfficial)
 		if (in.NonPersonal == nil || *in.NonPersonal != 1) && !isSuper && *in.IsOfficial == 0 {
@@ -99,7 +104,7 @@ func (s *StrategyGroupService) List(ctx context.Context, in *toogoin.StrategyGro
 	// 获取每个模板下的策略数量
 	var list []*toogoin.StrategyGroupItem
 	for _, group := range groups {
-		count, _ := g.DB().Model("hg_trading_strategy_template").Where("group_id", group.Id).Count()
+		count, _ := dao.TradingStrategyTemplate.Ctx(ctx).Where("group_id", group.Id).Count()
 		list = append(list, &toogoin.StrategyGroupItem{
 			TradingStrategyGroup: *group,
 			StrategyCount:        count,
@@ -116,7 +121,8 @@ func (s *StrategyGroupService) List(ctx context.Context, in *toogoin.StrategyGro
 // Create 创建策略模板
 func (s *StrategyGroupService) Create(ctx context.Context, in *toogoin.StrategyGroupCreateInp) error {
 	// 检查key是否重复
-	count, err := g.DB().Model("hg_trading_strategy_group").Where("group_key", in.GroupKey).Count()
+	tenantId := contexts.GetTenantId(ctx)
+	count, err := dao.TradingStrategyGroup.Ctx(ctx).Where("tenant_id", tenantId).Where("group_key", in.GroupKey).Count()
 	if err != nil {
 		return err
 	}
@@ -125,6 +131,7 @@ func (s *StrategyGroupService) Create(ctx context.Context, in *toogoin.StrategyG
 	}
 
 	data := g.Map{
+		"tenant_id":   tenantId,
 		"group_name":  in.GroupName,
 		"group_key":   in.GroupKey,
 		"exchange":    in.Exchange,
@@ -140,8 +147,8 @@ func (s *StrategyGroupService) Create(ctx context.Context, in *toogoin.StrategyG
 		"updated_at":  gtime.Now(),
 	}
 
-	// admin overrides (super only)
-	if contexts.GetRoleKey(ctx) == consts.SuperRoleKey {
+	// admin overrides (super / tenant admin only)
+	if canManageOfficial(ctx) {
 		if in.IsOfficial != nil {
 			data["is_official"] = *in.IsOfficial
 			if *in.IsOfficial == 1 {
@@ -165,7 +172,7 @@ func (s *StrategyGroupService) Create(ctx context.Context, in *toogoin.StrategyG
 		data["margin_mode"] = "isolated"
 	}
 
-	_, err = g.DB().Model("hg_trading_strategy_group").Insert(data)
+	_, err = dao.TradingStrategyGroup.Ctx(ctx).Insert(data)
 	return err
 }
 
@@ -173,7 +180,7 @@ func (s *StrategyGroupService) Create(ctx context.Context, in *toogoin.StrategyG
 func (s *StrategyGroupService) Update(ctx context.Context, in *toogoin.StrategyGroupUpdateInp) error {
 	// 检查模板是否存在
 	var group entity.TradingStrategyGroup
-	err := g.DB().Model("hg_trading_strategy_group").Where("id", in.Id).Scan(&group)
+	err := dao.TradingStrategyGroup.Ctx(ctx).Where("id", in.Id).Scan(&group)
 	if err != nil {
 		return err
 	}
@@ -184,7 +191,7 @@ func (s *StrategyGroupService) Update(ctx context.Context, in *toogoin.StrategyG
 
 	// 检查是否有机器人绑定（如果未确认，则返回绑定信息）
 	if !in.Confirmed {
-		robotCount, err := g.DB().Model("hg_trading_robot").
+		robotCount, err := dao.TradingRobot.Ctx(ctx).
 			Where("strategy_group_id", in.Id).
 			WhereNull("deleted_at").
 			Count()
@@ -194,7 +201,7 @@ func (s *StrategyGroupService) Update(ctx context.Context, in *toogoin.StrategyG
 		if robotCount > 0 {
 			// 获取绑定的机器人列表（最多10个，用于提示）
 			var robots []map[string]interface{}
-			g.DB().Model("hg_trading_robot").
+			dao.TradingRobot.Ctx(ctx).
 				Fields("id", "robot_name", "status").
 				Where("strategy_group_id", in.Id).
 				WhereNull("deleted_at").
@@ -245,7 +252,7 @@ func (s *StrategyGroupService) Update(ctx context.Context, in *toogoin.StrategyG
 
 	// admin-only: allow toggling is_official / is_active
 	if in.IsOfficial != nil || in.IsActive != nil {
-		if contexts.GetRoleKey(ctx) != consts.SuperRoleKey {
+		if !canManageOfficial(ctx) {
 			return gerror.New("permission denied")
 		}
 		if in.IsOfficial != nil {
@@ -259,7 +266,7 @@ func (s *StrategyGroupService) Update(ctx context.Context, in *toogoin.StrategyG
 		}
 	}
 
-	_, err = g.DB().Model("hg_trading_strategy_group").Where("id", in.Id).Update(data)
+	_, err = dao.TradingStrategyGroup.Ctx(ctx).Where("id", in.Id).Update(data)
 	if err != nil {
 		return err
 	}
@@ -274,7 +281,7 @@ func (s *StrategyGroupService) Update(ctx context.Context, in *toogoin.StrategyG
 func (s *StrategyGroupService) Delete(ctx context.Context, in *toogoin.StrategyGroupDeleteInp) error {
 	// 检查模板是否存在
 	var group entity.TradingStrategyGroup
-	err := g.DB().Model("hg_trading_strategy_group").Where("id", in.Id).Scan(&group)
+	err := dao.TradingStrategyGroup.Ctx(ctx).Where("id", in.Id).Scan(&group)
 	if err != nil {
 		return err
 	}
@@ -284,7 +291,7 @@ func (s *StrategyGroupService) Delete(ctx context.Context, in *toogoin.StrategyG
 	// 【管理员权限】允许删除官方策略组（在官方策略组管理页面中）
 
 	// 检查是否有机器人绑定
-	robotCount, err := g.DB().Model("hg_trading_robot").
+	robotCount, err := dao.TradingRobot.Ctx(ctx).
 		Where("strategy_group_id", in.Id).
 		WhereNull("deleted_at").
 		Count()
@@ -294,7 +301,7 @@ func (s *StrategyGroupService) Delete(ctx context.Context, in *toogoin.StrategyG
 	if robotCount > 0 {
 		// 获取绑定的机器人列表（最多5个，用于提示）
 		var robots []map[string]interface{}
-		g.DB().Model("hg_trading_robot").
+		dao.TradingRobot.Ctx(ctx).
 			Fields("id", "robot_name", "status").
 			Where("strategy_group_id", in.Id).
 			WhereNull("deleted_at").
@@ -321,12 +328,12 @@ func (s *StrategyGroupService) Delete(ctx context.Context, in *toogoin.StrategyG
 	}
 
 	// 删除关联的策略
-	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
-		_, err := tx.Model("hg_trading_strategy_template").Where("group_id", in.Id).Delete()
+	err = dao.TradingStrategyGroup.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		_, err := dao.TradingStrategyTemplate.Ctx(ctx).Where("group_id", in.Id).Delete()
 		if err != nil {
 			return err
 		}
-		_, err = tx.Model("hg_trading_strategy_group").Where("id", in.Id).Delete()
+		_, err = dao.TradingStrategyGroup.Ctx(ctx).Where("id", in.Id).Delete()
 		return err
 	})
 	return err
@@ -336,7 +343,7 @@ func (s *StrategyGroupService) Delete(ctx context.Context, in *toogoin.StrategyG
 func (s *StrategyGroupService) Init(ctx context.Context, in *toogoin.StrategyGroupInitInp) error {
 	// 获取模板信息
 	var group entity.TradingStrategyGroup
-	err := g.DB().Model("hg_trading_strategy_group").Where("id", in.GroupId).Scan(&group)
+	err := dao.TradingStrategyGroup.Ctx(ctx).Where("id", in.GroupId).Scan(&group)
 	if err != nil {
 		return err
 	}
@@ -402,7 +409,7 @@ func (s *StrategyGroupService) Init(ctx context.Context, in *toogoin.StrategyGro
 			strategyKey := fmt.Sprintf("%d_%s_%s", group.Id, market.Key, risk.Key)
 
 			// 检查是否已存在
-			count, _ := g.DB().Model("hg_trading_strategy_template").Where("strategy_key", strategyKey).Count()
+			count, _ := dao.TradingStrategyTemplate.Ctx(ctx).Where("strategy_key", strategyKey).Count()
 			if count > 0 {
 				continue
 			}
@@ -440,7 +447,7 @@ func (s *StrategyGroupService) Init(ctx context.Context, in *toogoin.StrategyGro
 				"updated_at":                 gtime.Now(),
 			}
 
-			_, err := g.DB().Model("hg_trading_strategy_template").Insert(data)
+			_, err := dao.TradingStrategyTemplate.Ctx(ctx).Insert(data)
 			if err != nil {
 				g.Log().Error(ctx, "初始化策略失败", err, data)
 			}
@@ -457,7 +464,7 @@ func (s *StrategyGroupService) CopyFromOfficial(ctx context.Context, officialGro
 	userId := contexts.GetUserId(ctx)
 	// 获取官方模板
 	var officialGroup entity.TradingStrategyGroup
-	err := g.DB().Model("hg_trading_strategy_group").Ctx(ctx).Where("id", officialGroupId).Scan(&officialGroup)
+	err := dao.TradingStrategyGroup.Ctx(ctx).Where("id", officialGroupId).Scan(&officialGroup)
 	if err != nil {
 		return 0, gerror.Wrap(err, "查询官方模板失败")
 	}
@@ -469,7 +476,7 @@ func (s *StrategyGroupService) CopyFromOfficial(ctx context.Context, officialGro
 	}
 
 	// 检查是否已经存在从该官方模板复制的版本
-	count, err := g.DB().Model("hg_trading_strategy_group").Ctx(ctx).
+	count, err := dao.TradingStrategyGroup.Ctx(ctx).
 		Where("from_official_id", officialGroupId).
 		Where("is_official", 0).
 		Where("user_id", userId).
@@ -481,7 +488,7 @@ func (s *StrategyGroupService) CopyFromOfficial(ctx context.Context, officialGro
 	if count > 0 {
 		// 已存在，查询并返回已存在的策略组ID
 		var existingGroup entity.TradingStrategyGroup
-		err = g.DB().Model("hg_trading_strategy_group").Ctx(ctx).
+		err = dao.TradingStrategyGroup.Ctx(ctx).
 			Where("from_official_id", officialGroupId).
 			Where("is_official", 0).
 			Where("user_id", userId).
@@ -508,7 +515,7 @@ func (s *StrategyGroupService) CopyFromOfficial(ctx context.Context, officialGro
 	newGroupKey := ""
 	for i := 0; i < 5; i++ {
 		candidate := fmt.Sprintf("copy_%d_%d", officialGroupId, gtime.Now().UnixNano())
-		c, e := g.DB().Model("hg_trading_strategy_group").Ctx(ctx).Where("group_key", candidate).Count()
+		c, e := dao.TradingStrategyGroup.Ctx(ctx).Where("group_key", candidate).Count()
 		if e != nil {
 			return 0, gerror.Wrap(e, "生成策略组标识失败")
 		}
@@ -521,6 +528,7 @@ func (s *StrategyGroupService) CopyFromOfficial(ctx context.Context, officialGro
 		return 0, gerror.New("生成策略组标识失败")
 	}
 	newGroupData := g.Map{
+		"tenant_id":        contexts.GetTenantId(ctx),
 		"group_name":       officialGroup.GroupName + " (我的副本)",
 		"group_key":        newGroupKey,
 		"exchange":         officialGroup.Exchange,
@@ -538,12 +546,12 @@ func (s *StrategyGroupService) CopyFromOfficial(ctx context.Context, officialGro
 	}
 
 	var newGroupId int64
-	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+	err = dao.TradingStrategyGroup.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
 		// 【关键修复】不使用 InsertAndGetId/LastInsertId，避免 PostgreSQL 驱动不支持导致“第一次点击报错”
-		if _, e := tx.Model("hg_trading_strategy_group").Data(newGroupData).Insert(); e != nil {
+		if _, e := dao.TradingStrategyGroup.Ctx(ctx).Data(newGroupData).Insert(); e != nil {
 			return e
 		}
-		v, e := tx.Model("hg_trading_strategy_group").Where("group_key", newGroupKey).Value("id")
+		v, e := dao.TradingStrategyGroup.Ctx(ctx).Where("tenant_id", newGroupData["tenant_id"]).Where("group_key", newGroupKey).Value("id")
 		if e != nil {
 			return e
 		}
@@ -554,7 +562,7 @@ func (s *StrategyGroupService) CopyFromOfficial(ctx context.Context, officialGro
 
 		// 复制策略（同事务，避免只插入组但没插入模板）
 		var strategies []*entity.TradingStrategyTemplate
-		if e := tx.Model("hg_trading_strategy_template").Where("group_id", officialGroupId).Scan(&strategies); e != nil {
+		if e := dao.TradingStrategyTemplate.Ctx(ctx).Where("group_id", officialGroupId).Scan(&strategies); e != nil {
 			return gerror.Wrap(e, "查询官方策略列表失败")
 		}
 		if len(strategies) == 0 {
@@ -587,7 +595,7 @@ func (s *StrategyGroupService) CopyFromOfficial(ctx context.Context, officialGro
 				"created_at":                 now,
 				"updated_at":                 now,
 			}
-			if _, e := tx.Model("hg_trading_strategy_template").Data(newStrategyData).Insert(); e != nil {
+			if _, e := dao.TradingStrategyTemplate.Ctx(ctx).Data(newStrategyData).Insert(); e != nil {
 				return e
 			}
 		}
@@ -609,11 +617,11 @@ func (s *StrategyGroupService) CopyFromOfficial(ctx context.Context, officialGro
 }
 
 // Clone 复制策略组（含策略模板）
-// - 仅超级管理员可用
+// - 仅超级管理员/租户管理员可用
 // - 会复制策略组记录，并复制其下所有策略模板
 // - 自动生成新的 group_key，避免唯一键冲突
 func (s *StrategyGroupService) Clone(ctx context.Context, groupId int64) (int64, error) {
-	if contexts.GetRoleKey(ctx) != consts.SuperRoleKey {
+	if !canManageOfficial(ctx) {
 		return 0, gerror.New("permission denied")
 	}
 	if groupId <= 0 {
@@ -622,7 +630,7 @@ func (s *StrategyGroupService) Clone(ctx context.Context, groupId int64) (int64,
 
 	// 读取源策略组
 	var src entity.TradingStrategyGroup
-	if err := g.DB().Model("hg_trading_strategy_group").Ctx(ctx).
+	if err := dao.TradingStrategyGroup.Ctx(ctx).
 		Where("id", groupId).
 		Scan(&src); err != nil {
 		return 0, err
@@ -635,7 +643,7 @@ func (s *StrategyGroupService) Clone(ctx context.Context, groupId int64) (int64,
 	newKey := ""
 	for i := 0; i < 5; i++ {
 		newKey = fmt.Sprintf("%s_copy_%d", src.GroupKey, gtime.Now().UnixNano())
-		cnt, err := g.DB().Model("hg_trading_strategy_group").Ctx(ctx).Where("group_key", newKey).Count()
+		cnt, err := dao.TradingStrategyGroup.Ctx(ctx).Where("tenant_id", src.TenantId).Where("group_key", newKey).Count()
 		if err != nil {
 			return 0, err
 		}
@@ -647,33 +655,63 @@ func (s *StrategyGroupService) Clone(ctx context.Context, groupId int64) (int64,
 		return 0, gerror.New("生成模板标识失败")
 	}
 
+	return s.cloneGroup(ctx, &src, src.TenantId, g.Map{
+		"group_name":  fmt.Sprintf("%s (复制)", src.GroupName),
+		"group_key":   newKey,
+		"is_official": src.IsOfficial,
+		"user_id":     src.UserId,
+	})
+}
+
+// CloneToTenant 将平台官方策略组（含策略模板）复制到白标租户，保留 group_key
+// 租户下已存在相同 group_key 时跳过，返回 0
+func (s *StrategyGroupService) CloneToTenant(ctx context.Context, src *entity.TradingStrategyGroup, tenantId int64) (int64, error) {
+	cnt, err := dao.TradingStrategyGroup.Ctx(ctx).Where("tenant_id", tenantId).Where("group_key", src.GroupKey).Count()
+	if err != nil {
+		return 0, err
+	}
+	if cnt > 0 {
+		return 0, nil
+	}
+	return s.cloneGroup(ctx, src, tenantId, g.Map{
+		"group_name":  src.GroupName,
+		"group_key":   src.GroupKey,
+		"is_official": 1,
+		"user_id":     int64(0),
+	})
+}
+
+// cloneGroup 复制策略组到指定租户，fields 覆盖策略组名称/标识/归属等字段
+func (s *StrategyGroupService) cloneGroup(ctx context.Context, src *entity.TradingStrategyGroup, tenantId int64, fields g.Map) (int64, error) {
+	newKey := fields["group_key"]
+
 	var newGroupId int64
-	err := g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+	err := dao.TradingStrategyGroup.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
 		// 复制策略组
 		data := g.Map{
-			"group_name":  fmt.Sprintf("%s (复制)", src.GroupName),
-			"group_key":   newKey,
+			"tenant_id":   tenantId,
 			"exchange":    src.Exchange,
 			"symbol":      src.Symbol,
 			"order_type":  src.OrderType,
 			"margin_mode": src.MarginMode,
 			"description": src.Description,
-			"is_official": src.IsOfficial,
-			"user_id":     src.UserId,
 			"is_active":   src.IsActive,
 			"sort":        src.Sort,
 			"created_at":  gtime.Now(),
 			"updated_at":  gtime.Now(),
 		}
+		for k, v := range fields {
+			data[k] = v
+		}
 
 		// PostgreSQL 兼容：InsertAndGetId 在某些驱动实现下仍可能触发 LastInsertId 错误
 		// 兜底策略：InsertAndGetId 失败且包含 LastInsertId → 普通 Insert + 通过唯一 group_key 反查 id
-		id, err := tx.Model("hg_trading_strategy_group").Data(data).InsertAndGetId()
+		id, err := dao.TradingStrategyGroup.Ctx(ctx).Data(data).InsertAndGetId()
 		if err != nil && strings.Contains(err.Error(), "LastInsertId is not supported") {
-			if _, e := tx.Model("hg_trading_strategy_group").Data(data).Insert(); e != nil {
+			if _, e := dao.TradingStrategyGroup.Ctx(ctx).Data(data).Insert(); e != nil {
 				return e
 			}
-			v, e := tx.Model("hg_trading_strategy_group").Where("group_key", newKey).Value("id")
+			v, e := dao.TradingStrategyGroup.Ctx(ctx).Where("tenant_id", tenantId).Where("group_key", newKey).Value("id")
 			if e != nil {
 				return e
 			}
@@ -690,7 +728,7 @@ func (s *StrategyGroupService) Clone(ctx context.Context, groupId int64) (int64,
 
 		// 复制策略模板
 		var templates []*entity.TradingStrategyTemplate
-		if err := tx.Model("hg_trading_strategy_template").Where("group_id", src.Id).Scan(&templates); err != nil {
+		if err := dao.TradingStrategyTemplate.Ctx(ctx).Where("group_id", src.Id).Scan(&templates); err != nil {
 			return err
 		}
 		// 仅复制“标准12套”（4种市场状态×3种风险偏好），避免源数据存在非标准组合导致复制后模板数量异常。
@@ -733,7 +771,7 @@ func (s *StrategyGroupService) Clone(ctx context.Context, groupId int64) (int64,
 				"created_at":                 gtime.Now(),
 				"updated_at":                 gtime.Now(),
 			}
-			if _, err := tx.Model("hg_trading_strategy_template").Insert(row); err != nil {
+			if _, err := dao.TradingStrategyTemplate.Ctx(ctx).Insert(row); err != nil {
 				return err
 			}
 		}
@@ -745,7 +783,7 @@ func (s *StrategyGroupService) Clone(ctx context.Context, groupId int64) (int64,
 
 	// ✅ 保证 12 套模板：若复制后不足 12，则仅补齐缺失组合（不覆盖已复制的模板）
 	// 注意：这里补齐的是“标准12套”，若源数据只有非标准组合，我们会复制不到任何模板，此时用默认值补齐。
-	cnt, _ := g.DB().Model("hg_trading_strategy_template").Ctx(ctx).Where("group_id", newGroupId).Count()
+	cnt, _ := dao.TradingStrategyTemplate.Ctx(ctx).Where("group_id", newGroupId).Count()
 	if cnt < 12 {
 		if err := s.Init(ctx, &toogoin.StrategyGroupInitInp{
 			GroupId:    newGroupId,
@@ -761,7 +799,7 @@ func (s *StrategyGroupService) Clone(ctx context.Context, groupId int64) (int64,
 func (s *StrategyGroupService) SetDefault(ctx context.Context, groupId int64) error {
 	// 检查模板是否存在
 	var group entity.TradingStrategyGroup
-	err := g.DB().Model("hg_trading_strategy_group").Where("id", groupId).Scan(&group)
+	err := dao.TradingStrategyGroup.Ctx(ctx).Where("id", groupId).Scan(&group)
 	if err != nil {
 		return err
 	}
@@ -773,7 +811,7 @@ func (s *StrategyGroupService) SetDefault(ctx context.Context, groupId int64) er
 	}
 
 	// 清除其他默认标记
-	_, err = g.DB().Model("hg_trading_strategy_group").
+	_, err = dao.TradingStrategyGroup.Ctx(ctx).
 		Where("is_official", 0).
 		Where("is_default", 1).
 		Update(g.Map{"is_default": 0, "updated_at": gtime.Now()})
@@ -782,7 +820,7 @@ func (s *StrategyGroupService) SetDefault(ctx context.Context, groupId int64) er
 	}
 
 	// 设置当前为默认
-	_, err = g.DB().Model("hg_trading_strategy_group").
+	_, err = dao.TradingStrategyGroup.Ctx(ctx).
 		Where("id", groupId).
 		Update(g.Map{"is_default": 1, "updated_at": gtime.Now()})
 
@@ -7,6 +7,7 @@ package toogo
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 	
 	"github.com/gogf/gf/v2/database/gdb"
@@ -14,7 +15,9 @@ import (
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/util/grand"
+	"hotgo/internal/consts"
 	"hotgo/internal/dao"
+	"hotgo/internal/library/contexts"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/toogoin"
 	"hotgo/internal/service"
@@ -38,6 +41,10 @@ func (s *sToogoSubscription) PlanList(ctx context.Context, in *toogoin.PlanListI
 		mod = mod.Where(dao.ToogoPlan.Columns().Status, in.Status)
 	}
 
+	if in.TenantId != nil {
+		mod = mod.Where(dao.ToogoPlan.Columns().TenantId, *in.TenantId)
+	}
+
 	err = mod.OrderAsc(dao.ToogoPlan.Columns().Sort).Page(in.Page, in.PerPage).ScanAndCount(&list, &totalCount, true)
 	if err != nil {
 		err = gerror.Wrap(err, "获取套餐列表失败")
@@ -69,6 +76,8 @@ func (s *sToogoSubscription) PlanEdit(ctx context.Context, in *toogoin.PlanEditI
 		cols.GiftPowerQuarterly: in.GiftPowerQuarterly,
 		cols.GiftPowerHalfYear:  in.GiftPowerHalfYear,
 		cols.GiftPowerYearly:    in.GiftPowerYearly,
+		cols.TrialDays:          in.TrialDays,
+		cols.TrialRobotLimit:    in.TrialRobotLimit,
 		cols.Description:        in.Description,
 		cols.Features:           in.Features,
 		cols.IsDefault:          in.IsDefault,
@@ -76,6 +85,11 @@ func (s *sToogoSubscription) PlanEdit(ctx context.Context, in *toogoin.PlanEditI
 		cols.Status:             in.Status,
 	}
 
+	// 租户只能维护本租户套餐；超管可指定租户，编辑时未指定则保持原归属，新增时归平台
+	if tenantId, ok := targetTenantId(ctx, in.TenantId); ok || in.Id == 0 {
+		data[cols.TenantId] = tenantId
+	}
+
 	if in.Id > 0 {
 		_, err = dao.ToogoPlan.Ctx(ctx).Where(dao.ToogoPlan.Columns().Id, in.Id).Data(data).Update()
 	} else {
@@ -106,60 +120,48 @@ func (s *sToogoSubscription) PlanDelete(ctx context.Context, in *toogoin.PlanDel
 	return
 }
 
-// Subscribe 订阅套餐（支持积分抵扣）
+// Subscribe 订阅套餐（支持积分抵扣、优惠码/推广码、免费试用）
 func (s *sToogoSubscription) Subscribe(ctx context.Context, in *toogoin.SubscribeInp) (res *toogoin.SubscribeModel, err error) {
 	// 获取套餐信息
-	var plan *entity.ToogoPlan
-	err = dao.ToogoPlan.Ctx(ctx).Where(dao.ToogoPlan.Columns().Id, in.PlanId).Scan(&plan)
-	if err != nil || plan == nil {
-		return nil, gerror.New("套餐不存在")
+	plan, err := s.getOnSalePlan(ctx, in.UserId, in.PlanId)
+	if err != nil {
+		return nil, err
 	}
-	if plan.Status != 1 {
-		return nil, gerror.New("套餐已下架")
+
+	// 免费试用
+	if in.PeriodType == consts.SubscriptionPeriodTrial {
+		return s.subscribeTrial(ctx, in, plan)
 	}
 
 	// 计算价格和天数（不再赠送积分）
-	var amount float64
-	var days int
+	amount, days, err := planPeriodPrice(plan, in.PeriodType)
+	if err != nil {
+		return nil, err
+	}
 
-	switch in.PeriodType {
-	case "daily":
-		amount = plan.PriceDaily
-		days = 1
-	case "monthly":
-		amount = plan.PriceMonthly
-		days = 30
-	case "quarterly":
-		amount = plan.PriceQuarterly
-		days = 90
-	case "half_year":
-		amount = plan.PriceHalfYear
-		days = 180
-	case "yearly":
-		amount = plan.PriceYearly
-		days = 365
-	default:
-		return nil, gerror.New("无效的订阅周期")
-	}
-
-	// 购买次数限制校验（套餐级别：按“用户 + 套餐”限购；0 表示不限）
-	// 注意：这里不做前端判断，统一由后端强制。
-	if plan.PurchaseLimit > 0 {
-		subCols := dao.ToogoSubscription.Columns()
-		// 统计该用户购买该套餐的次数（包含：待支付/生效中/已过期；不含已取消）
-		count, err := dao.ToogoSubscription.Ctx(ctx).
-			Where(subCols.UserId, in.UserId).
-			Where(subCols.PlanId, plan.Id).
-			WhereIn(subCols.Status, []int{1, 2, 3}).
-			Count()
+	// 优惠码抵扣（订阅金额记为优惠后金额，佣金按实际支付的余额结算）
+	originalAmount := amount
+	var (
+		coupon   *entity.ToogoCoupon
+		discount float64
+	)
+	if in.CouponCode != "" {
+		coupon, discount, err = s.resolveCoupon(ctx, in.UserId, in.CouponCode, plan, in.PeriodType, amount)
 		if err != nil {
-			return nil, gerror.Wrap(err, "查询套餐购买次数限制失败")
+			return nil, err
 		}
-		if count >= plan.PurchaseLimit {
-			return nil, gerror.Newf("该套餐已达到限购次数（%d次），请更换套餐后再试", plan.PurchaseLimit)
+		amount = math.Round((amount-discount)*100) / 100
+		// 全额抵扣无需外部支付，直接生效
+		if amount <= 0 {
+			in.PayType = "balance"
 		}
 	}
 
+	// 购买次数限制校验
+	if err = s.checkPurchaseLimit(ctx, in.UserId, plan); err != nil {
+		return nil, err
+	}
+
 	// 获取用户信息
 	toogoUser, err := service.ToogoUser().GetOrCreate(ctx, in.UserId)
 	if err != nil {
@@ -173,21 +175,7 @@ func (s *sToogoSubscription) Subscribe(ctx context.Context, in *toogoin.Subscrib
 	}
 
 	// 计算积分抵扣金额（积分可抵扣订阅费用，1积分=1USDT）
-	var pointsDeduct float64 = 0
-	var balanceDeduct float64 = amount
-	usePoints := in.UsePoints // 是否使用积分抵扣
-
-	if usePoints && wallet.GiftPower > 0 && amount > 0 {
-		if wallet.GiftPower >= amount {
-			// 积分足够，全额抵扣
-			pointsDeduct = amount
-			balanceDeduct = 0
-		} else {
-			// 积分不足，部分抵扣
-			pointsDeduct = wallet.GiftPower
-			balanceDeduct = amount - pointsDeduct
-		}
-	}
+	pointsDeduct, balanceDeduct := splitSubscriptionPayment(wallet, amount, in.UsePoints)
 
 	// 检查余额是否足够支付剩余金额
 	if in.PayType == "balance" && balanceDeduct > 0 {
@@ -201,102 +189,60 @@ func (s *sToogoSubscription) Subscribe(ctx context.Context, in *toogoin.Subscrib
 
 	// 计算开始和结束时间
 	startTime := gtime.Now()
-	// 如果当前有有效订阅，从过期时间开始
+	// 如果当前有有效订阅，从过期时间开始（宽限期内续费从当前时间开始）
 	if toogoUser.PlanExpireTime != nil && toogoUser.PlanExpireTime.After(gtime.Now()) {
 		startTime = toogoUser.PlanExpireTime
 	}
 	expireTime := startTime.Add(time.Duration(days) * 24 * time.Hour)
 
+	autoRenew := 0
+	if in.AutoRenew {
+		autoRenew = 1
+	}
+
 	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
 		// 创建订阅记录（不再记录赠送积分）
 		subscription := &entity.ToogoSubscription{
-			UserId:     in.UserId,
-			PlanId:     plan.Id,
-			PlanCode:   plan.PlanCode,
-			OrderSn:    orderSn,
-			PeriodType: in.PeriodType,
-			Amount:     amount,
-			GiftPower:  0, // 不再赠送积分
-			StartTime:  startTime,
-			ExpireTime: expireTime,
-			Days:       days,
-			Status:     1, // 待支付
-			InviterId:  toogoUser.InviterId,
-			CreatedAt:  gtime.Now(),
-			UpdatedAt:  gtime.Now(),
+			UserId:         in.UserId,
+			PlanId:         plan.Id,
+			PlanCode:       plan.PlanCode,
+			OrderSn:        orderSn,
+			PeriodType:     in.PeriodType,
+			Amount:         amount,
+			OriginalAmount: originalAmount,
+			DiscountAmount: discount,
+			GiftPower:      0, // 不再赠送积分
+			StartTime:      startTime,
+			ExpireTime:     expireTime,
+			Days:           days,
+			Status:         consts.SubscriptionStatusPending,
+			AutoRenew:      autoRenew,
+			InviterId:      toogoUser.InviterId,
+			CreatedAt:      gtime.Now(),
+			UpdatedAt:      gtime.Now(),
+		}
+		if coupon != nil {
+			subscription.CouponId = coupon.Id
+			subscription.CouponCode = coupon.Code
 		}
 
 		subscriptionId, err := dao.ToogoSubscription.Ctx(ctx).Data(subscription).InsertAndGetId()
 		if err != nil {
 			return gerror.Wrap(err, "创建订阅记录失败")
 		}
+		subscription.Id = subscriptionId
 
 		// 如果是余额支付
 		if in.PayType == "balance" {
-			// 先扣除积分（如果使用积分抵扣）
-			if pointsDeduct > 0 {
-				err = service.ToogoWallet().ChangeBalance(ctx, &toogoin.ChangeBalanceInp{
-					UserId:      in.UserId,
-					AccountType: "gift_power",
-					ChangeType:  "subscribe_deduct",
-					Amount:      -pointsDeduct,
-					RelatedId:   subscriptionId,
-					RelatedType: "subscription",
-					OrderSn:     orderSn,
-					Remark:      fmt.Sprintf("订阅%s套餐积分抵扣", plan.PlanName),
-				})
-				if err != nil {
-					return gerror.Wrap(err, "扣除积分失败")
-				}
-			}
-
-			// 再扣除余额（如果还有剩余金额）
-			if balanceDeduct > 0 {
-				err = service.ToogoWallet().ChangeBalance(ctx, &toogoin.ChangeBalanceInp{
-					UserId:      in.UserId,
-					AccountType: "balance",
-					ChangeType:  "subscribe",
-					Amount:      -balanceDeduct,
-					RelatedId:   subscriptionId,
-					RelatedType: "subscription",
-					OrderSn:     orderSn,
-					Remark:      fmt.Sprintf("订阅%s套餐", plan.PlanName),
-				})
-				if err != nil {
-					return err
-				}
-			}
-
-			// 更新订阅状态为生效中
-			_, err = dao.ToogoSubscription.Ctx(ctx).Where(dao.ToogoSubscription.Columns().Id, subscriptionId).Data(g.Map{
-				dao.ToogoSubscription.Columns().Status:  2,
-				dao.ToogoSubscription.Columns().PaidAt:  gtime.Now(),
-				dao.ToogoSubscription.Columns().PayType: in.PayType,
-			}).Update()
-			if err != nil {
-				return gerror.Wrap(err, "更新订阅状态失败")
-			}
-
-			// 更新用户套餐信息
-			_, err = dao.ToogoUser.Ctx(ctx).
-				Where(dao.ToogoUser.Columns().MemberId, in.UserId).
-				Data(g.Map{
-					dao.ToogoUser.Columns().CurrentPlanId:  plan.Id,
-					dao.ToogoUser.Columns().PlanExpireTime: expireTime,
-					dao.ToogoUser.Columns().RobotLimit:     plan.RobotLimit,
-				}).
-				Update()
+			err = s.paySubscription(ctx, subscription, plan, pointsDeduct, balanceDeduct, in.PayType, fmt.Sprintf("订阅%s套餐", plan.PlanName))
 			if err != nil {
-				return gerror.Wrap(err, "更新用户套餐信息失败")
-			}
-
-			// 结算订阅佣金（只针对实际支付的余额部分）
-			if balanceDeduct > 0 {
-				err = service.ToogoCommission().SettleSubscribeCommission(ctx, in.UserId, balanceDeduct, subscriptionId, orderSn)
-				if err != nil {
-					g.Log().Warningf(ctx, "结算订阅佣金失败: %v", err)
-				}
+				return err
 			}
+			recordSubscriptionLog(ctx, subscription, "subscribe", consts.SubscriptionStatusPending, consts.SubscriptionStatusActive, amount,
+				fmt.Sprintf("订阅%s套餐(%s)，优惠码%s抵扣%.2f，积分抵扣%.2f，余额支付%.2f", plan.PlanName, in.PeriodType, subscription.CouponCode, discount, pointsDeduct, balanceDeduct))
+		} else {
+			recordSubscriptionLog(ctx, subscription, "subscribe", 0, consts.SubscriptionStatusPending, amount,
+				fmt.Sprintf("创建%s套餐订单(%s)，待支付", plan.PlanName, in.PeriodType))
 		}
 
 		return nil
@@ -309,7 +255,8 @@ func (s *sToogoSubscription) Subscribe(ctx context.Context, in *toogoin.Subscrib
 	res = &toogoin.SubscribeModel{
 		OrderSn:      orderSn,
 		PlanName:     plan.PlanName,
-		Amount:       amount,
+		Amount:       originalAmount,
+		Discount:     discount,
 		PointsDeduct: pointsDeduct,  // 积分抵扣金额
 		BalancePaid:  balanceDeduct, // 余额支付金额
 		Days:         days,
@@ -318,6 +265,165 @@ func (s *sToogoSubscription) Subscribe(ctx context.Context, in *toogoin.Subscrib
 	return
 }
 
+// getOnSalePlan 获取上架中的套餐，套餐必须属于用户所在租户
+func (s *sToogoSubscription) getOnSalePlan(ctx context.Context, userId, planId int64) (plan *entity.ToogoPlan, err error) {
+	err = dao.ToogoPlan.Ctx(ctx).Where(dao.ToogoPlan.Columns().Id, planId).Scan(&plan)
+	if err != nil || plan == nil {
+		return nil, gerror.New("套餐不存在")
+	}
+	if userId <= 0 {
+		userId = contexts.GetUserId(ctx)
+	}
+	if plan.TenantId != service.ToogoTenant().MemberTenantId(ctx, userId) {
+		return nil, gerror.New("套餐不存在")
+	}
+	if plan.Status != 1 {
+		return nil, gerror.New("套餐已下架")
+	}
+	return plan, nil
+}
+
+// planPeriodPrice 套餐周期价格与天数
+func planPeriodPrice(plan *entity.ToogoPlan, periodType string) (amount float64, days int, err error) {
+	switch periodType {
+	case "daily":
+		return plan.PriceDaily, 1, nil
+	case "monthly":
+		return plan.PriceMonthly, 30, nil
+	case "quarterly":
+		return plan.PriceQuarterly, 90, nil
+	case "half_year":
+		return plan.PriceHalfYear, 180, nil
+	case "yearly":
+		return plan.PriceYearly, 365, nil
+	default:
+		return 0, 0, gerror.New("无效的订阅周期")
+	}
+}
+
+// checkPurchaseLimit 购买次数限制校验（套餐级别：按“用户 + 套餐”限购；0 表示不限）
+// 注意：这里不做前端判断，统一由后端强制。
+func (s *sToogoSubscription) checkPurchaseLimit(ctx context.Context, userId int64, plan *entity.ToogoPlan) error {
+	if plan.PurchaseLimit <= 0 {
+		return nil
+	}
+	subCols := dao.ToogoSubscription.Columns()
+	// 统计该用户购买该套餐的次数（包含：待支付/生效中/已过期/宽限期/已变更；不含已取消和免费试用）
+	count, err := dao.ToogoSubscription.Ctx(ctx).
+		Where(subCols.UserId, userId).
+		Where(subCols.PlanId, plan.Id).
+		WhereNot(subCols.Status, consts.SubscriptionStatusCancelled).
+		WhereNot(subCols.PeriodType, consts.SubscriptionPeriodTrial).
+		Count()
+	if err != nil {
+		return gerror.Wrap(err, "查询套餐购买次数限制失败")
+	}
+	if count >= plan.PurchaseLimit {
+		return gerror.Newf("该套餐已达到限购次数（%d次），请更换套餐后再试", plan.PurchaseLimit)
+	}
+	return nil
+}
+
+// splitSubscriptionPayment 积分抵扣拆分：使用积分时优先抵扣，不足部分由余额支付
+func splitSubscriptionPayment(wallet *entity.ToogoWallet, amount float64, usePoints bool) (pointsDeduct, balanceDeduct float64) {
+	balanceDeduct = amount
+	if usePoints && wallet.GiftPower > 0 && amount > 0 {
+		if wallet.GiftPower >= amount {
+			// 积分足够，全额抵扣
+			pointsDeduct = amount
+			balanceDeduct = 0
+		} else {
+			// 积分不足，部分抵扣
+			pointsDeduct = wallet.GiftPower
+			balanceDeduct = amount - pointsDeduct
+		}
+	}
+	return
+}
+
+// paySubscription 余额/积分支付订阅并生效：扣款、更新订阅与用户套餐、结算佣金（需在事务内调用）
+func (s *sToogoSubscription) paySubscription(ctx context.Context, subscription *entity.ToogoSubscription, plan *entity.ToogoPlan, pointsDeduct, balanceDeduct float64, payType, remark string) (err error) {
+	// 优惠码在支付成功时才占用次数并绑定推广关系，未支付的订单不消耗优惠码
+	if subscription.CouponId > 0 {
+		if err = s.redeemCoupon(ctx, subscription); err != nil {
+			return err
+		}
+	}
+
+	// 先扣除积分（如果使用积分抵扣）
+	if pointsDeduct > 0 {
+		err = service.ToogoWallet().ChangeBalance(ctx, &toogoin.ChangeBalanceInp{
+			UserId:      subscription.UserId,
+			AccountType: "gift_power",
+			ChangeType:  "subscribe_deduct",
+			Amount:      -pointsDeduct,
+			RelatedId:   subscription.Id,
+			RelatedType: "subscription",
+			OrderSn:     subscription.OrderSn,
+			Remark:      remark + "积分抵扣",
+		})
+		if err != nil {
+			return gerror.Wrap(err, "扣除积分失败")
+		}
+	}
+
+	// 再扣除余额（如果还有剩余金额）
+	if balanceDeduct > 0 {
+		err = service.ToogoWallet().ChangeBalance(ctx, &toogoin.ChangeBalanceInp{
+			UserId:      subscription.UserId,
+			AccountType: "balance",
+			ChangeType:  "subscribe",
+			Amount:      -balanceDeduct,
+			RelatedId:   subscription.Id,
+			RelatedType: "subscription",
+			OrderSn:     subscription.OrderSn,
+			Remark:      remark,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// 更新订阅状态为生效中
+	_, err = dao.ToogoSubscription.Ctx(ctx).Where(dao.ToogoSubscription.Columns().Id, subscription.Id).Data(g.Map{
+		dao.ToogoSubscription.Columns().Status:  consts.SubscriptionStatusActive,
+		dao.ToogoSubscription.Columns().PaidAt:  gtime.Now(),
+		dao.ToogoSubscription.Columns().PayType: payType,
+	}).Update()
+	if err != nil {
+		return gerror.Wrap(err, "更新订阅状态失败")
+	}
+	subscription.Status = consts.SubscriptionStatusActive
+
+	// 宽限期内续费：结束宽限期
+	if err = s.closeGrace(ctx, subscription.UserId, subscription.Id); err != nil {
+		return err
+	}
+
+	// 更新用户套餐信息
+	_, err = dao.ToogoUser.Ctx(ctx).
+		Where(dao.ToogoUser.Columns().MemberId, subscription.UserId).
+		Data(g.Map{
+			dao.ToogoUser.Columns().CurrentPlanId:   plan.Id,
+			dao.ToogoUser.Columns().PlanExpireTime:  subscription.ExpireTime,
+			dao.ToogoUser.Columns().PlanGraceExpire: nil,
+			dao.ToogoUser.Columns().RobotLimit:      plan.RobotLimit,
+		}).
+		Update()
+	if err != nil {
+		return gerror.Wrap(err, "更新用户套餐信息失败")
+	}
+
+	// 结算订阅佣金（只针对实际支付的余额部分）
+	if balanceDeduct > 0 {
+		err = service.ToogoCommission().SettleSubscribeCommission(ctx, subscription.UserId, balanceDeduct, subscription.Id, subscription.OrderSn)
+		if err != nil {
+			g.Log().Warningf(ctx, "结算订阅佣金失败: %v", err)
+		}
+	}
+	return nil
+}
+
 // SubscriptionList 订阅记录列表
 func (s *sToogoSubscription) SubscriptionList(ctx context.Context, in *toogoin.SubscriptionListInp) (list []*toogoin.SubscriptionListModel, totalCount int, err error) {
 	mod := dao.ToogoSubscription.Ctx(ctx)
@@ -387,107 +493,28 @@ func (s *sToogoSubscription) MySubscription(ctx context.Context, in *toogoin.MyS
 				res.PlanName = plan.PlanName
 				res.PlanCode = plan.PlanCode
 			}
-		}
-	}
-
-	return
-}
-
-// CheckExpired 检查并处理过期订阅 (定时任务调用)
-func (s *sToogoSubscription) CheckExpired(ctx context.Context) error {
-	// 查找已过期但状态还是生效中的订阅
-	var subscriptions []*entity.ToogoSubscription
-	err := dao.ToogoSubscription.Ctx(ctx).
-		Where(dao.ToogoSubscription.Columns().Status, 2). // 生效中
-		WhereLT(dao.ToogoSubscription.Columns().ExpireTime, gtime.Now()).
-		Scan(&subscriptions)
-	if err != nil {
-		return gerror.Wrap(err, "查询过期订阅失败")
-	}
-
-	for _, sub := range subscriptions {
-		// 更新订阅状态为已过期
-		_, err = dao.ToogoSubscription.Ctx(ctx).Where(dao.ToogoSubscription.Columns().Id, sub.Id).Data(g.Map{
-			dao.ToogoSubscription.Columns().Status: 3, // 已过期
-		}).Update()
-		if err != nil {
-			g.Log().Warningf(ctx, "更新订阅状态失败: %v", err)
-			continue
-		}
-
-		// 检查用户是否还有其他有效订阅
-		count, err := dao.ToogoSubscription.Ctx(ctx).
-			Where(dao.ToogoSubscription.Columns().UserId, sub.UserId).
-			Where(dao.ToogoSubscription.Columns().Status, 2).
-			WhereGT(dao.ToogoSubscription.Columns().ExpireTime, gtime.Now()).
-			Count()
-		if err != nil {
-			g.Log().Warningf(ctx, "检查用户订阅失败: %v", err)
-			continue
-		}
-
-		// 如果没有其他有效订阅，需要停止用户的所有运行中机器人并重置套餐
-		if count == 0 {
-			// 停止用户的所有运行中机器人（会自动平仓所有订单）
-			var runningRobots []*entity.TradingRobot
-			err = dao.TradingRobot.Ctx(ctx).
-				Where(dao.TradingRobot.Columns().UserId, sub.UserId).
-				Where(dao.TradingRobot.Columns().Status, 2). // 运行中
-				WhereNull(dao.TradingRobot.Columns().DeletedAt).
-				Scan(&runningRobots)
-			if err != nil {
-				g.Log().Warningf(ctx, "查询用户运行中机器人失败: %v", err)
-			} else {
-				// 停止所有运行中的机器人
-				for _, robot := range runningRobots {
-					// 使用系统上下文（不需要用户登录）
-					systemCtx := context.Background()
-					// 调用机器人任务管理器平仓并停止
-					if err := GetRobotTaskManager().CloseAllAndWait(systemCtx, robot.Id, "subscription_expired", 30*time.Second); err != nil {
-						g.Log().Warningf(ctx, "停止机器人失败 (robotId=%d): %v", robot.Id, err)
-						continue
-					}
-					// 更新机器人状态为未启动
-					_, err = dao.TradingRobot.Ctx(ctx).
-						Where(dao.TradingRobot.Columns().Id, robot.Id).
-						Data(g.Map{
-							dao.TradingRobot.Columns().Status:   1, // 未启动
-							dao.TradingRobot.Columns().StopTime: gtime.Now(),
-						}).
-						Update()
-					if err != nil {
-						g.Log().Warningf(ctx, "更新机器人状态失败 (robotId=%d): %v", robot.Id, err)
-					} else {
-						g.Log().Infof(ctx, "订阅到期自动停止机器人: userId=%d, robotId=%d, robotName=%s", sub.UserId, robot.Id, robot.RobotName)
-					}
-				}
-			}
-
-			// 获取免费套餐
-			var freePlan *entity.ToogoPlan
-			dao.ToogoPlan.Ctx(ctx).Where(dao.ToogoPlan.Columns().IsDefault, 1).Scan(&freePlan)
-
-			robotLimit := 1
-			if freePlan != nil {
-				robotLimit = freePlan.RobotLimit
-			}
+		} else if toogoUser.PlanGraceExpire != nil && toogoUser.PlanGraceExpire.After(gtime.Now()) {
+			// 宽限期：机器人继续管理持仓，但不再开新仓
+			res.InGrace = true
+			res.PlanId = toogoUser.CurrentPlanId
+			res.RobotLimit = toogoUser.RobotLimit
+			res.ActiveRobots = toogoUser.ActiveRobotCount
+			res.ExpireTime = toogoUser.PlanExpireTime.String()
+			res.GraceExpireTime = toogoUser.PlanGraceExpire.String()
 
-			// 重置用户套餐为免费套餐
-			_, err = dao.ToogoUser.Ctx(ctx).
-				Where(dao.ToogoUser.Columns().MemberId, sub.UserId).
-				Data(g.Map{
-					dao.ToogoUser.Columns().CurrentPlanId:  0,
-					dao.ToogoUser.Columns().PlanExpireTime: nil,
-					dao.ToogoUser.Columns().RobotLimit:     robotLimit,
-				}).
-				Update()
-			if err != nil {
-				g.Log().Warningf(ctx, "重置用户套餐失败: %v", err)
+			var plan *entity.ToogoPlan
+			dao.ToogoPlan.Ctx(ctx).Where(dao.ToogoPlan.Columns().Id, toogoUser.CurrentPlanId).Scan(&plan)
+			if plan != nil {
+				res.PlanName = plan.PlanName
+				res.PlanCode = plan.PlanCode
 			}
 		}
 	}
 
-	g.Log().Infof(ctx, "处理过期订阅完成，共处理 %d 条", len(subscriptions))
-	return nil
-}
+	// 自动续费状态取最后一笔订阅（续费从它开始）
+	if last, _ := s.lastSubscription(ctx, in.UserId); last != nil {
+		res.AutoRenew = last.AutoRenew == 1
+	}
 
+	return
+}
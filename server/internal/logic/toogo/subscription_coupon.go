@@ -0,0 +1,490 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 订阅优惠：优惠券、代理推广码与套餐免费试用
+package toogo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/toogoin"
+	"hotgo/internal/service"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/gogf/gf/v2/util/grand"
+)
+
+var couponCodeRegex = regexp.MustCompile(`^[A-Z0-9_-]{4,32}$`)
+
+// normalizeCouponCode 优惠码不区分大小写，统一按大写存储和匹配
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// CouponList 优惠券列表
+func (s *sToogoSubscription) CouponList(ctx context.Context, in *toogoin.CouponListInp) (list []*toogoin.CouponListModel, totalCount int, err error) {
+	cols := dao.ToogoCoupon.Columns()
+	mod := dao.ToogoCoupon.Ctx(ctx)
+	if in.Code != "" {
+		mod = mod.WhereLike(cols.Code, "%"+normalizeCouponCode(in.Code)+"%")
+	}
+	if in.AgentId > 0 {
+		mod = mod.Where(cols.AgentId, in.AgentId)
+	}
+	if in.Status > 0 {
+		mod = mod.Where(cols.Status, in.Status)
+	}
+	if in.TenantId != nil {
+		mod = mod.Where(cols.TenantId, *in.TenantId)
+	}
+
+	err = mod.OrderDesc(cols.Id).Page(in.Page, in.PerPage).ScanAndCount(&list, &totalCount, true)
+	if err != nil {
+		err = gerror.Wrap(err, "获取优惠券列表失败")
+	}
+	return
+}
+
+// CouponEdit 编辑优惠券（agentId > 0 为代理推广码）
+func (s *sToogoSubscription) CouponEdit(ctx context.Context, in *toogoin.CouponEditInp) (err error) {
+	code := normalizeCouponCode(in.Code)
+	if !couponCodeRegex.MatchString(code) {
+		return gerror.New("优惠码为4-32位字母、数字、下划线或中划线")
+	}
+	if in.CouponType == consts.CouponTypePercent && in.DiscountValue > 100 {
+		return gerror.New("折扣比例不能超过100%")
+	}
+	if in.StartTime != nil && in.ExpireTime != nil && !in.ExpireTime.After(in.StartTime) {
+		return gerror.New("过期时间必须晚于生效时间")
+	}
+	for _, period := range in.PeriodTypes {
+		if _, _, err = planPeriodPrice(&entity.ToogoPlan{}, period); err != nil {
+			return gerror.Newf("无效的订阅周期: %s", period)
+		}
+	}
+	cols := dao.ToogoCoupon.Columns()
+
+	// 优惠券归属租户：编辑时未显式指定保持原归属，推广码跟随代理所属租户
+	tenantId, tenantSet := targetTenantId(ctx, in.TenantId)
+	if in.Id > 0 && !tenantSet {
+		var current *entity.ToogoCoupon
+		if err = dao.ToogoCoupon.Ctx(ctx).Where(cols.Id, in.Id).Scan(&current); err != nil {
+			return gerror.Wrap(err, "获取优惠券失败")
+		}
+		if current == nil {
+			return gerror.New("优惠券不存在")
+		}
+		tenantId = current.TenantId
+	}
+	if in.AgentId > 0 {
+		var agent *entity.ToogoUser
+		if err = dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, in.AgentId).Scan(&agent); err != nil {
+			return gerror.Wrap(err, "查询代理信息失败")
+		}
+		if agent == nil || agent.IsAgent != 1 {
+			return gerror.New("推广码归属用户不是代理商")
+		}
+		if in.Id == 0 && !tenantSet {
+			tenantId = agent.TenantId
+		}
+		if agent.TenantId != tenantId {
+			return gerror.New("推广码归属代理不属于该租户")
+		}
+	}
+
+	count, err := dao.ToogoCoupon.Ctx(ctx).Where(cols.TenantId, tenantId).Where(cols.Code, code).WhereNot(cols.Id, in.Id).Count()
+	if err != nil {
+		return gerror.Wrap(err, "检查优惠码失败")
+	}
+	if count > 0 {
+		return gerror.New("优惠码已存在")
+	}
+
+	status := in.Status
+	if status == 0 {
+		status = 1
+	}
+	data := g.Map{
+		cols.TenantId:      tenantId,
+		cols.Code:          code,
+		cols.CouponName:    in.CouponName,
+		cols.CouponType:    in.CouponType,
+		cols.DiscountValue: in.DiscountValue,
+		cols.MaxDiscount:   in.MaxDiscount,
+		cols.MinAmount:     in.MinAmount,
+		cols.PlanIds:       strings.Join(gconv.Strings(in.PlanIds), ","),
+		cols.PeriodTypes:   strings.Join(in.PeriodTypes, ","),
+		cols.TotalLimit:    in.TotalLimit,
+		cols.AgentId:       in.AgentId,
+		cols.StartTime:     in.StartTime,
+		cols.ExpireTime:    in.ExpireTime,
+		cols.Status:        status,
+		cols.Remark:        in.Remark,
+		cols.UpdatedAt:     gtime.Now(),
+	}
+
+	if in.Id > 0 {
+		_, err = dao.ToogoCoupon.Ctx(ctx).Where(cols.Id, in.Id).Data(data).Update()
+	} else {
+		data[cols.CreatedAt] = gtime.Now()
+		_, err = dao.ToogoCoupon.Ctx(ctx).Data(data).Insert()
+	}
+	if err != nil {
+		err = gerror.Wrap(err, "保存优惠券失败")
+	}
+	return
+}
+
+// CouponDelete 删除优惠券（已有使用记录的只能停用）
+func (s *sToogoSubscription) CouponDelete(ctx context.Context, in *toogoin.CouponDeleteInp) error {
+	count, err := dao.ToogoCouponUsage.Ctx(ctx).Where(dao.ToogoCouponUsage.Columns().CouponId, in.Id).Count()
+	if err != nil {
+		return gerror.Wrap(err, "检查优惠券使用情况失败")
+	}
+	if count > 0 {
+		return gerror.Newf("该优惠券已被使用%d次，无法删除，请改为停用", count)
+	}
+	if _, err = dao.ToogoCoupon.Ctx(ctx).Where(dao.ToogoCoupon.Columns().Id, in.Id).Delete(); err != nil {
+		return gerror.Wrap(err, "删除优惠券失败")
+	}
+	return nil
+}
+
+// CouponUsageList 优惠券使用记录
+func (s *sToogoSubscription) CouponUsageList(ctx context.Context, in *toogoin.CouponUsageListInp) (list []*toogoin.CouponUsageListModel, totalCount int, err error) {
+	cols := dao.ToogoCouponUsage.Columns()
+	mod := dao.ToogoCouponUsage.Ctx(ctx)
+	if in.CouponId > 0 {
+		mod = mod.Where(cols.CouponId, in.CouponId)
+	}
+	if in.UserId > 0 {
+		mod = mod.Where(cols.UserId, in.UserId)
+	}
+	if in.AgentId > 0 {
+		mod = mod.Where(cols.AgentId, in.AgentId)
+	}
+
+	err = mod.OrderDesc(cols.Id).Page(in.Page, in.PerPage).ScanAndCount(&list, &totalCount, true)
+	if err != nil {
+		err = gerror.Wrap(err, "获取优惠券使用记录失败")
+	}
+	return
+}
+
+// CouponPreview 优惠码试算（与 Subscribe 使用相同的校验）
+func (s *sToogoSubscription) CouponPreview(ctx context.Context, in *toogoin.CouponPreviewInp) (*toogoin.CouponPreviewModel, error) {
+	plan, err := s.getOnSalePlan(ctx, in.UserId, in.PlanId)
+	if err != nil {
+		return nil, err
+	}
+	amount, _, err := planPeriodPrice(plan, in.PeriodType)
+	if err != nil {
+		return nil, err
+	}
+	coupon, discount, err := s.resolveCoupon(ctx, in.UserId, in.CouponCode, plan, in.PeriodType, amount)
+	if err != nil {
+		return nil, err
+	}
+	return &toogoin.CouponPreviewModel{
+		CouponCode:     coupon.Code,
+		CouponName:     coupon.CouponName,
+		OriginalAmount: amount,
+		Discount:       discount,
+		PayAmount:      math.Round((amount-discount)*100) / 100,
+		IsAgentCode:    coupon.AgentId > 0,
+	}, nil
+}
+
+// resolveCoupon 校验优惠码并计算抵扣金额
+func (s *sToogoSubscription) resolveCoupon(ctx context.Context, userId int64, code string, plan *entity.ToogoPlan, periodType string, amount float64) (coupon *entity.ToogoCoupon, discount float64, err error) {
+	cols := dao.ToogoCoupon.Columns()
+	err = dao.ToogoCoupon.Ctx(ctx).
+		Where(cols.TenantId, plan.TenantId).
+		Where(cols.Code, normalizeCouponCode(code)).
+		Scan(&coupon)
+	if err != nil {
+		return nil, 0, gerror.Wrap(err, "查询优惠码失败")
+	}
+	if err = checkCoupon(coupon, userId, plan, periodType, amount, gtime.Now()); err != nil {
+		return nil, 0, err
+	}
+
+	used, err := dao.ToogoCouponUsage.Ctx(ctx).
+		Where(dao.ToogoCouponUsage.Columns().CouponId, coupon.Id).
+		Where(dao.ToogoCouponUsage.Columns().UserId, userId).
+		Count()
+	if err != nil {
+		return nil, 0, gerror.Wrap(err, "查询优惠码使用记录失败")
+	}
+	if used > 0 {
+		return nil, 0, gerror.New("您已使用过该优惠码")
+	}
+
+	return coupon, couponDiscount(coupon, amount), nil
+}
+
+// checkCoupon 校验优惠码本身的适用条件（不含同一用户限用一次）
+func checkCoupon(coupon *entity.ToogoCoupon, userId int64, plan *entity.ToogoPlan, periodType string, amount float64, now *gtime.Time) error {
+	// 优惠码只能用于同一租户的套餐
+	if coupon == nil || coupon.Status != 1 || coupon.TenantId != plan.TenantId {
+		return gerror.New("优惠码无效")
+	}
+	if coupon.StartTime != nil && coupon.StartTime.After(now) {
+		return gerror.New("优惠码尚未生效")
+	}
+	if coupon.ExpireTime != nil && !coupon.ExpireTime.After(now) {
+		return gerror.New("优惠码已过期")
+	}
+	if coupon.TotalLimit > 0 && coupon.UsedCount >= coupon.TotalLimit {
+		return gerror.New("优惠码已被领完")
+	}
+	if coupon.PlanIds != "" && !inCommaList(coupon.PlanIds, gconv.String(plan.Id)) {
+		return gerror.New("优惠码不适用于该套餐")
+	}
+	if coupon.PeriodTypes != "" && !inCommaList(coupon.PeriodTypes, periodType) {
+		return gerror.New("优惠码不适用于该订阅周期")
+	}
+	if coupon.MinAmount > 0 && amount < coupon.MinAmount {
+		return gerror.Newf("订单金额满 %.2f USDT 才可使用该优惠码", coupon.MinAmount)
+	}
+	if coupon.AgentId > 0 && coupon.AgentId == userId {
+		return gerror.New("不能使用自己的推广码")
+	}
+	return nil
+}
+
+// couponDiscount 优惠券抵扣金额：按比例或固定金额，受最高抵扣与订单金额限制，精确到分（向下取整）
+func couponDiscount(coupon *entity.ToogoCoupon, amount float64) float64 {
+	var discount float64
+	switch coupon.CouponType {
+	case consts.CouponTypePercent:
+		discount = amount * coupon.DiscountValue / 100
+	case consts.CouponTypeFixed:
+		discount = coupon.DiscountValue
+	}
+	if coupon.MaxDiscount > 0 && discount > coupon.MaxDiscount {
+		discount = coupon.MaxDiscount
+	}
+	if discount > amount {
+		discount = amount
+	}
+	if discount < 0 {
+		discount = 0
+	}
+	return math.Floor(discount*100+1e-9) / 100
+}
+
+func inCommaList(list, value string) bool {
+	for _, item := range strings.Split(list, ",") {
+		if strings.TrimSpace(item) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// redeemCoupon 订阅支付成功时兑现优惠码（需在事务内调用）
+// 代理推广码先为未绑定邀请人的用户绑定到该代理，本单佣金即按新的代理链结算；再占用使用次数并记录使用
+func (s *sToogoSubscription) redeemCoupon(ctx context.Context, sub *entity.ToogoSubscription) error {
+	var coupon *entity.ToogoCoupon
+	if err := dao.ToogoCoupon.Ctx(ctx).Where(dao.ToogoCoupon.Columns().Id, sub.CouponId).Scan(&coupon); err != nil {
+		return gerror.Wrap(err, "查询优惠码失败")
+	}
+	if coupon == nil {
+		return gerror.New("优惠码无效")
+	}
+
+	attributed := false
+	if coupon.AgentId > 0 {
+		toogoUser, err := service.ToogoUser().GetOrCreate(ctx, sub.UserId)
+		if err != nil {
+			return err
+		}
+		if attributed, err = s.attributeAgent(ctx, toogoUser, coupon.AgentId); err != nil {
+			return err
+		}
+		if attributed {
+			_, err = dao.ToogoSubscription.Ctx(ctx).
+				Where(dao.ToogoSubscription.Columns().Id, sub.Id).
+				Data(dao.ToogoSubscription.Columns().InviterId, coupon.AgentId).
+				Update()
+			if err != nil {
+				return gerror.Wrap(err, "更新订阅邀请人失败")
+			}
+			sub.InviterId = coupon.AgentId
+		}
+	}
+	return s.consumeCoupon(ctx, coupon, sub, attributed)
+}
+
+// consumeCoupon 占用优惠券使用次数并记录使用（需在事务内调用）
+func (s *sToogoSubscription) consumeCoupon(ctx context.Context, coupon *entity.ToogoCoupon, sub *entity.ToogoSubscription, attributed bool) error {
+	cols := dao.ToogoCoupon.Columns()
+	mod := dao.ToogoCoupon.Ctx(ctx).Where(cols.Id, coupon.Id).Where(cols.Status, 1)
+	if coupon.TotalLimit > 0 {
+		mod = mod.WhereLT(cols.UsedCount, coupon.TotalLimit)
+	}
+	result, err := mod.Increment(cols.UsedCount, 1)
+	if err != nil {
+		return gerror.Wrap(err, "更新优惠码使用次数失败")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return gerror.New("优惠码已被领完")
+	}
+
+	_, err = dao.ToogoCouponUsage.Ctx(ctx).Data(&entity.ToogoCouponUsage{
+		CouponId:       coupon.Id,
+		CouponCode:     coupon.Code,
+		UserId:         sub.UserId,
+		AgentId:        coupon.AgentId,
+		Attributed:     gconv.Int(attributed),
+		SubscriptionId: sub.Id,
+		OrderSn:        sub.OrderSn,
+		PlanId:         sub.PlanId,
+		PeriodType:     sub.PeriodType,
+		OriginalAmount: sub.OriginalAmount,
+		DiscountAmount: sub.DiscountAmount,
+		CreatedAt:      gtime.Now(),
+	}).Insert()
+	if err != nil {
+		// 唯一索引 coupon_id + user_id 兜底并发重复使用
+		return gerror.Wrap(err, "记录优惠码使用失败（同一用户限用一次）")
+	}
+	return nil
+}
+
+// attributeAgent 推广码绑定邀请关系：仅对尚未绑定邀请人的用户生效，且不能形成循环（需在事务内调用）
+func (s *sToogoSubscription) attributeAgent(ctx context.Context, user *entity.ToogoUser, agentId int64) (bool, error) {
+	if user.InviterId > 0 || agentId == user.MemberId {
+		return false, nil
+	}
+	if service.ToogoTenant().MemberTenantId(ctx, agentId) != user.TenantId {
+		g.Log().Infof(ctx, "[Coupon] 推广码代理 %d 与用户 %d 不属于同一租户，不绑定邀请关系", agentId, user.MemberId)
+		return false, nil
+	}
+	agentAncestors, err := loadAncestors(ctx, agentId)
+	if err != nil {
+		return false, gerror.Wrap(err, "查询推广码代理上级链失败")
+	}
+	if slices.Contains(agentAncestors, user.MemberId) {
+		g.Log().Infof(ctx, "[Coupon] 推广码代理 %d 是用户 %d 的下级，不绑定邀请关系", agentId, user.MemberId)
+		return false, nil
+	}
+
+	// 事务内加锁重读，避免并发绑定
+	var current *entity.ToogoUser
+	err = dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, user.MemberId).LockUpdate().Scan(&current)
+	if err != nil {
+		return false, gerror.Wrap(err, "绑定推广关系失败")
+	}
+	if current == nil || current.InviterId > 0 {
+		return false, nil
+	}
+	// 用户及其团队整体挂到代理名下
+	if err = bindInviter(ctx, current, agentId); err != nil {
+		return false, gerror.Wrap(err, "绑定推广关系失败")
+	}
+	user.InviterId = agentId
+	return true, nil
+}
+
+// subscribeTrial 套餐免费试用：每个用户仅限一次，且当前没有生效中的订阅
+func (s *sToogoSubscription) subscribeTrial(ctx context.Context, in *toogoin.SubscribeInp, plan *entity.ToogoPlan) (*toogoin.SubscribeModel, error) {
+	if plan.TrialDays <= 0 {
+		return nil, gerror.New("该套餐不支持免费试用")
+	}
+	if in.CouponCode != "" {
+		return nil, gerror.New("免费试用不能使用优惠码")
+	}
+
+	if _, err := service.ToogoUser().GetOrCreate(ctx, in.UserId); err != nil {
+		return nil, err
+	}
+
+	// 试用期按试用机器人数量生效
+	trialPlan := *plan
+	if plan.TrialRobotLimit > 0 {
+		trialPlan.RobotLimit = plan.TrialRobotLimit
+	}
+
+	now := gtime.Now()
+	expireTime := now.Add(time.Duration(plan.TrialDays) * 24 * time.Hour)
+	orderSn := fmt.Sprintf("SUB%s%s", now.Format("YmdHis"), grand.S(6))
+
+	err := g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		// 锁定用户行后再校验试用资格，并发请求串行执行，避免重复试用
+		var toogoUser *entity.ToogoUser
+		err := dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, in.UserId).LockUpdate().Scan(&toogoUser)
+		if err != nil {
+			return gerror.Wrap(err, "查询用户信息失败")
+		}
+		if toogoUser == nil {
+			return gerror.New("用户不存在")
+		}
+
+		count, err := dao.ToogoSubscription.Ctx(ctx).
+			Where(dao.ToogoSubscription.Columns().UserId, in.UserId).
+			Where(dao.ToogoSubscription.Columns().PeriodType, consts.SubscriptionPeriodTrial).
+			Count()
+		if err != nil {
+			return gerror.Wrap(err, "查询试用记录失败")
+		}
+		if count > 0 {
+			return gerror.New("您已使用过免费试用")
+		}
+		if toogoUser.PlanExpireTime != nil && toogoUser.PlanExpireTime.After(gtime.Now()) {
+			return gerror.New("当前已有生效中的订阅，无需试用")
+		}
+
+		subscription := &entity.ToogoSubscription{
+			UserId:     in.UserId,
+			PlanId:     plan.Id,
+			PlanCode:   plan.PlanCode,
+			OrderSn:    orderSn,
+			PeriodType: consts.SubscriptionPeriodTrial,
+			StartTime:  now,
+			ExpireTime: expireTime,
+			Days:       plan.TrialDays,
+			Status:     consts.SubscriptionStatusPending,
+			InviterId:  toogoUser.InviterId,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		subscriptionId, err := dao.ToogoSubscription.Ctx(ctx).Data(subscription).InsertAndGetId()
+		if err != nil {
+			return gerror.Wrap(err, "创建试用订阅失败")
+		}
+		subscription.Id = subscriptionId
+
+		if err = s.paySubscription(ctx, subscription, &trialPlan, 0, 0, "free", fmt.Sprintf("试用%s套餐", plan.PlanName)); err != nil {
+			return err
+		}
+		recordSubscriptionLog(ctx, subscription, "trial", consts.SubscriptionStatusPending, consts.SubscriptionStatusActive, 0,
+			fmt.Sprintf("免费试用%s套餐%d天，机器人上限%d", plan.PlanName, plan.TrialDays, trialPlan.RobotLimit))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &toogoin.SubscribeModel{
+		OrderSn:    orderSn,
+		PlanName:   plan.PlanName,
+		Days:       plan.TrialDays,
+		ExpireTime: expireTime.String(),
+	}, nil
+}
@@ -0,0 +1,79 @@
+// Package toogo
+// @Description 优惠码抵扣测试
+package toogo
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"hotgo/internal/consts"
+	"hotgo/internal/model/entity"
+
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+func TestCouponDiscount(t *testing.T) {
+	testCases := []struct {
+		name     string
+		coupon   *entity.ToogoCoupon
+		amount   float64
+		expected float64
+	}{
+		{"按比例", &entity.ToogoCoupon{CouponType: consts.CouponTypePercent, DiscountValue: 20}, 99, 19.8},
+		{"按比例向下取整到分", &entity.ToogoCoupon{CouponType: consts.CouponTypePercent, DiscountValue: 15}, 33.33, 4.99},
+		{"按比例受最高抵扣限制", &entity.ToogoCoupon{CouponType: consts.CouponTypePercent, DiscountValue: 50, MaxDiscount: 30}, 100, 30},
+		{"固定金额", &entity.ToogoCoupon{CouponType: consts.CouponTypeFixed, DiscountValue: 10}, 99, 10},
+		{"固定金额受最高抵扣限制", &entity.ToogoCoupon{CouponType: consts.CouponTypeFixed, DiscountValue: 50, MaxDiscount: 20}, 99, 20},
+		{"固定金额不超过订单金额", &entity.ToogoCoupon{CouponType: consts.CouponTypeFixed, DiscountValue: 50}, 29.9, 29.9},
+		{"负数折扣按0处理", &entity.ToogoCoupon{CouponType: consts.CouponTypeFixed, DiscountValue: -5}, 99, 0},
+		{"未知类型不抵扣", &entity.ToogoCoupon{CouponType: "unknown", DiscountValue: 10}, 99, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := couponDiscount(tc.coupon, tc.amount); math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("couponDiscount() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCheckCoupon(t *testing.T) {
+	now := gtime.Now()
+	plan := &entity.ToogoPlan{Id: 2, TenantId: 1}
+	valid := func() *entity.ToogoCoupon {
+		return &entity.ToogoCoupon{TenantId: 1, Status: 1, CouponType: consts.CouponTypeFixed, DiscountValue: 10}
+	}
+
+	testCases := []struct {
+		name    string
+		modify  func(c *entity.ToogoCoupon)
+		amount  float64
+		wantErr bool
+	}{
+		{"可用", func(c *entity.ToogoCoupon) {}, 99, false},
+		{"满足最低金额", func(c *entity.ToogoCoupon) { c.MinAmount = 99 }, 99, false},
+		{"未达最低金额", func(c *entity.ToogoCoupon) { c.MinAmount = 100 }, 99, true},
+		{"已停用", func(c *entity.ToogoCoupon) { c.Status = 0 }, 99, true},
+		{"其他租户", func(c *entity.ToogoCoupon) { c.TenantId = 9 }, 99, true},
+		{"尚未生效", func(c *entity.ToogoCoupon) { c.StartTime = now.Add(time.Hour) }, 99, true},
+		{"已过期", func(c *entity.ToogoCoupon) { c.ExpireTime = now.Add(-time.Hour) }, 99, true},
+		{"已领完", func(c *entity.ToogoCoupon) { c.TotalLimit, c.UsedCount = 5, 5 }, 99, true},
+		{"适用套餐", func(c *entity.ToogoCoupon) { c.PlanIds = "1, 2" }, 99, false},
+		{"不适用套餐", func(c *entity.ToogoCoupon) { c.PlanIds = "1,3" }, 99, true},
+		{"不适用周期", func(c *entity.ToogoCoupon) { c.PeriodTypes = "yearly" }, 99, true},
+		{"自己的推广码", func(c *entity.ToogoCoupon) { c.AgentId = 7 }, 99, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			coupon := valid()
+			tc.modify(coupon)
+			err := checkCoupon(coupon, 7, plan, "monthly", tc.amount, now)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("checkCoupon() err = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
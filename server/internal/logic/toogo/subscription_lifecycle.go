@@ -0,0 +1,954 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 订阅生命周期：升级/降级折算、自动续费、到期提醒、宽限期与状态审计
+package toogo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/toogoin"
+	"hotgo/internal/service"
+	"hotgo/internal/websocket"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/grand"
+)
+
+// subscriptionLifecycleConfig 订阅生命周期配置（toogo.subscription）
+type subscriptionLifecycleConfig struct {
+	GraceHours           int  // 到期后宽限期(小时)，0=到期立即停止机器人
+	AutoRenewBeforeHours int  // 到期前多少小时尝试自动续费
+	AutoRenewUsePoints   bool // 自动续费是否使用积分抵扣
+}
+
+func getSubscriptionLifecycleConfig(ctx context.Context) *subscriptionLifecycleConfig {
+	cfg := &subscriptionLifecycleConfig{
+		GraceHours:           g.Cfg().MustGet(ctx, "toogo.subscription.graceHours", 72).Int(),
+		AutoRenewBeforeHours: g.Cfg().MustGet(ctx, "toogo.subscription.autoRenewBeforeHours", 24).Int(),
+		AutoRenewUsePoints:   g.Cfg().MustGet(ctx, "toogo.subscription.autoRenewUsePoints").Bool(),
+	}
+	if cfg.GraceHours < 0 {
+		cfg.GraceHours = 0
+	}
+	if cfg.AutoRenewBeforeHours < 1 {
+		cfg.AutoRenewBeforeHours = 1
+	}
+	return cfg
+}
+
+// planChangeQuote 升降级试算结果
+type planChangeQuote struct {
+	plan          *entity.ToogoPlan
+	replaced      []*entity.ToogoSubscription
+	autoRenew     int
+	pointsDeduct  float64
+	balanceDeduct float64
+	expireTime    *gtime.Time
+	model         *toogoin.ChangePlanModel
+}
+
+// PlanChangeQuote 升级/降级套餐试算
+func (s *sToogoSubscription) PlanChangeQuote(ctx context.Context, in *toogoin.ChangePlanInp) (*toogoin.ChangePlanModel, error) {
+	quote, err := s.quotePlanChange(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return quote.model, nil
+}
+
+// ChangePlan 升级/降级套餐
+// 规则：
+// - 当前生效中订阅（含已排队的后续订阅）按剩余时间折算剩余价值，抵扣新套餐价格，新套餐立即生效
+// - 折抵超过新套餐价格时（降级），超出部分按新套餐日价折算为额外时长，不退现
+// - 降级时运行中机器人数量不能超过新套餐上限
+// - 原订阅状态变为“已变更”，佣金只按本次实际支付的余额结算
+func (s *sToogoSubscription) ChangePlan(ctx context.Context, in *toogoin.ChangePlanInp) (res *toogoin.ChangePlanModel, err error) {
+	quote, err := s.quotePlanChange(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	res = quote.model
+	res.OrderSn = fmt.Sprintf("SUB%s%s", gtime.Now().Format("YmdHis"), grand.S(6))
+
+	toogoUser, err := service.ToogoUser().GetOrCreate(ctx, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		now := gtime.Now()
+		subscription := &entity.ToogoSubscription{
+			UserId:       in.UserId,
+			PlanId:       quote.plan.Id,
+			PlanCode:     quote.plan.PlanCode,
+			OrderSn:      res.OrderSn,
+			PeriodType:   in.PeriodType,
+			Amount:       res.Amount,
+			StartTime:    now,
+			ExpireTime:   quote.expireTime,
+			Days:         res.Days,
+			Status:       consts.SubscriptionStatusPending,
+			AutoRenew:    quote.autoRenew,
+			CreditAmount: res.CreditAmount,
+			InviterId:    toogoUser.InviterId,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		subscriptionId, err := dao.ToogoSubscription.Ctx(ctx).Data(subscription).InsertAndGetId()
+		if err != nil {
+			return gerror.Wrap(err, "创建订阅记录失败")
+		}
+		subscription.Id = subscriptionId
+
+		// 原订阅标记为已变更（乐观锁：状态仍为生效中）
+		cols := dao.ToogoSubscription.Columns()
+		for _, old := range quote.replaced {
+			result, err := dao.ToogoSubscription.Ctx(ctx).
+				Where(cols.Id, old.Id).
+				Where(cols.Status, consts.SubscriptionStatusActive).
+				Data(g.Map{
+					cols.Status:     consts.SubscriptionStatusReplaced,
+					cols.ReplacedBy: subscriptionId,
+					cols.AutoRenew:  0,
+					cols.UpdatedAt:  now,
+				}).
+				Update()
+			if err != nil {
+				return gerror.Wrap(err, "更新原订阅状态失败")
+			}
+			if affected, _ := result.RowsAffected(); affected == 0 {
+				return gerror.New("订阅状态已变化，请刷新后重试")
+			}
+			recordSubscriptionLog(ctx, old, "replaced", consts.SubscriptionStatusActive, consts.SubscriptionStatusReplaced, 0,
+				fmt.Sprintf("变更为%s套餐，新订单%s", quote.plan.PlanName, res.OrderSn))
+		}
+
+		remark := fmt.Sprintf("升级至%s套餐", quote.plan.PlanName)
+		if res.Direction == "downgrade" {
+			remark = fmt.Sprintf("降级至%s套餐", quote.plan.PlanName)
+		}
+		if err = s.paySubscription(ctx, subscription, quote.plan, quote.pointsDeduct, quote.balanceDeduct, "balance", remark); err != nil {
+			return err
+		}
+		recordSubscriptionLog(ctx, subscription, res.Direction, consts.SubscriptionStatusPending, consts.SubscriptionStatusActive, res.Amount,
+			fmt.Sprintf("%s(%s)，原订阅折抵%.2f，积分抵扣%.2f，余额支付%.2f，额外时长%d小时",
+				remark, in.PeriodType, res.CreditAmount, res.PointsDeduct, res.BalancePaid, res.BonusHours))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// quotePlanChange 计算升降级折抵与应付金额
+func (s *sToogoSubscription) quotePlanChange(ctx context.Context, in *toogoin.ChangePlanInp) (*planChangeQuote, error) {
+	plan, err := s.getOnSalePlan(ctx, in.UserId, in.PlanId)
+	if err != nil {
+		return nil, err
+	}
+	amount, days, err := planPeriodPrice(plan, in.PeriodType)
+	if err != nil {
+		return nil, err
+	}
+	if amount <= 0 {
+		return nil, gerror.New("目标套餐该周期未定价，无法变更")
+	}
+
+	cols := dao.ToogoSubscription.Columns()
+	var actives []*entity.ToogoSubscription
+	err = dao.ToogoSubscription.Ctx(ctx).
+		Where(cols.UserId, in.UserId).
+		Where(cols.Status, consts.SubscriptionStatusActive).
+		WhereGT(cols.ExpireTime, gtime.Now()).
+		OrderAsc(cols.StartTime).
+		Scan(&actives)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询生效中订阅失败")
+	}
+	if len(actives) == 0 {
+		return nil, gerror.New("当前没有生效中的订阅，请直接订阅套餐")
+	}
+	current := actives[0]
+	if current.PlanId == plan.Id && current.PeriodType == in.PeriodType {
+		return nil, gerror.New("目标套餐与当前套餐相同，续费请直接订阅")
+	}
+
+	// 剩余价值：进行中的订阅按剩余时间比例折算，排队中的后续订阅全额折算
+	now := gtime.Now()
+	credit := 0.0
+	autoRenew := 0
+	for _, sub := range actives {
+		credit += subscriptionRemainingValue(sub, now)
+		if sub.AutoRenew == 1 {
+			autoRenew = 1
+		}
+	}
+	credit = math.Floor(credit*100) / 100
+	if in.AutoRenew {
+		autoRenew = 1
+	}
+
+	// 方向按日价判断
+	direction := "upgrade"
+	if current.Days > 0 && amount/float64(days) < current.Amount/float64(current.Days) {
+		direction = "downgrade"
+	}
+
+	toogoUser, err := service.ToogoUser().GetOrCreate(ctx, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if plan.RobotLimit < toogoUser.ActiveRobotCount {
+		return nil, gerror.Newf("运行中机器人(%d)超过目标套餐上限(%d)，请先停止部分机器人", toogoUser.ActiveRobotCount, plan.RobotLimit)
+	}
+	if err = s.checkPurchaseLimit(ctx, in.UserId, plan); err != nil {
+		return nil, err
+	}
+
+	payable, bonus := planChangePayable(amount, days, credit)
+
+	wallet, err := service.ToogoWallet().GetOrCreate(ctx, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+	pointsDeduct, balanceDeduct := splitSubscriptionPayment(wallet, payable, in.UsePoints)
+	if balanceDeduct > 0 && wallet.Balance < balanceDeduct {
+		return nil, gerror.Newf("余额不足，需支付 %.2f USDT（已折抵 %.2f，已抵扣积分 %.2f）", balanceDeduct, credit, pointsDeduct)
+	}
+
+	expireTime := now.Add(time.Duration(days)*24*time.Hour + bonus)
+	return &planChangeQuote{
+		plan:          plan,
+		replaced:      actives,
+		autoRenew:     autoRenew,
+		pointsDeduct:  pointsDeduct,
+		balanceDeduct: balanceDeduct,
+		expireTime:    expireTime,
+		model: &toogoin.ChangePlanModel{
+			Direction:    direction,
+			PlanName:     plan.PlanName,
+			Amount:       amount,
+			CreditAmount: credit,
+			PointsDeduct: pointsDeduct,
+			BalancePaid:  balanceDeduct,
+			Days:         days,
+			BonusHours:   int(bonus / time.Hour),
+			ExpireTime:   expireTime.String(),
+		},
+	}, nil
+}
+
+// planChangePayable 升降级应付金额：新套餐价格扣除折抵，折抵超出部分按新套餐日价折算为额外时长（按小时取整）
+func planChangePayable(amount float64, days int, credit float64) (payable float64, bonus time.Duration) {
+	payable = amount - credit
+	if payable < 0 {
+		bonus = time.Duration(-payable / amount * float64(days) * float64(24*time.Hour)).Truncate(time.Hour)
+		payable = 0
+	}
+	return math.Round(payable*100) / 100, bonus
+}
+
+// subscriptionRemainingValue 订阅剩余价值（按时间比例）
+func subscriptionRemainingValue(sub *entity.ToogoSubscription, now *gtime.Time) float64 {
+	if sub.StartTime == nil || sub.ExpireTime == nil || !sub.ExpireTime.After(now) {
+		return 0
+	}
+	if !sub.StartTime.Before(now) {
+		return sub.Amount
+	}
+	total := sub.ExpireTime.Sub(sub.StartTime)
+	if total <= 0 {
+		return 0
+	}
+	return sub.Amount * float64(sub.ExpireTime.Sub(now)) / float64(total)
+}
+
+// SetAutoRenew 开启/关闭自动续费（作用于所有生效中的订阅）
+func (s *sToogoSubscription) SetAutoRenew(ctx context.Context, in *toogoin.SubscriptionAutoRenewInp) error {
+	cols := dao.ToogoSubscription.Columns()
+	var subs []*entity.ToogoSubscription
+	err := dao.ToogoSubscription.Ctx(ctx).
+		Where(cols.UserId, in.UserId).
+		WhereIn(cols.Status, []int{consts.SubscriptionStatusActive, consts.SubscriptionStatusGrace}).
+		Scan(&subs)
+	if err != nil {
+		return gerror.Wrap(err, "查询订阅失败")
+	}
+	if len(subs) == 0 {
+		return gerror.New("当前没有生效中的订阅")
+	}
+
+	event := "auto_renew_off"
+	if in.AutoRenew == 1 {
+		event = "auto_renew_on"
+	}
+	for _, sub := range subs {
+		if sub.AutoRenew == in.AutoRenew {
+			continue
+		}
+		_, err = dao.ToogoSubscription.Ctx(ctx).Where(cols.Id, sub.Id).Data(g.Map{
+			cols.AutoRenew: in.AutoRenew,
+			cols.UpdatedAt: gtime.Now(),
+		}).Update()
+		if err != nil {
+			return gerror.Wrap(err, "更新自动续费失败")
+		}
+		recordSubscriptionLog(ctx, sub, event, sub.Status, sub.Status, 0, "")
+	}
+	return nil
+}
+
+// RefundSubscription 订阅退款（管理员）
+// 退回该订阅实际扣除的余额与积分（升降级折抵部分不退），订阅取消并冲正来源订单的代理佣金；
+// 用户没有其他生效中的订阅时停止机器人并重置为免费套餐
+func (s *sToogoSubscription) RefundSubscription(ctx context.Context, in *toogoin.SubscriptionRefundInp) (res *toogoin.SubscriptionRefundModel, err error) {
+	var sub *entity.ToogoSubscription
+	if err = dao.ToogoSubscription.Ctx(ctx).Where(dao.ToogoSubscription.Columns().Id, in.Id).Scan(&sub); err != nil {
+		return nil, gerror.Wrap(err, "查询订阅失败")
+	}
+	if sub == nil {
+		return nil, gerror.New("订阅不存在")
+	}
+	if sub.Status != consts.SubscriptionStatusActive && sub.Status != consts.SubscriptionStatusGrace {
+		return nil, gerror.New("仅生效中或宽限期的订阅可以退款")
+	}
+
+	res = &toogoin.SubscriptionRefundModel{}
+	if res.RefundBalance, err = subscriptionPaidAmount(ctx, sub.Id, "balance"); err != nil {
+		return nil, err
+	}
+	if res.RefundPoints, err = subscriptionPaidAmount(ctx, sub.Id, "gift_power"); err != nil {
+		return nil, err
+	}
+
+	var hasPlan bool
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		remark := fmt.Sprintf("订阅退款：余额%.4f USDT，积分%.4f，原因：%s", res.RefundBalance, res.RefundPoints, in.Reason)
+		if !s.transitSubscription(ctx, sub, consts.SubscriptionStatusCancelled, nil, "refund", remark) {
+			return gerror.New("订阅状态已变化，请刷新后重试")
+		}
+
+		refunds := []struct {
+			accountType string
+			amount      float64
+		}{{"balance", res.RefundBalance}, {"gift_power", res.RefundPoints}}
+		for _, refund := range refunds {
+			if refund.amount <= 0 {
+				continue
+			}
+			err := service.ToogoWallet().ChangeBalance(ctx, &toogoin.ChangeBalanceInp{
+				UserId:      sub.UserId,
+				AccountType: refund.accountType,
+				ChangeType:  "subscribe_refund",
+				Amount:      refund.amount,
+				RelatedId:   sub.Id,
+				RelatedType: "subscription",
+				OrderSn:     sub.OrderSn,
+				Remark:      "订阅退款",
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if res.ReversedCommission, err = service.ToogoCommission().ReverseOrderCommission(ctx, sub.OrderSn, "订阅退款："+in.Reason, 1); err != nil {
+			return err
+		}
+		hasPlan, err = s.syncUserPlan(ctx, sub.UserId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasPlan {
+		// 请求结束后上下文会被取消，停止机器人需要脱离请求上下文继续执行
+		go s.stopExpiredUser(context.WithoutCancel(ctx), sub.UserId)
+	}
+	notifySubscription(ctx, sub.UserId, "订阅已退款",
+		fmt.Sprintf("您的%s套餐订阅（订单%s）已退款，退回余额%.2f USDT、积分%.2f。", s.planName(ctx, sub.PlanId), sub.OrderSn, res.RefundBalance, res.RefundPoints))
+	return res, nil
+}
+
+// RefundOrder 支付订单退款联动（在退款事务内调用）
+// 按退款比例冲正来源订单的代理佣金；订单对应订阅时，全额退款取消订阅，部分退款按比例从到期时间倒扣天数，
+// 倒扣后已到期的同样取消。事务提交后由调用方执行 AfterRefundOrder
+func (s *sToogoSubscription) RefundOrder(ctx context.Context, in *toogoin.OrderRefundInp) (res *toogoin.OrderRefundModel, err error) {
+	if in.Ratio <= 0 {
+		return nil, gerror.New("退款比例无效")
+	}
+	ratio := math.Min(in.Ratio, 1)
+
+	res = &toogoin.OrderRefundModel{}
+	if res.ReversedCommission, err = service.ToogoCommission().ReverseOrderCommission(ctx, in.OrderSn, in.Reason, ratio); err != nil {
+		return nil, err
+	}
+
+	var sub *entity.ToogoSubscription
+	cols := dao.ToogoSubscription.Columns()
+	err = dao.ToogoSubscription.Ctx(ctx).
+		Where(cols.OrderSn, in.OrderSn).
+		WhereIn(cols.Status, []int{consts.SubscriptionStatusPending, consts.SubscriptionStatusActive, consts.SubscriptionStatusGrace}).
+		LockUpdate().
+		Scan(&sub)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询订单订阅失败")
+	}
+	if sub == nil {
+		return res, nil
+	}
+	res.SubscriptionId = sub.Id
+	res.UserId = sub.UserId
+
+	expireTime := sub.ExpireTime
+	if ratio < 1 && expireTime != nil {
+		expireTime = expireTime.Add(-time.Duration(float64(sub.Days) * ratio * float64(24*time.Hour)))
+	}
+	if ratio >= 1 || expireTime == nil || !expireTime.After(gtime.Now()) || sub.Status != consts.SubscriptionStatusActive {
+		remark := fmt.Sprintf("订单退款%.2f%%，订阅取消，原因：%s", ratio*100, in.Reason)
+		if !s.transitSubscription(ctx, sub, consts.SubscriptionStatusCancelled, nil, "refund", remark) {
+			return nil, gerror.New("订阅状态已变化，请刷新后重试")
+		}
+		res.Cancelled = true
+	} else {
+		_, err = dao.ToogoSubscription.Ctx(ctx).
+			Where(cols.Id, sub.Id).
+			Data(g.Map{
+				cols.ExpireTime: expireTime,
+				cols.UpdatedAt:  gtime.Now(),
+			}).
+			Update()
+		if err != nil {
+			return nil, gerror.Wrap(err, "更新订阅到期时间失败")
+		}
+		recordSubscriptionLog(ctx, sub, "refund", sub.Status, sub.Status, 0,
+			fmt.Sprintf("订单部分退款%.2f%%，到期时间%s调整为%s，原因：%s", ratio*100, sub.ExpireTime, expireTime, in.Reason))
+		res.ExpireTime = expireTime.String()
+	}
+
+	if res.HasPlan, err = s.syncUserPlan(ctx, sub.UserId); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// AfterRefundOrder 支付订单退款事务提交后：通知用户，已无订阅时停止其运行中的机器人
+func (s *sToogoSubscription) AfterRefundOrder(ctx context.Context, res *toogoin.OrderRefundModel) {
+	if res == nil || res.SubscriptionId == 0 {
+		return
+	}
+	if !res.HasPlan {
+		go s.stopExpiredUser(context.WithoutCancel(ctx), res.UserId)
+	}
+	content := fmt.Sprintf("您的订阅订单已部分退款，到期时间调整为%s。", res.ExpireTime)
+	if res.Cancelled {
+		content = "您的订阅订单已退款，订阅已取消。"
+	}
+	notifySubscription(ctx, res.UserId, "订阅已退款", content)
+}
+
+// subscriptionPaidAmount 订阅实际扣除的金额（按账户流水汇总）
+func subscriptionPaidAmount(ctx context.Context, subscriptionId int64, accountType string) (float64, error) {
+	cols := dao.ToogoWalletLog.Columns()
+	sum, err := dao.ToogoWalletLog.Ctx(ctx).
+		Where(cols.RelatedType, "subscription").
+		Where(cols.RelatedId, subscriptionId).
+		Where(cols.AccountType, accountType).
+		Sum(cols.ChangeAmount)
+	if err != nil {
+		return 0, gerror.Wrap(err, "查询订阅支付流水失败")
+	}
+	if sum >= 0 {
+		return 0, nil
+	}
+	return -sum, nil
+}
+
+// syncUserPlan 按剩余的生效中/宽限期订阅更新用户套餐信息，返回是否仍有订阅
+func (s *sToogoSubscription) syncUserPlan(ctx context.Context, userId int64) (bool, error) {
+	last, err := s.lastSubscription(ctx, userId)
+	if err != nil {
+		return false, gerror.Wrap(err, "查询用户订阅失败")
+	}
+	if last == nil {
+		return false, nil
+	}
+
+	var plan *entity.ToogoPlan
+	if err = dao.ToogoPlan.Ctx(ctx).Where(dao.ToogoPlan.Columns().Id, last.PlanId).Scan(&plan); err != nil || plan == nil {
+		return false, gerror.New("套餐不存在")
+	}
+	robotLimit := plan.RobotLimit
+	if last.PeriodType == consts.SubscriptionPeriodTrial && plan.TrialRobotLimit > 0 {
+		robotLimit = plan.TrialRobotLimit
+	}
+	_, err = dao.ToogoUser.Ctx(ctx).
+		Where(dao.ToogoUser.Columns().MemberId, userId).
+		Data(g.Map{
+			dao.ToogoUser.Columns().CurrentPlanId:   last.PlanId,
+			dao.ToogoUser.Columns().PlanExpireTime:  last.ExpireTime,
+			dao.ToogoUser.Columns().PlanGraceExpire: last.GraceExpireTime,
+			dao.ToogoUser.Columns().RobotLimit:      robotLimit,
+		}).
+		Update()
+	if err != nil {
+		return false, gerror.Wrap(err, "更新用户套餐信息失败")
+	}
+	return true, nil
+}
+
+// SubscriptionLogList 订阅变更记录列表
+func (s *sToogoSubscription) SubscriptionLogList(ctx context.Context, in *toogoin.SubscriptionLogListInp) (list []*toogoin.SubscriptionLogListModel, totalCount int, err error) {
+	cols := dao.ToogoSubscriptionLog.Columns()
+	mod := dao.ToogoSubscriptionLog.Ctx(ctx)
+	if in.UserId > 0 {
+		mod = mod.Where(cols.UserId, in.UserId)
+	}
+	if in.SubscriptionId > 0 {
+		mod = mod.Where(cols.SubscriptionId, in.SubscriptionId)
+	}
+	if in.Event != "" {
+		mod = mod.Where(cols.Event, in.Event)
+	}
+
+	var logs []*entity.ToogoSubscriptionLog
+	err = mod.OrderDesc(cols.Id).Page(in.Page, in.PerPage).ScanAndCount(&logs, &totalCount, true)
+	if err != nil {
+		return nil, 0, gerror.Wrap(err, "获取订阅变更记录失败")
+	}
+	for _, log := range logs {
+		list = append(list, &toogoin.SubscriptionLogListModel{ToogoSubscriptionLog: log})
+	}
+	return
+}
+
+// CheckExpired 检查并处理过期订阅 (定时任务调用)
+// 依次处理：到期提醒(T-3/T-1天) → 自动续费 → 到期进入宽限期 → 宽限期结束停止机器人
+func (s *sToogoSubscription) CheckExpired(ctx context.Context) error {
+	cfg := getSubscriptionLifecycleConfig(ctx)
+	if err := s.remindExpiring(ctx, cfg); err != nil {
+		return err
+	}
+	if err := s.autoRenewExpiring(ctx, cfg); err != nil {
+		return err
+	}
+	if err := s.expireSubscriptions(ctx, cfg); err != nil {
+		return err
+	}
+	return s.endGracePeriods(ctx)
+}
+
+// remindExpiring 到期提醒：T-3天、T-1天各提醒一次；已有后续订阅的不提醒
+func (s *sToogoSubscription) remindExpiring(ctx context.Context, cfg *subscriptionLifecycleConfig) error {
+	cols := dao.ToogoSubscription.Columns()
+	now := gtime.Now()
+	var subs []*entity.ToogoSubscription
+	err := dao.ToogoSubscription.Ctx(ctx).
+		Where(cols.Status, consts.SubscriptionStatusActive).
+		WhereGT(cols.ExpireTime, now).
+		WhereLTE(cols.ExpireTime, now.Add(3*24*time.Hour)).
+		WhereLT(cols.RemindStage, 2).
+		Scan(&subs)
+	if err != nil {
+		return gerror.Wrap(err, "查询即将到期订阅失败")
+	}
+
+	for _, sub := range subs {
+		if s.hasLaterSubscription(ctx, sub) {
+			continue
+		}
+		stage := 1
+		if sub.ExpireTime.Sub(now) <= 24*time.Hour {
+			stage = 2
+		}
+		if stage <= sub.RemindStage {
+			continue
+		}
+		result, err := dao.ToogoSubscription.Ctx(ctx).
+			Where(cols.Id, sub.Id).
+			WhereLT(cols.RemindStage, stage).
+			Data(g.Map{cols.RemindStage: stage}).
+			Update()
+		if err != nil {
+			g.Log().Warningf(ctx, "[Subscription] 更新提醒状态失败 subscriptionId=%d: %v", sub.Id, err)
+			continue
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			continue
+		}
+
+		planName := s.planName(ctx, sub.PlanId)
+		content := fmt.Sprintf("您的%s套餐将于 %s 到期", planName, sub.ExpireTime.Format("Y-m-d H:i"))
+		switch {
+		case sub.AutoRenew == 1:
+			amount, _, _ := s.renewPrice(ctx, sub)
+			content += fmt.Sprintf("，已开启自动续费，将从余额扣除 %.2f USDT，请确保余额充足。", amount)
+		case cfg.GraceHours > 0:
+			content += fmt.Sprintf("，请及时续费。到期后有%d小时宽限期，宽限期内机器人只管理已有持仓，不再开新仓。", cfg.GraceHours)
+		default:
+			content += "，请及时续费。到期后运行中的机器人将自动平仓并停止。"
+		}
+		notifySubscription(ctx, sub.UserId, "订阅即将到期", content)
+		recordSubscriptionLog(ctx, sub, "remind", sub.Status, sub.Status, 0, content)
+	}
+	return nil
+}
+
+// autoRenewExpiring 自动续费：到期前 autoRenewBeforeHours 小时起从余额续费同套餐同周期，宽限期内继续重试
+func (s *sToogoSubscription) autoRenewExpiring(ctx context.Context, cfg *subscriptionLifecycleConfig) error {
+	cols := dao.ToogoSubscription.Columns()
+	var subs []*entity.ToogoSubscription
+	err := dao.ToogoSubscription.Ctx(ctx).
+		WhereIn(cols.Status, []int{consts.SubscriptionStatusActive, consts.SubscriptionStatusGrace}).
+		Where(cols.AutoRenew, 1).
+		WhereLTE(cols.ExpireTime, gtime.Now().Add(time.Duration(cfg.AutoRenewBeforeHours)*time.Hour)).
+		Scan(&subs)
+	if err != nil {
+		return gerror.Wrap(err, "查询待续费订阅失败")
+	}
+
+	for _, sub := range subs {
+		if s.hasLaterSubscription(ctx, sub) {
+			continue
+		}
+		renewed, err := s.Subscribe(ctx, &toogoin.SubscribeInp{
+			UserId:     sub.UserId,
+			PlanId:     sub.PlanId,
+			PeriodType: sub.PeriodType,
+			PayType:    "balance",
+			UsePoints:  cfg.AutoRenewUsePoints,
+			AutoRenew:  true,
+		})
+		if err != nil {
+			g.Log().Infof(ctx, "[Subscription] 自动续费失败 userId=%d, subscriptionId=%d: %v", sub.UserId, sub.Id, err)
+			// 同一订阅24小时内只通知一次
+			count, _ := dao.ToogoSubscriptionLog.Ctx(ctx).
+				Where(dao.ToogoSubscriptionLog.Columns().SubscriptionId, sub.Id).
+				Where(dao.ToogoSubscriptionLog.Columns().Event, "renew_failed").
+				WhereGT(dao.ToogoSubscriptionLog.Columns().CreatedAt, gtime.Now().Add(-24*time.Hour)).
+				Count()
+			if count == 0 {
+				recordSubscriptionLog(ctx, sub, "renew_failed", sub.Status, sub.Status, 0, err.Error())
+				notifySubscription(ctx, sub.UserId, "自动续费失败",
+					fmt.Sprintf("您的%s套餐自动续费失败：%s。请充值后手动续费，或等待系统下次重试。", s.planName(ctx, sub.PlanId), err.Error()))
+			}
+			continue
+		}
+
+		// 自动续费只从最后一笔订阅发起，原订阅关闭自动续费避免重复
+		_, _ = dao.ToogoSubscription.Ctx(ctx).Where(cols.Id, sub.Id).Data(g.Map{cols.AutoRenew: 0}).Update()
+		recordSubscriptionLog(ctx, sub, "auto_renew", sub.Status, sub.Status, renewed.Amount,
+			fmt.Sprintf("自动续费成功，新订单%s，到期时间%s", renewed.OrderSn, renewed.ExpireTime))
+		notifySubscription(ctx, sub.UserId, "自动续费成功",
+			fmt.Sprintf("您的%s套餐已自动续费，余额支付 %.2f USDT，新的到期时间 %s。", renewed.PlanName, renewed.BalancePaid, renewed.ExpireTime))
+	}
+	return nil
+}
+
+// expireSubscriptions 处理到期订阅：有后续订阅直接过期；否则进入宽限期（未配置宽限期则立即停止机器人）
+func (s *sToogoSubscription) expireSubscriptions(ctx context.Context, cfg *subscriptionLifecycleConfig) error {
+	cols := dao.ToogoSubscription.Columns()
+	now := gtime.Now()
+	// 查找已过期但状态还是生效中的订阅
+	var subscriptions []*entity.ToogoSubscription
+	err := dao.ToogoSubscription.Ctx(ctx).
+		Where(cols.Status, consts.SubscriptionStatusActive).
+		WhereLT(cols.ExpireTime, now).
+		Scan(&subscriptions)
+	if err != nil {
+		return gerror.Wrap(err, "查询过期订阅失败")
+	}
+
+	for _, sub := range subscriptions {
+		// 检查用户是否还有其他有效订阅
+		count, err := dao.ToogoSubscription.Ctx(ctx).
+			Where(cols.UserId, sub.UserId).
+			Where(cols.Status, consts.SubscriptionStatusActive).
+			WhereGT(cols.ExpireTime, now).
+			Count()
+		if err != nil {
+			g.Log().Warningf(ctx, "检查用户订阅失败: %v", err)
+			continue
+		}
+		if count > 0 {
+			s.transitSubscription(ctx, sub, consts.SubscriptionStatusExpired, nil, "expired", "已由后续订阅接续")
+			continue
+		}
+
+		graceExpire := sub.ExpireTime.Add(time.Duration(cfg.GraceHours) * time.Hour)
+		if cfg.GraceHours > 0 && graceExpire.After(now) {
+			if !s.transitSubscription(ctx, sub, consts.SubscriptionStatusGrace, graceExpire, "grace",
+				fmt.Sprintf("进入宽限期，截止%s", graceExpire.String())) {
+				continue
+			}
+			_, err = dao.ToogoUser.Ctx(ctx).
+				Where(dao.ToogoUser.Columns().MemberId, sub.UserId).
+				Data(g.Map{dao.ToogoUser.Columns().PlanGraceExpire: graceExpire}).
+				Update()
+			if err != nil {
+				g.Log().Warningf(ctx, "更新用户宽限期失败: %v", err)
+			}
+			notifySubscription(ctx, sub.UserId, "订阅已到期",
+				fmt.Sprintf("您的%s套餐已到期，已进入宽限期（截止 %s）。宽限期内机器人只管理已有持仓，不再开新仓；宽限期结束后将自动平仓并停止机器人，请尽快续费。",
+					s.planName(ctx, sub.PlanId), graceExpire.Format("Y-m-d H:i")))
+			continue
+		}
+
+		if !s.transitSubscription(ctx, sub, consts.SubscriptionStatusExpired, nil, "expired", "订阅到期") {
+			continue
+		}
+		s.stopExpiredUser(ctx, sub.UserId)
+		notifySubscription(ctx, sub.UserId, "订阅已到期",
+			fmt.Sprintf("您的%s套餐已到期，运行中的机器人已平仓并停止。续费后可重新启动。", s.planName(ctx, sub.PlanId)))
+	}
+
+	g.Log().Infof(ctx, "处理过期订阅完成，共处理 %d 条", len(subscriptions))
+	return nil
+}
+
+// endGracePeriods 宽限期结束：订阅过期、停止机器人并重置为免费套餐
+func (s *sToogoSubscription) endGracePeriods(ctx context.Context) error {
+	cols := dao.ToogoSubscription.Columns()
+	var subs []*entity.ToogoSubscription
+	err := dao.ToogoSubscription.Ctx(ctx).
+		Where(cols.Status, consts.SubscriptionStatusGrace).
+		WhereLT(cols.GraceExpireTime, gtime.Now()).
+		Scan(&subs)
+	if err != nil {
+		return gerror.Wrap(err, "查询宽限期订阅失败")
+	}
+
+	for _, sub := range subs {
+		if !s.transitSubscription(ctx, sub, consts.SubscriptionStatusExpired, nil, "expired", "宽限期结束") {
+			continue
+		}
+		count, err := dao.ToogoSubscription.Ctx(ctx).
+			Where(cols.UserId, sub.UserId).
+			Where(cols.Status, consts.SubscriptionStatusActive).
+			WhereGT(cols.ExpireTime, gtime.Now()).
+			Count()
+		if err != nil || count > 0 {
+			continue
+		}
+		s.stopExpiredUser(ctx, sub.UserId)
+		notifySubscription(ctx, sub.UserId, "宽限期已结束",
+			fmt.Sprintf("您的%s套餐宽限期已结束，运行中的机器人已平仓并停止。续费后可重新启动。", s.planName(ctx, sub.PlanId)))
+	}
+	return nil
+}
+
+// transitSubscription 变更订阅状态并记录审计（乐观锁：状态未被并发修改）
+func (s *sToogoSubscription) transitSubscription(ctx context.Context, sub *entity.ToogoSubscription, toStatus int, graceExpire *gtime.Time, event, remark string) bool {
+	cols := dao.ToogoSubscription.Columns()
+	data := g.Map{cols.Status: toStatus, cols.UpdatedAt: gtime.Now()}
+	if graceExpire != nil {
+		data[cols.GraceExpireTime] = graceExpire
+	}
+	result, err := dao.ToogoSubscription.Ctx(ctx).
+		Where(cols.Id, sub.Id).
+		Where(cols.Status, sub.Status).
+		Data(data).
+		Update()
+	if err != nil {
+		g.Log().Warningf(ctx, "更新订阅状态失败: %v", err)
+		return false
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false
+	}
+	recordSubscriptionLog(ctx, sub, event, sub.Status, toStatus, 0, remark)
+	sub.Status = toStatus
+	return true
+}
+
+// closeGrace 宽限期内续费：原宽限期订阅结束
+func (s *sToogoSubscription) closeGrace(ctx context.Context, userId, renewedBy int64) error {
+	cols := dao.ToogoSubscription.Columns()
+	var subs []*entity.ToogoSubscription
+	err := dao.ToogoSubscription.Ctx(ctx).
+		Where(cols.UserId, userId).
+		Where(cols.Status, consts.SubscriptionStatusGrace).
+		Scan(&subs)
+	if err != nil {
+		return gerror.Wrap(err, "查询宽限期订阅失败")
+	}
+	for _, sub := range subs {
+		s.transitSubscription(ctx, sub, consts.SubscriptionStatusExpired, nil, "expired",
+			fmt.Sprintf("宽限期内续费，由订阅%d接续", renewedBy))
+	}
+	return nil
+}
+
+// stopExpiredUser 订阅彻底到期：停止用户的所有运行中机器人（会自动平仓所有订单）并重置为免费套餐
+func (s *sToogoSubscription) stopExpiredUser(ctx context.Context, userId int64) {
+	var runningRobots []*entity.TradingRobot
+	err := dao.TradingRobot.Ctx(ctx).
+		Where(dao.TradingRobot.Columns().UserId, userId).
+		Where(dao.TradingRobot.Columns().Status, 2). // 运行中
+		WhereNull(dao.TradingRobot.Columns().DeletedAt).
+		Scan(&runningRobots)
+	if err != nil {
+		g.Log().Warningf(ctx, "查询用户运行中机器人失败: %v", err)
+	} else {
+		// 停止所有运行中的机器人
+		for _, robot := range runningRobots {
+			// 使用系统上下文（不需要用户登录）
+			systemCtx := context.Background()
+			// 调用机器人任务管理器平仓并停止
+			if err := GetRobotTaskManager().CloseAllAndWait(systemCtx, robot.Id, "subscription_expired", 30*time.Second); err != nil {
+				g.Log().Warningf(ctx, "停止机器人失败 (robotId=%d): %v", robot.Id, err)
+				continue
+			}
+			// 更新机器人状态为未启动
+			_, err = dao.TradingRobot.Ctx(ctx).
+				Where(dao.TradingRobot.Columns().Id, robot.Id).
+				Data(g.Map{
+					dao.TradingRobot.Columns().Status:   1, // 未启动
+					dao.TradingRobot.Columns().StopTime: gtime.Now(),
+				}).
+				Update()
+			if err != nil {
+				g.Log().Warningf(ctx, "更新机器人状态失败 (robotId=%d): %v", robot.Id, err)
+			} else {
+				g.Log().Infof(ctx, "订阅到期自动停止机器人: userId=%d, robotId=%d, robotName=%s", userId, robot.Id, robot.RobotName)
+			}
+		}
+	}
+
+	// 获取用户所在租户的免费套餐
+	var freePlan *entity.ToogoPlan
+	dao.ToogoPlan.Ctx(ctx).
+		Where(dao.ToogoPlan.Columns().TenantId, service.ToogoTenant().MemberTenantId(ctx, userId)).
+		Where(dao.ToogoPlan.Columns().IsDefault, 1).
+		Scan(&freePlan)
+
+	robotLimit := 1
+	if freePlan != nil {
+		robotLimit = freePlan.RobotLimit
+	}
+
+	// 重置用户套餐为免费套餐
+	_, err = dao.ToogoUser.Ctx(ctx).
+		Where(dao.ToogoUser.Columns().MemberId, userId).
+		Data(g.Map{
+			dao.ToogoUser.Columns().CurrentPlanId:   0,
+			dao.ToogoUser.Columns().PlanExpireTime:  nil,
+			dao.ToogoUser.Columns().PlanGraceExpire: nil,
+			dao.ToogoUser.Columns().RobotLimit:      robotLimit,
+		}).
+		Update()
+	if err != nil {
+		g.Log().Warningf(ctx, "重置用户套餐失败: %v", err)
+	}
+}
+
+// lastSubscription 用户最后一笔生效中/宽限期订阅
+func (s *sToogoSubscription) lastSubscription(ctx context.Context, userId int64) (sub *entity.ToogoSubscription, err error) {
+	cols := dao.ToogoSubscription.Columns()
+	err = dao.ToogoSubscription.Ctx(ctx).
+		Where(cols.UserId, userId).
+		WhereIn(cols.Status, []int{consts.SubscriptionStatusActive, consts.SubscriptionStatusGrace}).
+		OrderDesc(cols.ExpireTime).
+		Limit(1).
+		Scan(&sub)
+	return
+}
+
+// hasLaterSubscription 是否已有接续的后续订阅（已续费）
+func (s *sToogoSubscription) hasLaterSubscription(ctx context.Context, sub *entity.ToogoSubscription) bool {
+	cols := dao.ToogoSubscription.Columns()
+	count, err := dao.ToogoSubscription.Ctx(ctx).
+		Where(cols.UserId, sub.UserId).
+		Where(cols.Status, consts.SubscriptionStatusActive).
+		WhereNot(cols.Id, sub.Id).
+		WhereGT(cols.ExpireTime, sub.ExpireTime).
+		Count()
+	return err != nil || count > 0
+}
+
+// renewPrice 续费同套餐同周期的价格
+func (s *sToogoSubscription) renewPrice(ctx context.Context, sub *entity.ToogoSubscription) (amount float64, days int, err error) {
+	var plan *entity.ToogoPlan
+	if err = dao.ToogoPlan.Ctx(ctx).Where(dao.ToogoPlan.Columns().Id, sub.PlanId).Scan(&plan); err != nil || plan == nil {
+		return 0, 0, gerror.New("套餐不存在")
+	}
+	return planPeriodPrice(plan, sub.PeriodType)
+}
+
+func (s *sToogoSubscription) planName(ctx context.Context, planId int64) string {
+	name, _ := dao.ToogoPlan.Ctx(ctx).Where(dao.ToogoPlan.Columns().Id, planId).Value(dao.ToogoPlan.Columns().PlanName)
+	if name.IsEmpty() {
+		return ""
+	}
+	return name.String()
+}
+
+// recordSubscriptionLog 记录订阅状态变更
+func recordSubscriptionLog(ctx context.Context, sub *entity.ToogoSubscription, event string, fromStatus, toStatus int, amount float64, remark string) {
+	_, err := dao.ToogoSubscriptionLog.Ctx(ctx).Data(&entity.ToogoSubscriptionLog{
+		SubscriptionId: sub.Id,
+		UserId:         sub.UserId,
+		PlanId:         sub.PlanId,
+		Event:          event,
+		FromStatus:     fromStatus,
+		ToStatus:       toStatus,
+		Amount:         amount,
+		Remark:         remark,
+		CreatedAt:      gtime.Now(),
+	}).Insert()
+	if err != nil {
+		g.Log().Warningf(ctx, "[Subscription] 记录订阅变更失败 subscriptionId=%d, event=%s: %v", sub.Id, event, err)
+	}
+}
+
+// notifySubscription 订阅消息写入消息中心（财务消息）并实时推送
+func notifySubscription(ctx context.Context, userId int64, title, content string) {
+	notifyUser(ctx, userId, consts.NoticeTypeFinance, title, content)
+}
+
+// notifyUser 定向消息写入消息中心并实时推送
+func notifyUser(ctx context.Context, userId int64, noticeType int64, title, content string) {
+	notice := &entity.AdminNotice{
+		Title:     title,
+		Type:      noticeType,
+		Content:   content,
+		Receiver:  gjson.New([]int64{userId}),
+		Status:    consts.StatusEnabled,
+		CreatedAt: gtime.Now(),
+	}
+	if _, err := dao.AdminNotice.Ctx(ctx).Data(notice).OmitEmptyData().Insert(); err != nil {
+		g.Log().Warningf(ctx, "[Notice] 写入用户通知失败 userId=%d, title=%s: %v", userId, title, err)
+		return
+	}
+	websocket.SendToUser(userId, &websocket.WResponse{Event: "notice", Data: notice})
+}
+
+// SubscriptionOpenBlocked 订阅宽限期内禁止开新仓（已有持仓照常管理）
+func SubscriptionOpenBlocked(ctx context.Context, userId int64) bool {
+	var user struct {
+		PlanExpireTime  *gtime.Time `orm:"plan_expire_time"`
+		PlanGraceExpire *gtime.Time `orm:"plan_grace_expire"`
+	}
+	err := dao.ToogoUser.Ctx(ctx).
+		Fields(dao.ToogoUser.Columns().PlanExpireTime, dao.ToogoUser.Columns().PlanGraceExpire).
+		Where(dao.ToogoUser.Columns().MemberId, userId).
+		Scan(&user)
+	if err != nil || user.PlanGraceExpire == nil {
+		return false
+	}
+	return user.PlanExpireTime == nil || !user.PlanExpireTime.After(gtime.Now())
+}
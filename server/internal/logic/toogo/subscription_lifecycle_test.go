@@ -0,0 +1,67 @@
+// Package toogo
+// @Description 订阅升降级折算测试
+package toogo
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"hotgo/internal/model/entity"
+
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+func TestSubscriptionRemainingValue(t *testing.T) {
+	now := gtime.Now()
+	day := 24 * time.Hour
+	testCases := []struct {
+		name     string
+		sub      *entity.ToogoSubscription
+		expected float64
+	}{
+		{"进行中按剩余时间比例", &entity.ToogoSubscription{Amount: 30, StartTime: now.Add(-10 * day), ExpireTime: now.Add(20 * day)}, 20},
+		{"刚开始接近全额", &entity.ToogoSubscription{Amount: 30, StartTime: now, ExpireTime: now.Add(30 * day)}, 30},
+		{"排队中的后续订阅全额", &entity.ToogoSubscription{Amount: 90, StartTime: now.Add(5 * day), ExpireTime: now.Add(95 * day)}, 90},
+		{"已到期无剩余价值", &entity.ToogoSubscription{Amount: 30, StartTime: now.Add(-30 * day), ExpireTime: now}, 0},
+		{"缺少起止时间", &entity.ToogoSubscription{Amount: 30}, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := subscriptionRemainingValue(tc.sub, now); math.Abs(got-tc.expected) > 1e-6 {
+				t.Errorf("subscriptionRemainingValue() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPlanChangePayable(t *testing.T) {
+	testCases := []struct {
+		name            string
+		amount          float64
+		days            int
+		credit          float64
+		expectedPayable float64
+		expectedBonus   time.Duration
+	}{
+		{"升级补差价", 100, 30, 20.333, 79.67, 0},
+		{"无折抵全额支付", 100, 30, 0, 100, 0},
+		{"折抵恰好抵扣", 100, 30, 100, 0, 0},
+		{"降级折抵超出折算为额外时长", 30, 30, 45, 0, 15 * 24 * time.Hour},
+		{"额外时长按小时取整", 24, 1, 24.5, 0, 0},
+		{"额外时长不足一天", 24, 1, 36, 0, 12 * time.Hour},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payable, bonus := planChangePayable(tc.amount, tc.days, tc.credit)
+			if math.Abs(payable-tc.expectedPayable) > 1e-9 {
+				t.Errorf("planChangePayable() payable = %v, want %v", payable, tc.expectedPayable)
+			}
+			if bonus != tc.expectedBonus {
+				t.Errorf("planChangePayable() bonus = %v, want %v", bonus, tc.expectedBonus)
+			}
+		})
+	}
+}
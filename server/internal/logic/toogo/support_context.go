@@ -0,0 +1,172 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 客服工作台：用户交易上下文与运维操作
+package toogo
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/toogoin"
+)
+
+const (
+	supportFailureLimit = 20                 // 上下文面板展示的失败记录条数
+	supportFailureSince = 7 * 24 * time.Hour // 失败记录回溯时长
+)
+
+// SupportContext 客服工作台展示的用户上下文
+func (s *sToogoRobot) SupportContext(ctx context.Context, userId int64) (res *toogoin.SupportContextModel, err error) {
+	if userId <= 0 {
+		return nil, gerror.New("用户ID不正确")
+	}
+	res = &toogoin.SupportContextModel{UserId: userId}
+
+	if err = dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, userId).Scan(&res.User); err != nil {
+		return nil, err
+	}
+	if err = dao.ToogoWallet.Ctx(ctx).Where(dao.ToogoWallet.Columns().UserId, userId).Scan(&res.Wallet); err != nil {
+		return nil, err
+	}
+	if err = dao.ToogoSubscription.Ctx(ctx).
+		Where(dao.ToogoSubscription.Columns().UserId, userId).
+		WhereIn(dao.ToogoSubscription.Columns().Status, []int{consts.SubscriptionStatusActive, consts.SubscriptionStatusGrace}).
+		OrderDesc(dao.ToogoSubscription.Columns().ExpireTime).
+		Limit(1).
+		Scan(&res.Subscription); err != nil {
+		return nil, err
+	}
+
+	if err = dao.TradingApiConfig.Ctx(ctx).
+		Fields(toogoin.SupportApiConfigModel{}).
+		Where(dao.TradingApiConfig.Columns().UserId, userId).
+		OrderDesc(dao.TradingApiConfig.Columns().IsDefault).
+		OrderDesc(dao.TradingApiConfig.Columns().Id).
+		Scan(&res.ApiConfigs); err != nil {
+		return nil, err
+	}
+
+	var robots []*entity.TradingRobot
+	if err = dao.TradingRobot.Ctx(ctx).
+		Where(dao.TradingRobot.Columns().UserId, userId).
+		OrderDesc(dao.TradingRobot.Columns().Id).
+		Scan(&robots); err != nil {
+		return nil, err
+	}
+	// 运行中的机器人排在前面
+	sort.SliceStable(robots, func(i, j int) bool {
+		return robots[i].Status == 2 && robots[j].Status != 2
+	})
+
+	robotIds := make([]int64, 0, len(robots))
+	res.Robots = make([]*toogoin.SupportRobotModel, 0, len(robots))
+	for _, robot := range robots {
+		robotIds = append(robotIds, robot.Id)
+		item := &toogoin.SupportRobotModel{
+			Id:          robot.Id,
+			RobotName:   robot.RobotName,
+			Exchange:    robot.Exchange,
+			Symbol:      robot.Symbol,
+			Status:      robot.Status,
+			ApiConfigId: robot.ApiConfigId,
+			StartTime:   robot.StartTime,
+			TotalProfit: robot.TotalProfit,
+		}
+		if status := GetRobotTaskManager().GetEngineStatus(robot.Id); status != nil {
+			item.Engine = &toogoin.SupportEngineStatusModel{
+				Running:        status.Running,
+				Connected:      status.Connected,
+				LastPrice:      status.LastPrice,
+				TotalBalance:   status.TotalBalance,
+				AvailBalance:   status.AvailBalance,
+				MarketState:    status.MarketState,
+				RiskPreference: status.RiskPreference,
+				HasPosition:    status.HasPosition,
+				PositionSide:   status.PositionSide,
+				PositionAmt:    status.PositionAmt,
+				UnrealizedPnl:  status.UnrealizedPnl,
+				SignalReason:   status.SignalReason,
+			}
+		}
+		res.Robots = append(res.Robots, item)
+	}
+
+	res.Failures, res.FailureStats, err = s.supportFailures(ctx, robotIds)
+	return
+}
+
+// supportFailures 最近的执行失败记录，按 analyzeFailureReason 重新归类（兼容未加 failure_category 列的旧库）
+func (s *sToogoRobot) supportFailures(ctx context.Context, robotIds []int64) (list []*toogoin.SupportFailureModel, stats []*toogoin.SupportFailureStatModel, err error) {
+	list = make([]*toogoin.SupportFailureModel, 0)
+	stats = make([]*toogoin.SupportFailureStatModel, 0)
+	if len(robotIds) == 0 {
+		return
+	}
+
+	var logs []*entity.TradingExecutionLog
+	cols := dao.TradingExecutionLog.Columns()
+	if err = dao.TradingExecutionLog.Ctx(ctx).
+		WhereIn(cols.RobotId, robotIds).
+		Where(cols.Status, "failed").
+		WhereGTE(cols.CreatedAt, time.Now().Add(-supportFailureSince)).
+		OrderDesc(cols.Id).
+		Limit(supportFailureLimit).
+		Scan(&logs); err != nil {
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, log := range logs {
+		category, reason := analyzeFailureReason(log.EventType, log.Message, gjson.New(log.EventData).Map())
+		if category == "" {
+			// 非下单失败事件（如同步/平仓失败）没有细分规则，统一归为系统类
+			category, reason = "system", log.Message
+		}
+		if _, ok := counts[category]; !ok {
+			stats = append(stats, &toogoin.SupportFailureStatModel{Category: category})
+		}
+		counts[category]++
+		list = append(list, &toogoin.SupportFailureModel{
+			Id:        log.Id,
+			RobotId:   log.RobotId,
+			OrderId:   log.OrderId,
+			EventType: log.EventType,
+			Message:   log.Message,
+			Category:  category,
+			Reason:    reason,
+			CreatedAt: log.CreatedAt,
+		})
+	}
+	for _, stat := range stats {
+		stat.Count = counts[stat.Category]
+	}
+	return
+}
+
+// RestartRobotEngine 重建运行中机器人的引擎
+func (s *sToogoRobot) RestartRobotEngine(ctx context.Context, robotId int64) error {
+	if err := GetRobotTaskManager().RestartEngine(ctx, robotId); err != nil {
+		return err
+	}
+	g.Log().Infof(ctx, "[ToogoRobot] 机器人引擎已重建: robotId=%d", robotId)
+	return nil
+}
+
+// TriggerOrderSync 触发机器人订单/持仓重新同步（异步执行）
+func (s *sToogoRobot) TriggerOrderSync(ctx context.Context, robotId int64) error {
+	syncService := GetOrderStatusSyncService()
+	if !syncService.IsRunning() {
+		return gerror.New("订单同步服务未运行")
+	}
+	syncService.TriggerRobotSync(robotId)
+	return nil
+}
@@ -0,0 +1,144 @@
+// Package toogo 交易链路指标与追踪
+package toogo
+
+import (
+	"context"
+	"time"
+
+	"hotgo/internal/library/metrics"
+
+	"github.com/gogf/gf/v2/net/gtrace"
+	"github.com/gogf/gf/v2/os/gcache"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// latencyBuckets 交易链路耗时分桶（秒）：行情到信号为毫秒级，信号到下单含写库与交易所往返
+var latencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+var (
+	tickToSignal = metrics.NewHistogram("toogo_tick_to_signal_seconds",
+		"Latency from price tick to dispatched open signal.", latencyBuckets, "platform")
+	signalToOrder = metrics.NewHistogram("toogo_signal_to_order_seconds",
+		"Latency from dispatched open signal to order accepted by the exchange.", latencyBuckets, "platform")
+	ordersTotal = metrics.NewCounter("toogo_orders_total",
+		"Auto trade order attempts by result and failure category.", "platform", "result", "category")
+	walletMutations = metrics.NewCounter("toogo_wallet_mutations_total",
+		"Wallet balance changes by account type, change type and result.", "account_type", "change_type", "result")
+)
+
+func init() {
+	metrics.NewGaugeFunc("toogo_robot_engines", "Running robot engines.", []string{"platform"}, func(emit func(float64, ...string)) {
+		counts := make(map[string]int)
+		for _, e := range GetRobotTaskManager().GetAllEngines() {
+			counts[e.Platform]++
+		}
+		for platform, n := range counts {
+			emit(float64(n), platform)
+		}
+	})
+	metrics.NewGaugeFunc("toogo_queue_depth", "Pending items in trading pipeline queues.", []string{"queue"}, func(emit func(float64, ...string)) {
+		m := GetRobotTaskManager()
+		m.mu.RLock()
+		emit(float64(len(m.autoTradeTriggerCh)), "auto_trade_trigger")
+		m.mu.RUnlock()
+
+		windowSignals := 0
+		for _, e := range m.GetAllEngines() {
+			windowSignals += len(e.windowSignalCh)
+		}
+		emit(float64(windowSignals), "window_signal")
+
+		if s := orderStatusSyncService; s != nil {
+			emit(float64(len(s.triggerCh)), "order_sync_trigger")
+		}
+	})
+}
+
+// signalTrace 信号从评估到下单的链路信息（预警写库后按 logId 交给订单链路）
+type signalTrace struct {
+	SpanContext trace.SpanContext
+	SignalAt    time.Time
+}
+
+// signalTraces logId → *signalTrace；订单链路未消费时按TTL过期
+var signalTraces = gcache.New()
+
+const signalTraceTTL = 10 * time.Minute
+
+type signalTraceCtxKey struct{}
+
+// rememberSignalTrace 预警写库后登记链路信息
+func rememberSignalTrace(logId int64, st *signalTrace) {
+	if logId <= 0 || st == nil {
+		return
+	}
+	_ = signalTraces.Set(context.Background(), logId, st, signalTraceTTL)
+}
+
+// resumeSignalTrace 订单链路按 logId 接续信号评估的追踪，并把链路信息放入上下文
+func resumeSignalTrace(ctx context.Context, logId int64) context.Context {
+	if logId <= 0 {
+		return ctx
+	}
+	v, err := signalTraces.Remove(ctx, logId)
+	if err != nil || v == nil || v.IsNil() {
+		return ctx
+	}
+	st, ok := v.Val().(*signalTrace)
+	if !ok {
+		return ctx
+	}
+	if st.SpanContext.IsValid() && !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, st.SpanContext)
+	}
+	return context.WithValue(ctx, signalTraceCtxKey{}, st)
+}
+
+// observeOrderResult 统计下单结果；成功时记录信号到下单耗时
+func observeOrderResult(ctx context.Context, platform, eventType, category string) {
+	switch eventType {
+	case "order_success":
+		ordersTotal.Inc(platform, "success", "")
+		if st, ok := ctx.Value(signalTraceCtxKey{}).(*signalTrace); ok && !st.SignalAt.IsZero() {
+			signalToOrder.Observe(time.Since(st.SignalAt).Seconds(), platform)
+		}
+	case "order_failed":
+		ordersTotal.Inc(platform, "failed", category)
+	}
+}
+
+// observeWalletMutation 统计钱包变更
+func observeWalletMutation(accountType, changeType string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failed"
+	}
+	walletMutations.Inc(accountType, changeType, result)
+}
+
+// startTickSpan 行情触发的信号评估作为新的追踪根（引擎启动上下文可能带着早已结束的请求追踪）
+func startTickSpan(ctx context.Context, robotId int64, platform, symbol string) (context.Context, *gtrace.Span) {
+	ctx, span := gtrace.NewSpan(ctx, "toogo.OnPriceUpdate", trace.WithNewRoot())
+	span.SetAttributes(robotSpanAttrs(robotId, platform, symbol)...)
+	return ctx, span
+}
+
+// endSpan 结束追踪并记录错误
+func endSpan(span *gtrace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// robotSpanAttrs 机器人通用追踪属性
+func robotSpanAttrs(robotId int64, platform, symbol string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int64("robot.id", robotId),
+		attribute.String("exchange.platform", platform),
+		attribute.String("robot.symbol", symbol),
+	}
+}
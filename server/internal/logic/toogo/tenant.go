@@ -0,0 +1,552 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 白标租户：租户账号、品牌配置、独立收款凭证与平台目录复制
+package toogo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/contexts"
+	"hotgo/internal/library/payment"
+	"hotgo/internal/library/vault"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/toogoin"
+	"hotgo/internal/service"
+)
+
+type sToogoTenant struct{}
+
+func NewToogoTenant() *sToogoTenant {
+	return &sToogoTenant{}
+}
+
+func init() {
+	service.RegisterToogoTenant(NewToogoTenant())
+}
+
+// List 租户列表（超管跨租户视图，含各租户用户、机器人、订阅与充值统计）
+func (s *sToogoTenant) List(ctx context.Context, in *toogoin.TenantListInp) (list []*toogoin.TenantListModel, totalCount int, err error) {
+	if !contexts.IsCompanyDept(ctx) {
+		return nil, 0, gerror.New("仅平台管理员可管理租户")
+	}
+
+	cols := dao.ToogoTenant.Columns()
+	mod := dao.ToogoTenant.Ctx(ctx)
+	if in.Name != "" {
+		mod = mod.WhereLike(cols.Name, "%"+in.Name+"%")
+	}
+	if in.Domain != "" {
+		mod = mod.WhereLike(cols.Domain, "%"+in.Domain+"%")
+	}
+	if in.Status > 0 {
+		mod = mod.Where(cols.Status, in.Status)
+	}
+
+	var tenants []*entity.ToogoTenant
+	if err = mod.OrderDesc(cols.Id).Page(in.Page, in.PerPage).ScanAndCount(&tenants, &totalCount, true); err != nil {
+		return nil, 0, gerror.Wrap(err, "获取租户列表失败")
+	}
+
+	list = make([]*toogoin.TenantListModel, 0, len(tenants))
+	for _, tenant := range tenants {
+		item := &toogoin.TenantListModel{TenantModel: toTenantModel(tenant)}
+		if err = s.fillStats(ctx, item); err != nil {
+			return nil, 0, err
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+// fillStats 租户统计
+func (s *sToogoTenant) fillStats(ctx context.Context, item *toogoin.TenantListModel) (err error) {
+	tenantId := item.MemberId
+	members := dao.ToogoUser.Ctx(ctx).Fields(dao.ToogoUser.Columns().MemberId).Where(dao.ToogoUser.Columns().TenantId, tenantId)
+
+	username, err := dao.AdminMember.Ctx(ctx).Where(dao.AdminMember.Columns().Id, tenantId).Value(dao.AdminMember.Columns().Username)
+	if err != nil {
+		return gerror.Wrap(err, "获取租户账号失败")
+	}
+	item.Username = username.String()
+
+	if item.UserCount, err = dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().TenantId, tenantId).Count(); err != nil {
+		return gerror.Wrap(err, "统计租户用户失败")
+	}
+	if item.RunningRobotCount, err = dao.TradingRobot.Ctx(ctx).
+		Where(dao.TradingRobot.Columns().UserId+" IN(?)", members).
+		Where(dao.TradingRobot.Columns().Status, 2).
+		Count(); err != nil {
+		return gerror.Wrap(err, "统计租户机器人失败")
+	}
+	if item.ActiveSubCount, err = dao.ToogoSubscription.Ctx(ctx).
+		Where(dao.ToogoSubscription.Columns().UserId+" IN(?)", members).
+		Where(dao.ToogoSubscription.Columns().Status, consts.SubscriptionStatusActive).
+		Count(); err != nil {
+		return gerror.Wrap(err, "统计租户订阅失败")
+	}
+	if item.PlanCount, err = dao.ToogoPlan.Ctx(ctx).Where(dao.ToogoPlan.Columns().TenantId, tenantId).Count(); err != nil {
+		return gerror.Wrap(err, "统计租户套餐失败")
+	}
+	if item.DepositAmount, err = dao.ToogoDeposit.Ctx(ctx).
+		Where(dao.ToogoDeposit.Columns().UserId+" IN(?)", members).
+		Where(dao.ToogoDeposit.Columns().Status, 2).
+		Sum(dao.ToogoDeposit.Columns().Amount); err != nil {
+		return gerror.Wrap(err, "统计租户充值失败")
+	}
+	return
+}
+
+// View 租户详情
+func (s *sToogoTenant) View(ctx context.Context, in *toogoin.TenantViewInp) (*toogoin.TenantModel, error) {
+	if !contexts.IsCompanyDept(ctx) {
+		return nil, gerror.New("仅平台管理员可管理租户")
+	}
+
+	var tenant *entity.ToogoTenant
+	if err := dao.ToogoTenant.Ctx(ctx).Where(dao.ToogoTenant.Columns().Id, in.Id).Scan(&tenant); err != nil {
+		return nil, gerror.Wrap(err, "获取租户信息失败")
+	}
+	if tenant == nil {
+		return nil, gerror.New("租户不存在")
+	}
+	return toTenantModel(tenant), nil
+}
+
+// Edit 新增/编辑租户
+func (s *sToogoTenant) Edit(ctx context.Context, in *toogoin.TenantEditInp) (err error) {
+	if !contexts.IsCompanyDept(ctx) {
+		return gerror.New("仅平台管理员可管理租户")
+	}
+
+	deptType, err := s.memberDeptType(ctx, in.MemberId)
+	if err != nil {
+		return err
+	}
+	if deptType != consts.DeptTypeTenant {
+		return gerror.New("租户账号必须属于租户类型的部门")
+	}
+
+	cols := dao.ToogoTenant.Columns()
+	count, err := dao.ToogoTenant.Ctx(ctx).Where(cols.MemberId, in.MemberId).WhereNot(cols.Id, in.Id).Count()
+	if err != nil {
+		return gerror.Wrap(err, "检查租户账号失败")
+	}
+	if count > 0 {
+		return gerror.New("该账号已开通租户")
+	}
+
+	domain := strings.ToLower(strings.TrimSpace(in.Domain))
+	if domain != "" {
+		count, err = dao.ToogoTenant.Ctx(ctx).Where(cols.Domain, domain).WhereNot(cols.Id, in.Id).Count()
+		if err != nil {
+			return gerror.Wrap(err, "检查绑定域名失败")
+		}
+		if count > 0 {
+			return gerror.New("该域名已被其他租户绑定")
+		}
+	}
+
+	var tenant *entity.ToogoTenant
+	if in.Id > 0 {
+		if err = dao.ToogoTenant.Ctx(ctx).Where(cols.Id, in.Id).Scan(&tenant); err != nil {
+			return gerror.Wrap(err, "获取租户信息失败")
+		}
+		if tenant == nil {
+			return gerror.New("租户不存在")
+		}
+	}
+
+	data, err := brandingData(ctx, &in.TenantBrandingEditInp, tenant)
+	if err != nil {
+		return err
+	}
+	data[cols.MemberId] = in.MemberId
+	data[cols.Name] = in.Name
+	data[cols.Domain] = domain
+	data[cols.Remark] = in.Remark
+	data[cols.Status] = in.Status
+	data[cols.UpdatedAt] = gtime.Now()
+
+	if in.Id > 0 {
+		_, err = dao.ToogoTenant.Ctx(ctx).Where(cols.Id, in.Id).Data(data).Update()
+	} else {
+		data[cols.CreatedAt] = gtime.Now()
+		_, err = dao.ToogoTenant.Ctx(ctx).Data(data).Insert()
+	}
+	if err != nil {
+		err = gerror.Wrap(err, "保存租户失败")
+	}
+	return
+}
+
+// Status 更新租户状态，停用后租户域名不再接受注册
+func (s *sToogoTenant) Status(ctx context.Context, in *toogoin.TenantStatusInp) (err error) {
+	if !contexts.IsCompanyDept(ctx) {
+		return gerror.New("仅平台管理员可管理租户")
+	}
+
+	_, err = dao.ToogoTenant.Ctx(ctx).Where(dao.ToogoTenant.Columns().Id, in.Id).Data(g.Map{
+		dao.ToogoTenant.Columns().Status:    in.Status,
+		dao.ToogoTenant.Columns().UpdatedAt: gtime.Now(),
+	}).Update()
+	if err != nil {
+		err = gerror.Wrap(err, "更新租户状态失败")
+	}
+	return
+}
+
+// Branding 当前租户的品牌配置
+func (s *sToogoTenant) Branding(ctx context.Context) (*toogoin.TenantModel, error) {
+	tenant, err := s.currentTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toTenantModel(tenant), nil
+}
+
+// BrandingEdit 修改当前租户的品牌配置与收款凭证
+func (s *sToogoTenant) BrandingEdit(ctx context.Context, in *toogoin.TenantBrandingEditInp) (err error) {
+	tenant, err := s.currentTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := brandingData(ctx, in, tenant)
+	if err != nil {
+		return err
+	}
+	data[dao.ToogoTenant.Columns().UpdatedAt] = gtime.Now()
+	_, err = dao.ToogoTenant.Ctx(ctx).Where(dao.ToogoTenant.Columns().Id, tenant.Id).Data(data).Update()
+	if err != nil {
+		err = gerror.Wrap(err, "保存品牌配置失败")
+	}
+	return
+}
+
+// currentTenant 当前登录的租户账号
+func (s *sToogoTenant) currentTenant(ctx context.Context) (tenant *entity.ToogoTenant, err error) {
+	if !contexts.IsTenantDept(ctx) {
+		return nil, gerror.New("仅租户账号可设置品牌")
+	}
+	if err = dao.ToogoTenant.Ctx(ctx).Where(dao.ToogoTenant.Columns().MemberId, contexts.GetTenantId(ctx)).Scan(&tenant); err != nil {
+		return nil, gerror.Wrap(err, "获取租户信息失败")
+	}
+	if tenant == nil {
+		return nil, gerror.New("租户未开通，请联系平台管理员")
+	}
+	return
+}
+
+// CopyCatalog 复制平台套餐/官方策略模板到租户，租户已有相同套餐代码/模板标识的跳过
+func (s *sToogoTenant) CopyCatalog(ctx context.Context, in *toogoin.TenantCopyCatalogInp) (res *toogoin.TenantCopyCatalogModel, err error) {
+	if !contexts.IsCompanyDept(ctx) {
+		return nil, gerror.New("仅平台管理员可管理租户")
+	}
+
+	count, err := dao.ToogoTenant.Ctx(ctx).Where(dao.ToogoTenant.Columns().MemberId, in.TenantId).Count()
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取租户信息失败")
+	}
+	if count == 0 {
+		return nil, gerror.New("租户不存在")
+	}
+
+	res = new(toogoin.TenantCopyCatalogModel)
+	if in.Plans {
+		if res.PlanCount, err = s.copyPlans(ctx, in.TenantId); err != nil {
+			return nil, err
+		}
+	}
+	if in.StrategyGroups {
+		if res.GroupCount, err = s.copyStrategyGroups(ctx, in.TenantId); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// copyPlans 复制平台套餐到租户
+func (s *sToogoTenant) copyPlans(ctx context.Context, tenantId int64) (count int, err error) {
+	cols := dao.ToogoPlan.Columns()
+
+	var plans []*entity.ToogoPlan
+	if err = dao.ToogoPlan.Ctx(ctx).Where(cols.TenantId, 0).OrderAsc(cols.Sort).Scan(&plans); err != nil {
+		return 0, gerror.Wrap(err, "获取平台套餐失败")
+	}
+
+	existing, err := dao.ToogoPlan.Ctx(ctx).Where(cols.TenantId, tenantId).Array(cols.PlanCode)
+	if err != nil {
+		return 0, gerror.Wrap(err, "获取租户套餐失败")
+	}
+	codes := make(map[string]struct{}, len(existing))
+	for _, v := range existing {
+		codes[v.String()] = struct{}{}
+	}
+
+	for _, plan := range plans {
+		if _, ok := codes[plan.PlanCode]; ok {
+			continue
+		}
+		plan.TenantId = tenantId
+		plan.CreatedAt = gtime.Now()
+		plan.UpdatedAt = gtime.Now()
+		if _, err = dao.ToogoPlan.Ctx(ctx).FieldsEx(cols.Id).Data(plan).Insert(); err != nil {
+			return count, gerror.Wrapf(err, "复制套餐 %s 失败", plan.PlanCode)
+		}
+		count++
+	}
+	return
+}
+
+// copyStrategyGroups 复制平台官方策略模板（含12套策略）到租户
+func (s *sToogoTenant) copyStrategyGroups(ctx context.Context, tenantId int64) (count int, err error) {
+	var groups []*entity.TradingStrategyGroup
+	if err = dao.TradingStrategyGroup.Ctx(ctx).
+		Where("tenant_id", 0).
+		Where("is_official", 1).
+		Order("sort ASC, id ASC").
+		Scan(&groups); err != nil {
+		return 0, gerror.Wrap(err, "获取官方策略模板失败")
+	}
+
+	groupService := NewStrategyGroupService()
+	for _, group := range groups {
+		newId, err := groupService.CloneToTenant(ctx, group, tenantId)
+		if err != nil {
+			return count, gerror.Wrapf(err, "复制策略模板 %s 失败", group.GroupKey)
+		}
+		if newId > 0 {
+			count++
+		}
+	}
+	return
+}
+
+// MemberTenantId 获取用户所属租户ID，租户账号本身即为租户，0为平台
+func (s *sToogoTenant) MemberTenantId(ctx context.Context, memberId int64) int64 {
+	if memberId <= 0 {
+		return 0
+	}
+
+	v, err := dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, memberId).Value(dao.ToogoUser.Columns().TenantId)
+	if err != nil {
+		g.Log().Warningf(ctx, "[ToogoTenant] 获取用户所属租户失败 memberId=%d: %v", memberId, err)
+		return 0
+	}
+	if tenantId := v.Int64(); tenantId > 0 {
+		return tenantId
+	}
+
+	if deptType, _ := s.memberDeptType(ctx, memberId); deptType == consts.DeptTypeTenant {
+		return memberId
+	}
+	return 0
+}
+
+// memberDeptType 后台账号所属部门类型
+func (s *sToogoTenant) memberDeptType(ctx context.Context, memberId int64) (string, error) {
+	deptId, err := dao.AdminMember.Ctx(ctx).Where(dao.AdminMember.Columns().Id, memberId).Value(dao.AdminMember.Columns().DeptId)
+	if err != nil {
+		return "", gerror.Wrap(err, "获取账号信息失败")
+	}
+	if deptId.Int64() <= 0 {
+		return "", gerror.New("账号不存在")
+	}
+
+	deptType, err := dao.AdminDept.Ctx(ctx).Where(dao.AdminDept.Columns().Id, deptId.Int64()).Value(dao.AdminDept.Columns().Type)
+	if err != nil {
+		return "", gerror.Wrap(err, "获取部门信息失败")
+	}
+	return deptType.String(), nil
+}
+
+// RequestTenant 按请求域名识别租户，未绑定返回nil
+func (s *sToogoTenant) RequestTenant(ctx context.Context) (tenant *entity.ToogoTenant, err error) {
+	r := g.RequestFromCtx(ctx)
+	if r == nil {
+		return nil, nil
+	}
+	host := strings.ToLower(r.GetHost())
+	if host == "" {
+		return nil, nil
+	}
+
+	if err = dao.ToogoTenant.Ctx(ctx).Where(dao.ToogoTenant.Columns().Domain, host).Scan(&tenant); err != nil {
+		return nil, gerror.Wrap(err, "获取站点租户失败")
+	}
+	return
+}
+
+// BindMember 绑定用户到租户
+func (s *sToogoTenant) BindMember(ctx context.Context, memberId, tenantId int64) (err error) {
+	if _, err = service.ToogoUser().GetOrCreate(ctx, memberId); err != nil {
+		return err
+	}
+
+	_, err = dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, memberId).Data(g.Map{
+		dao.ToogoUser.Columns().TenantId:  tenantId,
+		dao.ToogoUser.Columns().UpdatedAt: gtime.Now(),
+	}).Update()
+	if err != nil {
+		err = gerror.Wrap(err, "绑定用户租户失败")
+	}
+	return
+}
+
+// SiteBranding 当前访问站点的品牌配置，未绑定租户或租户停用时使用平台配置
+func (s *sToogoTenant) SiteBranding(ctx context.Context) (*toogoin.TenantBrandingModel, error) {
+	res := new(toogoin.TenantBrandingModel)
+	if basic, err := service.SysConfig().GetBasic(ctx); err == nil && basic != nil {
+		res.SiteName = basic.Name
+		res.Logo = basic.Logo
+	}
+
+	tenant, err := s.RequestTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil || tenant.Status != consts.StatusEnabled {
+		return res, nil
+	}
+
+	res.TenantId = tenant.MemberId
+	if tenant.SiteName != "" {
+		res.SiteName = tenant.SiteName
+	}
+	if tenant.Logo != "" {
+		res.Logo = tenant.Logo
+	}
+	res.Favicon = tenant.Favicon
+	res.PrimaryColor = tenant.PrimaryColor
+	res.SupportEmail = tenant.SupportEmail
+	return res, nil
+}
+
+// NOWPayments 获取租户的NOWPayments收款网关，平台使用 nowpayments 配置，未配置返回nil
+func (s *sToogoTenant) NOWPayments(ctx context.Context, tenantId int64) (*payment.NOWPayments, error) {
+	if tenantId == 0 {
+		apiKey := g.Cfg().MustGet(ctx, "nowpayments.apiKey").String()
+		if apiKey == "" {
+			return nil, nil
+		}
+		ipnSecret := g.Cfg().MustGet(ctx, "nowpayments.ipnSecret").String()
+		if ipnSecret == "" {
+			return nil, gerror.New("平台NOWPayments未设置IPN密钥")
+		}
+		return payment.NewNOWPayments(&payment.NOWPaymentsConfig{
+			ApiKey:    apiKey,
+			IpnSecret: ipnSecret,
+			IsSandbox: g.Cfg().MustGet(ctx, "nowpayments.isSandbox").Bool(),
+		}), nil
+	}
+
+	var tenant *entity.ToogoTenant
+	if err := dao.ToogoTenant.Ctx(ctx).Where(dao.ToogoTenant.Columns().MemberId, tenantId).Scan(&tenant); err != nil {
+		return nil, gerror.Wrap(err, "获取租户收款配置失败")
+	}
+	if tenant == nil || tenant.NowpaymentsApiKey == "" {
+		return nil, nil
+	}
+	creds, err := vault.TenantCredentials(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return payment.NewNOWPayments(&payment.NOWPaymentsConfig{
+		ApiKey:    creds.ApiKey,
+		IpnSecret: creds.SecretKey,
+		IsSandbox: tenant.NowpaymentsSandbox == 1,
+	}), nil
+}
+
+// RequirePlatform 平台统一管理的配置（VIP等级、波动率、全局代理）仅平台管理员可修改
+func RequirePlatform(ctx context.Context) error {
+	if contexts.GetUser(ctx) != nil && !contexts.IsCompanyDept(ctx) {
+		return gerror.New("该配置由平台统一管理")
+	}
+	return nil
+}
+
+// targetTenantId 新增/编辑租户目录数据（套餐、优惠券等）的归属租户
+// 租户账号固定为本租户；超管显式指定时使用指定值，未指定时 set=false，由调用方决定新增归属或保持原归属
+func targetTenantId(ctx context.Context, explicit *int64) (tenantId int64, set bool) {
+	if !contexts.IsCompanyDept(ctx) {
+		return contexts.GetTenantId(ctx), true
+	}
+	if explicit != nil {
+		return *explicit, true
+	}
+	return 0, false
+}
+
+// brandingData 品牌配置与收款凭证，收款密钥留空不修改
+// 收款凭证经保险箱加密保存；设置了API Key时必须同时设置IPN密钥，否则回调无法验签
+func brandingData(ctx context.Context, in *toogoin.TenantBrandingEditInp, tenant *entity.ToogoTenant) (g.Map, error) {
+	cols := dao.ToogoTenant.Columns()
+	data := g.Map{
+		cols.SiteName:           in.SiteName,
+		cols.Logo:               in.Logo,
+		cols.Favicon:            in.Favicon,
+		cols.PrimaryColor:       in.PrimaryColor,
+		cols.SupportEmail:       in.SupportEmail,
+		cols.NowpaymentsSandbox: in.NowpaymentsSandbox,
+	}
+
+	creds := new(vault.Credentials)
+	if tenant != nil {
+		var err error
+		if creds, err = vault.TenantCredentials(ctx, tenant); err != nil {
+			return nil, err
+		}
+	}
+	if in.NowpaymentsApiKey != "" {
+		creds.ApiKey = in.NowpaymentsApiKey
+	}
+	if in.NowpaymentsIpnSecret != "" {
+		creds.SecretKey = in.NowpaymentsIpnSecret
+	}
+	if creds.ApiKey != "" && creds.SecretKey == "" {
+		return nil, gerror.New("配置NOWPayments收款必须同时设置IPN密钥")
+	}
+	if in.NowpaymentsApiKey == "" && in.NowpaymentsIpnSecret == "" {
+		return data, nil
+	}
+
+	sealed, err := vault.Seal(ctx, creds)
+	if err != nil {
+		return nil, gerror.Wrap(err, "加密收款凭证失败")
+	}
+	data[cols.NowpaymentsApiKey] = sealed.ApiKey
+	data[cols.NowpaymentsIpnSecret] = sealed.SecretKey
+	data[cols.NowpaymentsDataKey] = sealed.DataKey
+	data[cols.NowpaymentsKeyVersion] = sealed.KeyVersion
+	return data, nil
+}
+
+// toTenantModel 租户信息，不返回收款密钥
+func toTenantModel(tenant *entity.ToogoTenant) *toogoin.TenantModel {
+	return &toogoin.TenantModel{
+		Id:                 tenant.Id,
+		MemberId:           tenant.MemberId,
+		Name:               tenant.Name,
+		Domain:             tenant.Domain,
+		SiteName:           tenant.SiteName,
+		Logo:               tenant.Logo,
+		Favicon:            tenant.Favicon,
+		PrimaryColor:       tenant.PrimaryColor,
+		SupportEmail:       tenant.SupportEmail,
+		NowpaymentsSandbox: tenant.NowpaymentsSandbox,
+		HasNowpayments:     tenant.NowpaymentsApiKey != "",
+		Remark:             tenant.Remark,
+		Status:             tenant.Status,
+		CreatedAt:          tenant.CreatedAt,
+		UpdatedAt:          tenant.UpdatedAt,
+	}
+}
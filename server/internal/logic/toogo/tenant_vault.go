@@ -0,0 +1,80 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 租户收款凭证迁移：主密钥轮换后重新包装数据密钥
+package toogo
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/vault"
+	"hotgo/internal/model/entity"
+)
+
+// TenantRewrapResult 租户收款凭证重新包装结果
+type TenantRewrapResult struct {
+	Total     int     // 已配置收款凭证的租户数
+	Rewrapped int     // 已迁移到当前版本
+	Skipped   int     // 已是当前版本
+	Conflicts int     // 迁移期间被修改，已跳过
+	Failed    []int64 // 解密失败的租户ID
+}
+
+// RewrapTenantCredentials 将所有租户的收款凭证迁移到当前主密钥版本
+func RewrapTenantCredentials(ctx context.Context) (res *TenantRewrapResult, err error) {
+	v, err := vault.Instance(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := dao.ToogoTenant.Columns()
+	var list []*entity.ToogoTenant
+	err = dao.ToogoTenant.Ctx(ctx).
+		Where("("+cols.NowpaymentsApiKey+" <> '' OR "+cols.NowpaymentsIpnSecret+" <> '')").
+		Scan(&list)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询租户收款配置失败")
+	}
+
+	res = &TenantRewrapResult{Total: len(list)}
+	for _, item := range list {
+		out, changed, err := v.Rewrap(ctx, vault.SealedFromTenant(item))
+		if err != nil {
+			g.Log().Warningf(ctx, "[Vault] 租户收款凭证重新包装失败: tenantId=%d, err=%v", item.MemberId, err)
+			res.Failed = append(res.Failed, item.MemberId)
+			continue
+		}
+		if !changed {
+			res.Skipped++
+			continue
+		}
+
+		// 以读取时的密文为条件更新，避免覆盖迁移期间租户提交的新凭证
+		result, err := dao.ToogoTenant.Ctx(ctx).
+			Where(cols.Id, item.Id).
+			Where(cols.NowpaymentsDataKey, item.NowpaymentsDataKey).
+			Where(cols.NowpaymentsApiKey, item.NowpaymentsApiKey).
+			Data(g.Map{
+				cols.NowpaymentsApiKey:     out.ApiKey,
+				cols.NowpaymentsIpnSecret:  out.SecretKey,
+				cols.NowpaymentsDataKey:    out.DataKey,
+				cols.NowpaymentsKeyVersion: out.KeyVersion,
+			}).
+			Update()
+		if err != nil {
+			g.Log().Warningf(ctx, "[Vault] 写入租户收款凭证失败: tenantId=%d, err=%v", item.MemberId, err)
+			res.Failed = append(res.Failed, item.MemberId)
+			continue
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			res.Conflicts++
+			continue
+		}
+		res.Rewrapped++
+	}
+	return res, nil
+}
@@ -142,6 +142,10 @@ func upsertTradeFillsFromTrades(ctx context.Context, apiConfigId int64, exchange
 				orderLinks[base.CloseOrderId] = append(orderLinks[base.CloseOrderId], &l)
 			}
 		}
+		// 分批加仓单：通过批次明细映射回主订单
+		if err := appendScaleEntryLinks(ctx, orderIDs, orderLinks); err != nil {
+			return 0, 0, gerror.Wrap(err, "query trading_order_entry for trade fill mapping failed")
+		}
 	}
 
 	// Gate 专用：订单级已实现盈亏分摊（高效/稳定）
@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"time"
 
+	"hotgo/internal/consts"
 	"hotgo/internal/dao"
+	"hotgo/internal/library/contexts"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/adminin"
 	"hotgo/internal/model/input/toogoin"
@@ -46,6 +48,7 @@ func (s *sToogoUser) GetOrCreate(ctx context.Context, memberId int64) (user *ent
 
 		user = &entity.ToogoUser{
 			MemberId:         memberId,
+			TenantId:         contexts.GetTenantId(ctx),
 			VipLevel:         1,
 			IsAgent:          0,
 			AgentLevel:       0,
@@ -64,6 +67,9 @@ func (s *sToogoUser) GetOrCreate(ctx context.Context, memberId int64) (user *ent
 		if err != nil {
 			return nil, gerror.Wrap(err, "创建用户信息失败")
 		}
+		if err = ensureSelfRelation(ctx, memberId); err != nil {
+			return nil, err
+		}
 
 		// 同时创建钱包
 		_, err = service.ToogoWallet().GetOrCreate(ctx, memberId)
@@ -148,6 +154,9 @@ func (s *sToogoUser) UserList(ctx context.Context, in *toogoin.UserListInp) (lis
 	if len(in.CreatedAt) == 2 {
 		mod = mod.WhereBetween(cols.CreatedAt, in.CreatedAt[0], in.CreatedAt[1])
 	}
+	if in.TenantId != nil {
+		mod = mod.Where(cols.TenantId, *in.TenantId)
+	}
 
 	var users []*entity.ToogoUser
 	err = mod.OrderDesc(cols.Id).Page(in.Page, in.PerPage).ScanAndCount(&users, &totalCount, true)
@@ -278,34 +287,33 @@ func (s *sToogoUser) RegisterWithInvite(ctx context.Context, in *toogoin.Registe
 
 	return g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
 		// 创建新用户的Toogo信息
-		newUser, err := s.GetOrCreate(ctx, in.MemberId)
-		if err != nil {
+		if _, err := s.GetOrCreate(ctx, in.MemberId); err != nil {
 			return err
 		}
 
-		// 设置邀请关系
-		_, err = dao.ToogoUser.Ctx(ctx).
-			Where(dao.ToogoUser.Columns().MemberId, in.MemberId).
-			Data(g.Map{
-				dao.ToogoUser.Columns().InviterId: inviter.MemberId,
-			}).
-			Update()
+		// 与调整邀请人相同顺序锁定新用户与邀请人，以锁定后的数据为准
+		newUser, lockedInviter, err := lockInviteMembers(ctx, in.MemberId, inviter.MemberId)
 		if err != nil {
-			return gerror.Wrap(err, "设置邀请关系失败")
+			return err
+		}
+		if newUser == nil {
+			return gerror.New("用户不存在")
+		}
+		if lockedInviter == nil || lockedInviter.Status != 1 {
+			return gerror.New("邀请人信息不存在或不可用")
+		}
+		if newUser.InviterId > 0 {
+			return gerror.New("该用户已绑定邀请人")
 		}
 
-		// 更新邀请人的邀请数量
-		_, err = dao.ToogoUser.Ctx(ctx).
-			Where(dao.ToogoUser.Columns().MemberId, inviter.MemberId).
-			Increment(dao.ToogoUser.Columns().InviteCount, 1)
-		if err != nil {
-			return gerror.Wrap(err, "更新邀请数量失败")
+		// 白标租户之间不允许跨租户邀请
+		if service.ToogoTenant().MemberTenantId(ctx, inviter.MemberId) != newUser.TenantId {
+			return gerror.New("邀请人与用户不属于同一站点")
 		}
 
-		// 更新邀请人的团队数量 (需要递归更新整个上级链)
-		err = s.UpdateTeamCount(ctx, inviter.MemberId, 1)
-		if err != nil {
-			g.Log().Warningf(ctx, "更新团队数量失败: %v", err)
+		// 设置邀请关系（同步维护闭包表、邀请人数与整条上级链的团队统计）
+		if err = bindInviter(ctx, newUser, inviter.MemberId); err != nil {
+			return err
 		}
 
 		// 发放邀请奖励
@@ -318,29 +326,6 @@ func (s *sToogoUser) RegisterWithInvite(ctx context.Context, in *toogoin.Registe
 	})
 }
 
-// UpdateTeamCount 更新团队人数 (递归更新上级链)
-func (s *sToogoUser) UpdateTeamCount(ctx context.Context, memberId int64, delta int) error {
-	var user *entity.ToogoUser
-	err := dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, memberId).Scan(&user)
-	if err != nil || user == nil {
-		return err
-	}
-
-	// 更新当前用户的团队数量
-	_, err = dao.ToogoUser.Ctx(ctx).
-		Where(dao.ToogoUser.Columns().MemberId, memberId).
-		Increment(dao.ToogoUser.Columns().TeamCount, delta)
-	if err != nil {
-		return err
-	}
-
-	// 如果有上级，递归更新
-	if user.InviterId > 0 {
-		return s.UpdateTeamCount(ctx, user.InviterId, delta)
-	}
-	return nil
-}
-
 // TeamList 团队列表
 func (s *sToogoUser) TeamList(ctx context.Context, in *toogoin.TeamListInp) (list []*toogoin.TeamListModel, totalCount int, err error) {
 	// 根据层级查询
@@ -437,149 +422,93 @@ func (s *sToogoUser) TeamList(ctx context.Context, in *toogoin.TeamListInp) (lis
 	return
 }
 
-// GetAllTeamMembersUnlimited 获取所有团队成员（无限级）
-// 使用 BFS 按 inviter_id 向下遍历，返回所有下级 member_id（不包含自己）
+// GetAllTeamMembersUnlimited 获取所有团队成员（无限级），返回所有下级 member_id（不包含自己）
 func (s *sToogoUser) GetAllTeamMembersUnlimited(ctx context.Context, memberId int64) ([]int64, error) {
-	visited := make(map[int64]struct{})
-	result := make([]int64, 0, 64)
-
-	current := []int64{memberId}
-	for len(current) > 0 {
-		var users []*entity.ToogoUser
-		err := dao.ToogoUser.Ctx(ctx).
-			WhereIn(dao.ToogoUser.Columns().InviterId, current).
-			Fields(dao.ToogoUser.Columns().MemberId).
-			Scan(&users)
-		if err != nil {
-			return nil, err
-		}
-
-		next := make([]int64, 0, len(users))
-		for _, u := range users {
-			if u == nil {
-				continue
-			}
-			id := u.MemberId
-			if id <= 0 || id == memberId {
-				continue
-			}
-			if _, ok := visited[id]; ok {
-				continue
-			}
-			visited[id] = struct{}{}
-			result = append(result, id)
-			next = append(next, id)
-		}
-		current = next
+	team, err := loadTeam(ctx, memberId, 0)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int64, 0, len(team))
+	for _, m := range team {
+		result = append(result, m.MemberId)
 	}
 	return result, nil
 }
 
-// GetDirectMembers 获取直推成员
-func (s *sToogoUser) GetDirectMembers(ctx context.Context, memberId int64) ([]int64, error) {
-	var users []*entity.ToogoUser
-	err := dao.ToogoUser.Ctx(ctx).
-		Where(dao.ToogoUser.Columns().InviterId, memberId).
-		Scan(&users)
+// teamIdBatch WhereIn/批量写入单批最大数量，避免大团队生成超长SQL
+const teamIdBatch = 1000
+
+// teamMember 团队成员及相对层级
+type teamMember struct {
+	MemberId int64
+	Depth    int // 1=直推
+}
+
+// loadTeam 从闭包表一次性加载团队成员（按层级排序），maxDepth<=0 表示无限级
+func loadTeam(ctx context.Context, rootId int64, maxDepth int) ([]*teamMember, error) {
+	cols := dao.ToogoUserRelation.Columns()
+	mod := dao.ToogoUserRelation.Ctx(ctx).
+		Where(cols.AncestorId, rootId).
+		WhereGT(cols.Depth, 0)
+	if maxDepth > 0 {
+		mod = mod.WhereLTE(cols.Depth, maxDepth)
+	}
+
+	var rows []*entity.ToogoUserRelation
+	err := mod.Fields(cols.DescendantId, cols.Depth).
+		OrderAsc(cols.Depth).
+		OrderAsc(cols.DescendantId).
+		Scan(&rows)
 	if err != nil {
 		return nil, err
 	}
-
-	var ids []int64
-	for _, u := range users {
-		ids = append(ids, u.MemberId)
+	result := make([]*teamMember, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &teamMember{MemberId: row.DescendantId, Depth: row.Depth})
 	}
-	return ids, nil
+	return result, nil
+}
+
+// GetDirectMembers 获取直推成员
+func (s *sToogoUser) GetDirectMembers(ctx context.Context, memberId int64) ([]int64, error) {
+	return membersAtDepth(ctx, memberId, 1)
 }
 
 // GetLevel2Members 获取二级成员
 func (s *sToogoUser) GetLevel2Members(ctx context.Context, memberId int64) ([]int64, error) {
-	directIds, err := s.GetDirectMembers(ctx, memberId)
-	if err != nil || len(directIds) == 0 {
-		return nil, err
-	}
-
-	var users []*entity.ToogoUser
-	err = dao.ToogoUser.Ctx(ctx).
-		WhereIn(dao.ToogoUser.Columns().InviterId, directIds).
-		Scan(&users)
-	if err != nil {
-		return nil, err
-	}
-
-	var ids []int64
-	for _, u := range users {
-		ids = append(ids, u.MemberId)
-	}
-	return ids, nil
+	return membersAtDepth(ctx, memberId, 2)
 }
 
 // GetLevel3Members 获取三级成员
 func (s *sToogoUser) GetLevel3Members(ctx context.Context, memberId int64) ([]int64, error) {
-	level2Ids, err := s.GetLevel2Members(ctx, memberId)
-	if err != nil || len(level2Ids) == 0 {
-		return nil, err
-	}
+	return membersAtDepth(ctx, memberId, 3)
+}
 
-	var users []*entity.ToogoUser
-	err = dao.ToogoUser.Ctx(ctx).
-		WhereIn(dao.ToogoUser.Columns().InviterId, level2Ids).
-		Scan(&users)
+// GetAllTeamMembers 获取所有团队成员（三级以内）
+func (s *sToogoUser) GetAllTeamMembers(ctx context.Context, memberId int64) ([]int64, error) {
+	team, err := loadTeam(ctx, memberId, 3)
 	if err != nil {
 		return nil, err
 	}
-
-	var ids []int64
-	for _, u := range users {
-		ids = append(ids, u.MemberId)
+	allIds := make([]int64, 0, len(team))
+	for _, m := range team {
+		allIds = append(allIds, m.MemberId)
 	}
-	return ids, nil
-}
-
-// GetAllTeamMembers 获取所有团队成员
-func (s *sToogoUser) GetAllTeamMembers(ctx context.Context, memberId int64) ([]int64, error) {
-	var allIds []int64
-
-	level1, _ := s.GetDirectMembers(ctx, memberId)
-	level2, _ := s.GetLevel2Members(ctx, memberId)
-	level3, _ := s.GetLevel3Members(ctx, memberId)
-
-	allIds = append(allIds, level1...)
-	allIds = append(allIds, level2...)
-	allIds = append(allIds, level3...)
-
 	return allIds, nil
 }
 
-// GetMemberLevel 获取成员层级
+// GetMemberLevel 获取成员相对 rootId 的层级（1=直推），不在团队内返回0
 func (s *sToogoUser) GetMemberLevel(ctx context.Context, rootId, memberId int64) int {
-	var user *entity.ToogoUser
-	dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, memberId).Scan(&user)
-	if user == nil {
+	cols := dao.ToogoUserRelation.Columns()
+	depth, err := dao.ToogoUserRelation.Ctx(ctx).
+		Where(cols.AncestorId, rootId).
+		Where(cols.DescendantId, memberId).
+		WhereGT(cols.Depth, 0).
+		Value(cols.Depth)
+	if err != nil || depth.IsNil() {
 		return 0
 	}
-
-	if user.InviterId == rootId {
-		return 1
-	}
-
-	// 检查二级
-	var inviter *entity.ToogoUser
-	dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, user.InviterId).Scan(&inviter)
-	if inviter != nil && inviter.InviterId == rootId {
-		return 2
-	}
-
-	// 检查三级
-	if inviter != nil {
-		var inviter2 *entity.ToogoUser
-		dao.ToogoUser.Ctx(ctx).Where(dao.ToogoUser.Columns().MemberId, inviter.InviterId).Scan(&inviter2)
-		if inviter2 != nil && inviter2.InviterId == rootId {
-			return 3
-		}
-	}
-
-	return 0
+	return depth.Int()
 }
 
 // TeamStat 团队统计
@@ -660,6 +589,9 @@ func (s *sToogoUser) VipLevelList(ctx context.Context, in *toogoin.VipLevelListI
 
 // VipLevelEdit 编辑VIP等级
 func (s *sToogoUser) VipLevelEdit(ctx context.Context, in *toogoin.VipLevelEditInp) (err error) {
+	if err = RequirePlatform(ctx); err != nil {
+		return
+	}
 	cols := dao.ToogoVipLevel.Columns()
 
 	data := g.Map{
@@ -720,30 +652,26 @@ func (s *sToogoUser) CheckVipUpgrade(ctx context.Context, in *toogoin.CheckVipUp
 		NextLevelName: nextLevel.LevelName,
 	}
 
-	res.Progress.InviteCount = user.InviteCount
+	metrics, err := loadVipMetrics(ctx, []*entity.ToogoUser{user})
+	if err != nil {
+		return nil, err
+	}
+	m := metrics[user.MemberId]
+	res.Progress.InviteCount = m.InviteCount
 	res.Progress.RequireInvite = nextLevel.RequireInviteCount
-	res.Progress.ConsumePower = user.TotalConsumePower
+	res.Progress.ConsumePower = m.ConsumePower
 	res.Progress.RequireConsume = nextLevel.RequireConsumePower
-	res.Progress.TeamConsume = user.TeamConsumePower
+	res.Progress.TeamConsume = m.TeamConsume
 	res.Progress.RequireTeam = nextLevel.RequireTeamConsume
 
-	// 判断是否满足升级条件
-	canUpgrade := (user.InviteCount >= nextLevel.RequireInviteCount) ||
-		(user.TotalConsumePower >= nextLevel.RequireConsumePower) ||
-		(user.TeamConsumePower >= nextLevel.RequireTeamConsume)
-	res.CanUpgrade = canUpgrade
-
-	// 如果满足条件，自动升级
-	if canUpgrade {
-		_, err = dao.ToogoUser.Ctx(ctx).
-			Where(dao.ToogoUser.Columns().MemberId, in.MemberId).
-			Data(g.Map{
-				dao.ToogoUser.Columns().VipLevel:      nextLevel.Level,
-				dao.ToogoUser.Columns().PowerDiscount: nextLevel.PowerDiscount,
-			}).
-			Update()
-		if err != nil {
+	// 判断是否满足升级条件（与定时评估同一规则）
+	res.CanUpgrade = vipLevelQualified(nextLevel, m)
+
+	// 如果满足条件，立即升级并记录变更
+	if res.CanUpgrade {
+		if err = applyVipLevelChange(ctx, user, nextLevel, m, consts.VipEvaluateSourceCheck, ""); err != nil {
 			g.Log().Warningf(ctx, "VIP升级失败: %v", err)
+			err = nil
 		}
 	}
 
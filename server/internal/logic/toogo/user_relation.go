@@ -0,0 +1,316 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+package toogo
+
+import (
+	"context"
+	"slices"
+
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/toogoin"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// 邀请关系闭包表（hg_toogo_user_relation）：
+// - 每个用户对自身及其所有上级各有一行，depth 为层级距离（0=自身，1=直推）
+// - 上级链、各级下级、成员层级、团队人数均通过一次查询得到，不再逐级递归
+// - 绑定/调整邀请人时与 inviter_id、团队计数在同一事务内维护
+
+// ensureSelfRelation 确保用户在闭包表中存在自身节点
+func ensureSelfRelation(ctx context.Context, memberId int64) error {
+	_, err := dao.ToogoUserRelation.Ctx(ctx).Data(&entity.ToogoUserRelation{
+		AncestorId:   memberId,
+		DescendantId: memberId,
+		Depth:        0,
+		CreatedAt:    gtime.Now(),
+	}).InsertIgnore()
+	if err != nil {
+		return gerror.Wrap(err, "初始化邀请关系失败")
+	}
+	return nil
+}
+
+// loadAncestors 按层级由近到远返回上级链（不含自身）
+func loadAncestors(ctx context.Context, memberId int64) ([]int64, error) {
+	cols := dao.ToogoUserRelation.Columns()
+	values, err := dao.ToogoUserRelation.Ctx(ctx).
+		Where(cols.DescendantId, memberId).
+		WhereGT(cols.Depth, 0).
+		OrderAsc(cols.Depth).
+		Fields(cols.AncestorId).
+		Array()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(values))
+	for _, v := range values {
+		ids = append(ids, v.Int64())
+	}
+	return ids, nil
+}
+
+// loadSubtree 返回以 memberId 为根的子树（含自身，depth=0），锁定读，需在事务内调用
+func loadSubtree(ctx context.Context, memberId int64) (nodes []*entity.ToogoUserRelation, err error) {
+	cols := dao.ToogoUserRelation.Columns()
+	err = dao.ToogoUserRelation.Ctx(ctx).
+		Where(cols.AncestorId, memberId).
+		Fields(cols.DescendantId, cols.Depth).
+		OrderAsc(cols.Depth).
+		LockUpdate().
+		Scan(&nodes)
+	return
+}
+
+// membersAtDepth 获取指定层级的下级成员（depth=1 为直推）
+func membersAtDepth(ctx context.Context, memberId int64, depth int) ([]int64, error) {
+	cols := dao.ToogoUserRelation.Columns()
+	values, err := dao.ToogoUserRelation.Ctx(ctx).
+		Where(cols.AncestorId, memberId).
+		Where(cols.Depth, depth).
+		OrderAsc(cols.DescendantId).
+		Fields(cols.DescendantId).
+		Array()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(values))
+	for _, v := range values {
+		ids = append(ids, v.Int64())
+	}
+	return ids, nil
+}
+
+// bindInviter 将用户（连同其整个团队）挂到新的邀请人名下，newInviterId=0 表示解除邀请关系（需在事务内调用）
+// 同步维护 inviter_id、闭包表，以及新旧上级链的邀请人数、团队人数、团队消耗算力
+// 子树与新旧上级链均以锁定读加载：与并发的注册绑定或上级调整在闭包行上互斥，并读取已提交的最新关系
+func bindInviter(ctx context.Context, user *entity.ToogoUser, newInviterId int64) error {
+	if newInviterId == user.MemberId {
+		return gerror.New("不能将自己设为邀请人")
+	}
+	if newInviterId == user.InviterId {
+		return nil
+	}
+	if err := ensureSelfRelation(ctx, user.MemberId); err != nil {
+		return err
+	}
+
+	subtree, err := loadSubtree(ctx, user.MemberId)
+	if err != nil {
+		return gerror.Wrap(err, "查询团队成员失败")
+	}
+	subtreeIds := make([]int64, 0, len(subtree))
+	for _, node := range subtree {
+		subtreeIds = append(subtreeIds, node.DescendantId)
+	}
+
+	relCols := dao.ToogoUserRelation.Columns()
+	var newAncestors []*entity.ToogoUserRelation
+	if newInviterId > 0 {
+		if err = ensureSelfRelation(ctx, newInviterId); err != nil {
+			return err
+		}
+		err = dao.ToogoUserRelation.Ctx(ctx).
+			Where(relCols.DescendantId, newInviterId).
+			Fields(relCols.AncestorId, relCols.Depth).
+			LockUpdate().
+			Scan(&newAncestors)
+		if err != nil {
+			return gerror.Wrap(err, "查询邀请人上级链失败")
+		}
+		for _, a := range newAncestors {
+			if a.AncestorId == user.MemberId {
+				return gerror.New("邀请人是该用户的下级，不能形成循环邀请关系")
+			}
+		}
+	}
+
+	values, err := dao.ToogoUserRelation.Ctx(ctx).
+		Where(relCols.DescendantId, user.MemberId).
+		WhereGT(relCols.Depth, 0).
+		Fields(relCols.AncestorId).
+		LockUpdate().
+		Array()
+	if err != nil {
+		return gerror.Wrap(err, "查询原上级链失败")
+	}
+	oldAncestors := make([]int64, 0, len(values))
+	for _, v := range values {
+		oldAncestors = append(oldAncestors, v.Int64())
+	}
+
+	// 团队规模与团队消耗随子树整体迁移
+	cols := dao.ToogoUser.Columns()
+	teamSize := len(subtreeIds)
+	var teamConsume float64
+	for batch := range slices.Chunk(subtreeIds, teamIdBatch) {
+		sum, err := dao.ToogoUser.Ctx(ctx).WhereIn(cols.MemberId, batch).Sum(cols.TotalConsumePower)
+		if err != nil {
+			return gerror.Wrap(err, "统计团队消耗失败")
+		}
+		teamConsume += sum
+	}
+
+	if len(oldAncestors) > 0 {
+		for batch := range slices.Chunk(subtreeIds, teamIdBatch) {
+			_, err = dao.ToogoUserRelation.Ctx(ctx).
+				WhereIn(relCols.AncestorId, oldAncestors).
+				WhereIn(relCols.DescendantId, batch).
+				Delete()
+			if err != nil {
+				return gerror.Wrap(err, "解除原邀请关系失败")
+			}
+		}
+		if err = adjustTeamStats(ctx, oldAncestors, -teamSize, -teamConsume); err != nil {
+			return err
+		}
+	}
+
+	if len(newAncestors) > 0 {
+		now := gtime.Now()
+		rows := make([]*entity.ToogoUserRelation, 0, len(newAncestors)*len(subtree))
+		for _, a := range newAncestors {
+			for _, node := range subtree {
+				rows = append(rows, &entity.ToogoUserRelation{
+					AncestorId:   a.AncestorId,
+					DescendantId: node.DescendantId,
+					Depth:        a.Depth + node.Depth + 1,
+					CreatedAt:    now,
+				})
+			}
+		}
+		if _, err = dao.ToogoUserRelation.Ctx(ctx).Data(rows).Batch(teamIdBatch).Insert(); err != nil {
+			return gerror.Wrap(err, "写入邀请关系失败")
+		}
+		ancestorIds := make([]int64, 0, len(newAncestors))
+		for _, a := range newAncestors {
+			ancestorIds = append(ancestorIds, a.AncestorId)
+		}
+		if err = adjustTeamStats(ctx, ancestorIds, teamSize, teamConsume); err != nil {
+			return err
+		}
+	}
+
+	_, err = dao.ToogoUser.Ctx(ctx).
+		Where(cols.MemberId, user.MemberId).
+		Data(g.Map{cols.InviterId: newInviterId}).
+		Update()
+	if err != nil {
+		return gerror.Wrap(err, "设置邀请关系失败")
+	}
+	if user.InviterId > 0 {
+		if _, err = dao.ToogoUser.Ctx(ctx).Where(cols.MemberId, user.InviterId).Decrement(cols.InviteCount, 1); err != nil {
+			return gerror.Wrap(err, "更新邀请数量失败")
+		}
+	}
+	if newInviterId > 0 {
+		if _, err = dao.ToogoUser.Ctx(ctx).Where(cols.MemberId, newInviterId).Increment(cols.InviteCount, 1); err != nil {
+			return gerror.Wrap(err, "更新邀请数量失败")
+		}
+	}
+	user.InviterId = newInviterId
+	return nil
+}
+
+// adjustTeamStats 批量调整上级的团队人数与团队消耗算力
+func adjustTeamStats(ctx context.Context, ancestorIds []int64, teamDelta int, consumeDelta float64) error {
+	cols := dao.ToogoUser.Columns()
+	data := g.Map{
+		cols.TeamCount: &gdb.Counter{Field: cols.TeamCount, Value: float64(teamDelta)},
+	}
+	if consumeDelta != 0 {
+		data[cols.TeamConsumePower] = &gdb.Counter{Field: cols.TeamConsumePower, Value: consumeDelta}
+	}
+	_, err := dao.ToogoUser.Ctx(ctx).WhereIn(cols.MemberId, ancestorIds).Data(data).Update()
+	if err != nil {
+		return gerror.Wrap(err, "更新团队统计失败")
+	}
+	return nil
+}
+
+// ChangeInviter 管理员调整邀请人：整个团队随用户迁移，新旧上级链的团队统计按迁移的团队规模增量调整
+func (s *sToogoUser) ChangeInviter(ctx context.Context, in *toogoin.ChangeInviterInp) (res *toogoin.ChangeInviterModel, err error) {
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		user, inviter, err := lockInviteMembers(ctx, in.MemberId, in.InviterId)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return gerror.New("用户不存在")
+		}
+		if in.InviterId > 0 && inviter == nil {
+			return gerror.New("邀请人不存在")
+		}
+
+		res = &toogoin.ChangeInviterModel{
+			MemberId:     user.MemberId,
+			OldInviterId: user.InviterId,
+			NewInviterId: in.InviterId,
+		}
+		oldAncestors, err := loadAncestors(ctx, user.MemberId)
+		if err != nil {
+			return gerror.Wrap(err, "查询原上级链失败")
+		}
+		if err = bindInviter(ctx, user, in.InviterId); err != nil {
+			return err
+		}
+		newAncestors, err := loadAncestors(ctx, user.MemberId)
+		if err != nil {
+			return gerror.Wrap(err, "查询新上级链失败")
+		}
+
+		affected := append(slices.Clone(oldAncestors), newAncestors...)
+		slices.Sort(affected)
+		affected = slices.Compact(affected)
+		teamCount, err := membersCount(ctx, user.MemberId)
+		if err != nil {
+			return gerror.Wrap(err, "统计团队人数失败")
+		}
+		res.MovedCount = teamCount + 1
+		res.AffectedCount = len(affected)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	g.Log().Infof(ctx, "[ChangeInviter] 用户 %d 邀请人 %d -> %d，迁移 %d 人", res.MemberId, res.OldInviterId, res.NewInviterId, res.MovedCount)
+	return res, nil
+}
+
+// lockInviteMembers 按 member_id 升序锁定用户与邀请人（需在事务内调用），inviterId=0 时只锁定用户
+// 调整邀请人与注册绑定都先锁定这两行再改写闭包表，避免基于过期的 inviter_id 或上级链交错写入
+func lockInviteMembers(ctx context.Context, memberId, inviterId int64) (user, inviter *entity.ToogoUser, err error) {
+	lockIds := []int64{memberId}
+	if inviterId > 0 {
+		lockIds = append(lockIds, inviterId)
+	}
+	cols := dao.ToogoUser.Columns()
+	var users []*entity.ToogoUser
+	if err = dao.ToogoUser.Ctx(ctx).WhereIn(cols.MemberId, lockIds).OrderAsc(cols.MemberId).LockUpdate().Scan(&users); err != nil {
+		return nil, nil, gerror.Wrap(err, "获取用户信息失败")
+	}
+	for _, u := range users {
+		switch u.MemberId {
+		case memberId:
+			user = u
+		case inviterId:
+			inviter = u
+		}
+	}
+	return
+}
+
+// membersCount 统计团队人数（不含自身）
+func membersCount(ctx context.Context, memberId int64) (int, error) {
+	cols := dao.ToogoUserRelation.Columns()
+	return dao.ToogoUserRelation.Ctx(ctx).
+		Where(cols.AncestorId, memberId).
+		WhereGT(cols.Depth, 0).
+		Count()
+}
@@ -0,0 +1,360 @@
+// Package toogo
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+package toogo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/toogoin"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// vipEvaluateBatch 定时评估每批加载的用户数
+const vipEvaluateBatch = 500
+
+// vipLevelConfig VIP等级评估配置（toogo.vip）
+type vipLevelConfig struct {
+	DowngradeEnabled   bool // 是否自动降级
+	DowngradeGraceDays int  // 持续不满足当前等级多少天后降级，0=立即降级
+}
+
+func getVipLevelConfig(ctx context.Context) *vipLevelConfig {
+	cfg := &vipLevelConfig{
+		DowngradeEnabled:   g.Cfg().MustGet(ctx, "toogo.vip.downgradeEnabled", true).Bool(),
+		DowngradeGraceDays: g.Cfg().MustGet(ctx, "toogo.vip.downgradeGraceDays", 7).Int(),
+	}
+	if cfg.DowngradeGraceDays < 0 {
+		cfg.DowngradeGraceDays = 0
+	}
+	return cfg
+}
+
+// vipMetrics 评估VIP等级的实时指标
+type vipMetrics struct {
+	InviteCount  int
+	ConsumePower float64
+	TeamConsume  float64
+}
+
+// vipLevelQualified 是否满足等级门槛：与升级检查一致，任一已设置（>0）的门槛达标即可；门槛全部为0的是基础等级
+func vipLevelQualified(level *entity.ToogoVipLevel, m *vipMetrics) bool {
+	if level.RequireInviteCount <= 0 && level.RequireConsumePower <= 0 && level.RequireTeamConsume <= 0 {
+		return true
+	}
+	return (level.RequireInviteCount > 0 && m.InviteCount >= level.RequireInviteCount) ||
+		(level.RequireConsumePower > 0 && m.ConsumePower >= level.RequireConsumePower) ||
+		(level.RequireTeamConsume > 0 && m.TeamConsume >= level.RequireTeamConsume)
+}
+
+// loadVipLevels 加载启用中的VIP等级（按等级升序）
+func loadVipLevels(ctx context.Context) (levels []*entity.ToogoVipLevel, err error) {
+	cols := dao.ToogoVipLevel.Columns()
+	err = dao.ToogoVipLevel.Ctx(ctx).
+		Where(cols.Status, 1).
+		OrderAsc(cols.Level).
+		Scan(&levels)
+	return
+}
+
+// targetVipLevel 按指标计算应有等级：满足门槛的最高等级，均不满足时取最低等级
+func targetVipLevel(levels []*entity.ToogoVipLevel, m *vipMetrics) *entity.ToogoVipLevel {
+	if len(levels) == 0 {
+		return nil
+	}
+	target := levels[0]
+	for _, level := range levels {
+		if vipLevelQualified(level, m) {
+			target = level
+		}
+	}
+	return target
+}
+
+// loadVipMetrics 批量计算用户的直推人数（闭包表）、个人消耗算力与团队消耗算力（实时汇总团队成员消耗）
+func loadVipMetrics(ctx context.Context, users []*entity.ToogoUser) (map[int64]*vipMetrics, error) {
+	metrics := make(map[int64]*vipMetrics, len(users))
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		metrics[u.MemberId] = &vipMetrics{ConsumePower: u.TotalConsumePower}
+		ids = append(ids, u.MemberId)
+	}
+	if len(ids) == 0 {
+		return metrics, nil
+	}
+
+	relCols := dao.ToogoUserRelation.Columns()
+	invites, err := dao.ToogoUserRelation.Ctx(ctx).
+		Fields(relCols.AncestorId).
+		FieldCount(relCols.DescendantId, "total").
+		WhereIn(relCols.AncestorId, ids).
+		Where(relCols.Depth, 1).
+		Group(relCols.AncestorId).
+		All()
+	if err != nil {
+		return nil, gerror.Wrap(err, "统计直推人数失败")
+	}
+	for _, row := range invites {
+		if m, ok := metrics[row[relCols.AncestorId].Int64()]; ok {
+			m.InviteCount = row["total"].Int()
+		}
+	}
+
+	userCols := dao.ToogoUser.Columns()
+	teams, err := g.DB().Model(dao.ToogoUserRelation.Table()+" r").Ctx(ctx).
+		InnerJoin(dao.ToogoUser.Table()+" u", "u."+userCols.MemberId+" = r."+relCols.DescendantId).
+		Fields("r."+relCols.AncestorId).
+		FieldSum("u."+userCols.TotalConsumePower, "total").
+		WhereIn("r."+relCols.AncestorId, ids).
+		WhereGT("r."+relCols.Depth, 0).
+		Group("r." + relCols.AncestorId).
+		All()
+	if err != nil {
+		return nil, gerror.Wrap(err, "统计团队消耗失败")
+	}
+	for _, row := range teams {
+		if m, ok := metrics[row[relCols.AncestorId].Int64()]; ok {
+			m.TeamConsume = row["total"].Float64()
+		}
+	}
+	return metrics, nil
+}
+
+// EvaluateVipLevels 按实时指标重新评估VIP等级：达标立即升级；不满足当前等级时进入降级宽限期，宽限期满仍不达标才降级
+func (s *sToogoUser) EvaluateVipLevels(ctx context.Context, in *toogoin.VipEvaluateInp) (res *toogoin.VipEvaluateModel, err error) {
+	if in.Source == "" {
+		in.Source = consts.VipEvaluateSourceCron
+	}
+	levels, err := loadVipLevels(ctx)
+	if err != nil {
+		return nil, gerror.Wrap(err, "获取VIP等级配置失败")
+	}
+	res = &toogoin.VipEvaluateModel{}
+	if len(levels) == 0 {
+		return res, nil
+	}
+	cfg := getVipLevelConfig(ctx)
+
+	cols := dao.ToogoUser.Columns()
+	var lastId int64
+	for {
+		mod := dao.ToogoUser.Ctx(ctx).
+			Fields(cols.Id, cols.MemberId, cols.VipLevel, cols.PowerDiscount, cols.VipDowngradeAt, cols.TotalConsumePower).
+			WhereGT(cols.Id, lastId)
+		if in.MemberId > 0 {
+			mod = mod.Where(cols.MemberId, in.MemberId)
+		}
+		var users []*entity.ToogoUser
+		if err = mod.OrderAsc(cols.Id).Limit(vipEvaluateBatch).Scan(&users); err != nil {
+			return res, gerror.Wrap(err, "加载用户失败")
+		}
+		if len(users) == 0 {
+			break
+		}
+		lastId = users[len(users)-1].Id
+
+		metrics, err := loadVipMetrics(ctx, users)
+		if err != nil {
+			return res, err
+		}
+		for _, user := range users {
+			res.Evaluated++
+			changeType, pending, err := s.evaluateUserVipLevel(ctx, user, levels, metrics[user.MemberId], cfg, in.Source)
+			if err != nil {
+				g.Log().Warningf(ctx, "[VIP] 评估用户等级失败 memberId=%d: %v", user.MemberId, err)
+				continue
+			}
+			switch {
+			case changeType == consts.VipChangeUpgrade:
+				res.Upgraded++
+			case changeType == consts.VipChangeDowngrade:
+				res.Downgraded++
+			case pending:
+				res.Pending++
+			}
+		}
+		if len(users) < vipEvaluateBatch {
+			break
+		}
+	}
+	return res, nil
+}
+
+// evaluateUserVipLevel 评估单个用户，返回发生的变更类型及是否处于降级宽限期
+func (s *sToogoUser) evaluateUserVipLevel(ctx context.Context, user *entity.ToogoUser, levels []*entity.ToogoVipLevel, m *vipMetrics, cfg *vipLevelConfig, source string) (changeType string, pending bool, err error) {
+	target := targetVipLevel(levels, m)
+	if target == nil {
+		return "", false, nil
+	}
+	cols := dao.ToogoUser.Columns()
+
+	if target.Level >= user.VipLevel {
+		if target.Level > user.VipLevel {
+			return consts.VipChangeUpgrade, false, applyVipLevelChange(ctx, user, target, m, source, "")
+		}
+		// 重新达标：取消降级宽限期
+		if user.VipDowngradeAt != nil {
+			_, err = dao.ToogoUser.Ctx(ctx).
+				Where(cols.MemberId, user.MemberId).
+				Data(g.Map{cols.VipDowngradeAt: nil}).
+				Update()
+		}
+		return "", false, err
+	}
+
+	if !cfg.DowngradeEnabled {
+		return "", false, nil
+	}
+	now := gtime.Now()
+	if user.VipDowngradeAt == nil && cfg.DowngradeGraceDays > 0 {
+		_, err = dao.ToogoUser.Ctx(ctx).
+			Where(cols.MemberId, user.MemberId).
+			WhereNull(cols.VipDowngradeAt).
+			Data(g.Map{cols.VipDowngradeAt: now}).
+			Update()
+		if err != nil {
+			return "", false, err
+		}
+		notifyUser(ctx, user.MemberId, consts.NoticeTypePromotion, "VIP等级保级提醒",
+			fmt.Sprintf("您当前已不满足V%d等级条件，%d天内未重新达标将调整为V%d（算力折扣 %.2f%%）。",
+				user.VipLevel, cfg.DowngradeGraceDays, target.Level, target.PowerDiscount))
+		return "", true, nil
+	}
+
+	graceEnd := now
+	if user.VipDowngradeAt != nil {
+		graceEnd = user.VipDowngradeAt.Add(time.Duration(cfg.DowngradeGraceDays) * 24 * time.Hour)
+	}
+	if now.Before(graceEnd) {
+		return "", true, nil
+	}
+	remark := "未满足当前等级条件"
+	if cfg.DowngradeGraceDays > 0 {
+		remark = fmt.Sprintf("连续%d天未满足当前等级条件", cfg.DowngradeGraceDays)
+	}
+	return consts.VipChangeDowngrade, false, applyVipLevelChange(ctx, user, target, m, source, remark)
+}
+
+// applyVipLevelChange 变更VIP等级：同步算力折扣、清除降级宽限期、记录变更及触发指标并通知用户
+func applyVipLevelChange(ctx context.Context, user *entity.ToogoUser, target *entity.ToogoVipLevel, m *vipMetrics, source, remark string) error {
+	changeType := consts.VipChangeUpgrade
+	if target.Level < user.VipLevel {
+		changeType = consts.VipChangeDowngrade
+	}
+
+	cols := dao.ToogoUser.Columns()
+	err := g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		// 以当前等级为条件更新，避免与并发评估重复变更
+		result, err := dao.ToogoUser.Ctx(ctx).
+			Where(cols.MemberId, user.MemberId).
+			Where(cols.VipLevel, user.VipLevel).
+			Data(g.Map{
+				cols.VipLevel:       target.Level,
+				cols.PowerDiscount:  target.PowerDiscount,
+				cols.VipDowngradeAt: nil,
+			}).
+			Update()
+		if err != nil {
+			return gerror.Wrap(err, "更新VIP等级失败")
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return gerror.New("VIP等级已被其他操作修改")
+		}
+
+		_, err = dao.ToogoVipLevelLog.Ctx(ctx).Data(&entity.ToogoVipLevelLog{
+			UserId:            user.MemberId,
+			FromLevel:         user.VipLevel,
+			ToLevel:           target.Level,
+			ChangeType:        changeType,
+			Source:            source,
+			InviteCount:       m.InviteCount,
+			ConsumePower:      m.ConsumePower,
+			TeamConsumePower:  m.TeamConsume,
+			FromPowerDiscount: user.PowerDiscount,
+			ToPowerDiscount:   target.PowerDiscount,
+			Remark:            remark,
+			CreatedAt:         gtime.Now(),
+		}).Insert()
+		if err != nil {
+			return gerror.Wrap(err, "记录VIP等级变更失败")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g.Log().Infof(ctx, "[VIP] 用户等级%s: memberId=%d, V%d -> V%d, invite=%d, consume=%.4f, teamConsume=%.4f, source=%s",
+		changeType, user.MemberId, user.VipLevel, target.Level, m.InviteCount, m.ConsumePower, m.TeamConsume, source)
+	if changeType == consts.VipChangeUpgrade {
+		notifyUser(ctx, user.MemberId, consts.NoticeTypePromotion, "VIP等级提升",
+			fmt.Sprintf("恭喜您升级为%s（V%d），算力消耗折扣调整为 %.2f%%。", target.LevelName, target.Level, target.PowerDiscount))
+	} else {
+		notifyUser(ctx, user.MemberId, consts.NoticeTypePromotion, "VIP等级调整",
+			fmt.Sprintf("由于%s，您的VIP等级已调整为%s（V%d），算力消耗折扣调整为 %.2f%%。", remark, target.LevelName, target.Level, target.PowerDiscount))
+	}
+
+	user.VipLevel = target.Level
+	user.PowerDiscount = target.PowerDiscount
+	user.VipDowngradeAt = nil
+	return nil
+}
+
+// VipLevelLogList VIP等级变更记录
+func (s *sToogoUser) VipLevelLogList(ctx context.Context, in *toogoin.VipLevelLogListInp) (list []*toogoin.VipLevelLogListModel, totalCount int, err error) {
+	mod := dao.ToogoVipLevelLog.Ctx(ctx)
+	cols := dao.ToogoVipLevelLog.Columns()
+
+	if in.UserId > 0 {
+		mod = mod.Where(cols.UserId, in.UserId)
+	}
+	if in.ChangeType != "" {
+		mod = mod.Where(cols.ChangeType, in.ChangeType)
+	}
+	if len(in.CreatedAt) == 2 {
+		mod = mod.WhereBetween(cols.CreatedAt, in.CreatedAt[0], in.CreatedAt[1])
+	}
+
+	var logs []*entity.ToogoVipLevelLog
+	err = mod.OrderDesc(cols.Id).Page(in.Page, in.PerPage).ScanAndCount(&logs, &totalCount, true)
+	if err != nil {
+		return nil, 0, gerror.Wrap(err, "获取VIP等级变更记录失败")
+	}
+
+	userIds := make([]int64, 0, len(logs))
+	for _, log := range logs {
+		userIds = append(userIds, log.UserId)
+	}
+	slices.Sort(userIds)
+	userIds = slices.Compact(userIds)
+	usernames := make(map[int64]string, len(userIds))
+	if len(userIds) > 0 {
+		var members []*entity.AdminMember
+		_ = dao.AdminMember.Ctx(ctx).
+			Fields(dao.AdminMember.Columns().Id, dao.AdminMember.Columns().Username).
+			WhereIn(dao.AdminMember.Columns().Id, userIds).
+			Scan(&members)
+		for _, member := range members {
+			usernames[member.Id] = member.Username
+		}
+	}
+
+	for _, log := range logs {
+		list = append(list, &toogoin.VipLevelLogListModel{
+			ToogoVipLevelLog: log,
+			Username:         usernames[log.UserId],
+		})
+	}
+	return
+}
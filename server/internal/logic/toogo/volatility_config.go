@@ -55,6 +55,9 @@ func (s *sToogoVolatilityConfig) List(ctx context.Context, in *toogoin.Volatilit
 
 // Edit 编辑波动率配置（简化版：市场状态阈值 + 5个时间周期权重）
 func (s *sToogoVolatilityConfig) Edit(ctx context.Context, in *toogoin.VolatilityConfigEditInp) error {
+	if err := RequirePlatform(ctx); err != nil {
+		return err
+	}
 	cols := dao.ToogoVolatilityConfig.Columns()
 	
 	// 检查交易对是否已存在（排除当前记录）
@@ -122,6 +125,9 @@ func (s *sToogoVolatilityConfig) Edit(ctx context.Context, in *toogoin.Volatilit
 
 // BatchEdit 批量编辑波动率配置（为多个货币对批量设置相同配置）
 func (s *sToogoVolatilityConfig) BatchEdit(ctx context.Context, in *toogoin.VolatilityConfigBatchEditInp) error {
+	if err := RequirePlatform(ctx); err != nil {
+		return err
+	}
 	if len(in.Symbols) == 0 {
 		return gerror.New("请选择至少一个交易对")
 	}
@@ -249,6 +255,9 @@ func (s *sToogoVolatilityConfig) GetBySymbol(ctx context.Context, symbol string)
 
 // Delete 删除波动率配置
 func (s *sToogoVolatilityConfig) Delete(ctx context.Context, id int64) error {
+	if err := RequirePlatform(ctx); err != nil {
+		return err
+	}
 	// 检查是否是全局配置
 	var config *entity.ToogoVolatilityConfig
 	err := dao.ToogoVolatilityConfig.Ctx(ctx).Where(dao.ToogoVolatilityConfig.Columns().Id, id).Scan(&config)
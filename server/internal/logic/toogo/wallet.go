@@ -16,7 +16,6 @@ import (
 	"hotgo/internal/service"
 	"math"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/gogf/gf/v2/database/gdb"
@@ -25,7 +24,6 @@ import (
 	"github.com/gogf/gf/v2/os/gcache"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/util/grand"
-	"golang.org/x/time/rate"
 )
 
 type sToogoWallet struct{}
@@ -34,26 +32,6 @@ type sToogoWallet struct{}
 // 只缓存很短时间（秒级），主要用于“并发/短时间重复请求”去重，降低服务器与交易所API压力。
 var orderHistoryTradeCache = gcache.New()
 
-// tradeHistoryLimiter 对“交易明细页的成交拉取”做低优先级限流（按 apiConfigId）。
-// 目的：避免用户频繁刷新交易明细时占满交易所限频额度，从而影响“机器人持仓刷新”等实时性更高的接口。
-// 策略：不等待（Allow 即放行，否则直接放弃本次拉取，靠缓存/DB兜底）。
-var (
-	tradeHistoryLimiterMu sync.Mutex
-	tradeHistoryLimiter   = map[int64]*rate.Limiter{}
-)
-
-func getTradeHistoryLimiter(apiId int64) *rate.Limiter {
-	tradeHistoryLimiterMu.Lock()
-	defer tradeHistoryLimiterMu.Unlock()
-	if lim := tradeHistoryLimiter[apiId]; lim != nil {
-		return lim
-	}
-	// 每个 apiConfigId：约 1 req/s，burst=1（尽量温和，不抢占额度）
-	lim := rate.NewLimiter(rate.Every(1*time.Second), 1)
-	tradeHistoryLimiter[apiId] = lim
-	return lim
-}
-
 func NewToogoWallet() *sToogoWallet {
 	return &sToogoWallet{}
 }
@@ -110,6 +88,7 @@ func (s *sToogoWallet) GetOverview(ctx context.Context, in *toogoin.WalletOvervi
 
 // ChangeBalance 变更账户余额 (核心方法)
 func (s *sToogoWallet) ChangeBalance(ctx context.Context, in *toogoin.ChangeBalanceInp) (err error) {
+	defer func() { observeWalletMutation(in.AccountType, in.ChangeType, err) }()
 	return g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
 		// 获取钱包
 		wallet, err := s.GetOrCreate(ctx, in.UserId)
@@ -151,11 +130,20 @@ func (s *sToogoWallet) ChangeBalance(ctx context.Context, in *toogoin.ChangeBala
 		case "commission":
 			beforeAmount = wallet.Commission
 			afterAmount = beforeAmount + in.Amount
-			if afterAmount < 0 {
+			// 佣金冲正允许佣金余额为负，由后续佣金抵扣
+			if afterAmount < 0 && in.ChangeType != consts.CommissionChangeReversal {
 				return gerror.New("佣金不足")
 			}
 			updateField = dao.ToogoWallet.Columns().Commission
 
+		case "frozen_commission":
+			beforeAmount = wallet.FrozenCommission
+			afterAmount = beforeAmount + in.Amount
+			if afterAmount < 0 {
+				return gerror.New("冻结佣金不足")
+			}
+			updateField = dao.ToogoWallet.Columns().FrozenCommission
+
 		default:
 			return gerror.Newf("不支持的账户类型: %s", in.AccountType)
 		}
@@ -270,6 +258,7 @@ func (s *sToogoWallet) Transfer(ctx context.Context, in *toogoin.TransferInp) (r
 
 // ConsumePower 消耗算力（已禁用）
 // 说明：按产品需求不再对“盈利订单”扣除算力；保留空实现以兼容历史调用。
+// 算力消耗改由业绩费（高水位）周期结算，见 SettlePerformanceFee。
 func (s *sToogoWallet) ConsumePower(ctx context.Context, userId int64, robotId int64, orderId int64, orderSn string, profitAmount float64) error {
 	g.Log().Infof(ctx, "[ConsumePower] 已禁用：跳过算力扣除 userId=%d, robotId=%d, orderId=%d, profit=%.4f",
 		userId, robotId, orderId, profitAmount)
@@ -359,9 +348,9 @@ func (s *sToogoWallet) UserWalletList(ctx context.Context, username, mobile stri
 		perPage = 20
 	}
 
-	// 构建基础查询条件
-	baseModel := g.DB().Model("hg_toogo_wallet w").
-		LeftJoin("hg_admin_member m", "w.user_id = m.id")
+	// 构建基础查询条件，经钱包 DAO 构建以按租户隔离用户
+	baseModel := dao.ToogoWallet.Ctx(ctx).As("w").
+		LeftJoin(dao.AdminMember.Table()+" m", "w.user_id = m.id")
 
 	// 搜索条件
 	if username != "" {
@@ -788,19 +777,14 @@ func (s *sToogoWallet) OrderHistoryList(ctx context.Context, in *toogoin.OrderHi
 			if limit <= 0 {
 				limit = 400
 			}
-			// 低优先级限流：不等待，避免影响持仓刷新等更关键接口
-			if apiId > 0 {
-				if !getTradeHistoryLimiter(apiId).Allow() {
-					return nil, gerror.New("trade history rate limited (low priority)")
-				}
-			}
 			cacheKey := fmt.Sprintf("toogo:orderHistory:trades:%d:%s:%d", apiId, symbol, limit)
 			if v, _ := orderHistoryTradeCache.Get(ctx, cacheKey); v != nil && !v.IsEmpty() {
 				if cached, ok := v.Val().([]*exlib.Trade); ok {
 					return cached, nil
 				}
 			}
-			trades, err := p.GetTradeHistory(ctx, symbol, limit)
+			// 低优先级：额度紧张时直接放弃，避免影响持仓刷新等更关键接口
+			trades, err := p.GetTradeHistory(exlib.WithPriority(ctx, exlib.PriorityLow), symbol, limit)
 			if err == nil && len(trades) > 0 {
 				// 【优化】延长缓存时间从10秒到5分钟，大幅减少API调用
 				// 交易明细页面数据变化不频繁，5分钟缓存可以覆盖大部分场景
@@ -1397,13 +1381,14 @@ func (s *sToogoWallet) RunSessionSummaryList(ctx context.Context, in *toogoin.Ru
 	if in.IsRunning == 0 || in.IsRunning == 1 {
 		type rbRow struct {
 			Id        int64       `orm:"id"`
+			BasketId  int64       `orm:"basket_id"`
 			Exchange  string      `orm:"exchange"`
 			Symbol    string      `orm:"symbol"`
 			StartTime *gtime.Time `orm:"start_time"`
 		}
 		var robots []*rbRow
 		rbMod := dao.TradingRobot.Ctx(ctx).
-			Fields("id", "exchange", "symbol", "start_time").
+			Fields("id", "basket_id", "exchange", "symbol", "start_time").
 			Where(dao.TradingRobot.Columns().UserId, memberId).
 			Where(dao.TradingRobot.Columns().Status, 2). // 2=运行中
 			WhereNull(dao.TradingRobot.Columns().DeletedAt)
@@ -1458,6 +1443,7 @@ func (s *sToogoWallet) RunSessionSummaryList(ctx context.Context, in *toogoin.Ru
 					}
 					data = append(data, g.Map{
 						"robot_id":   r.Id,
+						"basket_id":  r.BasketId,
 						"user_id":    memberId,
 						"exchange":   r.Exchange,
 						"symbol":     r.Symbol,
@@ -0,0 +1,74 @@
+//go:build integration
+// +build integration
+
+// Package toogo
+// @Description 用户钱包列表租户隔离测试（需要数据库）
+package toogo
+
+import (
+	_ "github.com/gogf/gf/contrib/drivers/mysql/v2"
+
+	"context"
+	"testing"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/util/grand"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/model"
+)
+
+// TestUserWalletListTenant 租户管理员只能看到本租户用户的钱包
+func TestUserWalletListTenant(t *testing.T) {
+	var (
+		ctx      = gctx.New()
+		tenantA  = int64(grand.N(90000000, 94999999))
+		tenantB  = tenantA + 5000000
+		memberA  = int64(grand.N(900000000, 949999999))
+		memberB  = memberA + 50000000
+		userCols = dao.ToogoUser.Columns()
+		walCols  = dao.ToogoWallet.Columns()
+	)
+
+	for memberId, tenantId := range map[int64]int64{memberA: tenantA, memberB: tenantB} {
+		if _, err := dao.ToogoUser.Ctx(ctx).Data(g.Map{userCols.MemberId: memberId, userCols.TenantId: tenantId}).Insert(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := dao.ToogoWallet.Ctx(ctx).Data(g.Map{walCols.UserId: memberId, walCols.Balance: 1}).Insert(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		_, _ = dao.ToogoWallet.Ctx(ctx).WhereIn(walCols.UserId, []int64{memberA, memberB}).Delete()
+		_, _ = dao.ToogoUser.Ctx(ctx).WhereIn(userCols.MemberId, []int64{memberA, memberB}).Delete()
+	}()
+
+	// 以租户A管理员身份查询
+	tenantCtx := context.WithValue(ctx, consts.ContextHTTPKey, &model.Context{
+		User: &model.Identity{
+			Id:       tenantA,
+			DeptType: consts.DeptTypeTenant,
+		},
+	})
+
+	list, total, err := NewToogoWallet().UserWalletList(tenantCtx, "", "", 1, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seenA bool
+	for _, item := range list {
+		switch item.UserId {
+		case memberA:
+			seenA = true
+		case memberB:
+			t.Errorf("租户A查询到了租户B用户 %d 的钱包", memberB)
+		}
+	}
+	if !seenA {
+		t.Errorf("租户A未查询到本租户用户 %d 的钱包", memberA)
+	}
+	if total != len(list) {
+		t.Errorf("租户A钱包总数 = %d, 列表数 = %d", total, len(list))
+	}
+}
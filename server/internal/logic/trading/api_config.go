@@ -12,11 +12,13 @@ import (
 	"hotgo/internal/consts"
 	"hotgo/internal/dao"
 	"hotgo/internal/library/contexts"
+	"hotgo/internal/library/exchange"
+	"hotgo/internal/library/vault"
 	toogoLogic "hotgo/internal/logic/toogo"
 	"hotgo/internal/model/do"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input"
-	"hotgo/utility/encrypt"
+	"hotgo/internal/service"
 	"strings"
 	"time"
 
@@ -91,6 +93,11 @@ func (s *apiConfigImpl) Create(ctx context.Context, in *input.TradingApiConfigCr
 		return 0, gerror.New("不支持的交易平台")
 	}
 
+	// 敏感操作二次验证
+	if err = service.SysTwoFactor().RequireVerified(ctx, memberId, consts.SensitiveOpApiKey, fmt.Sprintf("创建API配置: platform=%s, name=%s", in.Platform, in.ApiName)); err != nil {
+		return 0, err
+	}
+
 	// 如果设为默认，先取消其他默认配置
 	if in.IsDefault == 1 {
 		err = s.CancelOtherDefaults(ctx, memberId, 0)
@@ -105,33 +112,27 @@ func (s *apiConfigImpl) Create(ctx context.Context, in *input.TradingApiConfigCr
 		baseUrl = s.GetDefaultBaseUrl(in.Platform)
 	}
 
-	// 加密敏感信息
-	encryptedApiKey, err := encrypt.AesEncrypt(in.ApiKey)
-	if err != nil {
-		return 0, gerror.Wrap(err, "API Key加密失败")
-	}
-
-	encryptedSecretKey, err := encrypt.AesEncrypt(in.SecretKey)
+	// 信封加密敏感信息
+	sealed, err := vault.Seal(ctx, &vault.Credentials{
+		ApiKey:     in.ApiKey,
+		SecretKey:  in.SecretKey,
+		Passphrase: in.Passphrase,
+	})
 	if err != nil {
-		return 0, gerror.Wrap(err, "Secret Key加密失败")
-	}
-
-	encryptedPassphrase := ""
-	if in.Passphrase != "" {
-		encryptedPassphrase, err = encrypt.AesEncrypt(in.Passphrase)
-		if err != nil {
-			return 0, gerror.Wrap(err, "Passphrase加密失败")
-		}
+		return 0, gerror.Wrap(err, "API密钥加密失败")
 	}
 
 	data := &do.TradingApiConfig{
+		TenantId:     contexts.GetTenantId(ctx),
 		UserId:       memberId,
 		ApiName:      in.ApiName,
 		Platform:     in.Platform,
 		BaseUrl:      baseUrl,
-		ApiKey:       encryptedApiKey,
-		SecretKey:    encryptedSecretKey,
-		Passphrase:   encryptedPassphrase,
+		ApiKey:       sealed.ApiKey,
+		SecretKey:    sealed.SecretKey,
+		Passphrase:   sealed.Passphrase,
+		DataKey:      sealed.DataKey,
+		KeyVersion:   sealed.KeyVersion,
 		IsDefault:    in.IsDefault,
 		Status:       consts.StatusEnabled,
 		VerifyStatus: 0, // 未验证
@@ -166,16 +167,10 @@ func (s *apiConfigImpl) Create(ctx context.Context, in *input.TradingApiConfigCr
 
 // GetDefaultBaseUrl 根据平台获取默认API地址
 func (s *apiConfigImpl) GetDefaultBaseUrl(platform string) string {
-	switch platform {
-	case "binance":
-		return "https://fapi.binance.com"
-	case "okx":
-		return "https://www.okx.com"
-	case "gate":
-		return "https://api.gateio.ws"
-	default:
-		return ""
+	if d, ok := exchange.GetDriver(platform); ok {
+		return d.BaseUrl
 	}
+	return ""
 }
 
 // Update 更新API配置
@@ -185,6 +180,11 @@ func (s *apiConfigImpl) Update(ctx context.Context, in *input.TradingApiConfigUp
 		return gerror.New("用户未登录")
 	}
 
+	// 验证平台
+	if !s.ValidatePlatform(in.Platform) {
+		return gerror.New("不支持的交易平台")
+	}
+
 	// 验证所属
 	var config *entity.TradingApiConfig
 	err := dao.TradingApiConfig.Ctx(ctx).
@@ -200,6 +200,11 @@ func (s *apiConfigImpl) Update(ctx context.Context, in *input.TradingApiConfigUp
 		return gerror.New("配置不存在或无权限")
 	}
 
+	// 敏感操作二次验证
+	if err = service.SysTwoFactor().RequireVerified(ctx, memberId, consts.SensitiveOpApiKey, fmt.Sprintf("更新API配置: id=%d, platform=%s", config.Id, config.Platform)); err != nil {
+		return err
+	}
+
 	// 如果设为默认，先取消其他默认配置
 	if in.IsDefault == 1 {
 		err = s.CancelOtherDefaults(ctx, memberId, in.Id)
@@ -222,32 +227,36 @@ func (s *apiConfigImpl) Update(ctx context.Context, in *input.TradingApiConfigUp
 		data[dao.TradingApiConfig.Columns().Status] = in.Status
 	}
 
-	// 如果提供了新的密钥，则更新（加密）
-	if in.ApiKey != "" {
-		encryptedApiKey, err := encrypt.AesEncrypt(in.ApiKey)
+	// 如果提供了新的密钥，与未修改的字段合并后使用新的数据密钥整体重新加密
+	if in.ApiKey != "" || in.SecretKey != "" || in.Passphrase != "" {
+		creds, err := vault.ApiCredentials(ctx, config)
 		if err != nil {
-			return gerror.Wrap(err, "API Key加密失败")
+			// 旧密钥无法解密时，只有同时提供 API Key 和 Secret Key 才允许整体替换
+			if in.ApiKey == "" || in.SecretKey == "" {
+				return err
+			}
+			creds = new(vault.Credentials)
 		}
-		data[dao.TradingApiConfig.Columns().ApiKey] = encryptedApiKey
-		data[dao.TradingApiConfig.Columns().VerifyStatus] = 0 // 重新验证
-	}
-
-	if in.SecretKey != "" {
-		encryptedSecretKey, err := encrypt.AesEncrypt(in.SecretKey)
-		if err != nil {
-			return gerror.Wrap(err, "Secret Key加密失败")
+		if in.ApiKey != "" {
+			creds.ApiKey = in.ApiKey
 		}
-		data[dao.TradingApiConfig.Columns().SecretKey] = encryptedSecretKey
-		data[dao.TradingApiConfig.Columns().VerifyStatus] = 0 // 重新验证
-	}
-
-	if in.Passphrase != "" {
-		encryptedPassphrase, err := encrypt.AesEncrypt(in.Passphrase)
+		if in.SecretKey != "" {
+			creds.SecretKey = in.SecretKey
+		}
+		if in.Passphrase != "" {
+			creds.Passphrase = in.Passphrase
+		}
+		sealed, err := vault.Seal(ctx, creds)
 		if err != nil {
-			return gerror.Wrap(err, "Passphrase加密失败")
+			return gerror.Wrap(err, "API密钥加密失败")
 		}
-		data[dao.TradingApiConfig.Columns().Passphrase] = encryptedPassphrase
+		data[dao.TradingApiConfig.Columns().ApiKey] = sealed.ApiKey
+		data[dao.TradingApiConfig.Columns().SecretKey] = sealed.SecretKey
+		data[dao.TradingApiConfig.Columns().Passphrase] = sealed.Passphrase
+		data[dao.TradingApiConfig.Columns().DataKey] = sealed.DataKey
+		data[dao.TradingApiConfig.Columns().KeyVersion] = sealed.KeyVersion
 		data[dao.TradingApiConfig.Columns().VerifyStatus] = 0 // 重新验证
+		data[dao.TradingApiConfig.Columns().PermStatus] = consts.ApiPermStatusUnchecked
 	}
 
 	_, err = dao.TradingApiConfig.Ctx(ctx).
@@ -384,13 +393,14 @@ func (s *apiConfigImpl) Test(ctx context.Context, in *input.TradingApiConfigTest
 				Balance: "0.0000 USDT",
 				Latency: latency,
 			}
+			verifyStatus := s.applyPermissionCheck(ctx, config, ex, out)
 
 			// 更新验证状态为成功（但带提示信息）
 			_, _ = dao.TradingApiConfig.Ctx(ctx).
 				Where(dao.TradingApiConfig.Columns().Id, in.Id).
 				Data(g.Map{
 					dao.TradingApiConfig.Columns().LastVerifyTime: gtime.Now(),
-					dao.TradingApiConfig.Columns().VerifyStatus:   1,
+					dao.TradingApiConfig.Columns().VerifyStatus:   verifyStatus,
 					dao.TradingApiConfig.Columns().VerifyMessage:  out.Message,
 				}).
 				Update()
@@ -424,13 +434,14 @@ func (s *apiConfigImpl) Test(ctx context.Context, in *input.TradingApiConfigTest
 		Balance: balanceStr,
 		Latency: latency,
 	}
+	verifyStatus := s.applyPermissionCheck(ctx, config, ex, out)
 
-	// 更新验证状态为成功
+	// 更新验证状态（权限不符合要求时为失败）
 	_, err = dao.TradingApiConfig.Ctx(ctx).
 		Where(dao.TradingApiConfig.Columns().Id, in.Id).
 		Data(g.Map{
 			dao.TradingApiConfig.Columns().LastVerifyTime: gtime.Now(),
-			dao.TradingApiConfig.Columns().VerifyStatus:   1,
+			dao.TradingApiConfig.Columns().VerifyStatus:   verifyStatus,
 			dao.TradingApiConfig.Columns().VerifyMessage:  out.Message,
 		}).
 		Update()
@@ -480,27 +491,15 @@ func (s *apiConfigImpl) SetDefault(ctx context.Context, in *input.TradingApiConf
 	return err
 }
 
-// GetPlatforms 获取支持的平台列表
+// GetPlatforms 获取支持的平台列表（已注册的交易所插件）
 func (s *apiConfigImpl) GetPlatforms(ctx context.Context) (list []*input.TradingApiConfigPlatformsModel, err error) {
-	list = []*input.TradingApiConfigPlatformsModel{
-		{
-			Value:    "binance",
-			Label:    "Binance（币安）",
-			BaseUrl:  "https://api.binance.com",
-			NeedPass: false,
-		},
-		{
-			Value:    "okx",
-			Label:    "OKX（欧易）",
-			BaseUrl:  "https://www.okx.com",
-			NeedPass: true,
-		},
-		{
-			Value:    "gate",
-			Label:    "Gate.io",
-			BaseUrl:  "https://api.gateio.ws",
-			NeedPass: false,
-		},
+	for _, d := range exchange.Drivers() {
+		list = append(list, &input.TradingApiConfigPlatformsModel{
+			Value:    d.Platform,
+			Label:    d.Title,
+			BaseUrl:  d.BaseUrl,
+			NeedPass: d.NeedPassphrase,
+		})
 	}
 	return
 }
@@ -518,13 +517,8 @@ func (s *apiConfigImpl) MaskApiKey(apiKey string) string {
 
 // ValidatePlatform 验证平台是否支持
 func (s *apiConfigImpl) ValidatePlatform(platform string) bool {
-	validPlatforms := []string{"binance", "okx", "gate"}
-	for _, p := range validPlatforms {
-		if p == platform {
-			return true
-		}
-	}
-	return false
+	_, ok := exchange.GetDriver(platform)
+	return ok
 }
 
 // CancelOtherDefaults 取消其他默认配置
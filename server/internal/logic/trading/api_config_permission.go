@@ -0,0 +1,220 @@
+// Package trading
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description API Key 权限与IP限制检测：测试连接时检测，定时复检，权限变化时通知用户
+
+package trading
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/exchange"
+	toogoLogic "hotgo/internal/logic/toogo"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input"
+)
+
+// CheckPermissions 检测API Key权限并保存结果
+// notify=true 时与上次检测结果对比，权限发生变化则推送通知
+func (s *apiConfigImpl) CheckPermissions(ctx context.Context, config *entity.TradingApiConfig, ex exchange.Exchange, notify bool) (*input.TradingApiConfigPermissionModel, error) {
+	perm, err := exchange.InspectKey(ctx, ex)
+	if err != nil {
+		return nil, err
+	}
+
+	out := evaluateKeyPermissions(ctx, perm)
+	cols := dao.TradingApiConfig.Columns()
+	_, err = dao.TradingApiConfig.Ctx(ctx).
+		Where(cols.Id, config.Id).
+		Data(g.Map{
+			cols.PermStatus:    out.Status,
+			cols.PermFutures:   out.Futures,
+			cols.PermWithdraw:  out.Withdraw,
+			cols.IpRestricted:  boolToInt(out.IpRestricted),
+			cols.IpWhitelist:   truncateRunes(strings.Join(out.IpWhitelist, ","), 500),
+			cols.KeyExpireAt:   out.ExpireAt,
+			cols.PermMessage:   truncateRunes(strings.Join(out.Issues, "；"), 500),
+			cols.PermCheckedAt: gtime.Now(),
+		}).
+		Update()
+	if err != nil {
+		return out, gerror.Wrap(err, consts.ErrorORM)
+	}
+
+	if notify && config.PermCheckedAt != nil {
+		if changes := permissionChanges(config, out); len(changes) > 0 {
+			level := "warning"
+			if out.Status == consts.ApiPermStatusDanger {
+				level = "error"
+			}
+			content := fmt.Sprintf("API配置【%s】(%s) 权限发生变化：%s", config.ApiName, config.Platform, strings.Join(changes, "；"))
+			if len(out.Issues) > 0 {
+				content += "。当前问题：" + strings.Join(out.Issues, "；")
+			}
+			toogoLogic.GetPusher().PushSystemNotice(ctx, config.UserId, "API权限变更", content, level)
+			g.Log().Infof(ctx, "[ApiPermission] 权限变化: apiConfigId=%d, userId=%d, changes=%v", config.Id, config.UserId, changes)
+		}
+	}
+	return out, nil
+}
+
+// applyPermissionCheck 测试连接成功后检测权限，结果写入 out，返回应保存的验证状态
+// 权限检测本身失败（接口异常、交易所不支持）不影响连通性结论，仅在消息中提示
+func (s *apiConfigImpl) applyPermissionCheck(ctx context.Context, config *entity.TradingApiConfig, ex exchange.Exchange, out *input.TradingApiConfigTestModel) int {
+	perm, err := s.CheckPermissions(ctx, config, ex, false)
+	if err != nil {
+		g.Log().Warningf(ctx, "[ApiPermission] 权限检测失败: apiConfigId=%d, platform=%s, err=%v", config.Id, config.Platform, err)
+		out.Message += "（权限检测失败：" + err.Error() + "）"
+		return 1
+	}
+
+	out.Permission = perm
+	switch perm.Status {
+	case consts.ApiPermStatusDanger:
+		out.Success = false
+		out.Message = "API Key权限不符合要求：" + strings.Join(perm.Issues, "；")
+		return 2
+	case consts.ApiPermStatusWarning:
+		out.Message += "（风险提示：" + strings.Join(perm.Issues, "；") + "）"
+	}
+	return 1
+}
+
+// CheckUsable 启动机器人前校验API Key权限，最近一次检测为不可用时拒绝
+func (s *apiConfigImpl) CheckUsable(config *entity.TradingApiConfig) error {
+	if config.PermStatus != consts.ApiPermStatusDanger {
+		return nil
+	}
+	return gerror.Newf("API配置【%s】权限检测未通过：%s，请在交易所调整后重新测试连接", config.ApiName, config.PermMessage)
+}
+
+// RecheckAllPermissions 复检所有启用中的API配置，返回检测数与失败数
+func (s *apiConfigImpl) RecheckAllPermissions(ctx context.Context) (checked, failed int, err error) {
+	cols := dao.TradingApiConfig.Columns()
+	var list []*entity.TradingApiConfig
+	err = dao.TradingApiConfig.Ctx(ctx).
+		Where(cols.Status, consts.StatusEnabled).
+		WhereNull(cols.DeletedAt).
+		OrderAsc(cols.Id).
+		Scan(&list)
+	if err != nil {
+		return 0, 0, gerror.Wrap(err, consts.ErrorORM)
+	}
+
+	for _, config := range list {
+		config.Platform = strings.ToLower(strings.TrimSpace(config.Platform))
+		ex, err := toogoLogic.GetExchangeManager().GetExchangeFromConfig(ctx, config)
+		if err == nil {
+			_, err = s.CheckPermissions(ctx, config, ex, true)
+		}
+		if err != nil {
+			failed++
+			g.Log().Warningf(ctx, "[ApiPermission] 复检失败: apiConfigId=%d, platform=%s, err=%v", config.Id, config.Platform, err)
+			continue
+		}
+		checked++
+	}
+	return
+}
+
+// evaluateKeyPermissions 按策略判定权限风险
+// 配置项：exchange.keyPolicy.rejectWithdraw 开启提现权限是否判为不可用（默认是），
+// exchange.keyPolicy.expireWarnDays 过期前多少天提示（默认7天）
+func evaluateKeyPermissions(ctx context.Context, perm *exchange.KeyPermissions) *input.TradingApiConfigPermissionModel {
+	rejectWithdraw := g.Cfg().MustGet(ctx, "exchange.keyPolicy.rejectWithdraw", true).Bool()
+	expireWarnDays := g.Cfg().MustGet(ctx, "exchange.keyPolicy.expireWarnDays", 7).Int()
+
+	out := &input.TradingApiConfigPermissionModel{
+		Status:       consts.ApiPermStatusNormal,
+		Futures:      int(perm.Futures),
+		Withdraw:     int(perm.Withdraw),
+		IpRestricted: perm.IpRestricted,
+		IpWhitelist:  perm.IpWhitelist,
+	}
+	raise := func(status int, issue string) {
+		if status > out.Status {
+			out.Status = status
+		}
+		out.Issues = append(out.Issues, issue)
+	}
+
+	if perm.Read == exchange.PermDisabled {
+		raise(consts.ApiPermStatusDanger, "未开启读取权限")
+	}
+	switch perm.Futures {
+	case exchange.PermDisabled:
+		raise(consts.ApiPermStatusDanger, "未开启合约交易权限")
+	case exchange.PermUnknown:
+		raise(consts.ApiPermStatusWarning, "无法确认合约交易权限")
+	}
+	switch perm.Withdraw {
+	case exchange.PermEnabled:
+		if rejectWithdraw {
+			raise(consts.ApiPermStatusDanger, "开启了提现权限，请在交易所关闭提现权限")
+		} else {
+			raise(consts.ApiPermStatusWarning, "开启了提现权限，建议在交易所关闭")
+		}
+	case exchange.PermUnknown:
+		raise(consts.ApiPermStatusWarning, "交易所不提供提现权限查询，请确认已关闭提现权限")
+	}
+	if !perm.IpRestricted {
+		raise(consts.ApiPermStatusWarning, "未绑定IP白名单")
+	}
+	if perm.ExpireAt > 0 {
+		expireAt := time.UnixMilli(perm.ExpireAt)
+		out.ExpireAt = gtime.New(expireAt)
+		switch {
+		case time.Now().After(expireAt):
+			raise(consts.ApiPermStatusDanger, "交易权限已过期")
+		case time.Until(expireAt) < time.Duration(expireWarnDays)*24*time.Hour:
+			raise(consts.ApiPermStatusWarning, fmt.Sprintf("交易权限将于 %s 过期", out.ExpireAt.Format("Y-m-d H:i")))
+		}
+	}
+	return out
+}
+
+// permissionChanges 与上次检测结果对比
+func permissionChanges(prev *entity.TradingApiConfig, cur *input.TradingApiConfigPermissionModel) (changes []string) {
+	permText := map[int]string{0: "未开启", 1: "已开启", 2: "无法确认"}
+	if prev.PermFutures != cur.Futures {
+		changes = append(changes, fmt.Sprintf("合约交易权限 %s→%s", permText[prev.PermFutures], permText[cur.Futures]))
+	}
+	if prev.PermWithdraw != cur.Withdraw {
+		changes = append(changes, fmt.Sprintf("提现权限 %s→%s", permText[prev.PermWithdraw], permText[cur.Withdraw]))
+	}
+	if (prev.IpRestricted == 1) != cur.IpRestricted {
+		if cur.IpRestricted {
+			changes = append(changes, "已绑定IP白名单")
+		} else {
+			changes = append(changes, "IP白名单已解除")
+		}
+	}
+	if prev.PermStatus != cur.Status && cur.Status == consts.ApiPermStatusDanger {
+		changes = append(changes, "API Key已不可用于交易")
+	}
+	return
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
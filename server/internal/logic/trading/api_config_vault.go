@@ -0,0 +1,104 @@
+// Package trading
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description API配置密钥迁移：主密钥轮换后重新包装数据密钥，旧版数据迁移为信封加密
+
+package trading
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/vault"
+	"hotgo/internal/model/entity"
+)
+
+const rewrapBatchSize = 200
+
+// ApiConfigRewrapResult 重新包装结果
+type ApiConfigRewrapResult struct {
+	KeyVersion int     // 当前主密钥版本
+	Total      int     // 扫描记录数
+	Rewrapped  int     // 已迁移到当前版本
+	Skipped    int     // 已是当前版本
+	Conflicts  int     // 迁移期间被修改，已跳过（记录本身已使用当前版本重新加密）
+	Failed     []int64 // 解密失败的记录ID
+}
+
+// RewrapAll 将所有API配置（含已软删除）迁移到当前主密钥版本
+// 可在线执行：按记录乐观更新，期间被用户修改的记录以用户写入为准
+func (s *apiConfigImpl) RewrapAll(ctx context.Context) (res *ApiConfigRewrapResult, err error) {
+	v, err := vault.Instance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version, err := v.KMS().CurrentVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &ApiConfigRewrapResult{KeyVersion: version}
+	cols := dao.TradingApiConfig.Columns()
+	var lastId int64
+	for {
+		var list []*entity.TradingApiConfig
+		err = dao.TradingApiConfig.Ctx(ctx).Unscoped().
+			WhereGT(cols.Id, lastId).
+			OrderAsc(cols.Id).
+			Limit(rewrapBatchSize).
+			Scan(&list)
+		if err != nil {
+			return res, gerror.Wrap(err, "查询API配置失败")
+		}
+		if len(list) == 0 {
+			return res, nil
+		}
+
+		for _, item := range list {
+			lastId = item.Id
+			res.Total++
+			s.rewrapOne(ctx, v, item, res)
+		}
+	}
+}
+
+func (s *apiConfigImpl) rewrapOne(ctx context.Context, v *vault.Vault, item *entity.TradingApiConfig, res *ApiConfigRewrapResult) {
+	cols := dao.TradingApiConfig.Columns()
+	out, changed, err := v.Rewrap(ctx, vault.SealedFromApiConfig(item))
+	if err != nil {
+		g.Log().Warningf(ctx, "[Vault] 重新包装失败: apiConfigId=%d, err=%v", item.Id, err)
+		res.Failed = append(res.Failed, item.Id)
+		return
+	}
+	if !changed {
+		res.Skipped++
+		return
+	}
+
+	// 以读取时的密文为条件更新，避免覆盖迁移期间用户提交的新密钥
+	result, err := dao.TradingApiConfig.Ctx(ctx).Unscoped().
+		Where(cols.Id, item.Id).
+		Where(cols.DataKey, item.DataKey).
+		Where(cols.ApiKey, item.ApiKey).
+		Data(g.Map{
+			cols.ApiKey:     out.ApiKey,
+			cols.SecretKey:  out.SecretKey,
+			cols.Passphrase: out.Passphrase,
+			cols.DataKey:    out.DataKey,
+			cols.KeyVersion: out.KeyVersion,
+		}).
+		Update()
+	if err != nil {
+		g.Log().Warningf(ctx, "[Vault] 写入重新包装结果失败: apiConfigId=%d, err=%v", item.Id, err)
+		res.Failed = append(res.Failed, item.Id)
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		res.Conflicts++
+		return
+	}
+	res.Rewrapped++
+}
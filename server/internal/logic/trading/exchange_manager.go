@@ -8,26 +8,25 @@ package trading
 
 import (
 	"context"
-	"fmt"
-	"hotgo/addons/exchange"
-	"hotgo/addons/exchange_factory/service"
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
 	"hotgo/internal/library/contexts"
-	"sync"
+	"hotgo/internal/library/exchange"
+	"hotgo/internal/logic/toogo"
+	"hotgo/internal/model/entity"
+	"strings"
 
 	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
 )
 
-type exchangeManagerImpl struct {
-	factory *service.ExchangeFactory
-	cache   sync.Map // 缓存交易所实例
-}
+type exchangeManagerImpl struct{}
 
-var ExchangeManager = &exchangeManagerImpl{
-	factory: service.NewExchangeFactory(),
-}
+var ExchangeManager = &exchangeManagerImpl{}
 
-// GetExchange 获取交易所实例
-func (s *exchangeManagerImpl) GetExchange(ctx context.Context, apiConfigId int64) (exchange.IExchange, error) {
+// GetExchange 获取当前用户API配置对应的交易所实例
+// 实例由引擎侧交易所管理器按API配置缓存，交易平台实现由交易所插件注册
+func (s *exchangeManagerImpl) GetExchange(ctx context.Context, apiConfigId int64) (exchange.Exchange, error) {
 	userId := contexts.GetUserId(ctx)
 	tenantId := contexts.GetTenantId(ctx)
 
@@ -35,31 +34,39 @@ func (s *exchangeManagerImpl) GetExchange(ctx context.Context, apiConfigId int64
 		return nil, gerror.New("用户未登录")
 	}
 
-	// 从缓存中获取
-	cacheKey := getCacheKey(tenantId, userId, apiConfigId)
-	if cached, ok := s.cache.Load(cacheKey); ok {
-		return cached.(exchange.IExchange), nil
-	}
-
-	// 创建新实例
-	exchangeInst, err := s.factory.CreateExchange(ctx, apiConfigId, userId, tenantId)
+	cols := dao.TradingApiConfig.Columns()
+	var apiConfig *entity.TradingApiConfig
+	err := dao.TradingApiConfig.Ctx(ctx).
+		Where(cols.Id, apiConfigId).
+		Where(cols.UserId, userId).
+		Where(cols.TenantId, tenantId).
+		WhereNull(cols.DeletedAt).
+		Scan(&apiConfig)
 	if err != nil {
-		return nil, err
+		return nil, gerror.Wrap(err, consts.ErrorORM)
+	}
+	if apiConfig == nil {
+		return nil, gerror.New("API配置不存在或无权限")
 	}
 
-	// 缓存实例
-	s.cache.Store(cacheKey, exchangeInst)
+	// 检查状态（允许 status=0 的情况，视为未设置，兼容旧数据）
+	if apiConfig.Status == 2 {
+		return nil, gerror.New("API配置已禁用")
+	}
+	// 如果status=0（未设置），自动更新为正常状态
+	if apiConfig.Status == 0 {
+		_, _ = dao.TradingApiConfig.Ctx(ctx).
+			Where(cols.Id, apiConfigId).
+			Data(g.Map{cols.Status: 1}).
+			Update()
+		g.Log().Infof(ctx, "自动修复API配置状态: apiConfigId=%d", apiConfigId)
+	}
 
-	return exchangeInst, nil
+	apiConfig.Platform = strings.ToLower(strings.TrimSpace(apiConfig.Platform))
+	return toogo.GetExchangeManager().GetExchangeFromConfig(ctx, apiConfig)
 }
 
 // ClearCache 清除缓存
 func (s *exchangeManagerImpl) ClearCache(tenantId int64, userId int64, apiConfigId int64) {
-	cacheKey := getCacheKey(tenantId, userId, apiConfigId)
-	s.cache.Delete(cacheKey)
-}
-
-// getCacheKey 生成缓存Key
-func getCacheKey(tenantId, userId, apiConfigId int64) string {
-	return fmt.Sprintf("%d:%d:%d", tenantId, userId, apiConfigId)
+	toogo.GetExchangeManager().RemoveExchange(apiConfigId)
 }
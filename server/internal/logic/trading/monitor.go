@@ -10,10 +10,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"hotgo/addons/exchange"
 	"hotgo/api/admin/trading"
 	"hotgo/internal/dao"
 	"hotgo/internal/library/contexts"
+	"hotgo/internal/library/exchange"
 	"hotgo/internal/library/market"
 	"hotgo/internal/logic/toogo"
 	"hotgo/internal/model/do"
@@ -113,7 +113,7 @@ func (s *monitorImpl) GetTicker(ctx context.Context, in *input.TradingMonitorTic
 		Low24h:    ticker.Low24h,
 		Volume24h: ticker.Volume24h,
 		Change24h: ticker.Change24h,
-		Timestamp: ticker.Timestamp.Format("2006-01-02 15:04:05"),
+		Timestamp: time.UnixMilli(ticker.Timestamp).Format("2006-01-02 15:04:05"),
 	}
 
 	return out, nil
@@ -482,15 +482,10 @@ func (s *monitorImpl) GetRobotAnalysis(ctx context.Context, robotId int64) (*tra
 		// 重要：机器人运行中时，引擎已经持有可用的 Exchange 实例，并且 buildAccountInfo 会走引擎缓存/按需刷新。
 		// 不应强依赖 ExchangeManager 重新创建交易所实例（可能因为解密/代理/限流配置等失败），否则会导致前端长期看到 account=0 或 '--'。
 		if engine != nil {
-			res.Account = s.buildAccountInfo(ctx, nil, robot)
+			res.Account = s.buildAccountInfo(ctx, robot)
 		} else {
-			exchangeInst, err := ExchangeManager.GetExchange(ctx, robot.ApiConfigId)
-			if err == nil {
-				res.Account = s.buildAccountInfo(ctx, exchangeInst, robot)
-			} else {
-				// 账户信息获取失败：返回 nil，避免前端误显示 0.00 的假数据
-				res.Account = nil
-			}
+			// 引擎未运行时没有账户快照：返回 nil，避免前端误显示 0.00 的假数据
+			res.Account = nil
 		}
 
 		// 填充机器人配置信息
@@ -593,7 +588,7 @@ func (s *monitorImpl) buildMarketStateRealtime(platform, symbol string) *trading
 
 // buildAccountInfo 构建账户信息
 // 【优化】从RobotEngine缓存获取数据，避免频繁调用API
-func (s *monitorImpl) buildAccountInfo(ctx context.Context, exchangeInst exchange.IExchange, robot *entity.TradingRobot) *trading.RobotAccountInfo {
+func (s *monitorImpl) buildAccountInfo(ctx context.Context, robot *entity.TradingRobot) *trading.RobotAccountInfo {
 	// 【优化】从RobotEngine获取缓存数据，避免每秒调用API
 	engine := toogo.GetRobotTaskManager().GetEngine(robot.Id)
 	if engine == nil {
@@ -1349,7 +1344,7 @@ func (s *monitorImpl) GetKline(ctx context.Context, apiConfigId int64, symbol, i
 	}
 
 	// 获取K线数据
-	klines, err := exchangeInst.GetKline(ctx, symbol, interval, limit)
+	klines, err := exchangeInst.GetKlines(ctx, symbol, interval, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -1358,13 +1353,13 @@ func (s *monitorImpl) GetKline(ctx context.Context, apiConfigId int64, symbol, i
 	list := make([]*trading.KlineDataItem, 0, len(klines))
 	for _, k := range klines {
 		list = append(list, &trading.KlineDataItem{
-			OpenTime:  k.OpenTime.UnixMilli(),
+			OpenTime:  k.OpenTime,
 			Open:      k.Open,
 			High:      k.High,
 			Low:       k.Low,
 			Close:     k.Close,
 			Volume:    k.Volume,
-			CloseTime: k.CloseTime.UnixMilli(),
+			CloseTime: k.CloseTime,
 		})
 	}
 
@@ -10,6 +10,8 @@ import (
 	"context"
 	"fmt"
 	"hotgo/internal/dao"
+	"hotgo/internal/library/exchange"
+	"hotgo/internal/logic/toogo"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input"
 	"hotgo/utility/encrypt"
@@ -27,14 +29,19 @@ import (
 
 type proxyConfigImpl struct{}
 
-// Get 获取代理配置（全局配置，user_id=0表示全局）
-func (s *proxyConfigImpl) Get(ctx context.Context, in *input.TradingProxyConfigGetInp) (out *input.TradingProxyConfigModel, err error) {
-	var config *entity.TradingProxyConfig
+// defaultConfig 默认代理：代理池中 id 最小的全局代理（user_id=0），兼容原单代理配置
+func (s *proxyConfigImpl) defaultConfig(ctx context.Context) (config *entity.TradingProxyConfig, err error) {
 	err = dao.TradingProxyConfig.Ctx(ctx).
 		Where(dao.TradingProxyConfig.Columns().UserId, 0).   // user_id=0 表示全局配置
 		Where(dao.TradingProxyConfig.Columns().TenantId, 0). // tenant_id=0 表示全局配置
+		OrderAsc(dao.TradingProxyConfig.Columns().Id).
 		Scan(&config)
+	return
+}
 
+// Get 获取代理配置（全局默认代理，user_id=0表示全局）
+func (s *proxyConfigImpl) Get(ctx context.Context, in *input.TradingProxyConfigGetInp) (out *input.TradingProxyConfigModel, err error) {
+	config, err := s.defaultConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +78,10 @@ func (s *proxyConfigImpl) Get(ctx context.Context, in *input.TradingProxyConfigG
 
 // Save 保存代理配置（全局配置，user_id=0表示全局）
 func (s *proxyConfigImpl) Save(ctx context.Context, in *input.TradingProxyConfigSaveInp) error {
+	if err := toogo.RequirePlatform(ctx); err != nil {
+		return err
+	}
+
 	// 加密密码
 	encryptedPassword := ""
 	if in.Password != "" {
@@ -81,13 +92,8 @@ func (s *proxyConfigImpl) Save(ctx context.Context, in *input.TradingProxyConfig
 		}
 	}
 
-	// 检查是否已存在全局配置（user_id=0 且 tenant_id=0 表示全局配置）
-	var existConfig *entity.TradingProxyConfig
-	err := dao.TradingProxyConfig.Ctx(ctx).
-		Where(dao.TradingProxyConfig.Columns().UserId, 0).   // user_id=0 表示全局配置
-		Where(dao.TradingProxyConfig.Columns().TenantId, 0). // tenant_id=0 表示全局配置
-		Scan(&existConfig)
-
+	// 检查是否已存在全局默认代理（user_id=0 且 tenant_id=0 表示全局配置）
+	existConfig, err := s.defaultConfig(ctx)
 	if err != nil {
 		return err
 	}
@@ -118,8 +124,7 @@ func (s *proxyConfigImpl) Save(ctx context.Context, in *input.TradingProxyConfig
 	} else {
 		// 更新现有配置
 		_, err = dao.TradingProxyConfig.Ctx(ctx).
-			Where(dao.TradingProxyConfig.Columns().UserId, 0).
-			Where(dao.TradingProxyConfig.Columns().TenantId, 0).
+			Where(dao.TradingProxyConfig.Columns().Id, existConfig.Id).
 			Data(data).
 			Update()
 		if err != nil {
@@ -129,7 +134,7 @@ func (s *proxyConfigImpl) Save(ctx context.Context, in *input.TradingProxyConfig
 		g.Log().Infof(ctx, "[ProxyConfig] 更新全局代理配置成功: proxyType=%s, proxyAddress=%s", in.ProxyType, in.ProxyAddress)
 	}
 
-	return nil
+	return toogo.ReloadProxyPool(ctx)
 }
 
 // Test 测试代理连接
@@ -154,45 +159,48 @@ func (s *proxyConfigImpl) Test(ctx context.Context, in *input.TradingProxyConfig
 		testStatus = 1 // 成功
 	}
 
-	_, _ = dao.TradingProxyConfig.Ctx(ctx).
-		Where(dao.TradingProxyConfig.Columns().UserId, 0).   // user_id=0 表示全局配置
-		Where(dao.TradingProxyConfig.Columns().TenantId, 0). // tenant_id=0 表示全局配置
-		Data(g.Map{
-			dao.TradingProxyConfig.Columns().LastTestTime: gtime.Now(),
-			dao.TradingProxyConfig.Columns().TestStatus:   testStatus,
-			dao.TradingProxyConfig.Columns().TestMessage:  message,
-		}).
-		Update()
+	if config, _ := s.defaultConfig(ctx); config != nil {
+		_, _ = dao.TradingProxyConfig.Ctx(ctx).
+			Where(dao.TradingProxyConfig.Columns().Id, config.Id).
+			Data(g.Map{
+				dao.TradingProxyConfig.Columns().LastTestTime: gtime.Now(),
+				dao.TradingProxyConfig.Columns().TestStatus:   testStatus,
+				dao.TradingProxyConfig.Columns().TestMessage:  message,
+			}).
+			Update()
+	}
 
 	return
 }
 
 // Toggle 切换启用状态（全局配置）
 func (s *proxyConfigImpl) Toggle(ctx context.Context, in *input.TradingProxyConfigToggleInp) error {
-	// 检查全局配置是否存在
-	count, err := dao.TradingProxyConfig.Ctx(ctx).
-		Where(dao.TradingProxyConfig.Columns().UserId, 0).   // user_id=0 表示全局配置
-		Where(dao.TradingProxyConfig.Columns().TenantId, 0). // tenant_id=0 表示全局配置
-		Count()
+	if err := toogo.RequirePlatform(ctx); err != nil {
+		return err
+	}
 
+	// 检查全局默认代理是否存在
+	config, err := s.defaultConfig(ctx)
 	if err != nil {
 		return err
 	}
 
-	if count == 0 {
+	if config == nil {
 		return gerror.New("请先保存代理配置")
 	}
 
 	// 更新启用状态
 	_, err = dao.TradingProxyConfig.Ctx(ctx).
-		Where(dao.TradingProxyConfig.Columns().UserId, 0).
-		Where(dao.TradingProxyConfig.Columns().TenantId, 0).
+		Where(dao.TradingProxyConfig.Columns().Id, config.Id).
 		Data(g.Map{
 			dao.TradingProxyConfig.Columns().Enabled: in.Enabled,
 		}).
 		Update()
+	if err != nil {
+		return err
+	}
 
-	return err
+	return toogo.ReloadProxyPool(ctx)
 }
 
 // testProxyConnection 测试代理连接
@@ -331,239 +339,17 @@ func (s *proxyConfigImpl) testHTTPProxy(proxyURL *url.URL) (string, error) {
 	return ip, nil
 }
 
-// GetProxyDialer 获取代理拨号器（供其他模块使用，使用全局配置）
+// GetProxyDialer 获取代理拨号器（供其他模块使用，经代理池中的全局代理，连接失败自动切换）
 func (s *proxyConfigImpl) GetProxyDialer(ctx context.Context) (proxy.Dialer, error) {
-	// 获取全局代理配置
-	var config *entity.TradingProxyConfig
-	err := dao.TradingProxyConfig.Ctx(ctx).
-		Where(dao.TradingProxyConfig.Columns().UserId, 0).   // user_id=0 表示全局配置
-		Where(dao.TradingProxyConfig.Columns().TenantId, 0). // tenant_id=0 表示全局配置
-		Where(dao.TradingProxyConfig.Columns().Enabled, 1).
-		Scan(&config)
-
-	if err != nil {
-		return nil, err
-	}
-
-	// 如果没有启用代理，返回直连
-	if config == nil {
-		return proxy.Direct, nil
-	}
-
-	// 如果是HTTP代理，不支持Dialer方式
-	if config.ProxyType == "http" {
-		return nil, gerror.New("HTTP代理不支持Dialer方式，请使用GetProxyTransport")
-	}
-
-	// 解密密码
-	var auth *proxy.Auth
-	if config.AuthEnabled == 1 && config.Username != "" {
-		password := ""
-		if config.Password != "" {
-			password, err = encrypt.AesDecrypt(config.Password)
-			if err != nil {
-				return nil, gerror.Wrap(err, "密码解密失败")
-			}
-		}
-
-		auth = &proxy.Auth{
-			User:     config.Username,
-			Password: password,
-		}
-	}
-
-	// 创建SOCKS5拨号器
-	dialer, err := proxy.SOCKS5("tcp", config.ProxyAddress, auth, proxy.Direct)
-	if err != nil {
-		return nil, err
-	}
-
-	return dialer, nil
+	return exchange.GetProxyPool().NetDialer(toogo.GlobalProxyBinding(ctx)), nil
 }
 
-// GetWebSocketDialer 获取WebSocket代理拨号器（支持HTTP和SOCKS5代理）
+// GetWebSocketDialer 获取WebSocket代理拨号器（支持HTTP和SOCKS5代理，连接失败自动切换）
 func (s *proxyConfigImpl) GetWebSocketDialer(ctx context.Context) (func(network, addr string) (net.Conn, error), error) {
-	// 获取全局代理配置
-	var config *entity.TradingProxyConfig
-	err := dao.TradingProxyConfig.Ctx(ctx).
-		Where(dao.TradingProxyConfig.Columns().UserId, 0).   // user_id=0 表示全局配置
-		Where(dao.TradingProxyConfig.Columns().TenantId, 0). // tenant_id=0 表示全局配置
-		Where(dao.TradingProxyConfig.Columns().Enabled, 1).
-		Scan(&config)
-
-	if err != nil {
-		return nil, err
-	}
-
-	// 如果没有启用代理，返回nil（使用默认拨号器）
-	if config == nil {
-		return nil, nil
-	}
-
-	// 解密密码
-	password := ""
-	if config.AuthEnabled == 1 && config.Password != "" {
-		password, err = encrypt.AesDecrypt(config.Password)
-		if err != nil {
-			return nil, gerror.Wrap(err, "密码解密失败")
-		}
-	}
-
-	// 根据代理类型创建拨号器
-	if config.ProxyType == "http" || config.ProxyType == "https" {
-		// HTTP代理：使用CONNECT方法建立隧道
-		return func(network, addr string) (net.Conn, error) {
-			// 连接到代理服务器
-			proxyConn, err := net.DialTimeout("tcp", config.ProxyAddress, 30*time.Second)
-			if err != nil {
-				return nil, fmt.Errorf("连接HTTP代理失败: %v", err)
-			}
-
-			// 构建CONNECT请求
-			connectReq := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\n", addr, addr)
-
-			// 如果需要认证
-			if config.AuthEnabled == 1 && config.Username != "" {
-				auth := config.Username
-				if password != "" {
-					auth += ":" + password
-				}
-				encoded := base64Encode([]byte(auth))
-				connectReq += fmt.Sprintf("Proxy-Authorization: Basic %s\r\n", encoded)
-			}
-
-			connectReq += "\r\n"
-
-			// 发送CONNECT请求
-			_, err = proxyConn.Write([]byte(connectReq))
-			if err != nil {
-				proxyConn.Close()
-				return nil, fmt.Errorf("发送CONNECT请求失败: %v", err)
-			}
-
-			// 读取响应
-			response := make([]byte, 1024)
-			n, err := proxyConn.Read(response)
-			if err != nil {
-				proxyConn.Close()
-				return nil, fmt.Errorf("读取代理响应失败: %v", err)
-			}
-
-			// 检查响应状态
-			respStr := string(response[:n])
-			if !strings.Contains(respStr, "200") {
-				proxyConn.Close()
-				return nil, fmt.Errorf("HTTP代理CONNECT失败: %s", respStr)
-			}
-
-			return proxyConn, nil
-		}, nil
-	} else if config.ProxyType == "socks5" {
-		// SOCKS5代理
-		var auth *proxy.Auth
-		if config.AuthEnabled == 1 && config.Username != "" {
-			auth = &proxy.Auth{
-				User:     config.Username,
-				Password: password,
-			}
-		}
-
-		dialer, err := proxy.SOCKS5("tcp", config.ProxyAddress, auth, proxy.Direct)
-		if err != nil {
-			return nil, err
-		}
-
-		return dialer.Dial, nil
-	}
-
-	return nil, gerror.Newf("不支持的代理类型: %s", config.ProxyType)
+	return exchange.GetProxyPool().Dialer(toogo.GlobalProxyBinding(ctx)), nil
 }
 
-// base64Encode 简单的base64编码
-func base64Encode(data []byte) string {
-	const base64Chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
-	var result strings.Builder
-
-	for i := 0; i < len(data); i += 3 {
-		var n uint32
-		var padding int
-
-		n = uint32(data[i]) << 16
-		if i+1 < len(data) {
-			n |= uint32(data[i+1]) << 8
-		} else {
-			padding++
-		}
-		if i+2 < len(data) {
-			n |= uint32(data[i+2])
-		} else {
-			padding++
-		}
-
-		result.WriteByte(base64Chars[(n>>18)&0x3F])
-		result.WriteByte(base64Chars[(n>>12)&0x3F])
-		if padding < 2 {
-			result.WriteByte(base64Chars[(n>>6)&0x3F])
-		} else {
-			result.WriteByte('=')
-		}
-		if padding < 1 {
-			result.WriteByte(base64Chars[n&0x3F])
-		} else {
-			result.WriteByte('=')
-		}
-	}
-
-	return result.String()
-}
-
-// GetProxyTransport 获取代理传输层（供HTTP客户端使用，使用全局配置）
+// GetProxyTransport 获取代理传输层（供HTTP客户端使用，经代理池中的全局代理）
 func (s *proxyConfigImpl) GetProxyTransport(ctx context.Context) (*http.Transport, error) {
-	// 获取全局代理配置
-	var config *entity.TradingProxyConfig
-	err := dao.TradingProxyConfig.Ctx(ctx).
-		Where(dao.TradingProxyConfig.Columns().UserId, 0).   // user_id=0 表示全局配置
-		Where(dao.TradingProxyConfig.Columns().TenantId, 0). // tenant_id=0 表示全局配置
-		Where(dao.TradingProxyConfig.Columns().Enabled, 1).
-		Scan(&config)
-
-	if err != nil {
-		return nil, err
-	}
-
-	// 如果没有启用代理，返回默认传输层
-	if config == nil {
-		return &http.Transport{}, nil
-	}
-
-	// 构建代理URL
-	var proxyURL *url.URL
-	if config.AuthEnabled == 1 && config.Username != "" {
-		password := ""
-		if config.Password != "" {
-			password, err = encrypt.AesDecrypt(config.Password)
-			if err != nil {
-				return nil, gerror.Wrap(err, "密码解密失败")
-			}
-		}
-
-		if password != "" {
-			proxyURL, _ = url.Parse(fmt.Sprintf("%s://%s:%s@%s", config.ProxyType, config.Username, password, config.ProxyAddress))
-		} else {
-			proxyURL, _ = url.Parse(fmt.Sprintf("%s://%s@%s", config.ProxyType, config.Username, config.ProxyAddress))
-		}
-	} else {
-		proxyURL, _ = url.Parse(fmt.Sprintf("%s://%s", config.ProxyType, config.ProxyAddress))
-	}
-
-	// 创建传输层
-	transport := &http.Transport{
-		Proxy: http.ProxyURL(proxyURL),
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-	}
-
-	return transport, nil
+	return exchange.GetProxyPool().Transport(toogo.GlobalProxyBinding(ctx)).Clone(), nil
 }
@@ -0,0 +1,148 @@
+// Package trading
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+
+package trading
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"hotgo/internal/consts"
+	"hotgo/internal/dao"
+	"hotgo/internal/library/exchange"
+	"hotgo/internal/logic/toogo"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input"
+	"hotgo/utility/encrypt"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// PoolList 代理池列表（含运行时健康状态与粘性分配数）
+func (s *proxyConfigImpl) PoolList(ctx context.Context, in *input.TradingProxyPoolListInp) (list []*input.TradingProxyPoolModel, err error) {
+	var rows []*entity.TradingProxyConfig
+	cols := dao.TradingProxyConfig.Columns()
+	if err = dao.TradingProxyConfig.Ctx(ctx).
+		OrderAsc(cols.UserId).
+		OrderAsc(cols.Id).
+		Scan(&rows); err != nil {
+		return nil, gerror.Wrap(err, consts.ErrorORM)
+	}
+
+	runtime := make(map[int64]*exchange.ProxyMemberStatus)
+	for _, st := range exchange.GetProxyPool().Status() {
+		runtime[st.Id] = st
+	}
+
+	defaultId := int64(0)
+	list = make([]*input.TradingProxyPoolModel, 0, len(rows))
+	for _, row := range rows {
+		if defaultId == 0 && row.UserId == 0 && row.TenantId == 0 {
+			defaultId = row.Id
+		}
+		item := &input.TradingProxyPoolModel{
+			Id:           row.Id,
+			UserId:       row.UserId,
+			Name:         row.Name,
+			Enabled:      row.Enabled,
+			ProxyType:    row.ProxyType,
+			ProxyAddress: row.ProxyAddress,
+			AuthEnabled:  row.AuthEnabled,
+			Username:     row.Username,
+			Weight:       row.Weight,
+			IsDefault:    row.Id == defaultId,
+			LastTestTime: row.LastTestTime,
+			TestStatus:   row.TestStatus,
+			TestMessage:  row.TestMessage,
+			Latency:      row.Latency,
+			CreatedAt:    row.CreatedAt,
+			UpdatedAt:    row.UpdatedAt,
+		}
+		if row.VenueLatency != "" {
+			_ = gjson.DecodeTo(row.VenueLatency, &item.VenueLatency)
+		}
+		if st, ok := runtime[row.Id]; ok {
+			item.Healthy = st.Healthy
+			item.Fails = st.Fails
+			item.LastError = st.LastError
+			item.Assigned = st.Assigned
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+// PoolEdit 新增/修改代理池成员
+func (s *proxyConfigImpl) PoolEdit(ctx context.Context, in *input.TradingProxyPoolEditInp) (err error) {
+	in.ProxyAddress = strings.TrimSpace(in.ProxyAddress)
+	if _, _, err = net.SplitHostPort(in.ProxyAddress); err != nil {
+		return gerror.Newf("代理地址格式错误，应为 host:port：%s", in.ProxyAddress)
+	}
+	if in.Weight <= 0 {
+		in.Weight = 1
+	}
+
+	cols := dao.TradingProxyConfig.Columns()
+	data := g.Map{
+		cols.UserId:       in.UserId,
+		cols.Name:         in.Name,
+		cols.Enabled:      in.Enabled,
+		cols.ProxyType:    in.ProxyType,
+		cols.ProxyAddress: in.ProxyAddress,
+		cols.AuthEnabled:  in.AuthEnabled,
+		cols.Username:     in.Username,
+		cols.Weight:       in.Weight,
+	}
+	if in.Password != "" {
+		encrypted, err := encrypt.AesEncrypt(in.Password)
+		if err != nil {
+			return gerror.Wrap(err, "密码加密失败")
+		}
+		data[cols.Password] = encrypted
+	}
+
+	if in.Id > 0 {
+		count, err := dao.TradingProxyConfig.Ctx(ctx).Where(cols.Id, in.Id).Count()
+		if err != nil {
+			return gerror.Wrap(err, consts.ErrorORM)
+		}
+		if count == 0 {
+			return gerror.New("代理不存在")
+		}
+		if _, err = dao.TradingProxyConfig.Ctx(ctx).Where(cols.Id, in.Id).Data(data).Update(); err != nil {
+			return gerror.Wrap(err, "更新代理失败")
+		}
+	} else {
+		data[cols.TenantId] = 0
+		if _, err = dao.TradingProxyConfig.Ctx(ctx).Data(data).Insert(); err != nil {
+			return gerror.Wrap(err, "创建代理失败")
+		}
+	}
+
+	g.Log().Infof(ctx, "[ProxyPool] 保存代理: id=%d, userId=%d, name=%s, address=%s", in.Id, in.UserId, in.Name, in.ProxyAddress)
+	return toogo.ReloadProxyPool(ctx)
+}
+
+// PoolDelete 删除代理池成员
+func (s *proxyConfigImpl) PoolDelete(ctx context.Context, in *input.TradingProxyPoolDeleteInp) error {
+	if _, err := dao.TradingProxyConfig.Ctx(ctx).
+		Where(dao.TradingProxyConfig.Columns().Id, in.Id).
+		Delete(); err != nil {
+		return gerror.Wrap(err, "删除代理失败")
+	}
+	return toogo.ReloadProxyPool(ctx)
+}
+
+// PoolCheck 立即执行代理池健康检查
+func (s *proxyConfigImpl) PoolCheck(ctx context.Context) (list []*input.TradingProxyPoolModel, err error) {
+	if _, err = toogo.CheckProxyPool(ctx); err != nil {
+		return nil, err
+	}
+	return s.PoolList(ctx, &input.TradingProxyPoolListInp{})
+}
@@ -9,6 +9,7 @@ package trading
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"math"
 	"strings"
 	"time"
@@ -21,6 +22,7 @@ import (
 	"hotgo/internal/logic/toogo"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input"
+	"hotgo/internal/service"
 
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -168,22 +170,34 @@ func (s *robotImpl) Create(ctx context.Context, in *input.TradingRobotCreateInp)
 		return 0, gerror.New("交易对不能为空")
 	}
 
-	// 校验策略组：平台/币对必须与机器人一致（每机器人只绑定一个平台API账户）
-	var group *entity.TradingStrategyGroup
-	_ = g.DB().Model("hg_trading_strategy_group").Ctx(ctx).
-		Where("id", in.StrategyGroupId).
-		Scan(&group)
-	if group == nil || group.Id == 0 {
-		return 0, gerror.New("策略组不存在，请重新选择")
-	}
-	if canonicalPlatform(group.Exchange) != platform {
-		return 0, gerror.Newf("策略组平台(%s)与API平台(%s)不一致，无法创建机器人", group.Exchange, platform)
-	}
-	if canonicalSymbol(group.Symbol) != symbol {
-		return 0, gerror.Newf("策略组交易对(%s)与机器人交易对(%s)不一致，无法创建机器人", group.Symbol, symbol)
+	// 网格模式：不绑定策略组，按网格配置挂单
+	robotMode := toogo.RobotModeSignal
+	gridJSON := ""
+	if in.RobotMode == toogo.RobotModeGrid {
+		robotMode = toogo.RobotModeGrid
+		if gridJSON, err = normalizeGridConfig(in.GridConfig); err != nil {
+			return 0, err
+		}
 	}
-	if group.IsActive == 0 {
-		return 0, gerror.New("该策略组已禁用，无法创建机器人")
+
+	// 校验策略组：平台/币对必须与机器人一致（每机器人只绑定一个平台API账户）
+	if robotMode == toogo.RobotModeSignal {
+		var group *entity.TradingStrategyGroup
+		_ = dao.TradingStrategyGroup.Ctx(ctx).
+			Where("id", in.StrategyGroupId).
+			Scan(&group)
+		if group == nil || group.Id == 0 {
+			return 0, gerror.New("策略组不存在，请重新选择")
+		}
+		if canonicalPlatform(group.Exchange) != platform {
+			return 0, gerror.Newf("策略组平台(%s)与API平台(%s)不一致，无法创建机器人", group.Exchange, platform)
+		}
+		if canonicalSymbol(group.Symbol) != symbol {
+			return 0, gerror.Newf("策略组交易对(%s)与机器人交易对(%s)不一致，无法创建机器人", group.Symbol, symbol)
+		}
+		if group.IsActive == 0 {
+			return 0, gerror.New("该策略组已禁用，无法创建机器人")
+		}
 	}
 
 	// 【新增】校验：每个API配置只能绑定一个未删除的机器人
@@ -201,7 +215,7 @@ func (s *robotImpl) Create(ctx context.Context, in *input.TradingRobotCreateInp)
 	}
 
 	// v2 规则：创建时只绑定策略组ID + 市场状态→风险偏好映射；交易参数/止盈止损运行时从策略模板加载
-	if in.StrategyGroupId == 0 {
+	if robotMode == toogo.RobotModeSignal && in.StrategyGroupId == 0 {
 		return 0, gerror.New("策略组ID不能为空，请选择策略组")
 	}
 
@@ -246,6 +260,7 @@ func (s *robotImpl) Create(ctx context.Context, in *input.TradingRobotCreateInp)
 	}
 
 	insertData := g.Map{
+		"tenant_id":          contexts.GetTenantId(ctx),
 		"user_id":            memberId,
 		"robot_name":         in.RobotName,
 		"api_config_id":      in.ApiConfigId,
@@ -266,6 +281,10 @@ func (s *robotImpl) Create(ctx context.Context, in *input.TradingRobotCreateInp)
 		"dual_side_position": dualSide,
 		"status":             1, // 未启动
 		"remark":             string(mappingJSON),
+		"robot_mode":         robotMode,
+	}
+	if gridJSON != "" {
+		insertData["grid_config"] = gridJSON
 	}
 
 	// 定时开关（可选）
@@ -309,6 +328,57 @@ func (s *robotImpl) Create(ctx context.Context, in *input.TradingRobotCreateInp)
 	return id, nil
 }
 
+// normalizeGridConfig 校验网格配置并返回规范化后的JSON
+func normalizeGridConfig(in *input.TradingGridConfigInp) (string, error) {
+	if in == nil {
+		return "", gerror.New("网格模式必须填写网格配置")
+	}
+	cfg := &toogo.GridConfig{
+		LowerPrice:      in.LowerPrice,
+		UpperPrice:      in.UpperPrice,
+		GridCount:       in.GridCount,
+		Spacing:         in.Spacing,
+		Bias:            in.Bias,
+		QuantityPerGrid: in.QuantityPerGrid,
+		Leverage:        in.Leverage,
+	}
+	if err := cfg.Normalize(); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return "", gerror.Wrap(err, "网格配置JSON转换失败")
+	}
+	return string(b), nil
+}
+
+// checkRobotModeConfig 启动前按运行模式校验：信号模式校验策略组，网格模式校验网格配置
+func checkRobotModeConfig(ctx context.Context, robot *entity.TradingRobot, platform, symbol, action string) error {
+	if robot.RobotMode == toogo.RobotModeGrid {
+		if _, err := toogo.ParseGridConfig(robot.GridConfig); err != nil {
+			return gerror.Wrapf(err, "%s失败", action)
+		}
+		return nil
+	}
+	if robot.StrategyGroupId <= 0 {
+		return gerror.Newf("机器人未绑定策略组，无法%s", action)
+	}
+	var group *entity.TradingStrategyGroup
+	_ = dao.TradingStrategyGroup.Ctx(ctx).
+		Where("id", robot.StrategyGroupId).
+		Scan(&group)
+	if group == nil || group.Id == 0 {
+		return gerror.Newf("机器人绑定的策略组不存在，无法%s", action)
+	}
+	if canonicalPlatform(group.Exchange) != platform {
+		return gerror.Newf("%s失败：策略组平台(%s)与API平台(%s)不一致", action, group.Exchange, platform)
+	}
+	if canonicalSymbol(group.Symbol) != symbol {
+		return gerror.Newf("%s失败：策略组交易对(%s)与机器人交易对(%s)不一致", action, group.Symbol, symbol)
+	}
+	return nil
+}
+
 // Update 更新机器人
 func (s *robotImpl) Update(ctx context.Context, in *input.TradingRobotUpdateInp) error {
 	memberId := contexts.GetUserId(ctx)
@@ -339,6 +409,7 @@ func (s *robotImpl) Update(ctx context.Context, in *input.TradingRobotUpdateInp)
 		in.Leverage != 0 || in.MarginPercent != 0 ||
 		in.StopLossPercent != 0 || in.ProfitRetreatPercent != 0 || in.AutoStartRetreatPercent != 0 ||
 		in.UseMonitorSignal != 0 || in.AutoMarketState != 0 ||
+		in.GridConfig != nil ||
 		in.Remark != ""
 
 	if robot.Status == 2 && hasOtherUpdate {
@@ -401,6 +472,18 @@ func (s *robotImpl) Update(ctx context.Context, in *input.TradingRobotUpdateInp)
 		data["dual_side_position"] = *in.DualSidePosition
 	}
 
+	// 网格配置：仅网格模式可改（运行中已被上方拦截）
+	if in.GridConfig != nil {
+		if robot.RobotMode != toogo.RobotModeGrid {
+			return gerror.New("非网格模式机器人不能设置网格配置")
+		}
+		gridJSON, err := normalizeGridConfig(in.GridConfig)
+		if err != nil {
+			return err
+		}
+		data["grid_config"] = gridJSON
+	}
+
 	// remark：v2 remark 用于映射JSON，不建议直接写入普通备注；但保留管理员覆盖入口
 	if in.Remark != "" {
 		data["remark"] = in.Remark
@@ -454,6 +537,11 @@ func (s *robotImpl) Delete(ctx context.Context, in *input.TradingRobotDeleteInp)
 		return gerror.New("机器人不存在或无权限")
 	}
 
+	// 组合机器人的腿需随组合一起删除
+	if robot.BasketId > 0 {
+		return gerror.New("该机器人属于组合机器人，请通过组合删除")
+	}
+
 	// 运行中的机器人不能删除
 	if robot.Status == 2 {
 		return gerror.New("运行中的机器人不能删除，请先停止")
@@ -592,6 +680,9 @@ func (s *robotImpl) Start(ctx context.Context, in *input.TradingRobotStartInp) e
 	if apiConfig == nil {
 		return gerror.New("API配置不可用")
 	}
+	if err = ApiConfig.CheckUsable(apiConfig); err != nil {
+		return err
+	}
 
 	// 【强一致修复】启动前强制对齐 robot.exchange/robot.symbol 到统一口径（避免平台/币对不一致导致全链路断）
 	platform := canonicalPlatform(apiConfig.Platform)
@@ -603,22 +694,9 @@ func (s *robotImpl) Start(ctx context.Context, in *input.TradingRobotStartInp) e
 		return gerror.New("机器人交易对为空，请检查机器人配置")
 	}
 
-	// 启动前校验策略组：平台/交易对必须一致
-	if robot.StrategyGroupId <= 0 {
-		return gerror.New("机器人未绑定策略组，无法启动")
-	}
-	var group *entity.TradingStrategyGroup
-	_ = g.DB().Model("hg_trading_strategy_group").Ctx(ctx).
-		Where("id", robot.StrategyGroupId).
-		Scan(&group)
-	if group == nil || group.Id == 0 {
-		return gerror.New("机器人绑定的策略组不存在，无法启动")
-	}
-	if canonicalPlatform(group.Exchange) != platform {
-		return gerror.Newf("启动失败：策略组平台(%s)与API平台(%s)不一致", group.Exchange, platform)
-	}
-	if canonicalSymbol(group.Symbol) != symbol {
-		return gerror.Newf("启动失败：策略组交易对(%s)与机器人交易对(%s)不一致", group.Symbol, symbol)
+	// 启动前校验策略组：平台/交易对必须一致（网格模式校验网格配置）
+	if err = checkRobotModeConfig(ctx, robot, platform, symbol, "启动"); err != nil {
+		return err
 	}
 
 	now := gtime.Now()
@@ -649,6 +727,7 @@ func (s *robotImpl) Start(ctx context.Context, in *input.TradingRobotStartInp) e
 		if cnt == 0 {
 			_, _ = dao.TradingRobotRunSession.Ctx(ctx).Data(g.Map{
 				"robot_id":   robot.Id,
+				"basket_id":  robot.BasketId,
 				"user_id":    memberId,
 				"exchange":   platform,
 				"symbol":     symbol,
@@ -705,6 +784,9 @@ func (s *robotImpl) Restart(ctx context.Context, in *input.TradingRobotStartInp)
 	if apiConfig == nil {
 		return gerror.New("API配置不可用")
 	}
+	if err = ApiConfig.CheckUsable(apiConfig); err != nil {
+		return err
+	}
 
 	// 对齐口径（修复历史脏数据）
 	platform := canonicalPlatform(apiConfig.Platform)
@@ -716,22 +798,9 @@ func (s *robotImpl) Restart(ctx context.Context, in *input.TradingRobotStartInp)
 		return gerror.New("机器人交易对为空，请检查机器人配置")
 	}
 
-	// 启动前校验策略组：平台/交易对必须一致
-	if robot.StrategyGroupId <= 0 {
-		return gerror.New("机器人未绑定策略组，无法重启")
-	}
-	var group *entity.TradingStrategyGroup
-	_ = g.DB().Model("hg_trading_strategy_group").Ctx(ctx).
-		Where("id", robot.StrategyGroupId).
-		Scan(&group)
-	if group == nil || group.Id == 0 {
-		return gerror.New("机器人绑定的策略组不存在，无法重启")
-	}
-	if canonicalPlatform(group.Exchange) != platform {
-		return gerror.Newf("重启失败：策略组平台(%s)与API平台(%s)不一致", group.Exchange, platform)
-	}
-	if canonicalSymbol(group.Symbol) != symbol {
-		return gerror.Newf("重启失败：策略组交易对(%s)与机器人交易对(%s)不一致", group.Symbol, symbol)
+	// 启动前校验策略组：平台/交易对必须一致（网格模式校验网格配置）
+	if err = checkRobotModeConfig(ctx, robot, platform, symbol, "重启"); err != nil {
+		return err
 	}
 
 	now := gtime.Now()
@@ -759,6 +828,7 @@ func (s *robotImpl) Restart(ctx context.Context, in *input.TradingRobotStartInp)
 		if cnt == 0 {
 			_, _ = dao.TradingRobotRunSession.Ctx(ctx).Data(g.Map{
 				"robot_id":   robot.Id,
+				"basket_id":  robot.BasketId,
 				"user_id":    memberId,
 				"exchange":   platform,
 				"symbol":     symbol,
@@ -1001,6 +1071,50 @@ func (s *robotImpl) Stop(ctx context.Context, in *input.TradingRobotStopInp) err
 	return nil
 }
 
+// StopAll 停止当前用户全部未停用的机器人
+// 逐个走 Stop 的持仓检查，有真实持仓的机器人停止失败并在结果中返回原因，不影响其他机器人
+func (s *robotImpl) StopAll(ctx context.Context, in *input.TradingRobotStopAllInp) (out *input.TradingRobotStopAllModel, err error) {
+	memberId := contexts.GetUserId(ctx)
+	if memberId <= 0 {
+		return nil, gerror.New("用户未登录")
+	}
+
+	var robots []*entity.TradingRobot
+	err = dao.TradingRobot.Ctx(ctx).
+		Fields(dao.TradingRobot.Columns().Id, dao.TradingRobot.Columns().RobotName).
+		Where(dao.TradingRobot.Columns().UserId, memberId).
+		WhereNot(dao.TradingRobot.Columns().Status, 4).
+		WhereNull(dao.TradingRobot.Columns().DeletedAt).
+		Scan(&robots)
+	if err != nil {
+		return nil, err
+	}
+
+	// 敏感操作二次验证
+	if err = service.SysTwoFactor().RequireVerified(ctx, memberId, consts.SensitiveOpRobotStopAll, fmt.Sprintf("停止全部机器人: count=%d", len(robots))); err != nil {
+		return nil, err
+	}
+
+	out = &input.TradingRobotStopAllModel{
+		Stopped: make([]int64, 0, len(robots)),
+		Failed:  make([]*input.TradingRobotStopAllFailed, 0),
+	}
+	for _, robot := range robots {
+		if stopErr := s.Stop(ctx, &input.TradingRobotStopInp{Id: robot.Id}); stopErr != nil {
+			out.Failed = append(out.Failed, &input.TradingRobotStopAllFailed{
+				Id:        robot.Id,
+				RobotName: robot.RobotName,
+				Reason:    stopErr.Error(),
+			})
+			continue
+		}
+		out.Stopped = append(out.Stopped, robot.Id)
+	}
+
+	g.Log().Infof(ctx, "停止全部机器人: 用户=%d, 成功=%d, 失败=%d", memberId, len(out.Stopped), len(out.Failed))
+	return out, nil
+}
+
 // GetStats 获取运行统计
 func (s *robotImpl) GetStats(ctx context.Context, in *input.TradingRobotStatsInp) (out *input.TradingRobotStatsModel, err error) {
 	memberId := contexts.GetUserId(ctx)
@@ -1193,7 +1307,7 @@ func (s *robotImpl) GetSignalLogs(ctx context.Context, robotId int64, limit int)
 		limit = 20
 	}
 
-	model := g.DB().Model("hg_trading_signal_log").Ctx(ctx)
+	model := dao.TradingSignalLog.Ctx(ctx)
 	if robotId > 0 {
 		model = model.Where("robot_id", robotId)
 	}
@@ -0,0 +1,520 @@
+// Package trading
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"hotgo/internal/dao"
+	"hotgo/internal/library/contexts"
+	"hotgo/internal/logic/toogo"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// robotBasketImpl 组合机器人：一个API账户同时交易多个交易对
+// 每个交易对落一条普通机器人记录（腿），通过 basket_id 关联；运行时由 toogo.BasketCoordinator
+// 统一约束保证金预算、分配权重与最大同时持仓数，私有WS与账户快照按API账户共享。
+type robotBasketImpl struct{}
+
+// getBasket 获取当前用户的组合
+func (s *robotBasketImpl) getBasket(ctx context.Context, id int64) (basket *entity.TradingRobotBasket, memberId int64, err error) {
+	memberId = contexts.GetUserId(ctx)
+	if memberId <= 0 {
+		return nil, 0, gerror.New("用户未登录")
+	}
+	err = dao.TradingRobotBasket.Ctx(ctx).
+		Where(dao.TradingRobotBasket.Columns().Id, id).
+		Where(dao.TradingRobotBasket.Columns().UserId, memberId).
+		WhereNull(dao.TradingRobotBasket.Columns().DeletedAt).
+		Scan(&basket)
+	if err != nil {
+		return nil, memberId, err
+	}
+	if basket == nil {
+		return nil, memberId, gerror.New("组合机器人不存在或无权限")
+	}
+	return basket, memberId, nil
+}
+
+// getLegs 获取组合下的全部腿
+func (s *robotBasketImpl) getLegs(ctx context.Context, basketId int64) (legs []*entity.TradingRobot, err error) {
+	err = dao.TradingRobot.Ctx(ctx).
+		Where(dao.TradingRobot.Columns().BasketId, basketId).
+		WhereNull(dao.TradingRobot.Columns().DeletedAt).
+		OrderAsc(dao.TradingRobot.Columns().Id).
+		Scan(&legs)
+	return
+}
+
+// Create 创建组合机器人
+func (s *robotBasketImpl) Create(ctx context.Context, in *input.TradingRobotBasketCreateInp) (id int64, err error) {
+	memberId := contexts.GetUserId(ctx)
+	if memberId <= 0 {
+		return 0, gerror.New("用户未登录")
+	}
+
+	// 验证API配置是否存在且属于当前用户
+	var apiConfig *entity.TradingApiConfig
+	err = dao.TradingApiConfig.Ctx(ctx).
+		Where(dao.TradingApiConfig.Columns().Id, in.ApiConfigId).
+		Where(dao.TradingApiConfig.Columns().UserId, memberId).
+		WhereNull(dao.TradingApiConfig.Columns().DeletedAt).
+		Scan(&apiConfig)
+	if err != nil {
+		return 0, err
+	}
+	if apiConfig == nil {
+		return 0, gerror.New("API配置不存在或无权限")
+	}
+	platform := canonicalPlatform(apiConfig.Platform)
+	if platform == "" {
+		return 0, gerror.New("API配置平台为空，请检查API配置")
+	}
+
+	// 与独立机器人一致：每个API配置只能被一个机器人（或一个组合）占用
+	var existingRobot *entity.TradingRobot
+	err = dao.TradingRobot.Ctx(ctx).
+		Where(dao.TradingRobot.Columns().ApiConfigId, in.ApiConfigId).
+		WhereNull(dao.TradingRobot.Columns().DeletedAt).
+		Scan(&existingRobot)
+	if err != nil {
+		return 0, gerror.Wrap(err, "检查API配置绑定失败")
+	}
+	if existingRobot != nil {
+		return 0, gerror.Newf("该API配置已绑定机器人【%s】，每个API配置只能绑定一个机器人或组合", existingRobot.RobotName)
+	}
+
+	if len(in.Legs) < 2 {
+		return 0, gerror.New("组合机器人至少需要2个交易对")
+	}
+	if in.MaxPositions > len(in.Legs) {
+		return 0, gerror.Newf("最大同时持仓数(%d)不能超过交易对数量(%d)", in.MaxPositions, len(in.Legs))
+	}
+
+	// 校验各腿：交易对去重、策略组平台/交易对一致
+	seen := make(map[string]bool, len(in.Legs))
+	var weightSum float64
+	for _, leg := range in.Legs {
+		if leg == nil {
+			return 0, gerror.New("交易对配置不能为空")
+		}
+		symbol := canonicalSymbol(leg.Symbol)
+		if symbol == "" {
+			return 0, gerror.New("交易对不能为空")
+		}
+		if seen[symbol] {
+			return 0, gerror.Newf("交易对(%s)重复", symbol)
+		}
+		seen[symbol] = true
+		leg.Symbol = symbol
+
+		var group *entity.TradingStrategyGroup
+		_ = dao.TradingStrategyGroup.Ctx(ctx).
+			Where("id", leg.StrategyGroupId).
+			Scan(&group)
+		if group == nil || group.Id == 0 {
+			return 0, gerror.Newf("交易对(%s)的策略组不存在，请重新选择", symbol)
+		}
+		if canonicalPlatform(group.Exchange) != platform {
+			return 0, gerror.Newf("交易对(%s)的策略组平台(%s)与API平台(%s)不一致", symbol, group.Exchange, platform)
+		}
+		if canonicalSymbol(group.Symbol) != symbol {
+			return 0, gerror.Newf("策略组交易对(%s)与组合交易对(%s)不一致", group.Symbol, symbol)
+		}
+		if group.IsActive == 0 {
+			return 0, gerror.Newf("交易对(%s)的策略组已禁用", symbol)
+		}
+		weightSum += leg.Weight
+	}
+
+	// 权重归一化：全部未填写时均分
+	for _, leg := range in.Legs {
+		if weightSum <= 0 {
+			leg.Weight = 1 / float64(len(in.Legs))
+		} else {
+			leg.Weight = leg.Weight / weightSum
+		}
+	}
+
+	mapping := in.MarketRiskMapping
+	if len(mapping) == 0 {
+		mapping = map[string]string{
+			"trend":    "balanced",
+			"volatile": "balanced",
+			"high_vol": "aggressive",
+			"low_vol":  "conservative",
+		}
+	}
+	mappingJSON, err := json.Marshal(mapping)
+	if err != nil {
+		return 0, gerror.Wrap(err, "映射关系JSON转换失败")
+	}
+
+	// 开关默认值（与独立机器人一致）
+	autoTrade := 0
+	if in.AutoTradeEnabled != nil {
+		autoTrade = *in.AutoTradeEnabled
+	}
+	autoClose := 1
+	if in.AutoCloseEnabled != nil {
+		autoClose = *in.AutoCloseEnabled
+	}
+	dualSide := 1
+	if in.DualSidePosition != nil {
+		dualSide = *in.DualSidePosition
+	}
+
+	// 【PostgreSQL 兼容】InsertAndGetId() 不支持 PostgreSQL，改用事务 + LASTVAL()
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		_, err := tx.Model(dao.TradingRobotBasket.Table()).Ctx(ctx).Data(g.Map{
+			"user_id":       memberId,
+			"basket_name":   in.BasketName,
+			"api_config_id": in.ApiConfigId,
+			"exchange":      platform,
+			"margin_budget": in.MarginBudget,
+			"max_positions": in.MaxPositions,
+			"status":        1, // 未启动
+		}).Insert()
+		if err != nil {
+			return gerror.Wrap(err, "创建组合机器人失败")
+		}
+		val, err := tx.GetValue("SELECT LASTVAL()")
+		if err != nil {
+			return gerror.Wrap(err, "获取组合机器人ID失败")
+		}
+		id = val.Int64()
+
+		for _, leg := range in.Legs {
+			strategyJSON, err := json.Marshal(map[string]interface{}{"groupId": leg.StrategyGroupId})
+			if err != nil {
+				return gerror.Wrap(err, "策略JSON转换失败")
+			}
+			_, err = tx.Model(dao.TradingRobot.Table()).Ctx(ctx).Data(g.Map{
+				"user_id":             memberId,
+				"robot_name":          fmt.Sprintf("%s-%s", in.BasketName, leg.Symbol),
+				"api_config_id":       in.ApiConfigId,
+				"max_profit_target":   in.MaxProfitTarget,
+				"max_loss_amount":     in.MaxLossAmount,
+				"max_runtime":         in.MaxRuntime,
+				"auto_market_state":   in.AutoMarketState,
+				"exchange":            platform,
+				"symbol":              leg.Symbol,
+				"use_monitor_signal":  in.UseMonitorSignal,
+				"current_strategy":    string(strategyJSON),
+				"strategy_group_id":   leg.StrategyGroupId,
+				"auto_trade_enabled":  autoTrade,
+				"auto_close_enabled":  autoClose,
+				"profit_lock_enabled": 1,
+				"dual_side_position":  dualSide,
+				"basket_id":           id,
+				"allocation_weight":   leg.Weight,
+				"status":              1, // 未启动
+				"remark":              string(mappingJSON),
+			}).Insert()
+			if err != nil {
+				return gerror.Wrapf(err, "创建交易对(%s)失败", leg.Symbol)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// List 组合机器人列表
+func (s *robotBasketImpl) List(ctx context.Context, in *input.TradingRobotBasketListInp) (list []*input.TradingRobotBasketListModel, totalCount int, err error) {
+	memberId := contexts.GetUserId(ctx)
+	if memberId <= 0 {
+		err = gerror.New("用户未登录")
+		return
+	}
+
+	mod := dao.TradingRobotBasket.Ctx(ctx).
+		Where(dao.TradingRobotBasket.Columns().UserId, memberId).
+		WhereNull(dao.TradingRobotBasket.Columns().DeletedAt)
+	if in.Status > 0 {
+		mod = mod.Where(dao.TradingRobotBasket.Columns().Status, in.Status)
+	}
+
+	totalCount, err = mod.Count()
+	if err != nil || totalCount == 0 {
+		return
+	}
+	if err = mod.Page(in.Page, in.PageSize).OrderDesc(dao.TradingRobotBasket.Columns().Id).Scan(&list); err != nil {
+		return nil, 0, err
+	}
+
+	for _, item := range list {
+		legs, lerr := s.getLegs(ctx, item.Id)
+		if lerr != nil {
+			continue
+		}
+		item.LegCount = len(legs)
+		for _, leg := range legs {
+			item.TotalProfit += leg.TotalProfit
+		}
+	}
+	return
+}
+
+// View 组合详情：分腿与合计盈亏（按 run_session 汇总）
+func (s *robotBasketImpl) View(ctx context.Context, in *input.TradingRobotBasketIdInp) (out *input.TradingRobotBasketViewModel, err error) {
+	basket, memberId, err := s.getBasket(ctx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+	legs, err := s.getLegs(ctx, basket.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []*entity.TradingRobotRunSession
+	err = dao.TradingRobotRunSession.Ctx(ctx).
+		Where(dao.TradingRobotRunSession.Columns().UserId, memberId).
+		Where(dao.TradingRobotRunSession.Columns().BasketId, basket.Id).
+		Scan(&sessions)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询运行区间失败")
+	}
+
+	out = &input.TradingRobotBasketViewModel{
+		Basket: basket,
+		Legs:   make([]*input.TradingRobotBasketLegModel, 0, len(legs)),
+		Total:  &input.TradingRobotBasketPnlModel{},
+	}
+	legIndex := make(map[int64]*input.TradingRobotBasketLegModel, len(legs))
+	for _, leg := range legs {
+		item := &input.TradingRobotBasketLegModel{
+			RobotId:          leg.Id,
+			Symbol:           leg.Symbol,
+			StrategyGroupId:  leg.StrategyGroupId,
+			AllocationWeight: leg.AllocationWeight,
+			Status:           leg.Status,
+			StartTime:        leg.StartTime,
+		}
+		out.Legs = append(out.Legs, item)
+		legIndex[leg.Id] = item
+	}
+
+	now := gtime.Now()
+	for _, sess := range sessions {
+		runtime := int64(sess.RuntimeSeconds)
+		if sess.EndTime == nil && sess.StartTime != nil {
+			runtime = int64(now.Sub(sess.StartTime).Seconds())
+		}
+		var pnl, fee float64
+		if sess.TotalPnl != nil {
+			pnl = *sess.TotalPnl
+		}
+		if sess.TotalFee != nil {
+			fee = *sess.TotalFee
+		}
+		targets := []*input.TradingRobotBasketPnlModel{out.Total}
+		if item, ok := legIndex[sess.RobotId]; ok {
+			targets = append(targets, &item.TradingRobotBasketPnlModel)
+		}
+		for _, t := range targets {
+			t.Sessions++
+			t.RuntimeSeconds += runtime
+			t.TradeCount += sess.TradeCount
+			t.TotalPnl += pnl
+			t.TotalFee += fee
+			t.NetPnl += pnl - fee
+		}
+	}
+	return out, nil
+}
+
+// Start 启动组合：依次启动各腿（已停用的腿走重启）
+func (s *robotBasketImpl) Start(ctx context.Context, in *input.TradingRobotBasketIdInp) error {
+	basket, _, err := s.getBasket(ctx, in.Id)
+	if err != nil {
+		return err
+	}
+	if basket.Status == 2 {
+		return gerror.New("组合机器人已经在运行中")
+	}
+	legs, err := s.getLegs(ctx, basket.Id)
+	if err != nil {
+		return err
+	}
+	if len(legs) == 0 {
+		return gerror.New("组合机器人没有可用的交易对")
+	}
+
+	var failed []string
+	for _, leg := range legs {
+		var lerr error
+		switch leg.Status {
+		case 2:
+			continue
+		case 4:
+			lerr = Robot.Restart(ctx, &input.TradingRobotStartInp{Id: leg.Id})
+		default:
+			lerr = Robot.Start(ctx, &input.TradingRobotStartInp{Id: leg.Id})
+		}
+		if lerr != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", leg.Symbol, lerr))
+		}
+	}
+	if len(failed) == len(legs) {
+		return gerror.Newf("组合启动失败：%s", strings.Join(failed, "；"))
+	}
+
+	if err = s.updateStatus(ctx, basket.Id, 2, g.Map{
+		dao.TradingRobotBasket.Columns().StartTime: gtime.Now(),
+	}); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return gerror.Newf("组合已启动，但部分交易对启动失败：%s", strings.Join(failed, "；"))
+	}
+	g.Log().Infof(ctx, "组合机器人启动成功: ID=%d, 名称=%s, 交易对数=%d", basket.Id, basket.BasketName, len(legs))
+	return nil
+}
+
+// Pause 暂停组合：暂停运行中的腿
+func (s *robotBasketImpl) Pause(ctx context.Context, in *input.TradingRobotBasketIdInp) error {
+	basket, _, err := s.getBasket(ctx, in.Id)
+	if err != nil {
+		return err
+	}
+	if basket.Status != 2 {
+		return gerror.New("只能暂停运行中的组合机器人")
+	}
+	legs, err := s.getLegs(ctx, basket.Id)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, leg := range legs {
+		if leg.Status != 2 {
+			continue
+		}
+		if lerr := Robot.Pause(ctx, &input.TradingRobotPauseInp{Id: leg.Id}); lerr != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", leg.Symbol, lerr))
+		}
+	}
+	if len(failed) > 0 {
+		return gerror.Newf("部分交易对暂停失败：%s", strings.Join(failed, "；"))
+	}
+	return s.updateStatus(ctx, basket.Id, 3, nil)
+}
+
+// Stop 停用组合：各腿需无持仓
+func (s *robotBasketImpl) Stop(ctx context.Context, in *input.TradingRobotBasketIdInp) error {
+	basket, _, err := s.getBasket(ctx, in.Id)
+	if err != nil {
+		return err
+	}
+	if basket.Status == 4 {
+		return gerror.New("组合机器人已经停用")
+	}
+	legs, err := s.getLegs(ctx, basket.Id)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, leg := range legs {
+		if leg.Status == 4 {
+			continue
+		}
+		if lerr := Robot.Stop(ctx, &input.TradingRobotStopInp{Id: leg.Id}); lerr != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", leg.Symbol, lerr))
+		}
+	}
+	if len(failed) > 0 {
+		return gerror.Newf("部分交易对停用失败：%s", strings.Join(failed, "；"))
+	}
+	return s.updateStatus(ctx, basket.Id, 4, g.Map{
+		dao.TradingRobotBasket.Columns().StopTime: gtime.Now(),
+	})
+}
+
+// Delete 删除组合（软删除，连同各腿）
+func (s *robotBasketImpl) Delete(ctx context.Context, in *input.TradingRobotBasketIdInp) error {
+	basket, _, err := s.getBasket(ctx, in.Id)
+	if err != nil {
+		return err
+	}
+	legs, err := s.getLegs(ctx, basket.Id)
+	if err != nil {
+		return err
+	}
+
+	legIds := make([]int64, 0, len(legs))
+	for _, leg := range legs {
+		if leg.Status == 2 {
+			return gerror.Newf("交易对(%s)运行中，请先停止组合", leg.Symbol)
+		}
+		legIds = append(legIds, leg.Id)
+	}
+	if len(legIds) > 0 {
+		count, err := dao.TradingOrder.Ctx(ctx).
+			WhereIn(dao.TradingOrder.Columns().RobotId, legIds).
+			Where(dao.TradingOrder.Columns().Status, toogo.OrderStatusOpen).
+			Count()
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return gerror.Newf("该组合有%d笔持仓订单，无法删除", count)
+		}
+	}
+
+	now := gtime.Now()
+	err = g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		if len(legIds) > 0 {
+			if _, err := tx.Model(dao.TradingRobot.Table()).Ctx(ctx).
+				WhereIn(dao.TradingRobot.Columns().Id, legIds).
+				Data(g.Map{dao.TradingRobot.Columns().DeletedAt: now}).
+				Update(); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Model(dao.TradingRobotBasket.Table()).Ctx(ctx).
+			Where(dao.TradingRobotBasket.Columns().Id, basket.Id).
+			Data(g.Map{dao.TradingRobotBasket.Columns().DeletedAt: now}).
+			Update()
+		return err
+	})
+	if err != nil {
+		return gerror.Wrap(err, "删除组合机器人失败")
+	}
+	toogo.InvalidateBasket(basket.Id)
+	return nil
+}
+
+// updateStatus 更新组合状态并刷新运行时缓存
+func (s *robotBasketImpl) updateStatus(ctx context.Context, basketId int64, status int, extra g.Map) error {
+	data := g.Map{dao.TradingRobotBasket.Columns().Status: status}
+	for k, v := range extra {
+		data[k] = v
+	}
+	_, err := dao.TradingRobotBasket.Ctx(ctx).
+		Where(dao.TradingRobotBasket.Columns().Id, basketId).
+		Data(data).
+		Update()
+	if err != nil {
+		return err
+	}
+	toogo.InvalidateBasket(basketId)
+	return nil
+}
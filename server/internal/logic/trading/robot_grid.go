@@ -0,0 +1,85 @@
+// Package trading
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+
+package trading
+
+import (
+	"context"
+
+	"hotgo/internal/dao"
+	"hotgo/internal/library/contexts"
+	"hotgo/internal/logic/toogo"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+// GridStatus 网格运行状态：格子挂单/持仓与最近网格利润
+func (s *robotImpl) GridStatus(ctx context.Context, in *input.TradingRobotGridStatusInp) (out *input.TradingRobotGridStatusModel, err error) {
+	memberId := contexts.GetUserId(ctx)
+	if memberId <= 0 {
+		return nil, gerror.New("用户未登录")
+	}
+
+	var robot *entity.TradingRobot
+	err = dao.TradingRobot.Ctx(ctx).
+		Where(dao.TradingRobot.Columns().Id, in.Id).
+		Where(dao.TradingRobot.Columns().UserId, memberId).
+		WhereNull(dao.TradingRobot.Columns().DeletedAt).
+		Scan(&robot)
+	if err != nil {
+		return nil, err
+	}
+	if robot == nil {
+		return nil, gerror.New("机器人不存在或无权限")
+	}
+	if robot.RobotMode != toogo.RobotModeGrid {
+		return nil, gerror.New("该机器人不是网格模式")
+	}
+
+	out = &input.TradingRobotGridStatusModel{}
+	if cfg, perr := toogo.ParseGridConfig(robot.GridConfig); perr == nil {
+		out.Config = &input.TradingGridConfigInp{
+			LowerPrice:      cfg.LowerPrice,
+			UpperPrice:      cfg.UpperPrice,
+			GridCount:       cfg.GridCount,
+			Spacing:         cfg.Spacing,
+			Bias:            cfg.Bias,
+			QuantityPerGrid: cfg.QuantityPerGrid,
+			Leverage:        cfg.Leverage,
+		}
+	}
+
+	err = dao.TradingGridCell.Ctx(ctx).
+		Where(dao.TradingGridCell.Columns().RobotId, robot.Id).
+		OrderAsc(dao.TradingGridCell.Columns().CellIndex).
+		Scan(&out.Cells)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询网格状态失败")
+	}
+	for _, c := range out.Cells {
+		if c.Holding == 1 {
+			out.HoldingCells++
+		}
+		out.RoundTrips += c.RoundTrips
+		out.RealizedProfit += c.RealizedProfit
+	}
+
+	limit := in.TradeLimit
+	if limit <= 0 {
+		limit = 50
+	}
+	err = dao.TradingGridTrade.Ctx(ctx).
+		Where(dao.TradingGridTrade.Columns().RobotId, robot.Id).
+		OrderDesc(dao.TradingGridTrade.Columns().Id).
+		Limit(limit).
+		Scan(&out.RecentTrades)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询网格利润记录失败")
+	}
+	return out, nil
+}
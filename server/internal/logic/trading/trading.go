@@ -10,5 +10,6 @@ var (
 	ApiConfig   = &apiConfigImpl{}
 	ProxyConfig = &proxyConfigImpl{}
 	Robot       = &robotImpl{}
+	RobotBasket = &robotBasketImpl{}
 	Order       = &orderImpl{}
 )
@@ -13,6 +13,7 @@ import (
 	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/dao"
 )
 
 // TradingLogService 交易日志服务
@@ -50,7 +51,7 @@ func (s *TradingLogService) LogOperation(ctx context.Context, log *OperationLog)
 	requestData, _ := json.Marshal(log.RequestData)
 	responseData, _ := json.Marshal(log.ResponseData)
 
-	_, err := g.DB().Model("hg_trading_operation_log").Ctx(ctx).Insert(g.Map{
+	_, err := dao.TradingOperationLog.Ctx(ctx).Insert(g.Map{
 		"robot_id":      log.RobotId,
 		"user_id":       log.UserId,
 		"operation":     log.Operation,
@@ -104,7 +105,7 @@ func (s *TradingLogService) UpdateDailyStats(ctx context.Context, stats *DailySt
 		stats.ProfitFactor = stats.MaxProfit / (-stats.MaxLoss)
 	}
 
-	_, err := g.DB().Model("hg_trading_daily_stats").Ctx(ctx).Save(g.Map{
+	_, err := dao.TradingDailyStats.Ctx(ctx).Save(g.Map{
 		"robot_id":         stats.RobotId,
 		"user_id":          stats.UserId,
 		"date":             stats.Date,
@@ -129,7 +130,7 @@ func (s *TradingLogService) UpdateDailyStats(ctx context.Context, stats *DailySt
 // GetDailyStats 获取日统计
 func (s *TradingLogService) GetDailyStats(ctx context.Context, robotId int64, date string) (*DailyStats, error) {
 	var stats DailyStats
-	err := g.DB().Model("hg_trading_daily_stats").Ctx(ctx).
+	err := dao.TradingDailyStats.Ctx(ctx).
 		Where("robot_id", robotId).
 		Where("date", date).
 		Scan(&stats)
@@ -142,7 +143,7 @@ func (s *TradingLogService) GetDailyStats(ctx context.Context, robotId int64, da
 // GetRobotStatsRange 获取机器人统计范围
 func (s *TradingLogService) GetRobotStatsRange(ctx context.Context, robotId int64, startDate, endDate string) ([]*DailyStats, error) {
 	var stats []*DailyStats
-	err := g.DB().Model("hg_trading_daily_stats").Ctx(ctx).
+	err := dao.TradingDailyStats.Ctx(ctx).
 		Where("robot_id", robotId).
 		WhereBetween("date", startDate, endDate).
 		Order("date ASC").
@@ -172,7 +173,7 @@ type UserSummary struct {
 // UpdateUserSummary 更新用户汇总
 func (s *TradingLogService) UpdateUserSummary(ctx context.Context, userId int64) error {
 	// 聚合用户所有机器人的统计数据
-	result, err := g.DB().Model("hg_trading_daily_stats").Ctx(ctx).
+	result, err := dao.TradingDailyStats.Ctx(ctx).
 		Fields("SUM(total_trades) as total_trades, SUM(total_volume) as total_volume, SUM(total_pnl) as total_pnl, SUM(commission) as total_commission, SUM(win_trades) as win_trades").
 		Where("user_id", userId).
 		One()
@@ -188,11 +189,11 @@ func (s *TradingLogService) UpdateUserSummary(ctx context.Context, userId int64)
 	}
 
 	// 获取机器人数量
-	robotCount, _ := g.DB().Model("hg_trading_robot").Ctx(ctx).Where("user_id", userId).Count()
-	activeRobots, _ := g.DB().Model("hg_trading_robot").Ctx(ctx).Where("user_id", userId).Where("status", 2).Count()
+	robotCount, _ := dao.TradingRobot.Ctx(ctx).Where("user_id", userId).Count()
+	activeRobots, _ := dao.TradingRobot.Ctx(ctx).Where("user_id", userId).Where("status", 2).Count()
 
 	// 获取最佳机器人
-	bestRobot, _ := g.DB().Model("hg_trading_robot").Ctx(ctx).
+	bestRobot, _ := dao.TradingRobot.Ctx(ctx).
 		Fields("id, total_profit").
 		Where("user_id", userId).
 		Order("total_profit DESC").
@@ -241,7 +242,7 @@ func (s *TradingLogService) LogSignal(ctx context.Context, log *SignalLog) error
 		executed = 1
 	}
 
-	_, err := g.DB().Model("hg_trading_signal_log").Ctx(ctx).Insert(g.Map{
+	_, err := dao.TradingSignalLog.Ctx(ctx).Insert(g.Map{
 		"robot_id":        log.RobotId,
 		"strategy_id":     log.StrategyId,
 		"symbol":          log.Symbol,
@@ -297,7 +298,7 @@ func (s *TradingLogService) UpdateSystemMonitor(ctx context.Context, monitor *Sy
 
 // GetOperationLogs 获取操作日志
 func (s *TradingLogService) GetOperationLogs(ctx context.Context, robotId int64, page, pageSize int) ([]gdb.Record, int, error) {
-	model := g.DB().Model("hg_trading_operation_log").Ctx(ctx)
+	model := dao.TradingOperationLog.Ctx(ctx)
 	if robotId > 0 {
 		model = model.Where("robot_id", robotId)
 	}
@@ -317,7 +318,7 @@ func (s *TradingLogService) GetOperationLogs(ctx context.Context, robotId int64,
 
 // GetSignalLogs 获取信号日志
 func (s *TradingLogService) GetSignalLogs(ctx context.Context, robotId int64, page, pageSize int) ([]gdb.Record, int, error) {
-	model := g.DB().Model("hg_trading_signal_log").Ctx(ctx)
+	model := dao.TradingSignalLog.Ctx(ctx)
 	if robotId > 0 {
 		model = model.Where("robot_id", robotId)
 	}
@@ -340,14 +341,14 @@ func (s *TradingLogService) CleanOldLogs(ctx context.Context, days int) error {
 	expireTime := gtime.Now().AddDate(0, 0, -days)
 
 	// 清理操作日志
-	_, err := g.DB().Model("hg_trading_operation_log").Ctx(ctx).
+	_, err := dao.TradingOperationLog.Ctx(ctx).
 		Where("created_at < ?", expireTime).Delete()
 	if err != nil {
 		return err
 	}
 
 	// 清理信号日志
-	_, err = g.DB().Model("hg_trading_signal_log").Ctx(ctx).
+	_, err = dao.TradingSignalLog.Ctx(ctx).
 		Where("created_at < ?", expireTime).Delete()
 	if err != nil {
 		return err
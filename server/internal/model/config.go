@@ -185,4 +185,6 @@ type LoginConfig struct {
 	Policy         string  `json:"loginPolicy"`
 	AutoOpenId     int     `json:"loginAutoOpenId"`
 	ForceInvite    int     `json:"loginForceInvite"`
+	// TwoFactorRoleIds 强制启用双因素认证的角色
+	TwoFactorRoleIds []int64 `json:"loginTwoFactorRoleIds"`
 }
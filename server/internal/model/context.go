@@ -25,6 +25,7 @@ type Identity struct {
 	Pid      int64       `json:"pid"             description:"上级ID"`
 	DeptId   int64       `json:"deptId"          description:"部门ID"`
 	DeptType string      `json:"deptType"        description:"部门类型"`
+	TenantId int64       `json:"tenantId"        description:"所属租户ID，0为平台"`
 	RoleId   int64       `json:"roleId"          description:"角色ID"`
 	RoleKey  string      `json:"roleKey"         description:"角色唯一标识符"`
 	Username string      `json:"username"        description:"用户名"`
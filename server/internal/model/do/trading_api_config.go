@@ -21,11 +21,21 @@ type TradingApiConfig struct {
 	ApiKey         any         // API Key（加密）
 	SecretKey      any         // Secret Key（加密）
 	Passphrase     any         // Passphrase（加密，可选）
+	DataKey        any         // 数据密钥（主密钥包装）
+	KeyVersion     any         // 主密钥版本：0=旧版静态密钥
 	IsDefault      any         // 是否默认：0=否,1=是
 	Status         any         // 状态：1=正常,2=禁用
 	LastVerifyTime *gtime.Time // 最后验证时间
 	VerifyStatus   any         // 验证状态：0=未验证,1=成功,2=失败
 	VerifyMessage  any         // 验证消息
+	PermStatus     any         // 权限检测状态：0=未检测,1=正常,2=风险提示,3=不可用
+	PermFutures    any         // 合约交易权限：0=未开启,1=已开启,2=无法确认
+	PermWithdraw   any         // 提现权限：0=未开启,1=已开启,2=无法确认
+	IpRestricted   any         // 是否绑定IP白名单：0=否,1=是
+	IpWhitelist    any         // IP白名单（逗号分隔）
+	KeyExpireAt    any         // API Key过期时间
+	PermMessage    any         // 权限检测结果说明
+	PermCheckedAt  any         // 最后权限检测时间
 	Remark         any         // 备注
 	CreatedAt      *gtime.Time // 创建时间
 	UpdatedAt      *gtime.Time // 更新时间
@@ -15,6 +15,8 @@ type TradingProxyConfig struct {
 	Id           any         // 主键ID
 	TenantId     any         // 租户ID
 	UserId       any         // 用户ID
+	Name         any         // 代理名称
+	Weight       any         // 选择权重
 	Enabled      any         // 是否启用：0=禁用,1=启用
 	ProxyType    any         // 代理类型：socks5/http
 	ProxyAddress any         // 代理地址
@@ -24,6 +26,8 @@ type TradingProxyConfig struct {
 	LastTestTime *gtime.Time // 最后测试时间
 	TestStatus   any         // 测试状态：0=未测试,1=成功,2=失败
 	TestMessage  any         // 测试消息
+	Latency      any         // 平均延迟(ms)
+	VenueLatency any         // 各交易所延迟(ms)，JSON
 	CreatedAt    *gtime.Time // 创建时间
 	UpdatedAt    *gtime.Time // 更新时间
 }
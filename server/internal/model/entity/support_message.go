@@ -4,13 +4,18 @@ import "github.com/gogf/gf/v2/os/gtime"
 
 // SupportMessage is the golang structure for table hg_support_message.
 type SupportMessage struct {
-	Id        int64       `json:"id"        orm:"id"         description:"消息ID"`
-	SessionId int64       `json:"sessionId" orm:"session_id" description:"会话ID"`
-	SenderRole int        `json:"senderRole" orm:"sender_role" description:"发送方角色：1用户 2客服 3系统"`
-	SenderId  int64       `json:"senderId"  orm:"sender_id"  description:"发送方ID"`
-	MsgType   int         `json:"msgType"   orm:"msg_type"   description:"消息类型：1文本"`
-	Content   string      `json:"content"   orm:"content"    description:"内容"`
-	CreatedAt *gtime.Time `json:"createdAt" orm:"created_at" description:"创建时间"`
-}
+	Id         int64  `json:"id"        orm:"id"         description:"消息ID"`
+	SessionId  int64  `json:"sessionId" orm:"session_id" description:"会话ID"`
+	SenderRole int    `json:"senderRole" orm:"sender_role" description:"发送方角色：1用户 2客服 3系统"`
+	SenderId   int64  `json:"senderId"  orm:"sender_id"  description:"发送方ID"`
+	MsgType    int    `json:"msgType"   orm:"msg_type"   description:"消息类型：1文本 2图片 3文件"`
+	Content    string `json:"content"   orm:"content"    description:"内容（附件消息为附件地址）"`
 
+	AttachmentId    int64       `json:"attachmentId"    orm:"attachment_id"    description:"附件ID"`
+	FileName        string      `json:"fileName"        orm:"file_name"        description:"附件原始文件名"`
+	FileSize        int64       `json:"fileSize"        orm:"file_size"        description:"附件大小(字节)"`
+	ReadAt          *gtime.Time `json:"readAt"          orm:"read_at"          description:"接收方已读时间"`
+	OfflineNotified int         `json:"offlineNotified" orm:"offline_notified" description:"离线邮件是否已送达"`
 
+	CreatedAt *gtime.Time `json:"createdAt" orm:"created_at" description:"创建时间"`
+}
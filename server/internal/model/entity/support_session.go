@@ -15,9 +15,17 @@ type SupportSession struct {
 	UnreadUser  int `json:"unreadUser"  orm:"unread_user"  description:"用户未读数"`
 	UnreadAgent int `json:"unreadAgent" orm:"unread_agent" description:"客服未读数"`
 
+	Rating       int         `json:"rating"       orm:"rating"        description:"用户评分：1-5，0未评价"`
+	RatingRemark string      `json:"ratingRemark" orm:"rating_remark" description:"评价内容"`
+	RatedAt      *gtime.Time `json:"ratedAt"      orm:"rated_at"      description:"评价时间"`
+
+	RobotId        int64       `json:"robotId"        orm:"robot_id"        description:"工单关联机器人ID"`
+	OrderId        int64       `json:"orderId"        orm:"order_id"        description:"工单关联订单ID"`
+	EscalateRemark string      `json:"escalateRemark" orm:"escalate_remark" description:"工单说明"`
+	EscalatedBy    int64       `json:"escalatedBy"    orm:"escalated_by"    description:"升级为工单的客服ID"`
+	EscalatedAt    *gtime.Time `json:"escalatedAt"    orm:"escalated_at"    description:"升级为工单时间"`
+
 	CreatedAt *gtime.Time `json:"createdAt" orm:"created_at" description:"创建时间"`
 	UpdatedAt *gtime.Time `json:"updatedAt" orm:"updated_at" description:"更新时间"`
 	ClosedAt  *gtime.Time `json:"closedAt"  orm:"closed_at"  description:"关闭时间"`
 }
-
-
@@ -11,6 +11,7 @@ import (
 // ToogoAgentLevel is the golang structure for table hg_toogo_agent_level.
 type ToogoAgentLevel struct {
 	Id                   int64       `json:"id"                   orm:"id"                     description:"主键ID"`
+	TenantId             int64       `json:"tenantId"             orm:"tenant_id"              description:"租户ID，0=平台"`
 	Level                int         `json:"level"                orm:"level"                  description:"等级: 1-5"`
 	LevelName            string      `json:"levelName"            orm:"level_name"             description:"等级名称"`
 	RequireTeamCount     int         `json:"requireTeamCount"     orm:"require_team_count"     description:"需要团队人数"`
@@ -12,7 +12,7 @@ import (
 type ToogoAiLearning struct {
 	Id               int64       `json:"id"               orm:"id"                description:"主键ID"`
 	Symbol           string      `json:"symbol"           orm:"symbol"            description:"交易对"`
-	TimeFrame        string      `json:"timeFrame"        orm:"time_frame"        description:"时间周期: 1m/5m/15m/30m/1h"`
+	TimeFrame        string      `json:"timeFrame"        orm:"time_frame"        description:"信号来源: 1m/5m/15m/30m/1h 或 market/technical/volatility"`
 	MarketState      string      `json:"marketState"      orm:"market_state"      description:"市场状态"`
 	RiskPreference   string      `json:"riskPreference"   orm:"risk_preference"   description:"风险偏好"`
 	PriceWeight      float64     `json:"priceWeight"      orm:"price_weight"      description:"价格权重"`
@@ -28,4 +28,3 @@ type ToogoAiLearning struct {
 	CreatedAt        *gtime.Time `json:"createdAt"        orm:"created_at"        description:"创建时间"`
 	UpdatedAt        *gtime.Time `json:"updatedAt"        orm:"updated_at"        description:"更新时间"`
 }
-
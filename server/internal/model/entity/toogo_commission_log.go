@@ -19,11 +19,16 @@ type ToogoCommissionLog struct {
 	CommissionRate   float64     `json:"commissionRate"   orm:"commission_rate"   description:"佣金比例"`
 	CommissionAmount float64     `json:"commissionAmount" orm:"commission_amount" description:"佣金金额"`
 	SettleType       string      `json:"settleType"       orm:"settle_type"       description:"结算类型: power/usdt"`
-	Status           int         `json:"status"           orm:"status"            description:"状态: 1=待结算, 2=已结算"`
+	Status           int         `json:"status"           orm:"status"            description:"状态: 1=冻结中(待结算), 2=已结算, 3=已冲正"`
 	RelatedId        int64       `json:"relatedId"        orm:"related_id"        description:"关联ID"`
 	RelatedType      string      `json:"relatedType"      orm:"related_type"      description:"关联类型"`
 	OrderSn          string      `json:"orderSn"          orm:"order_sn"          description:"关联订单号"`
 	Remark           string      `json:"remark"           orm:"remark"            description:"备注"`
+	ReleaseTime      *gtime.Time `json:"releaseTime"      orm:"release_time"      description:"冻结到期时间"`
+	ReleasedAt       *gtime.Time `json:"releasedAt"       orm:"released_at"       description:"解冻入账时间"`
+	ReleasedAmount   float64     `json:"releasedAmount"   orm:"released_amount"   description:"实际解冻入账金额(扣除解冻前已冲正部分)"`
+	ReversedAt       *gtime.Time `json:"reversedAt"       orm:"reversed_at"       description:"冲正时间"`
+	ReversalOf       int64       `json:"reversalOf"       orm:"reversal_of"       description:"冲正的原佣金记录ID(冲正记录)"`
 	CreatedAt        *gtime.Time `json:"createdAt"        orm:"created_at"        description:"创建时间"`
 }
 
@@ -11,6 +11,7 @@ import (
 // ToogoConfig is the golang structure for table hg_toogo_config.
 type ToogoConfig struct {
 	Id          int64       `json:"id"          orm:"id"          description:"主键ID"`
+	TenantId    int64       `json:"tenantId"    orm:"tenant_id"   description:"租户ID，0=平台"`
 	Group       string      `json:"group"       orm:"group"       description:"配置分组"`
 	Key         string      `json:"key"         orm:"key"         description:"配置KEY"`
 	Value       string      `json:"value"       orm:"value"       description:"配置值"`
@@ -0,0 +1,32 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package entity
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// ToogoCoupon is the golang structure for table hg_toogo_coupon.
+type ToogoCoupon struct {
+	Id            int64       `json:"id"            orm:"id"             description:"主键ID"`
+	TenantId      int64       `json:"tenantId"      orm:"tenant_id"      description:"租户ID，0=平台"`
+	Code          string      `json:"code"          orm:"code"           description:"优惠码"`
+	CouponName    string      `json:"couponName"    orm:"coupon_name"    description:"优惠券名称"`
+	CouponType    string      `json:"couponType"    orm:"coupon_type"    description:"类型: percent=按比例, fixed=固定金额"`
+	DiscountValue float64     `json:"discountValue" orm:"discount_value" description:"折扣值: percent为百分比, fixed为USDT"`
+	MaxDiscount   float64     `json:"maxDiscount"   orm:"max_discount"   description:"最高抵扣金额(USDT)，0为不限"`
+	MinAmount     float64     `json:"minAmount"     orm:"min_amount"     description:"最低订单金额(USDT)，0为不限"`
+	PlanIds       string      `json:"planIds"       orm:"plan_ids"       description:"限定套餐ID(逗号分隔)，空为不限"`
+	PeriodTypes   string      `json:"periodTypes"   orm:"period_types"   description:"限定订阅周期(逗号分隔)，空为不限"`
+	TotalLimit    int         `json:"totalLimit"    orm:"total_limit"    description:"总使用次数上限，0为不限"`
+	UsedCount     int         `json:"usedCount"     orm:"used_count"     description:"已使用次数"`
+	AgentId       int64       `json:"agentId"       orm:"agent_id"       description:"归属代理ID(推广码)，0为平台券"`
+	StartTime     *gtime.Time `json:"startTime"     orm:"start_time"     description:"生效时间"`
+	ExpireTime    *gtime.Time `json:"expireTime"    orm:"expire_time"    description:"过期时间"`
+	Status        int         `json:"status"        orm:"status"         description:"状态: 1=启用, 2=停用"`
+	Remark        string      `json:"remark"        orm:"remark"         description:"备注"`
+	CreatedAt     *gtime.Time `json:"createdAt"     orm:"created_at"     description:"创建时间"`
+	UpdatedAt     *gtime.Time `json:"updatedAt"     orm:"updated_at"     description:"更新时间"`
+}
@@ -0,0 +1,26 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package entity
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// ToogoCouponUsage is the golang structure for table hg_toogo_coupon_usage.
+type ToogoCouponUsage struct {
+	Id             int64       `json:"id"             orm:"id"              description:"主键ID"`
+	CouponId       int64       `json:"couponId"       orm:"coupon_id"       description:"优惠券ID"`
+	CouponCode     string      `json:"couponCode"     orm:"coupon_code"     description:"优惠码"`
+	UserId         int64       `json:"userId"         orm:"user_id"         description:"用户ID(member_id)"`
+	AgentId        int64       `json:"agentId"        orm:"agent_id"        description:"推广码归属代理ID"`
+	Attributed     int         `json:"attributed"     orm:"attributed"      description:"是否由推广码绑定邀请关系"`
+	SubscriptionId int64       `json:"subscriptionId" orm:"subscription_id" description:"订阅ID"`
+	OrderSn        string      `json:"orderSn"        orm:"order_sn"        description:"订单号"`
+	PlanId         int64       `json:"planId"         orm:"plan_id"         description:"套餐ID"`
+	PeriodType     string      `json:"periodType"     orm:"period_type"     description:"订阅周期"`
+	OriginalAmount float64     `json:"originalAmount" orm:"original_amount" description:"原价(USDT)"`
+	DiscountAmount float64     `json:"discountAmount" orm:"discount_amount" description:"抵扣金额(USDT)"`
+	CreatedAt      *gtime.Time `json:"createdAt"      orm:"created_at"      description:"使用时间"`
+}
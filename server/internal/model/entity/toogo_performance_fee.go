@@ -0,0 +1,23 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package entity
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// ToogoPerformanceFee is the golang structure for table hg_toogo_performance_fee.
+type ToogoPerformanceFee struct {
+	Id                int64       `json:"id"                orm:"id"                  description:"主键ID"`
+	UserId            int64       `json:"userId"            orm:"user_id"             description:"用户ID(member_id)"`
+	RobotId           int64       `json:"robotId"           orm:"robot_id"            description:"机器人ID"`
+	CumPnl            float64     `json:"cumPnl"            orm:"cum_pnl"             description:"累计净已实现盈亏(USDT)"`
+	HighWaterMark     float64     `json:"highWaterMark"     orm:"high_water_mark"     description:"已计费高水位(USDT)"`
+	SettledUntil      int64       `json:"settledUntil"      orm:"settled_until"       description:"已结算至(成交时间戳毫秒)"`
+	TotalConsumePower float64     `json:"totalConsumePower" orm:"total_consume_power" description:"累计收取算力"`
+	LastSettleAt      *gtime.Time `json:"lastSettleAt"      orm:"last_settle_at"      description:"最近结算时间"`
+	CreatedAt         *gtime.Time `json:"createdAt"         orm:"created_at"          description:"创建时间"`
+	UpdatedAt         *gtime.Time `json:"updatedAt"         orm:"updated_at"          description:"更新时间"`
+}
@@ -11,6 +11,7 @@ import (
 // ToogoPlan is the golang structure for table hg_toogo_plan.
 type ToogoPlan struct {
 	Id                int64       `json:"id"                orm:"id"                  description:"主键ID"`
+	TenantId          int64       `json:"tenantId"          orm:"tenant_id"           description:"租户ID，0=平台"`
 	PlanName          string      `json:"planName"          orm:"plan_name"           description:"套餐名称"`
 	PlanCode          string      `json:"planCode"          orm:"plan_code"           description:"套餐代码: FREE/A/B/C/D"`
 	RobotLimit        int         `json:"robotLimit"        orm:"robot_limit"         description:"支持机器人数量"`
@@ -30,6 +31,8 @@ type ToogoPlan struct {
 	GiftPowerQuarterly float64    `json:"giftPowerQuarterly" orm:"gift_power_quarterly" description:"季订阅赠送积分"`
 	GiftPowerHalfYear float64     `json:"giftPowerHalfYear" orm:"gift_power_half_year" description:"半年订阅赠送积分"`
 	GiftPowerYearly   float64     `json:"giftPowerYearly"   orm:"gift_power_yearly"   description:"年订阅赠送积分"`
+	TrialDays         int         `json:"trialDays"         orm:"trial_days"          description:"免费试用天数，0为不支持试用"`
+	TrialRobotLimit   int         `json:"trialRobotLimit"   orm:"trial_robot_limit"   description:"试用期机器人数量，0为与套餐相同"`
 	Description       string      `json:"description"       orm:"description"         description:"套餐描述"`
 	Features          string      `json:"features"          orm:"features"            description:"套餐特性(JSON)"`
 	IsDefault         int         `json:"isDefault"         orm:"is_default"          description:"是否默认套餐(免费)"`
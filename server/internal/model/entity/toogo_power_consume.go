@@ -10,17 +10,25 @@ import (
 
 // ToogoPowerConsume is the golang structure for table hg_toogo_power_consume.
 type ToogoPowerConsume struct {
-	Id            int64       `json:"id"            orm:"id"             description:"主键ID"`
-	UserId        int64       `json:"userId"        orm:"user_id"        description:"用户ID(member_id)"`
-	RobotId       int64       `json:"robotId"       orm:"robot_id"       description:"机器人ID"`
-	OrderId       int64       `json:"orderId"       orm:"order_id"       description:"交易订单ID"`
-	OrderSn       string      `json:"orderSn"       orm:"order_sn"       description:"订单号"`
-	ProfitAmount  float64     `json:"profitAmount"  orm:"profit_amount"  description:"盈利金额(USDT)"`
-	ConsumeRate   float64     `json:"consumeRate"   orm:"consume_rate"   description:"消耗比例"`
-	ConsumePower  float64     `json:"consumePower"  orm:"consume_power"  description:"消耗算力"`
-	FromPower     float64     `json:"fromPower"     orm:"from_power"     description:"从算力账户扣除"`
-	VipLevel      int         `json:"vipLevel"      orm:"vip_level"      description:"用户VIP等级"`
-	DiscountRate  float64     `json:"discountRate"  orm:"discount_rate"  description:"折扣比例(%)"`
-	OriginalPower float64     `json:"originalPower" orm:"original_power" description:"原始消耗算力(未折扣)"`
-	CreatedAt     *gtime.Time `json:"createdAt"     orm:"created_at"     description:"创建时间"`
+	Id            int64       `json:"id"            orm:"id"              description:"主键ID"`
+	UserId        int64       `json:"userId"        orm:"user_id"         description:"用户ID(member_id)"`
+	RobotId       int64       `json:"robotId"       orm:"robot_id"        description:"机器人ID"`
+	OrderId       int64       `json:"orderId"       orm:"order_id"        description:"交易订单ID"`
+	OrderSn       string      `json:"orderSn"       orm:"order_sn"        description:"订单号"`
+	SettleType    string      `json:"settleType"    orm:"settle_type"     description:"结算方式：order=逐单(已停用),hwm=高水位周期结算"`
+	PeriodStart   int64       `json:"periodStart"   orm:"period_start"    description:"结算周期开始(毫秒,不含)"`
+	PeriodEnd     int64       `json:"periodEnd"     orm:"period_end"      description:"结算周期结束(毫秒,含)"`
+	NetPnl        float64     `json:"netPnl"        orm:"net_pnl"         description:"本期净已实现盈亏"`
+	HwmBefore     float64     `json:"hwmBefore"     orm:"hwm_before"      description:"结算前高水位"`
+	HwmAfter      float64     `json:"hwmAfter"      orm:"hwm_after"       description:"结算后高水位"`
+	ProfitAmount  float64     `json:"profitAmount"  orm:"profit_amount"   description:"盈利金额(USDT)"`
+	ConsumeRate   float64     `json:"consumeRate"   orm:"consume_rate"    description:"消耗比例"`
+	ConsumePower  float64     `json:"consumePower"  orm:"consume_power"   description:"消耗算力"`
+	FromPower     float64     `json:"fromPower"     orm:"from_power"      description:"从算力账户扣除"`
+	FromGiftPower float64     `json:"fromGiftPower" orm:"from_gift_power" description:"从积分账户扣除"`
+	PowerAfter    float64     `json:"powerAfter"    orm:"power_after"     description:"扣除后算力余额(负数为欠费)"`
+	VipLevel      int         `json:"vipLevel"      orm:"vip_level"       description:"用户VIP等级"`
+	DiscountRate  float64     `json:"discountRate"  orm:"discount_rate"   description:"折扣比例(%)"`
+	OriginalPower float64     `json:"originalPower" orm:"original_power"  description:"原始消耗算力(未折扣)"`
+	CreatedAt     *gtime.Time `json:"createdAt"     orm:"created_at"      description:"创建时间"`
 }
@@ -15,15 +15,24 @@ type ToogoSubscription struct {
 	PlanId             int64       `json:"planId"             orm:"plan_id"              description:"套餐ID"`
 	PlanCode           string      `json:"planCode"           orm:"plan_code"            description:"套餐代码"`
 	OrderSn            string      `json:"orderSn"            orm:"order_sn"             description:"订单号"`
-	PeriodType         string      `json:"periodType"         orm:"period_type"          description:"订阅周期: daily/monthly/quarterly/half_year/yearly"`
-	Amount             float64     `json:"amount"             orm:"amount"               description:"订阅金额(USDT)"`
+	PeriodType         string      `json:"periodType"         orm:"period_type"          description:"订阅周期: daily/monthly/quarterly/half_year/yearly/trial"`
+	Amount             float64     `json:"amount"             orm:"amount"               description:"订阅金额(USDT，优惠后)"`
+	OriginalAmount     float64     `json:"originalAmount"     orm:"original_amount"      description:"订阅原价(USDT)"`
+	DiscountAmount     float64     `json:"discountAmount"     orm:"discount_amount"      description:"优惠券抵扣金额(USDT)"`
+	CouponId           int64       `json:"couponId"           orm:"coupon_id"            description:"优惠券ID"`
+	CouponCode         string      `json:"couponCode"         orm:"coupon_code"          description:"优惠码"`
 	GiftPower          float64     `json:"giftPower"          orm:"gift_power"           description:"赠送积分"`
 	StartTime          *gtime.Time `json:"startTime"          orm:"start_time"           description:"开始时间"`
 	ExpireTime         *gtime.Time `json:"expireTime"         orm:"expire_time"          description:"到期时间"`
 	Days               int         `json:"days"               orm:"days"                 description:"订阅天数"`
-	Status             int         `json:"status"             orm:"status"               description:"状态: 1=待支付, 2=生效中, 3=已过期, 4=已取消"`
+	Status             int         `json:"status"             orm:"status"               description:"状态: 1=待支付, 2=生效中, 3=已过期, 4=已取消, 5=宽限期, 6=已变更"`
 	PaidAt             *gtime.Time `json:"paidAt"             orm:"paid_at"              description:"支付时间"`
-	PayType            string      `json:"payType"            orm:"pay_type"             description:"支付方式: balance/crypto"`
+	PayType            string      `json:"payType"            orm:"pay_type"             description:"支付方式: balance/crypto/free(试用)"`
+	AutoRenew          int         `json:"autoRenew"          orm:"auto_renew"           description:"自动续费: 0=关闭, 1=开启"`
+	RemindStage        int         `json:"remindStage"        orm:"remind_stage"         description:"到期提醒: 0=未提醒, 1=已提醒T-3天, 2=已提醒T-1天"`
+	GraceExpireTime    *gtime.Time `json:"graceExpireTime"    orm:"grace_expire_time"    description:"宽限期截止时间"`
+	CreditAmount       float64     `json:"creditAmount"       orm:"credit_amount"        description:"升降级折抵金额(原订阅剩余价值)"`
+	ReplacedBy         int64       `json:"replacedBy"         orm:"replaced_by"          description:"被替换的新订阅ID(升降级)"`
 	InviterId          int64       `json:"inviterId"          orm:"inviter_id"           description:"邀请人ID"`
 	CommissionSettled  int         `json:"commissionSettled"  orm:"commission_settled"   description:"佣金是否已结算"`
 	CreatedAt          *gtime.Time `json:"createdAt"          orm:"created_at"           description:"创建时间"`
@@ -0,0 +1,23 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package entity
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// ToogoSubscriptionLog is the golang structure for table hg_toogo_subscription_log.
+type ToogoSubscriptionLog struct {
+	Id             int64       `json:"id"             orm:"id"              description:"主键ID"`
+	SubscriptionId int64       `json:"subscriptionId" orm:"subscription_id" description:"订阅ID"`
+	UserId         int64       `json:"userId"         orm:"user_id"         description:"用户ID(member_id)"`
+	PlanId         int64       `json:"planId"         orm:"plan_id"         description:"套餐ID"`
+	Event          string      `json:"event"          orm:"event"           description:"事件"`
+	FromStatus     int         `json:"fromStatus"     orm:"from_status"     description:"变更前状态"`
+	ToStatus       int         `json:"toStatus"       orm:"to_status"       description:"变更后状态"`
+	Amount         float64     `json:"amount"         orm:"amount"          description:"涉及金额(USDT)"`
+	Remark         string      `json:"remark"         orm:"remark"          description:"备注"`
+	CreatedAt      *gtime.Time `json:"createdAt"      orm:"created_at"      description:"创建时间"`
+}
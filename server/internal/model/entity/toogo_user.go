@@ -11,6 +11,7 @@ import (
 // ToogoUser is the golang structure for table hg_toogo_user.
 type ToogoUser struct {
 	Id                  int64       `json:"id"                  orm:"id"                    description:"主键ID"`
+	TenantId            int64       `json:"tenantId"            orm:"tenant_id"             description:"租户ID，0=平台"`
 	MemberId            int64       `json:"memberId"            orm:"member_id"             description:"关联admin_member.id"`
 	VipLevel            int         `json:"vipLevel"            orm:"vip_level"             description:"身份等级: V1-V10"`
 	IsAgent             int         `json:"isAgent"             orm:"is_agent"              description:"是否代理商: 0=否, 1=是"`
@@ -28,9 +29,11 @@ type ToogoUser struct {
 	TeamConsumePower    float64     `json:"teamConsumePower"    orm:"team_consume_power"    description:"团队消耗算力"`
 	CurrentPlanId       int64       `json:"currentPlanId"       orm:"current_plan_id"       description:"当前订阅套餐ID"`
 	PlanExpireTime      *gtime.Time `json:"planExpireTime"      orm:"plan_expire_time"      description:"套餐到期时间"`
+	PlanGraceExpire     *gtime.Time `json:"planGraceExpire"     orm:"plan_grace_expire"     description:"套餐宽限期截止时间"`
 	RobotLimit          int         `json:"robotLimit"          orm:"robot_limit"           description:"机器人数量限制"`
 	ActiveRobotCount    int         `json:"activeRobotCount"    orm:"active_robot_count"    description:"运行中机器人数量"`
 	PowerDiscount       float64     `json:"powerDiscount"       orm:"power_discount"        description:"算力消耗折扣(%)"`
+	VipDowngradeAt      *gtime.Time `json:"vipDowngradeAt"      orm:"vip_downgrade_at"      description:"不满足当前VIP等级的起始时间(降级宽限期起点)"`
 	AgentApplyRemark    string      `json:"agentApplyRemark"    orm:"agent_apply_remark"    description:"代理商申请备注"`
 	AgentApplyAt        *gtime.Time `json:"agentApplyAt"        orm:"agent_apply_at"        description:"代理商申请时间"`
 	AgentApprovedAt     *gtime.Time `json:"agentApprovedAt"     orm:"agent_approved_at"     description:"代理商审批时间"`
@@ -0,0 +1,17 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package entity
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// ToogoUserRelation is the golang structure for table hg_toogo_user_relation.
+type ToogoUserRelation struct {
+	AncestorId   int64       `json:"ancestorId"   orm:"ancestor_id"   description:"上级ID(member_id)，含自身"`
+	DescendantId int64       `json:"descendantId" orm:"descendant_id" description:"下级ID(member_id)"`
+	Depth        int         `json:"depth"        orm:"depth"         description:"层级距离: 0=自身, 1=直推"`
+	CreatedAt    *gtime.Time `json:"createdAt"    orm:"created_at"    description:"创建时间"`
+}
@@ -0,0 +1,26 @@
+// =================================================================================
+// Code generated and target for Toogo.Ai system.
+// =================================================================================
+
+package entity
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// ToogoVipLevelLog is the golang structure for table hg_toogo_vip_level_log.
+type ToogoVipLevelLog struct {
+	Id                int64       `json:"id"                orm:"id"                  description:"主键ID"`
+	UserId            int64       `json:"userId"            orm:"user_id"             description:"用户ID(member_id)"`
+	FromLevel         int         `json:"fromLevel"         orm:"from_level"          description:"变更前等级"`
+	ToLevel           int         `json:"toLevel"           orm:"to_level"            description:"变更后等级"`
+	ChangeType        string      `json:"changeType"        orm:"change_type"         description:"变更类型: upgrade/downgrade"`
+	Source            string      `json:"source"            orm:"source"              description:"触发来源: cron/check/admin"`
+	InviteCount       int         `json:"inviteCount"       orm:"invite_count"        description:"评估时直推人数"`
+	ConsumePower      float64     `json:"consumePower"      orm:"consume_power"       description:"评估时个人消耗算力"`
+	TeamConsumePower  float64     `json:"teamConsumePower"  orm:"team_consume_power"  description:"评估时团队消耗算力"`
+	FromPowerDiscount float64     `json:"fromPowerDiscount" orm:"from_power_discount" description:"变更前算力折扣(%)"`
+	ToPowerDiscount   float64     `json:"toPowerDiscount"   orm:"to_power_discount"   description:"变更后算力折扣(%)"`
+	Remark            string      `json:"remark"            orm:"remark"              description:"备注"`
+	CreatedAt         *gtime.Time `json:"createdAt"         orm:"created_at"          description:"创建时间"`
+}
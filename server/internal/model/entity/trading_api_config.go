@@ -19,11 +19,21 @@ type TradingApiConfig struct {
 	ApiKey         string      `json:"apiKey"         orm:"api_key"            description:"API Key（加密）"`
 	SecretKey      string      `json:"secretKey"      orm:"secret_key"         description:"Secret Key（加密）"`
 	Passphrase     string      `json:"passphrase"     orm:"passphrase"         description:"Passphrase（加密，可选）"`
+	DataKey        string      `json:"-"              orm:"data_key"           description:"数据密钥（主密钥包装）"`
+	KeyVersion     int         `json:"keyVersion"     orm:"key_version"        description:"主密钥版本：0=旧版静态密钥"`
 	IsDefault      int         `json:"isDefault"      orm:"is_default"         description:"是否默认：0=否,1=是"`
 	Status         int         `json:"status"         orm:"status"             description:"状态：1=正常,2=禁用"`
 	LastVerifyTime *gtime.Time `json:"lastVerifyTime" orm:"last_verify_time"   description:"最后验证时间"`
 	VerifyStatus   int         `json:"verifyStatus"   orm:"verify_status"      description:"验证状态：0=未验证,1=成功,2=失败"`
 	VerifyMessage  string      `json:"verifyMessage"  orm:"verify_message"     description:"验证消息"`
+	PermStatus     int         `json:"permStatus"     orm:"perm_status"        description:"权限检测状态：0=未检测,1=正常,2=风险提示,3=不可用"`
+	PermFutures    int         `json:"permFutures"    orm:"perm_futures"       description:"合约交易权限：0=未开启,1=已开启,2=无法确认"`
+	PermWithdraw   int         `json:"permWithdraw"   orm:"perm_withdraw"      description:"提现权限：0=未开启,1=已开启,2=无法确认"`
+	IpRestricted   int         `json:"ipRestricted"   orm:"ip_restricted"      description:"是否绑定IP白名单：0=否,1=是"`
+	IpWhitelist    string      `json:"ipWhitelist"    orm:"ip_whitelist"       description:"IP白名单（逗号分隔）"`
+	KeyExpireAt    *gtime.Time `json:"keyExpireAt"    orm:"key_expire_at"      description:"API Key过期时间"`
+	PermMessage    string      `json:"permMessage"    orm:"perm_message"       description:"权限检测结果说明"`
+	PermCheckedAt  *gtime.Time `json:"permCheckedAt"  orm:"perm_checked_at"    description:"最后权限检测时间"`
 	Remark         string      `json:"remark"         orm:"remark"             description:"备注"`
 	CreatedAt      *gtime.Time `json:"createdAt"      orm:"created_at"         description:"创建时间"`
 	UpdatedAt      *gtime.Time `json:"updatedAt"      orm:"updated_at"         description:"更新时间"`
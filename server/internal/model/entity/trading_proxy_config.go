@@ -13,6 +13,8 @@ type TradingProxyConfig struct {
 	Id           int64       `json:"id"           orm:"id"               description:"主键ID"`
 	TenantId     int64       `json:"tenantId"     orm:"tenant_id"        description:"租户ID"`
 	UserId       int64       `json:"userId"       orm:"user_id"          description:"用户ID"`
+	Name         string      `json:"name"         orm:"name"             description:"代理名称"`
+	Weight       int         `json:"weight"       orm:"weight"           description:"选择权重"`
 	Enabled      int         `json:"enabled"      orm:"enabled"          description:"是否启用：0=禁用,1=启用"`
 	ProxyType    string      `json:"proxyType"    orm:"proxy_type"       description:"代理类型：socks5/http"`
 	ProxyAddress string      `json:"proxyAddress" orm:"proxy_address"    description:"代理地址"`
@@ -22,6 +24,8 @@ type TradingProxyConfig struct {
 	LastTestTime *gtime.Time `json:"lastTestTime" orm:"last_test_time"   description:"最后测试时间"`
 	TestStatus   int         `json:"testStatus"   orm:"test_status"      description:"测试状态：0=未测试,1=成功,2=失败"`
 	TestMessage  string      `json:"testMessage"  orm:"test_message"     description:"测试消息"`
+	Latency      int         `json:"latency"      orm:"latency"          description:"平均延迟(ms)"`
+	VenueLatency string      `json:"venueLatency" orm:"venue_latency"    description:"各交易所延迟(ms)，JSON"`
 	CreatedAt    *gtime.Time `json:"createdAt"    orm:"created_at"       description:"创建时间"`
 	UpdatedAt    *gtime.Time `json:"updatedAt"    orm:"updated_at"       description:"更新时间"`
 }
@@ -46,6 +46,10 @@ type TradingRobot struct {
 	AutoCloseEnabled        int         `json:"autoCloseEnabled"         orm:"auto_close_enabled"          description:"全自动平仓：0=否,1=是"`
 	ProfitLockEnabled       int         `json:"profitLockEnabled"        orm:"profit_lock_enabled"         description:"锁定盈利开关：0=关闭,1=开启（止盈启动后禁止自动开新仓）"`
 	DualSidePosition        int         `json:"dualSidePosition"         orm:"dual_side_position"          description:"双向开单：0=单向,1=双向"`
+	BasketId                int64       `json:"basketId"                 orm:"basket_id"                   description:"组合机器人ID：0=独立机器人"`
+	AllocationWeight        float64     `json:"allocationWeight"         orm:"allocation_weight"           description:"组合内分配权重"`
+	RobotMode               string      `json:"robotMode"                orm:"robot_mode"                  description:"运行模式：signal=方向信号,grid=网格"`
+	GridConfig              string      `json:"gridConfig"               orm:"grid_config"                 description:"网格配置(JSON)"`
 	ScheduleStart           *gtime.Time `json:"scheduleStart"            orm:"schedule_start"              description:"定时启动时间"`
 	ScheduleStop            *gtime.Time `json:"scheduleStop"             orm:"schedule_stop"               description:"定时停止时间"`
 	Remark                  string      `json:"remark"                   orm:"remark"                      description:"备注"`
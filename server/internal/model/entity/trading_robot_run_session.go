@@ -12,6 +12,7 @@ import (
 type TradingRobotRunSession struct {
 	Id             int64       `json:"id"             orm:"id"              description:"主键ID"`
 	RobotId        int64       `json:"robotId"        orm:"robot_id"        description:"机器人ID"`
+	BasketId       int64       `json:"basketId"       orm:"basket_id"       description:"组合机器人ID：0=独立机器人"`
 	UserId         int64       `json:"userId"         orm:"user_id"         description:"用户ID"`
 	Exchange       string      `json:"exchange"       orm:"exchange"        description:"交易所"`
 	Symbol         string      `json:"symbol"         orm:"symbol"          description:"交易对"`
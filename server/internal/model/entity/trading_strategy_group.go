@@ -11,6 +11,7 @@ import (
 // TradingStrategyGroup 策略模板表
 type TradingStrategyGroup struct {
 	Id             int64       `json:"id"             orm:"id"               description:"主键ID"`
+	TenantId       int64       `json:"tenantId"       orm:"tenant_id"        description:"租户ID，0=平台"`
 	GroupName      string      `json:"groupName"      orm:"group_name"       description:"模板名称"`
 	GroupKey       string      `json:"groupKey"       orm:"group_key"        description:"模板标识"`
 	Exchange       string      `json:"exchange"       orm:"exchange"         description:"交易平台"`
@@ -27,4 +28,3 @@ type TradingStrategyGroup struct {
 	CreatedAt      *gtime.Time `json:"createdAt"      orm:"created_at"       description:"创建时间"`
 	UpdatedAt      *gtime.Time `json:"updatedAt"      orm:"updated_at"       description:"更新时间"`
 }
-
@@ -34,26 +34,44 @@ func (in *RegisterInp) Filter(ctx context.Context) (err error) {
 }
 
 // LoginModel 统一登录响应
+// 需要双因素认证时不返回 token，前端凭 TwoFactorTicket 提交动态口令完成登录
 type LoginModel struct {
-	Id       int64  `json:"id"              dc:"用户ID"`
-	Username string `json:"username"        dc:"用户名"`
-	Token    string `json:"token"           dc:"登录token"`
-	Expires  int64  `json:"expires"         dc:"登录有效期"`
+	Id                int64    `json:"id"                          dc:"用户ID"`
+	Username          string   `json:"username"                    dc:"用户名"`
+	Token             string   `json:"token"                       dc:"登录token"`
+	Expires           int64    `json:"expires"                     dc:"登录有效期"`
+	TwoFactorRequired bool     `json:"twoFactorRequired,omitempty" dc:"是否需要双因素认证"`
+	TwoFactorSetup    bool     `json:"twoFactorSetup,omitempty"    dc:"是否需要先绑定验证器"`
+	TwoFactorTicket   string   `json:"twoFactorTicket,omitempty"   dc:"双因素认证登录票据"`
+	RecoveryCodes     []string `json:"recoveryCodes,omitempty"     dc:"登录时完成绑定返回的恢复码，仅展示一次"`
 }
 
 // AccountLoginInp 账号登录
 type AccountLoginInp struct {
-	Username string `json:"username" v:"required#用户名不能为空" dc:"用户名"`
-	Password string `json:"password" v:"required#密码不能为空" dc:"密码"`
-	Cid      string `json:"cid"  dc:"验证码ID"`
-	Code     string `json:"code" dc:"验证码"`
-	IsLock   bool   `json:"isLock"  dc:"是否为锁屏状态"`
+	Username      string `json:"username" v:"required#用户名不能为空" dc:"用户名"`
+	Password      string `json:"password" v:"required#密码不能为空" dc:"密码"`
+	Cid           string `json:"cid"  dc:"验证码ID"`
+	Code          string `json:"code" dc:"验证码"`
+	IsLock        bool   `json:"isLock"  dc:"是否为锁屏状态"`
+	TwoFactorCode string `json:"twoFactorCode" dc:"双因素认证动态口令，已启用时可随登录一并提交"`
 }
 
 // MobileLoginInp 手机号登录
 type MobileLoginInp struct {
-	Mobile string `json:"mobile" v:"required|phone-loose#手机号不能为空|手机号格式不正确" dc:"手机号"`
-	Code   string `json:"code" v:"required#验证码不能为空"  dc:"验证码"`
+	Mobile        string `json:"mobile" v:"required|phone-loose#手机号不能为空|手机号格式不正确" dc:"手机号"`
+	Code          string `json:"code" v:"required#验证码不能为空"  dc:"验证码"`
+	TwoFactorCode string `json:"twoFactorCode" dc:"双因素认证动态口令，已启用时可随登录一并提交"`
+}
+
+// TwoFactorLoginSetupInp 登录时绑定验证器
+type TwoFactorLoginSetupInp struct {
+	Ticket string `json:"ticket" v:"required#登录票据不能为空" dc:"双因素认证登录票据"`
+}
+
+// TwoFactorLoginInp 提交动态口令完成登录
+type TwoFactorLoginInp struct {
+	Ticket string `json:"ticket" v:"required#登录票据不能为空" dc:"双因素认证登录票据"`
+	Code   string `json:"code" v:"required#请输入动态口令或恢复码" dc:"动态口令或恢复码"`
 }
 
 // MemberLoginPermissions 登录用户角色信息
@@ -4,6 +4,9 @@ import (
 	"context"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/form"
+	"hotgo/internal/model/input/toogoin"
+
+	"github.com/gogf/gf/v2/net/ghttp"
 )
 
 // SupportAgentOnlineInp 客服上下线
@@ -42,6 +45,17 @@ type SupportSendInp struct {
 	Content   string `json:"content" v:"required#消息内容不能为空" dc:"消息内容"`
 }
 
+// SupportSendAttachmentInp 发送附件（客服端）
+type SupportSendAttachmentInp struct {
+	SessionId int64             `json:"sessionId" v:"required#会话ID不能为空" dc:"会话ID"`
+	File      *ghttp.UploadFile `json:"file" type:"file" dc:"附件"`
+}
+
+// SupportReadInp 标记已读
+type SupportReadInp struct {
+	SessionId int64 `json:"sessionId" v:"required#会话ID不能为空" dc:"会话ID"`
+}
+
 // SupportMessageListInp 消息列表
 type SupportMessageListInp struct {
 	form.PageReq
@@ -54,6 +68,40 @@ type SupportTransferInp struct {
 	ToAgentId int64 `json:"toAgentId" v:"required#目标客服ID不能为空" dc:"目标客服ID"`
 }
 
+// SupportEscalateInp 升级为工单（关联机器人/订单）
+type SupportEscalateInp struct {
+	SessionId int64  `json:"sessionId" v:"required#会话ID不能为空" dc:"会话ID"`
+	RobotId   int64  `json:"robotId" dc:"关联机器人ID"`
+	OrderId   int64  `json:"orderId" dc:"关联订单ID（填写后机器人按订单归属自动补全）"`
+	Remark    string `json:"remark" v:"max-length:500#工单说明不能超过500个字符" dc:"工单说明"`
+}
+
+// SupportExecutionLogListInp 工单关联的执行日志
+type SupportExecutionLogListInp struct {
+	form.PageReq
+	SessionId int64  `json:"sessionId" v:"required#会话ID不能为空" dc:"会话ID"`
+	Status    string `json:"status" dc:"状态：pending/success/failed"`
+}
+
+// SupportContextInp 会话用户上下文
+type SupportContextInp struct {
+	SessionId int64 `json:"sessionId" v:"required#会话ID不能为空" dc:"会话ID"`
+}
+
+// SupportContextModel 会话用户上下文
+type SupportContextModel struct {
+	SessionId int64 `json:"sessionId" dc:"会话ID"`
+	*toogoin.SupportContextModel
+	Actions []string `json:"actions" dc:"当前客服有权限执行的操作（接口路由）"`
+}
+
+// SupportRobotActionInp 对会话用户的机器人执行操作
+type SupportRobotActionInp struct {
+	SessionId int64  `json:"sessionId" v:"required#会话ID不能为空" dc:"会话ID"`
+	RobotId   int64  `json:"robotId" v:"required#机器人ID不能为空" dc:"机器人ID"`
+	Remark    string `json:"remark" v:"max-length:200#备注不能超过200个字符" dc:"操作备注（写入审计日志）"`
+}
+
 // SupportCannedListInp 常用语列表
 type SupportCannedListInp struct {
 	form.PageReq
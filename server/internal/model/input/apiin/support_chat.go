@@ -3,6 +3,8 @@ package apiin
 import (
 	"context"
 	"hotgo/internal/model/input/form"
+
+	"github.com/gogf/gf/v2/net/ghttp"
 )
 
 // SupportStartSessionInp 发起/获取我的客服会话
@@ -23,3 +25,21 @@ type SupportMessageListInp struct {
 }
 
 
+
+// SupportSendAttachmentInp 发送附件（用户端）
+type SupportSendAttachmentInp struct {
+	SessionId int64             `json:"sessionId" v:"required#会话ID不能为空" dc:"会话ID"`
+	File      *ghttp.UploadFile `json:"file" type:"file" dc:"附件（截图等）"`
+}
+
+// SupportReadInp 标记已读（用户端）
+type SupportReadInp struct {
+	SessionId int64 `json:"sessionId" v:"required#会话ID不能为空" dc:"会话ID"`
+}
+
+// SupportRateInp 会话评价（用户端）
+type SupportRateInp struct {
+	SessionId int64  `json:"sessionId" v:"required#会话ID不能为空" dc:"会话ID"`
+	Rating    int    `json:"rating" v:"required|between:1,5#请选择评分|评分范围为1-5" dc:"评分：1-5"`
+	Remark    string `json:"remark" v:"max-length:500#评价内容不能超过500个字符" dc:"评价内容"`
+}
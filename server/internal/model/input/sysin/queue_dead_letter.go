@@ -0,0 +1,52 @@
+// Package sysin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package sysin
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/model/entity"
+	"hotgo/internal/model/input/form"
+)
+
+// QueueDeadLetterListInp 获取死信列表
+type QueueDeadLetterListInp struct {
+	form.PageReq
+	form.StatusReq
+	Topic     string        `json:"topic"     dc:"主题"`
+	CreatedAt []*gtime.Time `json:"createdAt" dc:"进入死信时间"`
+}
+
+type QueueDeadLetterListModel struct {
+	entity.SysQueueDeadLetter
+}
+
+// QueueDeadLetterViewInp 获取死信信息
+type QueueDeadLetterViewInp struct {
+	Id int64 `json:"id" v:"required#死信ID不能为空" dc:"死信ID"`
+}
+
+type QueueDeadLetterViewModel struct {
+	entity.SysQueueDeadLetter
+}
+
+// QueueDeadLetterReplayInp 重放死信
+type QueueDeadLetterReplayInp struct {
+	Id []int64 `json:"id" v:"required#死信ID不能为空" dc:"死信ID"`
+}
+
+type QueueDeadLetterReplayModel struct {
+	Replayed int `json:"replayed" dc:"成功重放数量"`
+}
+
+// QueueDeadLetterIgnoreInp 忽略死信
+type QueueDeadLetterIgnoreInp struct {
+	Id []int64 `json:"id" v:"required#死信ID不能为空" dc:"死信ID"`
+}
+
+// QueueDeadLetterDeleteInp 删除死信
+type QueueDeadLetterDeleteInp struct {
+	Id interface{} `json:"id" v:"required#死信ID不能为空" dc:"死信ID"`
+}
@@ -0,0 +1,45 @@
+// Package sysin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package sysin
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// TwoFactorStatusModel 双因素认证状态
+type TwoFactorStatusModel struct {
+	Enabled           bool        `json:"enabled"           dc:"是否已启用"`
+	Required          bool        `json:"required"          dc:"当前角色是否强制启用"`
+	RecoveryRemaining int         `json:"recoveryRemaining" dc:"剩余可用恢复码数量"`
+	EnabledAt         *gtime.Time `json:"enabledAt"         dc:"启用时间"`
+}
+
+// TwoFactorSetupModel 待绑定的验证器信息
+type TwoFactorSetupModel struct {
+	Secret     string `json:"secret"     dc:"验证器密钥，无法扫码时手动输入"`
+	OtpauthUrl string `json:"otpauthUrl" dc:"验证器绑定地址，前端生成二维码"`
+}
+
+// TwoFactorCodeInp 提交动态口令
+type TwoFactorCodeInp struct {
+	Code string `json:"code" v:"required#请输入动态口令或恢复码" dc:"动态口令或恢复码"`
+}
+
+// TwoFactorRecoveryModel 恢复码
+type TwoFactorRecoveryModel struct {
+	RecoveryCodes []string `json:"recoveryCodes" dc:"恢复码，仅展示一次，每个只能使用一次"`
+}
+
+// TwoFactorVerifyModel 敏感操作二次验证结果
+type TwoFactorVerifyModel struct {
+	ExpiresIn int64 `json:"expiresIn" dc:"免验证剩余秒数，期间执行敏感操作无需再次输入口令"`
+}
+
+// TwoFactorLoginTicket 登录二次验证票据
+type TwoFactorLoginTicket struct {
+	MemberId int64 `json:"memberId"`
+	Attempts int   `json:"attempts"`
+}
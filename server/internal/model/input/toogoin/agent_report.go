@@ -0,0 +1,135 @@
+// Package toogoin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+package toogoin
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// AgentDashboardInp 代理看板输入
+type AgentDashboardInp struct {
+	MemberId  int64    `json:"memberId" description:"代理用户ID（取当前登录用户）"`
+	CreatedAt []string `json:"createdAt" description:"统计区间[开始,结束]，默认近30天"`
+	Interval  string   `json:"interval" v:"in:day,month" description:"团队增长统计粒度: day=按天, month=按月，默认day"`
+	TopLimit  int      `json:"topLimit" v:"between:0,100" description:"贡献排行数量，默认10"`
+}
+
+// AgentDashboardModel 代理看板返回
+type AgentDashboardModel struct {
+	StartTime  string                 `json:"startTime" description:"统计开始时间"`
+	EndTime    string                 `json:"endTime" description:"统计结束时间"`
+	Overview   *AgentOverview         `json:"overview" description:"概览"`
+	Growth     []*AgentTeamGrowth     `json:"growth" description:"团队增长趋势"`
+	Levels     []*AgentLevelReport    `json:"levels" description:"按下级层级统计"`
+	TopMembers []*AgentTopMember      `json:"topMembers" description:"贡献佣金最多的成员"`
+	Conversion *AgentInviteConversion `json:"conversion" description:"邀请转化（统计区间内注册的成员）"`
+}
+
+// AgentOverview 代理看板概览
+type AgentOverview struct {
+	TeamCount           int     `json:"teamCount" description:"团队人数（未解锁层级时仅直推）"`
+	DirectCount         int     `json:"directCount" description:"直推人数"`
+	NewMembers          int     `json:"newMembers" description:"区间内新增成员"`
+	ActiveRobots        int     `json:"activeRobots" description:"团队运行中机器人"`
+	SubscriptionRevenue float64 `json:"subscriptionRevenue" description:"区间内团队订阅金额(USDT，扣除升降级折抵)"`
+	Commission          float64 `json:"commission" description:"区间内佣金（扣除冲正）"`
+	FrozenCommission    float64 `json:"frozenCommission" description:"结算期内冻结中的佣金"`
+	CommissionBalance   float64 `json:"commissionBalance" description:"当前佣金余额"`
+}
+
+// AgentTeamGrowth 团队增长
+type AgentTeamGrowth struct {
+	Date         string `json:"date" description:"日期(Y-m-d / Y-m)"`
+	NewMembers   int    `json:"newMembers" description:"新增成员"`
+	TotalMembers int    `json:"totalMembers" description:"累计成员"`
+}
+
+// AgentLevelReport 按下级层级统计
+type AgentLevelReport struct {
+	Level               int       `json:"level" description:"层级: 1=直推"`
+	MemberCount         int       `json:"memberCount" description:"成员数"`
+	ActiveRobots        int       `json:"activeRobots" description:"运行中机器人"`
+	SubscriptionCount   int       `json:"subscriptionCount" description:"区间内付费订阅笔数"`
+	SubscriptionRevenue float64   `json:"subscriptionRevenue" description:"区间内订阅金额(USDT，扣除升降级折抵)"`
+	BaseAmount          float64   `json:"baseAmount" description:"区间内计佣基数"`
+	Commission          float64   `json:"commission" description:"区间内佣金（扣除冲正）"`
+	RateDiffs           []float64 `json:"rateDiffs" description:"区间内使用过的级差比例(%)"`
+	EffectiveRate       float64   `json:"effectiveRate" description:"实际佣金率(%) = 佣金 / 计佣基数"`
+}
+
+// AgentTopMember 贡献佣金排行
+type AgentTopMember struct {
+	MemberId            int64   `json:"memberId" description:"成员ID"`
+	Username            string  `json:"username" description:"用户名"`
+	Level               int     `json:"level" description:"层级"`
+	SubscriptionRevenue float64 `json:"subscriptionRevenue" description:"区间内订阅金额(USDT，扣除升降级折抵)"`
+	Commission          float64 `json:"commission" description:"区间内贡献佣金"`
+}
+
+// AgentInviteConversion 邀请转化
+type AgentInviteConversion struct {
+	Invited        int     `json:"invited" description:"区间内注册成员"`
+	TrialMembers   int     `json:"trialMembers" description:"其中领取过免费试用"`
+	PaidMembers    int     `json:"paidMembers" description:"其中购买过付费套餐"`
+	ConversionRate float64 `json:"conversionRate" description:"付费转化率(%)"`
+}
+
+// AgentStatementInp 代理月度对账单输入
+type AgentStatementInp struct {
+	MemberId int64  `json:"memberId" description:"代理用户ID（取当前登录用户）"`
+	Month    string `json:"month" v:"required|date-format:Y-m#请选择账单月份|账单月份格式为 Y-m" description:"账单月份，如 2026-10"`
+}
+
+// AgentStatementExportInp 代理月度对账单下载输入
+type AgentStatementExportInp struct {
+	AgentStatementInp
+	Format string `json:"format" v:"in:csv,pdf" description:"文件格式: csv/pdf，默认csv"`
+}
+
+// AgentStatementModel 代理月度对账单
+type AgentStatementModel struct {
+	MemberId          int64                       `json:"memberId" description:"代理用户ID"`
+	Username          string                      `json:"username" description:"用户名"`
+	Month             string                      `json:"month" description:"账单月份"`
+	Earned            float64                     `json:"earned" description:"本月新增佣金"`
+	Reversed          float64                     `json:"reversed" description:"本月冲正佣金"`
+	NetCommission     float64                     `json:"netCommission" description:"本月净佣金 = 新增 - 冲正"`
+	Released          float64                     `json:"released" description:"本月解冻入账"`
+	Withdrawn         float64                     `json:"withdrawn" description:"本月完成提现(申请金额)"`
+	FrozenCommission  float64                     `json:"frozenCommission" description:"当前冻结中的佣金"`
+	CommissionBalance float64                     `json:"commissionBalance" description:"当前佣金余额"`
+	LedgerCredited    float64                     `json:"ledgerCredited" description:"本月账户流水记入的佣金（佣金/冻结佣金账户）"`
+	LedgerReleased    float64                     `json:"ledgerReleased" description:"本月账户流水记入的解冻金额"`
+	Reconciled        bool                        `json:"reconciled" description:"佣金记录与账户流水是否一致"`
+	Commissions       []*AgentStatementCommission `json:"commissions" description:"佣金明细"`
+	Withdraws         []*AgentStatementWithdraw   `json:"withdraws" description:"提现明细"`
+}
+
+// AgentStatementCommission 对账单佣金明细
+type AgentStatementCommission struct {
+	Id             int64       `json:"id" description:"佣金记录ID"`
+	CreatedAt      *gtime.Time `json:"createdAt" description:"时间"`
+	CommissionType string      `json:"commissionType" description:"佣金类型"`
+	FromUserId     int64       `json:"fromUserId" description:"来源用户ID"`
+	FromUsername   string      `json:"fromUsername" description:"来源用户名"`
+	Level          int         `json:"level" description:"层级"`
+	BaseAmount     float64     `json:"baseAmount" description:"计佣基数"`
+	RateDiff       float64     `json:"rateDiff" description:"级差比例(%)"`
+	Amount         float64     `json:"amount" description:"佣金金额"`
+	Status         int         `json:"status" description:"状态: 1=冻结中, 2=已结算, 3=已冲正"`
+	ReversalOf     int64       `json:"reversalOf" description:"冲正的原佣金记录ID"`
+	OrderSn        string      `json:"orderSn" description:"来源订单号"`
+}
+
+// AgentStatementWithdraw 对账单提现明细
+type AgentStatementWithdraw struct {
+	OrderSn     string      `json:"orderSn" description:"提现订单号"`
+	Amount      float64     `json:"amount" description:"提现金额"`
+	Fee         float64     `json:"fee" description:"手续费"`
+	RealAmount  float64     `json:"realAmount" description:"实际到账"`
+	Status      int         `json:"status" description:"状态: 1=待审核, 2=审核通过, 3=审核拒绝, 4=已完成, 5=已取消"`
+	CreatedAt   *gtime.Time `json:"createdAt" description:"申请时间"`
+	CompletedAt *gtime.Time `json:"completedAt" description:"完成时间"`
+}
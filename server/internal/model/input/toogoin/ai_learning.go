@@ -0,0 +1,49 @@
+// Package toogoin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+package toogoin
+
+// AI学习模式
+const (
+	AiLearningModeOff    = "off"    // 未启用
+	AiLearningModeShadow = "shadow" // 影子模式：只生成报告
+	AiLearningModeLive   = "live"   // 正式模式：学习权重生效
+)
+
+// AiLearningReportInp 学习报告输入
+type AiLearningReportInp struct {
+	Symbol string `json:"symbol" description:"交易对（留空返回全部）"`
+}
+
+// AiLearningRunModel 一轮学习结果
+type AiLearningRunModel struct {
+	Mode          string   `json:"mode"          description:"学习模式: off/shadow/live"`
+	LearnedOrders int      `json:"learnedOrders" description:"本轮计入统计的订单数"`
+	Symbols       []string `json:"symbols"       description:"已生成学习权重的交易对"`
+}
+
+// AiLearningReportModel 学习报告（当前权重 vs 学习权重）
+type AiLearningReportModel struct {
+	Mode    string                   `json:"mode"    description:"学习模式: off/shadow/live"`
+	Symbols []*AiLearningSymbolModel `json:"symbols" description:"各交易对学习结果"`
+}
+
+// AiLearningSymbolModel 单个交易对的学习结果
+type AiLearningSymbolModel struct {
+	Symbol     string                              `json:"symbol"     description:"交易对"`
+	Timeframes []*AiLearningSourceModel            `json:"timeframes" description:"周期投票权重"`
+	RiskStates map[string][]*AiLearningSourceModel `json:"riskStates" description:"各市场状态下的风险维度权重"`
+}
+
+// AiLearningSourceModel 单个信号来源的统计与权重
+type AiLearningSourceModel struct {
+	Source         string  `json:"source"         description:"信号来源: 周期或风险维度"`
+	TotalSignals   int     `json:"totalSignals"   description:"总信号数"`
+	CorrectSignals int     `json:"correctSignals" description:"正确信号数"`
+	AccuracyRate   float64 `json:"accuracyRate"   description:"准确率"`
+	TotalProfit    float64 `json:"totalProfit"    description:"总收益"`
+	AvgProfit      float64 `json:"avgProfit"      description:"平均收益"`
+	CurrentWeight  float64 `json:"currentWeight"  description:"当前配置权重"`
+	LearnedWeight  float64 `json:"learnedWeight"  description:"学习后的权重"`
+}
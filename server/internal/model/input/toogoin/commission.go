@@ -15,6 +15,7 @@ type CommissionLogListInp struct {
 	UserId         int64    `json:"userId" description:"用户ID"`
 	CommissionType string   `json:"commissionType" description:"佣金类型"`
 	Level          int      `json:"level" description:"层级"`
+	Status         int      `json:"status" description:"状态: 1=冻结中, 2=已结算, 3=已冲正"`
 	CreatedAt      []string `json:"createdAt" description:"创建时间"`
 }
 
@@ -37,6 +38,8 @@ type CommissionStatModel struct {
 	MonthCommission   float64 `json:"monthCommission" description:"本月佣金"`
 	InviteReward      float64 `json:"inviteReward" description:"邀请奖励(积分)"`
 	SubscribeCommission float64 `json:"subscribeCommission" description:"订阅佣金"`
+	FrozenCommission    float64 `json:"frozenCommission" description:"结算期内冻结中的佣金"`
+	ReversedCommission  float64 `json:"reversedCommission" description:"来源订单退款已冲正的佣金"`
 }
 
 // SettleCommissionInp 结算佣金输入(内部使用)
@@ -7,12 +7,15 @@ package toogoin
 import (
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/form"
+
+	"github.com/gogf/gf/v2/os/gtime"
 )
 
 // PlanListInp 套餐列表输入
 type PlanListInp struct {
 	form.PageReq
-	Status int `json:"status" description:"状态"`
+	Status   int    `json:"status" description:"状态"`
+	TenantId *int64 `json:"tenantId" description:"租户ID（超管跨租户查看）"`
 }
 
 // PlanListModel 套餐列表返回
@@ -42,11 +45,14 @@ type PlanEditInp struct {
 	GiftPowerQuarterly float64 `json:"giftPowerQuarterly" description:"季赠送积分"`
 	GiftPowerHalfYear  float64 `json:"giftPowerHalfYear" description:"半年赠送积分"`
 	GiftPowerYearly    float64 `json:"giftPowerYearly" description:"年赠送积分"`
+	TrialDays          int     `json:"trialDays" v:"min:0" description:"免费试用天数，0为不支持试用"`
+	TrialRobotLimit    int     `json:"trialRobotLimit" v:"min:0" description:"试用期机器人数量，0为与套餐相同"`
 	Description        string  `json:"description" description:"套餐描述"`
 	Features           string  `json:"features" description:"套餐特性"`
 	IsDefault          int     `json:"isDefault" description:"是否默认"`
 	Sort               int     `json:"sort" description:"排序"`
 	Status             int     `json:"status" description:"状态"`
+	TenantId           *int64  `json:"tenantId" description:"所属租户ID，仅超管可指定，0为平台；编辑时不传保持原归属"`
 }
 
 // PlanDeleteInp 删除套餐输入
@@ -58,9 +64,11 @@ type PlanDeleteInp struct {
 type SubscribeInp struct {
 	UserId     int64  `json:"userId" description:"用户ID（不传则从上下文获取）"`
 	PlanId     int64  `json:"planId" v:"required" description:"套餐ID"`
-	PeriodType string `json:"periodType" v:"required|in:daily,monthly,quarterly,half_year,yearly" description:"订阅周期"`
+	PeriodType string `json:"periodType" v:"required|in:daily,monthly,quarterly,half_year,yearly,trial" description:"订阅周期（trial=免费试用）"`
 	PayType    string `json:"payType" v:"required|in:balance,crypto" description:"支付方式"`
 	UsePoints  bool   `json:"usePoints" description:"是否使用积分抵扣"`
+	AutoRenew  bool   `json:"autoRenew" description:"是否开启自动续费（到期前从余额续费同套餐同周期）"`
+	CouponCode string `json:"couponCode" description:"优惠码/推广码"`
 }
 
 // SubscribeModel 订阅套餐返回
@@ -68,6 +76,7 @@ type SubscribeModel struct {
 	OrderSn      string  `json:"orderSn" description:"订单号"`
 	PlanName     string  `json:"planName" description:"套餐名称"`
 	Amount       float64 `json:"amount" description:"订阅原价"`
+	Discount     float64 `json:"discount" description:"优惠券抵扣金额"`
 	PointsDeduct float64 `json:"pointsDeduct" description:"积分抵扣金额"`
 	BalancePaid  float64 `json:"balancePaid" description:"余额支付金额"`
 	Days         int     `json:"days" description:"订阅天数"`
@@ -103,5 +112,149 @@ type MySubscriptionModel struct {
 	ActiveRobots    int     `json:"activeRobots" description:"运行中机器人"`
 	ExpireTime      string  `json:"expireTime" description:"到期时间"`
 	RemainingDays   int     `json:"remainingDays" description:"剩余天数"`
+	InGrace         bool    `json:"inGrace" description:"是否处于宽限期（只管理持仓，不再开新仓）"`
+	GraceExpireTime string  `json:"graceExpireTime" description:"宽限期截止时间"`
+	AutoRenew       bool    `json:"autoRenew" description:"是否开启自动续费"`
+}
+
+// ChangePlanInp 升级/降级套餐输入
+type ChangePlanInp struct {
+	UserId     int64  `json:"userId" description:"用户ID（不传则从上下文获取）"`
+	PlanId     int64  `json:"planId" v:"required" description:"目标套餐ID"`
+	PeriodType string `json:"periodType" v:"required|in:daily,monthly,quarterly,half_year,yearly" description:"订阅周期"`
+	UsePoints  bool   `json:"usePoints" description:"是否使用积分抵扣"`
+	AutoRenew  bool   `json:"autoRenew" description:"是否开启自动续费"`
 }
 
+// ChangePlanModel 升级/降级套餐返回（试算时 OrderSn 为空）
+type ChangePlanModel struct {
+	OrderSn      string  `json:"orderSn" description:"订单号"`
+	Direction    string  `json:"direction" description:"变更方向: upgrade/downgrade"`
+	PlanName     string  `json:"planName" description:"目标套餐名称"`
+	Amount       float64 `json:"amount" description:"目标套餐原价"`
+	CreditAmount float64 `json:"creditAmount" description:"原订阅剩余价值折抵"`
+	PointsDeduct float64 `json:"pointsDeduct" description:"积分抵扣金额"`
+	BalancePaid  float64 `json:"balancePaid" description:"余额支付金额"`
+	Days         int     `json:"days" description:"订阅天数"`
+	BonusHours   int     `json:"bonusHours" description:"折抵超出部分折算的额外时长(小时)"`
+	ExpireTime   string  `json:"expireTime" description:"到期时间"`
+}
+
+// SubscriptionAutoRenewInp 设置自动续费输入
+type SubscriptionAutoRenewInp struct {
+	UserId    int64 `json:"userId" description:"用户ID（不传则从上下文获取）"`
+	AutoRenew int   `json:"autoRenew" v:"in:0,1" description:"自动续费: 0=关闭, 1=开启"`
+}
+
+// SubscriptionRefundInp 订阅退款输入（管理员）
+type SubscriptionRefundInp struct {
+	Id     int64  `json:"id" v:"required#订阅ID不能为空" description:"订阅ID"`
+	Reason string `json:"reason" v:"required#退款原因不能为空" description:"退款原因"`
+}
+
+// SubscriptionRefundModel 订阅退款返回
+type SubscriptionRefundModel struct {
+	RefundBalance      float64 `json:"refundBalance" description:"退回余额(USDT)"`
+	RefundPoints       float64 `json:"refundPoints" description:"退回积分"`
+	ReversedCommission float64 `json:"reversedCommission" description:"冲正的代理佣金合计(USDT)"`
+}
+
+// OrderRefundInp 支付订单退款联动输入
+type OrderRefundInp struct {
+	OrderSn string  `json:"orderSn" description:"业务订单号"`
+	Ratio   float64 `json:"ratio" description:"退款比例(0,1]"`
+	Reason  string  `json:"reason" description:"退款原因"`
+}
+
+// OrderRefundModel 支付订单退款联动结果
+type OrderRefundModel struct {
+	SubscriptionId     int64   `json:"subscriptionId" description:"关联订阅ID，0=非订阅订单"`
+	UserId             int64   `json:"userId" description:"订阅用户ID"`
+	Cancelled          bool    `json:"cancelled" description:"订阅是否已取消"`
+	ExpireTime         string  `json:"expireTime" description:"缩短后的到期时间"`
+	HasPlan            bool    `json:"hasPlan" description:"用户是否仍有生效中的订阅"`
+	ReversedCommission float64 `json:"reversedCommission" description:"冲正的代理佣金合计(USDT)"`
+}
+
+// SubscriptionLogListInp 订阅变更记录列表输入
+type SubscriptionLogListInp struct {
+	form.PageReq
+	UserId         int64  `json:"userId" description:"用户ID"`
+	SubscriptionId int64  `json:"subscriptionId" description:"订阅ID"`
+	Event          string `json:"event" description:"事件"`
+}
+
+// SubscriptionLogListModel 订阅变更记录列表返回
+type SubscriptionLogListModel struct {
+	*entity.ToogoSubscriptionLog
+}
+
+// CouponListInp 优惠券列表输入
+type CouponListInp struct {
+	form.PageReq
+	Code    string `json:"code" description:"优惠码"`
+	AgentId  int64  `json:"agentId" description:"归属代理ID"`
+	Status   int    `json:"status" description:"状态"`
+	TenantId *int64 `json:"tenantId" description:"租户ID（超管跨租户查看）"`
+}
+
+// CouponListModel 优惠券列表返回
+type CouponListModel struct {
+	*entity.ToogoCoupon
+}
+
+// CouponEditInp 编辑优惠券输入
+type CouponEditInp struct {
+	Id            int64       `json:"id" description:"优惠券ID"`
+	Code          string      `json:"code" v:"required|length:4,32" description:"优惠码（字母、数字、下划线或中划线，不区分大小写）"`
+	CouponName    string      `json:"couponName" description:"优惠券名称"`
+	CouponType    string      `json:"couponType" v:"required|in:percent,fixed" description:"类型: percent=按比例, fixed=固定金额"`
+	DiscountValue float64     `json:"discountValue" v:"required|min:0.01" description:"折扣值: percent为百分比(0-100), fixed为USDT"`
+	MaxDiscount   float64     `json:"maxDiscount" v:"min:0" description:"最高抵扣金额(USDT)，0为不限"`
+	MinAmount     float64     `json:"minAmount" v:"min:0" description:"最低订单金额(USDT)，0为不限"`
+	PlanIds       []int64     `json:"planIds" description:"限定套餐ID，空为不限"`
+	PeriodTypes   []string    `json:"periodTypes" description:"限定订阅周期，空为不限"`
+	TotalLimit    int         `json:"totalLimit" v:"min:0" description:"总使用次数上限，0为不限"`
+	AgentId       int64       `json:"agentId" description:"归属代理ID(推广码)，0为平台券"`
+	TenantId      *int64      `json:"tenantId" description:"所属租户ID，仅超管可指定，0为平台；推广码默认跟随代理所属租户"`
+	StartTime     *gtime.Time `json:"startTime" description:"生效时间"`
+	ExpireTime    *gtime.Time `json:"expireTime" description:"过期时间"`
+	Status        int         `json:"status" v:"in:1,2" description:"状态: 1=启用, 2=停用"`
+	Remark        string      `json:"remark" description:"备注"`
+}
+
+// CouponDeleteInp 删除优惠券输入
+type CouponDeleteInp struct {
+	Id int64 `json:"id" v:"required" description:"优惠券ID"`
+}
+
+// CouponUsageListInp 优惠券使用记录输入
+type CouponUsageListInp struct {
+	form.PageReq
+	CouponId int64 `json:"couponId" description:"优惠券ID"`
+	UserId   int64 `json:"userId" description:"用户ID"`
+	AgentId  int64 `json:"agentId" description:"推广码归属代理ID"`
+}
+
+// CouponUsageListModel 优惠券使用记录返回
+type CouponUsageListModel struct {
+	*entity.ToogoCouponUsage
+}
+
+// CouponPreviewInp 优惠码试算输入
+type CouponPreviewInp struct {
+	UserId     int64  `json:"userId" description:"用户ID（不传则从上下文获取）"`
+	CouponCode string `json:"couponCode" v:"required" description:"优惠码/推广码"`
+	PlanId     int64  `json:"planId" v:"required" description:"套餐ID"`
+	PeriodType string `json:"periodType" v:"required|in:daily,monthly,quarterly,half_year,yearly" description:"订阅周期"`
+}
+
+// CouponPreviewModel 优惠码试算返回
+type CouponPreviewModel struct {
+	CouponCode     string  `json:"couponCode" description:"优惠码"`
+	CouponName     string  `json:"couponName" description:"优惠券名称"`
+	OriginalAmount float64 `json:"originalAmount" description:"原价"`
+	Discount       float64 `json:"discount" description:"优惠券抵扣金额"`
+	PayAmount      float64 `json:"payAmount" description:"优惠后应付金额"`
+	IsAgentCode    bool    `json:"isAgentCode" description:"是否代理推广码"`
+}
@@ -0,0 +1,117 @@
+// Package toogoin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+package toogoin
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// SupportContextModel 客服工作台中展示的用户上下文（机器人、失败记录、API Key、钱包、订阅）
+type SupportContextModel struct {
+	UserId       int64                      `json:"userId" description:"用户ID"`
+	User         *SupportUserModel          `json:"user" description:"用户等级与套餐"`
+	Wallet       *SupportWalletModel        `json:"wallet" description:"钱包/算力余额"`
+	Subscription *SupportSubscriptionModel  `json:"subscription" description:"当前订阅，无订阅为空"`
+	Robots       []*SupportRobotModel       `json:"robots" description:"机器人（运行中优先）"`
+	ApiConfigs   []*SupportApiConfigModel   `json:"apiConfigs" description:"API Key 验证状态"`
+	Failures     []*SupportFailureModel     `json:"failures" description:"最近的执行失败记录"`
+	FailureStats []*SupportFailureStatModel `json:"failureStats" description:"失败分类统计"`
+}
+
+// SupportUserModel 用户等级与套餐
+type SupportUserModel struct {
+	VipLevel         int         `json:"vipLevel" description:"VIP等级"`
+	CurrentPlanId    int64       `json:"currentPlanId" description:"当前套餐ID"`
+	PlanExpireTime   *gtime.Time `json:"planExpireTime" description:"套餐到期时间"`
+	PlanGraceExpire  *gtime.Time `json:"planGraceExpire" description:"宽限期结束时间"`
+	RobotLimit       int         `json:"robotLimit" description:"机器人上限"`
+	ActiveRobotCount int         `json:"activeRobotCount" description:"运行中机器人数"`
+	Status           int         `json:"status" description:"状态"`
+}
+
+// SupportWalletModel 钱包/算力余额
+type SupportWalletModel struct {
+	Balance          float64 `json:"balance" description:"余额(USDT)"`
+	FrozenBalance    float64 `json:"frozenBalance" description:"冻结余额"`
+	Power            float64 `json:"power" description:"算力"`
+	FrozenPower      float64 `json:"frozenPower" description:"冻结算力"`
+	GiftPower        float64 `json:"giftPower" description:"赠送算力"`
+	Commission       float64 `json:"commission" description:"佣金余额"`
+	FrozenCommission float64 `json:"frozenCommission" description:"冻结佣金"`
+}
+
+// SupportSubscriptionModel 当前订阅
+type SupportSubscriptionModel struct {
+	Id              int64       `json:"id" description:"订阅ID"`
+	PlanId          int64       `json:"planId" description:"套餐ID"`
+	PlanCode        string      `json:"planCode" description:"套餐编码"`
+	PeriodType      string      `json:"periodType" description:"周期类型"`
+	Status          int         `json:"status" description:"状态"`
+	StartTime       *gtime.Time `json:"startTime" description:"开始时间"`
+	ExpireTime      *gtime.Time `json:"expireTime" description:"到期时间"`
+	GraceExpireTime *gtime.Time `json:"graceExpireTime" description:"宽限期结束时间"`
+	AutoRenew       int         `json:"autoRenew" description:"自动续费"`
+}
+
+// SupportRobotModel 机器人概况
+type SupportRobotModel struct {
+	Id          int64                     `json:"id" description:"机器人ID"`
+	RobotName   string                    `json:"robotName" description:"机器人名称"`
+	Exchange    string                    `json:"exchange" description:"交易所"`
+	Symbol      string                    `json:"symbol" description:"交易对"`
+	Status      int                       `json:"status" description:"状态"`
+	ApiConfigId int64                     `json:"apiConfigId" description:"API配置ID"`
+	StartTime   *gtime.Time               `json:"startTime" description:"启动时间"`
+	TotalProfit float64                   `json:"totalProfit" description:"累计盈亏"`
+	Engine      *SupportEngineStatusModel `json:"engine" description:"引擎状态，引擎不在本节点时为空"`
+}
+
+// SupportEngineStatusModel 引擎状态摘要（RobotEngineStatus 去掉价格窗口等图表数据）
+type SupportEngineStatusModel struct {
+	Running        bool    `json:"running" description:"是否运行"`
+	Connected      bool    `json:"connected" description:"行情是否连接"`
+	LastPrice      float64 `json:"lastPrice" description:"最新价"`
+	TotalBalance   float64 `json:"totalBalance" description:"账户总权益"`
+	AvailBalance   float64 `json:"availBalance" description:"可用余额"`
+	MarketState    string  `json:"marketState" description:"市场状态"`
+	RiskPreference string  `json:"riskPreference" description:"风险偏好"`
+	HasPosition    bool    `json:"hasPosition" description:"是否持仓"`
+	PositionSide   string  `json:"positionSide" description:"持仓方向"`
+	PositionAmt    float64 `json:"positionAmt" description:"持仓数量"`
+	UnrealizedPnl  float64 `json:"unrealizedPnl" description:"未实现盈亏"`
+	SignalReason   string  `json:"signalReason" description:"当前信号说明"`
+}
+
+// SupportApiConfigModel API Key 验证状态（不含密钥）
+type SupportApiConfigModel struct {
+	Id             int64       `json:"id" description:"API配置ID"`
+	ApiName        string      `json:"apiName" description:"名称"`
+	Platform       string      `json:"platform" description:"平台"`
+	Status         int         `json:"status" description:"状态：1=正常,2=禁用"`
+	VerifyStatus   int         `json:"verifyStatus" description:"验证状态：0=未验证,1=成功,2=失败"`
+	VerifyMessage  string      `json:"verifyMessage" description:"验证消息"`
+	LastVerifyTime *gtime.Time `json:"lastVerifyTime" description:"最后验证时间"`
+	PermStatus     int         `json:"permStatus" description:"权限检测状态：0=未检测,1=正常,2=风险提示,3=不可用"`
+	PermMessage    string      `json:"permMessage" description:"权限检测说明"`
+	KeyExpireAt    *gtime.Time `json:"keyExpireAt" description:"Key到期时间"`
+}
+
+// SupportFailureModel 执行失败记录
+type SupportFailureModel struct {
+	Id        int64       `json:"id" description:"日志ID"`
+	RobotId   int64       `json:"robotId" description:"机器人ID"`
+	OrderId   int64       `json:"orderId" description:"订单ID"`
+	EventType string      `json:"eventType" description:"事件类型"`
+	Message   string      `json:"message" description:"消息"`
+	Category  string      `json:"category" description:"失败分类：system/config/position/balance/strategy/exchange"`
+	Reason    string      `json:"reason" description:"失败原因与解决建议"`
+	CreatedAt *gtime.Time `json:"createdAt" description:"时间"`
+}
+
+// SupportFailureStatModel 失败分类统计
+type SupportFailureStatModel struct {
+	Category string `json:"category" description:"失败分类"`
+	Count    int    `json:"count" description:"次数"`
+}
@@ -0,0 +1,106 @@
+// Package toogoin
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+package toogoin
+
+import (
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/model/input/form"
+)
+
+// TenantListInp 白标租户列表输入
+type TenantListInp struct {
+	form.PageReq
+	Name   string `json:"name" description:"租户名称"`
+	Domain string `json:"domain" description:"绑定域名"`
+	Status int    `json:"status" description:"状态"`
+}
+
+// TenantModel 白标租户信息（不含收款密钥）
+type TenantModel struct {
+	Id                 int64       `json:"id" description:"主键ID"`
+	MemberId           int64       `json:"memberId" description:"租户账号ID(即租户ID)"`
+	Name               string      `json:"name" description:"租户名称"`
+	Domain             string      `json:"domain" description:"绑定域名"`
+	SiteName           string      `json:"siteName" description:"站点名称"`
+	Logo               string      `json:"logo" description:"Logo"`
+	Favicon            string      `json:"favicon" description:"站点图标"`
+	PrimaryColor       string      `json:"primaryColor" description:"主题色"`
+	SupportEmail       string      `json:"supportEmail" description:"客服邮箱"`
+	NowpaymentsSandbox int         `json:"nowpaymentsSandbox" description:"NOWPayments沙箱模式"`
+	HasNowpayments     bool        `json:"hasNowpayments" description:"是否已配置NOWPayments收款"`
+	Remark             string      `json:"remark" description:"备注"`
+	Status             int         `json:"status" description:"状态"`
+	CreatedAt          *gtime.Time `json:"createdAt" description:"创建时间"`
+	UpdatedAt          *gtime.Time `json:"updatedAt" description:"更新时间"`
+}
+
+// TenantListModel 白标租户列表返回（含跨租户统计）
+type TenantListModel struct {
+	*TenantModel
+	Username          string  `json:"username" description:"租户账号"`
+	UserCount         int     `json:"userCount" description:"用户数"`
+	RunningRobotCount int     `json:"runningRobotCount" description:"运行中机器人数"`
+	ActiveSubCount    int     `json:"activeSubCount" description:"有效订阅数"`
+	PlanCount         int     `json:"planCount" description:"套餐数"`
+	DepositAmount     float64 `json:"depositAmount" description:"累计充值(USDT)"`
+}
+
+// TenantViewInp 白标租户详情输入
+type TenantViewInp struct {
+	Id int64 `json:"id" v:"required#租户ID不能为空" description:"主键ID"`
+}
+
+// TenantEditInp 编辑白标租户输入
+type TenantEditInp struct {
+	Id       int64  `json:"id" description:"主键ID，0为新增"`
+	MemberId int64  `json:"memberId" v:"required#租户账号不能为空" description:"租户账号ID(部门类型为租户的后台账号)"`
+	Name     string `json:"name" v:"required#租户名称不能为空" description:"租户名称"`
+	Domain   string `json:"domain" description:"绑定域名"`
+	Remark   string `json:"remark" description:"备注"`
+	Status   int    `json:"status" d:"1" description:"状态"`
+	TenantBrandingEditInp
+}
+
+// TenantStatusInp 更新白标租户状态输入
+type TenantStatusInp struct {
+	Id     int64 `json:"id" v:"required#租户ID不能为空" description:"主键ID"`
+	Status int   `json:"status" v:"required|in:1,2#状态不能为空|状态不正确" description:"状态"`
+}
+
+// TenantBrandingModel 站点品牌配置（公开）
+type TenantBrandingModel struct {
+	TenantId     int64  `json:"tenantId" description:"租户ID，0为平台"`
+	SiteName     string `json:"siteName" description:"站点名称"`
+	Logo         string `json:"logo" description:"Logo"`
+	Favicon      string `json:"favicon" description:"站点图标"`
+	PrimaryColor string `json:"primaryColor" description:"主题色"`
+	SupportEmail string `json:"supportEmail" description:"客服邮箱"`
+}
+
+// TenantBrandingEditInp 编辑品牌配置与收款凭证输入
+// 收款密钥留空表示不修改
+type TenantBrandingEditInp struct {
+	SiteName             string `json:"siteName" description:"站点名称"`
+	Logo                 string `json:"logo" description:"Logo"`
+	Favicon              string `json:"favicon" description:"站点图标"`
+	PrimaryColor         string `json:"primaryColor" description:"主题色"`
+	SupportEmail         string `json:"supportEmail" v:"email#客服邮箱格式不正确" description:"客服邮箱"`
+	NowpaymentsApiKey    string `json:"nowpaymentsApiKey" description:"NOWPayments API Key，留空不修改"`
+	NowpaymentsIpnSecret string `json:"nowpaymentsIpnSecret" description:"NOWPayments IPN密钥，留空不修改"`
+	NowpaymentsSandbox   int    `json:"nowpaymentsSandbox" description:"NOWPayments沙箱模式"`
+}
+
+// TenantCopyCatalogInp 复制平台套餐/官方策略模板到租户输入
+type TenantCopyCatalogInp struct {
+	TenantId       int64 `json:"tenantId" v:"required#租户ID不能为空" description:"租户ID"`
+	Plans          bool  `json:"plans" description:"复制套餐"`
+	StrategyGroups bool  `json:"strategyGroups" description:"复制官方策略模板"`
+}
+
+// TenantCopyCatalogModel 复制结果
+type TenantCopyCatalogModel struct {
+	PlanCount  int `json:"planCount" description:"复制的套餐数"`
+	GroupCount int `json:"groupCount" description:"复制的策略模板数"`
+}
@@ -32,6 +32,7 @@ type UserListInp struct {
 	IsAgent   int      `json:"isAgent" d:"-1" description:"是否代理商: -1=全部, 0=否, 1=是"`
 	Status    int      `json:"status" description:"状态"`
 	CreatedAt []string `json:"createdAt" description:"创建时间"`
+	TenantId  *int64   `json:"tenantId" description:"租户ID（超管跨租户查看）"`
 }
 
 // UserListModel 用户列表返回
@@ -65,6 +66,21 @@ type RegisterWithInviteInp struct {
 	MemberId   int64  `json:"memberId" v:"required" description:"新用户ID"`
 }
 
+// ChangeInviterInp 管理员调整邀请人输入
+type ChangeInviterInp struct {
+	MemberId  int64 `json:"memberId" v:"required|min:1#请选择用户|用户ID无效" description:"用户ID"`
+	InviterId int64 `json:"inviterId" v:"min:0#邀请人ID无效" description:"新邀请人ID，0表示解除邀请关系"`
+}
+
+// ChangeInviterModel 管理员调整邀请人返回
+type ChangeInviterModel struct {
+	MemberId      int64 `json:"memberId" description:"用户ID"`
+	OldInviterId  int64 `json:"oldInviterId" description:"原邀请人ID"`
+	NewInviterId  int64 `json:"newInviterId" description:"新邀请人ID"`
+	MovedCount    int   `json:"movedCount" description:"随迁人数（含该用户）"`
+	AffectedCount int   `json:"affectedCount" description:"团队统计随之调整的上级人数"`
+}
+
 // TeamListInp 团队列表输入
 type TeamListInp struct {
 	form.PageReq
@@ -158,3 +174,31 @@ type CheckVipUpgradeModel struct {
 		RequireTeam    float64 `json:"requireTeam" description:"需要团队消耗"`
 	} `json:"progress" description:"升级进度"`
 }
+
+// VipEvaluateInp VIP等级评估输入
+type VipEvaluateInp struct {
+	MemberId int64  `json:"memberId" description:"用户ID，0表示评估全部用户"`
+	Source   string `json:"-" description:"触发来源: cron/check/admin"`
+}
+
+// VipEvaluateModel VIP等级评估结果
+type VipEvaluateModel struct {
+	Evaluated  int `json:"evaluated" description:"评估用户数"`
+	Upgraded   int `json:"upgraded" description:"升级人数"`
+	Downgraded int `json:"downgraded" description:"降级人数"`
+	Pending    int `json:"pending" description:"处于降级宽限期的人数"`
+}
+
+// VipLevelLogListInp VIP等级变更记录输入
+type VipLevelLogListInp struct {
+	form.PageReq
+	UserId     int64    `json:"userId" description:"用户ID"`
+	ChangeType string   `json:"changeType" description:"变更类型: upgrade/downgrade"`
+	CreatedAt  []string `json:"createdAt" description:"创建时间"`
+}
+
+// VipLevelLogListModel VIP等级变更记录返回
+type VipLevelLogListModel struct {
+	*entity.ToogoVipLevelLog
+	Username string `json:"username" description:"用户名"`
+}
@@ -313,3 +313,48 @@ type RunSessionTotalSummary struct {
 	TotalNetPnl      float64 `json:"totalNetPnl" description:"总净盈亏(USDT)"`
 	TotalTrades      int     `json:"totalTrades" description:"总成交笔数"`
 }
+
+// PerformanceFeeInp 业绩费（高水位）结算输入
+type PerformanceFeeInp struct {
+	UserId  int64 `json:"userId" description:"用户ID，不传则结算全部用户"`
+	RobotId int64 `json:"robotId" description:"机器人ID，不传则结算用户全部机器人"`
+}
+
+// PerformanceFeeItem 单个用户+机器人的业绩费结算结果
+type PerformanceFeeItem struct {
+	UserId          int64   `json:"userId" description:"用户ID"`
+	RobotId         int64   `json:"robotId" description:"机器人ID"`
+	RobotName       string  `json:"robotName" description:"机器人名称"`
+	PeriodStart     int64   `json:"periodStart" description:"结算周期开始(毫秒,不含)"`
+	PeriodEnd       int64   `json:"periodEnd" description:"结算周期结束(毫秒,含)"`
+	TradeCount      int     `json:"tradeCount" description:"本期成交笔数"`
+	NetPnl          float64 `json:"netPnl" description:"本期净已实现盈亏(扣手续费)"`
+	CumPnl          float64 `json:"cumPnl" description:"结算后累计净盈亏"`
+	HwmBefore       float64 `json:"hwmBefore" description:"结算前高水位"`
+	HwmAfter        float64 `json:"hwmAfter" description:"结算后高水位"`
+	Chargeable      float64 `json:"chargeable" description:"超出高水位的计费盈利"`
+	ConsumeRate     float64 `json:"consumeRate" description:"消耗比例(%)"`
+	VipLevel        int     `json:"vipLevel" description:"用户VIP等级"`
+	DiscountRate    float64 `json:"discountRate" description:"折扣比例(%)"`
+	OriginalPower   float64 `json:"originalPower" description:"原始消耗算力(未折扣)"`
+	ConsumePower    float64 `json:"consumePower" description:"消耗算力"`
+	FromGiftPower   float64 `json:"fromGiftPower" description:"从积分账户扣除"`
+	FromPower       float64 `json:"fromPower" description:"从算力账户扣除"`
+	PowerAfter      float64 `json:"powerAfter" description:"扣除后算力余额(负数为欠费)"`
+	AgentCommission float64 `json:"agentCommission" description:"代理算力佣金合计(USDT)"`
+	Status          string  `json:"status" description:"结果：charged=已扣费,deferred=低于最小扣费顺延,below_hwm=未超过高水位,no_trade=无成交"`
+	ConsumeId       int64   `json:"consumeId" description:"算力消耗记录ID"`
+}
+
+// PerformanceFeeReport 业绩费结算报告
+type PerformanceFeeReport struct {
+	DryRun               bool                  `json:"dryRun" description:"是否试算（未扣费）"`
+	PeriodEnd            int64                 `json:"periodEnd" description:"本次结算截止(毫秒)"`
+	ConsumeRate          float64               `json:"consumeRate" description:"消耗比例(%)"`
+	Items                []*PerformanceFeeItem `json:"items" description:"结算明细"`
+	ChargedCount         int                   `json:"chargedCount" description:"扣费笔数"`
+	TotalChargeable      float64               `json:"totalChargeable" description:"计费盈利合计"`
+	TotalConsumePower    float64               `json:"totalConsumePower" description:"消耗算力合计"`
+	TotalArrears         float64               `json:"totalArrears" description:"结算后欠费算力合计"`
+	TotalAgentCommission float64               `json:"totalAgentCommission" description:"代理算力佣金合计"`
+}
@@ -34,6 +34,13 @@ type TradingApiConfigListModel struct {
 	LastVerifyTime *gtime.Time `json:"lastVerifyTime" dc:"最后验证时间"`
 	VerifyStatus   int         `json:"verifyStatus" dc:"验证状态"`
 	VerifyMessage  string      `json:"verifyMessage" dc:"验证消息"`
+	PermStatus     int         `json:"permStatus" dc:"权限检测状态：0=未检测,1=正常,2=风险提示,3=不可用"`
+	PermFutures    int         `json:"permFutures" dc:"合约交易权限：0=未开启,1=已开启,2=无法确认"`
+	PermWithdraw   int         `json:"permWithdraw" dc:"提现权限：0=未开启,1=已开启,2=无法确认"`
+	IpRestricted   int         `json:"ipRestricted" dc:"是否绑定IP白名单"`
+	KeyExpireAt    *gtime.Time `json:"keyExpireAt" dc:"API Key过期时间"`
+	PermMessage    string      `json:"permMessage" dc:"权限检测结果说明"`
+	PermCheckedAt  *gtime.Time `json:"permCheckedAt" dc:"最后权限检测时间"`
 	Remark         string      `json:"remark" dc:"备注"`
 	CreatedAt      *gtime.Time `json:"createdAt" dc:"创建时间"`
 	UpdatedAt      *gtime.Time `json:"updatedAt" dc:"更新时间"`
@@ -42,7 +49,7 @@ type TradingApiConfigListModel struct {
 // TradingApiConfigCreateInp 创建输入
 type TradingApiConfigCreateInp struct {
 	ApiName    string `json:"apiName" v:"required|length:1,100" dc:"API名称"`
-	Platform   string `json:"platform" v:"required|in:bitget,binance,okx,gate" dc:"平台"`
+	Platform   string `json:"platform" v:"required" dc:"平台（已注册的交易所插件）"`
 	BaseUrl    string `json:"baseUrl" dc:"API地址（可选，自动填充）"`
 	ApiKey     string `json:"apiKey" v:"required" dc:"API Key"`
 	SecretKey  string `json:"secretKey" v:"required" dc:"Secret Key"`
@@ -55,7 +62,7 @@ type TradingApiConfigCreateInp struct {
 type TradingApiConfigUpdateInp struct {
 	Id         int64  `json:"id" v:"required" dc:"ID"`
 	ApiName    string `json:"apiName" v:"required|length:1,100" dc:"API名称"`
-	Platform   string `json:"platform" v:"required|in:bitget,binance,okx,gate" dc:"平台"`
+	Platform   string `json:"platform" v:"required" dc:"平台（已注册的交易所插件）"`
 	BaseUrl    string `json:"baseUrl" dc:"API地址（可选，自动填充）"`
 	ApiKey     string `json:"apiKey" dc:"API Key（不修改则不传）"`
 	SecretKey  string `json:"secretKey" dc:"Secret Key（不修改则不传）"`
@@ -91,6 +98,19 @@ type TradingApiConfigTestModel struct {
 	Message string `json:"message" dc:"消息"`
 	Balance string `json:"balance" dc:"账户余额"`
 	Latency int    `json:"latency" dc:"延迟(ms)"`
+
+	Permission *TradingApiConfigPermissionModel `json:"permission" dc:"API Key权限检测结果"`
+}
+
+// TradingApiConfigPermissionModel API Key权限检测结果
+type TradingApiConfigPermissionModel struct {
+	Status       int         `json:"status" dc:"检测状态：1=正常,2=风险提示,3=不可用"`
+	Futures      int         `json:"futures" dc:"合约交易权限：0=未开启,1=已开启,2=无法确认"`
+	Withdraw     int         `json:"withdraw" dc:"提现权限：0=未开启,1=已开启,2=无法确认"`
+	IpRestricted bool        `json:"ipRestricted" dc:"是否绑定IP白名单"`
+	IpWhitelist  []string    `json:"ipWhitelist" dc:"IP白名单"`
+	ExpireAt     *gtime.Time `json:"expireAt" dc:"过期时间"`
+	Issues       []string    `json:"issues" dc:"问题说明"`
 }
 
 // TradingApiConfigSetDefaultInp 设为默认输入
@@ -60,3 +60,51 @@ type TradingProxyConfigTestModel struct {
 type TradingProxyConfigToggleInp struct {
 	Enabled int `json:"enabled" v:"required|in:0,1" dc:"是否启用"`
 }
+
+// TradingProxyPoolListInp 代理池列表输入
+type TradingProxyPoolListInp struct {
+}
+
+// TradingProxyPoolModel 代理池成员输出
+type TradingProxyPoolModel struct {
+	Id           int64            `json:"id" dc:"ID"`
+	UserId       int64            `json:"userId" dc:"用户ID：0=全局代理"`
+	Name         string           `json:"name" dc:"代理名称"`
+	Enabled      int              `json:"enabled" dc:"是否启用"`
+	ProxyType    string           `json:"proxyType" dc:"代理类型"`
+	ProxyAddress string           `json:"proxyAddress" dc:"代理地址"`
+	AuthEnabled  int              `json:"authEnabled" dc:"是否需要认证"`
+	Username     string           `json:"username" dc:"用户名"`
+	Weight       int              `json:"weight" dc:"选择权重"`
+	IsDefault    bool             `json:"isDefault" dc:"是否为默认代理"`
+	LastTestTime *gtime.Time      `json:"lastTestTime" dc:"最后检查时间"`
+	TestStatus   int              `json:"testStatus" dc:"检查状态：0=未检查,1=可用,2=不可用"`
+	TestMessage  string           `json:"testMessage" dc:"检查消息"`
+	Latency      int              `json:"latency" dc:"平均延迟(ms)"`
+	VenueLatency map[string]int64 `json:"venueLatency" dc:"各交易所延迟(ms)，-1=不可达"`
+	Healthy      bool             `json:"healthy" dc:"运行时是否可用"`
+	Fails        int              `json:"fails" dc:"连续连接失败次数"`
+	LastError    string           `json:"lastError" dc:"最近一次连接失败原因"`
+	Assigned     int              `json:"assigned" dc:"粘性分配到该代理的API Key/行情连接数"`
+	CreatedAt    *gtime.Time      `json:"createdAt" dc:"创建时间"`
+	UpdatedAt    *gtime.Time      `json:"updatedAt" dc:"更新时间"`
+}
+
+// TradingProxyPoolEditInp 编辑代理池成员输入
+type TradingProxyPoolEditInp struct {
+	Id           int64  `json:"id" dc:"ID，为空时新增"`
+	UserId       int64  `json:"userId" v:"min:0" dc:"用户ID：0=全局代理"`
+	Name         string `json:"name" v:"required|max-length:64" dc:"代理名称"`
+	Enabled      int    `json:"enabled" v:"in:0,1" dc:"是否启用：0=禁用,1=启用"`
+	ProxyType    string `json:"proxyType" v:"required|in:socks5,http" dc:"代理类型"`
+	ProxyAddress string `json:"proxyAddress" v:"required" dc:"代理地址（host:port）"`
+	AuthEnabled  int    `json:"authEnabled" v:"in:0,1" dc:"是否需要认证"`
+	Username     string `json:"username" dc:"用户名"`
+	Password     string `json:"password" dc:"密码，为空时不修改"`
+	Weight       int    `json:"weight" v:"between:0,100" dc:"选择权重，0按1处理"`
+}
+
+// TradingProxyPoolDeleteInp 删除代理池成员输入
+type TradingProxyPoolDeleteInp struct {
+	Id int64 `json:"id" v:"required" dc:"ID"`
+}
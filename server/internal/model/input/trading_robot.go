@@ -48,6 +48,9 @@ type TradingRobotListModel struct {
 	StartTime               *gtime.Time `json:"startTime" dc:"启动时间"`
 	CreatedAt               *gtime.Time `json:"createdAt" dc:"创建时间"`
 	StrategyGroupId         int64       `json:"strategyGroupId" dc:"策略组ID"`
+	BasketId                int64       `json:"basketId" dc:"组合机器人ID：0=独立机器人"`
+	AllocationWeight        float64     `json:"allocationWeight" dc:"组合内分配权重"`
+	RobotMode               string      `json:"robotMode" dc:"运行模式：signal=方向信号,grid=网格"`
 	CurrentStrategySnapshot *gjson.Json `json:"currentStrategy" dc:"当前策略快照"`
 	ScheduleStart           *gtime.Time `json:"scheduleStart" dc:"定时启动时间"`
 	ScheduleStop            *gtime.Time `json:"scheduleStop" dc:"定时停止时间"`
@@ -100,31 +103,47 @@ type TradingRobotCreateInp struct {
 	// ⑨市场状态与风险偏好映射（创建时静态配置，运行时根据映射关系匹配策略模板）
 	MarketRiskMapping map[string]string `json:"marketRiskMapping" dc:"市场状态与风险偏好映射"`
 
+	// ⑩运行模式（grid 模式不绑定策略组，按网格配置挂单）
+	RobotMode  string                `json:"robotMode" v:"in:signal,grid" dc:"运行模式：signal=方向信号(默认),grid=网格"`
+	GridConfig *TradingGridConfigInp `json:"gridConfig" dc:"网格配置（robotMode=grid 时必填）"`
+
 	Remark string `json:"remark" dc:"备注"`
 }
 
+// TradingGridConfigInp 网格配置
+type TradingGridConfigInp struct {
+	LowerPrice      float64 `json:"lowerPrice" dc:"下限价格"`
+	UpperPrice      float64 `json:"upperPrice" dc:"上限价格"`
+	GridCount       int     `json:"gridCount" dc:"网格数"`
+	Spacing         string  `json:"spacing" v:"in:arithmetic,geometric" dc:"间距：arithmetic=等差,geometric=等比"`
+	Bias            string  `json:"bias" v:"in:long,short,neutral" dc:"方向：long=做多,short=做空,neutral=中性"`
+	QuantityPerGrid float64 `json:"quantityPerGrid" dc:"每格下单数量（基础币）"`
+	Leverage        int     `json:"leverage" v:"between:1,125" dc:"杠杆倍数"`
+}
+
 // TradingRobotUpdateInp 更新机器人输入
 type TradingRobotUpdateInp struct {
-	Id                      int64   `json:"id" v:"required" dc:"ID"`
-	RobotName               string  `json:"robotName" v:"length:1,100" dc:"机器人名称"` // 改为可选，仅在完整更新时需要
-	MaxProfitTarget         float64 `json:"maxProfitTarget" v:"min:0" dc:"最大盈利目标"`
-	MaxLossAmount           float64 `json:"maxLossAmount" v:"min:0" dc:"最大亏损额"`
-	MaxRuntime              int     `json:"maxRuntime" v:"min:0" dc:"最大运行时长"`
-	RiskPreference          string  `json:"riskPreference" v:"in:conservative,balanced,aggressive" dc:"风险偏好"` // 改为可选
-	AutoRiskPreference      int     `json:"autoRiskPreference" v:"in:0,1" dc:"自动风险偏好"`
-	MarketState             string  `json:"marketState" v:"in:trend,volatile,high-volatility,low-volatility" dc:"市场状态"` // 改为可选
-	AutoMarketState         int     `json:"autoMarketState" v:"in:0,1" dc:"自动市场状态"`
-	Leverage                int     `json:"leverage" v:"between:1,125" dc:"杠杆倍数"`       // 改为可选
-	MarginPercent           float64 `json:"marginPercent" v:"between:1,100" dc:"保证金比例"` // 改为可选
-	UseMonitorSignal        int     `json:"useMonitorSignal" v:"in:0,1" dc:"采用方向预警信号"`
-	StopLossPercent         float64 `json:"stopLossPercent" v:"between:0.1,100" dc:"止损百分比"`           // 改为可选
-	ProfitRetreatPercent    float64 `json:"profitRetreatPercent" v:"between:0.1,100" dc:"止盈回撤百分比"`    // 改为可选
-	AutoStartRetreatPercent float64 `json:"autoStartRetreatPercent" v:"between:0.1,100" dc:"启动回撤百分比"` // 改为可选
-	AutoTradeEnabled        *int    `json:"autoTradeEnabled" dc:"全自动下单：0=否,1=是（可选，nil表示不更新）"`
-	AutoCloseEnabled        *int    `json:"autoCloseEnabled" dc:"全自动平仓：0=否,1=是（可选，nil表示不更新）"`
-	ProfitLockEnabled       *int    `json:"profitLockEnabled" dc:"锁定盈利开关：0=关闭,1=开启（可选，nil表示不更新；止盈启动后禁止自动开新仓）"`
-	DualSidePosition        *int    `json:"dualSidePosition" dc:"双向开单：0=单向,1=双向（可选，nil表示不更新）"`
-	Remark                  string  `json:"remark" dc:"备注"`
+	Id                      int64                 `json:"id" v:"required" dc:"ID"`
+	RobotName               string                `json:"robotName" v:"length:1,100" dc:"机器人名称"` // 改为可选，仅在完整更新时需要
+	MaxProfitTarget         float64               `json:"maxProfitTarget" v:"min:0" dc:"最大盈利目标"`
+	MaxLossAmount           float64               `json:"maxLossAmount" v:"min:0" dc:"最大亏损额"`
+	MaxRuntime              int                   `json:"maxRuntime" v:"min:0" dc:"最大运行时长"`
+	RiskPreference          string                `json:"riskPreference" v:"in:conservative,balanced,aggressive" dc:"风险偏好"` // 改为可选
+	AutoRiskPreference      int                   `json:"autoRiskPreference" v:"in:0,1" dc:"自动风险偏好"`
+	MarketState             string                `json:"marketState" v:"in:trend,volatile,high-volatility,low-volatility" dc:"市场状态"` // 改为可选
+	AutoMarketState         int                   `json:"autoMarketState" v:"in:0,1" dc:"自动市场状态"`
+	Leverage                int                   `json:"leverage" v:"between:1,125" dc:"杠杆倍数"`       // 改为可选
+	MarginPercent           float64               `json:"marginPercent" v:"between:1,100" dc:"保证金比例"` // 改为可选
+	UseMonitorSignal        int                   `json:"useMonitorSignal" v:"in:0,1" dc:"采用方向预警信号"`
+	StopLossPercent         float64               `json:"stopLossPercent" v:"between:0.1,100" dc:"止损百分比"`           // 改为可选
+	ProfitRetreatPercent    float64               `json:"profitRetreatPercent" v:"between:0.1,100" dc:"止盈回撤百分比"`    // 改为可选
+	AutoStartRetreatPercent float64               `json:"autoStartRetreatPercent" v:"between:0.1,100" dc:"启动回撤百分比"` // 改为可选
+	AutoTradeEnabled        *int                  `json:"autoTradeEnabled" dc:"全自动下单：0=否,1=是（可选，nil表示不更新）"`
+	AutoCloseEnabled        *int                  `json:"autoCloseEnabled" dc:"全自动平仓：0=否,1=是（可选，nil表示不更新）"`
+	ProfitLockEnabled       *int                  `json:"profitLockEnabled" dc:"锁定盈利开关：0=关闭,1=开启（可选，nil表示不更新；止盈启动后禁止自动开新仓）"`
+	DualSidePosition        *int                  `json:"dualSidePosition" dc:"双向开单：0=单向,1=双向（可选，nil表示不更新）"`
+	GridConfig              *TradingGridConfigInp `json:"gridConfig" dc:"网格配置（仅网格模式，需先暂停）"`
+	Remark                  string                `json:"remark" dc:"备注"`
 }
 
 // TradingRobotDeleteInp 删除机器人输入
@@ -160,6 +179,22 @@ type TradingRobotStopInp struct {
 	Id int64 `json:"id" v:"required" dc:"ID"`
 }
 
+// TradingRobotStopAllInp 停止全部机器人输入
+type TradingRobotStopAllInp struct{}
+
+// TradingRobotStopAllModel 停止全部机器人结果
+type TradingRobotStopAllModel struct {
+	Stopped []int64                      `json:"stopped" dc:"已停止的机器人ID"`
+	Failed  []*TradingRobotStopAllFailed `json:"failed" dc:"停止失败的机器人"`
+}
+
+// TradingRobotStopAllFailed 停止失败的机器人
+type TradingRobotStopAllFailed struct {
+	Id        int64  `json:"id" dc:"机器人ID"`
+	RobotName string `json:"robotName" dc:"机器人名称"`
+	Reason    string `json:"reason" dc:"失败原因"`
+}
+
 // TradingRobotStatusInp 更新状态输入
 type TradingRobotStatusInp struct {
 	Id     int64 `json:"id" v:"required" dc:"ID"`
@@ -211,3 +246,19 @@ type TradingRobotRecommendStrategyModel struct {
 	AutoStartRetreatPercent float64 `json:"autoStartRetreatPercent" dc:"启动回撤百分比(%)"`
 	GroupId                 int64   `json:"groupId" dc:"策略组ID"`
 }
+
+// TradingRobotGridStatusInp 网格运行状态输入
+type TradingRobotGridStatusInp struct {
+	Id         int64 `json:"id" v:"required" dc:"机器人ID"`
+	TradeLimit int   `json:"tradeLimit" v:"min:0|max:200" dc:"返回最近网格利润记录条数（默认50）"`
+}
+
+// TradingRobotGridStatusModel 网格运行状态
+type TradingRobotGridStatusModel struct {
+	Config         *TradingGridConfigInp      `json:"config" dc:"网格配置"`
+	Cells          []*entity.TradingGridCell  `json:"cells" dc:"各格子状态"`
+	RecentTrades   []*entity.TradingGridTrade `json:"recentTrades" dc:"最近网格利润记录"`
+	HoldingCells   int                        `json:"holdingCells" dc:"持仓格子数"`
+	RoundTrips     int                        `json:"roundTrips" dc:"累计套利次数"`
+	RealizedProfit float64                    `json:"realizedProfit" dc:"累计已实现网格利润(USDT)"`
+}
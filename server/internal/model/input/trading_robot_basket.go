@@ -0,0 +1,85 @@
+// Package input
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+
+package input
+
+import (
+	"hotgo/internal/model/entity"
+
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// TradingRobotBasketLegInp 组合机器人单个交易对（腿）配置
+type TradingRobotBasketLegInp struct {
+	Symbol          string  `json:"symbol" v:"required" dc:"交易对"`
+	StrategyGroupId int64   `json:"strategyGroupId" v:"required" dc:"策略组ID（平台/交易对须与本腿一致）"`
+	Weight          float64 `json:"weight" v:"min:0" dc:"分配权重（为0时均分，提交后按总和归一化）"`
+}
+
+// TradingRobotBasketCreateInp 创建组合机器人输入
+type TradingRobotBasketCreateInp struct {
+	BasketName        string                      `json:"basketName" v:"required|length:1,100" dc:"组合名称"`
+	ApiConfigId       int64                       `json:"apiConfigId" v:"required" dc:"API接口ID"`
+	MarginBudget      float64                     `json:"marginBudget" v:"min:0" dc:"组合保证金预算(USDT)：0=不限制（按账户可用余额）"`
+	MaxPositions      int                         `json:"maxPositions" v:"min:0" dc:"最大同时持仓交易对数：0=不限制"`
+	Legs              []*TradingRobotBasketLegInp `json:"legs" v:"required" dc:"交易对列表（至少2个）"`
+	MaxProfitTarget   float64                     `json:"maxProfitTarget" v:"min:0" dc:"单腿最大盈利目标(USDT)"`
+	MaxLossAmount     float64                     `json:"maxLossAmount" v:"min:0" dc:"单腿最大亏损额(USDT)"`
+	MaxRuntime        int                         `json:"maxRuntime" v:"min:0" dc:"最大运行时长(秒)"`
+	AutoMarketState   int                         `json:"autoMarketState" v:"in:0,1" dc:"自动市场状态"`
+	UseMonitorSignal  int                         `json:"useMonitorSignal" v:"in:0,1" dc:"采用方向预警信号"`
+	AutoTradeEnabled  *int                        `json:"autoTradeEnabled" dc:"全自动下单：0=否,1=是"`
+	AutoCloseEnabled  *int                        `json:"autoCloseEnabled" dc:"全自动平仓：0=否,1=是"`
+	DualSidePosition  *int                        `json:"dualSidePosition" dc:"双向开单：0=单向,1=双向"`
+	MarketRiskMapping map[string]string           `json:"marketRiskMapping" dc:"市场状态→风险偏好映射"`
+}
+
+// TradingRobotBasketListInp 组合机器人列表输入
+type TradingRobotBasketListInp struct {
+	Page     int `json:"page" v:"required|min:1" dc:"页码"`
+	PageSize int `json:"pageSize" v:"required|min:1|max:100" dc:"每页数量"`
+	Status   int `json:"status" dc:"状态筛选"`
+}
+
+// TradingRobotBasketListModel 组合机器人列表输出
+type TradingRobotBasketListModel struct {
+	entity.TradingRobotBasket
+	LegCount    int     `json:"legCount" dc:"交易对数量"`
+	TotalProfit float64 `json:"totalProfit" dc:"组合总盈亏"`
+}
+
+// TradingRobotBasketIdInp 组合机器人ID输入（查看/启动/暂停/停用/删除）
+type TradingRobotBasketIdInp struct {
+	Id int64 `json:"id" v:"required" dc:"组合ID"`
+}
+
+// TradingRobotBasketViewModel 组合机器人详情（含分腿与合计盈亏）
+type TradingRobotBasketViewModel struct {
+	Basket *entity.TradingRobotBasket    `json:"basket" dc:"组合信息"`
+	Legs   []*TradingRobotBasketLegModel `json:"legs" dc:"各交易对"`
+	Total  *TradingRobotBasketPnlModel   `json:"total" dc:"组合合计"`
+}
+
+// TradingRobotBasketLegModel 单腿运行信息
+type TradingRobotBasketLegModel struct {
+	RobotId          int64       `json:"robotId" dc:"腿机器人ID"`
+	Symbol           string      `json:"symbol" dc:"交易对"`
+	StrategyGroupId  int64       `json:"strategyGroupId" dc:"策略组ID"`
+	AllocationWeight float64     `json:"allocationWeight" dc:"分配权重"`
+	Status           int         `json:"status" dc:"状态"`
+	StartTime        *gtime.Time `json:"startTime" dc:"启动时间"`
+	TradingRobotBasketPnlModel
+}
+
+// TradingRobotBasketPnlModel 运行区间盈亏汇总（来自 run_session）
+type TradingRobotBasketPnlModel struct {
+	Sessions       int     `json:"sessions" dc:"运行区间数"`
+	RuntimeSeconds int64   `json:"runtimeSeconds" dc:"累计运行时长(秒)"`
+	TradeCount     int     `json:"tradeCount" dc:"成交笔数"`
+	TotalPnl       float64 `json:"totalPnl" dc:"已实现盈亏"`
+	TotalFee       float64 `json:"totalFee" dc:"手续费"`
+	NetPnl         float64 `json:"netPnl" dc:"净盈亏"`
+}
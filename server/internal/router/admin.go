@@ -30,7 +30,8 @@ func Admin(ctx context.Context, group *ghttp.RouterGroup) {
 
 	group.Group(simple.RouterPrefix(ctx, consts.AppAdmin), func(group *ghttp.RouterGroup) {
 		group.Bind(
-			common.Site, // 基础
+			common.Site,           // 基础
+			admin.PaymentCallback, // 支付回调（按租户收款凭证验签）
 		)
 		group.Middleware(service.Middleware().AdminAuth)
 		group.Bind(
@@ -52,6 +53,8 @@ func Admin(ctx context.Context, group *ghttp.RouterGroup) {
 			sys.ServeLog,         // 服务日志
 			sys.SmsLog,           // 短信记录
 			sys.ServeLicense,     // 服务许可证
+			sys.QueueDeadLetter,  // 消息队列死信
+			sys.TwoFactor,        // 双因素认证
 			adminCtrl.Member,     // 用户
 			adminCtrl.Monitor,    // 监控
 			adminCtrl.Role,       // 路由
@@ -68,6 +71,7 @@ func Admin(ctx context.Context, group *ghttp.RouterGroup) {
 			trading.ApiConfig,        // Trading API配置
 			trading.ProxyConfig,      // Trading 代理配置
 			trading.Robot,            // Trading 机器人
+			trading.RobotBasket,      // Trading 组合机器人
 			trading.Order,            // Trading 订单
 			trading.Monitor,          // Trading 监控
 			trading.StrategyGroup,    // Trading 策略模板
@@ -75,6 +79,7 @@ func Admin(ctx context.Context, group *ghttp.RouterGroup) {
 			trading.VolatilityConfig, // Trading 波动率配置
 			trading.PublicMarket,     // Trading 公共行情（无需API Key）
 			trading.AlertController,  // Trading 预警日志
+			trading.AiLearning,       // Trading 信号权重学习
 			// Payment模块
 			payment.Deposit,  // USDT充值
 			payment.Withdraw, // USDT提现
@@ -82,6 +87,7 @@ func Admin(ctx context.Context, group *ghttp.RouterGroup) {
 			// Toogo模块
 			admin.Toogo,       // Toogo量化交易
 			admin.ToogoConfig, // Toogo系统配置
+			admin.ToogoTenant, // Toogo白标租户
 		)
 
 		group.Middleware(service.Middleware().Develop)
@@ -36,13 +36,19 @@ func WebSocket(ctx context.Context, group *ghttp.RouterGroup) {
 	// 启动websocket监听
 	websocket.Start()
 
+	// 集群部署时，转发其他节点引擎生成的机器人实时快照
+	toogo.StartClusterRelay(ctx)
+
 	// 注册消息路由
 	websocket.RegisterMsg(websocket.EventHandlers{
-		"ping":                  common.Site.Ping,      // 心跳
-		"join":                  common.Site.Join,      // 加入组
-		"quit":                  common.Site.Quit,      // 退出组
-		"admin/monitor/trends":  admin.Monitor.Trends,  // 后台监控，动态数据
-		"admin/monitor/runInfo": admin.Monitor.RunInfo, // 后台监控，运行信息
+		"ping":                  common.Site.Ping,          // 心跳
+		"join":                  common.Site.Join,          // 加入组
+		"quit":                  common.Site.Quit,          // 退出组
+		"resume":                common.Site.Resume,        // 补发频道消息
+		"support/typing":        common.SupportChat.Typing, // 客服聊天，正在输入
+		"support/read":          common.SupportChat.Read,   // 客服聊天，已读回执
+		"admin/monitor/trends":  admin.Monitor.Trends,      // 后台监控，动态数据
+		"admin/monitor/runInfo": admin.Monitor.RunInfo,     // 后台监控，运行信息
 		// Toogo - 机器人实时推送（批量实时分析）
 		"toogo/robot/realtime/subscribe":   toogo.RobotRealtime.Subscribe,
 		"toogo/robot/realtime/unsubscribe": toogo.RobotRealtime.Unsubscribe,
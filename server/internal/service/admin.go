@@ -14,6 +14,7 @@ import (
 	"hotgo/internal/model/input/adminin"
 	"hotgo/internal/model/input/form"
 	"hotgo/internal/model/input/payin"
+	"hotgo/internal/model/input/sysin"
 	"hotgo/utility/tree"
 
 	"github.com/gogf/gf/v2/database/gdb"
@@ -253,6 +254,10 @@ type (
 		AccountLogin(ctx context.Context, in *adminin.AccountLoginInp) (res *adminin.LoginModel, err error)
 		// MobileLogin 手机号登录
 		MobileLogin(ctx context.Context, in *adminin.MobileLoginInp) (res *adminin.LoginModel, err error)
+		// TwoFactorLoginSetup 登录时绑定验证器，获取待绑定的密钥
+		TwoFactorLoginSetup(ctx context.Context, in *adminin.TwoFactorLoginSetupInp) (res *sysin.TwoFactorSetupModel, err error)
+		// TwoFactorLogin 提交动态口令完成登录
+		TwoFactorLogin(ctx context.Context, in *adminin.TwoFactorLoginInp) (res *adminin.LoginModel, err error)
 		// BindUserContext 绑定用户上下文
 		BindUserContext(ctx context.Context, claims *model.Identity) (err error)
 	}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/adminin"
+	"hotgo/internal/model/input/apiin"
+
+	"github.com/gogf/gf/v2/net/ghttp"
 )
 
 type ISupportChat interface {
@@ -20,10 +23,22 @@ type ISupportChat interface {
 	Close(ctx context.Context, in *adminin.SupportCloseInp) (err error)
 	// SendAgentMessage 客服发送消息
 	SendAgentMessage(ctx context.Context, in *adminin.SupportSendInp) (msg *entity.SupportMessage, err error)
+	// SendAgentAttachment 客服发送附件
+	SendAgentAttachment(ctx context.Context, in *adminin.SupportSendAttachmentInp) (msg *entity.SupportMessage, err error)
 	// MessageList 消息列表（客服端）
 	MessageList(ctx context.Context, in *adminin.SupportMessageListInp) (list []*entity.SupportMessage, totalCount int, err error)
 	// Transfer 转接会话（客服端）
 	Transfer(ctx context.Context, in *adminin.SupportTransferInp) (session *entity.SupportSession, err error)
+	// Escalate 升级为工单（关联机器人/订单）
+	Escalate(ctx context.Context, in *adminin.SupportEscalateInp) (session *entity.SupportSession, err error)
+	// ExecutionLogList 工单关联的执行日志
+	ExecutionLogList(ctx context.Context, in *adminin.SupportExecutionLogListInp) (list []*entity.TradingExecutionLog, totalCount int, err error)
+	// SessionContext 会话用户上下文（机器人/失败记录/API Key/钱包/订阅）与可用操作
+	SessionContext(ctx context.Context, in *adminin.SupportContextInp) (res *adminin.SupportContextModel, err error)
+	// RestartRobot 重建会话用户的机器人引擎（审计）
+	RestartRobot(ctx context.Context, in *adminin.SupportRobotActionInp) (err error)
+	// SyncRobotOrders 重新同步会话用户的机器人订单（审计）
+	SyncRobotOrders(ctx context.Context, in *adminin.SupportRobotActionInp) (err error)
 
 	// CannedList 常用语列表
 	CannedList(ctx context.Context, in *adminin.SupportCannedListInp) (list []*adminin.SupportCannedListModel, totalCount int, err error)
@@ -38,6 +53,17 @@ type ISupportChat interface {
 	SendUserMessage(ctx context.Context, sessionId int64, content string) (msg *entity.SupportMessage, err error)
 	// UserMessageList 用户端：消息列表
 	UserMessageList(ctx context.Context, sessionId int64, page, perPage int) (list []*entity.SupportMessage, totalCount int, err error)
+	// SendUserAttachment 用户端：发送附件
+	SendUserAttachment(ctx context.Context, sessionId int64, file *ghttp.UploadFile) (msg *entity.SupportMessage, err error)
+	// RateSession 用户端：会话评价
+	RateSession(ctx context.Context, in *apiin.SupportRateInp) (session *entity.SupportSession, err error)
+
+	// MarkRead 标记已读并回执给对方（用户/客服通用）
+	MarkRead(ctx context.Context, memberId, sessionId int64) (err error)
+	// Typing 推送正在输入状态（用户/客服通用）
+	Typing(ctx context.Context, memberId, sessionId int64, typing bool) (err error)
+	// NotifyOffline 离线用户未读回复邮件送达
+	NotifyOffline(ctx context.Context) (notified int, err error)
 }
 
 var localSupportChat ISupportChat
@@ -335,6 +335,19 @@ type (
 		// Select 省市区选项
 		Select(ctx context.Context, in *sysin.ProvincesSelectInp) (res *sysin.ProvincesSelectModel, err error)
 	}
+	ISysQueueDeadLetter interface {
+		// List 获取死信列表
+		List(ctx context.Context, in *sysin.QueueDeadLetterListInp) (list []*sysin.QueueDeadLetterListModel, totalCount int, err error)
+		// View 获取死信信息
+		View(ctx context.Context, in *sysin.QueueDeadLetterViewInp) (res *sysin.QueueDeadLetterViewModel, err error)
+		// Replay 将死信重新投递到原主题
+		// 重放后消息按正常流程消费，再次失败会生成新的死信记录。
+		Replay(ctx context.Context, in *sysin.QueueDeadLetterReplayInp) (res *sysin.QueueDeadLetterReplayModel, err error)
+		// Ignore 忽略死信
+		Ignore(ctx context.Context, in *sysin.QueueDeadLetterIgnoreInp) (err error)
+		// Delete 删除死信
+		Delete(ctx context.Context, in *sysin.QueueDeadLetterDeleteInp) (err error)
+	}
 	ISysServeLicense interface {
 		// Model 服务许可证ORM模型
 		Model(ctx context.Context, option ...*handler.Option) *gdb.Model
@@ -425,31 +438,66 @@ type (
 		// MaskSensitiveString 遮蔽敏感字符串
 		MaskSensitiveString(data string, showFirst, showLast int) string
 	}
+	ISysTwoFactor interface {
+		// Status 获取双因素认证状态
+		Status(ctx context.Context, memberId int64) (res *sysin.TwoFactorStatusModel, err error)
+		// IsEnabled 是否已启用双因素认证
+		IsEnabled(ctx context.Context, memberId int64) (bool, error)
+		// RequiredForRole 角色是否被登录配置强制启用双因素认证
+		RequiredForRole(ctx context.Context, roleId int64) (bool, error)
+		// RequiredForMember 用户所属角色是否被强制启用双因素认证
+		RequiredForMember(ctx context.Context, memberId int64) (bool, error)
+		// Setup 生成待绑定的验证器密钥，未启用前重复调用会替换密钥
+		Setup(ctx context.Context, memberId int64) (res *sysin.TwoFactorSetupModel, err error)
+		// Enable 校验验证器口令并启用，返回恢复码
+		Enable(ctx context.Context, memberId int64, code string) (res *sysin.TwoFactorRecoveryModel, err error)
+		// Disable 校验口令或恢复码后停用，被角色强制的用户不能停用
+		Disable(ctx context.Context, memberId int64, code string) (err error)
+		// RegenerateRecovery 校验验证器口令后重新生成恢复码，旧恢复码全部失效
+		RegenerateRecovery(ctx context.Context, memberId int64, code string) (res *sysin.TwoFactorRecoveryModel, err error)
+		// Verify 校验验证器口令或恢复码，恢复码使用后立即作废
+		// 登录、StepUp、敏感操作等所有场景共用同一用户级失败计数，锁定时长内累计错误达到上限后拒绝校验
+		Verify(ctx context.Context, memberId int64, code string) (err error)
+		// StepUp 校验口令，通过后一段时间内执行敏感操作无需再次输入
+		StepUp(ctx context.Context, memberId int64, code string) (res *sysin.TwoFactorVerifyModel, err error)
+		// RequireVerified 敏感操作二次验证，并写入审计记录
+		// 未启用双因素认证的用户直接放行（角色被强制的除外）；已启用的用户需在请求中携带口令，或处于 StepUp 免验证窗口内
+		RequireVerified(ctx context.Context, memberId int64, operation, details string) (err error)
+		// IssueLoginTicket 签发登录二次验证票据
+		IssueLoginTicket(ctx context.Context, memberId int64) (ticket string, err error)
+		// LoginTicketMember 获取登录票据对应的用户，票据无效或已过期时返回错误
+		LoginTicketMember(ctx context.Context, ticket string) (memberId int64, err error)
+		// VerifyLoginTicket 校验登录票据及口令，已启用的用户校验口令或恢复码，未启用的用户校验待绑定密钥并启用
+		// 口令错误次数超过限制后票据作废，需重新登录
+		VerifyLoginTicket(ctx context.Context, ticket, code string) (memberId int64, recovery *sysin.TwoFactorRecoveryModel, err error)
+	}
 )
 
 var (
-	localSysAddons         ISysAddons
-	localSysAddonsConfig   ISysAddonsConfig
-	localSysAttachment     ISysAttachment
-	localSysBlacklist      ISysBlacklist
-	localSysConfig         ISysConfig
-	localSysCron           ISysCron
-	localSysCronGroup      ISysCronGroup
-	localSysCurdDemo       ISysCurdDemo
-	localSysDictData       ISysDictData
-	localSysDictType       ISysDictType
-	localSysEmsLog         ISysEmsLog
-	localSysGenCodes       ISysGenCodes
-	localSysLog            ISysLog
-	localSysLoginLog       ISysLoginLog
-	localSysNormalTreeDemo ISysNormalTreeDemo
-	localSysOptionTreeDemo ISysOptionTreeDemo
-	localSysProvinces      ISysProvinces
-	localSysServeLicense   ISysServeLicense
-	localSysServeLog       ISysServeLog
-	localSysSmsLog         ISysSmsLog
-	localSysTestCategory   ISysTestCategory
-	localSysSecurity       ISysSecurity
+	localSysAddons          ISysAddons
+	localSysAddonsConfig    ISysAddonsConfig
+	localSysAttachment      ISysAttachment
+	localSysBlacklist       ISysBlacklist
+	localSysConfig          ISysConfig
+	localSysCron            ISysCron
+	localSysCronGroup       ISysCronGroup
+	localSysCurdDemo        ISysCurdDemo
+	localSysDictData        ISysDictData
+	localSysDictType        ISysDictType
+	localSysEmsLog          ISysEmsLog
+	localSysGenCodes        ISysGenCodes
+	localSysLog             ISysLog
+	localSysLoginLog        ISysLoginLog
+	localSysNormalTreeDemo  ISysNormalTreeDemo
+	localSysOptionTreeDemo  ISysOptionTreeDemo
+	localSysProvinces       ISysProvinces
+	localSysQueueDeadLetter ISysQueueDeadLetter
+	localSysServeLicense    ISysServeLicense
+	localSysServeLog        ISysServeLog
+	localSysSmsLog          ISysSmsLog
+	localSysTestCategory    ISysTestCategory
+	localSysSecurity        ISysSecurity
+	localSysTwoFactor       ISysTwoFactor
 )
 
 func SysAddons() ISysAddons {
@@ -639,6 +687,17 @@ func RegisterSysProvinces(i ISysProvinces) {
 	localSysProvinces = i
 }
 
+func SysQueueDeadLetter() ISysQueueDeadLetter {
+	if localSysQueueDeadLetter == nil {
+		panic("implement not found for interface ISysQueueDeadLetter, forgot register?")
+	}
+	return localSysQueueDeadLetter
+}
+
+func RegisterSysQueueDeadLetter(i ISysQueueDeadLetter) {
+	localSysQueueDeadLetter = i
+}
+
 func SysServeLicense() ISysServeLicense {
 	if localSysServeLicense == nil {
 		panic("implement not found for interface ISysServeLicense, forgot register?")
@@ -693,3 +752,14 @@ func SysSecurity() ISysSecurity {
 func RegisterSysSecurity(i ISysSecurity) {
 	localSysSecurity = i
 }
+
+func SysTwoFactor() ISysTwoFactor {
+	if localSysTwoFactor == nil {
+		panic("implement not found for interface ISysTwoFactor, forgot register?")
+	}
+	return localSysTwoFactor
+}
+
+func RegisterSysTwoFactor(i ISysTwoFactor) {
+	localSysTwoFactor = i
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"hotgo/internal/library/exchange"
+	"hotgo/internal/library/payment"
 	"hotgo/internal/model/entity"
 	"hotgo/internal/model/input/toogoin"
 )
@@ -28,8 +29,12 @@ type IToogoWallet interface {
 	AdminRecharge(ctx context.Context, userId int64, accountType string, amount float64, remark string) (beforeAmount, afterAmount float64, err error)
 	// UserWalletList 用户钱包列表（管理员用）
 	UserWalletList(ctx context.Context, username, mobile string, page, perPage int) ([]*toogoin.UserWalletListModel, int, error)
-	// ConsumePower 消耗算力
+	// ConsumePower 消耗算力（已禁用，由业绩费结算替代）
 	ConsumePower(ctx context.Context, userId int64, robotId int64, orderId int64, orderSn string, profitAmount float64) error
+	// PerformanceFeeReport 业绩费（高水位）试算报告，不扣费
+	PerformanceFeeReport(ctx context.Context, in *toogoin.PerformanceFeeInp) (*toogoin.PerformanceFeeReport, error)
+	// SettlePerformanceFee 业绩费（高水位）结算
+	SettlePerformanceFee(ctx context.Context, in *toogoin.PerformanceFeeInp) (*toogoin.PerformanceFeeReport, error)
 	// OrderHistoryList 历史交易订单列表
 	OrderHistoryList(ctx context.Context, in *toogoin.OrderHistoryListInp) ([]*toogoin.OrderHistoryModel, int, error)
 	// TradeHistoryList 成交流水列表（交易所成交明细）
@@ -71,8 +76,8 @@ type IToogoUser interface {
 	RefreshInviteCode(ctx context.Context, in *toogoin.InviteCodeRefreshInp) (*toogoin.InviteCodeRefreshModel, error)
 	// RegisterWithInvite 使用邀请码注册关联
 	RegisterWithInvite(ctx context.Context, in *toogoin.RegisterWithInviteInp) error
-	// UpdateTeamCount 更新团队人数
-	UpdateTeamCount(ctx context.Context, memberId int64, delta int) error
+	// ChangeInviter 管理员调整邀请人
+	ChangeInviter(ctx context.Context, in *toogoin.ChangeInviterInp) (*toogoin.ChangeInviterModel, error)
 	// TeamList 团队列表
 	TeamList(ctx context.Context, in *toogoin.TeamListInp) ([]*toogoin.TeamListModel, int, error)
 	// TeamStat 团队统计
@@ -83,6 +88,10 @@ type IToogoUser interface {
 	VipLevelEdit(ctx context.Context, in *toogoin.VipLevelEditInp) error
 	// CheckVipUpgrade 检查VIP升级
 	CheckVipUpgrade(ctx context.Context, in *toogoin.CheckVipUpgradeInp) (*toogoin.CheckVipUpgradeModel, error)
+	// EvaluateVipLevels 按实时指标评估VIP等级（升级立即生效，降级经过宽限期）
+	EvaluateVipLevels(ctx context.Context, in *toogoin.VipEvaluateInp) (*toogoin.VipEvaluateModel, error)
+	// VipLevelLogList VIP等级变更记录
+	VipLevelLogList(ctx context.Context, in *toogoin.VipLevelLogListInp) ([]*toogoin.VipLevelLogListModel, int, error)
 }
 
 var localToogoUser IToogoUser
@@ -112,7 +121,31 @@ type IToogoSubscription interface {
 	SubscriptionList(ctx context.Context, in *toogoin.SubscriptionListInp) ([]*toogoin.SubscriptionListModel, int, error)
 	// MySubscription 我的订阅
 	MySubscription(ctx context.Context, in *toogoin.MySubscriptionInp) (*toogoin.MySubscriptionModel, error)
-	// CheckExpired 检查并处理过期订阅
+	// PlanChangeQuote 升级/降级套餐试算
+	PlanChangeQuote(ctx context.Context, in *toogoin.ChangePlanInp) (*toogoin.ChangePlanModel, error)
+	// ChangePlan 升级/降级套餐（剩余价值折抵）
+	ChangePlan(ctx context.Context, in *toogoin.ChangePlanInp) (*toogoin.ChangePlanModel, error)
+	// SetAutoRenew 开启/关闭自动续费
+	SetAutoRenew(ctx context.Context, in *toogoin.SubscriptionAutoRenewInp) error
+	// RefundSubscription 订阅退款（管理员）：退回余额与积分、取消订阅并冲正代理佣金
+	RefundSubscription(ctx context.Context, in *toogoin.SubscriptionRefundInp) (*toogoin.SubscriptionRefundModel, error)
+	// RefundOrder 支付订单退款联动：按退款比例冲正代理佣金，取消或缩短订单对应的订阅（在退款事务内调用）
+	RefundOrder(ctx context.Context, in *toogoin.OrderRefundInp) (*toogoin.OrderRefundModel, error)
+	// AfterRefundOrder 支付订单退款事务提交后：通知用户，已无订阅时停止其运行中的机器人
+	AfterRefundOrder(ctx context.Context, res *toogoin.OrderRefundModel)
+	// SubscriptionLogList 订阅变更记录列表
+	SubscriptionLogList(ctx context.Context, in *toogoin.SubscriptionLogListInp) ([]*toogoin.SubscriptionLogListModel, int, error)
+	// CouponList 优惠券列表
+	CouponList(ctx context.Context, in *toogoin.CouponListInp) ([]*toogoin.CouponListModel, int, error)
+	// CouponEdit 编辑优惠券/推广码
+	CouponEdit(ctx context.Context, in *toogoin.CouponEditInp) error
+	// CouponDelete 删除优惠券
+	CouponDelete(ctx context.Context, in *toogoin.CouponDeleteInp) error
+	// CouponUsageList 优惠券使用记录
+	CouponUsageList(ctx context.Context, in *toogoin.CouponUsageListInp) ([]*toogoin.CouponUsageListModel, int, error)
+	// CouponPreview 优惠码试算
+	CouponPreview(ctx context.Context, in *toogoin.CouponPreviewInp) (*toogoin.CouponPreviewModel, error)
+	// CheckExpired 检查并处理过期订阅（到期提醒、自动续费、宽限期）
 	CheckExpired(ctx context.Context) error
 }
 
@@ -137,8 +170,20 @@ type IToogoCommission interface {
 	CommissionStat(ctx context.Context, in *toogoin.CommissionStatInp) (*toogoin.CommissionStatModel, error)
 	// SettleSubscribeCommission 结算订阅佣金（级差制）
 	SettleSubscribeCommission(ctx context.Context, fromUserId int64, amount float64, subscriptionId int64, orderSn string) error
+	// SettlePowerCommission 结算算力消耗佣金（级差制），返回佣金合计
+	SettlePowerCommission(ctx context.Context, fromUserId int64, amount float64, consumeId int64, orderSn string) (float64, error)
 	// SettleInviteReward 发放邀请奖励
 	SettleInviteReward(ctx context.Context, inviterId int64, inviteeId int64) error
+	// ReleaseCommission 解冻结算期已满的佣金（定时任务调用），返回解冻笔数与金额
+	ReleaseCommission(ctx context.Context) (int, float64, error)
+	// ReverseOrderCommission 按退款比例冲正来源订单产生的佣金（订单退款时调用），返回冲正金额合计
+	ReverseOrderCommission(ctx context.Context, orderSn, reason string, ratio float64) (float64, error)
+	// AgentDashboard 代理看板：团队增长、分层业绩与佣金、贡献排行、邀请转化
+	AgentDashboard(ctx context.Context, in *toogoin.AgentDashboardInp) (*toogoin.AgentDashboardModel, error)
+	// AgentStatement 代理月度对账单（与佣金记录、账户流水核对）
+	AgentStatement(ctx context.Context, in *toogoin.AgentStatementInp) (*toogoin.AgentStatementModel, error)
+	// AgentStatementExport 下载代理月度对账单（CSV/PDF）
+	AgentStatementExport(ctx context.Context, in *toogoin.AgentStatementExportInp) error
 	// AgentLevelList 代理商等级列表（已废弃）
 	AgentLevelList(ctx context.Context, in *toogoin.AgentLevelListInp) ([]*toogoin.AgentLevelListModel, int, error)
 	// AgentLevelEdit 编辑代理商等级（已废弃）
@@ -264,6 +309,12 @@ type IToogoRobot interface {
 	SyncClosedOrders(ctx context.Context) error
 	// SyncOrderHistoryToDB 同步订单历史到数据库
 	SyncOrderHistoryToDB(ctx context.Context, robotId int64, robot *entity.TradingRobot, orders []*exchange.Order) error
+	// SupportContext 客服工作台的用户上下文（机器人、失败记录、API Key、钱包、订阅）
+	SupportContext(ctx context.Context, userId int64) (*toogoin.SupportContextModel, error)
+	// RestartRobotEngine 重建运行中机器人的引擎
+	RestartRobotEngine(ctx context.Context, robotId int64) error
+	// TriggerOrderSync 触发机器人订单/持仓重新同步
+	TriggerOrderSync(ctx context.Context, robotId int64) error
 }
 
 var localToogoRobot IToogoRobot
@@ -338,3 +389,67 @@ func ToogoVolatilityConfig() IToogoVolatilityConfig {
 func RegisterToogoVolatilityConfig(i IToogoVolatilityConfig) {
 	localToogoVolatilityConfig = i
 }
+
+// IToogoAiLearning Toogo信号权重自适应学习服务接口
+type IToogoAiLearning interface {
+	// Run 统计新平仓订单并重新计算学习权重（影子模式只生成报告）
+	Run(ctx context.Context) (*toogoin.AiLearningRunModel, error)
+	// LoadWeights 从学习表恢复已生效的学习权重（服务启动及定时刷新时调用）
+	LoadWeights(ctx context.Context) error
+	// Report 学习报告：当前权重 vs 学习权重
+	Report(ctx context.Context, in *toogoin.AiLearningReportInp) (*toogoin.AiLearningReportModel, error)
+}
+
+var localToogoAiLearning IToogoAiLearning
+
+func ToogoAiLearning() IToogoAiLearning {
+	if localToogoAiLearning == nil {
+		panic("implement not found for interface IToogoAiLearning, forgot register?")
+	}
+	return localToogoAiLearning
+}
+
+func RegisterToogoAiLearning(i IToogoAiLearning) {
+	localToogoAiLearning = i
+}
+
+// IToogoTenant Toogo白标租户服务接口
+type IToogoTenant interface {
+	// List 租户列表（超管跨租户视图）
+	List(ctx context.Context, in *toogoin.TenantListInp) ([]*toogoin.TenantListModel, int, error)
+	// View 租户详情
+	View(ctx context.Context, in *toogoin.TenantViewInp) (*toogoin.TenantModel, error)
+	// Edit 新增/编辑租户
+	Edit(ctx context.Context, in *toogoin.TenantEditInp) error
+	// Status 更新租户状态
+	Status(ctx context.Context, in *toogoin.TenantStatusInp) error
+	// Branding 当前租户的品牌配置
+	Branding(ctx context.Context) (*toogoin.TenantModel, error)
+	// BrandingEdit 修改当前租户的品牌配置与收款凭证
+	BrandingEdit(ctx context.Context, in *toogoin.TenantBrandingEditInp) error
+	// CopyCatalog 复制平台套餐/官方策略模板到租户
+	CopyCatalog(ctx context.Context, in *toogoin.TenantCopyCatalogInp) (*toogoin.TenantCopyCatalogModel, error)
+	// MemberTenantId 获取用户所属租户ID，0为平台
+	MemberTenantId(ctx context.Context, memberId int64) int64
+	// RequestTenant 按请求域名识别租户，未绑定返回nil
+	RequestTenant(ctx context.Context) (*entity.ToogoTenant, error)
+	// BindMember 绑定用户到租户
+	BindMember(ctx context.Context, memberId, tenantId int64) error
+	// SiteBranding 当前访问站点的品牌配置
+	SiteBranding(ctx context.Context) (*toogoin.TenantBrandingModel, error)
+	// NOWPayments 获取租户的NOWPayments收款网关，未配置返回nil
+	NOWPayments(ctx context.Context, tenantId int64) (*payment.NOWPayments, error)
+}
+
+var localToogoTenant IToogoTenant
+
+func ToogoTenant() IToogoTenant {
+	if localToogoTenant == nil {
+		panic("implement not found for interface IToogoTenant, forgot register?")
+	}
+	return localToogoTenant
+}
+
+func RegisterToogoTenant(i IToogoTenant) {
+	localToogoTenant = i
+}
@@ -0,0 +1,70 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"encoding/json"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"hotgo/internal/library/hgrds/pubsub"
+)
+
+// EventChannelResync 补发缓存不足时通知客户端全量刷新频道数据
+const EventChannelResync = "channel/resync"
+
+// SendToChannel 发送频道消息
+// 频道即客户端通过 join 加入的标签。与 SendToTag 不同，频道消息会分配集群内递增的序号并保留最近的消息，
+// 客户端断线重连后携带最后收到的序号重新加入频道，即可补发期间缺失的消息。
+func SendToChannel(channel string, response *WResponse) {
+	response.Channel = channel
+	if response.Timestamp == 0 {
+		response.Timestamp = gtime.Now().Timestamp()
+	}
+
+	if payload, err := json.Marshal(response); err != nil {
+		g.Log().Warningf(mctx, "websocket SendToChannel marshal err:%+v", err)
+	} else if response.Seq, err = pubsub.Append(mctx, channel, string(payload)); err != nil {
+		// 序号分配失败不影响实时推送，只是该消息无法补发
+		g.Log().Warningf(mctx, "websocket SendToChannel append %v err:%+v", channel, err)
+	}
+
+	sendToTagLocal(channel, response)
+	publishCluster(&clusterMessage{Kind: clusterKindChannel, Tag: channel, Response: response})
+}
+
+// Resume 按客户端最后收到的序号补发频道消息
+// 缺失的消息已超出保留窗口时推送 EventChannelResync，客户端应全量刷新该频道的数据后从返回的序号继续。
+func Resume(client *Client, channel string, lastSeq int64) {
+	ctx := client.Context()
+	list, complete, err := pubsub.Since(ctx, channel, lastSeq)
+	if err != nil {
+		g.Log().Warningf(ctx, "websocket Resume %v since:%v err:%+v", channel, lastSeq, err)
+	}
+
+	if !complete {
+		seq, _ := pubsub.LastSeq(ctx, channel)
+		client.SendMsg(&WResponse{
+			Event:     EventChannelResync,
+			Data:      g.Map{"channel": channel, "lastSeq": lastSeq},
+			Code:      gcode.CodeOK.Code(),
+			Timestamp: gtime.Now().Unix(),
+			Channel:   channel,
+			Seq:       seq,
+		})
+		return
+	}
+
+	for _, item := range list {
+		var res *WResponse
+		if err = json.Unmarshal([]byte(item.Payload), &res); err != nil || res == nil {
+			continue
+		}
+		res.Seq = item.Seq
+		client.SendMsg(res)
+	}
+}
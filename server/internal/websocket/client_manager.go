@@ -33,14 +33,15 @@ type ClientManager struct {
 
 func NewClientManager() (clientManager *ClientManager) {
 	clientManager = &ClientManager{
-		Clients:       make(map[*Client]bool),
-		Users:         make(map[string][]*Client),
-		Register:      make(chan *Client, 1000),
-		Unregister:    make(chan *Client, 1000),
-		Broadcast:     make(chan *WResponse, 1000),
-		TagBroadcast:  make(chan *TagWResponse, 1000),
-		UserBroadcast: make(chan *UserWResponse, 1000),
-		closeSignal:   make(chan struct{}, 1),
+		Clients:         make(map[*Client]bool),
+		Users:           make(map[string][]*Client),
+		Register:        make(chan *Client, 1000),
+		Unregister:      make(chan *Client, 1000),
+		Broadcast:       make(chan *WResponse, 1000),
+		ClientBroadcast: make(chan *ClientWResponse, 1000),
+		TagBroadcast:    make(chan *TagWResponse, 1000),
+		UserBroadcast:   make(chan *UserWResponse, 1000),
+		closeSignal:     make(chan struct{}, 1),
 	}
 	return
 }
@@ -183,6 +184,7 @@ func (manager *ClientManager) EventLogin(login *login) {
 	if manager.InClient(client) {
 		userKey := login.GetKey()
 		manager.AddUsers(userKey, login.Client)
+		markPresence(login.UserId)
 	}
 }
 
@@ -195,6 +197,9 @@ func (manager *ClientManager) EventUnregister(client *Client) {
 		// 不是当前连接的客户端
 		return
 	}
+	if client.User != nil && len(manager.GetUserClient(client.User.Id)) == 0 {
+		clearPresence(client.User.Id)
+	}
 
 	client.close()
 }
@@ -231,6 +236,7 @@ func (manager *ClientManager) ping() {
 	// 定时任务，清理超时连接
 	_, _ = gcron.Add(mctx, "*/30 * * * * *", func(ctx context.Context) {
 		manager.clearTimeoutConnections()
+		manager.refreshPresence()
 	})
 }
 
@@ -304,11 +310,35 @@ func (manager *ClientManager) start() {
 
 // SendToAll 发送全部客户端
 func SendToAll(response *WResponse) {
-	clientManager.Broadcast <- response
+	sendToAllLocal(response)
+	publishCluster(&clusterMessage{Kind: clusterKindAll, Response: response})
 }
 
 // SendToClientID  发送单个客户端
 func SendToClientID(id string, response *WResponse) {
+	sendToClientIDLocal(id, response)
+	publishCluster(&clusterMessage{Kind: clusterKindClient, ClientId: id, Response: response})
+}
+
+// SendToUser 发送单个用户
+func SendToUser(userID int64, response *WResponse) {
+	sendToUserLocal(userID, response)
+	publishCluster(&clusterMessage{Kind: clusterKindUser, UserId: userID, Response: response})
+}
+
+// SendToTag 发送某个标签
+func SendToTag(tag string, response *WResponse) {
+	sendToTagLocal(tag, response)
+	publishCluster(&clusterMessage{Kind: clusterKindTag, Tag: tag, Response: response})
+}
+
+// sendToAllLocal 发送本节点全部客户端
+func sendToAllLocal(response *WResponse) {
+	clientManager.Broadcast <- response
+}
+
+// sendToClientIDLocal 发送本节点单个客户端
+func sendToClientIDLocal(id string, response *WResponse) {
 	clientRes := &ClientWResponse{
 		ID:        id,
 		WResponse: response,
@@ -316,8 +346,8 @@ func SendToClientID(id string, response *WResponse) {
 	clientManager.ClientBroadcast <- clientRes
 }
 
-// SendToUser 发送单个用户
-func SendToUser(userID int64, response *WResponse) {
+// sendToUserLocal 发送本节点单个用户
+func sendToUserLocal(userID int64, response *WResponse) {
 	userRes := &UserWResponse{
 		UserID:    userID,
 		WResponse: response,
@@ -325,8 +355,8 @@ func SendToUser(userID int64, response *WResponse) {
 	clientManager.UserBroadcast <- userRes
 }
 
-// SendToTag 发送某个标签
-func SendToTag(tag string, response *WResponse) {
+// sendToTagLocal 发送本节点某个标签
+func sendToTagLocal(tag string, response *WResponse) {
 	tagRes := &TagWResponse{
 		Tag:       tag,
 		WResponse: response,
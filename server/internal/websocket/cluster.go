@@ -0,0 +1,79 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gogf/gf/v2/database/gredis"
+	"github.com/gogf/gf/v2/frame/g"
+	"hotgo/internal/consts"
+	"hotgo/internal/library/hgrds/pubsub"
+	"hotgo/utility/simple"
+)
+
+// 跨节点推送类型
+const (
+	clusterKindAll     = "all"
+	clusterKindClient  = "client"
+	clusterKindUser    = "user"
+	clusterKindTag     = "tag"
+	clusterKindChannel = "channel"
+)
+
+// clusterMessage 跨节点推送消息
+// 集群部署时，每次推送先投递给本节点的连接，再通过redis广播给其他节点，由其他节点投递给各自的连接。
+type clusterMessage struct {
+	Node     string     `json:"node"`               // 发出消息的节点
+	Kind     string     `json:"kind"`               // 推送类型
+	ClientId string     `json:"clientId,omitempty"` // 客户端ID
+	UserId   int64      `json:"userId,omitempty"`   // 用户ID
+	Tag      string     `json:"tag,omitempty"`      // 标签/频道
+	Response *WResponse `json:"response"`           // 推送内容
+}
+
+// publishCluster 广播给其他节点，未开启集群部署时不做任何处理
+func publishCluster(msg *clusterMessage) {
+	if !simple.IsCluster(mctx) {
+		return
+	}
+
+	msg.Node = pubsub.NodeId()
+	data, err := json.Marshal(msg)
+	if err != nil {
+		g.Log().Warningf(mctx, "websocket publishCluster marshal err:%+v", err)
+		return
+	}
+	if _, err = pubsub.Publish(mctx, consts.ClusterSyncWebsocket, string(data)); err != nil {
+		g.Log().Warningf(mctx, "websocket publishCluster kind:%v err:%+v", msg.Kind, err)
+	}
+}
+
+// ClusterSync 集群同步，投递其他节点发出的推送
+func ClusterSync(ctx context.Context, message *gredis.Message) {
+	var msg *clusterMessage
+	if err := json.Unmarshal([]byte(message.Payload), &msg); err != nil {
+		g.Log().Warningf(ctx, "websocket ClusterSync unmarshal err:%+v, payload:%v", err, message.Payload)
+		return
+	}
+	if msg == nil || msg.Response == nil || msg.Node == pubsub.NodeId() {
+		return
+	}
+
+	switch msg.Kind {
+	case clusterKindAll:
+		sendToAllLocal(msg.Response)
+	case clusterKindClient:
+		sendToClientIDLocal(msg.ClientId, msg.Response)
+	case clusterKindUser:
+		sendToUserLocal(msg.UserId, msg.Response)
+	case clusterKindTag, clusterKindChannel:
+		sendToTagLocal(msg.Tag, msg.Response)
+	default:
+		g.Log().Warningf(ctx, "websocket ClusterSync unknown kind:%v", msg.Kind)
+	}
+}
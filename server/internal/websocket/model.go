@@ -21,6 +21,8 @@ type WResponse struct {
 	Code      int         `json:"code"`               // 状态码
 	ErrorMsg  string      `json:"errorMsg,omitempty"` // 错误消息
 	Timestamp int64       `json:"timestamp"`          // 服务器时间
+	Channel   string      `json:"channel,omitempty"`  // 频道，仅频道消息
+	Seq       int64       `json:"seq,omitempty"`      // 频道内序号，断线重连时携带最后收到的序号补发
 }
 
 type TagWResponse struct {
@@ -0,0 +1,97 @@
+// Package websocket
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2023 HotGo CLI
+// @Author  Ms <133814250@qq.com>
+// @License  https://github.com/bufanyun/hotgo/blob/master/LICENSE
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/util/gconv"
+	"hotgo/internal/library/hgrds/pubsub"
+	"hotgo/utility/simple"
+)
+
+// 集群在线状态
+// 集群部署时每个节点在redis中为本节点有连接的用户登记在线：hash字段为节点ID，值为过期时间戳。
+// 随连接清理定时刷新，节点异常退出后登记自动过期。
+const (
+	presenceKey = "ws:presence:"
+	presenceTTL = 90 // 秒，需大于刷新间隔
+)
+
+// IsUserOnline 用户是否有在线连接，集群部署时包含其他节点上的连接
+func IsUserOnline(ctx context.Context, userId int64) bool {
+	if len(clientManager.GetUserClient(userId)) > 0 {
+		return true
+	}
+	if !simple.IsCluster(ctx) {
+		return false
+	}
+
+	v, err := g.Redis().HGetAll(ctx, presenceUserKey(userId))
+	if err != nil {
+		g.Log().Warningf(ctx, "websocket IsUserOnline userId:%v err:%+v", userId, err)
+		return false
+	}
+	now := time.Now().Unix()
+	for _, expire := range v.Map() {
+		if gconv.Int64(expire) > now {
+			return true
+		}
+	}
+	return false
+}
+
+// markPresence 登记本节点上的在线用户
+func markPresence(userIds ...int64) {
+	if !simple.IsCluster(mctx) {
+		return
+	}
+	expire := time.Now().Unix() + presenceTTL
+	for _, userId := range userIds {
+		if userId <= 0 {
+			continue
+		}
+		key := presenceUserKey(userId)
+		if _, err := g.Redis().HSet(mctx, key, map[string]any{pubsub.NodeId(): expire}); err != nil {
+			g.Log().Warningf(mctx, "websocket markPresence userId:%v err:%+v", userId, err)
+			continue
+		}
+		_, _ = g.Redis().Expire(mctx, key, presenceTTL)
+	}
+}
+
+// clearPresence 用户在本节点已无连接时撤销登记
+func clearPresence(userId int64) {
+	if userId <= 0 || !simple.IsCluster(mctx) {
+		return
+	}
+	if _, err := g.Redis().HDel(mctx, presenceUserKey(userId), pubsub.NodeId()); err != nil {
+		g.Log().Warningf(mctx, "websocket clearPresence userId:%v err:%+v", userId, err)
+	}
+}
+
+// refreshPresence 刷新本节点全部在线用户的登记
+func (manager *ClientManager) refreshPresence() {
+	seen := make(map[int64]struct{})
+	manager.ClientsRange(func(client *Client, _ bool) bool {
+		if client.User != nil && client.User.Id > 0 {
+			seen[client.User.Id] = struct{}{}
+		}
+		return true
+	})
+	userIds := make([]int64, 0, len(seen))
+	for userId := range seen {
+		userIds = append(userIds, userId)
+	}
+	markPresence(userIds...)
+}
+
+func presenceUserKey(userId int64) string {
+	return fmt.Sprintf("%s%d", presenceKey, userId)
+}
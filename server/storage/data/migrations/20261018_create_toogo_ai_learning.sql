@@ -0,0 +1,60 @@
+-- ============================================================
+-- 信号权重自适应学习
+-- 说明：
+-- - hg_toogo_ai_learning: 按 交易对/周期/市场状态/风险偏好 统计信号准确率与学习权重
+--   time_frame=1m..1h 行：该周期K线方向与订单实际有利方向是否一致
+--   time_frame=market/technical/volatility 行：风险评估该维度的胜负判断是否正确
+--   trend_weight 保存该信号来源学习后的权重（周期投票权重/风险维度权重）
+-- - hg_trading_order.signal_snapshot: 下单时的信号快照(JSON)
+-- - hg_trading_order.ai_learned: 是否已计入学习统计
+-- - 学习配置默认影子模式：只生成报告，不影响实盘
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `hg_toogo_ai_learning` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `symbol` varchar(20) NOT NULL COMMENT '交易对',
+  `time_frame` varchar(10) NOT NULL COMMENT '信号来源: 1m/5m/15m/30m/1h 或 market/technical/volatility',
+  `market_state` varchar(20) NOT NULL COMMENT '市场状态',
+  `risk_preference` varchar(20) NOT NULL COMMENT '风险偏好',
+  `price_weight` decimal(5,4) NOT NULL DEFAULT '0.3000' COMMENT '价格权重',
+  `volume_weight` decimal(5,4) NOT NULL DEFAULT '0.2000' COMMENT '成交量权重',
+  `trend_weight` decimal(5,4) NOT NULL DEFAULT '0.2500' COMMENT '趋势权重',
+  `volatility_weight` decimal(5,4) NOT NULL DEFAULT '0.2500' COMMENT '波动率权重',
+  `total_signals` int(11) NOT NULL DEFAULT '0' COMMENT '总信号数',
+  `correct_signals` int(11) NOT NULL DEFAULT '0' COMMENT '正确信号数',
+  `accuracy_rate` decimal(5,4) NOT NULL DEFAULT '0.0000' COMMENT '准确率',
+  `total_profit` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '总收益',
+  `avg_profit` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '平均收益',
+  `last_update` datetime DEFAULT NULL COMMENT '最后更新时间',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_symbol_frame_state_risk` (`symbol`, `time_frame`, `market_state`, `risk_preference`),
+  KEY `idx_symbol` (`symbol`),
+  KEY `idx_accuracy` (`accuracy_rate`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='AI学习记录表';
+
+ALTER TABLE `hg_trading_order`
+  ADD COLUMN IF NOT EXISTS `signal_snapshot` TEXT NULL COMMENT '下单时的信号快照(JSON)',
+  ADD COLUMN IF NOT EXISTS `ai_learned` TINYINT NOT NULL DEFAULT 0 COMMENT '是否已计入AI学习: 0=否,1=是';
+
+ALTER TABLE `hg_trading_order`
+  ADD INDEX `idx_ai_learned` (`ai_learned`, `status`);
+
+INSERT INTO `hg_toogo_config` (`group`, `key`, `value`, `type`, `name`, `description`, `sort`) VALUES
+('ai_learning', 'enabled', '0', 'boolean', '启用权重学习', '1=学习结果生效于实盘,0=关闭', 1),
+('ai_learning', 'shadow_mode', '1', 'boolean', '影子模式', '1=只生成报告不生效,0=正式生效', 2),
+('ai_learning', 'min_samples', '30', 'number', '最小样本数', '样本不足的信号来源不调整权重', 3),
+('ai_learning', 'max_step', '0.05', 'number', '单次最大调整幅度', '每轮学习单个权重最多变化量', 4),
+('ai_learning', 'weight_min', '0.05', 'number', '周期权重下限', '', 5),
+('ai_learning', 'weight_max', '0.50', 'number', '周期权重上限', '', 6),
+('ai_learning', 'risk_weight_min', '0.05', 'number', '风险维度权重下限', '', 7),
+('ai_learning', 'risk_weight_max', '0.40', 'number', '风险维度权重上限', '', 8)
+ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `description` = VALUES(`description`);
+
+INSERT INTO `hg_sys_cron` (`group_id`, `title`, `name`, `params`, `pattern`, `policy`, `count`, `sort`, `remark`, `status`, `created_at`, `updated_at`)
+VALUES (10, 'AI Learning', 'ToogoAiLearning', '', '@every 1h', 1, 0, 60, 'Signal weight learning', 1, NOW(), NOW())
+ON DUPLICATE KEY UPDATE `pattern` = VALUES(`pattern`), `remark` = VALUES(`remark`), `updated_at` = NOW();
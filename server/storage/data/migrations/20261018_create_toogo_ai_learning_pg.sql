@@ -0,0 +1,48 @@
+-- 信号权重自适应学习（说明见 MySQL 版本）
+-- PostgreSQL version
+CREATE TABLE IF NOT EXISTS hg_toogo_ai_learning (
+  id BIGSERIAL PRIMARY KEY,
+  symbol VARCHAR(20) NOT NULL,
+  time_frame VARCHAR(10) NOT NULL,
+  market_state VARCHAR(20) NOT NULL,
+  risk_preference VARCHAR(20) NOT NULL,
+  price_weight NUMERIC(5,4) NOT NULL DEFAULT 0.3000,
+  volume_weight NUMERIC(5,4) NOT NULL DEFAULT 0.2000,
+  trend_weight NUMERIC(5,4) NOT NULL DEFAULT 0.2500,
+  volatility_weight NUMERIC(5,4) NOT NULL DEFAULT 0.2500,
+  total_signals INT NOT NULL DEFAULT 0,
+  correct_signals INT NOT NULL DEFAULT 0,
+  accuracy_rate NUMERIC(5,4) NOT NULL DEFAULT 0,
+  total_profit NUMERIC(20,8) NOT NULL DEFAULT 0,
+  avg_profit NUMERIC(20,8) NOT NULL DEFAULT 0,
+  last_update TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT uk_symbol_frame_state_risk UNIQUE (symbol, time_frame, market_state, risk_preference)
+);
+CREATE INDEX IF NOT EXISTS idx_toogo_ai_learning_symbol ON hg_toogo_ai_learning (symbol);
+
+COMMENT ON TABLE hg_toogo_ai_learning IS 'AI学习记录表';
+COMMENT ON COLUMN hg_toogo_ai_learning.time_frame IS '信号来源: 1m/5m/15m/30m/1h 或 market/technical/volatility';
+
+ALTER TABLE hg_trading_order
+  ADD COLUMN IF NOT EXISTS signal_snapshot TEXT NULL,
+  ADD COLUMN IF NOT EXISTS ai_learned SMALLINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_trading_order_ai_learned ON hg_trading_order (ai_learned, status);
+
+COMMENT ON COLUMN hg_trading_order.signal_snapshot IS '下单时的信号快照(JSON)';
+COMMENT ON COLUMN hg_trading_order.ai_learned IS '是否已计入AI学习: 0=否,1=是';
+
+INSERT INTO hg_toogo_config ("group", "key", value, type, name, description, sort) VALUES
+('ai_learning', 'enabled', '0', 'boolean', '启用权重学习', '1=学习结果生效于实盘,0=关闭', 1),
+('ai_learning', 'shadow_mode', '1', 'boolean', '影子模式', '1=只生成报告不生效,0=正式生效', 2),
+('ai_learning', 'min_samples', '30', 'number', '最小样本数', '样本不足的信号来源不调整权重', 3),
+('ai_learning', 'max_step', '0.05', 'number', '单次最大调整幅度', '每轮学习单个权重最多变化量', 4),
+('ai_learning', 'weight_min', '0.05', 'number', '周期权重下限', '', 5),
+('ai_learning', 'weight_max', '0.50', 'number', '周期权重上限', '', 6),
+('ai_learning', 'risk_weight_min', '0.05', 'number', '风险维度权重下限', '', 7),
+('ai_learning', 'risk_weight_max', '0.40', 'number', '风险维度权重上限', '', 8)
+ON CONFLICT ("group", "key") DO NOTHING;
+
+INSERT INTO hg_sys_cron (group_id, title, name, params, pattern, policy, count, sort, remark, status, created_at, updated_at)
+VALUES (10, 'AI Learning', 'ToogoAiLearning', '', '@every 1h', 1, 0, 60, 'Signal weight learning', 1, NOW(), NOW());
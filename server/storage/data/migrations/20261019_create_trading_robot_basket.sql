@@ -0,0 +1,43 @@
+-- ============================================================
+-- 组合机器人（一个API账户交易一篮子交易对）
+-- 说明：
+-- - hg_trading_robot_basket: 组合配置（共享保证金预算、最大同时持仓交易对数）
+-- - hg_trading_robot.basket_id / allocation_weight: 组合内每个交易对是一个普通机器人（腿），
+--   沿用现有引擎/私有WS（按 api_config_id 复用同一条连接），按分配权重切分保证金预算
+-- - hg_trading_robot_run_session.basket_id: 运行区间归属组合，用于汇总分币种/合计盈亏
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `hg_trading_robot_basket` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `user_id` bigint(20) NOT NULL COMMENT '用户ID',
+  `basket_name` varchar(100) NOT NULL COMMENT '组合名称',
+  `api_config_id` bigint(20) NOT NULL COMMENT 'API接口ID',
+  `exchange` varchar(20) NOT NULL DEFAULT '' COMMENT '交易所',
+  `margin_budget` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '共享保证金预算(USDT)：0=按可用余额',
+  `max_positions` int(11) NOT NULL DEFAULT '0' COMMENT '最大同时持仓交易对数：0=不限',
+  `status` tinyint(4) NOT NULL DEFAULT '1' COMMENT '状态：1=未启动,2=运行中,3=暂停,4=停用',
+  `start_time` datetime DEFAULT NULL COMMENT '启动时间',
+  `stop_time` datetime DEFAULT NULL COMMENT '停止时间',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `deleted_at` datetime DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_api_config_id` (`api_config_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='组合机器人表';
+
+ALTER TABLE `hg_trading_robot`
+  ADD COLUMN IF NOT EXISTS `basket_id` BIGINT NOT NULL DEFAULT 0 COMMENT '组合机器人ID：0=独立机器人',
+  ADD COLUMN IF NOT EXISTS `allocation_weight` DECIMAL(6,4) NOT NULL DEFAULT 0 COMMENT '组合内分配权重';
+
+ALTER TABLE `hg_trading_robot`
+  ADD INDEX `idx_basket_id` (`basket_id`);
+
+ALTER TABLE `hg_trading_robot_run_session`
+  ADD COLUMN IF NOT EXISTS `basket_id` BIGINT NOT NULL DEFAULT 0 COMMENT '组合机器人ID：0=独立机器人' AFTER `robot_id`;
+
+ALTER TABLE `hg_trading_robot_run_session`
+  ADD INDEX `idx_basket_id` (`basket_id`);
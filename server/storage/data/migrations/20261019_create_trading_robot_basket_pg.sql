@@ -0,0 +1,38 @@
+-- 组合机器人（说明见 MySQL 版本）
+-- PostgreSQL version
+CREATE TABLE IF NOT EXISTS hg_trading_robot_basket (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL,
+  basket_name VARCHAR(100) NOT NULL,
+  api_config_id BIGINT NOT NULL,
+  exchange VARCHAR(20) NOT NULL DEFAULT '',
+  margin_budget NUMERIC(20,8) NOT NULL DEFAULT 0,
+  max_positions INT NOT NULL DEFAULT 0,
+  status SMALLINT NOT NULL DEFAULT 1,
+  start_time TIMESTAMP NULL,
+  stop_time TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  deleted_at TIMESTAMP NULL
+);
+CREATE INDEX IF NOT EXISTS idx_trading_robot_basket_user_id ON hg_trading_robot_basket (user_id);
+CREATE INDEX IF NOT EXISTS idx_trading_robot_basket_api_config_id ON hg_trading_robot_basket (api_config_id);
+
+COMMENT ON TABLE hg_trading_robot_basket IS '组合机器人表';
+COMMENT ON COLUMN hg_trading_robot_basket.margin_budget IS '共享保证金预算(USDT)：0=按可用余额';
+COMMENT ON COLUMN hg_trading_robot_basket.max_positions IS '最大同时持仓交易对数：0=不限';
+COMMENT ON COLUMN hg_trading_robot_basket.status IS '状态：1=未启动,2=运行中,3=暂停,4=停用';
+
+ALTER TABLE hg_trading_robot
+  ADD COLUMN IF NOT EXISTS basket_id BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS allocation_weight NUMERIC(6,4) NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_trading_robot_basket_id ON hg_trading_robot (basket_id);
+
+COMMENT ON COLUMN hg_trading_robot.basket_id IS '组合机器人ID：0=独立机器人';
+COMMENT ON COLUMN hg_trading_robot.allocation_weight IS '组合内分配权重';
+
+ALTER TABLE hg_trading_robot_run_session
+  ADD COLUMN IF NOT EXISTS basket_id BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_trading_robot_run_session_basket_id ON hg_trading_robot_run_session (basket_id);
+
+COMMENT ON COLUMN hg_trading_robot_run_session.basket_id IS '组合机器人ID：0=独立机器人';
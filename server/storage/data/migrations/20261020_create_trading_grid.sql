@@ -0,0 +1,54 @@
+-- ============================================================
+-- 网格交易模式
+-- 说明：
+-- - hg_trading_robot.robot_mode / grid_config: 机器人运行模式（signal=方向信号，grid=网格）与网格参数
+-- - hg_trading_grid_cell: 每个网格格子的状态；每格始终只挂一张单（空仓挂开仓单，持仓挂平仓单），成交后翻转
+-- - hg_trading_grid_trade: 每完成一次“开仓→平仓”的网格利润记录（算力按已实现网格利润消耗）
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+ALTER TABLE `hg_trading_robot`
+  ADD COLUMN IF NOT EXISTS `robot_mode` VARCHAR(20) NOT NULL DEFAULT 'signal' COMMENT '运行模式：signal=方向信号,grid=网格',
+  ADD COLUMN IF NOT EXISTS `grid_config` TEXT NULL COMMENT '网格配置(JSON)';
+
+CREATE TABLE IF NOT EXISTS `hg_trading_grid_cell` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `robot_id` bigint(20) NOT NULL COMMENT '机器人ID',
+  `cell_index` int(11) NOT NULL COMMENT '网格序号（自下而上，从0开始）',
+  `lower_price` decimal(20,8) NOT NULL COMMENT '网格下沿价格',
+  `upper_price` decimal(20,8) NOT NULL COMMENT '网格上沿价格',
+  `direction` varchar(10) NOT NULL DEFAULT 'long' COMMENT '方向：long=低买高卖,short=高卖低买',
+  `quantity` decimal(20,8) NOT NULL COMMENT '每格数量',
+  `holding` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否持仓：0=空仓(挂开仓单),1=持仓(挂平仓单)',
+  `order_id` varchar(64) NOT NULL DEFAULT '' COMMENT '当前挂单的交易所订单ID',
+  `order_side` varchar(10) NOT NULL DEFAULT '' COMMENT '当前挂单方向：BUY/SELL',
+  `order_price` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '当前挂单价格',
+  `entry_price` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '本轮开仓成交价',
+  `round_trips` int(11) NOT NULL DEFAULT '0' COMMENT '已完成套利次数',
+  `realized_profit` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '累计已实现网格利润(USDT)',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_robot_cell` (`robot_id`, `cell_index`),
+  KEY `idx_order_id` (`order_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='网格格子状态表';
+
+CREATE TABLE IF NOT EXISTS `hg_trading_grid_trade` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `user_id` bigint(20) NOT NULL COMMENT '用户ID',
+  `robot_id` bigint(20) NOT NULL COMMENT '机器人ID',
+  `symbol` varchar(50) NOT NULL DEFAULT '' COMMENT '交易对',
+  `cell_index` int(11) NOT NULL COMMENT '网格序号',
+  `direction` varchar(10) NOT NULL DEFAULT '' COMMENT '方向：long/short',
+  `open_price` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '开仓成交价',
+  `close_price` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '平仓成交价',
+  `quantity` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '数量',
+  `profit` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '本次网格利润(USDT)',
+  `close_order_id` varchar(64) NOT NULL DEFAULT '' COMMENT '平仓交易所订单ID',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_close_order` (`robot_id`, `close_order_id`),
+  KEY `idx_robot_created` (`robot_id`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='网格利润记录表';
@@ -0,0 +1,55 @@
+-- 网格交易模式（说明见 MySQL 版本）
+-- PostgreSQL version
+ALTER TABLE hg_trading_robot
+  ADD COLUMN IF NOT EXISTS robot_mode VARCHAR(20) NOT NULL DEFAULT 'signal',
+  ADD COLUMN IF NOT EXISTS grid_config TEXT NULL;
+
+COMMENT ON COLUMN hg_trading_robot.robot_mode IS '运行模式：signal=方向信号,grid=网格';
+COMMENT ON COLUMN hg_trading_robot.grid_config IS '网格配置(JSON)';
+
+CREATE TABLE IF NOT EXISTS hg_trading_grid_cell (
+  id BIGSERIAL PRIMARY KEY,
+  robot_id BIGINT NOT NULL,
+  cell_index INT NOT NULL,
+  lower_price NUMERIC(20,8) NOT NULL,
+  upper_price NUMERIC(20,8) NOT NULL,
+  direction VARCHAR(10) NOT NULL DEFAULT 'long',
+  quantity NUMERIC(20,8) NOT NULL,
+  holding SMALLINT NOT NULL DEFAULT 0,
+  order_id VARCHAR(64) NOT NULL DEFAULT '',
+  order_side VARCHAR(10) NOT NULL DEFAULT '',
+  order_price NUMERIC(20,8) NOT NULL DEFAULT 0,
+  entry_price NUMERIC(20,8) NOT NULL DEFAULT 0,
+  round_trips INT NOT NULL DEFAULT 0,
+  realized_profit NUMERIC(20,8) NOT NULL DEFAULT 0,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_trading_grid_cell_robot_cell ON hg_trading_grid_cell (robot_id, cell_index);
+CREATE INDEX IF NOT EXISTS idx_trading_grid_cell_order_id ON hg_trading_grid_cell (order_id);
+
+COMMENT ON TABLE hg_trading_grid_cell IS '网格格子状态表';
+COMMENT ON COLUMN hg_trading_grid_cell.cell_index IS '网格序号（自下而上，从0开始）';
+COMMENT ON COLUMN hg_trading_grid_cell.direction IS '方向：long=低买高卖,short=高卖低买';
+COMMENT ON COLUMN hg_trading_grid_cell.holding IS '是否持仓：0=空仓(挂开仓单),1=持仓(挂平仓单)';
+COMMENT ON COLUMN hg_trading_grid_cell.order_id IS '当前挂单的交易所订单ID';
+
+CREATE TABLE IF NOT EXISTS hg_trading_grid_trade (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL,
+  robot_id BIGINT NOT NULL,
+  symbol VARCHAR(50) NOT NULL DEFAULT '',
+  cell_index INT NOT NULL,
+  direction VARCHAR(10) NOT NULL DEFAULT '',
+  open_price NUMERIC(20,8) NOT NULL DEFAULT 0,
+  close_price NUMERIC(20,8) NOT NULL DEFAULT 0,
+  quantity NUMERIC(20,8) NOT NULL DEFAULT 0,
+  profit NUMERIC(20,8) NOT NULL DEFAULT 0,
+  close_order_id VARCHAR(64) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_trading_grid_trade_close_order ON hg_trading_grid_trade (robot_id, close_order_id);
+CREATE INDEX IF NOT EXISTS idx_trading_grid_trade_robot_created ON hg_trading_grid_trade (robot_id, created_at);
+
+COMMENT ON TABLE hg_trading_grid_trade IS '网格利润记录表';
+COMMENT ON COLUMN hg_trading_grid_trade.profit IS '本次网格利润(USDT)';
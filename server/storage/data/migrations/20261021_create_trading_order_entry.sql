@@ -0,0 +1,30 @@
+-- ============================================================
+-- 分批加仓（DCA）
+-- 说明：
+-- - 策略模板 config_json.scaling: 加仓配置（最大加仓次数/触发步长/数量倍数/总保证金上限）
+-- - hg_trading_order.scale_config / entry_count: 开仓时冻结的加仓配置与当前已成交批次数（含首单）
+-- - hg_trading_order_entry: 每一批开仓（首单 + 加仓单）的成交明细；主订单的 open_price/quantity/margin 为加权汇总
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+ALTER TABLE `hg_trading_order`
+  ADD COLUMN IF NOT EXISTS `scale_config` TEXT NULL COMMENT '开仓时冻结的加仓配置(JSON)',
+  ADD COLUMN IF NOT EXISTS `entry_count` int(11) NOT NULL DEFAULT '1' COMMENT '已成交开仓批次数(含首单)';
+
+CREATE TABLE IF NOT EXISTS `hg_trading_order_entry` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `order_id` bigint(20) NOT NULL COMMENT '主订单ID(hg_trading_order.id)',
+  `robot_id` bigint(20) NOT NULL COMMENT '机器人ID',
+  `entry_index` int(11) NOT NULL COMMENT '批次序号：0=首单,1..N=加仓',
+  `exchange_order_id` varchar(64) NOT NULL DEFAULT '' COMMENT '交易所订单ID',
+  `price` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '成交均价',
+  `quantity` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '成交数量',
+  `margin` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '本批保证金(USDT)',
+  `avg_price_after` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '本批成交后的持仓均价',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_order_entry` (`order_id`, `entry_index`),
+  KEY `idx_exchange_order_id` (`exchange_order_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='订单分批开仓明细表';
@@ -0,0 +1,32 @@
+-- 分批加仓（说明见 MySQL 版本）
+-- PostgreSQL version
+ALTER TABLE hg_trading_order
+  ADD COLUMN IF NOT EXISTS scale_config TEXT NULL,
+  ADD COLUMN IF NOT EXISTS entry_count INT NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN hg_trading_order.scale_config IS '开仓时冻结的加仓配置(JSON)';
+COMMENT ON COLUMN hg_trading_order.entry_count IS '已成交开仓批次数(含首单)';
+
+CREATE TABLE IF NOT EXISTS hg_trading_order_entry (
+  id BIGSERIAL PRIMARY KEY,
+  order_id BIGINT NOT NULL,
+  robot_id BIGINT NOT NULL,
+  entry_index INT NOT NULL,
+  exchange_order_id VARCHAR(64) NOT NULL DEFAULT '',
+  price NUMERIC(20,8) NOT NULL DEFAULT 0,
+  quantity NUMERIC(20,8) NOT NULL DEFAULT 0,
+  margin NUMERIC(20,8) NOT NULL DEFAULT 0,
+  avg_price_after NUMERIC(20,8) NOT NULL DEFAULT 0,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT uk_order_entry UNIQUE (order_id, entry_index)
+);
+CREATE INDEX IF NOT EXISTS idx_trading_order_entry_exchange_order_id ON hg_trading_order_entry (exchange_order_id);
+
+COMMENT ON TABLE hg_trading_order_entry IS '订单分批开仓明细表';
+COMMENT ON COLUMN hg_trading_order_entry.order_id IS '主订单ID(hg_trading_order.id)';
+COMMENT ON COLUMN hg_trading_order_entry.entry_index IS '批次序号：0=首单,1..N=加仓';
+COMMENT ON COLUMN hg_trading_order_entry.exchange_order_id IS '交易所订单ID';
+COMMENT ON COLUMN hg_trading_order_entry.price IS '成交均价';
+COMMENT ON COLUMN hg_trading_order_entry.quantity IS '成交数量';
+COMMENT ON COLUMN hg_trading_order_entry.margin IS '本批保证金(USDT)';
+COMMENT ON COLUMN hg_trading_order_entry.avg_price_after IS '本批成交后的持仓均价';
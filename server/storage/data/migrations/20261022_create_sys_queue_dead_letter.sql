@@ -0,0 +1,25 @@
+-- ============================================================
+-- 消息队列死信
+-- 说明：
+-- - 开启 queue.reliable 后，消费重试用尽的消息写入 hg_sys_queue_dead_letter
+-- - 后台可查看失败原因，并重放到原主题或标记忽略
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `hg_sys_queue_dead_letter` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '死信ID',
+  `topic` varchar(128) NOT NULL COMMENT '原始主题',
+  `msg_id` varchar(64) NOT NULL DEFAULT '' COMMENT '消息ID',
+  `body` longtext NULL COMMENT '消息体',
+  `error` text NULL COMMENT '最后一次消费错误',
+  `attempts` int(11) NOT NULL DEFAULT '0' COMMENT '已尝试消费次数',
+  `status` tinyint(1) NOT NULL DEFAULT '1' COMMENT '状态：1=待处理,2=已重放,3=已忽略',
+  `replay_count` int(11) NOT NULL DEFAULT '0' COMMENT '重放次数',
+  `replayed_at` datetime NULL DEFAULT NULL COMMENT '最后重放时间',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '进入死信时间',
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_topic_status` (`topic`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='消息队列死信表';
@@ -0,0 +1,26 @@
+-- 消息队列死信（说明见 MySQL 版本）
+-- PostgreSQL version
+CREATE TABLE IF NOT EXISTS hg_sys_queue_dead_letter (
+  id BIGSERIAL PRIMARY KEY,
+  topic VARCHAR(128) NOT NULL,
+  msg_id VARCHAR(64) NOT NULL DEFAULT '',
+  body TEXT NULL,
+  error TEXT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  status SMALLINT NOT NULL DEFAULT 1,
+  replay_count INT NOT NULL DEFAULT 0,
+  replayed_at TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_sys_queue_dead_letter_topic_status ON hg_sys_queue_dead_letter (topic, status);
+
+COMMENT ON TABLE hg_sys_queue_dead_letter IS '消息队列死信表';
+COMMENT ON COLUMN hg_sys_queue_dead_letter.topic IS '原始主题';
+COMMENT ON COLUMN hg_sys_queue_dead_letter.msg_id IS '消息ID';
+COMMENT ON COLUMN hg_sys_queue_dead_letter.body IS '消息体';
+COMMENT ON COLUMN hg_sys_queue_dead_letter.error IS '最后一次消费错误';
+COMMENT ON COLUMN hg_sys_queue_dead_letter.attempts IS '已尝试消费次数';
+COMMENT ON COLUMN hg_sys_queue_dead_letter.status IS '状态：1=待处理,2=已重放,3=已忽略';
+COMMENT ON COLUMN hg_sys_queue_dead_letter.replay_count IS '重放次数';
+COMMENT ON COLUMN hg_sys_queue_dead_letter.replayed_at IS '最后重放时间';
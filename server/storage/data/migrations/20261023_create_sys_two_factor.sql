@@ -0,0 +1,40 @@
+-- ============================================================
+-- 双因素认证（TOTP）与敏感操作审计
+-- 说明：
+-- - hg_sys_two_factor 保存用户的验证器密钥（加密存储）与恢复码哈希
+-- - hg_sys_sensitive_log 记录敏感操作及二次验证结果
+-- - 登录配置 loginTwoFactorRoleIds：列表中的角色登录时必须完成双因素认证
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `hg_sys_two_factor` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `member_id` bigint(20) NOT NULL COMMENT '用户ID',
+  `secret` varchar(255) NOT NULL DEFAULT '' COMMENT '验证器密钥（加密）',
+  `status` tinyint(1) NOT NULL DEFAULT '0' COMMENT '状态：0=待绑定,1=已启用',
+  `recovery_codes` text NULL COMMENT '恢复码哈希(JSON)',
+  `last_used_step` bigint(20) NOT NULL DEFAULT '0' COMMENT '最后使用的口令步数，防重放',
+  `enabled_at` datetime NULL DEFAULT NULL COMMENT '启用时间',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_member_id` (`member_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='双因素认证表';
+
+CREATE TABLE IF NOT EXISTS `hg_sys_sensitive_log` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `member_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '用户ID',
+  `operation` varchar(64) NOT NULL DEFAULT '' COMMENT '操作',
+  `details` text NULL COMMENT '详情',
+  `ip` varchar(64) NOT NULL DEFAULT '' COMMENT '客户端IP',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '操作时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_member_id` (`member_id`),
+  KEY `idx_operation` (`operation`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='敏感操作审计表';
+
+INSERT INTO `hg_sys_config` (`group`, `name`, `type`, `key`, `value`, `default_value`, `sort`, `tip`, `is_default`, `status`, `created_at`, `updated_at`)
+SELECT 'login', '强制双因素认证角色', '[]int64', 'loginTwoFactorRoleIds', '[]', '[]', 1200, '列表中的角色登录时必须完成双因素认证，未绑定的用户在登录时引导绑定', 1, 1, NOW(), NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM `hg_sys_config` WHERE `key` = 'loginTwoFactorRoleIds');
@@ -0,0 +1,43 @@
+-- 双因素认证（TOTP）与敏感操作审计（说明见 MySQL 版本）
+-- PostgreSQL version
+CREATE TABLE IF NOT EXISTS hg_sys_two_factor (
+  id BIGSERIAL PRIMARY KEY,
+  member_id BIGINT NOT NULL,
+  secret VARCHAR(255) NOT NULL DEFAULT '',
+  status SMALLINT NOT NULL DEFAULT 0,
+  recovery_codes TEXT NULL,
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  enabled_at TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_sys_two_factor_member_id ON hg_sys_two_factor (member_id);
+
+COMMENT ON TABLE hg_sys_two_factor IS '双因素认证表';
+COMMENT ON COLUMN hg_sys_two_factor.member_id IS '用户ID';
+COMMENT ON COLUMN hg_sys_two_factor.secret IS '验证器密钥（加密）';
+COMMENT ON COLUMN hg_sys_two_factor.status IS '状态：0=待绑定,1=已启用';
+COMMENT ON COLUMN hg_sys_two_factor.recovery_codes IS '恢复码哈希(JSON)';
+COMMENT ON COLUMN hg_sys_two_factor.last_used_step IS '最后使用的口令步数，防重放';
+COMMENT ON COLUMN hg_sys_two_factor.enabled_at IS '启用时间';
+
+CREATE TABLE IF NOT EXISTS hg_sys_sensitive_log (
+  id BIGSERIAL PRIMARY KEY,
+  member_id BIGINT NOT NULL DEFAULT 0,
+  operation VARCHAR(64) NOT NULL DEFAULT '',
+  details TEXT NULL,
+  ip VARCHAR(64) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_sys_sensitive_log_member_id ON hg_sys_sensitive_log (member_id);
+CREATE INDEX IF NOT EXISTS idx_sys_sensitive_log_operation ON hg_sys_sensitive_log (operation);
+
+COMMENT ON TABLE hg_sys_sensitive_log IS '敏感操作审计表';
+COMMENT ON COLUMN hg_sys_sensitive_log.member_id IS '用户ID';
+COMMENT ON COLUMN hg_sys_sensitive_log.operation IS '操作';
+COMMENT ON COLUMN hg_sys_sensitive_log.details IS '详情';
+COMMENT ON COLUMN hg_sys_sensitive_log.ip IS '客户端IP';
+
+INSERT INTO hg_sys_config ("group", name, type, key, value, default_value, sort, tip, is_default, status, created_at, updated_at)
+SELECT 'login', '强制双因素认证角色', '[]int64', 'loginTwoFactorRoleIds', '[]', '[]', 1200, '列表中的角色登录时必须完成双因素认证，未绑定的用户在登录时引导绑定', 1, 1, NOW(), NOW()
+WHERE NOT EXISTS (SELECT 1 FROM hg_sys_config WHERE key = 'loginTwoFactorRoleIds');
@@ -0,0 +1,14 @@
+-- ============================================================
+-- API密钥信封加密
+-- 说明：
+-- - hg_trading_api_config.data_key: 每条记录独立的数据密钥，经主密钥包装后 base64 保存
+-- - hg_trading_api_config.key_version: 包装数据密钥的主密钥版本，主密钥轮换后执行 `tools -m=vault -a1=rewrap` 迁移
+-- - data_key 为空的旧数据仍按原静态密钥解密，执行 `tools -m=vault -a1=rewrap` 后迁移为信封加密
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+ALTER TABLE `hg_trading_api_config`
+  ADD COLUMN IF NOT EXISTS `data_key` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '数据密钥（主密钥包装）',
+  ADD COLUMN IF NOT EXISTS `key_version` INT NOT NULL DEFAULT 0 COMMENT '主密钥版本：0=旧版静态密钥';
@@ -0,0 +1,8 @@
+-- API密钥信封加密（说明见 MySQL 版本）
+-- PostgreSQL version
+ALTER TABLE hg_trading_api_config
+  ADD COLUMN IF NOT EXISTS data_key VARCHAR(255) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS key_version INT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN hg_trading_api_config.data_key IS '数据密钥（主密钥包装）';
+COMMENT ON COLUMN hg_trading_api_config.key_version IS '主密钥版本：0=旧版静态密钥';
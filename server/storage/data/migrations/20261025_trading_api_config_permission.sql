@@ -0,0 +1,24 @@
+-- ============================================================
+-- API Key 权限与IP限制检测
+-- 说明：
+-- - hg_trading_api_config.perm_*: 最近一次检测到的 API Key 权限（0=未开启,1=已开启,2=无法确认）
+-- - perm_status: 综合判定，3=不可用（开启提现/无合约权限/已过期）时机器人禁止启动
+-- - hg_sys_cron.ToogoApiKeyPermissionCheck: 定期复检，权限变化时推送通知
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+ALTER TABLE `hg_trading_api_config`
+  ADD COLUMN IF NOT EXISTS `perm_status` TINYINT NOT NULL DEFAULT 0 COMMENT '权限检测状态：0=未检测,1=正常,2=风险提示,3=不可用',
+  ADD COLUMN IF NOT EXISTS `perm_futures` TINYINT NOT NULL DEFAULT 2 COMMENT '合约交易权限：0=未开启,1=已开启,2=无法确认',
+  ADD COLUMN IF NOT EXISTS `perm_withdraw` TINYINT NOT NULL DEFAULT 2 COMMENT '提现权限：0=未开启,1=已开启,2=无法确认',
+  ADD COLUMN IF NOT EXISTS `ip_restricted` TINYINT NOT NULL DEFAULT 0 COMMENT '是否绑定IP白名单：0=否,1=是',
+  ADD COLUMN IF NOT EXISTS `ip_whitelist` VARCHAR(500) NOT NULL DEFAULT '' COMMENT 'IP白名单（逗号分隔）',
+  ADD COLUMN IF NOT EXISTS `key_expire_at` DATETIME NULL COMMENT 'API Key过期时间',
+  ADD COLUMN IF NOT EXISTS `perm_message` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '权限检测结果说明',
+  ADD COLUMN IF NOT EXISTS `perm_checked_at` DATETIME NULL COMMENT '最后权限检测时间';
+
+INSERT INTO `hg_sys_cron` (`group_id`, `title`, `name`, `params`, `pattern`, `policy`, `count`, `sort`, `remark`, `status`, `created_at`, `updated_at`)
+SELECT 10, 'API Key Permission Check', 'ToogoApiKeyPermissionCheck', '', '@every 6h', 1, 0, 70, 'Re-check exchange API key permissions', 1, NOW(), NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM `hg_sys_cron` WHERE `name` = 'ToogoApiKeyPermissionCheck');
@@ -0,0 +1,24 @@
+-- API Key 权限与IP限制检测（说明见 MySQL 版本）
+-- PostgreSQL version
+ALTER TABLE hg_trading_api_config
+  ADD COLUMN IF NOT EXISTS perm_status SMALLINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS perm_futures SMALLINT NOT NULL DEFAULT 2,
+  ADD COLUMN IF NOT EXISTS perm_withdraw SMALLINT NOT NULL DEFAULT 2,
+  ADD COLUMN IF NOT EXISTS ip_restricted SMALLINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS ip_whitelist VARCHAR(500) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS key_expire_at TIMESTAMP NULL,
+  ADD COLUMN IF NOT EXISTS perm_message VARCHAR(500) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS perm_checked_at TIMESTAMP NULL;
+
+COMMENT ON COLUMN hg_trading_api_config.perm_status IS '权限检测状态：0=未检测,1=正常,2=风险提示,3=不可用';
+COMMENT ON COLUMN hg_trading_api_config.perm_futures IS '合约交易权限：0=未开启,1=已开启,2=无法确认';
+COMMENT ON COLUMN hg_trading_api_config.perm_withdraw IS '提现权限：0=未开启,1=已开启,2=无法确认';
+COMMENT ON COLUMN hg_trading_api_config.ip_restricted IS '是否绑定IP白名单：0=否,1=是';
+COMMENT ON COLUMN hg_trading_api_config.ip_whitelist IS 'IP白名单（逗号分隔）';
+COMMENT ON COLUMN hg_trading_api_config.key_expire_at IS 'API Key过期时间';
+COMMENT ON COLUMN hg_trading_api_config.perm_message IS '权限检测结果说明';
+COMMENT ON COLUMN hg_trading_api_config.perm_checked_at IS '最后权限检测时间';
+
+INSERT INTO hg_sys_cron (group_id, title, name, params, pattern, policy, count, sort, remark, status, created_at, updated_at)
+SELECT 10, 'API Key Permission Check', 'ToogoApiKeyPermissionCheck', '', '@every 6h', 1, 0, 70, 'Re-check exchange API key permissions', 1, NOW(), NOW()
+WHERE NOT EXISTS (SELECT 1 FROM hg_sys_cron WHERE name = 'ToogoApiKeyPermissionCheck');
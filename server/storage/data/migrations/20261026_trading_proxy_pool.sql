@@ -0,0 +1,21 @@
+-- ============================================================
+-- 代理池：多代理健康检查与故障切换
+-- 说明：
+-- - hg_trading_proxy_config 由单条全局代理扩展为代理池，user_id=0 为全局代理，user_id>0 为用户专属代理
+-- - 原全局代理（id 最小的 user_id=0 记录）仍作为“默认代理”，由原有 获取/保存/开关 接口维护
+-- - test_status/last_test_time/test_message 记录最近一次健康检查结果，latency/venue_latency 记录到各交易所的延迟
+-- - hg_sys_cron.ToogoProxyHealthCheck: 定期检查代理池健康状态
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+ALTER TABLE `hg_trading_proxy_config`
+  ADD COLUMN IF NOT EXISTS `name` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '代理名称' AFTER `user_id`,
+  ADD COLUMN IF NOT EXISTS `weight` INT NOT NULL DEFAULT 1 COMMENT '选择权重' AFTER `name`,
+  ADD COLUMN IF NOT EXISTS `latency` INT NOT NULL DEFAULT 0 COMMENT '平均延迟(ms)' AFTER `test_message`,
+  ADD COLUMN IF NOT EXISTS `venue_latency` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '各交易所延迟(ms)，JSON' AFTER `latency`;
+
+INSERT INTO `hg_sys_cron` (`group_id`, `title`, `name`, `params`, `pattern`, `policy`, `count`, `sort`, `remark`, `status`, `created_at`, `updated_at`)
+SELECT 10, 'Proxy Pool Health Check', 'ToogoProxyHealthCheck', '', '@every 1m', 1, 0, 71, 'Check proxy pool latency to each exchange', 1, NOW(), NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM `hg_sys_cron` WHERE `name` = 'ToogoProxyHealthCheck');
@@ -0,0 +1,43 @@
+-- ============================================================
+-- 业绩费（高水位）结算：替代已禁用的逐单盈利扣算力
+-- 说明：
+-- - hg_toogo_performance_fee: 每个用户+机器人的高水位状态；累计净已实现盈亏（成交流水 realized_pnl - fee）
+--   超过历史高水位的部分才计费，亏损需先回本，手动平仓/盈亏对冲都无法规避
+-- - hg_toogo_power_consume: 新增 settle_type=hwm 的周期结算记录（order_id=0，order_sn 为结算单号）
+-- - hg_sys_cron.ToogoPowerSettlement: 由每分钟空跑改为每天结算；未开启 toogo.performanceFee.enabled 时只输出试算报告
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `hg_toogo_performance_fee` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `user_id` bigint(20) NOT NULL COMMENT '用户ID(member_id)',
+  `robot_id` bigint(20) NOT NULL COMMENT '机器人ID',
+  `cum_pnl` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '累计净已实现盈亏(USDT)',
+  `high_water_mark` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '已计费高水位(USDT)',
+  `settled_until` bigint(20) NOT NULL DEFAULT '0' COMMENT '已结算至(成交时间戳毫秒)',
+  `total_consume_power` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '累计收取算力',
+  `last_settle_at` datetime DEFAULT NULL COMMENT '最近结算时间',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_user_robot` (`user_id`, `robot_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='业绩费高水位状态表';
+
+ALTER TABLE `hg_toogo_power_consume`
+  ADD COLUMN IF NOT EXISTS `settle_type` varchar(10) NOT NULL DEFAULT 'order' COMMENT '结算方式：order=逐单(已停用),hwm=高水位周期结算' AFTER `order_sn`,
+  ADD COLUMN IF NOT EXISTS `period_start` bigint(20) NOT NULL DEFAULT '0' COMMENT '结算周期开始(毫秒,不含)' AFTER `settle_type`,
+  ADD COLUMN IF NOT EXISTS `period_end` bigint(20) NOT NULL DEFAULT '0' COMMENT '结算周期结束(毫秒,含)' AFTER `period_start`,
+  ADD COLUMN IF NOT EXISTS `net_pnl` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '本期净已实现盈亏' AFTER `period_end`,
+  ADD COLUMN IF NOT EXISTS `hwm_before` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '结算前高水位' AFTER `net_pnl`,
+  ADD COLUMN IF NOT EXISTS `hwm_after` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '结算后高水位' AFTER `hwm_before`,
+  ADD COLUMN IF NOT EXISTS `from_gift_power` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '从积分账户扣除' AFTER `from_power`,
+  ADD COLUMN IF NOT EXISTS `power_after` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '扣除后算力余额(负数为欠费)' AFTER `from_gift_power`;
+
+UPDATE `hg_sys_cron` SET `pattern` = '@every 24h', `remark` = 'High-water-mark performance fee settlement', `updated_at` = NOW()
+WHERE `name` = 'ToogoPowerSettlement' AND `pattern` = '@every 1m';
+
+INSERT INTO `hg_sys_cron` (`group_id`, `title`, `name`, `params`, `pattern`, `policy`, `count`, `sort`, `remark`, `status`, `created_at`, `updated_at`)
+SELECT 10, 'Power Settle', 'ToogoPowerSettlement', '', '@every 24h', 1, 0, 30, 'High-water-mark performance fee settlement', 1, NOW(), NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM `hg_sys_cron` WHERE `name` = 'ToogoPowerSettlement');
@@ -0,0 +1,50 @@
+-- ============================================================
+-- 业绩费（高水位）结算：替代已禁用的逐单盈利扣算力（说明见 MySQL 版本）
+-- PostgreSQL version
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS hg_toogo_performance_fee (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL,
+  robot_id BIGINT NOT NULL,
+  cum_pnl NUMERIC(20,8) NOT NULL DEFAULT 0,
+  high_water_mark NUMERIC(20,8) NOT NULL DEFAULT 0,
+  settled_until BIGINT NOT NULL DEFAULT 0,
+  total_consume_power NUMERIC(20,8) NOT NULL DEFAULT 0,
+  last_settle_at TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_toogo_performance_fee_user_robot ON hg_toogo_performance_fee (user_id, robot_id);
+
+COMMENT ON TABLE hg_toogo_performance_fee IS '业绩费高水位状态表';
+COMMENT ON COLUMN hg_toogo_performance_fee.cum_pnl IS '累计净已实现盈亏(USDT)';
+COMMENT ON COLUMN hg_toogo_performance_fee.high_water_mark IS '已计费高水位(USDT)';
+COMMENT ON COLUMN hg_toogo_performance_fee.settled_until IS '已结算至(成交时间戳毫秒)';
+COMMENT ON COLUMN hg_toogo_performance_fee.total_consume_power IS '累计收取算力';
+
+ALTER TABLE hg_toogo_power_consume
+  ADD COLUMN IF NOT EXISTS settle_type VARCHAR(10) NOT NULL DEFAULT 'order',
+  ADD COLUMN IF NOT EXISTS period_start BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS period_end BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS net_pnl NUMERIC(20,8) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS hwm_before NUMERIC(20,8) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS hwm_after NUMERIC(20,8) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS from_gift_power NUMERIC(20,8) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS power_after NUMERIC(20,8) NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN hg_toogo_power_consume.settle_type IS '结算方式：order=逐单(已停用),hwm=高水位周期结算';
+COMMENT ON COLUMN hg_toogo_power_consume.period_start IS '结算周期开始(毫秒,不含)';
+COMMENT ON COLUMN hg_toogo_power_consume.period_end IS '结算周期结束(毫秒,含)';
+COMMENT ON COLUMN hg_toogo_power_consume.net_pnl IS '本期净已实现盈亏';
+COMMENT ON COLUMN hg_toogo_power_consume.hwm_before IS '结算前高水位';
+COMMENT ON COLUMN hg_toogo_power_consume.hwm_after IS '结算后高水位';
+COMMENT ON COLUMN hg_toogo_power_consume.from_gift_power IS '从积分账户扣除';
+COMMENT ON COLUMN hg_toogo_power_consume.power_after IS '扣除后算力余额(负数为欠费)';
+
+UPDATE hg_sys_cron SET pattern = '@every 24h', remark = 'High-water-mark performance fee settlement', updated_at = NOW()
+WHERE name = 'ToogoPowerSettlement' AND pattern = '@every 1m';
+
+INSERT INTO hg_sys_cron (group_id, title, name, params, pattern, policy, count, sort, remark, status, created_at, updated_at)
+SELECT 10, 'Power Settle', 'ToogoPowerSettlement', '', '@every 24h', 1, 0, 30, 'High-water-mark performance fee settlement', 1, NOW(), NOW()
+WHERE NOT EXISTS (SELECT 1 FROM hg_sys_cron WHERE name = 'ToogoPowerSettlement');
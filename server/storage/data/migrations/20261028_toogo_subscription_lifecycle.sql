@@ -0,0 +1,38 @@
+-- ============================================================
+-- 订阅生命周期：升级/降级按剩余价值折算、余额自动续费、到期提醒、宽限期
+-- 说明：
+-- - hg_toogo_subscription.status 新增 5=宽限期（到期后机器人只管理持仓不再开仓）、6=已变更（升降级时被新订阅替换）
+-- - auto_renew: 到期前从余额自动续费同套餐同周期；remind_stage: 已发送的到期提醒（1=T-3天,2=T-1天）
+-- - hg_toogo_user.plan_grace_expire: 宽限期截止时间，宽限期结束后才停止机器人并重置为免费套餐
+-- - hg_toogo_subscription_log: 订阅状态变更审计
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+ALTER TABLE `hg_toogo_subscription`
+  MODIFY COLUMN `status` tinyint(2) NOT NULL DEFAULT '1' COMMENT '状态: 1=待支付, 2=生效中, 3=已过期, 4=已取消, 5=宽限期, 6=已变更',
+  ADD COLUMN IF NOT EXISTS `auto_renew` tinyint(1) NOT NULL DEFAULT '0' COMMENT '自动续费: 0=关闭, 1=开启' AFTER `pay_type`,
+  ADD COLUMN IF NOT EXISTS `remind_stage` tinyint(1) NOT NULL DEFAULT '0' COMMENT '到期提醒: 0=未提醒, 1=已提醒T-3天, 2=已提醒T-1天' AFTER `auto_renew`,
+  ADD COLUMN IF NOT EXISTS `grace_expire_time` datetime DEFAULT NULL COMMENT '宽限期截止时间' AFTER `remind_stage`,
+  ADD COLUMN IF NOT EXISTS `credit_amount` decimal(10,2) NOT NULL DEFAULT '0.00' COMMENT '升降级折抵金额(原订阅剩余价值)' AFTER `grace_expire_time`,
+  ADD COLUMN IF NOT EXISTS `replaced_by` bigint(20) NOT NULL DEFAULT '0' COMMENT '被替换的新订阅ID(升降级)' AFTER `credit_amount`;
+
+ALTER TABLE `hg_toogo_user`
+  ADD COLUMN IF NOT EXISTS `plan_grace_expire` datetime DEFAULT NULL COMMENT '套餐宽限期截止时间' AFTER `plan_expire_time`;
+
+CREATE TABLE IF NOT EXISTS `hg_toogo_subscription_log` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `subscription_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '订阅ID',
+  `user_id` bigint(20) NOT NULL COMMENT '用户ID(member_id)',
+  `plan_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '套餐ID',
+  `event` varchar(30) NOT NULL COMMENT '事件: subscribe/upgrade/downgrade/replaced/auto_renew/renew_failed/remind/grace/expired/auto_renew_on/auto_renew_off',
+  `from_status` tinyint(1) NOT NULL DEFAULT '0' COMMENT '变更前状态',
+  `to_status` tinyint(1) NOT NULL DEFAULT '0' COMMENT '变更后状态',
+  `amount` decimal(15,4) NOT NULL DEFAULT '0.0000' COMMENT '涉及金额(USDT)',
+  `remark` varchar(500) NOT NULL DEFAULT '' COMMENT '备注',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_subscription_id` (`subscription_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='订阅状态变更记录';
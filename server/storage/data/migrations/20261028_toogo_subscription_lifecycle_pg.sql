@@ -0,0 +1,41 @@
+-- ============================================================
+-- 订阅生命周期：升级/降级按剩余价值折算、余额自动续费、到期提醒、宽限期（说明见 MySQL 版本）
+-- PostgreSQL version
+-- ============================================================
+
+ALTER TABLE hg_toogo_subscription
+  ADD COLUMN IF NOT EXISTS auto_renew SMALLINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS remind_stage SMALLINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS grace_expire_time TIMESTAMP NULL,
+  ADD COLUMN IF NOT EXISTS credit_amount NUMERIC(10,2) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS replaced_by BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN hg_toogo_subscription.status IS '状态: 1=待支付, 2=生效中, 3=已过期, 4=已取消, 5=宽限期, 6=已变更';
+COMMENT ON COLUMN hg_toogo_subscription.auto_renew IS '自动续费: 0=关闭, 1=开启';
+COMMENT ON COLUMN hg_toogo_subscription.remind_stage IS '到期提醒: 0=未提醒, 1=已提醒T-3天, 2=已提醒T-1天';
+COMMENT ON COLUMN hg_toogo_subscription.grace_expire_time IS '宽限期截止时间';
+COMMENT ON COLUMN hg_toogo_subscription.credit_amount IS '升降级折抵金额(原订阅剩余价值)';
+COMMENT ON COLUMN hg_toogo_subscription.replaced_by IS '被替换的新订阅ID(升降级)';
+
+ALTER TABLE hg_toogo_user
+  ADD COLUMN IF NOT EXISTS plan_grace_expire TIMESTAMP NULL;
+
+COMMENT ON COLUMN hg_toogo_user.plan_grace_expire IS '套餐宽限期截止时间';
+
+CREATE TABLE IF NOT EXISTS hg_toogo_subscription_log (
+  id BIGSERIAL PRIMARY KEY,
+  subscription_id BIGINT NOT NULL DEFAULT 0,
+  user_id BIGINT NOT NULL,
+  plan_id BIGINT NOT NULL DEFAULT 0,
+  event VARCHAR(30) NOT NULL,
+  from_status SMALLINT NOT NULL DEFAULT 0,
+  to_status SMALLINT NOT NULL DEFAULT 0,
+  amount NUMERIC(15,4) NOT NULL DEFAULT 0,
+  remark VARCHAR(500) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_toogo_subscription_log_user_id ON hg_toogo_subscription_log (user_id);
+CREATE INDEX IF NOT EXISTS idx_toogo_subscription_log_subscription_id ON hg_toogo_subscription_log (subscription_id);
+
+COMMENT ON TABLE hg_toogo_subscription_log IS '订阅状态变更记录';
+COMMENT ON COLUMN hg_toogo_subscription_log.event IS '事件: subscribe/upgrade/downgrade/replaced/auto_renew/renew_failed/remind/grace/expired/auto_renew_on/auto_renew_off';
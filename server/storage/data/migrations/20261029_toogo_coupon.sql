@@ -0,0 +1,69 @@
+-- ============================================================
+-- 优惠券、代理推广码与套餐免费试用
+-- 说明：
+-- - hg_toogo_coupon: 优惠券（按比例/固定金额，可限定套餐与周期、总次数、有效期；每个用户限用一次）
+--   agent_id > 0 为代理推广码：未绑定邀请人的用户使用后，邀请人绑定为该代理，订阅佣金按新的代理链结算
+-- - hg_toogo_coupon_usage: 使用记录（coupon_id + user_id 唯一）
+-- - hg_toogo_plan.trial_days / trial_robot_limit: 套餐免费试用（period_type=trial，每个用户仅限一次）
+-- - hg_toogo_subscription.amount 为优惠后实付金额，original_amount 为原价
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+ALTER TABLE `hg_toogo_plan`
+  ADD COLUMN IF NOT EXISTS `trial_days` int(11) NOT NULL DEFAULT '0' COMMENT '免费试用天数，0为不支持试用' AFTER `gift_power_yearly`,
+  ADD COLUMN IF NOT EXISTS `trial_robot_limit` int(11) NOT NULL DEFAULT '0' COMMENT '试用期机器人数量，0为与套餐相同' AFTER `trial_days`;
+
+ALTER TABLE `hg_toogo_subscription`
+  MODIFY COLUMN `period_type` varchar(20) NOT NULL COMMENT '订阅周期: daily/monthly/quarterly/half_year/yearly/trial',
+  ADD COLUMN IF NOT EXISTS `original_amount` decimal(10,2) NOT NULL DEFAULT '0.00' COMMENT '订阅原价(USDT)' AFTER `amount`,
+  ADD COLUMN IF NOT EXISTS `discount_amount` decimal(10,2) NOT NULL DEFAULT '0.00' COMMENT '优惠券抵扣金额(USDT)' AFTER `original_amount`,
+  ADD COLUMN IF NOT EXISTS `coupon_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '优惠券ID' AFTER `discount_amount`,
+  ADD COLUMN IF NOT EXISTS `coupon_code` varchar(32) NOT NULL DEFAULT '' COMMENT '优惠码' AFTER `coupon_id`;
+
+UPDATE `hg_toogo_subscription` SET `original_amount` = `amount` WHERE `original_amount` = 0;
+
+CREATE TABLE IF NOT EXISTS `hg_toogo_coupon` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `code` varchar(32) NOT NULL COMMENT '优惠码',
+  `coupon_name` varchar(100) NOT NULL DEFAULT '' COMMENT '优惠券名称',
+  `coupon_type` varchar(20) NOT NULL COMMENT '类型: percent=按比例, fixed=固定金额',
+  `discount_value` decimal(10,2) NOT NULL DEFAULT '0.00' COMMENT '折扣值: percent为百分比, fixed为USDT',
+  `max_discount` decimal(10,2) NOT NULL DEFAULT '0.00' COMMENT '最高抵扣金额(USDT)，0为不限',
+  `min_amount` decimal(10,2) NOT NULL DEFAULT '0.00' COMMENT '最低订单金额(USDT)，0为不限',
+  `plan_ids` varchar(255) NOT NULL DEFAULT '' COMMENT '限定套餐ID(逗号分隔)，空为不限',
+  `period_types` varchar(100) NOT NULL DEFAULT '' COMMENT '限定订阅周期(逗号分隔)，空为不限',
+  `total_limit` int(11) NOT NULL DEFAULT '0' COMMENT '总使用次数上限，0为不限',
+  `used_count` int(11) NOT NULL DEFAULT '0' COMMENT '已使用次数',
+  `agent_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '归属代理ID(推广码)，0为平台券',
+  `start_time` datetime DEFAULT NULL COMMENT '生效时间',
+  `expire_time` datetime DEFAULT NULL COMMENT '过期时间',
+  `status` tinyint(1) NOT NULL DEFAULT '1' COMMENT '状态: 1=启用, 2=停用',
+  `remark` varchar(255) NOT NULL DEFAULT '' COMMENT '备注',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_code` (`code`),
+  KEY `idx_agent_id` (`agent_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='Toogo优惠券/推广码';
+
+CREATE TABLE IF NOT EXISTS `hg_toogo_coupon_usage` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `coupon_id` bigint(20) NOT NULL COMMENT '优惠券ID',
+  `coupon_code` varchar(32) NOT NULL DEFAULT '' COMMENT '优惠码',
+  `user_id` bigint(20) NOT NULL COMMENT '用户ID(member_id)',
+  `agent_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '推广码归属代理ID',
+  `attributed` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否由推广码绑定邀请关系',
+  `subscription_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '订阅ID',
+  `order_sn` varchar(64) NOT NULL DEFAULT '' COMMENT '订单号',
+  `plan_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '套餐ID',
+  `period_type` varchar(20) NOT NULL DEFAULT '' COMMENT '订阅周期',
+  `original_amount` decimal(10,2) NOT NULL DEFAULT '0.00' COMMENT '原价(USDT)',
+  `discount_amount` decimal(10,2) NOT NULL DEFAULT '0.00' COMMENT '抵扣金额(USDT)',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '使用时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_coupon_user` (`coupon_id`, `user_id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_agent_id` (`agent_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='Toogo优惠券使用记录';
@@ -0,0 +1,74 @@
+-- ============================================================
+-- 优惠券、代理推广码与套餐免费试用（说明见 MySQL 版本）
+-- PostgreSQL version
+-- ============================================================
+
+ALTER TABLE hg_toogo_plan
+  ADD COLUMN IF NOT EXISTS trial_days INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS trial_robot_limit INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN hg_toogo_plan.trial_days IS '免费试用天数，0为不支持试用';
+COMMENT ON COLUMN hg_toogo_plan.trial_robot_limit IS '试用期机器人数量，0为与套餐相同';
+
+ALTER TABLE hg_toogo_subscription
+  ADD COLUMN IF NOT EXISTS original_amount NUMERIC(10,2) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS discount_amount NUMERIC(10,2) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS coupon_id BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS coupon_code VARCHAR(32) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN hg_toogo_subscription.period_type IS '订阅周期: daily/monthly/quarterly/half_year/yearly/trial';
+COMMENT ON COLUMN hg_toogo_subscription.original_amount IS '订阅原价(USDT)';
+COMMENT ON COLUMN hg_toogo_subscription.discount_amount IS '优惠券抵扣金额(USDT)';
+COMMENT ON COLUMN hg_toogo_subscription.coupon_id IS '优惠券ID';
+COMMENT ON COLUMN hg_toogo_subscription.coupon_code IS '优惠码';
+
+UPDATE hg_toogo_subscription SET original_amount = amount WHERE original_amount = 0;
+
+CREATE TABLE IF NOT EXISTS hg_toogo_coupon (
+  id BIGSERIAL PRIMARY KEY,
+  code VARCHAR(32) NOT NULL,
+  coupon_name VARCHAR(100) NOT NULL DEFAULT '',
+  coupon_type VARCHAR(20) NOT NULL,
+  discount_value NUMERIC(10,2) NOT NULL DEFAULT 0,
+  max_discount NUMERIC(10,2) NOT NULL DEFAULT 0,
+  min_amount NUMERIC(10,2) NOT NULL DEFAULT 0,
+  plan_ids VARCHAR(255) NOT NULL DEFAULT '',
+  period_types VARCHAR(100) NOT NULL DEFAULT '',
+  total_limit INTEGER NOT NULL DEFAULT 0,
+  used_count INTEGER NOT NULL DEFAULT 0,
+  agent_id BIGINT NOT NULL DEFAULT 0,
+  start_time TIMESTAMP NULL,
+  expire_time TIMESTAMP NULL,
+  status SMALLINT NOT NULL DEFAULT 1,
+  remark VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_toogo_coupon_code ON hg_toogo_coupon (code);
+CREATE INDEX IF NOT EXISTS idx_toogo_coupon_agent_id ON hg_toogo_coupon (agent_id);
+
+COMMENT ON TABLE hg_toogo_coupon IS 'Toogo优惠券/推广码';
+COMMENT ON COLUMN hg_toogo_coupon.coupon_type IS '类型: percent=按比例, fixed=固定金额';
+COMMENT ON COLUMN hg_toogo_coupon.discount_value IS '折扣值: percent为百分比, fixed为USDT';
+COMMENT ON COLUMN hg_toogo_coupon.agent_id IS '归属代理ID(推广码)，0为平台券';
+
+CREATE TABLE IF NOT EXISTS hg_toogo_coupon_usage (
+  id BIGSERIAL PRIMARY KEY,
+  coupon_id BIGINT NOT NULL,
+  coupon_code VARCHAR(32) NOT NULL DEFAULT '',
+  user_id BIGINT NOT NULL,
+  agent_id BIGINT NOT NULL DEFAULT 0,
+  attributed SMALLINT NOT NULL DEFAULT 0,
+  subscription_id BIGINT NOT NULL DEFAULT 0,
+  order_sn VARCHAR(64) NOT NULL DEFAULT '',
+  plan_id BIGINT NOT NULL DEFAULT 0,
+  period_type VARCHAR(20) NOT NULL DEFAULT '',
+  original_amount NUMERIC(10,2) NOT NULL DEFAULT 0,
+  discount_amount NUMERIC(10,2) NOT NULL DEFAULT 0,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_toogo_coupon_usage_coupon_user ON hg_toogo_coupon_usage (coupon_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_toogo_coupon_usage_user_id ON hg_toogo_coupon_usage (user_id);
+CREATE INDEX IF NOT EXISTS idx_toogo_coupon_usage_agent_id ON hg_toogo_coupon_usage (agent_id);
+
+COMMENT ON TABLE hg_toogo_coupon_usage IS 'Toogo优惠券使用记录';
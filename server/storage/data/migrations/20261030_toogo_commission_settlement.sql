@@ -0,0 +1,24 @@
+-- ============================================================
+-- 代理佣金结算期与冲正
+-- 说明：
+-- - 订阅佣金先计入 hg_toogo_wallet.frozen_commission（status=1 冻结中），release_time 到期后由定时任务解冻入账（status=2）
+-- - 来源订单退款时原佣金记录 status=3 已冲正，并写入一条负金额冲正记录（reversal_of=原记录ID）；
+--   已解冻入账的部分从佣金余额扣回，允许佣金余额为负，由后续佣金抵扣
+-- - hg_sys_cron.ToogoCommissionRelease: 定期解冻到期佣金
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+ALTER TABLE `hg_toogo_commission_log`
+  MODIFY COLUMN `status` tinyint(2) NOT NULL DEFAULT '2' COMMENT '状态: 1=冻结中(待结算), 2=已结算, 3=已冲正',
+  ADD COLUMN IF NOT EXISTS `release_time` datetime DEFAULT NULL COMMENT '冻结到期时间' AFTER `remark`,
+  ADD COLUMN IF NOT EXISTS `released_at` datetime DEFAULT NULL COMMENT '解冻入账时间' AFTER `release_time`,
+  ADD COLUMN IF NOT EXISTS `reversed_at` datetime DEFAULT NULL COMMENT '冲正时间' AFTER `released_at`,
+  ADD COLUMN IF NOT EXISTS `reversal_of` bigint(20) NOT NULL DEFAULT '0' COMMENT '冲正的原佣金记录ID(冲正记录)' AFTER `reversed_at`,
+  ADD KEY `idx_status_release` (`status`, `release_time`),
+  ADD KEY `idx_order_sn` (`order_sn`);
+
+INSERT INTO `hg_sys_cron` (`group_id`, `title`, `name`, `params`, `pattern`, `policy`, `count`, `sort`, `remark`, `status`, `created_at`, `updated_at`)
+SELECT 10, 'Commission Release', 'ToogoCommissionRelease', '', '@every 10m', 1, 0, 72, 'Release agent commissions after the settlement period', 1, NOW(), NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM `hg_sys_cron` WHERE `name` = 'ToogoCommissionRelease');
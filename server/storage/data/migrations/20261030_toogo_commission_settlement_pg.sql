@@ -0,0 +1,23 @@
+-- ============================================================
+-- 代理佣金结算期与冲正（说明见 MySQL 版本）
+-- PostgreSQL version
+-- ============================================================
+
+ALTER TABLE hg_toogo_commission_log
+  ADD COLUMN IF NOT EXISTS release_time TIMESTAMP NULL,
+  ADD COLUMN IF NOT EXISTS released_at TIMESTAMP NULL,
+  ADD COLUMN IF NOT EXISTS reversed_at TIMESTAMP NULL,
+  ADD COLUMN IF NOT EXISTS reversal_of BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN hg_toogo_commission_log.status IS '状态: 1=冻结中(待结算), 2=已结算, 3=已冲正';
+COMMENT ON COLUMN hg_toogo_commission_log.release_time IS '冻结到期时间';
+COMMENT ON COLUMN hg_toogo_commission_log.released_at IS '解冻入账时间';
+COMMENT ON COLUMN hg_toogo_commission_log.reversed_at IS '冲正时间';
+COMMENT ON COLUMN hg_toogo_commission_log.reversal_of IS '冲正的原佣金记录ID(冲正记录)';
+
+CREATE INDEX IF NOT EXISTS idx_toogo_commission_log_status_release ON hg_toogo_commission_log (status, release_time);
+CREATE INDEX IF NOT EXISTS idx_toogo_commission_log_order_sn ON hg_toogo_commission_log (order_sn);
+
+INSERT INTO hg_sys_cron (group_id, title, name, params, pattern, policy, count, sort, remark, status, created_at, updated_at)
+SELECT 10, 'Commission Release', 'ToogoCommissionRelease', '', '@every 10m', 1, 0, 72, 'Release agent commissions after the settlement period', 1, NOW(), NOW()
+WHERE NOT EXISTS (SELECT 1 FROM hg_sys_cron WHERE name = 'ToogoCommissionRelease');
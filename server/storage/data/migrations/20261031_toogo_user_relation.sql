@@ -0,0 +1,31 @@
+-- ============================================================
+-- 邀请关系闭包表：每个用户与其所有上级（含自身）各一行，depth 为层级距离（0=自身，1=直推）
+-- 说明：
+-- - 注册绑定邀请人、推广码绑定、管理员调整邀请人时在同一事务内维护
+-- - 团队/佣金查询（上级链、各级下级、成员层级、团队人数）均基于此表，不再逐级递归查询
+-- - 按现有 inviter_id 回填（最多回溯64级，循环引用的数据会被截断）
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `hg_toogo_user_relation` (
+  `ancestor_id` bigint(20) NOT NULL COMMENT '上级ID(member_id)，含自身',
+  `descendant_id` bigint(20) NOT NULL COMMENT '下级ID(member_id)',
+  `depth` int(11) NOT NULL DEFAULT '0' COMMENT '层级距离: 0=自身, 1=直推',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`ancestor_id`, `descendant_id`),
+  KEY `idx_descendant_depth` (`descendant_id`, `depth`),
+  KEY `idx_ancestor_depth` (`ancestor_id`, `depth`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='邀请关系闭包表';
+
+INSERT IGNORE INTO `hg_toogo_user_relation` (`ancestor_id`, `descendant_id`, `depth`, `created_at`)
+WITH RECURSIVE `tree` AS (
+  SELECT `member_id` AS `ancestor_id`, `member_id` AS `descendant_id`, 0 AS `depth` FROM `hg_toogo_user`
+  UNION ALL
+  SELECT t.`ancestor_id`, u.`member_id`, t.`depth` + 1
+  FROM `tree` t
+  INNER JOIN `hg_toogo_user` u ON u.`inviter_id` = t.`descendant_id`
+  WHERE t.`depth` < 64
+)
+SELECT `ancestor_id`, `descendant_id`, `depth`, NOW() FROM `tree`;
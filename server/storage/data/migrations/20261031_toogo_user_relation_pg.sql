@@ -0,0 +1,32 @@
+-- ============================================================
+-- 邀请关系闭包表（说明见 MySQL 版本）
+-- PostgreSQL version
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS hg_toogo_user_relation (
+  ancestor_id BIGINT NOT NULL,
+  descendant_id BIGINT NOT NULL,
+  depth INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (ancestor_id, descendant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_toogo_user_relation_descendant_depth ON hg_toogo_user_relation (descendant_id, depth);
+CREATE INDEX IF NOT EXISTS idx_toogo_user_relation_ancestor_depth ON hg_toogo_user_relation (ancestor_id, depth);
+
+COMMENT ON TABLE hg_toogo_user_relation IS '邀请关系闭包表';
+COMMENT ON COLUMN hg_toogo_user_relation.ancestor_id IS '上级ID(member_id)，含自身';
+COMMENT ON COLUMN hg_toogo_user_relation.descendant_id IS '下级ID(member_id)';
+COMMENT ON COLUMN hg_toogo_user_relation.depth IS '层级距离: 0=自身, 1=直推';
+
+WITH RECURSIVE tree AS (
+  SELECT member_id AS ancestor_id, member_id AS descendant_id, 0 AS depth FROM hg_toogo_user
+  UNION ALL
+  SELECT t.ancestor_id, u.member_id, t.depth + 1
+  FROM tree t
+  INNER JOIN hg_toogo_user u ON u.inviter_id = t.descendant_id
+  WHERE t.depth < 64
+)
+INSERT INTO hg_toogo_user_relation (ancestor_id, descendant_id, depth, created_at)
+SELECT ancestor_id, descendant_id, depth, NOW() FROM tree
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,37 @@
+-- ============================================================
+-- VIP等级自动升降级
+-- 说明：
+-- - 定时任务 ToogoVipLevelCheck 按实时指标（直推人数、个人消耗算力、团队消耗算力）重新评估所有用户的VIP等级
+-- - 满足更高等级立即升级；低于当前等级时记录 hg_toogo_user.vip_downgrade_at，
+--   持续不满足超过 toogo.vip.downgradeGraceDays 天后才降级，期间重新达标则清除
+-- - 每次等级变化写入 hg_toogo_vip_level_log（含触发时的指标），同步更新 power_discount 并通知用户
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+ALTER TABLE `hg_toogo_user`
+  ADD COLUMN IF NOT EXISTS `vip_downgrade_at` datetime DEFAULT NULL COMMENT '不满足当前VIP等级的起始时间(降级宽限期起点)' AFTER `power_discount`;
+
+CREATE TABLE IF NOT EXISTS `hg_toogo_vip_level_log` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `user_id` bigint(20) NOT NULL COMMENT '用户ID(member_id)',
+  `from_level` int(11) NOT NULL DEFAULT '0' COMMENT '变更前等级',
+  `to_level` int(11) NOT NULL DEFAULT '0' COMMENT '变更后等级',
+  `change_type` varchar(20) NOT NULL COMMENT '变更类型: upgrade/downgrade',
+  `source` varchar(20) NOT NULL DEFAULT 'cron' COMMENT '触发来源: cron/check/admin',
+  `invite_count` int(11) NOT NULL DEFAULT '0' COMMENT '评估时直推人数',
+  `consume_power` decimal(15,4) NOT NULL DEFAULT '0.0000' COMMENT '评估时个人消耗算力',
+  `team_consume_power` decimal(15,4) NOT NULL DEFAULT '0.0000' COMMENT '评估时团队消耗算力',
+  `from_power_discount` decimal(5,2) NOT NULL DEFAULT '0.00' COMMENT '变更前算力折扣(%)',
+  `to_power_discount` decimal(5,2) NOT NULL DEFAULT '0.00' COMMENT '变更后算力折扣(%)',
+  `remark` varchar(500) NOT NULL DEFAULT '' COMMENT '备注',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='VIP等级变更记录';
+
+INSERT INTO `hg_sys_cron` (`group_id`, `title`, `name`, `params`, `pattern`, `policy`, `count`, `sort`, `remark`, `status`, `created_at`, `updated_at`)
+SELECT 10, 'VIP Check', 'ToogoVipLevelCheck', '', '@every 1h', 1, 0, 50, 'Re-evaluate VIP levels (upgrade immediately, downgrade after grace)', 1, NOW(), NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM `hg_sys_cron` WHERE `name` = 'ToogoVipLevelCheck');
@@ -0,0 +1,35 @@
+-- ============================================================
+-- VIP等级自动升降级（说明见 MySQL 版本）
+-- PostgreSQL version
+-- ============================================================
+
+ALTER TABLE hg_toogo_user
+  ADD COLUMN IF NOT EXISTS vip_downgrade_at TIMESTAMP NULL;
+
+COMMENT ON COLUMN hg_toogo_user.vip_downgrade_at IS '不满足当前VIP等级的起始时间(降级宽限期起点)';
+
+CREATE TABLE IF NOT EXISTS hg_toogo_vip_level_log (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL,
+  from_level INTEGER NOT NULL DEFAULT 0,
+  to_level INTEGER NOT NULL DEFAULT 0,
+  change_type VARCHAR(20) NOT NULL,
+  source VARCHAR(20) NOT NULL DEFAULT 'cron',
+  invite_count INTEGER NOT NULL DEFAULT 0,
+  consume_power NUMERIC(15,4) NOT NULL DEFAULT 0,
+  team_consume_power NUMERIC(15,4) NOT NULL DEFAULT 0,
+  from_power_discount NUMERIC(5,2) NOT NULL DEFAULT 0,
+  to_power_discount NUMERIC(5,2) NOT NULL DEFAULT 0,
+  remark VARCHAR(500) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_toogo_vip_level_log_user_id ON hg_toogo_vip_level_log (user_id);
+CREATE INDEX IF NOT EXISTS idx_toogo_vip_level_log_created_at ON hg_toogo_vip_level_log (created_at);
+
+COMMENT ON TABLE hg_toogo_vip_level_log IS 'VIP等级变更记录';
+COMMENT ON COLUMN hg_toogo_vip_level_log.change_type IS '变更类型: upgrade/downgrade';
+COMMENT ON COLUMN hg_toogo_vip_level_log.source IS '触发来源: cron/check/admin';
+
+INSERT INTO hg_sys_cron (group_id, title, name, params, pattern, policy, count, sort, remark, status, created_at, updated_at)
+SELECT 10, 'VIP Check', 'ToogoVipLevelCheck', '', '@every 1h', 1, 0, 50, 'Re-evaluate VIP levels (upgrade immediately, downgrade after grace)', 1, NOW(), NOW()
+WHERE NOT EXISTS (SELECT 1 FROM hg_sys_cron WHERE name = 'ToogoVipLevelCheck');
@@ -0,0 +1,43 @@
+-- ============================================================
+-- 客服聊天增强：附件、已读回执、离线送达、会话评价、工单升级
+-- 说明：
+-- - hg_support_message 新增附件字段（msg_type 2图片 3文件，content 存附件地址），
+--   read_at 为对方已读时间，offline_notified 标记离线邮件是否已送达
+-- - 定时任务 SupportOfflineNotify 将用户长时间未读且不在线的客服回复汇总后发送邮件
+-- - hg_support_session 新增评价字段，以及升级为工单时关联的机器人/订单
+-- - 「实时对话」菜单补齐附件/已读/工单/执行日志接口（用于 Casbin）
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+ALTER TABLE `hg_support_message`
+  ADD COLUMN IF NOT EXISTS `attachment_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '附件ID(hg_sys_attachment)' AFTER `content`,
+  ADD COLUMN IF NOT EXISTS `file_name` varchar(255) NOT NULL DEFAULT '' COMMENT '附件原始文件名' AFTER `attachment_id`,
+  ADD COLUMN IF NOT EXISTS `file_size` bigint(20) NOT NULL DEFAULT '0' COMMENT '附件大小(字节)' AFTER `file_name`,
+  ADD COLUMN IF NOT EXISTS `read_at` datetime DEFAULT NULL COMMENT '接收方已读时间' AFTER `file_size`,
+  ADD COLUMN IF NOT EXISTS `offline_notified` tinyint(1) NOT NULL DEFAULT '0' COMMENT '离线邮件是否已送达' AFTER `read_at`;
+
+ALTER TABLE `hg_support_message`
+  ADD KEY `idx_support_message_unread` (`session_id`, `sender_role`, `read_at`);
+
+ALTER TABLE `hg_support_session`
+  ADD COLUMN IF NOT EXISTS `rating` tinyint(1) NOT NULL DEFAULT '0' COMMENT '用户评分：1-5，0未评价' AFTER `unread_agent`,
+  ADD COLUMN IF NOT EXISTS `rating_remark` varchar(500) NOT NULL DEFAULT '' COMMENT '评价内容' AFTER `rating`,
+  ADD COLUMN IF NOT EXISTS `rated_at` datetime DEFAULT NULL COMMENT '评价时间' AFTER `rating_remark`,
+  ADD COLUMN IF NOT EXISTS `robot_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '工单关联机器人ID' AFTER `rated_at`,
+  ADD COLUMN IF NOT EXISTS `order_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '工单关联订单ID' AFTER `robot_id`,
+  ADD COLUMN IF NOT EXISTS `escalate_remark` varchar(500) NOT NULL DEFAULT '' COMMENT '工单说明' AFTER `order_id`,
+  ADD COLUMN IF NOT EXISTS `escalated_by` bigint(20) NOT NULL DEFAULT '0' COMMENT '升级为工单的客服ID' AFTER `escalate_remark`,
+  ADD COLUMN IF NOT EXISTS `escalated_at` datetime DEFAULT NULL COMMENT '升级为工单时间' AFTER `escalated_by`;
+
+INSERT INTO `hg_sys_cron` (`group_id`, `title`, `name`, `params`, `pattern`, `policy`, `count`, `sort`, `remark`, `status`, `created_at`, `updated_at`)
+SELECT 10, 'Support Offline Notify', 'SupportOfflineNotify', '', '@every 1m', 1, 0, 50, 'Email unread support replies to offline users', 1, NOW(), NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM `hg_sys_cron` WHERE `name` = 'SupportOfflineNotify');
+
+UPDATE `hg_admin_menu`
+SET
+  `permissions` = CONCAT(`permissions`, ',/supportChat/sendAttachment,/supportChat/read,/supportChat/escalate,/supportChat/executionLogList'),
+  `updated_at` = NOW()
+WHERE `name` = 'trading_support_realtime_chat'
+  AND `permissions` NOT LIKE '%/supportChat/sendAttachment%';
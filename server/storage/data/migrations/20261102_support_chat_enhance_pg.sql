@@ -0,0 +1,44 @@
+-- ============================================================
+-- 客服聊天增强：附件、已读回执、离线送达、会话评价、工单升级（说明见 MySQL 版本）
+-- PostgreSQL version
+-- ============================================================
+
+ALTER TABLE hg_support_message
+  ADD COLUMN IF NOT EXISTS attachment_id BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS file_name VARCHAR(255) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS file_size BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS read_at TIMESTAMP NULL,
+  ADD COLUMN IF NOT EXISTS offline_notified SMALLINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN hg_support_message.msg_type IS '消息类型：1文本 2图片 3文件';
+COMMENT ON COLUMN hg_support_message.attachment_id IS '附件ID(hg_sys_attachment)';
+COMMENT ON COLUMN hg_support_message.read_at IS '接收方已读时间';
+COMMENT ON COLUMN hg_support_message.offline_notified IS '离线邮件是否已送达';
+
+CREATE INDEX IF NOT EXISTS idx_support_message_unread ON hg_support_message (session_id, sender_role, read_at);
+
+ALTER TABLE hg_support_session
+  ADD COLUMN IF NOT EXISTS rating SMALLINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS rating_remark VARCHAR(500) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS rated_at TIMESTAMP NULL,
+  ADD COLUMN IF NOT EXISTS robot_id BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS order_id BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS escalate_remark VARCHAR(500) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS escalated_by BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP NULL;
+
+COMMENT ON COLUMN hg_support_session.rating IS '用户评分：1-5，0未评价';
+COMMENT ON COLUMN hg_support_session.robot_id IS '工单关联机器人ID';
+COMMENT ON COLUMN hg_support_session.order_id IS '工单关联订单ID';
+COMMENT ON COLUMN hg_support_session.escalated_at IS '升级为工单时间';
+
+INSERT INTO hg_sys_cron (group_id, title, name, params, pattern, policy, count, sort, remark, status, created_at, updated_at)
+SELECT 10, 'Support Offline Notify', 'SupportOfflineNotify', '', '@every 1m', 1, 0, 50, 'Email unread support replies to offline users', 1, NOW(), NOW()
+WHERE NOT EXISTS (SELECT 1 FROM hg_sys_cron WHERE name = 'SupportOfflineNotify');
+
+UPDATE hg_admin_menu
+SET
+  permissions = permissions || ',/supportChat/sendAttachment,/supportChat/read,/supportChat/escalate,/supportChat/executionLogList',
+  updated_at = NOW()
+WHERE name = 'trading_support_realtime_chat'
+  AND permissions NOT LIKE '%/supportChat/sendAttachment%';
@@ -0,0 +1,49 @@
+-- ============================================================
+-- 客服工作台：会话用户上下文与运维操作权限
+-- 说明：
+-- - 「实时对话」菜单补齐用户上下文接口（用于 Casbin）
+-- - 运维操作单独作为按钮权限，按角色授权：
+--   /supportChat/robot/restart     重建会话用户的机器人引擎
+--   /supportChat/robot/syncOrders  重新同步会话用户的机器人订单/持仓
+-- - 操作结果写入 hg_sys_sensitive_log（operation = support_action）
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+SET @support_chat_menu_id = (SELECT id FROM `hg_admin_menu` WHERE `name` = 'trading_support_realtime_chat' ORDER BY id DESC LIMIT 1);
+
+UPDATE `hg_admin_menu`
+SET
+  `permissions` = CONCAT(`permissions`, ',/supportChat/context'),
+  `updated_at` = NOW()
+WHERE `id` = @support_chat_menu_id
+  AND `permissions` NOT LIKE '%/supportChat/context%';
+
+INSERT INTO `hg_admin_menu` (
+  `pid`, `level`, `tree`, `title`, `name`, `path`, `icon`, `type`, `redirect`, `permissions`,
+  `permission_name`, `component`, `always_show`, `active_menu`, `is_root`,
+  `is_frame`, `frame_src`, `keep_alive`, `hidden`, `affix`, `sort`,
+  `remark`, `status`, `created_at`, `updated_at`
+)
+SELECT
+  p.`id`, p.`level` + 1, CONCAT(p.`tree`, 'tr_', p.`id`, ' '), '重建机器人引擎', 'trading_support_robot_restart', '', '', 3, '',
+  '/supportChat/robot/restart', '重建机器人引擎', '', 0, '', 0, 0, '', 0, 0, 0, 10,
+  '客服工作台-重建会话用户的机器人引擎', 1, NOW(), NOW()
+FROM `hg_admin_menu` p
+WHERE p.`id` = @support_chat_menu_id
+  AND NOT EXISTS (SELECT 1 FROM `hg_admin_menu` WHERE `name` = 'trading_support_robot_restart');
+
+INSERT INTO `hg_admin_menu` (
+  `pid`, `level`, `tree`, `title`, `name`, `path`, `icon`, `type`, `redirect`, `permissions`,
+  `permission_name`, `component`, `always_show`, `active_menu`, `is_root`,
+  `is_frame`, `frame_src`, `keep_alive`, `hidden`, `affix`, `sort`,
+  `remark`, `status`, `created_at`, `updated_at`
+)
+SELECT
+  p.`id`, p.`level` + 1, CONCAT(p.`tree`, 'tr_', p.`id`, ' '), '重新同步订单', 'trading_support_robot_sync_orders', '', '', 3, '',
+  '/supportChat/robot/syncOrders', '重新同步订单', '', 0, '', 0, 0, '', 0, 0, 0, 20,
+  '客服工作台-重新同步会话用户的机器人订单/持仓', 1, NOW(), NOW()
+FROM `hg_admin_menu` p
+WHERE p.`id` = @support_chat_menu_id
+  AND NOT EXISTS (SELECT 1 FROM `hg_admin_menu` WHERE `name` = 'trading_support_robot_sync_orders');
@@ -0,0 +1,43 @@
+-- ============================================================
+-- 客服工作台：会话用户上下文与运维操作权限（说明见 MySQL 版本）
+-- PostgreSQL version
+-- ============================================================
+
+UPDATE hg_admin_menu
+SET
+  permissions = permissions || ',/supportChat/context',
+  updated_at = NOW()
+WHERE name = 'trading_support_realtime_chat'
+  AND permissions NOT LIKE '%/supportChat/context%';
+
+INSERT INTO hg_admin_menu (
+  pid, level, tree, title, name, path, icon, type, redirect, permissions,
+  permission_name, component, always_show, active_menu, is_root,
+  is_frame, frame_src, keep_alive, hidden, affix, sort,
+  remark, status, created_at, updated_at
+)
+SELECT
+  p.id, p.level + 1, COALESCE(p.tree, '') || 'tr_' || p.id::TEXT || ' ', '重建机器人引擎', 'trading_support_robot_restart', '', '', 3, '',
+  '/supportChat/robot/restart', '重建机器人引擎', '', 0, '', 0, 0, '', 0, 0, 0, 10,
+  '客服工作台-重建会话用户的机器人引擎', 1, NOW(), NOW()
+FROM hg_admin_menu p
+WHERE p.name = 'trading_support_realtime_chat'
+  AND NOT EXISTS (SELECT 1 FROM hg_admin_menu WHERE name = 'trading_support_robot_restart')
+ORDER BY p.id DESC
+LIMIT 1;
+
+INSERT INTO hg_admin_menu (
+  pid, level, tree, title, name, path, icon, type, redirect, permissions,
+  permission_name, component, always_show, active_menu, is_root,
+  is_frame, frame_src, keep_alive, hidden, affix, sort,
+  remark, status, created_at, updated_at
+)
+SELECT
+  p.id, p.level + 1, COALESCE(p.tree, '') || 'tr_' || p.id::TEXT || ' ', '重新同步订单', 'trading_support_robot_sync_orders', '', '', 3, '',
+  '/supportChat/robot/syncOrders', '重新同步订单', '', 0, '', 0, 0, '', 0, 0, 0, 20,
+  '客服工作台-重新同步会话用户的机器人订单/持仓', 1, NOW(), NOW()
+FROM hg_admin_menu p
+WHERE p.name = 'trading_support_realtime_chat'
+  AND NOT EXISTS (SELECT 1 FROM hg_admin_menu WHERE name = 'trading_support_robot_sync_orders')
+ORDER BY p.id DESC
+LIMIT 1;
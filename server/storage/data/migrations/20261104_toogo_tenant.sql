@@ -0,0 +1,108 @@
+-- ============================================================
+-- 白标租户（多租户）隔离
+-- 说明：
+-- - hg_toogo_tenant 保存白标租户：租户账号（部门类型=tenant 的后台账号，member_id 即 tenant_id）、
+--   绑定域名、品牌配置、独立的 NOWPayments 收款凭证（经保险箱信封加密，与 hg_trading_api_config 相同，
+--   主密钥轮换后执行 `tools -m=vault -a1=rewrap` 一并迁移）
+-- - 以下表增加 tenant_id（0=平台），由 DAO 层租户处理器按登录用户所属租户隔离：
+--   hg_toogo_user         用户所属租户（注册时按邀请人/访问域名确定）
+--   hg_toogo_plan         租户独立套餐
+--   hg_toogo_config       租户覆盖配置（佣金/邀请/提现分组），未覆盖时使用平台配置
+--   hg_toogo_agent_level  租户独立代理等级
+--   hg_toogo_coupon       租户独立优惠券/推广码，只能用于本租户套餐
+--   hg_trading_strategy_group 租户独立官方策略模板
+-- - 上述表的唯一键改为租户内唯一
+-- - VIP等级、波动率配置、全局代理配置仍由平台统一管理
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `hg_toogo_tenant` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `member_id` bigint(20) NOT NULL COMMENT '租户账号ID(即租户ID)',
+  `name` varchar(100) NOT NULL DEFAULT '' COMMENT '租户名称',
+  `domain` varchar(255) NOT NULL DEFAULT '' COMMENT '绑定域名',
+  `site_name` varchar(100) NOT NULL DEFAULT '' COMMENT '站点名称',
+  `logo` varchar(500) NOT NULL DEFAULT '' COMMENT 'Logo',
+  `favicon` varchar(500) NOT NULL DEFAULT '' COMMENT '站点图标',
+  `primary_color` varchar(20) NOT NULL DEFAULT '' COMMENT '主题色',
+  `support_email` varchar(100) NOT NULL DEFAULT '' COMMENT '客服邮箱',
+  `nowpayments_api_key` varchar(500) NOT NULL DEFAULT '' COMMENT 'NOWPayments API Key（密文）',
+  `nowpayments_ipn_secret` varchar(500) NOT NULL DEFAULT '' COMMENT 'NOWPayments IPN密钥（密文）',
+  `nowpayments_data_key` varchar(255) NOT NULL DEFAULT '' COMMENT 'NOWPayments凭证数据密钥（主密钥包装）',
+  `nowpayments_key_version` int NOT NULL DEFAULT '0' COMMENT 'NOWPayments凭证主密钥版本',
+  `nowpayments_sandbox` tinyint(1) NOT NULL DEFAULT '0' COMMENT 'NOWPayments沙箱模式',
+  `remark` varchar(500) NOT NULL DEFAULT '' COMMENT '备注',
+  `status` tinyint(1) NOT NULL DEFAULT '1' COMMENT '状态：1=正常,2=禁用',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_member_id` (`member_id`),
+  KEY `idx_domain` (`domain`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='白标租户';
+
+ALTER TABLE `hg_toogo_user`
+  ADD COLUMN IF NOT EXISTS `tenant_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '租户ID，0=平台' AFTER `id`,
+  ADD INDEX IF NOT EXISTS `idx_tenant_id` (`tenant_id`);
+
+ALTER TABLE `hg_toogo_plan`
+  ADD COLUMN IF NOT EXISTS `tenant_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '租户ID，0=平台' AFTER `id`,
+  DROP INDEX IF EXISTS `uk_plan_code`,
+  ADD UNIQUE KEY IF NOT EXISTS `uk_tenant_plan_code` (`tenant_id`, `plan_code`);
+
+ALTER TABLE `hg_toogo_config`
+  ADD COLUMN IF NOT EXISTS `tenant_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '租户ID，0=平台' AFTER `id`,
+  DROP INDEX IF EXISTS `uk_group_key`,
+  ADD UNIQUE KEY IF NOT EXISTS `uk_tenant_group_key` (`tenant_id`, `group`, `key`);
+
+ALTER TABLE `hg_toogo_agent_level`
+  ADD COLUMN IF NOT EXISTS `tenant_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '租户ID，0=平台' AFTER `id`,
+  DROP INDEX IF EXISTS `uk_level`,
+  ADD UNIQUE KEY IF NOT EXISTS `uk_tenant_level` (`tenant_id`, `level`);
+
+ALTER TABLE `hg_toogo_coupon`
+  ADD COLUMN IF NOT EXISTS `tenant_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '租户ID，0=平台' AFTER `id`,
+  DROP INDEX IF EXISTS `uk_code`,
+  ADD UNIQUE KEY IF NOT EXISTS `uk_tenant_code` (`tenant_id`, `code`);
+
+ALTER TABLE `hg_trading_strategy_group`
+  ADD COLUMN IF NOT EXISTS `tenant_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '租户ID，0=平台' AFTER `id`,
+  DROP INDEX IF EXISTS `uk_group_key`,
+  ADD UNIQUE KEY IF NOT EXISTS `uk_tenant_group_key` (`tenant_id`, `group_key`);
+
+-- 佣金结算期改为平台配置项，租户可覆盖（未配置时使用 toogo.commission.holdDays）
+INSERT INTO `hg_toogo_config` (`tenant_id`, `group`, `key`, `value`, `type`, `name`, `description`, `sort`) VALUES
+(0, 'commission', 'hold_days', '7', 'number', '佣金结算期', '订阅佣金冻结天数，0=立即入账', 5)
+ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `description` = VALUES(`description`);
+
+-- 后台菜单：白标租户管理（超管）、品牌设置（租户账号）
+SET @toogo_admin_menu_id = (SELECT `pid` FROM `hg_admin_menu` WHERE `component` = '/toogo/admin/config/index' ORDER BY id LIMIT 1);
+
+INSERT INTO `hg_admin_menu` (
+  `pid`, `level`, `tree`, `title`, `name`, `path`, `icon`, `type`, `redirect`, `permissions`,
+  `permission_name`, `component`, `always_show`, `active_menu`, `is_root`,
+  `is_frame`, `frame_src`, `keep_alive`, `hidden`, `affix`, `sort`,
+  `remark`, `status`, `created_at`, `updated_at`
+)
+SELECT
+  p.`id`, p.`level` + 1, CONCAT(p.`tree`, 'tr_', p.`id`, ' '), '白标租户', 'toogo_admin_tenant', 'tenant', '', 2, '',
+  '/toogo/tenant/list,/toogo/tenant/view,/toogo/tenant/edit,/toogo/tenant/status,/toogo/tenant/copyCatalog', '白标租户', '/toogo/admin/tenant/index', 0, '', 0, 0, '', 0, 0, 0, 90,
+  '白标租户管理与跨租户统计', 1, NOW(), NOW()
+FROM `hg_admin_menu` p
+WHERE p.`id` = @toogo_admin_menu_id
+  AND NOT EXISTS (SELECT 1 FROM `hg_admin_menu` WHERE `name` = 'toogo_admin_tenant');
+
+INSERT INTO `hg_admin_menu` (
+  `pid`, `level`, `tree`, `title`, `name`, `path`, `icon`, `type`, `redirect`, `permissions`,
+  `permission_name`, `component`, `always_show`, `active_menu`, `is_root`,
+  `is_frame`, `frame_src`, `keep_alive`, `hidden`, `affix`, `sort`,
+  `remark`, `status`, `created_at`, `updated_at`
+)
+SELECT
+  p.`id`, p.`level` + 1, CONCAT(p.`tree`, 'tr_', p.`id`, ' '), '品牌设置', 'toogo_admin_tenant_branding', 'tenant-branding', '', 2, '',
+  '/toogo/tenant/branding,/toogo/tenant/branding/edit', '品牌设置', '/toogo/admin/tenant/branding', 0, '', 0, 0, '', 0, 0, 0, 91,
+  '租户账号维护自己的品牌配置与收款凭证', 1, NOW(), NOW()
+FROM `hg_admin_menu` p
+WHERE p.`id` = @toogo_admin_menu_id
+  AND NOT EXISTS (SELECT 1 FROM `hg_admin_menu` WHERE `name` = 'toogo_admin_tenant_branding');
@@ -0,0 +1,128 @@
+-- ============================================================
+-- 白标租户（多租户）隔离（说明见 MySQL 版本）
+-- PostgreSQL version
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS hg_toogo_tenant (
+  id BIGSERIAL PRIMARY KEY,
+  member_id BIGINT NOT NULL,
+  name VARCHAR(100) NOT NULL DEFAULT '',
+  domain VARCHAR(255) NOT NULL DEFAULT '',
+  site_name VARCHAR(100) NOT NULL DEFAULT '',
+  logo VARCHAR(500) NOT NULL DEFAULT '',
+  favicon VARCHAR(500) NOT NULL DEFAULT '',
+  primary_color VARCHAR(20) NOT NULL DEFAULT '',
+  support_email VARCHAR(100) NOT NULL DEFAULT '',
+  nowpayments_api_key VARCHAR(500) NOT NULL DEFAULT '',
+  nowpayments_ipn_secret VARCHAR(500) NOT NULL DEFAULT '',
+  nowpayments_data_key VARCHAR(255) NOT NULL DEFAULT '',
+  nowpayments_key_version INT NOT NULL DEFAULT 0,
+  nowpayments_sandbox SMALLINT NOT NULL DEFAULT 0,
+  remark VARCHAR(500) NOT NULL DEFAULT '',
+  status SMALLINT NOT NULL DEFAULT 1,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_toogo_tenant_member_id ON hg_toogo_tenant (member_id);
+CREATE INDEX IF NOT EXISTS idx_toogo_tenant_domain ON hg_toogo_tenant (domain);
+
+COMMENT ON TABLE hg_toogo_tenant IS '白标租户';
+COMMENT ON COLUMN hg_toogo_tenant.member_id IS '租户账号ID(即租户ID)';
+COMMENT ON COLUMN hg_toogo_tenant.domain IS '绑定域名';
+COMMENT ON COLUMN hg_toogo_tenant.nowpayments_api_key IS 'NOWPayments API Key（密文）';
+COMMENT ON COLUMN hg_toogo_tenant.nowpayments_ipn_secret IS 'NOWPayments IPN密钥（密文）';
+COMMENT ON COLUMN hg_toogo_tenant.nowpayments_data_key IS 'NOWPayments凭证数据密钥（主密钥包装）';
+COMMENT ON COLUMN hg_toogo_tenant.nowpayments_key_version IS 'NOWPayments凭证主密钥版本';
+COMMENT ON COLUMN hg_toogo_tenant.status IS '状态：1=正常,2=禁用';
+
+ALTER TABLE hg_toogo_user ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE hg_toogo_plan ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE hg_toogo_config ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE hg_toogo_agent_level ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE hg_toogo_coupon ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE hg_trading_strategy_group ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN hg_toogo_user.tenant_id IS '租户ID，0=平台';
+COMMENT ON COLUMN hg_toogo_plan.tenant_id IS '租户ID，0=平台';
+COMMENT ON COLUMN hg_toogo_config.tenant_id IS '租户ID，0=平台';
+COMMENT ON COLUMN hg_toogo_agent_level.tenant_id IS '租户ID，0=平台';
+COMMENT ON COLUMN hg_toogo_coupon.tenant_id IS '租户ID，0=平台';
+COMMENT ON COLUMN hg_trading_strategy_group.tenant_id IS '租户ID，0=平台';
+
+CREATE INDEX IF NOT EXISTS idx_toogo_user_tenant_id ON hg_toogo_user (tenant_id);
+
+-- 旧库的唯一约束/索引名称不统一，按表删除不含 tenant_id 的唯一约束后重建为租户内唯一
+DO $$
+DECLARE
+  r RECORD;
+BEGIN
+  FOR r IN
+    SELECT t.relname AS table_name, c.conname AS constraint_name
+    FROM pg_constraint c
+    JOIN pg_class t ON t.oid = c.conrelid
+    WHERE c.contype = 'u'
+      AND t.relname IN ('hg_toogo_plan', 'hg_toogo_config', 'hg_toogo_agent_level', 'hg_toogo_coupon', 'hg_trading_strategy_group')
+      AND NOT EXISTS (
+        SELECT 1 FROM pg_attribute a
+        WHERE a.attrelid = t.oid AND a.attname = 'tenant_id' AND a.attnum = ANY (c.conkey)
+      )
+  LOOP
+    EXECUTE format('ALTER TABLE %I DROP CONSTRAINT %I', r.table_name, r.constraint_name);
+  END LOOP;
+
+  FOR r IN
+    SELECT i.relname AS index_name
+    FROM pg_index x
+    JOIN pg_class i ON i.oid = x.indexrelid
+    JOIN pg_class t ON t.oid = x.indrelid
+    WHERE x.indisunique
+      AND NOT x.indisprimary
+      AND t.relname IN ('hg_toogo_plan', 'hg_toogo_config', 'hg_toogo_agent_level', 'hg_toogo_coupon', 'hg_trading_strategy_group')
+      AND NOT EXISTS (
+        SELECT 1 FROM pg_attribute a
+        WHERE a.attrelid = t.oid AND a.attname = 'tenant_id' AND a.attnum = ANY (x.indkey::SMALLINT[])
+      )
+  LOOP
+    EXECUTE format('DROP INDEX IF EXISTS %I', r.index_name);
+  END LOOP;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_toogo_plan_tenant_plan_code ON hg_toogo_plan (tenant_id, plan_code);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_toogo_config_tenant_group_key ON hg_toogo_config (tenant_id, "group", "key");
+CREATE UNIQUE INDEX IF NOT EXISTS uk_toogo_agent_level_tenant_level ON hg_toogo_agent_level (tenant_id, level);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_toogo_coupon_tenant_code ON hg_toogo_coupon (tenant_id, code);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_trading_strategy_group_tenant_key ON hg_trading_strategy_group (tenant_id, group_key);
+
+-- 佣金结算期改为平台配置项，租户可覆盖（未配置时使用 toogo.commission.holdDays）
+INSERT INTO hg_toogo_config (tenant_id, "group", "key", value, type, name, description, sort) VALUES
+(0, 'commission', 'hold_days', '7', 'number', '佣金结算期', '订阅佣金冻结天数，0=立即入账', 5)
+ON CONFLICT (tenant_id, "group", "key") DO NOTHING;
+
+-- 后台菜单：白标租户管理（超管）、品牌设置（租户账号）
+INSERT INTO hg_admin_menu (
+  pid, level, tree, title, name, path, icon, type, redirect, permissions,
+  permission_name, component, always_show, active_menu, is_root,
+  is_frame, frame_src, keep_alive, hidden, affix, sort,
+  remark, status, created_at, updated_at
+)
+SELECT
+  p.id, p.level + 1, COALESCE(p.tree, '') || 'tr_' || p.id::TEXT || ' ', '白标租户', 'toogo_admin_tenant', 'tenant', '', 2, '',
+  '/toogo/tenant/list,/toogo/tenant/view,/toogo/tenant/edit,/toogo/tenant/status,/toogo/tenant/copyCatalog', '白标租户', '/toogo/admin/tenant/index', 0, '', 0, 0, '', 0, 0, 0, 90,
+  '白标租户管理与跨租户统计', 1, NOW(), NOW()
+FROM hg_admin_menu p
+WHERE p.id = (SELECT pid FROM hg_admin_menu WHERE component = '/toogo/admin/config/index' ORDER BY id LIMIT 1)
+  AND NOT EXISTS (SELECT 1 FROM hg_admin_menu WHERE name = 'toogo_admin_tenant');
+
+INSERT INTO hg_admin_menu (
+  pid, level, tree, title, name, path, icon, type, redirect, permissions,
+  permission_name, component, always_show, active_menu, is_root,
+  is_frame, frame_src, keep_alive, hidden, affix, sort,
+  remark, status, created_at, updated_at
+)
+SELECT
+  p.id, p.level + 1, COALESCE(p.tree, '') || 'tr_' || p.id::TEXT || ' ', '品牌设置', 'toogo_admin_tenant_branding', 'tenant-branding', '', 2, '',
+  '/toogo/tenant/branding,/toogo/tenant/branding/edit', '品牌设置', '/toogo/admin/tenant/branding', 0, '', 0, 0, '', 0, 0, 0, 91,
+  '租户账号维护自己的品牌配置与收款凭证', 1, NOW(), NOW()
+FROM hg_admin_menu p
+WHERE p.id = (SELECT pid FROM hg_admin_menu WHERE component = '/toogo/admin/config/index' ORDER BY id LIMIT 1)
+  AND NOT EXISTS (SELECT 1 FROM hg_admin_menu WHERE name = 'toogo_admin_tenant_branding');
@@ -0,0 +1,14 @@
+-- ============================================================
+-- 网格格子部分成交跟踪
+-- 说明：
+-- - filled_qty / filled_price: 本轮（开仓或平仓）已失效挂单累计成交的数量与均价，补挂时只挂剩余数量
+-- - order_filled_qty: 当前挂单已成交数量（PARTIALLY_FILLED）
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+ALTER TABLE `hg_trading_grid_cell`
+  ADD COLUMN IF NOT EXISTS `filled_qty` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '本轮已失效挂单累计成交数量' AFTER `order_price`,
+  ADD COLUMN IF NOT EXISTS `filled_price` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '本轮已失效挂单成交均价' AFTER `filled_qty`,
+  ADD COLUMN IF NOT EXISTS `order_filled_qty` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '当前挂单已成交数量' AFTER `filled_price`;
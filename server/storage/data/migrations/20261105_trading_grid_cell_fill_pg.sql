@@ -0,0 +1,12 @@
+-- ============================================================
+-- 网格格子部分成交跟踪（说明见 MySQL 版本）
+-- PostgreSQL version
+-- ============================================================
+
+ALTER TABLE hg_trading_grid_cell ADD COLUMN IF NOT EXISTS filled_qty NUMERIC(20,8) NOT NULL DEFAULT 0;
+ALTER TABLE hg_trading_grid_cell ADD COLUMN IF NOT EXISTS filled_price NUMERIC(20,8) NOT NULL DEFAULT 0;
+ALTER TABLE hg_trading_grid_cell ADD COLUMN IF NOT EXISTS order_filled_qty NUMERIC(20,8) NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN hg_trading_grid_cell.filled_qty IS '本轮已失效挂单累计成交数量';
+COMMENT ON COLUMN hg_trading_grid_cell.filled_price IS '本轮已失效挂单成交均价';
+COMMENT ON COLUMN hg_trading_grid_cell.order_filled_qty IS '当前挂单已成交数量';
@@ -0,0 +1,19 @@
+-- ============================================================
+-- 佣金实际解冻金额
+-- 说明：
+-- - 部分冲正后解冻只转入剩余部分，released_amount 记录实际解冻入账金额，代理对账单按此统计本月解冻
+-- - 已解冻的历史记录按解冻前已产生的冲正记录回填
+-- MySQL version
+-- ============================================================
+
+SET NAMES utf8mb4;
+
+ALTER TABLE `hg_toogo_commission_log`
+  ADD COLUMN IF NOT EXISTS `released_amount` decimal(20,8) NOT NULL DEFAULT '0.00000000' COMMENT '实际解冻入账金额(扣除解冻前已冲正部分)' AFTER `released_at`;
+
+UPDATE `hg_toogo_commission_log` l
+SET l.`released_amount` = GREATEST(l.`commission_amount` + COALESCE((
+    SELECT SUM(r.`commission_amount`) FROM (SELECT `reversal_of`, `commission_amount`, `created_at` FROM `hg_toogo_commission_log` WHERE `reversal_of` > 0) r
+    WHERE r.`reversal_of` = l.`id` AND r.`created_at` <= l.`released_at`
+  ), 0), 0)
+WHERE l.`released_at` IS NOT NULL AND l.`reversal_of` = 0;
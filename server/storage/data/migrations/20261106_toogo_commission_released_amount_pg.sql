@@ -0,0 +1,16 @@
+-- ============================================================
+-- 佣金实际解冻金额（说明见 MySQL 版本）
+-- PostgreSQL version
+-- ============================================================
+
+ALTER TABLE hg_toogo_commission_log
+  ADD COLUMN IF NOT EXISTS released_amount DECIMAL(20,8) NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN hg_toogo_commission_log.released_amount IS '实际解冻入账金额(扣除解冻前已冲正部分)';
+
+UPDATE hg_toogo_commission_log l
+SET released_amount = GREATEST(l.commission_amount + COALESCE((
+    SELECT SUM(r.commission_amount) FROM hg_toogo_commission_log r
+    WHERE r.reversal_of = l.id AND r.created_at <= l.released_at
+  ), 0), 0)
+WHERE l.released_at IS NOT NULL AND l.reversal_of = 0;
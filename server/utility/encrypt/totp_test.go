@@ -0,0 +1,67 @@
+// Package encrypt
+// @Description TOTP 测试
+package encrypt
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录B SHA1 测试向量（取后6位）
+func TestTotpCodeRFC6238(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tc := range testCases {
+		code, err := TotpCode(secret, TotpStep(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatalf("TotpCode(%d) error: %v", tc.unix, err)
+		}
+		if code != tc.code {
+			t.Errorf("TotpCode(%d) = %s, want %s", tc.unix, code, tc.code)
+		}
+	}
+}
+
+func TestVerifyTotp(t *testing.T) {
+	secret, err := GenerateTotpSecret()
+	if err != nil {
+		t.Fatalf("GenerateTotpSecret error: %v", err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	prev, _ := TotpCode(secret, TotpStep(now)-1)
+	step, ok := VerifyTotp(secret, prev, now, 1)
+	if !ok || step != TotpStep(now)-1 {
+		t.Fatalf("previous step should be accepted, ok=%v step=%d", ok, step)
+	}
+
+	old, _ := TotpCode(secret, TotpStep(now)-3)
+	if _, ok = VerifyTotp(secret, old, now, 1); ok {
+		t.Fatal("code outside skew window should be rejected")
+	}
+
+	if _, ok = VerifyTotp(secret, "12345", now, 1); ok {
+		t.Fatal("short code should be rejected")
+	}
+}
+
+func TestTotpURI(t *testing.T) {
+	uri := TotpURI("Toogo", "admin", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(uri, "otpauth://totp/Toogo:admin?") || !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") {
+		t.Fatalf("unexpected uri: %s", uri)
+	}
+}
@@ -7,6 +7,7 @@ package excel
 
 import (
 	"context"
+	"encoding/csv"
 	"fmt"
 	"github.com/gogf/gf/v2/errors/gcode"
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -148,3 +149,34 @@ func NextLetter(input string) string {
 	}
 	return "A"
 }
+
+// ExportCSV 导出CSV文件（带UTF-8 BOM，Excel可直接打开中文）
+func ExportCSV(ctx context.Context, rows [][]string, fileName string) (err error) {
+	r := ghttp.RequestFromCtx(ctx)
+	if r == nil {
+		err = gerror.New("ctx not http request")
+		return
+	}
+
+	writer := r.Response.Writer
+	writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.csv", url.QueryEscape(fileName)))
+	writer.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
+
+	if _, err = writer.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return
+	}
+	w := csv.NewWriter(writer)
+	if err = w.WriteAll(rows); err != nil {
+		return
+	}
+
+	// 加入到上下文
+	contexts.SetResponse(ctx, &model.Response{
+		Code:      gcode.CodeOK.Code(),
+		Message:   "export successfully!",
+		Timestamp: time.Now().Unix(),
+		TraceID:   gctx.CtxId(ctx),
+	})
+	return
+}
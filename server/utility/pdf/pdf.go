@@ -0,0 +1,159 @@
+// Package pdf
+// @Link  https://github.com/bufanyun/hotgo
+// @Copyright  Copyright (c) 2024 Toogo.Ai
+// @Author  Toogo Team
+// @Description 简易文本PDF：A4纵向、内置Helvetica字体自动分页，用于对账单等纯文本报表下载
+// 内置字体只支持 WinAnsi 字符，其余字符以 ? 代替，报表内容请使用英文/数字
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hotgo/internal/library/contexts"
+	"hotgo/internal/model"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/os/gctx"
+)
+
+const (
+	pageWidth    = 595 // A4 宽(pt)
+	pageHeight   = 842 // A4 高(pt)
+	margin       = 40
+	fontSize     = 9
+	leading      = 12
+	maxLineChars = 110
+	linesPerPage = (pageHeight - 2*margin) / leading
+)
+
+// Document 纯文本PDF文档
+type Document struct {
+	lines []string
+}
+
+// New 创建文档
+func New() *Document {
+	return &Document{}
+}
+
+// Line 追加一行文本
+func (d *Document) Line(format string, args ...interface{}) {
+	text := format
+	if len(args) > 0 {
+		text = fmt.Sprintf(format, args...)
+	}
+	d.lines = append(d.lines, text)
+}
+
+// Bytes 生成PDF内容
+func (d *Document) Bytes() []byte {
+	var pages [][]string
+	for start := 0; start < len(d.lines) || start == 0; start += linesPerPage {
+		end := start + linesPerPage
+		if end > len(d.lines) {
+			end = len(d.lines)
+		}
+		pages = append(pages, d.lines[start:end])
+	}
+
+	// 对象编号：1=Catalog, 2=Pages, 3=Font, 之后每页依次为 Page、Contents
+	var (
+		buf     bytes.Buffer
+		offsets []int
+		kids    []string
+	)
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 4+i*2))
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	for i, lines := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 5+i*2))
+		stream := pageStream(lines)
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+// pageStream 单页文本绘制指令
+func pageStream(lines []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, leading, margin, pageHeight-margin-fontSize)
+	for _, line := range lines {
+		fmt.Fprintf(&sb, "(%s) Tj T*\n", escape(line))
+	}
+	sb.WriteString("ET")
+	return sb.String()
+}
+
+// escape 转义PDF字符串，非WinAnsi字符替换为 ?
+func escape(text string) string {
+	var sb strings.Builder
+	count := 0
+	for _, r := range text {
+		if count >= maxLineChars {
+			break
+		}
+		count++
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r == '\t':
+			sb.WriteString("    ")
+		case r < 0x20 || r > 0x7e:
+			sb.WriteByte('?')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// Export 输出PDF下载
+func Export(ctx context.Context, doc *Document, fileName string) (err error) {
+	r := ghttp.RequestFromCtx(ctx)
+	if r == nil {
+		err = gerror.New("ctx not http request")
+		return
+	}
+
+	writer := r.Response.Writer
+	writer.Header().Set("Content-Type", "application/pdf")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.pdf", url.QueryEscape(fileName)))
+	writer.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
+
+	if _, err = writer.Write(doc.Bytes()); err != nil {
+		return
+	}
+
+	// 加入到上下文
+	contexts.SetResponse(ctx, &model.Response{
+		Code:      gcode.CodeOK.Code(),
+		Message:   "export successfully!",
+		Timestamp: time.Now().Unix(),
+		TraceID:   gctx.CtxId(ctx),
+	})
+	return
+}
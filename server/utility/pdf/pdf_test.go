@@ -0,0 +1,52 @@
+// Package pdf
+// @Description 简易文本PDF 测试
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// 超过一页的文本自动分页，xref 偏移指向各对象
+func TestDocumentPagination(t *testing.T) {
+	doc := New()
+	for i := 0; i < linesPerPage+5; i++ {
+		doc.Line("line %d (escaped) \\ 中文", i)
+	}
+	data := doc.Bytes()
+
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatalf("invalid pdf envelope")
+	}
+	if !bytes.Contains(data, []byte("/Count 2")) {
+		t.Fatalf("expected 2 pages")
+	}
+	if !bytes.Contains(data, []byte(`(line 0 \(escaped\) \\ ??) Tj`)) {
+		t.Fatalf("text not escaped")
+	}
+
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if match == nil {
+		t.Fatalf("startxref not found")
+	}
+	xref, _ := strconv.Atoi(string(match[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n0 8\n")) {
+		t.Fatalf("startxref does not point to xref table")
+	}
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xref:], -1)
+	for i, offset := range offsets {
+		pos, _ := strconv.Atoi(string(offset[1]))
+		if !bytes.HasPrefix(data[pos:], []byte(fmt.Sprintf("%d 0 obj", i+1))) {
+			t.Fatalf("xref offset of object %d is wrong", i+1)
+		}
+	}
+}
+
+func TestEmptyDocument(t *testing.T) {
+	if data := New().Bytes(); !bytes.Contains(data, []byte("/Count 1")) {
+		t.Fatalf("empty document should have one blank page")
+	}
+}